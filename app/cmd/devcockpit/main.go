@@ -1,14 +1,33 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/csv"
 	"fmt"
-	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/caioricciuti/dev-cockpit/internal/app"
+	"github.com/caioricciuti/dev-cockpit/internal/backup"
+	"github.com/caioricciuti/dev-cockpit/internal/bloat"
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
+	"github.com/caioricciuti/dev-cockpit/internal/cliio"
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/debugstats"
+	"github.com/caioricciuti/dev-cockpit/internal/exporter"
 	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/maintain"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/quickactions"
+	"github.com/caioricciuti/dev-cockpit/internal/query"
+	"github.com/caioricciuti/dev-cockpit/internal/recorder"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/startup"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
 	"github.com/caioricciuti/dev-cockpit/internal/uninstaller"
 	"github.com/caioricciuti/dev-cockpit/internal/updater"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,54 +37,160 @@ import (
 var version = "dev"
 
 func main() {
-	// Debug logging off by default; enable with --debug
+	// Global flags may appear anywhere; the rest are the command and its
+	// arguments. Debug logging is off by default; enable with --debug.
 	debugMode := false
-	for _, arg := range os.Args {
-		switch arg {
-		case "--debug":
+	recordPath := ""
+	profileStartup := false
+	pprofAddr := ""
+	cpuProfile := ""
+	tour := false
+	safeMode := false
+	var output cliio.Options
+	var args []string
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		switch {
+		case arg == "--debug":
 			debugMode = true
-		case "--no-debug":
+		case arg == "--no-debug":
 			debugMode = false
+		case arg == "--no-color":
+			output.NoColor = true
+		case arg == "--plain":
+			output.Plain = true
+		case arg == "--record":
+			if i+1 >= len(os.Args) {
+				exit("Record", clierr.New(clierr.Usage, "Usage: devcockpit --record <file.cast>"))
+			}
+			i++
+			recordPath = os.Args[i]
+		case strings.HasPrefix(arg, "--record="):
+			recordPath = strings.TrimPrefix(arg, "--record=")
+		case arg == "--pprof":
+			pprofAddr = debugstats.DefaultAddr
+		case strings.HasPrefix(arg, "--pprof="):
+			pprofAddr = strings.TrimPrefix(arg, "--pprof=")
+		case arg == "--safe":
+			safeMode = true
+		case arg == "--profile-startup":
+			profileStartup = true
+		case strings.HasPrefix(arg, "--profile-startup="):
+			profileStartup = true
+			cpuProfile = strings.TrimPrefix(arg, "--profile-startup=")
+		default:
+			args = append(args, arg)
 		}
 	}
+	cliio.Configure(output)
 
 	// Check for command line arguments FIRST (before logger initialization)
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
+	if len(args) > 0 {
+		switch args[0] {
 		case "version", "--version", "-v":
-			fmt.Printf("Dev Cockpit v%s\n", version)
+			cliio.Printf("Dev Cockpit v%s\n", version)
 			os.Exit(0)
 		case "cleanup":
 			// Minimal CLI for testing cleanup operations
-			if len(os.Args) > 2 {
-				sub := os.Args[2]
+			if len(args) > 1 {
+				sub := args[1]
 				switch sub {
 				case "empty-trash", "--empty-trash":
 					// Use quickactions implementation
 					if err := quickactions.EmptyTrash(); err != nil {
-						fmt.Printf("Empty Trash failed: %v\n", err)
-						os.Exit(1)
+						exit("Empty Trash", err)
 					}
-					fmt.Println("Trash emptied successfully.")
+					cliio.Success("Trash emptied successfully.")
 					os.Exit(0)
 				}
 			}
-			fmt.Println("Usage: devcockpit cleanup empty-trash")
-			os.Exit(1)
+			exit("Cleanup", clierr.New(clierr.Usage, "Usage: devcockpit cleanup empty-trash"))
+		case "backup":
+			path := backup.DefaultName(time.Now())
+			if len(args) > 1 {
+				path = args[1]
+			}
+			summary, err := backup.Create(new(config.Config).Dir(), path)
+			if err != nil {
+				exit("Backup", err)
+			}
+			cliio.Success(fmt.Sprintf("Backed up %d files to %s", summary.Files, summary.Path))
+			os.Exit(0)
+		case "restore":
+			path, force := "", false
+			for _, arg := range args[1:] {
+				if arg == "--force" || arg == "-f" {
+					force = true
+				} else {
+					path = arg
+				}
+			}
+			if path == "" {
+				exit("Restore", clierr.New(clierr.Usage, "Usage: devcockpit restore <backup.tar.gz> [--force]"))
+			}
+			dir := new(config.Config).Dir()
+			if !force && !confirm(fmt.Sprintf("Replace %s with %s? The current one is kept as %s", dir, path, backup.PreviousDir(dir))) {
+				exit("Restore", clierr.New(clierr.Cancelled, "Restore cancelled"))
+			}
+			summary, err := backup.Restore(path, dir)
+			if err != nil {
+				exit("Restore", err)
+			}
+			cliio.Success(fmt.Sprintf("Restored %d files from %s", summary.Files, summary.Path))
+			if summary.Previous != "" {
+				cliio.Info("Your previous settings are in " + summary.Previous)
+			}
+			os.Exit(0)
+		case "serve":
+			addr := ""
+			for i := 1; i < len(args); i++ {
+				switch arg := args[i]; {
+				case arg == "--metrics" && i+1 < len(args):
+					addr = args[i+1]
+					i++
+				case arg == "--metrics":
+					addr = exporter.DefaultAddr
+				case strings.HasPrefix(arg, "--metrics="):
+					addr = strings.TrimPrefix(arg, "--metrics=")
+				}
+			}
+			if addr == "" {
+				exit("Serve", clierr.New(clierr.Usage, "Usage: devcockpit serve --metrics [addr]"))
+			}
+			listening, err := exporter.Serve(addr, runner.Default)
+			if err != nil {
+				exit("Serve", err)
+			}
+			cliio.Success(fmt.Sprintf("Serving Prometheus metrics on http://%s/metrics", listening))
+			cliio.Info("Press Ctrl+C to stop")
+			stop := make(chan os.Signal, 1)
+			signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+			<-stop
+			os.Exit(0)
+		case "maintain":
+			maintainCommand(args[1:])
+		case "query":
+			queryCommand(args[1:])
+		case "dev":
+			// Maintainer tools, left out of the help
+			devCommand(args[1:])
+		case "tour":
+			// Launches the TUI below, opening on the tour
+			tour = true
 		case "help", "--help", "-h":
 			showHelp()
 			os.Exit(0)
 		case "logs", "--logs":
 			// Initialize logger just to get the path
 			if err := logger.Initialize(false); err != nil {
-				log.Fatal("Failed to initialize logger:", err)
+				exit("Logger", err)
 			}
-			fmt.Printf("Log file location: %s\n", logger.GetLogPath())
+			cliio.Fields([][2]string{{"Log file location", logger.GetLogPath()}})
 			os.Exit(0)
 		case "uninstall", "--uninstall":
 			// Check for --force flag
 			force := false
-			for _, arg := range os.Args[2:] {
+			for _, arg := range args[1:] {
 				if arg == "--force" || arg == "-f" {
 					force = true
 					break
@@ -74,15 +199,14 @@ func main() {
 
 			// Perform uninstallation
 			if err := uninstaller.Uninstall(force); err != nil {
-				fmt.Printf("Uninstall failed: %v\n", err)
-				os.Exit(1)
+				exit("Uninstall", err)
 			}
 			os.Exit(0)
 		case "update", "--update":
 			// Parse flags
 			force := false
 			checkOnly := false
-			for _, arg := range os.Args[2:] {
+			for _, arg := range args[1:] {
 				switch arg {
 				case "--force", "-f":
 					force = true
@@ -99,8 +223,7 @@ func main() {
 			}
 
 			if err := updater.Update(opts); err != nil {
-				fmt.Printf("Update failed: %v\n", err)
-				os.Exit(1)
+				exit("Update", err)
 			}
 			os.Exit(0)
 		}
@@ -108,13 +231,15 @@ func main() {
 
 	// Initialize logger (only when launching TUI)
 	if err := logger.Initialize(debugMode); err != nil {
-		log.Fatal("Failed to initialize logger:", err)
+		exit("Logger", err)
 	}
 	defer logger.GetLogger().Close()
 
 	// Show debug info only when launching TUI
-	fmt.Printf("Debug logging: %v\n", debugMode)
-	fmt.Printf("Log file: %s\n", logger.GetLogPath())
+	cliio.Fields([][2]string{
+		{"Debug logging", fmt.Sprint(debugMode)},
+		{"Log file", logger.GetLogPath()},
+	})
 	if debugMode {
 		fmt.Println("Tail logs in another terminal with:")
 		fmt.Printf("  tail -f %s\n\n", logger.GetLogPath())
@@ -125,28 +250,298 @@ func main() {
 
 	logger.Info("Starting Dev Cockpit v%s", version)
 
+	// Time the launch; the breakdown is logged once the first frame and the
+	// first metrics are in (or at exit)
+	if profileStartup {
+		if err := startup.Begin(cpuProfile, startup.FirstRender, startup.FirstMetrics); err != nil {
+			exit("Startup profile", err)
+		}
+		defer startup.Finish()
+		fmt.Printf("Profiling startup; the breakdown is written to %s\n", logger.GetLogPath())
+	}
+
 	// Initialize configuration
+	configStart := time.Now()
 	cfg, err := config.Load()
 	if err != nil {
 		logger.Error("Failed to load configuration: %v", err)
-		log.Fatal("Failed to load configuration:", err)
+		exit("Configuration", clierr.Wrap(clierr.Config, err))
 	}
 	logger.Info("Configuration loaded successfully")
+	startup.Measure("load config", configStart)
+
+	// Enforce retention limits on stored data before anything new is written
+	storage.ApplyRetention(cfg)
+
+	// Pick colors before the program takes over the terminal
+	if _, set := os.LookupEnv("NO_COLOR"); set || output.NoColor || output.Plain {
+		components.DisableColor()
+	}
+	components.SetBackground(cfg.Theme)
+	if err := components.SetColorScheme(cfg.UI.ColorScheme); err != nil {
+		logger.Warn("%v", err)
+	}
 
 	// Create the main application
-	application := app.New(cfg, version)
+	var cockpit *app.Model
+	if safeMode {
+		fmt.Println("Safe mode: Quick Actions, Settings and Support only, with no background refreshes.")
+		logger.Info("Starting in safe mode")
+		cockpit = app.NewSafe(cfg, version)
+	} else {
+		cockpit = app.New(cfg, version)
+	}
+	var application tea.Model = cockpit
+	if tour {
+		cockpit.StartTour()
+	}
+
+	// Runtime stats overlay, with pprof on localhost when asked for
+	if debugMode || pprofAddr != "" {
+		if pprofAddr != "" {
+			addr, err := debugstats.Serve(pprofAddr)
+			if err != nil {
+				exit("pprof", err)
+			}
+			pprofAddr = addr
+			fmt.Printf("Serving pprof on http://%s/debug/pprof/\n", pprofAddr)
+			logger.Info("Serving pprof on %s", pprofAddr)
+		}
+		cockpit.EnableDebug(pprofAddr)
+	}
+
+	// Optionally record the session as an asciinema cast
+	var rec *recorder.Recorder
+	if recordPath != "" {
+		rec, err = recorder.Create(recordPath, fmt.Sprintf("Dev Cockpit v%s", version))
+		if err != nil {
+			exit("Recording", err)
+		}
+		application = rec.Wrap(application)
+		fmt.Printf("Recording session to %s\n", recordPath)
+		logger.Info("Recording session to %s", recordPath)
+	}
 
 	// Initialize Bubble Tea program
-	p := tea.NewProgram(
-		application,
-		tea.WithAltScreen(),       // Use alternate screen buffer
-		tea.WithMouseCellMotion(), // Enable mouse support
-	)
+	options := []tea.ProgramOption{tea.WithAltScreen()} // Use alternate screen buffer
+	if cfg.UI.MouseEnabled {
+		// Clicks and the wheel; turning it off restores the terminal's own selection
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(application, options...)
 
 	// Run the program
-	if _, err := p.Run(); err != nil {
-		log.Fatal("Error running program:", err)
+	_, runErr := p.Run()
+	if rec != nil {
+		if err := rec.Close(); err != nil {
+			logger.Error("Recording incomplete: %v", err)
+			fmt.Printf("Recording incomplete: %v\n", err)
+		} else {
+			fmt.Printf("Session recorded to %s (replay with: asciinema play %s)\n", recordPath, recordPath)
+		}
+	}
+	if runErr != nil {
+		exit("Dev Cockpit", runErr)
+	}
+}
+
+// exit reports err and exits with its documented code. Usage errors are
+// printed as-is and a cancelled command is reported as info.
+func exit(action string, err error) {
+	code := clierr.CodeOf(err)
+	switch code {
+	case clierr.Usage:
+		cliio.Println(err.Error())
+	case clierr.Cancelled:
+		cliio.Info(err.Error())
+	default:
+		cliio.Error(fmt.Sprintf("%s failed: %v", action, err))
+	}
+	os.Exit(int(code))
+}
+
+// maintainCommand runs, installs or removes the background maintenance
+// agent, or shows its state
+func maintainCommand(args []string) {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	if sub != "run" && sub != "install" && sub != "uninstall" && sub != "status" {
+		exit("Maintain", clierr.New(clierr.Usage, "Usage: devcockpit maintain run|install|uninstall|status"))
+	}
+	if err := logger.Initialize(false); err != nil {
+		exit("Logger", err)
+	}
+	defer logger.GetLogger().Close()
+	cfg, err := config.Load()
+	if err != nil {
+		exit("Configuration", clierr.Wrap(clierr.Config, err))
 	}
+
+	switch sub {
+	case "run":
+		report := maintain.Run(cfg, runner.Default, time.Now())
+		for _, step := range report.Steps {
+			switch {
+			case step.Error != "":
+				cliio.Error(fmt.Sprintf("%s: %s", step.Name, step.Error))
+			case step.Skipped:
+				cliio.Info(fmt.Sprintf("%s: skipped, %s", step.Name, step.Result))
+			default:
+				cliio.Success(fmt.Sprintf("%s: %s", step.Name, step.Result))
+			}
+		}
+		if report.Failed() {
+			os.Exit(int(clierr.Failure))
+		}
+	case "install":
+		path, err := maintain.Install(cfg, runner.Default)
+		if err != nil {
+			exit("Maintain install", err)
+		}
+		cliio.Success(fmt.Sprintf("Maintenance runs every %s from %s", maintain.Interval(cfg), path))
+		cliio.Info("Results are summarized the next time Dev Cockpit opens")
+	case "uninstall":
+		if err := maintain.Uninstall(runner.Default); err != nil {
+			exit("Maintain uninstall", err)
+		}
+		cliio.Success("Background maintenance removed")
+	case "status":
+		fields := [][2]string{{"Installed", fmt.Sprint(maintain.Installed())}}
+		if maintain.Installed() {
+			fields = append(fields, [2]string{"Every", maintain.Interval(cfg).String()}, [2]string{"Agent", maintain.PlistPath()})
+		}
+		report, found, err := maintain.LastRun(cfg)
+		if err != nil {
+			exit("Maintain status", err)
+		}
+		if found {
+			fields = append(fields, [2]string{"Last run", report.Summary()})
+		}
+		cliio.Fields(fields)
+	}
+	os.Exit(0)
+}
+
+// queryCommand runs read-only SQL over the collected data and prints the
+// result as CSV, or lists the tables there are to query
+func queryCommand(args []string) {
+	if err := logger.Initialize(false); err != nil {
+		exit("Logger", err)
+	}
+	defer logger.GetLogger().Close()
+	cfg, err := config.Load()
+	if err != nil {
+		exit("Configuration", clierr.Wrap(clierr.Config, err))
+	}
+	db, err := query.Load(context.Background(), cfg, runner.Default)
+	if err != nil {
+		exit("Query", err)
+	}
+
+	if len(args) == 0 {
+		db.Close()
+		for _, table := range db.Tables {
+			cliio.Println(fmt.Sprintf("%-14s %6d rows  %s", table.Name, table.Rows, strings.Join(table.Columns, ", ")))
+		}
+		cliio.Info(`Run one with: devcockpit query "SELECT * FROM metrics LIMIT 10"`)
+		os.Exit(0)
+	}
+	result, err := db.Query(context.Background(), strings.Join(args, " "))
+	db.Close()
+	if err != nil {
+		exit("Query", err)
+	}
+	out := csv.NewWriter(os.Stdout)
+	if len(result.Columns) > 0 {
+		out.Write(result.Columns)
+	}
+	out.WriteAll(result.Rows)
+	if result.More > 0 {
+		// On stderr, to keep the CSV on stdout whole
+		cliio.New(os.Stderr, cliio.Options{}).Warning(fmt.Sprintf("%d more rows left out; add a LIMIT or an aggregate", result.More))
+	}
+	os.Exit(0)
+}
+
+// devCommand runs the maintainer tools: bloat breaks the binary's size
+// down by section and module, and lists the embedded assets
+func devCommand(args []string) {
+	if len(args) == 0 || args[0] != "bloat" {
+		exit("Dev", clierr.New(clierr.Usage, "Usage: devcockpit dev bloat [binary]"))
+	}
+	path := ""
+	if len(args) > 1 {
+		path = args[1]
+	} else if exe, err := os.Executable(); err == nil {
+		path = exe
+	}
+	report, err := bloat.Analyze(path)
+	if err != nil {
+		exit("Bloat", err)
+	}
+
+	percent := func(n, of int64) string { return fmt.Sprintf("%5.1f%%", float64(n)/float64(max(of, 1))*100) }
+	row := func(size int64, of int64, name string) {
+		cliio.Println(cliio.Stdout.Truncate(fmt.Sprintf("  %9s %s  %s", bloat.FormatSize(size), percent(size, of), name), 0))
+	}
+	cliio.Banner(cliio.Blue, "Binary size")
+	cliio.Fields([][2]string{
+		{"Binary", report.Path},
+		{"Size", bloat.FormatSize(report.Size)},
+		{"Go", report.GoVersion},
+		{"Code", bloat.FormatSize(report.Code) + " in functions, by module below"},
+	})
+	cliio.Println("\nSections (of the file)")
+	for _, s := range report.Sections[:min(len(report.Sections), 8)] {
+		row(s.Size, report.Size, s.Name)
+	}
+	cliio.Println("\nModules (of the code)")
+	for _, m := range report.Modules {
+		row(m.Code, report.Code, strings.TrimSpace(m.Path+" "+m.Version))
+	}
+
+	cliio.Println("\nEmbedded assets")
+	wd, _ := os.Getwd()
+	root := bloat.FindModuleRoot(wd)
+	if root == "" {
+		cliio.Info("Run from the source tree to list the files go:embed includes")
+		os.Exit(0)
+	}
+	embeds, err := bloat.Embeds(root)
+	if err != nil {
+		exit("Bloat", err)
+	}
+	if len(embeds) == 0 {
+		cliio.Println("  none: no go:embed directives in " + root)
+	}
+	for _, e := range embeds {
+		row(e.Size, report.Size, e.Path)
+	}
+	os.Exit(0)
+}
+
+// confirm asks question on the terminal and reports whether the answer
+// was yes
+func confirm(question string) bool {
+	cliio.Warning(question)
+	cliio.Printf("Continue? (y/N): ")
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// exitCodes lists the documented exit codes for the help text
+func exitCodes() string {
+	var b strings.Builder
+	for _, code := range clierr.Codes {
+		fmt.Fprintf(&b, "  %d  %s\n", code, code)
+	}
+	return b.String()
 }
 
 func showHelp() {
@@ -154,7 +549,13 @@ func showHelp() {
 
 USAGE:
   devcockpit [flags]
+  devcockpit tour
   devcockpit cleanup empty-trash
+  devcockpit backup [file]
+  devcockpit restore <file> [--force]
+  devcockpit serve --metrics [addr]
+  devcockpit maintain run|install|uninstall|status
+  devcockpit query ["<sql>"]
   devcockpit uninstall [--force]
   devcockpit update [--check | --force]
 
@@ -167,11 +568,30 @@ AVAILABLE TUI MODULES:
   Docker          Container management and cleanup
   Network         Interface analysis and connectivity diagnostics
   Security        Firewall, FileVault, and SIP status
+  Settings        Storage footprint, retention, backups, and SQL over the data
   Support         Project support and sponsorship information
 
 CLI COMMANDS:
   devcockpit                       Launch interactive TUI
+  devcockpit tour                  Launch with a guided tour of every module
+                                   (also opens on first launch, or press T)
   devcockpit cleanup empty-trash   Empty the trash (CLI mode)
+  devcockpit backup [file]         Archive ~/.devcockpit (config, themes, state, data)
+                                   to file, or devcockpit-backup-<time>.tar.gz
+  devcockpit restore <file>        Replace ~/.devcockpit with a backup; the current
+                                   one is kept as ~/.devcockpit.before-restore
+  devcockpit serve --metrics [addr]
+                                   Serve the dashboard metrics for Prometheus
+                                   at /metrics (default %s)
+  devcockpit maintain run          Run brew update, brew cleanup and metrics
+                                   compaction now, headless
+  devcockpit maintain install      Run them in the background every
+                                   maintenance.interval hours, via launchd
+  devcockpit maintain uninstall    Stop the background maintenance
+  devcockpit maintain status       Show whether it's installed and its last run
+  devcockpit query                 List the tables of collected data
+  devcockpit query "<sql>"         Run read-only SQL over the metrics and the
+                                   audit logs, printing CSV (needs sqlite3)
   devcockpit update                Update to the latest version
   devcockpit update --check        Check for updates without installing
   devcockpit update --force        Update without confirmation prompts
@@ -180,27 +600,49 @@ CLI COMMANDS:
   devcockpit --help, -h            Show this help message
   devcockpit --version, -v         Show version information
   devcockpit --debug               Launch with debug logging
+  devcockpit --record <file>       Record the session to an asciinema cast file
+  devcockpit --safe                Launch with only the core modules and nothing
+                                   refreshing in the background, to tell whether
+                                   a module is behind a crash or a slowdown
+  devcockpit --profile-startup     Log how long each startup phase takes
+  devcockpit --profile-startup=<file>
+                                   Also write a pprof CPU profile of the launch
+
+GLOBAL FLAGS:
+  --no-color    Disable colored output, TUI included (also honors NO_COLOR)
+  --plain       Plain output for scripts: no colors, banners or symbols
+  --debug       Enable debug logging and the runtime stats overlay (Ctrl+D)
+  --pprof[=addr]
+                Serve pprof on localhost (default %s)
   devcockpit --logs                Show debug log file location
 
 EXAMPLES:
   devcockpit                      # Start the interactive interface
   devcockpit --debug              # Launch with live debug output
+  devcockpit --safe --debug       # Rule out a misbehaving module
+  devcockpit --record bug.cast    # Record a session for a bug report
   devcockpit cleanup empty-trash  # Empty trash from command line
   devcockpit update               # Update to the latest version
+  devcockpit backup ~/dc.tar.gz  # Take your setup to a new Mac
+  devcockpit serve --metrics :9100  # Let Prometheus scrape this Mac
+  devcockpit query "SELECT action, count(*) FROM quick_actions GROUP BY action"
   devcockpit uninstall            # Uninstall Dev Cockpit
 
 CONFIGURATION:
   Config: ~/.devcockpit/config.yaml
   Logs:   ~/.devcockpit/debug.log
+  Data:   ~/.devcockpit/data (retention via storage.retention)
 
 KEYBOARD SHORTCUTS (in TUI):
-  1-9         Jump to module
+  1-9         Jump to module (Alt+1-9 from inside a module)
   Tab         Cycle through modules
   ↑/↓         Navigate lists
   Enter       Select/Execute
   ESC         Go back / Close modal
   q, Ctrl+C   Quit
 
+EXIT CODES:
+%s
 DOCUMENTATION:
   Website: https://devcockpit.app
   GitHub:  https://github.com/caioricciuti/dev-cockpit
@@ -211,5 +653,5 @@ SUPPORT:
   Donate:  https://buymeacoffee.com/caioricciuti
 
 Pro Tip: Run 'devcockpit' to explore all features interactively!
-`, version)
+`, version, exporter.DefaultAddr, debugstats.DefaultAddr, exitCodes())
 }
@@ -0,0 +1,230 @@
+// Package alerts checks the threshold rules from config against sampled
+// metrics and sends the ones that fire to their channels: webhooks (Slack,
+// Discord or plain JSON) and shell commands.
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// Metrics are the names a rule can watch, all in percent
+var Metrics = []string{"cpu", "memory", "disk", "gpu"}
+
+// Alert is a rule that just fired
+type Alert struct {
+	Rule  config.AlertRule
+	Value float64
+	Host  string
+	At    time.Time
+}
+
+// Text describes the alert in one line, e.g. "disk-full: disk at 96%
+// (above 95%) on mac-mini"
+func (a Alert) Text() string {
+	text := fmt.Sprintf("%s: %s at %.0f%% (above %.0f%%)", a.Rule.Name, a.Rule.Metric, a.Value, a.Rule.Above)
+	if a.Host != "" {
+		text += " on " + a.Host
+	}
+	return text
+}
+
+// Short is the metric and its value, e.g. "Disk 96%", for a window title
+func (a Alert) Short() string {
+	name := strings.ToUpper(a.Rule.Metric)
+	if len(a.Rule.Metric) > 3 {
+		name = strings.ToUpper(a.Rule.Metric[:1]) + a.Rule.Metric[1:]
+	}
+	return fmt.Sprintf("%s %.0f%%", name, a.Value)
+}
+
+// Engine remembers which rules are firing, so each fires once per
+// crossing. It is safe for concurrent use.
+type Engine struct {
+	mu     sync.Mutex
+	rules  []config.AlertRule
+	firing map[int]Alert // by rule, with the latest value
+}
+
+// New returns an engine for rules. Rules watching an unknown metric are
+// returned as an error and left out.
+func New(rules []config.AlertRule) (*Engine, error) {
+	e := &Engine{firing: map[int]Alert{}}
+	var errs []error
+	for _, rule := range rules {
+		if !known(rule.Metric) {
+			errs = append(errs, fmt.Errorf("alert %q: unknown metric %q (want %s)", rule.Name, rule.Metric, strings.Join(Metrics, ", ")))
+			continue
+		}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("%s above %.0f%%", rule.Metric, rule.Above)
+		}
+		e.rules = append(e.rules, rule)
+	}
+	return e, errors.Join(errs...)
+}
+
+func known(metric string) bool {
+	for _, name := range Metrics {
+		if metric == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty reports whether there are no rules to check
+func (e *Engine) Empty() bool { return len(e.rules) == 0 }
+
+// Check returns the rules whose metric crossed their threshold since the
+// last check. Metrics missing from sample (e.g. no GPU) are skipped.
+func (e *Engine) Check(sample map[string]float64, host string, now time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var fired []Alert
+	for i, rule := range e.rules {
+		value, ok := sample[rule.Metric]
+		if !ok {
+			continue
+		}
+		if value <= rule.Above {
+			delete(e.firing, i)
+			continue
+		}
+		alert := Alert{Rule: rule, Value: value, Host: host, At: now}
+		if _, ok := e.firing[i]; !ok {
+			fired = append(fired, alert)
+		}
+		e.firing[i] = alert
+	}
+	return fired
+}
+
+// Firing returns the rules above their threshold as of the last check,
+// fired then or before, in the order of the rules
+func (e *Engine) Firing() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var firing []Alert
+	for i := range e.rules {
+		if alert, ok := e.firing[i]; ok {
+			firing = append(firing, alert)
+		}
+	}
+	return firing
+}
+
+// client posts webhooks, giving up on slow endpoints
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Send delivers a to every channel of its rule and returns the failures
+func Send(r runner.Runner, a Alert) error {
+	var errs []error
+	for _, ch := range a.Rule.Channels {
+		var err error
+		switch {
+		case ch.Webhook != "":
+			err = postWebhook(ch, a)
+		case ch.Command != "":
+			err = runCommand(r, ch.Command, a)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// payload is the webhook body in ch's format
+func payload(ch config.AlertChannel, a Alert) ([]byte, error) {
+	format := ch.Format
+	if format == "" {
+		switch {
+		case strings.Contains(ch.Webhook, "hooks.slack.com"):
+			format = "slack"
+		case strings.Contains(ch.Webhook, "discord.com/api/webhooks"):
+			format = "discord"
+		default:
+			format = "json"
+		}
+	}
+
+	switch format {
+	case "slack":
+		return json.Marshal(map[string]string{"text": "⚠️ " + a.Text()})
+	case "discord":
+		return json.Marshal(map[string]string{"content": "⚠️ " + a.Text()})
+	case "json":
+		return json.Marshal(map[string]interface{}{
+			"rule":      a.Rule.Name,
+			"metric":    a.Rule.Metric,
+			"value":     a.Value,
+			"threshold": a.Rule.Above,
+			"host":      a.Host,
+			"time":      a.At.Format(time.RFC3339),
+			"message":   a.Text(),
+		})
+	}
+	return nil, fmt.Errorf("unknown webhook format %q (want slack, discord or json)", format)
+}
+
+func postWebhook(ch config.AlertChannel, a Alert) error {
+	body, err := payload(ch, a)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(ch.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s returned %s", host(ch.Webhook), resp.Status)
+	}
+	return nil
+}
+
+// host keeps webhook tokens, which live in the path, out of messages
+func host(url string) string {
+	rest := url
+	if i := strings.Index(rest, "://"); i >= 0 {
+		rest = rest[i+3:]
+	}
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// runCommand runs command with sh, passing the alert in DEVCOCKPIT_ALERT_*
+// variables
+func runCommand(r runner.Runner, command string, a Alert) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"DEVCOCKPIT_ALERT_RULE="+a.Rule.Name,
+		"DEVCOCKPIT_ALERT_METRIC="+a.Rule.Metric,
+		fmt.Sprintf("DEVCOCKPIT_ALERT_VALUE=%.1f", a.Value),
+		fmt.Sprintf("DEVCOCKPIT_ALERT_THRESHOLD=%g", a.Rule.Above),
+		"DEVCOCKPIT_ALERT_HOST="+a.Host,
+		"DEVCOCKPIT_ALERT_MESSAGE="+a.Text(),
+	)
+	if out, err := r.CombinedOutput(cmd); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %s", command, msg)
+		}
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
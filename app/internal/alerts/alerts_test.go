@@ -0,0 +1,107 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+var diskFull = config.AlertRule{Name: "disk-full", Metric: "disk", Above: 95}
+
+func TestCheckFiresOncePerCrossing(t *testing.T) {
+	e, err := New([]config.AlertRule{diskFull, {Name: "hot", Metric: "temperature", Above: 90}})
+	if err == nil || !strings.Contains(err.Error(), `unknown metric "temperature"`) {
+		t.Errorf("err = %v", err)
+	}
+
+	now := time.Now()
+	var fired []int
+	for _, disk := range []float64{90, 96, 97, 94, 96} {
+		fired = append(fired, len(e.Check(map[string]float64{"disk": disk}, "mini", now)))
+	}
+	if got := []int{0, 1, 0, 0, 1}; !equal(fired, got) {
+		t.Errorf("fired %v, want %v", fired, got)
+	}
+	if alerts := e.Check(map[string]float64{"cpu": 99}, "mini", now); len(alerts) != 0 {
+		t.Errorf("a missing metric fired %v", alerts)
+	}
+	// Still firing from the last disk reading, at its latest value
+	if firing := e.Firing(); len(firing) != 1 || firing[0].Short() != "Disk 96%" {
+		t.Errorf("firing = %v", firing)
+	}
+	e.Check(map[string]float64{"disk": 80}, "mini", now)
+	if firing := e.Firing(); len(firing) != 0 {
+		t.Errorf("firing after dropping back = %v", firing)
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSendWebhooks(t *testing.T) {
+	var bodies []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		_ = json.Unmarshal(data, &body)
+		bodies = append(bodies, body)
+		if r.URL.Path == "/broken" {
+			http.Error(w, "nope", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	rule := diskFull
+	rule.Channels = []config.AlertChannel{
+		{Webhook: srv.URL + "/slack", Format: "slack"},
+		{Webhook: srv.URL + "/discord", Format: "discord"},
+		{Webhook: srv.URL + "/plain"},
+		{Webhook: srv.URL + "/broken"},
+	}
+	a := Alert{Rule: rule, Value: 96.4, Host: "mini", At: time.Date(2024, 7, 1, 3, 0, 0, 0, time.UTC)}
+	err := Send(runner.NewFake(), a)
+
+	text := "disk-full: disk at 96% (above 95%) on mini"
+	if len(bodies) != 4 || bodies[0]["text"] != "⚠️ "+text || bodies[1]["content"] != "⚠️ "+text {
+		t.Fatalf("bodies = %v", bodies)
+	}
+	if bodies[2]["rule"] != "disk-full" || bodies[2]["value"] != 96.4 || bodies[2]["time"] != "2024-07-01T03:00:00Z" {
+		t.Errorf("json body = %v", bodies[2])
+	}
+	if err == nil || !strings.Contains(err.Error(), "returned 404 Not Found") || strings.Contains(err.Error(), "/broken") {
+		t.Errorf("err = %v, want the failed webhook reported without its path", err)
+	}
+}
+
+func TestSendCommand(t *testing.T) {
+	rule := diskFull
+	rule.Channels = []config.AlertChannel{{Command: "say disk full"}, {Command: "false"}}
+	fake := runner.NewFake().
+		Set("sh -c say disk full", "", nil).
+		Set("sh -c false", "", errors.New("exit status 1"))
+
+	err := Send(fake, Alert{Rule: rule, Value: 96})
+	if calls := fake.Calls(); len(calls) != 2 {
+		t.Errorf("calls = %q", calls)
+	}
+	if err == nil || err.Error() != "false: exit status 1" {
+		t.Errorf("err = %v", err)
+	}
+}
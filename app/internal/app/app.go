@@ -1,24 +1,40 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/debugstats"
+	"github.com/caioricciuti/dev-cockpit/internal/export"
 	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/maintain"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/cleanup"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/dashboard"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/docker"
+	"github.com/caioricciuti/dev-cockpit/internal/modules/fleet"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/network"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/packages"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/quickactions"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/security"
+	"github.com/caioricciuti/dev-cockpit/internal/modules/settings"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/support"
 	"github.com/caioricciuti/dev-cockpit/internal/modules/system"
+	"github.com/caioricciuti/dev-cockpit/internal/notify"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/startup"
+	"github.com/caioricciuti/dev-cockpit/internal/state"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/clipboard"
 	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
 	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -32,6 +48,20 @@ type Module interface {
 	HasOpenModal() bool
 }
 
+// Shutdowner is implemented by modules that hold resources outside the
+// process, such as firewall rules, which must be released before exit
+type Shutdowner interface {
+	Shutdown()
+}
+
+// Keeper is implemented by modules whose results are worth keeping while
+// another tab is shown, such as scan sizes or package lists. Their Init
+// runs when they're first shown and again only once StaleAfter has passed;
+// until then their own refresh key reloads them.
+type Keeper interface {
+	StaleAfter() time.Duration
+}
+
 // Model represents the main application state
 type Model struct {
 	config        *config.Config
@@ -42,57 +72,562 @@ type Model struct {
 	height        int
 	showHelp      bool
 	showLogs      bool
+	palette       *palette.Model
+	tour          *tour         // guided tour, on first launch or T
+	dialog        *dialog.Model // confirmation a module asked for
+	clipPicker    bool          // palette is showing clipboard history
+	split         bool          // dashboard pinned beside the active module
+	vimMode       bool          // h/l tabs, gg/G and the : command line
+	pendingG      bool          // first g of gg typed
+	commandOpen   bool          // : command line is taking keys
+	commandLine   string
+	toasts        components.Toasts
 	moduleFocused bool
 	lastUpdate    time.Time
 	quitting      bool
+	quitNote      string // shown instead of the goodbye, e.g. after a restore
 	err           error
 	logLines      []string
 	logLoadErr    error
 	maxLogLines   int
 	logPath       string
+	logView       components.ScrollView
+	statePath     string // state.json; empty disables persistence
+	exportDir     string // where e writes exports; empty disables them
+	schedule      *scheduler.Scheduler
+	initAt        map[string]time.Time // when each Keeper last ran Init
+	tickEvery     time.Duration        // update_interval
+	debug         bool                 // --debug: Ctrl+D opens the runtime stats overlay
+	showDebug     bool
+	pprofAddr     string
+	debugStats    debugstats.Stats
+	debugBaseline debugstats.Stats // taken at launch
+	safe          bool             // --safe: core modules only, no scheduled refreshes
+	windowTitle   bool             // ui.window_title
+	notifyAlerts  bool             // post alerts as terminal notifications
+	title         string           // last title set
+	alerting      []string         // alerts firing, e.g. "Disk 92%"
 }
 
 // New creates a new application model
 func New(cfg *config.Config, version string) *Model {
+	return newModel(cfg, version, false)
+}
+
+func newModel(cfg *config.Config, version string, safe bool) *Model {
 	m := &Model{
 		config:      cfg,
 		version:     version,
 		lastUpdate:  time.Now(),
 		maxLogLines: 200,
 		logPath:     logger.GetLogPath(),
+		vimMode:     cfg != nil && cfg.UI.VimMode,
+		tickEvery:   tickInterval(cfg),
+		safe:        safe,
+	}
+	if cfg != nil {
+		m.windowTitle = cfg.UI.WindowTitle
+		m.notifyAlerts = terminalNotifies(cfg.UI.TerminalNotifications, os.Getenv)
 	}
 
 	// Initialize modules
 	m.initializeModules()
 
+	// Safe mode leaves the saved session, of modules it may not load, alone
+	if cfg != nil && !safe {
+		m.statePath = state.Path(cfg)
+		firstRun := m.firstRun()
+		m.restoreState()
+		if firstRun {
+			m.StartTour()
+		}
+	}
+	if cfg != nil {
+		m.exportDir = export.Dir(cfg)
+		// A background maintenance run since the last launch is shown once
+		if report, ok := maintain.TakeUnseen(cfg); ok {
+			kind := components.ToastSuccess
+			if report.Failed() {
+				kind = components.ToastWarning
+			}
+			m.toast(kind, report.Summary())
+		}
+	}
+
 	return m
 }
 
 func (m *Model) initializeModules() {
+	if m.safe {
+		m.initializeSafeModules()
+		return
+	}
 	m.modules = []Module{
-		dashboard.New(m.config),
-		quickactions.New(m.config),
-		cleanup.New(m.config),
-		packages.New(m.config),
-		system.New(m.config),
-		docker.New(m.config),
-		network.New(m.config),
-		security.New(m.config),
-		support.New(),
+		construct(func() Module { return dashboard.New(m.config) }),
+		construct(func() Module { return quickactions.New(m.config) }),
+		construct(func() Module { return cleanup.New(m.config) }),
+		construct(func() Module { return packages.New(m.config) }),
+		construct(func() Module { return system.New(m.config) }),
+		construct(func() Module { return docker.New(m.config) }),
+		construct(func() Module { return network.New(m.config) }),
+		construct(func() Module { return security.New(m.config) }),
+		construct(func() Module { return settings.New(m.config) }),
+		construct(func() Module { return support.New() }),
+	}
+	// The Fleet tab, after System, only makes sense with other Macs to check
+	if m.config != nil && len(m.config.Fleet.Hosts) > 0 {
+		m.modules = slices.Insert(m.modules, 5, construct(func() Module { return fleet.New(m.config) }))
+	}
+	if m.config != nil {
+		m.modules = arrangeModules(m.modules, m.config.Modules.Enabled, m.config.Modules.Order)
+	}
+
+	// Periodic refreshes run from the shell's tick, so hidden modules
+	// never start one
+	m.schedule = scheduler.New()
+	for _, module := range m.modules {
+		if scheduled, ok := module.(scheduler.Scheduled); ok {
+			m.schedule.Add(module.Title(), scheduled.Schedule()...)
+		}
+	}
+}
+
+// construct builds a module, timing it for --profile-startup
+func construct(build func() Module) Module {
+	start := time.Now()
+	module := build()
+	startup.Measure("new "+module.Title(), start)
+	return module
+}
+
+// arrangeModules keeps the enabled modules (all when enabled is empty) and
+// moves the ones in order to the front, in that order. Names are module
+// keys; unknown names are logged and ignored.
+func arrangeModules(modules []Module, enabled, order []string) []Module {
+	byKey := make(map[string]Module, len(modules))
+	for _, module := range modules {
+		byKey[config.ModuleKey(module.Title())] = module
 	}
+	keys := func(setting string, names []string) []string {
+		var found []string
+		for _, name := range names {
+			key := config.ModuleKey(name)
+			if _, ok := byKey[key]; !ok {
+				logger.Warn("Ignoring unknown module %q in modules.%s", name, setting)
+				continue
+			}
+			found = append(found, key)
+		}
+		return found
+	}
+
+	keep := make(map[string]bool, len(modules))
+	for _, key := range keys("enabled", enabled) {
+		keep[key] = true
+	}
+	if len(keep) == 0 {
+		if len(enabled) > 0 {
+			logger.Warn("modules.enabled lists no known module; showing all modules")
+		}
+		for key := range byKey {
+			keep[key] = true
+		}
+	}
+
+	// Placed modules leave keep, so each appears once
+	arranged := make([]Module, 0, len(keep))
+	for _, key := range keys("order", order) {
+		if keep[key] {
+			arranged = append(arranged, byKey[key])
+			delete(keep, key)
+		}
+	}
+	for _, module := range modules {
+		if key := config.ModuleKey(module.Title()); keep[key] {
+			arranged = append(arranged, module)
+		}
+	}
+	return arranged
 }
 
 // Init initializes the application
 func (m *Model) Init() tea.Cmd {
-	// Initialize the first module
-	if len(m.modules) > 0 {
-		return m.modules[0].Init()
+	// Initialize the module restored from the last session (or the first)
+	// and start the tick that drives toasts and scheduled refreshes
+	if m.activeModule < len(m.modules) {
+		return tea.Batch(m.initModule(m.activeModule), m.doTick())
+	}
+	return m.doTick()
+}
+
+// initModule runs a module's Init, tagging its messages with the module
+// ID, along with any refresh that fell due while it was hidden. A Keeper
+// whose results are still fresh isn't re-initialized.
+func (m *Model) initModule(index int) tea.Cmd {
+	module := m.modules[index]
+	if !m.stale(module, time.Now()) {
+		return m.runScheduled()
+	}
+	return tea.Batch(events.Wrap(module.Title(), module.Init()), m.runScheduled())
+}
+
+// stale reports whether module should run Init on being shown at now,
+// noting the time for a Keeper that should
+func (m *Model) stale(module Module, now time.Time) bool {
+	keeper, ok := module.(Keeper)
+	if !ok {
+		return true
+	}
+	if last, ok := m.initAt[module.Title()]; ok && now.Sub(last) < keeper.StaleAfter() {
+		return false
+	}
+	if m.initAt == nil {
+		m.initAt = map[string]time.Time{}
+	}
+	m.initAt[module.Title()] = now
+	return true
+}
+
+// runScheduled starts the scheduled tasks that are due
+func (m *Model) runScheduled() tea.Cmd {
+	if m.schedule == nil {
+		return nil
+	}
+	return m.schedule.Due(time.Now(), m.moduleVisible)
+}
+
+// moduleVisible reports whether a module is on screen: the active one, or
+// the dashboard pinned in split view
+func (m *Model) moduleVisible(title string) bool {
+	if m.activeModule < len(m.modules) && m.modules[m.activeModule].Title() == title {
+		return true
+	}
+	_, _, split := m.splitPanes()
+	return split && title == pinnedModule
+}
+
+// updateModule forwards msg to a module, tagging resulting messages with
+// the module ID so they find their way back even if the user switches tabs
+func (m *Model) updateModule(index int, msg tea.Msg) tea.Cmd {
+	module := m.modules[index]
+	_, cmd := module.Update(msg)
+	return events.Wrap(module.Title(), cmd)
+}
+
+// restoreState reopens the module the last session ended on and hands
+// each module the state it saved
+func (m *Model) restoreState() {
+	saved, err := state.Load(m.statePath)
+	if err != nil {
+		logger.Warn("Ignoring saved state: %v", err)
+		return
+	}
+	if index := m.moduleIndex(saved.ActiveModule); index >= 0 {
+		m.activeModule = index
+	}
+	for _, module := range m.modules {
+		persister, ok := module.(state.Persister)
+		data, found := saved.Modules[module.Title()]
+		if !ok || !found {
+			continue
+		}
+		if err := persister.RestoreState(data); err != nil {
+			logger.Warn("Ignoring saved state for %s: %v", module.Title(), err)
+		}
+	}
+}
+
+// saveState records the active module and every module's state
+func (m *Model) saveState() {
+	if m.statePath == "" || m.activeModule >= len(m.modules) {
+		return
+	}
+	saved := &state.State{
+		ActiveModule: m.modules[m.activeModule].Title(),
+		Modules:      map[string]json.RawMessage{},
+	}
+	for _, module := range m.modules {
+		persister, ok := module.(state.Persister)
+		if !ok {
+			continue
+		}
+		data, err := persister.SaveState()
+		if err != nil {
+			logger.Warn("Not saving state for %s: %v", module.Title(), err)
+			continue
+		}
+		saved.Modules[module.Title()] = data
+	}
+	if err := state.Save(m.statePath, saved); err != nil {
+		logger.Error("Failed to save state: %v", err)
+	}
+}
+
+// quit saves the session state, shuts modules down and ends the program
+func (m *Model) quit() tea.Cmd {
+	m.quitting = true
+	m.saveState()
+	m.resetTitle()
+	for _, module := range m.modules {
+		if s, ok := module.(Shutdowner); ok {
+			s.Shutdown()
+		}
+	}
+	return tea.Quit
+}
+
+// splitPanes returns the pane widths when split mode is on and the
+// terminal is wide enough for it
+func (m *Model) splitPanes() (left, right int, ok bool) {
+	if !m.split || m.moduleIndex(pinnedModule) < 0 {
+		return 0, 0, false
 	}
-	return nil
+	return components.NewLayout(m.width, m.height).SplitPanes(splitRatio, splitGap)
 }
 
-// Update handles messages and updates the model
+// resizeModules sends every module the size of the area it renders in.
+// Modules lay themselves out against the raw terminal size, so in split
+// mode each pane is reported with the margins the shell adds around it.
+func (m *Model) resizeModules() tea.Cmd {
+	left, right, split := m.splitPanes()
+	pinned := m.moduleIndex(pinnedModule)
+
+	var cmds []tea.Cmd
+	for i := range m.modules {
+		width := m.width
+		if split {
+			width = right + 4
+			if i == pinned {
+				width = left + 4
+			}
+		}
+		cmds = append(cmds, m.updateModule(i, tea.WindowSizeMsg{Width: width, Height: m.height}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// toggleSplit pins the dashboard beside the active module, or unpins it
+func (m *Model) toggleSplit() tea.Cmd {
+	m.split = !m.split
+	if _, _, ok := m.splitPanes(); m.split && !ok {
+		m.split = false
+		m.toast(components.ToastWarning, "Terminal too narrow to split")
+		return nil
+	}
+	return tea.Batch(m.resizeModules(), m.runScheduled())
+}
+
+// sendNotification is replaced in tests
+var sendNotification = func(subtitle, message string) error {
+	return notify.Send(runner.Default, subtitle, message)
+}
+
+// notifyCmd repeats a module's toast as a macOS notification, so results
+// aren't missed while working in another app
+func notifyCmd(toast components.ToastMsg) tea.Cmd {
+	return func() tea.Msg {
+		if err := sendNotification(toast.Source, toast.Text); err != nil {
+			logger.Warn("Notification failed: %v", err)
+		}
+		return nil
+	}
+}
+
+// toast shows a message from the shell itself above the footer
+func (m *Model) toast(kind components.ToastKind, text string) {
+	m.toasts.Push(components.ToastMsg{Kind: kind, Text: text}, time.Now())
+}
+
+// activeModalOpen reports whether the focused module has a dialog open
+func (m *Model) activeModalOpen() bool {
+	if m.dialog != nil {
+		return true
+	}
+	return m.moduleFocused && m.activeModule < len(m.modules) && m.modules[m.activeModule].HasOpenModal()
+}
+
+// paletteEntries collects a "Go to" entry for every module plus the
+// commands modules expose through palette.Provider
+func (m *Model) paletteEntries() []palette.Entry {
+	var entries []palette.Entry
+	for _, module := range m.modules {
+		entries = append(entries, palette.Entry{
+			Module:  module.Title(),
+			Command: palette.Command{Title: "Go to " + module.Title()},
+		})
+	}
+	for _, module := range m.modules {
+		provider, ok := module.(palette.Provider)
+		if !ok {
+			continue
+		}
+		for _, command := range provider.Commands() {
+			entries = append(entries, palette.Entry{Module: module.Title(), Command: command})
+		}
+	}
+	return entries
+}
+
+// searchEntries collects the searchable content of every module
+func (m *Model) searchEntries() []palette.Entry {
+	var entries []palette.Entry
+	for _, module := range m.modules {
+		searchable, ok := module.(palette.Searchable)
+		if !ok {
+			continue
+		}
+		for _, item := range searchable.SearchItems() {
+			entries = append(entries, palette.Entry{Module: module.Title(), Command: item})
+		}
+	}
+	return entries
+}
+
+// clipboardEntries lists the session's clipboard history for the picker
+func (m *Model) clipboardEntries() []palette.Entry {
+	var entries []palette.Entry
+	for _, item := range clipboard.History() {
+		// Keep multi-line copies (e.g. log lines) on one row
+		title := strings.Join(strings.Fields(item.Text), " ")
+		entries = append(entries, palette.Entry{
+			Module: item.Source,
+			Command: palette.Command{
+				Title: title,
+				Hint:  "Copied at " + item.Copied.Format("15:04:05"),
+				Msg:   item,
+			},
+		})
+	}
+	return entries
+}
+
+// recopy puts a clipboard history item back on the clipboard
+func (m *Model) recopy(item clipboard.Item) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.Recopy(item); err != nil {
+			return components.ToastMsg{Kind: components.ToastError, Text: err.Error()}
+		}
+		return components.ToastMsg{Kind: components.ToastSuccess, Text: "Copied " + components.TruncateString(strings.Join(strings.Fields(item.Text), " "), 40)}
+	}
+}
+
+// yank copies the active module's selected item or last output, or returns
+// nil when it has nothing to copy
+func (m *Model) yank() tea.Cmd {
+	if m.activeModule >= len(m.modules) {
+		return nil
+	}
+	module := m.modules[m.activeModule]
+	copyable, ok := module.(clipboard.Copyable)
+	if !ok {
+		return nil
+	}
+	text := copyable.Copyable()
+	if text == "" {
+		return nil
+	}
+	return m.recopy(clipboard.Item{Text: text, Source: module.Title()})
+}
+
+// yankCommands copies the command lines the active module's selection
+// runs, or returns nil when it runs none
+func (m *Model) yankCommands() tea.Cmd {
+	if m.activeModule >= len(m.modules) {
+		return nil
+	}
+	module := m.modules[m.activeModule]
+	previewer, ok := module.(runner.Previewer)
+	if !ok {
+		return nil
+	}
+	lines := previewer.CommandLines()
+	if len(lines) == 0 {
+		return nil
+	}
+	return m.recopy(clipboard.Item{Text: strings.Join(lines, "\n"), Source: module.Title()})
+}
+
+// exportModule writes the active module's data to a timestamped file in
+// the exports directory
+func (m *Model) exportModule() tea.Cmd {
+	if m.activeModule >= len(m.modules) || m.exportDir == "" {
+		return nil
+	}
+	module := m.modules[m.activeModule]
+	exporter, ok := module.(export.Exporter)
+	if !ok {
+		m.toast(components.ToastInfo, module.Title()+" has nothing to export")
+		return nil
+	}
+	body := exporter.Export()
+	if body == "" {
+		m.toast(components.ToastInfo, "Nothing loaded to export yet")
+		return nil
+	}
+
+	dir, title, version := m.exportDir, module.Title(), m.version
+	return func() tea.Msg {
+		path, err := export.Write(dir, title, version, body, time.Now())
+		if err != nil {
+			logger.Error("Export of %s failed: %v", title, err)
+			return components.ToastMsg{Kind: components.ToastError, Text: "Export failed: " + err.Error()}
+		}
+		logger.Info("Exported %s to %s", title, path)
+		return components.ToastMsg{Kind: components.ToastSuccess, Text: "Exported to " + path}
+	}
+}
+
+// runCommand switches to the module owning entry and, for module commands,
+// focuses it and delivers the command's message
+func (m *Model) runCommand(entry palette.Entry) tea.Cmd {
+	index := m.moduleIndex(entry.Module)
+	if index < 0 {
+		return nil
+	}
+	logger.Info("Command palette: %s › %s", entry.Module, entry.Title)
+
+	var cmds []tea.Cmd
+	if index != m.activeModule {
+		if m.moduleFocused {
+			m.moduleFocused = false
+			cmds = append(cmds, m.updateModule(m.activeModule, events.Blur{}))
+		}
+		m.activeModule = index
+		cmds = append(cmds, m.initModule(index))
+	}
+
+	if entry.Msg != nil {
+		if !m.moduleFocused {
+			m.moduleFocused = true
+			cmds = append(cmds, m.updateModule(index, events.Focus{}))
+		}
+		cmds = append(cmds, m.updateModule(index, entry.Msg))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// moduleIndex returns the index of the module with the given ID, or -1
+func (m *Model) moduleIndex(id string) int {
+	for i, module := range m.modules {
+		if module.Title() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Update handles messages and updates the model, keeping the terminal
+// title in step with the active module and the alerts
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if title := m.syncTitle(); title != nil {
+		cmd = tea.Batch(cmd, title)
+	}
+	return model, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -102,55 +637,191 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Forward RAW size to modules (they handle their own layout)
 		// Don't pre-adjust sizes or we get double reduction!
-		for _, module := range m.modules {
-			_, cmd := module.Update(msg)
-			if cmd != nil {
-				cmds = append(cmds, cmd)
-			}
-		}
+		cmds = append(cmds, m.resizeModules())
 
 	case tea.KeyMsg:
 		key := msg.String()
 		// Normalize to lowercase for case-insensitive commands
 		keyLower := strings.ToLower(key)
 
+		// gg is the only two-key motion; any other key cancels a lone g
+		gPending := m.pendingG
+		m.pendingG = false
+
 		switch key {
 		case "ctrl+c":
-			m.quitting = true
-			return m, tea.Quit
+			return m, m.quit()
+		}
+
+		// A confirmation dialog takes every key until answered
+		if m.dialog != nil {
+			result, done := m.dialog.Update(msg)
+			if done {
+				if index := m.moduleIndex(m.dialog.Module()); index >= 0 && result != nil {
+					cmds = append(cmds, m.updateModule(index, result))
+				}
+				m.dialog = nil
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+		// The command palette takes every key while open
+		if m.palette != nil {
+			entry, done := m.palette.Update(msg)
+			if entry != nil {
+				if m.clipPicker {
+					cmds = append(cmds, m.recopy(entry.Msg.(clipboard.Item)))
+				} else {
+					cmds = append(cmds, m.runCommand(*entry))
+				}
+			}
+			if done {
+				m.palette = nil
+				m.clipPicker = false
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+		// The tour takes every key until it ends
+		if m.tour != nil {
+			return m, m.updateTourKey(keyLower)
+		}
+
+		if m.commandOpen {
+			return m, m.updateCommandLine(msg)
+		}
+
+		if m.vimMode && key == ":" && !m.activeModalOpen() && !m.showHelp && !m.showLogs && !m.showDebug {
+			m.commandOpen = true
+			m.commandLine = ""
+			return m, nil
+		}
+
+		if key == "ctrl+y" && !m.activeModalOpen() {
+			m.showHelp = false
+			m.showLogs = false
+			m.showDebug = false
+			m.palette = palette.NewPicker("Search clipboard history…", "Nothing copied yet this session", "Copy", m.clipboardEntries())
+			m.clipPicker = true
+			return m, tea.Batch(cmds...)
+		}
+
+		if key == "ctrl+k" && !m.activeModalOpen() {
+			m.showHelp = false
+			m.showLogs = false
+			m.showDebug = false
+			m.palette = palette.New(m.paletteEntries())
+			return m, tea.Batch(cmds...)
+		}
+
+		// Ctrl+O jumps to the module behind the newest result toast
+		if key == "ctrl+o" && !m.activeModalOpen() {
+			if toast, ok := m.toasts.TakeResult(); ok {
+				m.showHelp = false
+				m.showLogs = false
+				m.showDebug = false
+				cmds = append(cmds, m.runCommand(palette.Entry{Module: toast.Source, Command: palette.Command{Title: "View result", Msg: toast.Result}}))
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+		// Global search opens from the module switcher; a focused module
+		// receives / like any other key
+		if key == "/" && !m.moduleFocused && !m.showHelp && !m.showLogs && !m.showDebug {
+			m.palette = palette.NewSearch(m.searchEntries())
+			return m, tea.Batch(cmds...)
+		}
+
+		if key == "ctrl+d" && m.debug && !m.activeModalOpen() {
+			m.toggleDebug()
+			return m, tea.Batch(cmds...)
 		}
 
 		// Handle help/logs screens first
+		if m.showDebug {
+			m.updateDebugKey(keyLower)
+			return m, tea.Batch(cmds...)
+		}
+
 		if m.showHelp {
 			switch keyLower {
-			case "esc", "q":
+			case "esc", "q", "?":
 				m.showHelp = false
 			}
 			return m, tea.Batch(cmds...)
 		}
 
 		if m.showLogs {
-			if keyLower == "esc" || keyLower == "q" {
+			switch keyLower {
+			case "esc", "q":
 				m.showLogs = false
+			case "up", "k":
+				m.logView.ScrollBy(-1)
+			case "down", "j":
+				m.logView.ScrollBy(1)
+			default:
+				m.logView.HandleKey(keyLower)
 			}
 			return m, tea.Batch(cmds...)
 		}
 
 		// If module is focused, it gets ALL keys
 		if m.moduleFocused {
+			// Esc closing a dialog or filter should not also leave the
+			// module, so check for one before the module sees the key
+			modalOpen := m.activeModalOpen()
+
+			if m.vimMode && key == "g" && !modalOpen && !gPending {
+				m.pendingG = true
+				return m, nil
+			}
+
+			// ? opens the module's own help unless a dialog is taking keys
+			if key == "?" && !modalOpen && m.activeModule < len(m.modules) {
+				if _, ok := m.modules[m.activeModule].(help.Provider); ok {
+					m.showHelp = true
+					m.showLogs = false
+					return m, nil
+				}
+			}
+
+			// Digits belong to the module (many use them for views), so
+			// Alt+digit jumps to another module from inside one
+			if index, ok := moduleNumber(key, "alt+"); ok && !modalOpen {
+				return m, m.jumpToModule(index)
+			}
+
+			// y copies the module's selection unless it wants the key typed,
+			// and Y the commands behind it
+			if key == "y" {
+				if cmd := m.yank(); cmd != nil {
+					return m, cmd
+				}
+			}
+			if key == "Y" {
+				if cmd := m.yankCommands(); cmd != nil {
+					return m, cmd
+				}
+			}
+
+			// Modules get navigation as events.Nav rather than raw keys
+			var forward tea.Msg = msg
+			if nav, ok := navFor(key, modalOpen, m.vimMode); ok {
+				forward = nav
+			}
+
 			// Pass key to the focused module first
 			if m.activeModule < len(m.modules) {
-				_, cmd := m.modules[m.activeModule].Update(msg)
-				if cmd != nil {
+				if cmd := m.updateModule(m.activeModule, forward); cmd != nil {
 					cmds = append(cmds, cmd)
 				}
 			}
 
 			// If ESC was pressed and module doesn't have open modals, unfocus
 			if key == "esc" && m.activeModule < len(m.modules) {
-				if !m.modules[m.activeModule].HasOpenModal() {
+				if !modalOpen {
 					m.moduleFocused = false
-					if _, cmd := m.modules[m.activeModule].Update(events.Blur{}); cmd != nil {
+					if cmd := m.updateModule(m.activeModule, events.Blur{}); cmd != nil {
 						cmds = append(cmds, cmd)
 					}
 				}
@@ -159,11 +830,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
+		if m.vimMode {
+			var swallowed bool
+			if key, swallowed = m.vimModuleKey(key, gPending); swallowed {
+				return m, nil
+			}
+			keyLower = strings.ToLower(key)
+		}
+
 		// Global commands (only when NOT focused on a module)
 		switch keyLower {
 		case "q":
-			m.quitting = true
-			return m, tea.Quit
+			return m, m.quit()
 		case "?":
 			m.showHelp = !m.showHelp
 			if m.showHelp {
@@ -175,19 +853,47 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showLogs = false
 			} else {
 				m.showLogs = true
+				m.logView = components.ScrollView{Follow: true}
 				m.refreshLogs()
 			}
 			return m, tea.Batch(cmds...)
+		case "s":
+			cmds = append(cmds, m.toggleSplit())
+			return m, tea.Batch(cmds...)
+		case "y":
+			if key == "Y" {
+				if cmd := m.yankCommands(); cmd != nil {
+					return m, cmd
+				}
+				m.toast(components.ToastInfo, "The selection runs no commands to copy")
+				return m, tea.Batch(cmds...)
+			}
+			if cmd := m.yank(); cmd != nil {
+				return m, cmd
+			}
+			m.toast(components.ToastInfo, "Nothing to copy here")
+			return m, tea.Batch(cmds...)
+		case "e":
+			cmds = append(cmds, m.exportModule())
+			return m, tea.Batch(cmds...)
+		case "t":
+			cmds = append(cmds, m.startTour())
+			return m, tea.Batch(cmds...)
 		}
 
 		if len(m.modules) == 0 {
 			return m, tea.Batch(cmds...)
 		}
 
+		if index, ok := moduleNumber(key, ""); ok {
+			cmds = append(cmds, m.jumpToModule(index))
+			return m, tea.Batch(cmds...)
+		}
+
 		switch key {
 		case "tab", "right":
 			m.activeModule = (m.activeModule + 1) % len(m.modules)
-			if init := m.modules[m.activeModule].Init(); init != nil {
+			if init := m.initModule(m.activeModule); init != nil {
 				cmds = append(cmds, init)
 			}
 		case "shift+tab", "left":
@@ -195,23 +901,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.activeModule < 0 {
 				m.activeModule = len(m.modules) - 1
 			}
-			if init := m.modules[m.activeModule].Init(); init != nil {
+			if init := m.initModule(m.activeModule); init != nil {
 				cmds = append(cmds, init)
 			}
 		case "home":
 			m.activeModule = 0
-			if init := m.modules[m.activeModule].Init(); init != nil {
+			if init := m.initModule(m.activeModule); init != nil {
 				cmds = append(cmds, init)
 			}
 		case "end":
 			m.activeModule = len(m.modules) - 1
-			if init := m.modules[m.activeModule].Init(); init != nil {
+			if init := m.initModule(m.activeModule); init != nil {
 				cmds = append(cmds, init)
 			}
 		case "enter":
 			m.moduleFocused = true
 			if m.activeModule < len(m.modules) {
-				if _, cmd := m.modules[m.activeModule].Update(events.Focus{}); cmd != nil {
+				if cmd := m.updateModule(m.activeModule, events.Focus{}); cmd != nil {
 					cmds = append(cmds, cmd)
 				}
 			}
@@ -219,25 +925,86 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, tea.Batch(cmds...)
 
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
+
+	case components.ToastMsg:
+		m.toasts.Push(msg, time.Now())
+
 	case tickMsg:
 		m.lastUpdate = time.Now()
-		// Update active module
-		if m.activeModule < len(m.modules) {
-			_, cmd := m.modules[m.activeModule].Update(msg)
-			if cmd != nil {
+		m.toasts.Expire(m.lastUpdate)
+		if m.showLogs {
+			m.refreshLogs()
+		}
+		if m.showDebug {
+			m.debugStats = debugstats.Read()
+		}
+		cmds = append(cmds, m.runScheduled(), m.doTick())
+
+	case scheduler.DoneMsg:
+		// A scheduled refresh finished; its result belongs to the module
+		if m.schedule != nil {
+			m.schedule.Done(msg, time.Now())
+		}
+		if index := m.moduleIndex(msg.Module); index >= 0 && msg.Msg != nil {
+			if cmd := m.updateModule(index, msg.Msg); cmd != nil {
 				cmds = append(cmds, cmd)
 			}
 		}
-		if m.showLogs {
-			m.refreshLogs()
+
+	case events.ModuleMsg:
+		// Toasts from any module are shown by the shell, tagged with their source
+		if toast, ok := msg.Msg.(components.ToastMsg); ok {
+			toast.Source = msg.Module
+			// The result is already on screen in the active module
+			if m.activeModule < len(m.modules) && m.modules[m.activeModule].Title() == msg.Module {
+				toast.Result = nil
+			}
+			m.toasts.Push(toast, time.Now())
+			if m.config != nil && m.config.Notifications.Notify(msg.Module) {
+				cmds = append(cmds, notifyCmd(toast))
+			}
+			break
+		}
+		// Alerts firing go in the title, and may notify through the terminal
+		if alerting, ok := msg.Msg.(events.Alerting); ok {
+			cmds = append(cmds, m.updateAlerting(alerting))
+			break
+		}
+		// A restored backup is only picked up by the next launch, and this
+		// one mustn't write its state over it
+		if restored, ok := msg.Msg.(events.Restored); ok {
+			m.statePath = ""
+			m.quitNote = restored.Note
+			return m, m.quit()
+		}
+		// Modules that changed how often they refresh
+		if _, ok := msg.Msg.(scheduler.RescheduleMsg); ok {
+			if index := m.moduleIndex(msg.Module); index >= 0 && m.schedule != nil {
+				if scheduled, ok := m.modules[index].(scheduler.Scheduled); ok {
+					m.schedule.Add(msg.Module, scheduled.Schedule()...)
+				}
+			}
+			cmds = append(cmds, m.runScheduled())
+			break
+		}
+		// Modules ask for confirmation through the shared dialog
+		if req, ok := msg.Msg.(dialog.Request); ok {
+			m.dialog = dialog.New(msg.Module, req)
+			break
+		}
+		// Deliver module results to their owner, active or not
+		if index := m.moduleIndex(msg.Module); index >= 0 {
+			if cmd := m.updateModule(index, msg.Msg); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
 		}
-		cmds = append(cmds, doTick())
 
 	default:
 		// Pass other messages to the active module
 		if m.activeModule < len(m.modules) {
-			_, cmd := m.modules[m.activeModule].Update(msg)
-			if cmd != nil {
+			if cmd := m.updateModule(m.activeModule, msg); cmd != nil {
 				cmds = append(cmds, cmd)
 			}
 		}
@@ -249,6 +1016,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // View renders the application
 func (m *Model) View() string {
 	if m.quitting {
+		if m.quitNote != "" {
+			return m.quitNote + "\n"
+		}
 		return "Thanks for using Dev Cockpit!\n"
 	}
 
@@ -259,6 +1029,14 @@ func (m *Model) View() string {
 	}
 
 	// Handle overlays (they take full screen)
+	if m.dialog != nil {
+		return m.dialog.View(m.width, m.height)
+	}
+
+	if m.palette != nil {
+		return m.palette.View(m.width, m.height)
+	}
+
 	if m.showHelp {
 		return m.renderHelp()
 	}
@@ -267,6 +1045,10 @@ func (m *Model) View() string {
 		return m.renderLogOverlay(layout)
 	}
 
+	if m.showDebug {
+		return m.renderDebugOverlay()
+	}
+
 	// Render main UI
 	tabs := m.renderTabs()
 	footer := m.renderFooter()
@@ -277,44 +1059,72 @@ func (m *Model) View() string {
 		moduleContent = m.modules[m.activeModule].View()
 	}
 
-	// Add hint if not focused
+	if left, right, ok := m.splitPanes(); ok {
+		moduleContent = m.renderSplit(left, right, moduleContent)
+	}
+
+	// Add hint if not focused (the tour's card says how to focus)
 	finalContent := moduleContent
-	if !m.moduleFocused {
+	if !m.moduleFocused && m.tour == nil {
 		hint := m.renderHint(layout.ContentWidth)
 		finalContent = lipgloss.JoinVertical(lipgloss.Top, hint, "", moduleContent)
 	}
 
+	// Toasts take their lines from the bottom of the content area
+	contentHeight := layout.ContentHeight
+	toasts := m.toasts.View(layout.ContentWidth)
+	if toasts != "" {
+		contentHeight = max(contentHeight-m.toasts.Len(), 1)
+	}
+
+	// The tour's card sits at the bottom, below as much of the module it
+	// describes as fits
+	if m.tour != nil {
+		card := m.renderTour(layout.ContentWidth - 4)
+		above := max(contentHeight-lipgloss.Height(card)-1, 0)
+		finalContent = lipgloss.JoinVertical(lipgloss.Top, components.Viewport(finalContent, above), "", card)
+	}
+
 	// Constrain content to prevent overflow
 	constrainedContent := lipgloss.NewStyle().
 		Width(layout.ContentWidth).
-		MaxHeight(layout.ContentHeight).
+		MaxHeight(contentHeight).
 		Padding(0, 2).
-		Render(components.Viewport(finalContent, layout.ContentHeight))
+		Render(components.Viewport(finalContent, contentHeight))
 
 	// Stack everything
-	return lipgloss.JoinVertical(
-		lipgloss.Top,
-		tabs,
-		constrainedContent,
-		footer,
-	)
-}
-
-func (m *Model) renderTabs() string {
-	styles := components.NewBaseStyles()
+	parts := []string{tabs, constrainedContent}
+	if toasts != "" {
+		parts = append(parts, toasts)
+	}
+	parts = append(parts, footer)
+	view := lipgloss.JoinVertical(lipgloss.Top, parts...)
 
-	// Calculate fixed width for each tab to prevent jumping
-	numTabs := len(m.modules)
-	if numTabs == 0 {
-		return ""
+	// The first frame at the real terminal size is what the user sees
+	if m.width > 0 {
+		startup.Mark(startup.FirstRender)
 	}
+	return view
+}
 
+// tabWidth is the fixed width of each tab, so tabs don't jump around
+func (m *Model) tabWidth() int {
 	// Reserve space for borders and padding in tab bar
 	availableWidth := m.width - 8 // margins and borders
-	tabWidth := (availableWidth / numTabs) - 2 // spacing between tabs
+	tabWidth := (availableWidth / len(m.modules)) - 2 // spacing between tabs
 	if tabWidth < 12 {
 		tabWidth = 12 // minimum width
 	}
+	return tabWidth
+}
+
+func (m *Model) renderTabs() string {
+	styles := components.NewBaseStyles()
+
+	if len(m.modules) == 0 {
+		return ""
+	}
+	tabWidth := m.tabWidth()
 
 	var tabs []string
 
@@ -342,7 +1152,7 @@ func (m *Model) renderTabs() string {
 					Width(tabWidth).
 					Bold(true).
 					Foreground(styles.Theme.Primary).
-					Background(lipgloss.Color("#1A1A2E")).
+					Background(components.ColorPanel).
 					Padding(0, 1).
 					Align(lipgloss.Center)
 			}
@@ -401,10 +1211,22 @@ func (m *Model) renderFooter() string {
 			Bold(true).
 			Render(" [FOCUSED]")
 	}
+	if m.safe {
+		focusIndicator += lipgloss.NewStyle().
+			Foreground(styles.Theme.Warning).
+			Bold(true).
+			Render(" [SAFE MODE]")
+	}
 
 	shortcuts := "Tab Switch • Enter Focus • Esc Back • ? Help • L Logs • Q Quit"
+	if m.vimMode {
+		shortcuts = "h/l Switch • Enter Focus • Esc Back • : Command • ? Help • Q Quit"
+	}
 	info := versionStyle.Render(fmt.Sprintf("Dev Cockpit v%s", m.version)) + focusIndicator
 	left := fmt.Sprintf("%s  │  %s", info, shortcutsStyle.Render(shortcuts))
+	if m.commandOpen {
+		left = versionStyle.Render(":"+m.commandLine+"▏") + shortcutsStyle.Render("  Enter Run • Esc Cancel")
+	}
 	status := statusStyle.Render(fmt.Sprintf("⟳ %s", m.lastUpdate.Format("15:04:05")))
 
 	// Calculate spacing dynamically
@@ -457,7 +1279,19 @@ func (m *Model) refreshLogs() {
 	m.logLines = trimmed
 }
 
+// logBoxHeight is the height of the log overlay box
+func (m *Model) logBoxHeight(layout *components.Layout) int {
+	return max(layout.ContentHeight-6, 12)
+}
+
+// logVisible is how many log lines fit in the overlay, below its border,
+// padding and four header lines
+func (m *Model) logVisible() int {
+	return max(m.logBoxHeight(components.NewLayout(m.width, m.height))-8, 1)
+}
+
 func (m *Model) renderLogOverlay(layout *components.Layout) string {
+	maxHeight := m.logBoxHeight(layout)
 	boxWidth := layout.ContentWidth - 6
 	if boxWidth > 120 {
 		boxWidth = 120
@@ -484,7 +1318,7 @@ func (m *Model) renderLogOverlay(layout *components.Layout) string {
 	location := fmt.Sprintf("File: %s", m.logPath)
 	builder.WriteString(infoStyle.Render(location))
 	builder.WriteString("\n")
-	builder.WriteString(infoStyle.Render("Press 'l' to close"))
+	builder.WriteString(infoStyle.Render("↑/↓ PgUp/PgDn Home/End Scroll • Press 'l' to close"))
 	builder.WriteString("\n\n")
 
 	if m.logLoadErr != nil {
@@ -496,16 +1330,16 @@ func (m *Model) renderLogOverlay(layout *components.Layout) string {
 		builder.WriteString(infoStyle.Render("No log entries captured yet."))
 		builder.WriteString("\n")
 	} else {
-		for _, line := range m.logLines {
-			truncated := components.TruncateString(line, boxWidth-4)
-			builder.WriteString(contentStyle.Render(truncated))
-			builder.WriteString("\n")
+		// Leave room for the scrollbar; the view opens on the newest lines
+		// and follows new ones until scrolled up
+		lines := make([]string, len(m.logLines))
+		for i, line := range m.logLines {
+			lines[i] = contentStyle.Render(components.TruncateString(line, boxWidth-6))
 		}
-	}
-
-	maxHeight := layout.ContentHeight - 6
-	if maxHeight < 12 {
-		maxHeight = 12
+		m.logView.Height = m.logVisible()
+		m.logView.SetLines(lines)
+		builder.WriteString(m.logView.View())
+		builder.WriteString("\n")
 	}
 
 	box := lipgloss.NewStyle().
@@ -525,12 +1359,33 @@ func (m *Model) renderLogOverlay(layout *components.Layout) string {
 	)
 }
 
+// renderSplit places the pinned dashboard to the left of the active module
+func (m *Model) renderSplit(left, right int, active string) string {
+	pinned := m.moduleIndex(pinnedModule)
+	if m.activeModule == pinned {
+		active = lipgloss.NewStyle().
+			Foreground(components.ColorMuted).
+			Render("Press Tab to open another module beside the dashboard")
+	}
+
+	leftPane := lipgloss.NewStyle().Width(left).MaxWidth(left).Render(m.modules[pinned].View())
+	rightPane := lipgloss.NewStyle().Width(right).MaxWidth(right).Render(active)
+
+	height := max(lipgloss.Height(leftPane), lipgloss.Height(rightPane))
+	divider := lipgloss.NewStyle().
+		Foreground(components.ColorBorder).
+		Padding(0, 1).
+		Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftPane, divider, rightPane)
+}
+
 func (m *Model) renderHint(width int) string {
 	styles := components.NewBaseStyles()
 
 	hintStyle := lipgloss.NewStyle().
 		Foreground(styles.Theme.Warning).
-		Background(lipgloss.Color("#1A1A2E")).
+		Background(components.ColorPanel).
 		Bold(true).
 		Padding(0, 2).
 		Border(lipgloss.RoundedBorder()).
@@ -544,74 +1399,81 @@ func (m *Model) renderHint(width int) string {
 		Render(hint)
 }
 
+// renderHelp shows the focused module's help, or the shell's keys
 func (m *Model) renderHelp() string {
-	boxStyle := lipgloss.NewStyle().
-		Width(m.width - 10).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00D9FF")).
-		Background(lipgloss.Color("#0F1419")).
-		Padding(2, 4)
-
-	headerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#00D9FF")).
-		Background(lipgloss.Color("#1A1A2E")).
-		Padding(0, 2).
-		MarginBottom(1)
-
-	sectionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFA500")).
-		Bold(true).
-		MarginTop(1)
-
-	keyStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
-		Bold(true)
-
-	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#DDD"))
-
-	content := lipgloss.JoinVertical(
-		lipgloss.Left,
-		headerStyle.Render("⌘ DEV COCKPIT HELP"),
-		"",
-		sectionStyle.Render("NAVIGATION (GLOBAL):"),
-		fmt.Sprintf("  %s  Switch modules", keyStyle.Render("Tab / Shift+Tab")),
-		fmt.Sprintf("  %s          Focus current module", keyStyle.Render("Enter")),
-		fmt.Sprintf("  %s            Leave focused module", keyStyle.Render("Esc")),
-		"",
-		sectionStyle.Render("COMMANDS:"),
-		fmt.Sprintf("  %s            Close current dialog", keyStyle.Render("q")),
-		fmt.Sprintf("  %s            Quit application", keyStyle.Render("Q")),
-		fmt.Sprintf("  %s            Toggle this help", keyStyle.Render("?")),
-		fmt.Sprintf("  %s            Toggle logs overlay", keyStyle.Render("l")),
-		fmt.Sprintf("  %s            Refresh current view", keyStyle.Render("r")),
-		"",
-		sectionStyle.Render("INSIDE MODULES:"),
-		descStyle.Render("  Follow the on-screen hints for module-specific controls"),
-		"",
-		sectionStyle.Render("SUPPORT:"),
-		descStyle.Render("  Navigate to the Support tab for contribution links"),
-		"",
-		lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render("Press 'q' or 'Esc' to close help"),
-	)
-
-	centered := lipgloss.Place(
-		m.width,
-		m.height,
-		lipgloss.Center,
-		lipgloss.Center,
-		boxStyle.Render(content),
-	)
+	footer := "Press 'q' or 'Esc' to close help"
+	if m.moduleFocused && m.activeModule < len(m.modules) {
+		if provider, ok := m.modules[m.activeModule].(help.Provider); ok {
+			return help.View(provider.Help(), m.width, m.height, footer+" • Esc again leaves the module")
+		}
+	}
+	return help.View(m.globalHelp(), m.width, m.height, footer)
+}
 
-	return centered
+// globalHelp lists the shell's own keys
+func (m *Model) globalHelp() help.Help {
+	sections := []help.Section{
+		{Title: "Navigation (global)", Bindings: []help.Binding{
+			{Key: "Tab / Shift+Tab", Desc: "Switch modules"},
+			{Key: "1-9", Desc: "Jump to module by position"},
+			{Key: "Alt+1-9", Desc: "Jump to module from inside another"},
+			{Key: "Enter", Desc: "Focus current module"},
+			{Key: "Esc", Desc: "Leave focused module"},
+			{Key: "Ctrl+K", Desc: "Command palette (search actions)"},
+			{Key: "/", Desc: "Search packages, containers, ports..."},
+			{Key: "y", Desc: "Copy selected item or last output"},
+			{Key: "Y", Desc: "Copy the commands the selected action runs"},
+			{Key: "Ctrl+Y", Desc: "Clipboard history (copy again)"},
+			{Key: "Ctrl+O", Desc: "View the result a toast reports"},
+			{Key: "e", Desc: "Export module data to ~/.devcockpit/exports"},
+			{Key: "S", Desc: "Split view (pin dashboard on the left)"},
+			{Key: "T", Desc: "Guided tour of every module"},
+		}},
+		{Title: "Commands", Bindings: []help.Binding{
+			{Key: "q", Desc: "Close current dialog"},
+			{Key: "Q", Desc: "Quit application"},
+			{Key: "?", Desc: "Toggle this help"},
+			{Key: "l", Desc: "Toggle logs overlay"},
+			{Key: "r", Desc: "Refresh current view"},
+		}},
+		{Title: "Inside modules", Note: "Press ? in a focused module for its own keys"},
+	}
+	if m.vimMode {
+		sections = append(sections, help.Section{Title: "Vim mode", Bindings: []help.Binding{
+			{Key: "h / l", Desc: "Previous / next module"},
+			{Key: "j / k", Desc: "Move down / up in lists"},
+			{Key: "gg / G", Desc: "First / last item (or module)"},
+			{Key: ":<module>", Desc: "Jump to a module, e.g. :docker"},
+			{Key: ":update :q", Desc: "Check for updates / quit"},
+			{Key: ":logs :help :split :tour", Desc: "Logs, help, split view, tour"},
+		}})
+	}
+	sections = append(sections, help.Section{Title: "Support", Note: "Navigate to the Support tab for contribution links"})
+	return help.Help{Title: "DEV COCKPIT HELP", Sections: sections}
 }
 
-// tickMsg is sent every second to update the display
+// tickMsg is sent every tickEvery to expire toasts and run scheduled tasks
 type tickMsg time.Time
 
-func doTick() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+// Split mode pins this module on the left
+const pinnedModule = "Dashboard"
+
+const (
+	splitRatio = 0.4 // share of the content width for the pinned pane
+	splitGap   = 3   // divider column with a space either side
+)
+
+func (m *Model) doTick() tea.Cmd {
+	return tea.Tick(m.tickEvery, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
+
+// tickInterval is update_interval, how often the shell checks for due
+// refreshes, kept between a tenth of a second and ten seconds
+func tickInterval(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.UpdateInterval <= 0 {
+		return time.Second
+	}
+	return min(max(time.Duration(cfg.UpdateInterval)*time.Millisecond, 100*time.Millisecond), 10*time.Second)
+}
@@ -0,0 +1,1134 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/clipboard"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
+	"github.com/caioricciuti/dev-cockpit/internal/updater"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// stubModule renders a fixed view so shell snapshots don't depend on the host
+type stubModule struct {
+	title string
+	view  string
+}
+
+func (s stubModule) Init() tea.Cmd                             { return nil }
+func (s stubModule) Update(msg tea.Msg) (interface{}, tea.Cmd) { return s, nil }
+func (s stubModule) View() string                              { return s.view }
+func (s stubModule) Title() string                             { return s.title }
+func (s stubModule) HasOpenModal() bool                        { return false }
+
+func newSnapshotModel(size golden.Size) *Model {
+	titles := []string{"Dashboard", "Quick Actions", "Cleanup", "Packages", "System", "Docker", "Network", "Security", "Settings", "Support"}
+	m := &Model{
+		version:    "1.0.0",
+		lastUpdate: time.Date(2024, 7, 1, 9, 30, 0, 0, time.UTC),
+	}
+	for _, title := range titles {
+		m.modules = append(m.modules, stubModule{title: title, view: title + " content"})
+	}
+	m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+	return m
+}
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := newSnapshotModel(size)
+			view := m.View()
+			golden.RequireEqual(t, view)
+			golden.RequireFits(t, view, size)
+		})
+	}
+}
+
+// providerModule records the messages it receives and exposes one command
+type providerModule struct {
+	stubModule
+	received []tea.Msg
+}
+
+func (p *providerModule) Update(msg tea.Msg) (interface{}, tea.Cmd) {
+	p.received = append(p.received, msg)
+	return p, nil
+}
+
+func (p *providerModule) Commands() []palette.Command {
+	return []palette.Command{{Title: "Flush DNS", Hint: "Clear DNS cache", Msg: flushMsg{}}}
+}
+
+type flushMsg struct{}
+
+func typeKeys(m *Model, s string) {
+	for _, r := range s {
+		if r == ' ' {
+			m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+			continue
+		}
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// drain runs cmd and feeds every resulting message back into m
+func drain(m *Model, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			drain(m, c)
+		}
+		return
+	}
+	if msg != nil {
+		m.Update(msg)
+	}
+}
+
+func TestCommandPaletteRunsModuleCommand(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	actions := &providerModule{stubModule: stubModule{title: "Quick Actions"}}
+	m.modules[1] = actions
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if m.palette == nil {
+		t.Fatal("Ctrl+K should open the command palette")
+	}
+	typeKeys(m, "flush dns")
+	golden.RequireEqual(t, m.View())
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.palette != nil {
+		t.Fatal("palette should close after running a command")
+	}
+	if m.activeModule != 1 || !m.moduleFocused {
+		t.Fatalf("active module = %d focused = %v, want Quick Actions focused", m.activeModule, m.moduleFocused)
+	}
+	if n := len(actions.received); n == 0 {
+		t.Fatal("module received no messages")
+	}
+	if _, ok := actions.received[len(actions.received)-1].(flushMsg); !ok {
+		t.Fatalf("last message = %T, want flushMsg", actions.received[len(actions.received)-1])
+	}
+}
+
+func TestCommandPaletteGoTo(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	typeKeys(m, "go netw")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if got := m.modules[m.activeModule].Title(); got != "Network" {
+		t.Fatalf("active module = %q, want Network", got)
+	}
+	if m.moduleFocused {
+		t.Error("Go to should switch modules without focusing")
+	}
+}
+
+func TestCommandPaletteEscCloses(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	if m.palette != nil || m.activeModule != 0 {
+		t.Fatal("Esc should close the palette without switching modules")
+	}
+}
+
+// searchModule exposes one search result
+type searchModule struct {
+	providerModule
+}
+
+func (s *searchModule) SearchItems() []palette.Command {
+	return []palette.Command{{Title: "postgres", Hint: "postgres:16 • running", Msg: selectMsg{id: "abc"}}}
+}
+
+type selectMsg struct{ id string }
+
+func TestGlobalSearchFocusesOwningModule(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	docker := &searchModule{providerModule{stubModule: stubModule{title: "Docker"}}}
+	m.modules[5] = docker
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if m.palette == nil {
+		t.Fatal("/ should open global search")
+	}
+	typeKeys(m, "postg")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.activeModule != 5 || !m.moduleFocused {
+		t.Fatalf("active module = %d focused = %v, want Docker focused", m.activeModule, m.moduleFocused)
+	}
+	last := docker.received[len(docker.received)-1]
+	if msg, ok := last.(selectMsg); !ok || msg.id != "abc" {
+		t.Fatalf("last message = %#v, want selectMsg{abc}", last)
+	}
+}
+
+func TestGlobalSearchIgnoredWhenFocused(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+
+	if m.palette != nil {
+		t.Fatal("/ should reach the focused module instead of opening search")
+	}
+}
+
+func TestClipboardHistoryRecopies(t *testing.T) {
+	fake := runner.NewFake().Set("pbcopy", "", nil)
+	clipboard.Runner = fake
+	clipboard.Clear()
+	t.Cleanup(func() {
+		clipboard.Runner = runner.Default
+		clipboard.Clear()
+	})
+	_ = clipboard.Copy("Support", "https://github.com/sponsors/caioricciuti")
+	_ = clipboard.Copy("Network", "192.168.1.42")
+
+	m := newSnapshotModel(golden.Sizes[0])
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	if m.palette == nil || !m.clipPicker {
+		t.Fatal("Ctrl+Y should open the clipboard history")
+	}
+	typeKeys(m, "spons")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.palette != nil {
+		t.Fatal("picker should close after copying")
+	}
+	drain(m, cmd)
+
+	if m.activeModule != 0 {
+		t.Error("copying from history should not switch modules")
+	}
+	if history := clipboard.History(); history[0].Text != "https://github.com/sponsors/caioricciuti" {
+		t.Errorf("history[0] = %q, want the re-copied URL", history[0].Text)
+	}
+	if m.toasts.Len() == 0 {
+		t.Error("re-copying should show a toast")
+	}
+}
+
+// copyModule offers text to y and records the keys it receives
+type copyModule struct {
+	providerModule
+	text string
+}
+
+func (c *copyModule) Copyable() string { return c.text }
+
+func TestYankCopiesModuleSelection(t *testing.T) {
+	fake := runner.NewFake().Set("pbcopy", "", nil)
+	clipboard.Runner = fake
+	clipboard.Clear()
+	t.Cleanup(func() {
+		clipboard.Runner = runner.Default
+		clipboard.Clear()
+	})
+
+	m := newSnapshotModel(golden.Sizes[0])
+	module := &copyModule{providerModule: providerModule{stubModule: stubModule{title: "Network"}}, text: "localhost:3000"}
+	m.modules[6] = module
+	m.activeModule = 6
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	drain(m, cmd)
+	if history := clipboard.History(); len(history) != 1 || history[0].Text != "localhost:3000" || history[0].Source != "Network" {
+		t.Fatalf("history = %+v, want the module's selection", history)
+	}
+
+	// A focused module with nothing to copy gets y as a typed key
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	module.text = ""
+	module.received = nil
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if len(module.received) != 1 {
+		t.Errorf("received %v, want y passed through", module.received)
+	}
+	if len(clipboard.History()) != 1 {
+		t.Error("nothing should be copied")
+	}
+}
+
+// helpModule has its own help
+type helpModule struct {
+	providerModule
+}
+
+func (h *helpModule) Help() help.Help {
+	return help.Help{Title: "NETWORK", Sections: []help.Section{{Title: "Ports", Bindings: []help.Binding{{Key: "S", Desc: "Share on LAN"}}}}}
+}
+
+func TestModuleHelp(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[1])
+	m.modules[6] = &helpModule{providerModule{stubModule: stubModule{title: "Network"}}}
+	m.activeModule = 6
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if !m.showHelp || !strings.Contains(m.View(), "DEV COCKPIT HELP") {
+		t.Fatal("? in the module switcher should show the global help")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if m.showHelp {
+		t.Fatal("? should close the help")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	view := m.View()
+	if !m.showHelp || !strings.Contains(view, "Share on LAN") || strings.Contains(view, "DEV COCKPIT HELP") {
+		t.Fatalf("? in a focused module should show its own help:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showHelp || !m.moduleFocused {
+		t.Error("Esc should close the help and keep the module focused")
+	}
+}
+
+// sizedModule records the last size it was given
+type sizedModule struct {
+	stubModule
+	size tea.WindowSizeMsg
+}
+
+func (s *sizedModule) Update(msg tea.Msg) (interface{}, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		s.size = size
+	}
+	return s, nil
+}
+
+func TestSplitPinsDashboard(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[1])
+	dashboard := &sizedModule{stubModule: stubModule{title: "Dashboard", view: "Dashboard content"}}
+	network := &sizedModule{stubModule: stubModule{title: "Network", view: "Network content"}}
+	m.modules[0] = dashboard
+	m.modules[6] = network
+	m.activeModule = 6
+
+	drain(m, m.toggleSplit())
+	if !m.split {
+		t.Fatal("split should be enabled at 120 columns")
+	}
+	// Each pane is reported with the shell's 4 columns of margin
+	panes := dashboard.size.Width - 4 + splitGap + network.size.Width - 4
+	if dashboard.size.Width >= network.size.Width || panes != 116 {
+		t.Errorf("pane widths = %d + %d, want dashboard narrower and both filling the content width", dashboard.size.Width, network.size.Width)
+	}
+	view := m.View()
+	golden.RequireEqual(t, view)
+	golden.RequireFits(t, view, golden.Sizes[1])
+
+	drain(m, m.toggleSplit())
+	if dashboard.size.Width != 120 || network.size.Width != 120 {
+		t.Errorf("unsplit widths = %d, %d, want full width", dashboard.size.Width, network.size.Width)
+	}
+}
+
+func TestSplitTooNarrow(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	if m.split || m.toasts.Len() == 0 {
+		t.Fatalf("split = %v toast = %q, want split refused with a toast", m.split, m.toasts.Latest())
+	}
+}
+
+// modalModule closes its dialog on Esc and counts the navigation it gets
+type modalModule struct {
+	stubModule
+	open bool
+	navs int
+}
+
+func (d *modalModule) Update(msg tea.Msg) (interface{}, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyEsc {
+			d.open = false
+		}
+	case events.Nav:
+		d.navs++
+	}
+	return d, nil
+}
+
+func (d *modalModule) HasOpenModal() bool { return d.open }
+
+func TestEscClosingModalKeepsFocus(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	dialog := &modalModule{stubModule: stubModule{title: "Dashboard"}, open: true}
+	m.modules[0] = dialog
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if dialog.open || !m.moduleFocused {
+		t.Fatalf("open = %v focused = %v, want dialog closed and module still focused", dialog.open, m.moduleFocused)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.moduleFocused {
+		t.Error("a second Esc should leave the module")
+	}
+}
+
+func TestVimModeSwitchesModules(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.vimMode = true
+
+	steps := []struct {
+		keys string
+		want int
+	}{
+		{"l", 1},
+		{"l", 2},
+		{"h", 1},
+		{"G", 9},
+		{"g", 9}, // waits for the second g
+		{"g", 0},
+	}
+	for _, step := range steps {
+		typeKeys(m, step.keys)
+		if m.activeModule != step.want {
+			t.Fatalf("after %q active module = %d, want %d", step.keys, m.activeModule, step.want)
+		}
+	}
+	if m.showLogs {
+		t.Error("l should switch modules in vim mode, not open logs")
+	}
+}
+
+func TestNumberKeysJumpToModules(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	tabs := &providerModule{stubModule: stubModule{title: "System"}}
+	m.modules[4] = tabs
+
+	typeKeys(m, "5")
+	if m.activeModule != 4 {
+		t.Fatalf("5 opened module %d, want 4", m.activeModule)
+	}
+	typeKeys(m, "0")
+	if m.activeModule != 4 {
+		t.Errorf("0 switched to module %d", m.activeModule)
+	}
+
+	// Inside a module digits are the module's; Alt+digit leaves it
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	typeKeys(m, "2")
+	if m.activeModule != 4 || !m.moduleFocused {
+		t.Fatalf("2 left the focused module for %d", m.activeModule)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("7"), Alt: true})
+	if m.activeModule != 6 || m.moduleFocused {
+		t.Errorf("alt+7: active = %d, focused = %v; want 6, unfocused", m.activeModule, m.moduleFocused)
+	}
+
+	var got []tea.Msg
+	for _, msg := range tabs.received {
+		switch msg.(type) {
+		case tea.KeyMsg, events.Blur:
+			got = append(got, msg)
+		}
+	}
+	if len(got) != 2 || got[1] != (events.Blur{}) {
+		t.Errorf("focused module received %v, want the 2 key then Blur", got)
+	}
+}
+
+func TestFocusedModuleReceivesNav(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.vimMode = true
+	list := &providerModule{stubModule: stubModule{title: "Dashboard"}}
+	m.modules[0] = list
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	typeKeys(m, "kggGx")
+
+	want := []tea.Msg{events.NavDown, events.NavUp, events.NavTop, events.NavBottom}
+	var got []tea.Msg
+	for _, msg := range list.received {
+		switch msg.(type) {
+		case events.Nav, tea.KeyMsg:
+			got = append(got, msg)
+		}
+	}
+	if len(got) != len(want)+1 {
+		t.Fatalf("received %v, want %v then the x key", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("message %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if key, ok := got[len(want)].(tea.KeyMsg); !ok || key.String() != "x" {
+		t.Errorf("last message = %v, want raw x key", got[len(want)])
+	}
+
+	// Without vim mode j/k still navigate, but g/G stay keys
+	m.vimMode = false
+	list.received = nil
+	typeKeys(m, "jG")
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	got = nil
+	for _, msg := range list.received {
+		switch msg.(type) {
+		case events.Nav, tea.KeyMsg:
+			got = append(got, msg)
+		}
+	}
+	if len(got) != 3 || got[0] != events.NavDown || got[2] != events.NavUp {
+		t.Fatalf("without vim mode received %v, want NavDown, the G key, then NavUp", got)
+	}
+	if key, ok := got[1].(tea.KeyMsg); !ok || key.String() != "G" {
+		t.Errorf("message 1 = %v, want raw G key", got[1])
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.vimMode = true
+
+	typeKeys(m, ":dock")
+	if !m.commandOpen || m.commandLine != "dock" {
+		t.Fatalf("command line open = %v %q, want typing dock", m.commandOpen, m.commandLine)
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	drain(m, cmd)
+	if m.commandOpen || m.activeModule != 5 {
+		t.Fatalf("open = %v active module = %d, want :dock to go to Docker", m.commandOpen, m.activeModule)
+	}
+
+	typeKeys(m, ":quick actions")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.activeModule != 1 {
+		t.Errorf("active module = %d, want Quick Actions", m.activeModule)
+	}
+
+	typeKeys(m, ":s")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.activeModule != 1 || m.toasts.Len() == 0 {
+		t.Errorf("ambiguous :s should only show a toast, got module %d toast %q", m.activeModule, m.toasts.Latest())
+	}
+
+	typeKeys(m, ":q")
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || !m.quitting {
+		t.Error(":q should quit")
+	}
+}
+
+func TestCommandLineUpdate(t *testing.T) {
+	latestRelease = func() (*updater.Release, error) { return &updater.Release{TagName: "v1.2.0"}, nil }
+	t.Cleanup(func() { latestRelease = updater.FetchLatestRelease })
+
+	m := newSnapshotModel(golden.Sizes[0])
+	m.vimMode = true
+	m.version = "1.1.0"
+	typeKeys(m, ":update")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	drain(m, cmd)
+	if got := m.toasts.Latest(); got != "v1.2.0 available • run devcockpit update" {
+		t.Errorf("toast = %q, want the new release", got)
+	}
+}
+
+// persistentModule saves and restores a cursor
+type persistentModule struct {
+	stubModule
+	cursor int
+}
+
+func (p *persistentModule) SaveState() (json.RawMessage, error) {
+	return json.Marshal(p.cursor)
+}
+
+func (p *persistentModule) RestoreState(data json.RawMessage) error {
+	return json.Unmarshal(data, &p.cursor)
+}
+
+func TestSessionStateRestored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	m := newSnapshotModel(golden.Sizes[0])
+	m.statePath = path
+	m.modules[2] = &persistentModule{stubModule: stubModule{title: "Cleanup"}, cursor: 3}
+	m.activeModule = 6
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd == nil {
+		t.Fatal("Ctrl+C should quit")
+	}
+
+	next := newSnapshotModel(golden.Sizes[0])
+	next.statePath = path
+	cleanup := &persistentModule{stubModule: stubModule{title: "Cleanup"}}
+	next.modules[2] = cleanup
+	next.restoreState()
+
+	if next.activeModule != 6 || cleanup.cursor != 3 {
+		t.Errorf("restored module %d cursor %d, want Network and cursor 3", next.activeModule, cleanup.cursor)
+	}
+}
+
+func TestRestoreQuitsWithoutSaving(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"active_module":"Docker"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newSnapshotModel(golden.Sizes[0])
+	m.statePath = path
+	if _, cmd := m.Update(events.ModuleMsg{Module: "Settings", Msg: events.Restored{Note: "✓ Restored 3 files"}}); cmd == nil {
+		t.Fatal("a restore should quit")
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"active_module":"Docker"}` {
+		t.Errorf("state.json was written over after the restore: %s", data)
+	}
+	if view := m.View(); view != "✓ Restored 3 files\n" {
+		t.Errorf("view = %q, want the restore's note", view)
+	}
+}
+
+func TestModuleToastShownWithSource(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.Update(events.ModuleMsg{Module: "Cleanup", Msg: components.ToastMsg{Kind: components.ToastSuccess, Text: "Cleaned 3 items"}})
+	if m.toasts.Len() != 1 || !strings.Contains(m.View(), "Cleanup • Cleaned 3 items") {
+		t.Errorf("module toast not shown with its source:\n%s", m.View())
+	}
+}
+
+func TestModuleToastNotifiesWhenEnabled(t *testing.T) {
+	var sent []string
+	orig := sendNotification
+	sendNotification = func(subtitle, message string) error {
+		sent = append(sent, subtitle+": "+message)
+		return nil
+	}
+	t.Cleanup(func() { sendNotification = orig })
+
+	m := newSnapshotModel(golden.Sizes[0])
+	m.config = &config.Config{Notifications: config.NotificationsConfig{
+		Enabled: true,
+		Modules: map[string]bool{"quickactions": true},
+	}}
+	for _, module := range []string{"Quick Actions", "Docker"} {
+		_, cmd := m.Update(events.ModuleMsg{Module: module, Msg: components.ToastMsg{Text: "done"}})
+		drain(m, cmd)
+	}
+	if len(sent) != 1 || sent[0] != "Quick Actions: done" {
+		t.Errorf("sent %q, want only the Quick Actions notification", sent)
+	}
+}
+
+func TestMouseClicks(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[1])
+	list := &providerModule{stubModule: stubModule{title: "Cleanup", view: "first row\nsecond row"}}
+	m.modules[2] = list
+
+	m.Update(tea.MouseMsg{Type: tea.MouseLeft, X: 2 + 2*m.tabWidth() + 1, Y: 1})
+	if m.activeModule != 2 || m.moduleFocused {
+		t.Fatalf("tab click: active = %d focused = %v, want module 2 unfocused", m.activeModule, m.moduleFocused)
+	}
+
+	// Click the module's second line where it's drawn on screen
+	y := -1
+	for i, line := range strings.Split(m.View(), "\n") {
+		if strings.Contains(line, "second row") {
+			y = i
+		}
+	}
+	m.Update(tea.MouseMsg{Type: tea.MouseLeft, X: 10, Y: y})
+	if !m.moduleFocused {
+		t.Error("clicking the content should focus the module")
+	}
+	var clicks []events.Click
+	for _, msg := range list.received {
+		if click, ok := msg.(events.Click); ok {
+			clicks = append(clicks, click)
+		}
+	}
+	if len(clicks) != 1 || clicks[0] != (events.Click{X: 8, Y: 1}) {
+		t.Errorf("module got clicks %v, want one on its second line", clicks)
+	}
+
+	m.Update(tea.MouseMsg{Type: tea.MouseWheelDown})
+	if last := list.received[len(list.received)-1]; last != events.NavDown {
+		t.Errorf("wheel sent %v, want NavDown", last)
+	}
+}
+
+func TestMouseWheelSparesModals(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	dialog := &modalModule{stubModule: stubModule{title: "Dashboard"}, open: true}
+	m.modules[0] = dialog
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m.Update(tea.MouseMsg{Type: tea.MouseWheelDown})
+	if dialog.navs != 0 {
+		t.Error("the wheel navigated behind an open dialog")
+	}
+	dialog.open = false
+	m.Update(tea.MouseMsg{Type: tea.MouseWheelDown})
+	if dialog.navs != 1 {
+		t.Errorf("navs = %d, want the wheel to navigate once the dialog closed", dialog.navs)
+	}
+}
+
+func TestMouseWheelScrollsLogs(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	for i := 0; i < 100; i++ {
+		m.logLines = append(m.logLines, fmt.Sprintf("log line %d", i))
+	}
+	m.showLogs = true
+	m.logView = components.ScrollView{Follow: true} // as opening the overlay does
+	if !strings.Contains(m.View(), "log line 99") {
+		t.Fatal("log overlay should open on the newest lines")
+	}
+
+	bottom := m.logView.Offset()
+	m.Update(tea.MouseMsg{Type: tea.MouseWheelUp})
+	if m.logView.Offset() != bottom-wheelLines || strings.Contains(m.View(), "log line 99") {
+		t.Errorf("wheel up: offset = %d, want %d with the newest lines scrolled away", m.logView.Offset(), bottom-wheelLines)
+	}
+	for i := 0; i < 100; i++ {
+		m.Update(tea.MouseMsg{Type: tea.MouseWheelUp})
+	}
+	if m.logView.Offset() != 0 || !strings.Contains(m.View(), "log line 0") {
+		t.Errorf("scrolling up should stop at the oldest line, offset = %d", m.logView.Offset())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if !m.logView.AtBottom() || !strings.Contains(m.View(), "log line 99") {
+		t.Error("End should jump back to the newest lines")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if got, want := m.logView.Offset(), bottom-m.logView.PageSize(); got != want {
+		t.Errorf("PgUp: offset = %d, want %d", got, want)
+	}
+}
+
+// shutdownModule records whether the shell shut it down
+type shutdownModule struct {
+	stubModule
+	shutdown bool
+}
+
+func (s *shutdownModule) Shutdown() { s.shutdown = true }
+
+func TestQuitShutsModulesDown(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	module := &shutdownModule{stubModule: stubModule{title: "Network"}}
+	m.modules[6] = module
+
+	if cmd := m.quit(); cmd == nil {
+		t.Fatal("quit should return tea.Quit")
+	}
+	if !module.shutdown {
+		t.Error("quit should shut down modules that implement Shutdowner")
+	}
+}
+
+// TestProgram drives the shell as a running program: keys typed reach it
+// through bubbletea's input loop, and q ends the program after shutting
+// modules down
+func TestProgram(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	module := &shutdownModule{stubModule: stubModule{title: "Cleanup", view: "Cleanup content"}}
+	m.modules[2] = module
+
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(golden.Sizes[0].Width, golden.Sizes[0].Height))
+	tm.Type("3")
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return strings.Contains(string(out), "Cleanup content")
+	}, teatest.WithDuration(5*time.Second))
+	tm.Type("q")
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(*Model)
+	if final.activeModule != 2 || !module.shutdown {
+		t.Errorf("active module = %d, shut down = %v; want Cleanup shown, then shut down", final.activeModule, module.shutdown)
+	}
+}
+
+func TestArrangeModules(t *testing.T) {
+	var modules []Module
+	for _, title := range []string{"Dashboard", "Quick Actions", "Docker", "Security", "Support"} {
+		modules = append(modules, stubModule{title: title})
+	}
+	titles := func(modules []Module) string {
+		var names []string
+		for _, module := range modules {
+			names = append(names, module.Title())
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		name           string
+		enabled, order []string
+		want           string
+	}{
+		{"defaults", nil, nil, "Dashboard,Quick Actions,Docker,Security,Support"},
+		{"hide", []string{"dashboard", "quickactions", "docker"}, nil, "Dashboard,Quick Actions,Docker"},
+		{"reorder", nil, []string{"Docker", "quick actions"}, "Docker,Quick Actions,Dashboard,Security,Support"},
+		{"order skips hidden", []string{"docker", "dashboard"}, []string{"support", "docker", "docker"}, "Docker,Dashboard"},
+		{"unknown names ignored", []string{"docker", "bogus"}, []string{"nope"}, "Docker"},
+		{"nothing known shows all", []string{"bogus"}, nil, "Dashboard,Quick Actions,Docker,Security,Support"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := titles(arrangeModules(modules, tt.enabled, tt.order)); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugOverlay(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	ctrlD := tea.KeyMsg{Type: tea.KeyCtrlD}
+
+	m.Update(ctrlD)
+	if m.showDebug {
+		t.Fatal("Ctrl+D should do nothing without --debug")
+	}
+
+	m.EnableDebug("127.0.0.1:6060")
+	m.Update(ctrlD)
+	view := m.View()
+	if !m.showDebug || !strings.Contains(view, "Goroutines") || !strings.Contains(view, "http://127.0.0.1:6060/debug/pprof/") {
+		t.Fatalf("Ctrl+D should open the runtime stats with the pprof address:\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showDebug {
+		t.Error("Esc should close the overlay")
+	}
+}
+
+// scheduledModule counts the refreshes the scheduler delivers to it
+type scheduledModule struct {
+	stubModule
+	refreshes int
+}
+
+type refreshMsg struct{}
+
+func (s *scheduledModule) Update(msg tea.Msg) (interface{}, tea.Cmd) {
+	if _, ok := msg.(refreshMsg); ok {
+		s.refreshes++
+	}
+	return s, nil
+}
+
+func (s *scheduledModule) Schedule() []scheduler.Task {
+	return []scheduler.Task{{Name: "refresh", Every: time.Minute, Run: func() tea.Cmd {
+		return func() tea.Msg { return refreshMsg{} }
+	}}}
+}
+
+func TestScheduledRefreshFollowsVisibility(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	system := &scheduledModule{stubModule: stubModule{title: "System"}}
+	m.modules[4] = system
+	m.schedule = scheduler.New()
+	m.schedule.Add("System", system.Schedule()...)
+
+	drain(m, m.runScheduled())
+	if system.refreshes != 0 {
+		t.Fatal("hidden module refreshed")
+	}
+
+	// Switching tabs runs the overdue refresh once, however often Init runs
+	for i := 0; i < 3; i++ {
+		m.activeModule = 4
+		drain(m, m.initModule(4))
+	}
+	if system.refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", system.refreshes)
+	}
+}
+
+// keeperModule counts its Inits and keeps its results for a minute
+type keeperModule struct {
+	stubModule
+	inits int
+}
+
+func (k *keeperModule) Init() tea.Cmd             { k.inits++; return nil }
+func (k *keeperModule) StaleAfter() time.Duration { return time.Minute }
+
+func TestKeeperInitsOnlyWhenStale(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	cleanup := &keeperModule{stubModule: stubModule{title: "Cleanup"}}
+	m.modules[2] = cleanup
+
+	// Tab away and back three times: the results are kept
+	for i := 0; i < 3; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	}
+	if cleanup.inits != 1 {
+		t.Fatalf("inits = %d, want 1 until the results are stale", cleanup.inits)
+	}
+
+	m.initAt["Cleanup"] = time.Now().Add(-2 * time.Minute)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	if cleanup.inits != 2 {
+		t.Errorf("inits = %d, want stale results reloaded", cleanup.inits)
+	}
+}
+
+// exportModule has data to export
+type exportModule struct {
+	stubModule
+}
+
+func (exportModule) Export() string { return "| Firewall | On |" }
+
+func TestExportWritesActiveModule(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.exportDir = t.TempDir()
+	m.modules[7] = exportModule{stubModule{title: "Security"}}
+
+	m.activeModule = 0
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if m.toasts.Latest() != "Dashboard has nothing to export" {
+		t.Errorf("toast = %q", m.toasts.Latest())
+	}
+
+	m.activeModule = 7
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	drain(m, cmd)
+	files, _ := filepath.Glob(filepath.Join(m.exportDir, "security-*.md"))
+	if len(files) != 1 || !strings.HasPrefix(m.toasts.Latest(), "Exported to "+files[0]) {
+		t.Fatalf("files = %v, toast = %q", files, m.toasts.Latest())
+	}
+	if data, _ := os.ReadFile(files[0]); !strings.Contains(string(data), "| Firewall | On |") {
+		t.Errorf("export = %q", data)
+	}
+}
+
+func TestConfirmDialogAnswersOpener(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	actions := &providerModule{stubModule: stubModule{title: "Quick Actions"}}
+	m.modules[1] = actions
+	ask := events.ModuleMsg{Module: "Quick Actions", Msg: dialog.Request{
+		Title:        "Flush DNS?",
+		Detail:       "Cached lookups are dropped.",
+		ConfirmLabel: "Flush",
+		Destructive:  true,
+		OnConfirm:    flushMsg{},
+	}}
+
+	m.Update(ask)
+	if m.dialog == nil || !m.activeModalOpen() {
+		t.Fatal("the request should open the dialog")
+	}
+	golden.RequireEqual(t, m.View())
+
+	// Enter starts on Cancel
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.dialog != nil || len(actions.received) != 0 {
+		t.Fatalf("dialog = %v received = %v, want it cancelled", m.dialog, actions.received)
+	}
+
+	m.Update(ask)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if len(actions.received) != 1 || actions.received[0] != (flushMsg{}) {
+		t.Fatalf("received = %v, want flushMsg", actions.received)
+	}
+}
+
+func TestResultToastJumpsToModule(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	actions := &providerModule{stubModule: stubModule{title: "Quick Actions"}}
+	m.modules[1] = actions
+	done := components.ToastMsg{Kind: components.ToastSuccess, Text: "Flush DNS completed", Result: flushMsg{}}
+
+	m.Update(events.ModuleMsg{Module: "Quick Actions", Msg: done})
+	if !strings.Contains(m.View(), "^O view") {
+		t.Fatal("a result from another tab should offer Ctrl+O")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.activeModule != 1 || !m.moduleFocused {
+		t.Fatalf("active module = %d focused = %v, want Quick Actions focused", m.activeModule, m.moduleFocused)
+	}
+	if last := actions.received[len(actions.received)-1]; last != (flushMsg{}) {
+		t.Errorf("last message = %T, want the result", last)
+	}
+
+	// From the module on screen there's nothing to jump to
+	m.Update(events.ModuleMsg{Module: "Quick Actions", Msg: done})
+	if strings.Contains(m.View(), "^O view") {
+		t.Error("the active module's own result shouldn't offer Ctrl+O")
+	}
+}
+
+func TestTour(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.activeModule = 2
+	right := tea.KeyMsg{Type: tea.KeyRight}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if m.tour == nil || !strings.Contains(m.View(), "Welcome to Dev Cockpit") {
+		t.Fatalf("T should open the tour on its welcome card:\n%s", m.View())
+	}
+
+	m.Update(right)
+	m.Update(right)
+	view := m.View()
+	if m.activeModule != 1 || !strings.Contains(view, "Fix all common issues") || !strings.Contains(view, "Quick Actions content") {
+		t.Fatalf("second stop should show Quick Actions behind its card, module %d:\n%s", m.activeModule, view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.activeModule != 0 {
+		t.Errorf("← should go back to the Dashboard, got module %d", m.activeModule)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.tour != nil || m.activeModule != 2 {
+		t.Errorf("Esc should end the tour back on the module it started from, got module %d", m.activeModule)
+	}
+
+	// The last card is the global keys, and Enter there finishes
+	m.startTour()
+	for range m.modules {
+		m.Update(right)
+	}
+	m.Update(right)
+	if !strings.Contains(m.View(), "Everywhere") {
+		t.Fatalf("last card should list the global keys:\n%s", m.View())
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.tour != nil {
+		t.Error("Enter on the last card should finish the tour")
+	}
+}
+
+func TestTourOnFirstRun(t *testing.T) {
+	m := newSnapshotModel(golden.Sizes[0])
+	m.statePath = filepath.Join(t.TempDir(), "state.json")
+	if !m.firstRun() {
+		t.Fatal("no state file should be a first run")
+	}
+	m.saveState()
+	if m.firstRun() {
+		t.Error("a saved session should not be a first run")
+	}
+}
+
+func TestSafeMode(t *testing.T) {
+	m := NewSafe(nil, "1.0.0")
+	var titles []string
+	for _, module := range m.modules {
+		titles = append(titles, module.Title())
+	}
+	if got := strings.Join(titles, ", "); got != "Quick Actions, Settings, Support" {
+		t.Errorf("modules = %s", got)
+	}
+	if m.schedule != nil || m.runScheduled() != nil {
+		t.Error("safe mode should schedule no refreshes")
+	}
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if !strings.Contains(m.renderFooter(), "[SAFE MODE]") {
+		t.Errorf("footer doesn't show safe mode:\n%s", m.renderFooter())
+	}
+}
+
+func TestTerminalTitleAndNotifications(t *testing.T) {
+	var out strings.Builder
+	terminalOut = &out
+	t.Cleanup(func() { terminalOut = os.Stdout })
+
+	m := newSnapshotModel(golden.Sizes[0])
+	m.windowTitle, m.notifyAlerts = true, true
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	drain(m, cmd)
+	if out.String() != "\x1b]2;DevCockpit — Quick Actions\a" {
+		t.Errorf("title = %q", out.String())
+	}
+
+	out.Reset()
+	_, cmd = m.Update(events.ModuleMsg{Module: "Dashboard", Msg: events.Alerting{
+		Firing: []string{"Disk 92%"},
+		Fired:  []string{"disk-full: disk at 92% (above 90%)"},
+	}})
+	drain(m, cmd)
+	for _, want := range []string{"\x1b]9;Dev Cockpit: disk-full: disk at 92% (above 90%)\a", "\x1b]2;DevCockpit — Disk 92%! — Quick Actions\a"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("wrote %q, want %q in it", out.String(), want)
+		}
+	}
+
+	// Nothing is written while the title stays the same
+	out.Reset()
+	_, cmd = m.Update(events.ModuleMsg{Module: "Dashboard", Msg: events.Alerting{Firing: []string{"Disk 92%"}}})
+	drain(m, cmd)
+	if out.Len() != 0 {
+		t.Errorf("wrote %q", out.String())
+	}
+	m.quit()
+	if out.String() != "\x1b]2;\a" {
+		t.Errorf("quitting wrote %q, want the title reset", out.String())
+	}
+}
+
+// commandModule lists the commands its selection runs for Y
+type commandModule struct {
+	providerModule
+}
+
+func (c *commandModule) CommandLines() []string {
+	return []string{"sudo dscacheutil -flushcache", "sudo killall -HUP mDNSResponder"}
+}
+
+func TestYankCopiesCommandLines(t *testing.T) {
+	fake := runner.NewFake().Set("pbcopy", "", nil)
+	clipboard.Runner = fake
+	clipboard.Clear()
+	t.Cleanup(func() {
+		clipboard.Runner = runner.Default
+		clipboard.Clear()
+	})
+
+	m := newSnapshotModel(golden.Sizes[0])
+	m.modules[0] = &commandModule{providerModule: providerModule{stubModule: stubModule{title: "Quick Actions"}}}
+	m.activeModule = 0
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	drain(m, cmd)
+	history := clipboard.History()
+	if len(history) != 1 || history[0].Text != "sudo dscacheutil -flushcache\nsudo killall -HUP mDNSResponder" {
+		t.Fatalf("history = %+v, want the command lines", history)
+	}
+
+	// Modules with no commands behind the selection say so
+	m.activeModule = 1
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	if len(clipboard.History()) != 1 {
+		t.Error("Y copied from a module without commands")
+	}
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/debugstats"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EnableDebug turns on the hidden runtime stats overlay (Ctrl+D). The
+// pprof address, if any, is shown there.
+func (m *Model) EnableDebug(pprofAddr string) {
+	m.debug = true
+	m.pprofAddr = pprofAddr
+	m.debugBaseline = debugstats.Read()
+}
+
+// toggleDebug opens or closes the runtime stats overlay
+func (m *Model) toggleDebug() {
+	m.showDebug = !m.showDebug
+	if m.showDebug {
+		m.showHelp = false
+		m.showLogs = false
+		m.debugStats = debugstats.Read()
+	}
+}
+
+// updateDebugKey handles keys while the overlay is open
+func (m *Model) updateDebugKey(key string) {
+	switch key {
+	case "esc", "q", "ctrl+d":
+		m.showDebug = false
+	case "g":
+		runtime.GC()
+		m.debugStats = debugstats.Read()
+	}
+}
+
+// renderDebugOverlay shows the runtime stats next to the ones taken at
+// launch, so steady growth stands out
+func (m *Model) renderDebugOverlay() string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	labelStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+	valueStyle := lipgloss.NewStyle().Foreground(components.ColorBright)
+	growthStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	now, base := m.debugStats, m.debugBaseline
+	row := func(label, value, growth string) string {
+		line := labelStyle.Render(fmt.Sprintf("%-14s", label)) + valueStyle.Render(value)
+		if growth != "" {
+			line += "  " + growthStyle.Render(growth)
+		}
+		return line
+	}
+	since := func(d int64, format func(int64) string) string {
+		if d <= 0 {
+			return ""
+		}
+		return "+" + format(d) + " since launch"
+	}
+	count := func(n int64) string { return fmt.Sprint(n) }
+	size := func(n int64) string { return formatBytes(uint64(n)) }
+
+	lastGC := "never"
+	if !now.LastGC.IsZero() {
+		lastGC = fmt.Sprintf("%s ago (paused %s)", time.Since(now.LastGC).Round(time.Second), now.LastPause.Round(time.Microsecond))
+	}
+
+	lines := []string{
+		headerStyle.Render("🛠 Runtime Stats"),
+		hintStyle.Render(fmt.Sprintf("Up %s • %s • %s", time.Since(base.Taken).Round(time.Second), runtime.Version(), runtime.GOARCH)),
+		"",
+		row("Goroutines", fmt.Sprint(now.Goroutines), since(int64(now.Goroutines-base.Goroutines), count)),
+		row("Heap in use", formatBytes(now.HeapInuse), since(int64(now.HeapInuse)-int64(base.HeapInuse), size)),
+		row("Heap live", formatBytes(now.HeapAlloc), ""),
+		row("Heap objects", fmt.Sprint(now.HeapObjects), since(int64(now.HeapObjects)-int64(base.HeapObjects), count)),
+		row("From the OS", formatBytes(now.Sys), ""),
+		row("GC cycles", fmt.Sprint(now.NumGC), ""),
+		row("Last GC", lastGC, ""),
+		row("GC pauses", now.PauseTotal.Round(time.Microsecond).String()+" total", ""),
+		"",
+	}
+	if m.pprofAddr != "" {
+		lines = append(lines,
+			row("pprof", "http://"+m.pprofAddr+"/debug/pprof/", ""),
+			hintStyle.Render("go tool pprof http://"+m.pprofAddr+"/debug/pprof/goroutine"),
+			"")
+	} else {
+		lines = append(lines, hintStyle.Render("Start with --pprof to serve profiles on "+debugstats.DefaultAddr), "")
+	}
+	lines = append(lines, hintStyle.Render("Refreshes every second • [G] Run GC • Ctrl+D/Esc close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(components.ColorBorder).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// formatBytes formats bytes into human-readable format
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// wheelLines is how far one wheel notch scrolls the log overlay
+const wheelLines = 3
+
+// handleMouse switches modules on tab clicks, focuses a module and passes
+// it the click when its content is clicked, and turns the wheel into
+// navigation
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if m.dialog != nil || m.palette != nil || m.tour != nil || m.showHelp || m.showDebug || m.commandOpen || len(m.modules) == 0 {
+		return nil
+	}
+
+	if m.showLogs {
+		switch msg.Type {
+		case tea.MouseWheelUp:
+			m.logView.ScrollBy(-wheelLines)
+		case tea.MouseWheelDown:
+			m.logView.ScrollBy(wheelLines)
+		}
+		return nil
+	}
+
+	// The wheel moves lists, not what a module's dialog shows over them
+	switch msg.Type {
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		if m.activeModalOpen() {
+			return nil
+		}
+		if msg.Type == tea.MouseWheelUp {
+			return m.updateModule(m.activeModule, events.NavUp)
+		}
+		return m.updateModule(m.activeModule, events.NavDown)
+	case tea.MouseLeft:
+		if index, ok := m.tabAt(msg.X, msg.Y); ok {
+			return m.selectModule(index)
+		}
+		return m.clickContent(msg.X, msg.Y)
+	}
+	return nil
+}
+
+// tabAt returns the module whose tab is at x, y. Tabs sit on the first
+// line inside the tab bar's padding.
+func (m *Model) tabAt(x, y int) (int, bool) {
+	if y != 1 || x < 2 {
+		return 0, false
+	}
+	index := (x - 2) / m.tabWidth()
+	return index, index < len(m.modules)
+}
+
+// selectModule makes index the active module, leaving the focused one.
+// Clicking the active tab enters the module.
+func (m *Model) selectModule(index int) tea.Cmd {
+	if index == m.activeModule {
+		return m.focusModule()
+	}
+
+	var cmds []tea.Cmd
+	if m.moduleFocused {
+		if m.activeModalOpen() {
+			return nil
+		}
+		m.moduleFocused = false
+		cmds = append(cmds, m.updateModule(m.activeModule, events.Blur{}))
+	}
+	m.activeModule = index
+	cmds = append(cmds, m.initModule(index))
+	return tea.Batch(cmds...)
+}
+
+// focusModule enters the active module, as Enter does
+func (m *Model) focusModule() tea.Cmd {
+	if m.moduleFocused {
+		return nil
+	}
+	m.moduleFocused = true
+	return m.updateModule(m.activeModule, events.Focus{})
+}
+
+// clickContent focuses the active module and passes it the click, in its
+// own coordinates. Clicks in split view only focus, since the panes share
+// the content area.
+func (m *Model) clickContent(x, y int) tea.Cmd {
+	// Where the module's View starts: below the tabs, the hint shown while
+	// unfocused and the content padding
+	top := lipgloss.Height(m.renderTabs())
+	if !m.moduleFocused {
+		layout := components.NewLayout(m.width, m.height).WithHint()
+		top += lipgloss.Height(m.renderHint(layout.ContentWidth)) + 1
+	}
+	bottom := m.height - lipgloss.Height(m.renderFooter()) - m.toasts.Len()
+	if y < top || y >= bottom {
+		return nil
+	}
+
+	cmds := []tea.Cmd{m.focusModule()}
+	if _, _, split := m.splitPanes(); !split {
+		cmds = append(cmds, m.updateModule(m.activeModule, events.Click{X: x - 2, Y: y - top}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// jumpToModule makes index the active module for the number keys. The
+// active module and positions past the last tab are ignored.
+func (m *Model) jumpToModule(index int) tea.Cmd {
+	if index < 0 || index >= len(m.modules) || index == m.activeModule {
+		return nil
+	}
+	return m.selectModule(index)
+}
+
+// moduleNumber reads the module index from a number key such as "3" or,
+// with prefix "alt+", "alt+3"
+func moduleNumber(key, prefix string) (int, bool) {
+	digit, ok := strings.CutPrefix(key, prefix)
+	if !ok || len(digit) != 1 || digit[0] < '1' || digit[0] > '9' {
+		return 0, false
+	}
+	return int(digit[0] - '1'), true
+}
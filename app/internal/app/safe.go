@@ -0,0 +1,30 @@
+package app
+
+import (
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/modules/quickactions"
+	"github.com/caioricciuti/dev-cockpit/internal/modules/settings"
+	"github.com/caioricciuti/dev-cockpit/internal/modules/support"
+)
+
+// NewSafe creates the application in safe mode, for telling whether a
+// module is behind a crash or a slowdown: only the core modules are
+// loaded, nothing refreshes or polls on its own, and the saved session is
+// neither restored nor overwritten
+func NewSafe(cfg *config.Config, version string) *Model {
+	return newModel(cfg, version, true)
+}
+
+// initializeSafeModules loads the modules that do nothing in the
+// background: Quick Actions, Settings (to turn off the module at fault)
+// and Support. modules.enabled and modules.order don't apply, and with no
+// scheduler, modules with periodic refreshes would sit empty anyway.
+func (m *Model) initializeSafeModules() {
+	m.modules = []Module{
+		construct(func() Module { return quickactions.New(m.config) }),
+		construct(func() Module { return settings.New(m.config) }),
+		construct(func() Module { return support.New() }),
+	}
+	logger.Info("Safe mode: loaded %d core modules, scheduled refreshes off", len(m.modules))
+}
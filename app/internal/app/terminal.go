@@ -0,0 +1,82 @@
+package app
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/terminal"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// terminalOut takes the title and notification sequences; the program
+// renders to stdout as well
+var terminalOut io.Writer = os.Stdout
+
+// terminalNotifies reads ui.terminal_notifications: "always", "never", or
+// "auto" for terminals known to show them
+func terminalNotifies(setting string, getenv func(string) string) bool {
+	switch setting {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return terminal.Notifies(getenv)
+}
+
+// terminalTitle names the firing alerts first, as tab titles are cut at
+// the end, e.g. "DevCockpit — Disk 92%! — Dashboard"
+func (m *Model) terminalTitle() string {
+	parts := []string{"DevCockpit"}
+	if len(m.alerting) > 0 {
+		parts = append(parts, strings.Join(m.alerting, ", ")+"!")
+	}
+	if m.activeModule < len(m.modules) {
+		parts = append(parts, m.modules[m.activeModule].Title())
+	}
+	return strings.Join(parts, " — ")
+}
+
+// syncTitle sets the terminal title when it's out of date
+func (m *Model) syncTitle() tea.Cmd {
+	if !m.windowTitle || m.quitting {
+		return nil
+	}
+	title := m.terminalTitle()
+	if title == m.title {
+		return nil
+	}
+	m.title = title
+	return writeTerminal(terminal.Title(title))
+}
+
+// resetTitle hands the title back to the terminal on the way out
+func (m *Model) resetTitle() {
+	if m.title != "" {
+		io.WriteString(terminalOut, terminal.Title(""))
+		m.title = ""
+	}
+}
+
+// updateAlerting notes the alerts firing for the title and posts a
+// terminal notification for each that just fired
+func (m *Model) updateAlerting(msg events.Alerting) tea.Cmd {
+	m.alerting = msg.Firing
+	if !m.notifyAlerts || len(msg.Fired) == 0 {
+		return nil
+	}
+	var sequences strings.Builder
+	for _, text := range msg.Fired {
+		sequences.WriteString(terminal.Notification("Dev Cockpit: " + text))
+	}
+	return writeTerminal(sequences.String())
+}
+
+func writeTerminal(sequence string) tea.Cmd {
+	return func() tea.Msg {
+		io.WriteString(terminalOut, sequence)
+		return nil
+	}
+}
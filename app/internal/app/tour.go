@@ -0,0 +1,318 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tourStep is one card of the guided tour. Steps with a module show it
+// behind the card.
+type tourStep struct {
+	module string
+	title  string
+	body   string
+	keys   []help.Binding
+	try    string // a sample task to do once the tour is over
+}
+
+// tour walks through the modules one card at a time
+type tour struct {
+	steps []tourStep
+	index int
+	from  int // module active when the tour started, shown again at the end
+}
+
+// tourStops are the highlights of each module. A module without one, or
+// added later, gets its description and first keys from its help.
+var tourStops = map[string]tourStep{
+	"Dashboard": {
+		body: "Live CPU, GPU, memory, disk and network, with the heaviest processes.",
+		keys: []help.Binding{
+			{Key: "H", Desc: "Metrics recorded over the last hour to 30 days"},
+			{Key: "W", Desc: "Graph ten minutes instead of one"},
+			{Key: "M", Desc: "Heaviest processes by memory"},
+			{Key: "X", Desc: "Quit the selected process"},
+		},
+		try: "Press H to see what the CPU did over the last hour.",
+	},
+	"Quick Actions": {
+		body: "One-step fixes for the usual macOS annoyances.",
+		keys: []help.Binding{
+			{Key: "Enter", Desc: "Run the selected action"},
+			{Key: "F", Desc: "Fix all common issues"},
+			{Key: "/", Desc: "Filter actions"},
+		},
+		try: "Run Flush DNS when a site still resolves to its old address.",
+	},
+	"Cleanup": {
+		body: "Measures caches, logs and build leftovers and clears the ones you pick.",
+		keys: []help.Binding{
+			{Key: "Space", Desc: "Pick a target"},
+			{Key: "A / N", Desc: "Pick all / none"},
+			{Key: "Enter", Desc: "Clean the picked targets"},
+			{Key: "O", Desc: "Open a target in Finder first"},
+		},
+		try: "Pick Go Build Cache and Xcode Derived Data, then press Enter.",
+	},
+	"Packages": {
+		body: "Homebrew, npm and the other package managers on this Mac.",
+		keys: []help.Binding{
+			{Key: "L", Desc: "List installed packages"},
+			{Key: "O", Desc: "Show outdated packages"},
+			{Key: "U", Desc: "Update the package manager"},
+			{Key: "C", Desc: "Clean its cache"},
+		},
+		try: "Select Homebrew and press O to see what's outdated.",
+	},
+	"System": {
+		body: "Hardware, performance, maintenance, displays, audio and more, one view per number key.",
+		keys: []help.Binding{
+			{Key: "1-9", Desc: "Switch views"},
+			{Key: "D", Desc: "Disk Utility First Aid (Maintenance)"},
+			{Key: "C", Desc: "Battery charge limit (Maintenance)"},
+		},
+		try: "Press 4 for Maintenance and D to check the disk.",
+	},
+	"Fleet": {
+		body: "The other Macs under fleet.hosts, checked over SSH.",
+		keys: []help.Binding{
+			{Key: "Enter", Desc: "Details of the selected host"},
+			{Key: "R", Desc: "Check all hosts now"},
+			{Key: "y", Desc: "Copy the ssh command"},
+		},
+		try: "Press R to check every host now.",
+	},
+	"Docker": {
+		body: "Containers from the local Docker daemon.",
+		keys: []help.Binding{
+			{Key: "S", Desc: "Start or stop the selected container"},
+			{Key: "L", Desc: "Its recent logs"},
+			{Key: "/", Desc: "Filter containers"},
+		},
+		try: "Select a container and press L to read its logs.",
+	},
+	"Network": {
+		body: "Interfaces, listening ports, diagnostics, connection quality and Wi-Fi.",
+		keys: []help.Binding{
+			{Key: "1-7", Desc: "Overview, Ports, Diagnostics, Quality, Tools, Wi-Fi, Mesh"},
+			{Key: "/", Desc: "Filter listening ports (Ports)"},
+			{Key: "P / T / D", Desc: "Ping, traceroute, DNS lookup (Diagnostics)"},
+		},
+		try: "Press 2 and type 3000 to find what's holding that port.",
+	},
+	"Security": {
+		body: "Firewall, FileVault and SIP, quarantined downloads and code signatures.",
+		keys: []help.Binding{
+			{Key: "U", Desc: "Remove the quarantine from a download you trust"},
+			{Key: "C", Desc: "Check the selected item's signature"},
+			{Key: "P", Desc: "Check any app or binary by path"},
+		},
+		try: "Press P and type /Applications/Safari.app to see a notarized app.",
+	},
+	"Settings": {
+		body: "What Dev Cockpit stores, how long it keeps it, and every setting changed this session.",
+		keys: []help.Binding{
+			{Key: "2", Desc: "Changes made this session"},
+			{Key: "U", Desc: "Revert the selected change (Changes)"},
+			{Key: "B / O", Desc: "Back up / restore ~/.devcockpit"},
+		},
+		try: "Press 2 to review, and undo, what you changed today.",
+	},
+	"Support": {
+		body: "Where to report issues and support the project.",
+		keys: []help.Binding{
+			{Key: "Enter", Desc: "Open the selected link"},
+		},
+	},
+}
+
+// firstRun reports whether this is the first launch, before any session
+// has saved its state
+func (m *Model) firstRun() bool {
+	if m.statePath == "" {
+		return false
+	}
+	_, err := os.Stat(m.statePath)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// tourSteps are the welcome card, a card per module in tab order and the
+// keys that work everywhere
+func (m *Model) tourSteps() []tourStep {
+	steps := []tourStep{{
+		title: "Welcome to Dev Cockpit",
+		body:  "This tour visits each module with the keys worth knowing. Modules are the tabs along the top; a module takes keys once you focus it.",
+		keys: []help.Binding{
+			{Key: "Tab / 1-9", Desc: "Switch modules"},
+			{Key: "Enter", Desc: "Focus the module"},
+			{Key: "Esc", Desc: "Leave it again"},
+			{Key: "?", Desc: "Every key of the focused module"},
+		},
+	}}
+	for _, module := range m.modules {
+		step, ok := tourStops[module.Title()]
+		if !ok {
+			step = helpStep(module)
+		}
+		step.module = module.Title()
+		step.title = module.Title()
+		steps = append(steps, step)
+	}
+	steps = append(steps, tourStep{
+		title: "Everywhere",
+		body:  "These work from any module. Press T or run devcockpit tour to take this tour again.",
+		keys: []help.Binding{
+			{Key: "Ctrl+K", Desc: "Command palette: every action by name"},
+			{Key: "/", Desc: "Search packages, containers, ports..."},
+			{Key: "S", Desc: "Pin the dashboard beside any module"},
+			{Key: "y / e", Desc: "Copy the selection / export the module"},
+			{Key: "l", Desc: "Logs"},
+		},
+		try: "Press Ctrl+K and type dns to find an action from anywhere.",
+	})
+	return steps
+}
+
+// helpStep builds a card from a module's help: its description and the
+// first few keys
+func helpStep(module Module) tourStep {
+	provider, ok := module.(help.Provider)
+	if !ok {
+		return tourStep{}
+	}
+	h := provider.Help()
+	step := tourStep{body: h.Description}
+	for _, section := range h.Sections {
+		for _, binding := range section.Bindings {
+			if len(step.keys) < 4 {
+				step.keys = append(step.keys, binding)
+			}
+		}
+	}
+	return step
+}
+
+// startTour opens the tour on its welcome card
+func (m *Model) startTour() tea.Cmd {
+	m.showHelp = false
+	m.showLogs = false
+	m.showDebug = false
+	m.tour = &tour{steps: m.tourSteps(), from: m.activeModule}
+	logger.Info("Tour started")
+	return m.tourGoTo(0)
+}
+
+// StartTour opens the tour at launch, for devcockpit tour. The welcome
+// card shows no module, so there is nothing to start yet.
+func (m *Model) StartTour() {
+	m.startTour()
+}
+
+// tourGoTo shows step index, switching to its module
+func (m *Model) tourGoTo(index int) tea.Cmd {
+	m.tour.index = index
+	module := m.moduleIndex(m.tour.steps[index].module)
+	if module < 0 || module == m.activeModule {
+		return nil
+	}
+	return m.showModule(module)
+}
+
+// showModule makes index the active module without focusing it
+func (m *Model) showModule(index int) tea.Cmd {
+	var cmds []tea.Cmd
+	if m.moduleFocused {
+		m.moduleFocused = false
+		cmds = append(cmds, m.updateModule(m.activeModule, events.Blur{}))
+	}
+	m.activeModule = index
+	cmds = append(cmds, m.initModule(index))
+	return tea.Batch(cmds...)
+}
+
+// endTour closes the tour, back on the module it started from
+func (m *Model) endTour(finished bool) tea.Cmd {
+	from := m.tour.from
+	m.tour = nil
+	if finished {
+		m.toast(components.ToastSuccess, "Tour finished • T takes it again")
+	} else {
+		m.toast(components.ToastInfo, "Tour closed • T takes it again")
+	}
+	if from < len(m.modules) && from != m.activeModule {
+		return m.showModule(from)
+	}
+	return nil
+}
+
+// updateTourKey handles keys while the tour is open; it takes every key
+func (m *Model) updateTourKey(key string) tea.Cmd {
+	t := m.tour
+	switch key {
+	case "right", "enter", "n", "l", " ":
+		if t.index == len(t.steps)-1 {
+			return m.endTour(true)
+		}
+		return m.tourGoTo(t.index + 1)
+	case "left", "backspace", "p", "h":
+		if t.index > 0 {
+			return m.tourGoTo(t.index - 1)
+		}
+	case "esc", "q":
+		return m.endTour(false)
+	}
+	return nil
+}
+
+// renderTour is the card for the current step, kept short so the module
+// it describes shows above it
+func (m *Model) renderTour(width int) string {
+	t := m.tour
+	step := t.steps[t.index]
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorBackground).Background(components.ColorWarning).Padding(0, 1)
+	textStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	inner := max(width-8, 20)
+
+	progress := fmt.Sprintf("  %d of %d", t.index+1, len(t.steps))
+	if step.module != "" && len(step.keys) > 0 {
+		progress += " • keys work once the module is focused with Enter"
+	}
+	lines := []string{titleStyle.Render("🧭 "+step.title) + hintStyle.Render(progress)}
+	if step.body != "" {
+		lines = append(lines, textStyle.Width(inner).Render(step.body))
+	}
+	keyWidth := 0
+	for _, binding := range step.keys {
+		keyWidth = max(keyWidth, lipgloss.Width(binding.Key))
+	}
+	for _, binding := range step.keys {
+		pad := strings.Repeat(" ", keyWidth-lipgloss.Width(binding.Key))
+		lines = append(lines, keyStyle.Render(binding.Key)+pad+"  "+textStyle.Render(components.TruncateString(binding.Desc, max(inner-keyWidth-4, 10))))
+	}
+	if step.try != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(components.ColorSuccess).Width(inner).Render("Try it: "+step.try))
+	}
+	next := "→/Enter Next"
+	if t.index == len(t.steps)-1 {
+		next = "Enter Finish"
+	}
+	lines = append(lines, hintStyle.Render(next+" • ← Back • Esc End tour"))
+
+	return lipgloss.NewStyle().
+		Width(width-2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(components.ColorPrimary).
+		Padding(0, 2).
+		Render(strings.Join(lines, "\n"))
+}
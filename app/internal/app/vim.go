@@ -0,0 +1,170 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
+	"github.com/caioricciuti/dev-cockpit/internal/updater"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// arrowNav keys are never text, so they always navigate
+var arrowNav = map[string]events.Nav{
+	"up":   events.NavUp,
+	"down": events.NavDown,
+	"home": events.NavTop,
+	"end":  events.NavBottom,
+}
+
+// letterNav keys navigate lists, except while a dialog or filter is
+// taking input
+var letterNav = map[string]events.Nav{
+	"k": events.NavUp,
+	"j": events.NavDown,
+}
+
+// vimNav keys also navigate in vim mode
+var vimNav = map[string]events.Nav{
+	"g": events.NavTop,
+	"G": events.NavBottom,
+}
+
+// navFor translates key into a navigation message for the focused module.
+// In vim mode the caller swallows the first "g" of "gg".
+func navFor(key string, modalOpen, vimMode bool) (events.Nav, bool) {
+	if nav, ok := arrowNav[key]; ok {
+		return nav, true
+	}
+	if modalOpen {
+		return 0, false
+	}
+	if nav, ok := letterNav[key]; ok {
+		return nav, true
+	}
+	if !vimMode {
+		return 0, false
+	}
+	nav, ok := vimNav[key]
+	return nav, ok
+}
+
+// vimModuleKey maps vim motions on the module switcher to the keys the
+// switcher already handles. handled is true when the key was swallowed
+// waiting for the second "g".
+func (m *Model) vimModuleKey(key string, gPending bool) (mapped string, handled bool) {
+	switch key {
+	case "h":
+		return "left", false
+	case "l":
+		return "right", false
+	case "G":
+		return "end", false
+	case "g":
+		if gPending {
+			return "home", false
+		}
+		m.pendingG = true
+		return key, true
+	}
+	return key, false
+}
+
+// updateCommandLine edits the : command line, running it on Enter
+func (m *Model) updateCommandLine(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.commandOpen = false
+	case tea.KeyEnter:
+		m.commandOpen = false
+		return m.execCommand(m.commandLine)
+	case tea.KeyBackspace:
+		if m.commandLine == "" {
+			m.commandOpen = false
+			break
+		}
+		runes := []rune(m.commandLine)
+		m.commandLine = string(runes[:len(runes)-1])
+	case tea.KeySpace:
+		m.commandLine += " "
+	case tea.KeyRunes:
+		m.commandLine += string(msg.Runes)
+	}
+	return nil
+}
+
+// execCommand runs a : command: quit, a module name or one of the shell's
+// toggles
+func (m *Model) execCommand(line string) tea.Cmd {
+	name := strings.ToLower(strings.TrimSpace(line))
+	switch name {
+	case "":
+		return nil
+	case "q", "q!", "qa", "quit":
+		return m.quit()
+	case "update":
+		m.toast(components.ToastInfo, "Checking for updates…")
+		return checkForUpdate(m.version)
+	case "logs":
+		m.showLogs = true
+		m.logView = components.ScrollView{Follow: true}
+		m.refreshLogs()
+		return nil
+	case "help":
+		m.showHelp = true
+		return nil
+	case "split":
+		return m.toggleSplit()
+	case "tour":
+		return m.startTour()
+	}
+
+	if index := m.matchModule(name); index >= 0 {
+		return m.runCommand(palette.Entry{Module: m.modules[index].Title()})
+	}
+	m.toast(components.ToastError, "Not a command: "+line)
+	return nil
+}
+
+// matchModule finds the module named by a command, ignoring case and
+// spaces: an exact name first, then a unique prefix (":quick", ":net")
+func (m *Model) matchModule(name string) int {
+	name = strings.ReplaceAll(name, " ", "")
+	match := -1
+	for i, module := range m.modules {
+		title := strings.ToLower(strings.ReplaceAll(module.Title(), " ", ""))
+		if title == name {
+			return i
+		}
+		if strings.HasPrefix(title, name) {
+			if match >= 0 {
+				return -1
+			}
+			match = i
+		}
+	}
+	return match
+}
+
+// latestRelease is replaced in tests
+var latestRelease = updater.FetchLatestRelease
+
+// checkForUpdate reports in a toast whether a newer release exists
+func checkForUpdate(version string) tea.Cmd {
+	return func() tea.Msg {
+		release, err := latestRelease()
+		if err != nil {
+			return components.ToastMsg{Kind: components.ToastError, Text: fmt.Sprintf("Update check failed: %v", err)}
+		}
+		newer, err := updater.HasUpdate(version, release.TagName)
+		if err != nil {
+			return components.ToastMsg{Kind: components.ToastError, Text: fmt.Sprintf("Update check failed: %v", err)}
+		}
+		if !newer {
+			return components.ToastMsg{Kind: components.ToastSuccess, Text: fmt.Sprintf("v%s is the latest version", version)}
+		}
+		return components.ToastMsg{Kind: components.ToastInfo, Text: fmt.Sprintf("%s available • run devcockpit update", release.TagName)}
+	}
+}
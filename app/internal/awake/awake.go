@@ -0,0 +1,89 @@
+// Package awake keeps the Mac from sleeping through long operations, like
+// a large cleanup or an update download, and says what could still cut
+// one short: a low battery, or closing the lid while on battery, which
+// sleeps the Mac however it's held awake.
+package awake
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/caioricciuti/dev-cockpit/internal/battery"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// lowBattery is the charge below which a long operation is warned about
+const lowBattery = 20
+
+// Hold keeps the Mac awake until released
+type Hold struct {
+	cmd  *exec.Cmd
+	once sync.Once
+}
+
+// Release lets the Mac sleep again. It's safe to call on a nil Hold and
+// more than once.
+func (h *Hold) Release() {
+	if h == nil {
+		return
+	}
+	h.once.Do(func() {
+		_ = h.cmd.Process.Kill()
+		_ = h.cmd.Wait()
+	})
+}
+
+// Start holds the Mac awake with caffeinate, which prevents idle sleep
+// until released or Dev Cockpit exits, or returns nil when it can't
+func Start(r runner.Runner) *Hold {
+	path, err := r.LookPath("caffeinate")
+	if err != nil {
+		return nil
+	}
+	cmd := exec.Command(path, "-i", "-w", strconv.Itoa(os.Getpid()))
+	if err := cmd.Start(); err != nil {
+		logger.Warn("Failed to start caffeinate: %v", err)
+		return nil
+	}
+	return &Hold{cmd: cmd}
+}
+
+// Warning is what could cut the operation named what, e.g. "the
+// cleanup", short, or "" when nothing is likely to
+func Warning(r runner.Runner, what string) string {
+	if status, err := battery.Read(r); err == nil && !status.External {
+		if status.Percent < lowBattery {
+			return fmt.Sprintf("Battery at %d%%: plug in so %s isn't cut short", status.Percent, what)
+		}
+		return fmt.Sprintf("On battery: closing the lid sleeps the Mac and stops %s", what)
+	}
+	// Without caffeinate to hold it awake, the Mac sleeps when idle
+	if _, err := r.LookPath("caffeinate"); err != nil {
+		if minutes := sleepMinutes(r); minutes > 0 {
+			return fmt.Sprintf("The Mac sleeps after %d min idle, which stops %s", minutes, what)
+		}
+	}
+	return ""
+}
+
+// sleepMinutes is the idle time before the Mac sleeps, from the power
+// settings in use, or 0 when it never does
+func sleepMinutes(r runner.Runner) int {
+	output, err := r.Output(exec.Command("pmset", "-g"))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "sleep" {
+			minutes, _ := strconv.Atoi(fields[1])
+			return minutes
+		}
+	}
+	return 0
+}
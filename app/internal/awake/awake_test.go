@@ -0,0 +1,56 @@
+package awake
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+const ioreg = "ioreg -rw0 -n AppleSmartBattery"
+
+// smartBattery is the ioreg output of a battery at percent
+func smartBattery(percent string, external string) string {
+	return `"CurrentCapacity" = ` + percent + "\n" +
+		`"MaxCapacity" = 100` + "\n" +
+		`"ExternalConnected" = ` + external + "\n"
+}
+
+func TestWarning(t *testing.T) {
+	noBattery := errors.New("exit status 1")
+	tests := []struct {
+		name    string
+		battery string
+		err     error
+		awake   bool
+		want    string
+	}{
+		{"low battery", smartBattery("12", "No"), nil, true, "Battery at 12%: plug in so the cleanup isn't cut short"},
+		{"on battery", smartBattery("87", "No"), nil, true, "On battery: closing the lid sleeps the Mac and stops the cleanup"},
+		{"plugged in", smartBattery("12", "Yes"), nil, true, ""},
+		{"desktop held awake", "", noBattery, true, ""},
+		{"desktop without caffeinate", "", noBattery, false, "The Mac sleeps after 10 min idle, which stops the cleanup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := runner.NewFake().Set(ioreg, tt.battery, tt.err)
+			if !tt.awake {
+				fake.Missing("caffeinate")
+			}
+			if err := fake.SetFixture("pmset -g", "testdata/pmset.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if got := Warning(fake, "the cleanup"); got != tt.want {
+				t.Errorf("warning = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartWithoutCaffeinate(t *testing.T) {
+	hold := Start(runner.NewFake().Missing("caffeinate"))
+	if hold != nil {
+		t.Error("hold without caffeinate")
+	}
+	hold.Release() // a nil hold is fine to release
+}
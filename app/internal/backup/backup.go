@@ -0,0 +1,197 @@
+// Package backup archives the Dev Cockpit directory (~/.devcockpit) with
+// its config, themes, saved state and stored data, and restores it, to
+// move to a new Mac or to try settings with a way back.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// configFile must be in an archive for it to be taken as a backup
+const configFile = "config.yaml"
+
+// Summary is what an archive holds
+type Summary struct {
+	Path  string // the archive
+	Files int
+	Bytes int64 // before compression
+	// Previous is where Restore moved the directory it replaced, or empty
+	// when there was none
+	Previous string
+}
+
+// DefaultName is the file name for a backup made at now
+func DefaultName(now time.Time) string {
+	return now.Format("devcockpit-backup-20060102-150405.tar.gz")
+}
+
+// PreviousDir is where Restore keeps the directory it replaced
+func PreviousDir(dir string) string {
+	return dir + ".before-restore"
+}
+
+// Create archives dir as a gzipped tar at path. The archive itself is left
+// out when it's written inside dir, as are sockets and other special files.
+func Create(dir, path string) (Summary, error) {
+	summary := Summary{Path: path}
+	if _, err := os.Stat(filepath.Join(dir, configFile)); err != nil {
+		return summary, fmt.Errorf("nothing to back up in %s: %w", dir, err)
+	}
+	absPath, _ := filepath.Abs(path)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return summary, err
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if abs, _ := filepath.Abs(file); abs == absPath || file == dir {
+			return nil
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, file)
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		src, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		n, err := io.Copy(tw, src)
+		summary.Files++
+		summary.Bytes += n
+		return err
+	})
+	for _, closer := range []io.Closer{tw, gz, f} {
+		if cerr := closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		os.Remove(path)
+		return Summary{Path: path}, err
+	}
+	return summary, nil
+}
+
+// Restore replaces dir with the contents of the archive at path. The
+// archive is unpacked next to dir first, so a bad archive leaves dir as it
+// was; dir itself is kept at PreviousDir, replacing an earlier one.
+func Restore(path, dir string) (Summary, error) {
+	summary := Summary{Path: path}
+	staging, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".restore-")
+	if err != nil {
+		return summary, err
+	}
+	defer os.RemoveAll(staging)
+
+	if summary.Files, summary.Bytes, err = extract(path, staging); err != nil {
+		return summary, err
+	}
+	if _, err := os.Stat(filepath.Join(staging, configFile)); err != nil {
+		return summary, fmt.Errorf("%s is not a Dev Cockpit backup: it has no %s", path, configFile)
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		summary.Previous = PreviousDir(dir)
+		if err := os.RemoveAll(summary.Previous); err != nil {
+			return summary, err
+		}
+		if err := os.Rename(dir, summary.Previous); err != nil {
+			return summary, err
+		}
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		// Put the old directory back rather than leave none
+		if summary.Previous != "" {
+			os.Rename(summary.Previous, dir)
+		}
+		return summary, err
+	}
+	return summary, nil
+}
+
+// extract unpacks the archive at path into dir, refusing entries that
+// would land outside it
+func extract(path, dir string) (files int, bytes int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s is not a backup archive: %w", path, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, bytes, nil
+		}
+		if err != nil {
+			return files, bytes, err
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return files, bytes, fmt.Errorf("unsafe path in archive: %s", header.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return files, bytes, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return files, bytes, err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return files, bytes, err
+			}
+			n, err := io.Copy(out, tr)
+			out.Close()
+			if err != nil {
+				return files, bytes, err
+			}
+			os.Chtimes(target, header.ModTime, header.ModTime)
+			files++
+			bytes += n
+		}
+	}
+}
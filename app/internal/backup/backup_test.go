@@ -0,0 +1,94 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".devcockpit")
+	writeFile(t, filepath.Join(dir, "config.yaml"), "theme: dark\n")
+	writeFile(t, filepath.Join(dir, "themes", "solar.yaml"), "primary: '#b58900'\n")
+	writeFile(t, filepath.Join(dir, "data", "metrics", "2024-07-01.bin"), "0123456789")
+
+	// Written inside the directory, the archive leaves itself out
+	archive := filepath.Join(dir, "backup.tar.gz")
+	summary, err := Create(dir, archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Files != 3 || summary.Bytes != 12+19+10 {
+		t.Errorf("summary = %+v, want 3 files of 41 bytes", summary)
+	}
+
+	// Experiment, then go back
+	writeFile(t, filepath.Join(dir, "config.yaml"), "theme: light\n")
+	restored, err := Restore(archive, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "config.yaml")); string(data) != "theme: dark\n" {
+		t.Errorf("config after restore = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "themes", "solar.yaml")); !strings.Contains(string(data), "b58900") {
+		t.Errorf("theme after restore = %q", data)
+	}
+	if restored.Previous != PreviousDir(dir) {
+		t.Fatalf("previous = %q", restored.Previous)
+	}
+	if data, _ := os.ReadFile(filepath.Join(restored.Previous, "config.yaml")); string(data) != "theme: light\n" {
+		t.Errorf("replaced config = %q, want it kept", data)
+	}
+}
+
+func TestRestoreRejectsBadArchives(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".devcockpit")
+	writeFile(t, filepath.Join(dir, "config.yaml"), "theme: dark\n")
+
+	archiveWith := func(name string) string {
+		path := filepath.Join(home, strings.NewReplacer("/", "_", ".", "_").Replace(name)+".tar.gz")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gz := gzip.NewWriter(f)
+		tw := tar.NewWriter(gz)
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 2, Typeflag: tar.TypeReg})
+		tw.Write([]byte("x\n"))
+		tw.Close()
+		gz.Close()
+		f.Close()
+		return path
+	}
+
+	for name, want := range map[string]string{
+		"../escape.yaml": "unsafe path",
+		"notes.txt":      "not a Dev Cockpit backup",
+	} {
+		if _, err := Restore(archiveWith(name), dir); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", name, err, want)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "config.yaml")); string(data) != "theme: dark\n" {
+		t.Errorf("a rejected archive changed the directory: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(home, "escape.yaml")); err == nil {
+		t.Error("an entry escaped the directory")
+	}
+}
@@ -0,0 +1,127 @@
+// Package battery reads the state of a MacBook's battery from the
+// AppleSmartBattery entry in the I/O Registry: charge, current draw, time
+// remaining, cycle count and the capacity left compared to when new.
+package battery
+
+import (
+	"errors"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// ErrNoBattery is returned on Macs without a battery
+var ErrNoBattery = errors.New("no battery")
+
+// serviceHealth is the share of its design capacity below which Apple
+// recommends servicing a battery
+const serviceHealth = 80
+
+// unknownTime is what the registry reports while macOS is still
+// estimating the time remaining
+const unknownTime = 65535
+
+// Status is one reading of the battery
+type Status struct {
+	Percent        int
+	Charging       bool
+	External       bool // on a power adapter, charging or not
+	FullyCharged   bool
+	Cycles         int
+	DesignCapacity int     // mAh when new
+	MaxCapacity    int     // mAh it holds when full now
+	Voltage        float64 // V
+	Amperage       float64 // A, negative while discharging
+	// TimeRemaining is until empty, or until full while charging; zero
+	// while macOS is still estimating or on a power adapter without
+	// charging
+	TimeRemaining time.Duration
+	Temperature   float64 // °C
+}
+
+// Watts is the power flowing in (positive, charging) or out (negative)
+func (s Status) Watts() float64 {
+	return s.Voltage * s.Amperage
+}
+
+// Health is the capacity left as a percentage of the design capacity,
+// or 0 when the battery doesn't report it
+func (s Status) Health() float64 {
+	if s.DesignCapacity <= 0 || s.MaxCapacity <= 0 {
+		return 0
+	}
+	return float64(s.MaxCapacity) / float64(s.DesignCapacity) * 100
+}
+
+// Condition is "Normal", or "Service Recommended" once the battery holds
+// less than 80% of its design capacity
+func (s Status) Condition() string {
+	if health := s.Health(); health > 0 && health < serviceHealth {
+		return "Service Recommended"
+	}
+	return "Normal"
+}
+
+// Read reads the battery with ioreg
+func Read(r runner.Runner) (Status, error) {
+	output, err := r.Output(exec.Command("ioreg", "-rw0", "-n", "AppleSmartBattery"))
+	if err != nil {
+		return Status{}, err
+	}
+	return Parse(string(output))
+}
+
+// Parse reads the properties ioreg prints for AppleSmartBattery
+func Parse(output string) (Status, error) {
+	props := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " = ")
+		if ok && strings.HasPrefix(key, `"`) {
+			props[strings.Trim(key, `"`)] = value
+		}
+	}
+	if len(props) == 0 || props["BatteryInstalled"] == "No" {
+		return Status{}, ErrNoBattery
+	}
+
+	number := func(key string) int64 {
+		// Negative values, like the amperage while discharging, are
+		// printed as unsigned 64-bit integers
+		n, err := strconv.ParseUint(props[key], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return int64(n)
+	}
+	s := Status{
+		Charging:       props["IsCharging"] == "Yes",
+		External:       props["ExternalConnected"] == "Yes",
+		FullyCharged:   props["FullyCharged"] == "Yes",
+		Cycles:         int(number("CycleCount")),
+		DesignCapacity: int(number("DesignCapacity")),
+		Voltage:        float64(number("Voltage")) / 1000,
+		Amperage:       float64(number("Amperage")) / 1000,
+		Temperature:    float64(number("Temperature")) / 100,
+	}
+
+	// Apple silicon reports the charge as a percentage of MaxCapacity,
+	// which is 100, and the capacities in mAh under AppleRaw names; Intel
+	// reports both in mAh
+	current, full := number("CurrentCapacity"), number("MaxCapacity")
+	if full > 0 {
+		s.Percent = int(math.Round(float64(current) / float64(full) * 100))
+	}
+	s.MaxCapacity = int(full)
+	if raw := number("AppleRawMaxCapacity"); raw > 0 {
+		s.MaxCapacity = int(raw)
+	}
+
+	if minutes := number("TimeRemaining"); minutes > 0 && minutes < unknownTime && (s.Charging || !s.External) {
+		s.TimeRemaining = time.Duration(minutes) * time.Minute
+	}
+	return s, nil
+}
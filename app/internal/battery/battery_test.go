@@ -0,0 +1,59 @@
+package battery
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+func TestRead(t *testing.T) {
+	fake := runner.NewFake()
+	if err := fake.SetFixture("ioreg -rw0 -n AppleSmartBattery", "testdata/ioreg_discharging.txt"); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Read(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{
+		Percent: 87, Cycles: 142, DesignCapacity: 5103, MaxCapacity: 4891,
+		Voltage: 12.48, Amperage: -0.798, TimeRemaining: 412 * time.Minute, Temperature: 30.12,
+	}
+	if s != want {
+		t.Errorf("got %+v\nwant %+v", s, want)
+	}
+	if w := s.Watts(); math.Abs(w+9.959) > 0.001 {
+		t.Errorf("watts = %v, want -9.96", w)
+	}
+	if h := s.Health(); math.Round(h) != 96 || s.Condition() != "Normal" {
+		t.Errorf("health = %v (%s), want 96%% Normal", h, s.Condition())
+	}
+}
+
+func TestParseIntelCharging(t *testing.T) {
+	fake := runner.NewFake()
+	if err := fake.SetFixture("ioreg -rw0 -n AppleSmartBattery", "testdata/ioreg_charging_intel.txt"); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Read(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Capacities in mAh, with the time to full still being estimated
+	if s.Percent != 64 || !s.Charging || !s.External || s.MaxCapacity != 3921 || s.TimeRemaining != 0 || s.Watts() <= 0 {
+		t.Errorf("got %+v", s)
+	}
+	if s.Condition() != "Service Recommended" {
+		t.Errorf("a battery at %.0f%% of its design capacity should need service", s.Health())
+	}
+}
+
+func TestNoBattery(t *testing.T) {
+	fake := runner.NewFake().Set("ioreg -rw0 -n AppleSmartBattery", "", nil)
+	if _, err := Read(fake); !errors.Is(err, ErrNoBattery) {
+		t.Errorf("err = %v, want ErrNoBattery", err)
+	}
+}
@@ -0,0 +1,328 @@
+// Package bloat breaks a Go binary's size down for maintainers: its
+// sections, the code each module compiles to and the files embedded with
+// go:embed, to keep the single-binary release lean.
+package bloat
+
+import (
+	"bufio"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Std is the module name the standard library's code is counted under
+const Std = "std"
+
+// Generated counts code that belongs to no package: type equality and
+// hash functions, wrappers and the like
+const Generated = "(generated)"
+
+// Report is a binary's size broken down
+type Report struct {
+	Path      string
+	Size      int64
+	GoVersion string
+	Sections  []Section // largest first
+	Modules   []Module  // largest first
+	Code      int64     // the functions' total size
+}
+
+// Section is a section of the executable file
+type Section struct {
+	Name string
+	Size int64
+}
+
+// Module is a module, or the standard library, with the size of the
+// functions compiled from its packages
+type Module struct {
+	Path    string
+	Version string
+	Code    int64
+	Funcs   int
+}
+
+// Embed is a file a go:embed directive includes
+type Embed struct {
+	Package string // the folder of the .go file, relative to the root
+	Path    string // relative to the root
+	Size    int64
+}
+
+// Mach-O section types that are zero-filled when loaded
+const (
+	machoZerofill    = 0x1
+	machoGBZerofill  = 0xc
+	machoTLVZerofill = 0x12
+)
+
+// executable is what Analyze needs from a Mach-O or ELF file
+type executable struct {
+	sections []Section
+	pclntab  []byte
+	text     uint64 // address of the text section, where code starts
+}
+
+// Analyze reads the binary at path. Code sizes come from the pclntab, the
+// function table the runtime needs, so stripped release builds work too.
+func Analyze(path string) (Report, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Report{}, err
+	}
+	exe, err := open(path)
+	if err != nil {
+		return Report{}, err
+	}
+	build, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return Report{}, fmt.Errorf("%s: %w", path, err)
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(exe.pclntab, exe.text))
+	if err != nil {
+		return Report{}, fmt.Errorf("%s: reading the function table: %w", path, err)
+	}
+
+	report := Report{Path: path, Size: info.Size(), GoVersion: build.GoVersion, Sections: exe.sections}
+	modules := map[string]*Module{
+		Std:       {Path: Std, Version: build.GoVersion},
+		Generated: {Path: Generated},
+	}
+	var paths []string
+	add := func(path, version string) {
+		modules[path] = &Module{Path: path, Version: version}
+		paths = append(paths, path)
+	}
+	add(build.Main.Path, build.Main.Version)
+	for _, dep := range build.Deps {
+		// Packages keep the path of a replaced module
+		if dep.Replace != nil {
+			add(dep.Path, dep.Replace.Path+" "+dep.Replace.Version)
+		} else {
+			add(dep.Path, dep.Version)
+		}
+	}
+	// The longest matching path wins, for modules nested in others
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+
+	for _, fn := range table.Funcs {
+		size := int64(fn.End - fn.Entry)
+		m := modules[moduleOf(fn.PackageName(), paths)]
+		m.Code += size
+		m.Funcs++
+		report.Code += size
+	}
+	for _, m := range modules {
+		if m.Funcs > 0 {
+			report.Modules = append(report.Modules, *m)
+		}
+	}
+	sort.Slice(report.Modules, func(i, j int) bool { return report.Modules[i].Code > report.Modules[j].Code })
+	return report, nil
+}
+
+// moduleOf is the module pkg belongs to: the longest of paths that
+// contains it, the standard library for paths without a dot in their
+// first element, or Generated for code of no package
+func moduleOf(pkg string, paths []string) string {
+	if pkg == "" {
+		return Generated
+	}
+	for _, path := range paths {
+		if pkg == path || strings.HasPrefix(pkg, path+"/") {
+			return path
+		}
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	if !strings.Contains(first, ".") {
+		return Std
+	}
+	return Generated
+}
+
+// open reads the sections and function table of a Mach-O or ELF file
+func open(path string) (executable, error) {
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		var exe executable
+		for _, s := range f.Sections {
+			// Zero-filled sections take memory but no room in the file
+			if kind := s.Flags & 0xff; kind != machoZerofill && kind != machoGBZerofill && kind != machoTLVZerofill {
+				exe.sections = append(exe.sections, Section{Name: s.Seg + "," + s.Name, Size: int64(s.Size)})
+			}
+			switch s.Name {
+			case "__text":
+				exe.text = s.Addr
+			case "__gopclntab":
+				if exe.pclntab, err = s.Data(); err != nil {
+					return executable{}, err
+				}
+			}
+		}
+		return exe.sorted(path)
+	}
+	f, err := elf.Open(path)
+	if err != nil {
+		return executable{}, fmt.Errorf("%s is neither a Mach-O nor an ELF executable", path)
+	}
+	defer f.Close()
+	var exe executable
+	for _, s := range f.Sections {
+		if s.Type == elf.SHT_NULL || s.Type == elf.SHT_NOBITS || s.Size == 0 {
+			continue
+		}
+		exe.sections = append(exe.sections, Section{Name: s.Name, Size: int64(s.Size)})
+		switch s.Name {
+		case ".text":
+			exe.text = s.Addr
+		case ".gopclntab":
+			if exe.pclntab, err = s.Data(); err != nil {
+				return executable{}, err
+			}
+		}
+	}
+	return exe.sorted(path)
+}
+
+func (exe executable) sorted(path string) (executable, error) {
+	if exe.pclntab == nil {
+		return executable{}, fmt.Errorf("%s has no Go function table", path)
+	}
+	sort.SliceStable(exe.sections, func(i, j int) bool { return exe.sections[i].Size > exe.sections[j].Size })
+	return exe, nil
+}
+
+// Embeds lists the files the go:embed directives in the .go files under
+// root include, largest first. Test files are left out, as they aren't
+// in the binary.
+func Embeds(root string) ([]Embed, error) {
+	var embeds []Embed
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata" || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		patterns, err := embedPatterns(path)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(path)
+		for _, pattern := range patterns {
+			found, err := embedFiles(root, dir, pattern)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			embeds = append(embeds, found...)
+		}
+		return nil
+	})
+	sort.SliceStable(embeds, func(i, j int) bool { return embeds[i].Size > embeds[j].Size })
+	return embeds, err
+}
+
+// embedPatterns returns the patterns of a file's go:embed directives
+func embedPatterns(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "//go:embed "); ok {
+			for _, field := range strings.Fields(rest) {
+				patterns = append(patterns, strings.Trim(field, "\"`"))
+			}
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// embedFiles returns the files pattern matches in dir, walking folders as
+// go:embed does; "all:" also takes the hidden files it otherwise skips
+func embedFiles(root, dir, pattern string) ([]Embed, error) {
+	all := strings.HasPrefix(pattern, "all:")
+	matches, err := filepath.Glob(filepath.Join(dir, strings.TrimPrefix(pattern, "all:")))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, errors.New("no files match " + pattern)
+	}
+	pkg, _ := filepath.Rel(root, dir)
+	var embeds []Embed
+	for _, match := range matches {
+		err := filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			hidden := strings.HasPrefix(d.Name(), ".") || strings.HasPrefix(d.Name(), "_")
+			if path != match && hidden && !all {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			embeds = append(embeds, Embed{Package: pkg, Path: rel, Size: info.Size()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return embeds, nil
+}
+
+// FindModuleRoot returns the folder with the go.mod dir is in, or ""
+// outside a module
+func FindModuleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// FormatSize is a size in decimal units, e.g. "12.4 MB", as Finder shows
+// file sizes
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1f GB", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1f MB", float64(n)/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1f kB", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d B", n)
+}
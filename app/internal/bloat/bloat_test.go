@@ -0,0 +1,102 @@
+package bloat
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeTestBinary(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Analyze(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Size == 0 || report.Code == 0 || report.Code > report.Size || len(report.Sections) == 0 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	sizes := map[string]int64{}
+	var total int64
+	for _, m := range report.Modules {
+		sizes[m.Path] = m.Code
+		total += m.Code
+	}
+	if total != report.Code {
+		t.Errorf("modules add up to %d, want %d", total, report.Code)
+	}
+	// The test binary holds this package, the runtime and the testing
+	// package, and nothing from the TUI libraries
+	if sizes[Std] == 0 || sizes["github.com/caioricciuti/dev-cockpit"] == 0 {
+		t.Errorf("sizes = %v", sizes)
+	}
+	if _, ok := sizes["github.com/charmbracelet/bubbletea"]; ok {
+		t.Errorf("bubbletea isn't linked into this test: %v", sizes)
+	}
+	for i := 1; i < len(report.Modules); i++ {
+		if report.Modules[i].Code > report.Modules[i-1].Code {
+			t.Fatalf("modules aren't sorted: %+v", report.Modules)
+		}
+	}
+}
+
+func TestModuleOf(t *testing.T) {
+	paths := []string{"github.com/acme/tool/v2", "github.com/acme/tool", "golang.org/x/sys"}
+	for pkg, want := range map[string]string{
+		"github.com/acme/tool/internal/app": "github.com/acme/tool",
+		"github.com/acme/tool/v2/cmd":       "github.com/acme/tool/v2",
+		"golang.org/x/sys/unix":             "golang.org/x/sys",
+		"runtime":                           Std,
+		"internal/abi":                      Std,
+		"":                                  Generated,
+		"example.com/unknown":               Generated,
+	} {
+		if got := moduleOf(pkg, paths); got != want {
+			t.Errorf("moduleOf(%q) = %q, want %q", pkg, got, want)
+		}
+	}
+}
+
+func TestEmbeds(t *testing.T) {
+	root := t.TempDir()
+	write := func(path string, size int) {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "internal/tour"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "internal/tour/tour.go"),
+		[]byte("package tour\n\nimport \"embed\"\n\n//go:embed intro.txt pages\nvar content embed.FS\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "internal/tour/tour_test.go"),
+		[]byte("package tour\n\n//go:embed testdata/big.bin\nvar fixture []byte\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	write("internal/tour/intro.txt", 120)
+	write("internal/tour/pages/1.md", 4000)
+	write("internal/tour/pages/.DS_Store", 6000)
+	write("internal/tour/testdata/big.bin", 1<<20)
+
+	embeds, err := Embeds(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Embed{
+		{Package: "internal/tour", Path: "internal/tour/pages/1.md", Size: 4000},
+		{Package: "internal/tour", Path: "internal/tour/intro.txt", Size: 120},
+	}
+	if !reflect.DeepEqual(embeds, want) {
+		t.Errorf("embeds = %+v, want %+v", embeds, want)
+	}
+}
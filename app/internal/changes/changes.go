@@ -0,0 +1,145 @@
+// Package changes records the system state Dev Cockpit changes during the
+// session (defaults writes, appearance switches and the like) with the value
+// before and after, so the Settings tab can list them and undo them.
+package changes
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// NotSet is shown for a value that didn't exist, e.g. a defaults key macOS
+// was using its built-in default for
+const NotSet = "(not set)"
+
+// ErrNotRevertible is returned by Revert for a change without an undo
+var ErrNotRevertible = errors.New("this change can't be reverted")
+
+// Change is one modification of system state
+type Change struct {
+	ID     int
+	Time   time.Time
+	Source string // module title
+	// What names what changed, e.g. "com.apple.dock autohide"
+	What   string
+	Before string
+	After  string
+	// Revert puts Before back; nil when the change can't be undone
+	Revert   func() error
+	Reverted bool
+	// Defaults are the defaults keys the change wrote, with the values
+	// they had, for undoing it once the session is over
+	Defaults []Default
+}
+
+// Default is a defaults key as it was before a change
+type Default struct {
+	Key    defaults.Key
+	Before defaults.Value
+}
+
+var (
+	mu      sync.Mutex
+	history []Change
+	nextID  int
+)
+
+// Record adds a change to the session and returns its ID
+func Record(c Change) int {
+	mu.Lock()
+	defer mu.Unlock()
+	nextID++
+	c.ID = nextID
+	if c.Time.IsZero() {
+		c.Time = time.Now()
+	}
+	history = append(history, c)
+	logger.Info("Changed %s: %s -> %s (%s)", c.What, c.Before, c.After, c.Source)
+	return c.ID
+}
+
+// List returns the session's changes, most recent first
+func List() []Change {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]Change, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		list = append(list, history[i])
+	}
+	return list
+}
+
+// Revert undoes the change with the given ID and marks it reverted
+func Revert(id int) error {
+	mu.Lock()
+	var undo func() error
+	for _, c := range history {
+		if c.ID == id && !c.Reverted {
+			undo = c.Revert
+		}
+	}
+	mu.Unlock()
+	if undo == nil {
+		return ErrNotRevertible
+	}
+
+	// The undo runs commands, so don't hold the lock meanwhile
+	if err := undo(); err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i := range history {
+		if history[i].ID == id {
+			history[i].Reverted = true
+			logger.Info("Reverted %s to %s", history[i].What, history[i].Before)
+		}
+	}
+	return nil
+}
+
+// Clear forgets every change
+func Clear() {
+	mu.Lock()
+	defer mu.Unlock()
+	history = nil
+}
+
+// WriteDefault writes value to key and records the change with the value
+// it replaces. apply, when set, runs after the write is reverted as well,
+// e.g. to restart the app that reads the key.
+func WriteDefault(r runner.Runner, source string, key defaults.Key, value string, apply func() error) error {
+	before, err := defaults.Read(r, key)
+	if err != nil {
+		return err
+	}
+	if err := defaults.Write(r, key, value); err != nil {
+		return err
+	}
+	shown := NotSet
+	if before.Set {
+		shown = before.Text
+	}
+	Record(Change{
+		Source:   source,
+		What:     key.String(),
+		Before:   shown,
+		After:    value,
+		Defaults: []Default{{Key: key, Before: before}},
+		Revert: func() error {
+			if err := defaults.Restore(r, key, before); err != nil {
+				return err
+			}
+			if apply != nil {
+				return apply()
+			}
+			return nil
+		},
+	})
+	return nil
+}
@@ -0,0 +1,52 @@
+package changes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+func TestWriteDefaultAndRevert(t *testing.T) {
+	t.Cleanup(Clear)
+	autohide := defaults.Key{Domain: "com.apple.dock", Name: "autohide", Type: defaults.Bool}
+	fake := runner.NewFake().
+		Set("defaults read com.apple.dock autohide", "The domain/default pair of (com.apple.dock, autohide) does not exist", errors.New("exit status 1")).
+		Set("defaults write com.apple.dock autohide -bool true", "", nil).
+		Set("defaults delete com.apple.dock autohide", "", nil)
+
+	restarts := 0
+	if err := WriteDefault(fake, "Quick Actions", autohide, "true", func() error { restarts++; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	list := List()
+	if len(list) != 1 || list[0].Before != NotSet || list[0].After != "true" || list[0].What != "com.apple.dock autohide" {
+		t.Fatalf("recorded %+v", list)
+	}
+
+	if err := Revert(list[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if calls := fake.Calls(); calls[len(calls)-1] != "defaults delete com.apple.dock autohide" || restarts != 1 {
+		t.Errorf("revert ran %q with %d restart(s), want the key deleted and the Dock restarted", calls, restarts)
+	}
+	if !List()[0].Reverted {
+		t.Error("the change should be marked reverted")
+	}
+	if err := Revert(list[0].ID); !errors.Is(err, ErrNotRevertible) {
+		t.Errorf("reverting twice: %v", err)
+	}
+}
+
+func TestListNewestFirst(t *testing.T) {
+	t.Cleanup(Clear)
+	Record(Change{What: "first"})
+	Record(Change{What: "second"})
+	if list := List(); list[0].What != "second" || list[1].What != "first" {
+		t.Errorf("list = %+v", list)
+	}
+	if err := Revert(List()[0].ID); !errors.Is(err, ErrNotRevertible) {
+		t.Errorf("a change without an undo reverted: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+// Package ci reads the latest GitHub Actions run of repositories from the
+// GitHub API, and finds the GitHub repositories checked out in the
+// workspace roots.
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/workspace"
+)
+
+// APIURL is the GitHub API; tests point it at a local server
+var APIURL = "https://api.github.com"
+
+// client gives up on a slow API rather than hold up the dashboard
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Run is the latest workflow run of a repository
+type Run struct {
+	Repo     string // owner/name
+	Workflow string
+	Branch   string
+	Number   int
+	// Status is queued, in_progress or completed; Conclusion is set once
+	// completed: success, failure, cancelled, timed_out and so on
+	Status     string
+	Conclusion string
+	URL        string // the run's page
+	Updated    time.Time
+}
+
+// Failed reports whether the run finished without succeeding for a reason
+// worth looking into
+func (r Run) Failed() bool {
+	switch r.Conclusion {
+	case "failure", "timed_out", "startup_failure", "action_required":
+		return true
+	}
+	return false
+}
+
+// State is the run's status in a word: passed, failed, running, queued or
+// the conclusion GitHub gave
+func (r Run) State() string {
+	switch {
+	case r.Status == "queued" || r.Status == "waiting" || r.Status == "pending":
+		return "queued"
+	case r.Status != "completed":
+		return "running"
+	case r.Conclusion == "success":
+		return "passed"
+	case r.Failed():
+		return "failed"
+	}
+	return strings.ReplaceAll(r.Conclusion, "_", " ")
+}
+
+// RateLimitError is GitHub refusing more requests until Reset
+type RateLimitError struct {
+	Repo  string
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: GitHub API rate limit reached; checking again at %s", e.Repo, e.Reset.Local().Format("15:04"))
+}
+
+// rateLimited returns the error for a response refused for the rate limit,
+// with when GitHub allows requests again, or nil for any other response
+func rateLimited(repo string, resp *http.Response) error {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	// Secondary limits say how long to wait; the hourly one when it resets
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return &RateLimitError{Repo: repo, Reset: time.Now().Add(time.Duration(secs) * time.Second)}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &RateLimitError{Repo: repo, Reset: time.Unix(reset, 0)}
+}
+
+// Latest fetches repo's most recent workflow run. token may be empty,
+// though GitHub then allows 60 requests an hour and no private repos.
+// Refused for the rate limit, it returns a *RateLimitError.
+func Latest(repo, token string) (Run, error) {
+	req, err := http.NewRequest(http.MethodGet, APIURL+"/repos/"+repo+"/actions/runs?per_page=1", nil)
+	if err != nil {
+		return Run{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Run{}, err
+	}
+	defer resp.Body.Close()
+	if err := rateLimited(repo, resp); err != nil {
+		return Run{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Run{}, fmt.Errorf("%s: GitHub API error: HTTP %d", repo, resp.StatusCode)
+	}
+
+	var body struct {
+		Runs []struct {
+			Name       string    `json:"name"`
+			Branch     string    `json:"head_branch"`
+			Number     int       `json:"run_number"`
+			Status     string    `json:"status"`
+			Conclusion string    `json:"conclusion"`
+			URL        string    `json:"html_url"`
+			Updated    time.Time `json:"updated_at"`
+		} `json:"workflow_runs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Run{}, fmt.Errorf("%s: %w", repo, err)
+	}
+	if len(body.Runs) == 0 {
+		return Run{Repo: repo}, nil
+	}
+	r := body.Runs[0]
+	return Run{Repo: repo, Workflow: r.Name, Branch: r.Branch, Number: r.Number,
+		Status: r.Status, Conclusion: r.Conclusion, URL: r.URL, Updated: r.Updated}, nil
+}
+
+// Token is the GitHub token from GITHUB_TOKEN or GH_TOKEN, or the one the
+// GitHub CLI is logged in with; empty when there is none
+func Token(r runner.Runner) string {
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	if _, err := r.LookPath("gh"); err != nil {
+		return ""
+	}
+	out, err := r.Output(exec.Command("gh", "auth", "token"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// RepoFromRemote returns owner/name from a GitHub remote URL in its
+// HTTPS, SSH or scp-like form
+func RepoFromRemote(url string) (string, bool) {
+	url = strings.TrimSpace(url)
+	for _, prefix := range []string{"https://github.com/", "http://github.com/", "ssh://git@github.com/", "git@github.com:"} {
+		if rest, ok := strings.CutPrefix(url, prefix); ok {
+			rest = strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git")
+			if owner, name, ok := strings.Cut(rest, "/"); ok && owner != "" && name != "" && !strings.Contains(name, "/") {
+				return owner + "/" + name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Detect returns the GitHub repositories of the checkouts in the roots,
+// most recently worked in first and at most limit of them
+func Detect(r runner.Runner, roots []string, limit int) []string {
+	type checkout struct {
+		repo    string
+		touched time.Time
+	}
+	var found []checkout
+	seen := map[string]bool{}
+	for _, dir := range workspace.Projects(roots) {
+		out, err := r.Output(exec.Command("git", "-C", dir, "remote", "get-url", "origin"))
+		if err != nil {
+			continue
+		}
+		repo, ok := RepoFromRemote(string(out))
+		if !ok || seen[repo] {
+			continue
+		}
+		seen[repo] = true
+		// The index changes with every commit, checkout and add
+		c := checkout{repo: repo}
+		if info, err := os.Stat(filepath.Join(dir, ".git", "index")); err == nil {
+			c.touched = info.ModTime()
+		}
+		found = append(found, c)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].touched.After(found[j].touched) })
+	repos := make([]string, 0, min(len(found), limit))
+	for _, c := range found[:min(len(found), limit)] {
+		repos = append(repos, c.repo)
+	}
+	return repos
+}
@@ -0,0 +1,148 @@
+package ci
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+func TestLatest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/repos/acme/api/actions/runs":
+			w.Write([]byte(`{"total_count": 2, "workflow_runs": [{"name": "CI", "head_branch": "main", "run_number": 418,
+				"status": "completed", "conclusion": "failure", "html_url": "https://github.com/acme/api/actions/runs/9",
+				"updated_at": "2026-10-15T09:12:00Z"}]}`))
+		case "/repos/acme/empty/actions/runs":
+			w.Write([]byte(`{"total_count": 0, "workflow_runs": []}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(url string) { APIURL = url }(APIURL)
+	APIURL = srv.URL
+
+	run, err := Latest("acme/api", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Run{Repo: "acme/api", Workflow: "CI", Branch: "main", Number: 418, Status: "completed", Conclusion: "failure",
+		URL: "https://github.com/acme/api/actions/runs/9", Updated: time.Date(2026, 10, 15, 9, 12, 0, 0, time.UTC)}
+	if run != want || !run.Failed() || run.State() != "failed" {
+		t.Errorf("got %+v", run)
+	}
+	if run, err := Latest("acme/empty", "secret"); err != nil || run != (Run{Repo: "acme/empty"}) {
+		t.Errorf("a repo without runs = %+v, %v", run, err)
+	}
+	if _, err := Latest("acme/api", ""); err == nil {
+		t.Error("an HTTP error should fail")
+	}
+}
+
+func TestLatestRateLimited(t *testing.T) {
+	reset := time.Now().Add(40 * time.Minute).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/api/actions/runs":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			http.Error(w, "API rate limit exceeded", http.StatusForbidden)
+		case "/repos/acme/busy/actions/runs":
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, "secondary rate limit", http.StatusForbidden)
+		default:
+			http.Error(w, "resource not accessible", http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+	defer func(url string) { APIURL = url }(APIURL)
+	APIURL = srv.URL
+
+	var limited *RateLimitError
+	if _, err := Latest("acme/api", ""); !errors.As(err, &limited) || !limited.Reset.Equal(reset) {
+		t.Errorf("the hourly limit = %v", err)
+	}
+	if _, err := Latest("acme/busy", ""); !errors.As(err, &limited) || time.Until(limited.Reset) < 50*time.Second {
+		t.Errorf("a secondary limit = %v", err)
+	}
+	if _, err := Latest("acme/private", ""); err == nil || errors.As(err, &limited) {
+		t.Errorf("a 403 with requests left isn't the rate limit: %v", err)
+	}
+}
+
+func TestState(t *testing.T) {
+	for _, tc := range []struct {
+		run  Run
+		want string
+	}{
+		{Run{Status: "in_progress"}, "running"},
+		{Run{Status: "queued"}, "queued"},
+		{Run{Status: "completed", Conclusion: "success"}, "passed"},
+		{Run{Status: "completed", Conclusion: "timed_out"}, "failed"},
+		{Run{Status: "completed", Conclusion: "cancelled"}, "cancelled"},
+	} {
+		if got := tc.run.State(); got != tc.want {
+			t.Errorf("%+v: state = %q, want %q", tc.run, got, tc.want)
+		}
+	}
+}
+
+func TestRepoFromRemote(t *testing.T) {
+	for url, want := range map[string]string{
+		"https://github.com/acme/api.git\n":  "acme/api",
+		"git@github.com:acme/web.git":        "acme/web",
+		"ssh://git@github.com/acme/cli":      "acme/cli",
+		"https://gitlab.com/acme/api.git":    "",
+		"https://github.com/acme":            "",
+		"https://github.com/acme/api/extras": "",
+	} {
+		if got, _ := RepoFromRemote(url); got != want {
+			t.Errorf("RepoFromRemote(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	touch := func(dir string, when time.Time) {
+		path := filepath.Join(root, dir, ".git", "index")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, when, when); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	touch("api", now.Add(-48*time.Hour))
+	touch("web", now.Add(-time.Hour))
+	touch("fork", now)
+	touch("local", now)
+	touch("work/cli", now.Add(-2*time.Hour))
+
+	fake := runner.NewFake().
+		Set("git -C "+filepath.Join(root, "api")+" remote get-url origin", "git@github.com:acme/api.git\n", nil).
+		Set("git -C "+filepath.Join(root, "web")+" remote get-url origin", "https://github.com/acme/web\n", nil).
+		Set("git -C "+filepath.Join(root, "fork")+" remote get-url origin", "https://gitlab.com/me/fork.git\n", nil).
+		Set("git -C "+filepath.Join(root, "work/cli")+" remote get-url origin", "git@github.com:acme/cli.git\n", nil)
+
+	if got := Detect(fake, []string{root}, 2); !reflect.DeepEqual(got, []string{"acme/web", "acme/cli"}) {
+		t.Errorf("detected %v, want the two most recently touched GitHub repos", got)
+	}
+}
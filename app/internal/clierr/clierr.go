@@ -0,0 +1,87 @@
+// Package clierr classifies CLI failures so every command exits with a
+// documented code that scripts can branch on. Errors keep their message
+// and are wrapped with a Code; the code survives further wrapping with
+// fmt.Errorf("...: %w", err).
+package clierr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is a process exit code
+type Code int
+
+// Exit codes. They are part of the CLI's interface: don't renumber them.
+const (
+	OK                Code = 0 // success
+	Failure           Code = 1 // any failure without a more specific code
+	Usage             Code = 2 // bad command line
+	Config            Code = 3 // configuration could not be loaded or written
+	DependencyMissing Code = 4 // a required tool or file is missing
+	Cancelled         Code = 5 // the user declined a prompt or authorization
+	PartialFailure    Code = 6 // the command did some but not all of its work
+	Network           Code = 7 // a network request failed
+)
+
+// String names the code for help output and logs
+func (c Code) String() string {
+	switch c {
+	case OK:
+		return "ok"
+	case Usage:
+		return "usage"
+	case Config:
+		return "config"
+	case DependencyMissing:
+		return "dependency missing"
+	case Cancelled:
+		return "cancelled"
+	case PartialFailure:
+		return "partial failure"
+	case Network:
+		return "network"
+	default:
+		return "failure"
+	}
+}
+
+// Codes lists every exit code in order, for documentation
+var Codes = []Code{OK, Failure, Usage, Config, DependencyMissing, Cancelled, PartialFailure, Network}
+
+// Error is an error with an exit code
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Wrap attaches code to err. It returns nil for a nil err.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// New returns a formatted error with code
+func New(code Code, format string, args ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// CodeOf returns the exit code for err: OK for nil, the outermost code in
+// its chain if there is one, and Failure otherwise. A caller that wraps a
+// coded error with another code thereby reclassifies it.
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return Failure
+}
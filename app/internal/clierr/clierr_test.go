@@ -0,0 +1,42 @@
+package clierr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCodeOf(t *testing.T) {
+	network := Wrap(Network, errors.New("failed to connect to GitHub"))
+
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, OK},
+		{"plain error", errors.New("boom"), Failure},
+		{"coded", network, Network},
+		{"wrapped again", fmt.Errorf("failed to check for updates: %w", network), Network},
+		{"new", New(Cancelled, "update cancelled"), Cancelled},
+		{"reclassified", Wrap(Config, fmt.Errorf("failed to fetch the config: %w", network)), Config},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapKeepsMessage(t *testing.T) {
+	base := errors.New("trash directory not found")
+	err := Wrap(DependencyMissing, base)
+	if err.Error() != base.Error() || !errors.Is(err, base) {
+		t.Errorf("Wrap changed the error: %v", err)
+	}
+	if Wrap(Failure, nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+}
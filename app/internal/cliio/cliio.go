@@ -0,0 +1,159 @@
+// Package cliio prints the output of the CLI subcommands (update,
+// uninstall, cleanup...). Colors are only used on a terminal and are
+// turned off by NO_COLOR or --no-color; --plain also drops the banners
+// and symbols so the output is easy to parse in scripts.
+package cliio
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// Options are the global output flags
+type Options struct {
+	NoColor bool // --no-color
+	Plain   bool // --plain: no color, banners or symbols
+}
+
+// Color is an ANSI foreground color
+type Color string
+
+const (
+	Red    Color = "\033[0;31m"
+	Green  Color = "\033[0;32m"
+	Yellow Color = "\033[1;33m"
+	Blue   Color = "\033[0;34m"
+	reset        = "\033[0m"
+)
+
+// defaultWidth is used when the output is not a terminal
+const defaultWidth = 80
+
+// Printer writes CLI output to one stream
+type Printer struct {
+	w     io.Writer
+	color bool
+	plain bool
+	width int
+}
+
+// New returns a printer for w. Colors and the terminal width are only
+// detected when w is a terminal.
+func New(w io.Writer, opts Options) *Printer {
+	p := &Printer{w: w, plain: opts.Plain, width: defaultWidth}
+	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		_, noColorSet := os.LookupEnv("NO_COLOR")
+		p.color = !opts.NoColor && !opts.Plain && !noColorSet
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			p.width = width
+		}
+	}
+	return p
+}
+
+// Stdout is the printer used by the package-level functions
+var Stdout = New(os.Stdout, Options{})
+
+// Configure applies the global flags to Stdout
+func Configure(opts Options) {
+	Stdout = New(os.Stdout, opts)
+}
+
+// Width returns the terminal width, or 80 when not on a terminal
+func (p *Printer) Width() int { return p.width }
+
+// Plain reports whether decorations are off
+func (p *Printer) Plain() bool { return p.plain }
+
+// Paint wraps text in color when colors are on
+func (p *Printer) Paint(c Color, text string) string {
+	if !p.color {
+		return text
+	}
+	return string(c) + text + reset
+}
+
+// Println writes a line
+func (p *Printer) Println(a ...interface{}) {
+	fmt.Fprintln(p.w, a...)
+}
+
+// Printf writes formatted text
+func (p *Printer) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(p.w, format, a...)
+}
+
+// Info, Success, Warning and Error print a status line. Plain output uses
+// a word instead of the symbol so scripts can grep for it.
+func (p *Printer) Info(msg string)    { p.status(Blue, "ℹ", "info:", msg) }
+func (p *Printer) Success(msg string) { p.status(Green, "✓", "ok:", msg) }
+func (p *Printer) Warning(msg string) { p.status(Yellow, "⚠", "warning:", msg) }
+func (p *Printer) Error(msg string)   { p.status(Red, "✗", "error:", msg) }
+
+func (p *Printer) status(c Color, symbol, word, msg string) {
+	if p.plain {
+		fmt.Fprintf(p.w, "%s %s\n", word, msg)
+		return
+	}
+	fmt.Fprintf(p.w, "%s %s\n", p.Paint(c, symbol), msg)
+}
+
+// Banner prints title in a box sized to fit, or just the title when plain
+func (p *Printer) Banner(c Color, title string) {
+	if p.plain {
+		fmt.Fprintln(p.w, title)
+		return
+	}
+	inner := min(lipgloss.Width(title)+6, p.width-2)
+	pad := max(inner-lipgloss.Width(title), 0)
+	left := strings.Repeat(" ", pad/2)
+	right := strings.Repeat(" ", pad-pad/2)
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.Paint(c, "╔"+strings.Repeat("═", inner)+"╗"))
+	fmt.Fprintln(p.w, p.Paint(c, "║"+left+title+right+"║"))
+	fmt.Fprintln(p.w, p.Paint(c, "╚"+strings.Repeat("═", inner)+"╝"))
+	fmt.Fprintln(p.w)
+}
+
+// Fields prints label/value rows with the values in one column
+func (p *Printer) Fields(rows [][2]string) {
+	labelWidth := 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, lipgloss.Width(row[0]))
+	}
+	for _, row := range rows {
+		label := row[0] + ":" + strings.Repeat(" ", labelWidth-lipgloss.Width(row[0]))
+		fmt.Fprintf(p.w, "%s %s\n", label, row[1])
+	}
+}
+
+// Truncate shortens line to the output width
+func (p *Printer) Truncate(line string, indent int) string {
+	limit := p.width - indent
+	if limit <= 3 || lipgloss.Width(line) <= limit {
+		return line
+	}
+	runes := []rune(line)
+	for lipgloss.Width(string(runes)) > limit-3 {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// Package-level shortcuts for Stdout
+
+func Info(msg string)                        { Stdout.Info(msg) }
+func Success(msg string)                     { Stdout.Success(msg) }
+func Warning(msg string)                     { Stdout.Warning(msg) }
+func Error(msg string)                       { Stdout.Error(msg) }
+func Banner(c Color, title string)           { Stdout.Banner(c, title) }
+func Fields(rows [][2]string)                { Stdout.Fields(rows) }
+func Println(a ...interface{})               { Stdout.Println(a...) }
+func Printf(format string, a ...interface{}) { Stdout.Printf(format, a...) }
+func Paint(c Color, text string) string      { return Stdout.Paint(c, text) }
@@ -0,0 +1,90 @@
+package cliio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPipedOutputHasNoColor(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf, Options{})
+	p.Success("done")
+	p.Banner(Green, "Dev Cockpit")
+
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("output to a pipe contains ANSI codes: %q", buf.String())
+	}
+	if p.Width() != defaultWidth {
+		t.Errorf("width = %d, want %d when not a terminal", p.Width(), defaultWidth)
+	}
+}
+
+func TestPaint(t *testing.T) {
+	p := &Printer{color: true}
+	if got := p.Paint(Red, "x"); got != string(Red)+"x"+reset {
+		t.Errorf("Paint = %q", got)
+	}
+	p.color = false
+	if got := p.Paint(Red, "x"); got != "x" {
+		t.Errorf("Paint without color = %q", got)
+	}
+}
+
+func TestPlainOutput(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf, Options{Plain: true})
+	p.Banner(Blue, "Dev Cockpit Updater")
+	p.Info("Checking for updates...")
+	p.Warning("Requesting administrator privileges")
+	p.Error("Update failed")
+
+	want := "Dev Cockpit Updater\ninfo: Checking for updates...\nwarning: Requesting administrator privileges\nerror: Update failed\n"
+	if buf.String() != want {
+		t.Errorf("plain output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFieldsAlignValues(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf, Options{})
+	p.Fields([][2]string{
+		{"Current version", "v1.0.0"},
+		{"Latest version", "v1.1.0"},
+		{"Log file", "~/.devcockpit/debug.log"},
+	})
+
+	want := "" +
+		"Current version: v1.0.0\n" +
+		"Latest version:  v1.1.0\n" +
+		"Log file:        ~/.devcockpit/debug.log\n"
+	if buf.String() != want {
+		t.Errorf("fields = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestBannerFitsTitle(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf, Options{})
+	p.Banner(Blue, "Dev Cockpit")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("banner = %q, want 3 lines", buf.String())
+	}
+	for _, line := range lines {
+		if len([]rune(line)) != len([]rune(lines[0])) {
+			t.Errorf("banner lines differ in width:\n%s", buf.String())
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	p := &Printer{width: 20}
+	if got := p.Truncate("short", 2); got != "short" {
+		t.Errorf("Truncate(short) = %q", got)
+	}
+	if got := p.Truncate("a line that is much too long", 2); got != "a line that is ..." {
+		t.Errorf("Truncate(long) = %q", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -27,6 +29,24 @@ type Config struct {
 
 	// Storage settings
 	Storage StorageConfig `mapstructure:"storage"`
+
+	// Commands for opening paths outside the TUI
+	Open OpenConfig `mapstructure:"open"`
+
+	// Native notifications for finished tasks
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+
+	// Threshold alerts and where to send them
+	Alerts AlertsConfig `mapstructure:"alerts"`
+
+	// Other Macs summarized over SSH
+	Fleet FleetConfig `mapstructure:"fleet"`
+
+	// What the background maintenance agent does on each run
+	Maintenance MaintenanceConfig `mapstructure:"maintenance"`
+
+	// dir is the directory config.yaml was loaded from
+	dir string
 }
 
 // UIConfig holds UI-related configuration
@@ -35,14 +55,27 @@ type UIConfig struct {
 	AnimationSpeed int    `mapstructure:"animation_speed"`
 	ShowFPS        bool   `mapstructure:"show_fps"`
 	MouseEnabled   bool   `mapstructure:"mouse_enabled"`
+	VimMode        bool   `mapstructure:"vim_mode"`
+	// WindowTitle names the active module and any firing alert in the
+	// terminal's window and tab title
+	WindowTitle bool `mapstructure:"window_title"`
+	// TerminalNotifications posts alerts through the terminal (OSC 9):
+	// "auto" (in iTerm2, WezTerm, Ghostty and kitty), "always" or "never"
+	TerminalNotifications string `mapstructure:"terminal_notifications"`
 }
 
-// ModulesConfig holds module-specific configuration
+// ModulesConfig holds module-specific configuration. Enabled limits the
+// tabs to the modules listed (all when empty) and Order puts the listed
+// modules first; both take module keys, e.g. "quickactions".
 type ModulesConfig struct {
-	Dashboard DashboardConfig `mapstructure:"dashboard"`
-	Docker    DockerConfig    `mapstructure:"docker"`
-	Network   NetworkConfig   `mapstructure:"network"`
-	Security  SecurityConfig  `mapstructure:"security"`
+	Enabled []string `mapstructure:"enabled"`
+	Order   []string `mapstructure:"order"`
+
+	Dashboard    DashboardConfig    `mapstructure:"dashboard"`
+	Docker       DockerConfig       `mapstructure:"docker"`
+	Network      NetworkConfig      `mapstructure:"network"`
+	Security     SecurityConfig     `mapstructure:"security"`
+	QuickActions QuickActionsConfig `mapstructure:"quickactions"`
 }
 
 // DashboardConfig holds dashboard module configuration
@@ -52,6 +85,15 @@ type DashboardConfig struct {
 	ShowMemDetails  bool `mapstructure:"show_mem_details"`
 	ShowDiskDetails bool `mapstructure:"show_disk_details"`
 	GraphHeight     int  `mapstructure:"graph_height"`
+	// Widgets lists the panels to show, in order; empty shows them all
+	Widgets []string `mapstructure:"widgets"`
+	// Compact is "auto" (a line per widget in windows under 30 rows),
+	// "always" or "never"
+	Compact string `mapstructure:"compact"`
+	// CIRepos are the GitHub repositories (owner/name) whose latest
+	// Actions run the ci widget shows; empty finds them in the workspace
+	// roots
+	CIRepos []string `mapstructure:"ci_repos"`
 }
 
 // DockerConfig holds Docker module configuration
@@ -59,6 +101,21 @@ type DockerConfig struct {
 	SocketPath        string `mapstructure:"socket_path"`
 	ShowAllContainers bool   `mapstructure:"show_all_containers"`
 	AutoRefresh       bool   `mapstructure:"auto_refresh"`
+	// WorkspaceRoots are searched for Dockerfiles to build and, by the
+	// Security module, for projects' .env files
+	WorkspaceRoots []string `mapstructure:"workspace_roots"`
+}
+
+// DefaultWorkspaceRoots are searched when the config doesn't name any
+var DefaultWorkspaceRoots = []string{"~/Developer", "~/Projects", "~/code", "~/src"}
+
+// WorkspaceRoots returns the folders projects are kept in: the configured
+// roots, or the defaults
+func (c *Config) WorkspaceRoots() []string {
+	if c != nil && len(c.Modules.Docker.WorkspaceRoots) > 0 {
+		return c.Modules.Docker.WorkspaceRoots
+	}
+	return DefaultWorkspaceRoots
 }
 
 // NetworkConfig holds network module configuration
@@ -66,6 +123,9 @@ type NetworkConfig struct {
 	DefaultInterface string `mapstructure:"default_interface"`
 	PacketCapture    bool   `mapstructure:"packet_capture"`
 	PortScanTimeout  int    `mapstructure:"port_scan_timeout"`
+	// TargetLists are named lists of diagnostics targets; typing @name
+	// runs ping, traceroute or DNS against all of them at once
+	TargetLists map[string][]string `mapstructure:"target_lists"`
 }
 
 // SecurityConfig holds security module configuration
@@ -76,18 +136,145 @@ type SecurityConfig struct {
 	CheckSIP       bool `mapstructure:"check_sip"`
 }
 
+// QuickActionsConfig holds quick actions module configuration
+type QuickActionsConfig struct {
+	// WallpaperDir is the folder Next Wallpaper rotates through
+	WallpaperDir string `mapstructure:"wallpaper_dir"`
+	// FocusShortcut is the Shortcuts shortcut Toggle Do Not Disturb runs
+	FocusShortcut string `mapstructure:"focus_shortcut"`
+	// DNSPresets adds DNS servers to switch to, by name, beside
+	// Cloudflare, Google, Quad9 and DHCP
+	DNSPresets map[string][]string `mapstructure:"dns_presets"`
+}
+
 // SystemConfig holds system-related configuration
 type SystemConfig struct {
+	// CommandTimeout is the seconds a quick action may run
 	CommandTimeout int    `mapstructure:"command_timeout"`
 	MaxRetries     int    `mapstructure:"max_retries"`
 	SudoCommand    string `mapstructure:"sudo_command"`
+	// Timeouts overrides the seconds a quick action or cleanup target may
+	// run, by its name in lower case without spaces, e.g. fixspotlight
+	Timeouts map[string]int `mapstructure:"timeouts"`
+	// WorldClock lists teammates' time zones for the Region tab
+	WorldClock []WorldClock `mapstructure:"world_clock"`
+}
+
+// Timeout is how long the quick action or cleanup target named name may
+// run: its entry in Timeouts, or fallback
+func (s SystemConfig) Timeout(name string, fallback time.Duration) time.Duration {
+	if seconds := s.Timeouts[ModuleKey(name)]; seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return fallback
+}
+
+// WorldClock is a named IANA time zone, e.g. America/Sao_Paulo
+type WorldClock struct {
+	Name     string `mapstructure:"name"`
+	Timezone string `mapstructure:"timezone"`
 }
 
 // StorageConfig holds storage configuration
 type StorageConfig struct {
-	DataDir         string `mapstructure:"data_dir"`
-	MaxHistoryDays  int    `mapstructure:"max_history_days"`
-	CompressOldData bool   `mapstructure:"compress_old_data"`
+	DataDir         string          `mapstructure:"data_dir"`
+	MaxHistoryDays  int             `mapstructure:"max_history_days"`
+	CompressOldData bool            `mapstructure:"compress_old_data"`
+	Retention       RetentionConfig `mapstructure:"retention"`
+}
+
+// RetentionConfig holds per-store retention limits for data Dev Cockpit writes
+type RetentionConfig struct {
+	Metrics   RetentionPolicy `mapstructure:"metrics"`
+	Reports   RetentionPolicy `mapstructure:"reports"`
+	Snapshots RetentionPolicy `mapstructure:"snapshots"`
+	Audit     RetentionPolicy `mapstructure:"audit"`
+}
+
+// RetentionPolicy limits how much a single store may keep on disk.
+// A zero value disables that limit.
+type RetentionPolicy struct {
+	MaxAgeDays int `mapstructure:"max_age_days"`
+	MaxSizeMB  int `mapstructure:"max_size_mb"`
+}
+
+// OpenConfig holds the commands used to open a selected path. "{path}" is
+// replaced with the path; without it the path is appended.
+type OpenConfig struct {
+	FileManager string `mapstructure:"file_manager"`
+	Terminal    string `mapstructure:"terminal"`
+	Editor      string `mapstructure:"editor"`
+}
+
+// ModuleKey is how config refers to the module titled title: the title in
+// lower case without spaces, e.g. "quickactions"
+func ModuleKey(title string) string {
+	return strings.ToLower(strings.ReplaceAll(title, " ", ""))
+}
+
+// NotificationsConfig controls macOS notifications sent when a module
+// finishes a long-running task. Modules are keyed by ModuleKey.
+type NotificationsConfig struct {
+	Enabled bool            `mapstructure:"enabled"`
+	Modules map[string]bool `mapstructure:"modules"`
+}
+
+// Notify reports whether finished tasks in the module titled title should
+// send a notification
+func (n NotificationsConfig) Notify(title string) bool {
+	return n.Enabled && n.Modules[ModuleKey(title)]
+}
+
+// AlertsConfig holds threshold rules checked in the background while Dev
+// Cockpit runs
+type AlertsConfig struct {
+	// Interval is the seconds between checks
+	Interval int         `mapstructure:"interval"`
+	Rules    []AlertRule `mapstructure:"rules"`
+}
+
+// AlertRule fires when Metric (cpu, memory, disk or gpu) goes above Above
+// percent. It fires again only after the metric has dropped back below.
+type AlertRule struct {
+	Name     string         `mapstructure:"name"`
+	Metric   string         `mapstructure:"metric"`
+	Above    float64        `mapstructure:"above"`
+	Channels []AlertChannel `mapstructure:"channels"`
+}
+
+// AlertChannel is where an alert is sent: a webhook POST or a shell
+// command. Format picks the webhook payload (slack, discord or json) and
+// is guessed from the URL when empty.
+type AlertChannel struct {
+	Webhook string `mapstructure:"webhook"`
+	Format  string `mapstructure:"format"`
+	Command string `mapstructure:"command"`
+}
+
+// FleetConfig lists the Macs the Fleet tab checks over SSH. The tab is
+// only shown when there are hosts.
+type FleetConfig struct {
+	Hosts []FleetHost `mapstructure:"hosts"`
+	// Interval is the minutes between checks
+	Interval int `mapstructure:"interval"`
+}
+
+// FleetHost is a Mac reachable with `ssh SSH` without a password prompt
+type FleetHost struct {
+	Name string `mapstructure:"name"`
+	SSH  string `mapstructure:"ssh"` // e.g. admin@mini.local or a Host from ~/.ssh/config
+}
+
+// MaintenanceConfig is what `devcockpit maintain run` does. It only runs
+// on a schedule once `devcockpit maintain install` has added it to launchd.
+type MaintenanceConfig struct {
+	// Interval is the hours between runs
+	Interval    int  `mapstructure:"interval"`
+	BrewUpdate  bool `mapstructure:"brew_update"`
+	BrewCleanup bool `mapstructure:"brew_cleanup"`
+	// CompactAfterDays thins stored metrics older than this many days to
+	// a sample per five minutes; 0 leaves them as recorded
+	CompactAfterDays int `mapstructure:"compact_after_days"`
 }
 
 // Load loads configuration from file and environment
@@ -139,6 +326,7 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	config.dir = configDir
 
 	return &config, nil
 }
@@ -146,7 +334,7 @@ func Load() (*Config, error) {
 // setDefaults sets default configuration values
 func setDefaults() {
 	// General defaults
-	viper.SetDefault("theme", "dark")
+	viper.SetDefault("theme", "auto")
 	viper.SetDefault("update_interval", 1000) // 1 second in milliseconds
 	viper.SetDefault("enable_telemetry", false)
 	viper.SetDefault("log_level", "info")
@@ -156,6 +344,9 @@ func setDefaults() {
 	viper.SetDefault("ui.animation_speed", 60) // FPS
 	viper.SetDefault("ui.show_fps", false)
 	viper.SetDefault("ui.mouse_enabled", true)
+	viper.SetDefault("ui.vim_mode", false)
+	viper.SetDefault("ui.window_title", true)
+	viper.SetDefault("ui.terminal_notifications", "auto")
 
 	// Dashboard defaults
 	viper.SetDefault("modules.dashboard.refresh_rate", 1)
@@ -168,6 +359,7 @@ func setDefaults() {
 	viper.SetDefault("modules.docker.socket_path", "/var/run/docker.sock")
 	viper.SetDefault("modules.docker.show_all_containers", false)
 	viper.SetDefault("modules.docker.auto_refresh", true)
+	viper.SetDefault("modules.docker.workspace_roots", DefaultWorkspaceRoots)
 
 	// Network defaults
 	viper.SetDefault("modules.network.default_interface", "en0")
@@ -180,6 +372,10 @@ func setDefaults() {
 	viper.SetDefault("modules.security.check_filevault", true)
 	viper.SetDefault("modules.security.check_sip", true)
 
+	// Quick actions defaults
+	viper.SetDefault("modules.quickactions.wallpaper_dir", "~/Pictures/Wallpapers")
+	viper.SetDefault("modules.quickactions.focus_shortcut", "Toggle Do Not Disturb")
+
 	// System defaults
 	viper.SetDefault("system.command_timeout", 30)
 	viper.SetDefault("system.max_retries", 3)
@@ -190,6 +386,42 @@ func setDefaults() {
 	viper.SetDefault("storage.data_dir", filepath.Join(homeDir, ".devcockpit", "data"))
 	viper.SetDefault("storage.max_history_days", 30)
 	viper.SetDefault("storage.compress_old_data", true)
+
+	// Retention defaults (0 disables a limit)
+	viper.SetDefault("storage.retention.metrics.max_age_days", 30)
+	viper.SetDefault("storage.retention.metrics.max_size_mb", 100)
+	viper.SetDefault("storage.retention.reports.max_age_days", 90)
+	viper.SetDefault("storage.retention.reports.max_size_mb", 50)
+	viper.SetDefault("storage.retention.snapshots.max_age_days", 30)
+	viper.SetDefault("storage.retention.snapshots.max_size_mb", 50)
+	viper.SetDefault("storage.retention.audit.max_age_days", 180)
+	viper.SetDefault("storage.retention.audit.max_size_mb", 20)
+
+	// Open defaults
+	viper.SetDefault("open.file_manager", "open {path}")
+	viper.SetDefault("open.terminal", "open -a Terminal {path}")
+	viper.SetDefault("open.editor", "code {path}")
+
+	// Notification defaults
+	viper.SetDefault("notifications.enabled", false)
+	viper.SetDefault("notifications.modules", map[string]bool{
+		"cleanup":      true,
+		"quickactions": true,
+		"docker":       true,
+		"packages":     false,
+	})
+
+	// Alert defaults
+	viper.SetDefault("alerts.interval", 30)
+
+	// Fleet defaults
+	viper.SetDefault("fleet.interval", 5)
+
+	// Maintenance defaults
+	viper.SetDefault("maintenance.interval", 24)
+	viper.SetDefault("maintenance.brew_update", true)
+	viper.SetDefault("maintenance.brew_cleanup", true)
+	viper.SetDefault("maintenance.compact_after_days", 7)
 }
 
 // createDefaultConfig creates a default configuration file
@@ -198,36 +430,63 @@ func createDefaultConfig(configFile string) error {
 # https://devcockpit.app/docs/configuration
 
 # General Settings
-theme: dark
-update_interval: 1000
+theme: auto # auto detects the terminal background; or light, dark
+update_interval: 1000 # ms between checks for due refreshes
 enable_telemetry: false
 log_level: info
 
 # UI Settings
 ui:
-  color_scheme: cyberpunk
+  color_scheme: cyberpunk # or high-contrast, ansi (16 colors)
   animation_speed: 60
   show_fps: false
   mouse_enabled: true
+  vim_mode: false # h/l tabs, gg/G, : command line
+  window_title: true # active module and firing alerts in the tab title
+  # Alerts as terminal notifications (OSC 9): auto (iTerm2, WezTerm,
+  # Ghostty, kitty), always or never
+  terminal_notifications: auto
 
 # Module Settings
 modules:
+  # Tabs to show (all when empty) and the ones to put first, by name:
+  # dashboard, quickactions, cleanup, packages, system, docker, network,
+  # security, settings, support (and fleet, when fleet.hosts is set)
+  enabled: []
+  order: []
+
   dashboard:
-    refresh_rate: 1
+    refresh_rate: 1 # seconds between samples; + and - change it live
     show_cpu_details: true
     show_mem_details: true
     show_disk_details: true
     graph_height: 10
+    # Panels to show, in order: system, cpu, gpu, thermal, memory, disk,
+    # network, processes, battery, ci, insights (empty shows them all)
+    widgets: []
+    # A line per widget: auto (in windows under 30 rows), always or never
+    compact: auto
+    # GitHub repositories (owner/name) whose latest Actions run the ci
+    # widget shows; empty uses the checkouts in modules.docker.workspace_roots.
+    # Set GITHUB_TOKEN or log in with gh for private repos.
+    ci_repos: []
 
   docker:
     socket_path: /var/run/docker.sock
     show_all_containers: false
     auto_refresh: true
+    # Folders searched for Dockerfiles to build with b, and for projects'
+    # .env files by the Security module
+    workspace_roots: [~/Developer, ~/Projects, ~/code, ~/src]
 
   network:
     default_interface: en0
     packet_capture: false
     port_scan_timeout: 2
+    # Targets to compare side by side in Diagnostics by typing @name
+    target_lists: {}
+    # target_lists:
+    #   routes: [1.1.1.1, 192.168.1.1, intranet.example.com]
 
   security:
     scan_interval: 300
@@ -235,21 +494,115 @@ modules:
     check_filevault: true
     check_sip: true
 
+  quickactions:
+    wallpaper_dir: ~/Pictures/Wallpapers # images Next Wallpaper cycles through
+    # Shortcut with a Set Focus action (Do Not Disturb, Toggle) that Toggle
+    # Do Not Disturb runs; macOS has no other way to switch a Focus
+    focus_shortcut: Toggle Do Not Disturb
+    # More DNS servers for the DNS actions to switch to, by name
+    dns_presets: {}
+    # dns_presets:
+    #   NextDNS: [45.90.28.0, 45.90.30.0]
+
 # System Settings
 system:
-  command_timeout: 30
+  command_timeout: 30 # seconds a quick action may run
+  # Longer (or shorter) limits for single quick actions and cleanup
+  # targets, by name in lower case without spaces
+  timeouts: {}
+  # timeouts:
+  #   fixspotlight: 300
+  #   xcodederiveddata: 120
   max_retries: 3
   sudo_command: sudo
+  # Teammates' time zones, shown as a world clock on the System Region tab
+  world_clock: []
+  # world_clock:
+  #   - name: Ana
+  #     timezone: America/Sao_Paulo
 
 # Storage Settings
 storage:
   max_history_days: 30
   compress_old_data: true
+  # Per-store limits for data Dev Cockpit writes (0 disables a limit)
+  retention:
+    metrics:
+      max_age_days: 30
+      max_size_mb: 100
+    reports:
+      max_age_days: 90
+      max_size_mb: 50
+    snapshots:
+      max_age_days: 30
+      max_size_mb: 50
+    audit:
+      max_age_days: 180
+      max_size_mb: 20
+
+# Commands for opening a selected path ({path} is replaced with the path).
+# They should launch an app and return, e.g. "open -a iTerm {path}".
+open:
+  file_manager: open {path}
+  terminal: open -a Terminal {path}
+  editor: code {path}
+
+# macOS notifications when a task finishes (uses terminal-notifier if
+# installed, osascript otherwise)
+notifications:
+  enabled: false
+  modules:
+    cleanup: true
+    quickactions: true
+    docker: true
+    packages: false
+
+# Threshold alerts, checked every interval seconds even while the dashboard
+# is hidden. Each rule fires once when its metric (cpu, memory, disk, gpu)
+# goes above the threshold and shows a toast; channels also send it out.
+alerts:
+  interval: 30
+  rules: []
+  # rules:
+  #   - name: disk-full
+  #     metric: disk
+  #     above: 95
+  #     channels:
+  #       - webhook: https://hooks.slack.com/services/...
+  #       - webhook: https://discord.com/api/webhooks/...
+  #       - command: mail -s "$DEVCOCKPIT_ALERT_MESSAGE" me@example.com < /dev/null
+
+# Other Macs to summarize in the Fleet tab, checked over SSH every interval
+# minutes. Hosts need key-based login (ssh must not ask for a password).
+fleet:
+  interval: 5
+  hosts: []
+  # hosts:
+  #   - name: Mac mini
+  #     ssh: admin@mini.local
+
+# Background maintenance, every interval hours once installed with
+# devcockpit maintain install. Each run is logged to the audit store and
+# summarized when Dev Cockpit next opens.
+maintenance:
+  interval: 24
+  brew_update: true
+  brew_cleanup: true
+  compact_after_days: 7 # thin older metrics to a sample per 5 minutes; 0 keeps them
 `
 
 	return os.WriteFile(configFile, []byte(defaultConfig), 0644)
 }
 
+// Dir returns the directory holding config.yaml (usually ~/.devcockpit)
+func (c *Config) Dir() string {
+	if c.dir == "" {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, ".devcockpit")
+	}
+	return c.dir
+}
+
 // Save saves the current configuration to file
 func (c *Config) Save() error {
 	return viper.WriteConfig()
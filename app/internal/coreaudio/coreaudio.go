@@ -0,0 +1,55 @@
+// Package coreaudio lists audio devices and changes their sample rate
+// through Core Audio, which has no command line equivalent short of Audio
+// MIDI Setup. Other platforms get ErrUnsupported.
+package coreaudio
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned where Core Audio isn't available
+var ErrUnsupported = errors.New("sample rates can only be changed on macOS")
+
+// Device is an audio device
+type Device struct {
+	ID         uint32
+	Name       string
+	Input      bool
+	Output     bool
+	Default    bool // the default output device
+	SampleRate float64
+	Rates      []float64 // nominal rates the device supports
+}
+
+// FormatRate formats a sample rate in kHz, e.g. "44.1 kHz"
+func FormatRate(rate float64) string {
+	return fmt.Sprintf("%g kHz", rate/1000)
+}
+
+// commonRates are offered for devices that report a continuous range
+var commonRates = []float64{44100, 48000, 88200, 96000, 176400, 192000}
+
+// expandRanges turns the reported rate ranges into a list of rates
+func expandRanges(mins, maxs []float64) []float64 {
+	var rates []float64
+	seen := make(map[float64]bool)
+	add := func(rate float64) {
+		if !seen[rate] {
+			seen[rate] = true
+			rates = append(rates, rate)
+		}
+	}
+	for i := range mins {
+		if mins[i] == maxs[i] {
+			add(mins[i])
+			continue
+		}
+		for _, rate := range commonRates {
+			if rate >= mins[i] && rate <= maxs[i] {
+				add(rate)
+			}
+		}
+	}
+	return rates
+}
@@ -0,0 +1,146 @@
+//go:build darwin && cgo
+
+package coreaudio
+
+/*
+#cgo LDFLAGS: -framework CoreAudio -framework CoreFoundation
+#include <CoreAudio/CoreAudio.h>
+#include <CoreFoundation/CoreFoundation.h>
+
+static AudioObjectPropertyAddress dc_address(AudioObjectPropertySelector selector, AudioObjectPropertyScope scope) {
+	AudioObjectPropertyAddress address = { selector, scope, 0 };
+	return address;
+}
+
+// dc_devices fills ids and returns the number of devices, or -1
+static int dc_devices(AudioObjectID *ids, int max) {
+	AudioObjectPropertyAddress address = dc_address(kAudioHardwarePropertyDevices, kAudioObjectPropertyScopeGlobal);
+	UInt32 size = max * sizeof(AudioObjectID);
+	if (AudioObjectGetPropertyData(kAudioObjectSystemObject, &address, 0, NULL, &size, ids) != noErr) {
+		return -1;
+	}
+	return size / sizeof(AudioObjectID);
+}
+
+static AudioObjectID dc_default_output(void) {
+	AudioObjectPropertyAddress address = dc_address(kAudioHardwarePropertyDefaultOutputDevice, kAudioObjectPropertyScopeGlobal);
+	AudioObjectID id = kAudioObjectUnknown;
+	UInt32 size = sizeof(id);
+	AudioObjectGetPropertyData(kAudioObjectSystemObject, &address, 0, NULL, &size, &id);
+	return id;
+}
+
+static int dc_name(AudioObjectID id, char *buf, int len) {
+	AudioObjectPropertyAddress address = dc_address(kAudioObjectPropertyName, kAudioObjectPropertyScopeGlobal);
+	CFStringRef name = NULL;
+	UInt32 size = sizeof(name);
+	if (AudioObjectGetPropertyData(id, &address, 0, NULL, &size, &name) != noErr || name == NULL) {
+		return 0;
+	}
+	Boolean ok = CFStringGetCString(name, buf, len, kCFStringEncodingUTF8);
+	CFRelease(name);
+	return ok;
+}
+
+// dc_has_streams reports whether the device has input or output streams
+static int dc_has_streams(AudioObjectID id, int output) {
+	AudioObjectPropertyScope scope = output ? kAudioObjectPropertyScopeOutput : kAudioObjectPropertyScopeInput;
+	AudioObjectPropertyAddress address = dc_address(kAudioDevicePropertyStreams, scope);
+	UInt32 size = 0;
+	if (AudioObjectGetPropertyDataSize(id, &address, 0, NULL, &size) != noErr) {
+		return 0;
+	}
+	return size > 0;
+}
+
+static double dc_rate(AudioObjectID id) {
+	AudioObjectPropertyAddress address = dc_address(kAudioDevicePropertyNominalSampleRate, kAudioObjectPropertyScopeGlobal);
+	Float64 rate = 0;
+	UInt32 size = sizeof(rate);
+	AudioObjectGetPropertyData(id, &address, 0, NULL, &size, &rate);
+	return rate;
+}
+
+// dc_rates fills the supported rate ranges and returns how many there are
+static int dc_rates(AudioObjectID id, double *mins, double *maxs, int max) {
+	AudioObjectPropertyAddress address = dc_address(kAudioDevicePropertyAvailableNominalSampleRates, kAudioObjectPropertyScopeGlobal);
+	AudioValueRange ranges[32];
+	UInt32 size = sizeof(ranges);
+	if (AudioObjectGetPropertyData(id, &address, 0, NULL, &size, ranges) != noErr) {
+		return 0;
+	}
+	int n = size / sizeof(AudioValueRange);
+	if (n > max) {
+		n = max;
+	}
+	for (int i = 0; i < n; i++) {
+		mins[i] = ranges[i].mMinimum;
+		maxs[i] = ranges[i].mMaximum;
+	}
+	return n;
+}
+
+static OSStatus dc_set_rate(AudioObjectID id, double rate) {
+	AudioObjectPropertyAddress address = dc_address(kAudioDevicePropertyNominalSampleRate, kAudioObjectPropertyScopeGlobal);
+	Float64 value = rate;
+	return AudioObjectSetPropertyData(id, &address, 0, NULL, sizeof(value), &value);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+const (
+	maxDevices = 64
+	maxRanges  = 32
+)
+
+// Devices lists the audio devices with their sample rates
+func Devices() ([]Device, error) {
+	ids := make([]C.AudioObjectID, maxDevices)
+	count := int(C.dc_devices(&ids[0], C.int(len(ids))))
+	if count < 0 {
+		return nil, errors.New("failed to list audio devices")
+	}
+	defaultOutput := C.dc_default_output()
+
+	devices := make([]Device, 0, count)
+	for _, id := range ids[:count] {
+		device := Device{
+			ID:         uint32(id),
+			Input:      C.dc_has_streams(id, 0) != 0,
+			Output:     C.dc_has_streams(id, 1) != 0,
+			Default:    id == defaultOutput,
+			SampleRate: float64(C.dc_rate(id)),
+		}
+
+		name := make([]byte, 256)
+		if C.dc_name(id, (*C.char)(unsafe.Pointer(&name[0])), C.int(len(name))) != 0 {
+			device.Name = C.GoString((*C.char)(unsafe.Pointer(&name[0])))
+		}
+
+		mins := make([]C.double, maxRanges)
+		maxs := make([]C.double, maxRanges)
+		n := int(C.dc_rates(id, &mins[0], &maxs[0], C.int(maxRanges)))
+		lo, hi := make([]float64, n), make([]float64, n)
+		for i := 0; i < n; i++ {
+			lo[i], hi[i] = float64(mins[i]), float64(maxs[i])
+		}
+		device.Rates = expandRanges(lo, hi)
+
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// SetSampleRate changes the nominal sample rate of the device
+func SetSampleRate(id uint32, rate float64) error {
+	if status := C.dc_set_rate(C.AudioObjectID(id), C.double(rate)); status != 0 {
+		return fmt.Errorf("Core Audio refused %s (status %d)", FormatRate(rate), int(status))
+	}
+	return nil
+}
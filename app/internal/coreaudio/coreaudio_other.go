@@ -0,0 +1,13 @@
+//go:build !darwin || !cgo
+
+package coreaudio
+
+// Devices lists the audio devices with their sample rates
+func Devices() ([]Device, error) {
+	return nil, ErrUnsupported
+}
+
+// SetSampleRate changes the nominal sample rate of the device
+func SetSampleRate(id uint32, rate float64) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,23 @@
+package coreaudio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandRanges(t *testing.T) {
+	got := expandRanges([]float64{44100, 48000, 8000, 48000}, []float64{44100, 48000, 96000, 48000})
+	want := []float64{44100, 48000, 88200, 96000}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rates = %v, want %v", got, want)
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	if got := FormatRate(44100); got != "44.1 kHz" {
+		t.Errorf("FormatRate(44100) = %q", got)
+	}
+	if got := FormatRate(48000); got != "48 kHz" {
+		t.Errorf("FormatRate(48000) = %q", got)
+	}
+}
@@ -0,0 +1,105 @@
+// Package cpusample measures CPU usage in the background. cpu.Percent
+// blocks for the whole interval it measures over, which held up every
+// refresh that asked for it by a second; a Sampler instead reads the
+// cumulative CPU times from a scheduler task and keeps the usage between
+// the last two readings, so asking for it returns at once. Like any task,
+// it pauses while the module that owns it is hidden.
+package cpusample
+
+import (
+	"sync"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// Sampler keeps the CPU usage of each core between its last two readings
+type Sampler struct {
+	read func() ([]cpu.TimesStat, error)
+
+	mu    sync.Mutex
+	prev  []cpu.TimesStat
+	cores []float64 // percent, per core
+}
+
+// Default is the sampler of the modules showing live CPU usage, read once
+// a second by their Task while one of them is shown
+var Default = New(Times)
+
+// Every is how often Task reads the CPU times
+const Every = time.Second
+
+// Times reads the CPU times of every core
+func Times() ([]cpu.TimesStat, error) {
+	return cpu.Times(true)
+}
+
+// New returns a sampler that calls read on each Sample
+func New(read func() ([]cpu.TimesStat, error)) *Sampler {
+	return &Sampler{read: read}
+}
+
+// Task samples s every second while the module scheduling it is shown.
+// Modules showing the same sampler each schedule it; a shown module keeps
+// it going.
+func (s *Sampler) Task() scheduler.Task {
+	return scheduler.Task{Name: "cpu", Every: Every, Run: func() tea.Cmd {
+		return func() tea.Msg {
+			s.Sample()
+			return nil
+		}
+	}}
+}
+
+// Cores returns the usage of each core as of the last reading; nil until
+// the sampler has read twice
+func (s *Sampler) Cores() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]float64(nil), s.cores...)
+}
+
+// Total returns the usage of all cores together, or false until the
+// sampler has read twice
+func (s *Sampler) Total() (float64, bool) {
+	cores := s.Cores()
+	if len(cores) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, core := range cores {
+		sum += core
+	}
+	return sum / float64(len(cores)), true
+}
+
+// Sample reads the CPU times and updates the usage since the reading
+// before. A failed reading keeps the last usage.
+func (s *Sampler) Sample() {
+	times, err := s.read()
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.prev) == len(times) {
+		s.cores = make([]float64, len(times))
+		for i := range times {
+			s.cores[i] = busy(s.prev[i], times[i])
+		}
+	}
+	s.prev = times
+}
+
+// busy is the percentage of the time between two readings of a core that
+// it spent on anything but idling
+func busy(before, after cpu.TimesStat) float64 {
+	total := after.Total() - before.Total()
+	idle := (after.Idle + after.Iowait) - (before.Idle + before.Iowait)
+	if total <= 0 {
+		return 0
+	}
+	return min(max((total-idle)/total*100, 0), 100)
+}
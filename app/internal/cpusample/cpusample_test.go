@@ -0,0 +1,61 @@
+package cpusample
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+func TestSample(t *testing.T) {
+	readings := [][]cpu.TimesStat{
+		{{User: 10, System: 5, Idle: 85}, {User: 1, Idle: 99}},
+		// The first core busy for 3 of 4 seconds, the second idle
+		{{User: 12, System: 6, Idle: 86}, {User: 1, Idle: 103}},
+	}
+	var fail bool
+	s := New(func() ([]cpu.TimesStat, error) {
+		if fail {
+			return nil, errors.New("host_processor_info failed")
+		}
+		times := readings[0]
+		readings = readings[1:]
+		return times, nil
+	})
+
+	s.Sample()
+	if total, ok := s.Total(); ok {
+		t.Fatalf("Total() = %v after one reading, want nothing until two", total)
+	}
+	s.Sample()
+	if got := s.Cores(); !reflect.DeepEqual(got, []float64{75, 0}) {
+		t.Errorf("Cores() = %v, want [75 0]", got)
+	}
+	if total, ok := s.Total(); !ok || total != 37.5 {
+		t.Errorf("Total() = %v, %v, want the average of the cores", total, ok)
+	}
+
+	fail = true
+	s.Sample()
+	if got := s.Cores(); !reflect.DeepEqual(got, []float64{75, 0}) {
+		t.Errorf("a failed reading should keep the last usage, got %v", got)
+	}
+}
+
+func TestTask(t *testing.T) {
+	var reads int
+	s := New(func() ([]cpu.TimesStat, error) {
+		reads++
+		return []cpu.TimesStat{{User: float64(reads), Idle: 1}}, nil
+	})
+	task := s.Task()
+	if task.Background || task.Every != Every {
+		t.Errorf("task = %+v, want it every second and paused while hidden", task)
+	}
+	task.Run()()
+	task.Run()()
+	if reads != 2 || len(s.Cores()) != 1 {
+		t.Errorf("reads = %d cores = %v, want each run to sample", reads, s.Cores())
+	}
+}
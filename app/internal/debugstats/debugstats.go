@@ -0,0 +1,84 @@
+// Package debugstats reads Go runtime statistics for the hidden debug
+// overlay and serves pprof on a loopback address, so leaks in long-running
+// sessions (goroutines left behind by tick loops, a growing heap) can be
+// diagnosed without rebuilding.
+package debugstats
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// DefaultAddr is where --pprof listens without an explicit address
+const DefaultAddr = "localhost:6060"
+
+// Stats is a snapshot of the runtime
+type Stats struct {
+	Taken       time.Time
+	Goroutines  int
+	HeapAlloc   uint64 // bytes of live and not yet swept objects
+	HeapInuse   uint64
+	HeapObjects uint64
+	Sys         uint64 // total bytes obtained from the OS
+	NumGC       uint32
+	LastGC      time.Time
+	LastPause   time.Duration
+	PauseTotal  time.Duration
+}
+
+// Read takes a snapshot. It briefly stops the world, so call it at most
+// every second or so.
+func Read() Stats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := Stats{
+		Taken:       time.Now(),
+		Goroutines:  runtime.NumGoroutine(),
+		HeapAlloc:   mem.HeapAlloc,
+		HeapInuse:   mem.HeapInuse,
+		HeapObjects: mem.HeapObjects,
+		Sys:         mem.Sys,
+		NumGC:       mem.NumGC,
+		PauseTotal:  time.Duration(mem.PauseTotalNs),
+	}
+	if mem.NumGC > 0 {
+		stats.LastGC = time.Unix(0, int64(mem.LastGC))
+		stats.LastPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256])
+	}
+	return stats
+}
+
+// Serve starts the pprof handlers on addr in the background and returns
+// the address it listens on. Only loopback addresses are accepted, since
+// profiles expose command lines and memory contents.
+func Serve(addr string) (string, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if host != "localhost" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			return "", fmt.Errorf("pprof must listen on a loopback address, not %q", host)
+		}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	go http.Serve(listener, mux)
+
+	return listener.Addr().String(), nil
+}
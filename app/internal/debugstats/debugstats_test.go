@@ -0,0 +1,32 @@
+package debugstats
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestServeOnlyOnLoopback(t *testing.T) {
+	if _, err := Serve("0.0.0.0:0"); err == nil {
+		t.Error("Serve accepted a non-loopback address")
+	}
+
+	addr, err := Serve("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get("http://" + addr + "/debug/pprof/goroutine?debug=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("goroutine profile: status %d", resp.StatusCode)
+	}
+}
+
+func TestRead(t *testing.T) {
+	stats := Read()
+	if stats.Goroutines < 1 || stats.Sys == 0 || stats.Taken.IsZero() {
+		t.Errorf("Read() = %+v", stats)
+	}
+}
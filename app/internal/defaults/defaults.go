@@ -0,0 +1,125 @@
+// Package defaults reads and writes macOS user defaults with the defaults
+// CLI. Read reports whether a key was set at all, so a change can be undone
+// by writing the old value back or deleting the key again.
+package defaults
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// Type is the value type a key is written with
+type Type string
+
+const (
+	String Type = "string"
+	Bool   Type = "bool"
+	Int    Type = "int"
+	Float  Type = "float"
+)
+
+// Key is one defaults key and the type of its value
+type Key struct {
+	Domain string
+	Name   string
+	Type   Type
+}
+
+// String returns the key as "domain name"
+func (k Key) String() string {
+	return k.Domain + " " + k.Name
+}
+
+// Value is what a key holds. Set is false when the key isn't set, so macOS
+// uses its built-in default; booleans read as "true" or "false".
+type Value struct {
+	Text string
+	Set  bool
+}
+
+// Read returns the value of key, or an unset Value when it doesn't exist
+func Read(r runner.Runner, key Key) (Value, error) {
+	output, err := r.CombinedOutput(exec.Command("defaults", "read", key.Domain, key.Name))
+	text := strings.TrimSpace(string(output))
+	if err != nil {
+		if strings.Contains(text, "does not exist") {
+			return Value{}, nil
+		}
+		return Value{}, fmt.Errorf("defaults read %s: %s", key, message(text, err))
+	}
+	if key.Type == Bool {
+		text = normalizeBool(text)
+	}
+	return Value{Text: text, Set: true}, nil
+}
+
+// Write sets key to value
+func Write(r runner.Runner, key Key, value string) error {
+	args := WriteArgs(key, value)
+	output, err := r.CombinedOutput(exec.Command(args[0], args[1:]...))
+	if err != nil {
+		return fmt.Errorf("defaults write %s: %s", key, message(strings.TrimSpace(string(output)), err))
+	}
+	return nil
+}
+
+// WriteArgs is the command line Write runs
+func WriteArgs(key Key, value string) []string {
+	return []string{"defaults", "write", key.Domain, key.Name, "-" + string(key.Type), value}
+}
+
+// Delete removes key so macOS falls back to its default. A key that isn't
+// set is not an error.
+func Delete(r runner.Runner, key Key) error {
+	args := DeleteArgs(key)
+	output, err := r.CombinedOutput(exec.Command(args[0], args[1:]...))
+	text := strings.TrimSpace(string(output))
+	if err != nil && !strings.Contains(text, "does not exist") {
+		return fmt.Errorf("defaults delete %s: %s", key, message(text, err))
+	}
+	return nil
+}
+
+// DeleteArgs is the command line Delete runs
+func DeleteArgs(key Key) []string {
+	return []string{"defaults", "delete", key.Domain, key.Name}
+}
+
+// RestoreArgs is the command line Restore runs
+func RestoreArgs(key Key, value Value) []string {
+	if !value.Set {
+		return DeleteArgs(key)
+	}
+	return WriteArgs(key, value.Text)
+}
+
+// Restore puts back a value returned by Read: it writes a value that was
+// set and deletes the key if it wasn't
+func Restore(r runner.Runner, key Key, value Value) error {
+	if !value.Set {
+		return Delete(r, key)
+	}
+	return Write(r, key, value.Text)
+}
+
+// normalizeBool turns the 1/0 defaults prints for booleans into true/false
+func normalizeBool(text string) string {
+	switch strings.ToLower(text) {
+	case "1", "true", "yes":
+		return "true"
+	default:
+		return "false"
+	}
+}
+
+// message is the command's output, or err when it printed nothing
+func message(output string, err error) string {
+	if output == "" {
+		return err.Error()
+	}
+	line, _, _ := strings.Cut(output, "\n")
+	return line
+}
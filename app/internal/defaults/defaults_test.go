@@ -0,0 +1,59 @@
+package defaults
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+var (
+	location = Key{Domain: "com.apple.screencapture", Name: "location", Type: String}
+	shadow   = Key{Domain: "com.apple.screencapture", Name: "disable-shadow", Type: Bool}
+)
+
+func TestRead(t *testing.T) {
+	exit1 := errors.New("exit status 1")
+	fake := runner.NewFake().
+		Set("defaults read com.apple.screencapture location", "/Users/caio/Screenshots\n", nil).
+		Set("defaults read com.apple.screencapture disable-shadow", "2024-07-01 defaults[123] \nThe domain/default pair of (com.apple.screencapture, disable-shadow) does not exist\n", exit1)
+
+	value, err := Read(fake, location)
+	if err != nil || value != (Value{Text: "/Users/caio/Screenshots", Set: true}) {
+		t.Errorf("location = %+v, %v", value, err)
+	}
+	value, err = Read(fake, shadow)
+	if err != nil || value.Set {
+		t.Errorf("a missing key should read as unset, got %+v, %v", value, err)
+	}
+
+	fake.Set("defaults read com.apple.screencapture disable-shadow", "1\n", nil)
+	if value, _ := Read(fake, shadow); value.Text != "true" {
+		t.Errorf("bool 1 read as %q, want true", value.Text)
+	}
+
+	fake.Set("defaults read com.apple.screencapture location", "", errors.New("signal: killed"))
+	if _, err := Read(fake, location); err == nil || err.Error() != "defaults read com.apple.screencapture location: signal: killed" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	fake := runner.NewFake().
+		Set("defaults write com.apple.screencapture disable-shadow -bool true", "", nil).
+		Set("defaults delete com.apple.screencapture disable-shadow", "Domain (com.apple.screencapture) not found.\nDefaults have not been changed.\nThe key does not exist", errors.New("exit status 1"))
+
+	if err := Restore(fake, shadow, Value{Text: "true", Set: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(fake, shadow, Value{}); err != nil {
+		t.Errorf("deleting a missing key: %v", err)
+	}
+	want := []string{
+		"defaults write com.apple.screencapture disable-shadow -bool true",
+		"defaults delete com.apple.screencapture disable-shadow",
+	}
+	if calls := fake.Calls(); len(calls) != 2 || calls[0] != want[0] || calls[1] != want[1] {
+		t.Errorf("calls = %q", calls)
+	}
+}
@@ -0,0 +1,71 @@
+// Package export writes a module's current data to a Markdown file under
+// ~/.devcockpit/exports, for attaching to bug reports and tickets.
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+)
+
+// DirName is the exports directory inside the config directory
+const DirName = "exports"
+
+// Exporter is implemented by modules whose data can be exported. Export
+// returns Markdown, or "" when there's nothing loaded yet.
+type Exporter interface {
+	Export() string
+}
+
+// Dir returns the exports directory for cfg
+func Dir(cfg *config.Config) string {
+	return filepath.Join(cfg.Dir(), DirName)
+}
+
+// Write saves body under dir as <module>-<timestamp>.md with a header
+// naming the module, the time and the version, and returns the file path
+func Write(dir, module, version, body string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.md", config.ModuleKey(module), now.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Dev Cockpit: %s\n\n", module)
+	fmt.Fprintf(&b, "Exported %s from Dev Cockpit v%s\n\n", now.Format("2006-01-02 15:04:05 MST"), version)
+	b.WriteString(strings.TrimSpace(body))
+	b.WriteString("\n")
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Table renders rows as a Markdown table under header. Pipes in cells are
+// escaped so they don't split columns.
+func Table(header []string, rows [][]string) string {
+	var b strings.Builder
+	line := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+	}
+	line(header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	line(separator)
+	for _, row := range rows {
+		line(row)
+	}
+	return b.String()
+}
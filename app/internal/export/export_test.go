@@ -0,0 +1,41 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "exports")
+	now := time.Date(2024, 7, 1, 9, 30, 5, 0, time.UTC)
+
+	path, err := Write(dir, "Quick Actions", "1.2.0", "\nbody\n\n", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "quickactions-20240701-093005.md"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Dev Cockpit: Quick Actions\n\nExported 2024-07-01 09:30:05 UTC from Dev Cockpit v1.2.0\n\nbody\n"
+	if string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+}
+
+func TestTableEscapesPipes(t *testing.T) {
+	table := Table([]string{"Name", "Value"}, [][]string{{"a|b", "1"}})
+	want := "| Name | Value |\n| --- | --- |\n| a\\|b | 1 |\n"
+	if table != want {
+		t.Errorf("table = %q, want %q", table, want)
+	}
+	if strings.Count(table, "\n") != 3 {
+		t.Error("want a header, separator and one row")
+	}
+}
@@ -0,0 +1,247 @@
+// Package exporter serves the dashboard metrics in the Prometheus text
+// exposition format, so a Mac can be scraped into Grafana alongside
+// servers. Metrics are read fresh on every scrape.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/thermal"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	psnet "github.com/shirou/gopsutil/v3/net"
+)
+
+// DefaultAddr is where serve listens without an address, the port the
+// Prometheus node exporter uses
+const DefaultAddr = ":9100"
+
+// Metric is one metric family
+type Metric struct {
+	Name    string
+	Help    string
+	Type    string // gauge or counter
+	Samples []Sample
+}
+
+// Sample is a value with its labels, in order
+type Sample struct {
+	Labels [][2]string
+	Value  float64
+}
+
+// gauge is a family with a single unlabelled sample
+func gauge(name, help string, value float64) Metric {
+	return Metric{Name: name, Help: help, Type: "gauge", Samples: []Sample{{Value: value}}}
+}
+
+// Write prints metrics in the text exposition format
+func Write(w io.Writer, metrics []Metric) error {
+	for _, m := range metrics {
+		if len(m.Samples) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.Name, m.Help, m.Name, m.Type); err != nil {
+			return err
+		}
+		for _, s := range m.Samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", m.Name, formatLabels(s.Labels), strconv.FormatFloat(s.Value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func formatLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = fmt.Sprintf(`%s="%s"`, l[0], escape.Replace(l[1]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// Collect reads every metric. What can't be read, like temperatures
+// without smctemp or a sudo session, or the battery of a desktop Mac, is
+// left out rather than reported as zero.
+func Collect(r runner.Runner) []Metric {
+	var metrics []Metric
+
+	if percents, err := cpu.Percent(500*time.Millisecond, true); err == nil && len(percents) > 0 {
+		cores := Metric{Name: "devcockpit_cpu_core_usage_percent", Help: "CPU usage of each core.", Type: "gauge"}
+		var total float64
+		for i, p := range percents {
+			cores.Samples = append(cores.Samples, Sample{Labels: [][2]string{{"core", strconv.Itoa(i)}}, Value: p})
+			total += p
+		}
+		metrics = append(metrics, gauge("devcockpit_cpu_usage_percent", "CPU usage averaged over all cores.", total/float64(len(percents))), cores)
+	}
+
+	if vm, err := mem.VirtualMemory(); err == nil {
+		metrics = append(metrics,
+			gauge("devcockpit_memory_total_bytes", "Physical memory.", float64(vm.Total)),
+			gauge("devcockpit_memory_used_bytes", "Physical memory in use.", float64(vm.Used)),
+			gauge("devcockpit_memory_used_percent", "Physical memory in use, as a percentage.", vm.UsedPercent),
+		)
+	}
+	if swap, err := mem.SwapMemory(); err == nil {
+		metrics = append(metrics, gauge("devcockpit_swap_used_bytes", "Swap in use.", float64(swap.Used)))
+	}
+	if out, err := r.Output(exec.Command("sysctl", "-n", "kern.memorystatus_vm_pressure_level")); err == nil {
+		if level, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil {
+			metrics = append(metrics, gauge("devcockpit_memory_pressure_level", "Kernel memory pressure: 1 normal, 2 warning, 4 critical.", float64(level)))
+		}
+	}
+
+	if usage, err := disk.Usage("/"); err == nil {
+		metrics = append(metrics,
+			gauge("devcockpit_disk_total_bytes", "Size of the startup volume.", float64(usage.Total)),
+			gauge("devcockpit_disk_used_bytes", "Space used on the startup volume.", float64(usage.Used)),
+			gauge("devcockpit_disk_used_percent", "Space used on the startup volume, as a percentage.", usage.UsedPercent),
+		)
+	}
+	if counters, err := disk.IOCounters(); err == nil {
+		metrics = append(metrics, diskIOMetrics(counters)...)
+	}
+
+	if counters, err := psnet.IOCounters(true); err == nil {
+		metrics = append(metrics, networkMetrics(counters)...)
+	}
+
+	if out, err := r.Output(exec.Command("pmset", "-g", "batt")); err == nil {
+		metrics = append(metrics, batteryMetrics(string(out))...)
+	}
+
+	if reading, err := thermal.Read(r); err == nil {
+		metrics = append(metrics, thermalMetrics(reading)...)
+	}
+	return metrics
+}
+
+// diskIOMetrics are the cumulative I/O counters of each disk; rate() over
+// them gives the throughput and IOPS the dashboard shows
+func diskIOMetrics(counters map[string]disk.IOCountersStat) []Metric {
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	read := Metric{Name: "devcockpit_disk_read_bytes_total", Help: "Bytes read from each disk.", Type: "counter"}
+	written := Metric{Name: "devcockpit_disk_written_bytes_total", Help: "Bytes written to each disk.", Type: "counter"}
+	reads := Metric{Name: "devcockpit_disk_reads_total", Help: "Read operations on each disk.", Type: "counter"}
+	writes := Metric{Name: "devcockpit_disk_writes_total", Help: "Write operations on each disk.", Type: "counter"}
+	for _, name := range names {
+		c, labels := counters[name], [][2]string{{"disk", name}}
+		read.Samples = append(read.Samples, Sample{Labels: labels, Value: float64(c.ReadBytes)})
+		written.Samples = append(written.Samples, Sample{Labels: labels, Value: float64(c.WriteBytes)})
+		reads.Samples = append(reads.Samples, Sample{Labels: labels, Value: float64(c.ReadCount)})
+		writes.Samples = append(writes.Samples, Sample{Labels: labels, Value: float64(c.WriteCount)})
+	}
+	return []Metric{read, written, reads, writes}
+}
+
+// networkMetrics are the byte counters of each interface that has moved
+// any traffic; rate() over them gives the dashboard's down and up rates
+func networkMetrics(counters []psnet.IOCountersStat) []Metric {
+	received := Metric{Name: "devcockpit_network_received_bytes_total", Help: "Bytes received on each interface.", Type: "counter"}
+	sent := Metric{Name: "devcockpit_network_sent_bytes_total", Help: "Bytes sent on each interface.", Type: "counter"}
+	for _, c := range counters {
+		if c.BytesRecv == 0 && c.BytesSent == 0 {
+			continue
+		}
+		labels := [][2]string{{"interface", c.Name}}
+		received.Samples = append(received.Samples, Sample{Labels: labels, Value: float64(c.BytesRecv)})
+		sent.Samples = append(sent.Samples, Sample{Labels: labels, Value: float64(c.BytesSent)})
+	}
+	return []Metric{received, sent}
+}
+
+// batteryLine is the InternalBattery line of pmset -g batt, e.g.
+// " -InternalBattery-0 (id=4653155)	87%; charging; 1:05 remaining"
+var batteryLine = regexp.MustCompile(`InternalBattery.*?(\d+)%;\s*([^;]+);`)
+
+// batteryMetrics reads the charge and power source from pmset -g batt;
+// nothing on Macs without a battery
+func batteryMetrics(output string) []Metric {
+	match := batteryLine.FindStringSubmatch(output)
+	if match == nil {
+		return nil
+	}
+	percent, _ := strconv.Atoi(match[1])
+	onAC, charging := 0.0, 0.0
+	if strings.Contains(output, "'AC Power'") {
+		onAC = 1
+	}
+	if strings.TrimSpace(match[2]) == "charging" {
+		charging = 1
+	}
+	return []Metric{
+		gauge("devcockpit_battery_charge_percent", "Battery charge.", float64(percent)),
+		gauge("devcockpit_battery_charging", "1 while the battery is charging.", charging),
+		gauge("devcockpit_power_adapter_connected", "1 while running on AC power.", onAC),
+	}
+}
+
+// thermalMetrics are the temperatures and fan speed that were reported
+func thermalMetrics(reading thermal.Reading) []Metric {
+	var metrics []Metric
+	if reading.CPU > 0 {
+		metrics = append(metrics, gauge("devcockpit_cpu_temperature_celsius", "CPU die temperature.", reading.CPU))
+	}
+	if reading.GPU > 0 {
+		metrics = append(metrics, gauge("devcockpit_gpu_temperature_celsius", "GPU die temperature.", reading.GPU))
+	}
+	if reading.FanRPM > 0 {
+		metrics = append(metrics, gauge("devcockpit_fan_speed_rpm", "Fan speed.", float64(reading.FanRPM)))
+	}
+	return metrics
+}
+
+// Handler serves the metrics, read with r on each request
+func Handler(r runner.Runner) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := Write(w, Collect(r)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Serve answers scrapes on /metrics at addr in the background and returns
+// the address it listens on. Unlike pprof, any address is accepted: a
+// Prometheus server on another machine has to reach it.
+func Serve(addr string, r runner.Runner) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(r))
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(w, req)
+			return
+		}
+		fmt.Fprintln(w, "Dev Cockpit metrics exporter: scrape /metrics")
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+
+	return listener.Addr().String(), nil
+}
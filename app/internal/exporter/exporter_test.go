@@ -0,0 +1,76 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+)
+
+func TestWrite(t *testing.T) {
+	metrics := []Metric{
+		gauge("devcockpit_cpu_usage_percent", "CPU usage averaged over all cores.", 12.5),
+		{Name: "devcockpit_empty", Help: "Left out.", Type: "gauge"},
+	}
+	metrics = append(metrics, networkMetrics([]psnet.IOCountersStat{
+		{Name: "en0", BytesRecv: 1500, BytesSent: 300},
+		{Name: "utun3"},
+		{Name: `we"ird`, BytesRecv: 1},
+	})...)
+
+	var b strings.Builder
+	if err := Write(&b, metrics); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP devcockpit_cpu_usage_percent CPU usage averaged over all cores.
+# TYPE devcockpit_cpu_usage_percent gauge
+devcockpit_cpu_usage_percent 12.5
+# HELP devcockpit_network_received_bytes_total Bytes received on each interface.
+# TYPE devcockpit_network_received_bytes_total counter
+devcockpit_network_received_bytes_total{interface="en0"} 1500
+devcockpit_network_received_bytes_total{interface="we\"ird"} 1
+# HELP devcockpit_network_sent_bytes_total Bytes sent on each interface.
+# TYPE devcockpit_network_sent_bytes_total counter
+devcockpit_network_sent_bytes_total{interface="en0"} 300
+devcockpit_network_sent_bytes_total{interface="we\"ird"} 0
+`
+	if b.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestBatteryMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []float64 // charge, charging, on AC
+	}{
+		{
+			name:   "charging",
+			output: "Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t87%; charging; 1:05 remaining present: true\n",
+			want:   []float64{87, 1, 1},
+		},
+		{
+			name:   "on battery",
+			output: "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t41%; discharging; 3:12 remaining present: true\n",
+			want:   []float64{41, 0, 0},
+		},
+		{
+			name:   "desktop",
+			output: "Now drawing from 'AC Power'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := batteryMetrics(tt.output)
+			if len(metrics) != len(tt.want) {
+				t.Fatalf("got %d metrics, want %d", len(metrics), len(tt.want))
+			}
+			for i, m := range metrics {
+				if got := m.Samples[0].Value; got != tt.want[i] {
+					t.Errorf("%s = %v, want %v", m.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
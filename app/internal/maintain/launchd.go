@@ -0,0 +1,141 @@
+package maintain
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// Label names the launch agent
+const Label = "app.devcockpit.maintain"
+
+// agentPath is the agent's PATH. launchd's own leaves out Homebrew, so
+// both of its prefixes are added.
+const agentPath = "/opt/homebrew/bin:/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin"
+
+// ErrNotInstalled is returned by Uninstall when there is no agent
+var ErrNotInstalled = errors.New("the maintenance agent isn't installed")
+
+// PlistPath is the launch agent's plist in ~/Library/LaunchAgents
+func PlistPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "Library", "LaunchAgents", Label+".plist")
+}
+
+// Installed reports whether the launch agent is in place
+func Installed() bool {
+	_, err := os.Stat(PlistPath())
+	return err == nil
+}
+
+// Interval is the time between runs set by maintenance.interval
+func Interval(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.Maintenance.Interval <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(cfg.Maintenance.Interval) * time.Hour
+}
+
+// Plist is the launch agent running `executable maintain run` every
+// interval, with its output appended to logPath
+func Plist(executable string, interval time.Duration, logPath string) string {
+	escape := func(s string) string {
+		var b bytes.Buffer
+		_ = xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>maintain</string>
+		<string>run</string>
+	</array>
+	<key>StartInterval</key>
+	<integer>%d</integer>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>PATH</key>
+		<string>%s</string>
+	</dict>
+	<key>ProcessType</key>
+	<string>Background</string>
+	<key>LowPriorityIO</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, Label, escape(executable), int(interval.Seconds()), agentPath, escape(logPath), escape(logPath))
+}
+
+// Install writes the launch agent for this executable and loads it,
+// replacing an agent installed before
+func Install(cfg *config.Config, r runner.Runner) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	path := PlistPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if Installed() {
+		_ = launchctl(r, "unload", path)
+	}
+	plist := Plist(executable, Interval(cfg), filepath.Join(cfg.Dir(), "maintain.log"))
+	if err := os.WriteFile(path, []byte(plist), 0644); err != nil {
+		return "", err
+	}
+	if err := launchctl(r, "load", "-w", path); err != nil {
+		return "", err
+	}
+	logger.Info("Installed the maintenance agent at %s", path)
+	return path, nil
+}
+
+// Uninstall unloads the launch agent and removes its plist
+func Uninstall(r runner.Runner) error {
+	path := PlistPath()
+	if !Installed() {
+		return ErrNotInstalled
+	}
+	if err := launchctl(r, "unload", "-w", path); err != nil {
+		logger.Warn("Maintenance: %v", err)
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	logger.Info("Removed the maintenance agent from %s", path)
+	return nil
+}
+
+func launchctl(r runner.Runner, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	output, err := r.CombinedOutput(exec.CommandContext(ctx, "launchctl", args...))
+	if err != nil {
+		return fmt.Errorf("launchctl %s: %w: %s", args[0], err, bytes.TrimSpace(output))
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+// Package maintain is the background maintenance agent. Once installed
+// with launchd it runs headless every few hours: brew update, brew cleanup
+// and compaction of the stored metrics. Each run is appended to the audit
+// store and left for the TUI to summarize when it next opens.
+package maintain
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+)
+
+// brewTimeout bounds each brew command; an update on a slow network can
+// take minutes
+const brewTimeout = 10 * time.Minute
+
+// compactBucket is the sample interval old metrics are thinned to
+const compactBucket = 5 * time.Minute
+
+// auditFile is the run log in the audit store, a JSON report per line
+const auditFile = "maintenance.jsonl"
+
+// unseenFile holds the latest report until the TUI has shown it
+const unseenFile = "maintenance-unseen.json"
+
+// Step is the outcome of one maintenance task
+type Step struct {
+	Name    string `json:"name"`
+	Result  string `json:"result,omitempty"` // what it did, or why it was skipped
+	Error   string `json:"error,omitempty"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// Report is one maintenance run
+type Report struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Steps    []Step    `json:"steps"`
+}
+
+// Failed reports whether any step of the run failed
+func (r Report) Failed() bool {
+	for _, step := range r.Steps {
+		if step.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary is the run in a line, for the toast at the next launch
+func (r Report) Summary() string {
+	var parts []string
+	for _, step := range r.Steps {
+		switch {
+		case step.Error != "":
+			parts = append(parts, step.Name+" failed: "+step.Error)
+		case !step.Skipped:
+			parts = append(parts, step.Name+": "+step.Result)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "nothing to do")
+	}
+	return fmt.Sprintf("Background maintenance %s: %s", r.Finished.Format("Jan 2 15:04"), strings.Join(parts, "; "))
+}
+
+// Run does every task enabled under maintenance, records the run in the
+// audit store and leaves it for the TUI. A failing task doesn't stop the
+// ones after it.
+func Run(cfg *config.Config, r runner.Runner, now time.Time) Report {
+	report := Report{Started: now}
+	settings := cfg.Maintenance
+
+	_, brewErr := r.LookPath("brew")
+	brewStep := func(name string, enabled bool, run func() (string, error)) {
+		step := Step{Name: name}
+		switch {
+		case !enabled:
+			step.Skipped, step.Result = true, "turned off"
+		case brewErr != nil:
+			step.Skipped, step.Result = true, "Homebrew isn't installed"
+		default:
+			result, err := run()
+			step.Result = result
+			if err != nil {
+				step.Error = err.Error()
+			}
+		}
+		report.Steps = append(report.Steps, step)
+	}
+	brewStep("brew update", settings.BrewUpdate, func() (string, error) { return brewUpdate(r) })
+	brewStep("brew cleanup", settings.BrewCleanup, func() (string, error) { return brewCleanup(r) })
+
+	metrics := Step{Name: "metrics"}
+	if settings.CompactAfterDays > 0 {
+		result, err := storage.CompactMetrics(cfg, now.AddDate(0, 0, -settings.CompactAfterDays), compactBucket)
+		switch {
+		case err != nil:
+			metrics.Error = err.Error()
+		case result.Days == 0:
+			metrics.Result = "nothing to compact"
+		default:
+			metrics.Result = fmt.Sprintf("compacted %d day(s), %s freed", result.Days, formatBytes(result.Freed))
+		}
+	} else {
+		metrics.Skipped, metrics.Result = true, "turned off"
+	}
+	report.Steps = append(report.Steps, metrics)
+	storage.ApplyRetention(cfg)
+
+	report.Finished = time.Now()
+	if err := record(cfg, report); err != nil {
+		logger.Error("Maintenance: failed to record the run: %v", err)
+	}
+	return report
+}
+
+func brewUpdate(r runner.Runner) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), brewTimeout)
+	defer cancel()
+	output, err := r.CombinedOutput(exec.CommandContext(ctx, "brew", "update"))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, firstLine(output))
+	}
+	return firstLine(output), nil
+}
+
+func brewCleanup(r runner.Runner) (string, error) {
+	homeDir, _ := os.UserHomeDir()
+	cache := filepath.Join(homeDir, "Library/Caches/Homebrew")
+	before := storage.DirSize(cache)
+
+	ctx, cancel := context.WithTimeout(context.Background(), brewTimeout)
+	defer cancel()
+	output, err := r.CombinedOutput(exec.CommandContext(ctx, "brew", "cleanup"))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, firstLine(output))
+	}
+	if after := storage.DirSize(cache); after < before {
+		return formatBytes(before-after) + " freed", nil
+	}
+	return "cache already clean", nil
+}
+
+// record appends the report to the audit log and keeps it as the unseen
+// run, replacing an earlier one the TUI hasn't shown
+func record(cfg *config.Config, report Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	dir, err := storage.StoreDir(cfg, storage.StoreAudit)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, auditFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storage.DataDir(cfg), unseenFile), data, 0644)
+}
+
+// TakeUnseen returns the latest run the TUI hasn't shown yet, marking it
+// shown
+func TakeUnseen(cfg *config.Config) (Report, bool) {
+	var report Report
+	path := filepath.Join(storage.DataDir(cfg), unseenFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, false
+	}
+	os.Remove(path)
+	if err := json.Unmarshal(data, &report); err != nil {
+		logger.Warn("Maintenance: ignoring unreadable %s: %v", path, err)
+		return report, false
+	}
+	return report, true
+}
+
+// LastRun returns the most recent run in the audit log
+func LastRun(cfg *config.Config) (Report, bool, error) {
+	var report Report
+	f, err := os.Open(filepath.Join(storage.DataDir(cfg), storage.StoreAudit, auditFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return report, false, nil
+	}
+	if err != nil {
+		return report, false, err
+	}
+	defer f.Close()
+
+	found := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var run Report
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			continue // skip a line cut short by a crash
+		}
+		report, found = run, true
+	}
+	return report, found, scanner.Err()
+}
+
+// firstLine is the first non-blank line of command output
+func firstLine(output []byte) string {
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return "done"
+}
+
+// formatBytes formats bytes into human-readable format
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
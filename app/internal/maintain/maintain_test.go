@@ -0,0 +1,97 @@
+package maintain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+)
+
+func TestRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{
+		Storage:     config.StorageConfig{DataDir: t.TempDir()},
+		Maintenance: config.MaintenanceConfig{BrewUpdate: true, BrewCleanup: true, CompactAfterDays: 7},
+	}
+	now := time.Date(2024, 7, 20, 9, 0, 0, 0, time.Local)
+
+	// Three samples within five minutes ten days ago become one; today's stay
+	old := time.Date(2024, 7, 10, 14, 0, 0, 0, time.Local)
+	for i, sample := range []storage.MetricSample{
+		{Time: old, CPU: 10, Memory: 40},
+		{Time: old.Add(time.Minute), CPU: 20, Memory: 50},
+		{Time: old.Add(2 * time.Minute), CPU: 30, Memory: 60},
+		{Time: now.Add(-time.Hour), CPU: 5},
+		{Time: now.Add(-time.Hour + time.Minute), CPU: 7},
+	} {
+		if err := storage.AppendMetrics(cfg, sample); err != nil {
+			t.Fatalf("sample %d: %v", i, err)
+		}
+	}
+
+	fake := runner.NewFake().
+		Set("brew update", "Already up-to-date.\n", nil).
+		Set("brew cleanup", "", errors.New("exit status 1"))
+	report := Run(cfg, fake, now)
+
+	if got := report.Steps[0]; got.Name != "brew update" || got.Result != "Already up-to-date." || got.Error != "" {
+		t.Errorf("brew update step = %+v", got)
+	}
+	if got := report.Steps[1]; !strings.HasPrefix(got.Error, "exit status 1") {
+		t.Errorf("brew cleanup step = %+v, want the failure", got)
+	}
+	if got := report.Steps[2]; got.Result != "compacted 1 day(s), 48 B freed" {
+		t.Errorf("metrics step = %+v", got)
+	}
+	if !report.Failed() {
+		t.Error("Failed() = false with a failed step")
+	}
+
+	samples, err := storage.ReadMetrics(cfg, old.AddDate(0, 0, -1), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 || samples[0].CPU != 20 || samples[0].Memory != 50 {
+		t.Errorf("after compaction: %+v, want the old samples averaged and today's kept", samples)
+	}
+
+	// The run is in the audit log and shown once at the next launch
+	last, found, err := LastRun(cfg)
+	if err != nil || !found || len(last.Steps) != 3 {
+		t.Errorf("LastRun() = %+v, %v, %v", last, found, err)
+	}
+	unseen, ok := TakeUnseen(cfg)
+	if !ok || !strings.Contains(unseen.Summary(), "brew update: Already up-to-date.; brew cleanup failed: exit status 1") {
+		t.Errorf("TakeUnseen() = %q, %v", unseen.Summary(), ok)
+	}
+	if _, ok := TakeUnseen(cfg); ok {
+		t.Error("the run was shown twice")
+	}
+
+	// Without Homebrew only the metrics are looked after
+	report = Run(cfg, runner.NewFake().Missing("brew"), now)
+	if !report.Steps[0].Skipped || !report.Steps[1].Skipped || report.Steps[2].Result != "nothing to compact" {
+		t.Errorf("without brew: %+v", report.Steps)
+	}
+}
+
+func TestPlist(t *testing.T) {
+	plist := Plist("/opt/homebrew/bin/devcockpit", 6*time.Hour, "/Users/me/.devcockpit/maintain.log")
+	for _, want := range []string{
+		"<string>" + Label + "</string>",
+		"<string>/opt/homebrew/bin/devcockpit</string>\n\t\t<string>maintain</string>\n\t\t<string>run</string>",
+		"<integer>21600</integer>",
+		"<string>/Users/me/.devcockpit/maintain.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist is missing %q:\n%s", want, plist)
+		}
+	}
+	if got := Plist("/tmp/a&b", time.Hour, "/tmp/log"); !strings.Contains(got, "/tmp/a&amp;b") {
+		t.Errorf("executable path isn't escaped:\n%s", got)
+	}
+}
@@ -2,18 +2,34 @@ package cleanup
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/caioricciuti/dev-cockpit/internal/awake"
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/opener"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/disk"
 )
 
+// maxParallelCleanups bounds how many targets are cleaned at the same time
+const maxParallelCleanups = 3
+
 // CleanupTarget represents a cleanable target
 type CleanupTarget struct {
 	Name        string
@@ -27,22 +43,55 @@ type CleanupTarget struct {
 // Model represents the cleanup module state
 type Model struct {
 	config         *config.Config
+	runner         runner.Runner
 	width          int
 	height         int
 	targets        []CleanupTarget
-	cursor         int
+	cursor         int // position in visible()
+	filter         components.ListFilter
+	rows           components.RowHits // target rows from the last render, for clicks
 	scanning       bool
 	cleaning       bool
+	cleanTotal     int
+	progress       components.Progress // weighted by target size
+	weights        map[string]int64    // by target name
+	active         []activeTarget      // targets being cleaned right now
 	results        []CleanupResult
+	space          *SpaceReport
 	showingResults bool
 	message        string
 }
 
+// activeTarget is a target being cleaned; deadline is set once its
+// contents are being deleted
+type activeTarget struct {
+	name     string
+	deadline time.Time
+}
+
+// SpaceReport compares volume free space before and after a cleanup run.
+// Bytes deleted by rm and bytes the volume reports as free differ on APFS:
+// clones share blocks, local snapshots retain deleted data, and purgeable
+// space is only released when macOS needs it.
+type SpaceReport struct {
+	AvailableBefore uint64
+	AvailableAfter  uint64
+	PurgeableAfter  uint64 // 0 when macOS does not report it
+}
+
+// Gained returns how much free space the volume actually gained
+func (r *SpaceReport) Gained() uint64 {
+	if r == nil || r.AvailableAfter <= r.AvailableBefore {
+		return 0
+	}
+	return r.AvailableAfter - r.AvailableBefore
+}
+
 // CleanupResult represents the result of a cleanup operation
 type CleanupResult struct {
 	Target   string
 	Success  bool
-	Freed    uint64
+	Freed    uint64 // bytes deleted, measured with du before and after
 	Error    error
 	Duration time.Duration
 }
@@ -96,8 +145,16 @@ func New(cfg *config.Config) *Model {
 		},
 	}
 
+	// system.timeouts gives slow targets, like a huge DerivedData, longer
+	if cfg != nil {
+		for i := range targets {
+			targets[i].Timeout = cfg.System.Timeout(targets[i].Name, targets[i].Timeout)
+		}
+	}
+
 	return &Model{
 		config:   cfg,
+		runner:   runner.Default,
 		targets:  targets,
 		scanning: true,
 	}
@@ -108,6 +165,25 @@ func (m *Model) Init() tea.Cmd {
 	return m.scanSizes()
 }
 
+// StaleAfter keeps the scanned sizes and the last cleanup's results across
+// tab switches for ten minutes; R rescans sooner
+func (m *Model) StaleAfter() time.Duration {
+	return 10 * time.Minute
+}
+
+// Schedule redraws the progress bar every second during a cleanup, so the
+// time left counts down between finished targets
+func (m *Model) Schedule() []scheduler.Task {
+	return []scheduler.Task{{Name: "progress", Every: time.Second, Run: m.progressTick}}
+}
+
+func (m *Model) progressTick() tea.Cmd {
+	if !m.cleaning {
+		return nil
+	}
+	return func() tea.Msg { return progressTickMsg{} }
+}
+
 // Update handles messages
 func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -115,6 +191,17 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case events.Click:
+		if row, ok := m.rows.Row(msg.Y); ok {
+			m.cursor = row
+		}
+
+	case events.Nav:
+		// The results stay up until a key dismisses them
+		if !m.showingResults && !m.scanning && !m.cleaning {
+			m.cursor = msg.Move(m.cursor, len(m.visible()))
+		}
+
 	case tea.KeyMsg:
 		// Handle results screen - any key dismisses
 		if m.showingResults {
@@ -127,55 +214,71 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
+		if m.filter.HandleKey(msg) {
+			m.cursor = 0
+			return m, nil
+		}
 
-		case "down", "j":
-			if m.cursor < len(m.targets)-1 {
-				m.cursor++
-			}
+		visible := m.visible()
 
+		switch msg.String() {
 		case " ":
 			// Toggle selection
-			m.targets[m.cursor].Selected = !m.targets[m.cursor].Selected
+			if m.cursor < len(visible) {
+				target := &m.targets[visible[m.cursor]]
+				target.Selected = !target.Selected
+			}
 
 		case "a":
-			// Select all
-			for i := range m.targets {
+			// Select all (shown) targets
+			for _, i := range visible {
 				m.targets[i].Selected = true
 			}
 			m.message = "All items selected"
+			if m.filter.Query() != "" {
+				m.message = "All matching items selected"
+			}
 
 		case "n":
 			// Select none
-			for i := range m.targets {
+			for _, i := range visible {
 				m.targets[i].Selected = false
 			}
 			m.message = "Selection cleared"
 
 		case "enter":
-			// Start cleanup
-			hasSelected := false
-			for _, target := range m.targets {
-				if target.Selected {
-					hasSelected = true
-					break
-				}
-			}
-			if hasSelected {
-				return m, m.performCleanup()
-			} else {
-				m.message = "⚠ Select at least one item to clean"
+			// Confirm, then start cleanup
+			if req, ok := m.confirmCleanup(); ok {
+				return m, dialog.Confirm(req)
 			}
+			m.message = "⚠ Select at least one item to clean"
 
 		case "r":
 			// Rescan sizes
 			m.scanning = true
 			m.message = "Rescanning..."
 			return m, m.scanSizes()
+
+		case "o", "t", "e":
+			if m.cursor < len(visible) {
+				return m, m.openTarget(m.targets[visible[m.cursor]], openTargets[msg.String()])
+			}
+		}
+
+	case startCleanupMsg:
+		if !m.scanning && !m.cleaning {
+			return m, m.performCleanup()
+		}
+
+	case openMsg:
+		m.message = msg.note
+
+	case selectTargetMsg:
+		m.filter.Reset()
+		for i, target := range m.targets {
+			if target.Name == msg.name {
+				m.cursor = i
+			}
 		}
 
 	case scanCompleteMsg:
@@ -183,25 +286,42 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.scanning = false
 		m.message = fmt.Sprintf("Found %.2f GB available to clean", float64(m.getTotalSize())/1024/1024/1024)
 
+	case cleanupProgressMsg:
+		if msg.result == nil {
+			m.setActive(activeTarget{name: msg.started, deadline: msg.deadline})
+			return m, waitForCleanupResult(msg.run)
+		}
+		m.results = append(m.results, *msg.result)
+		m.progress.Advance(m.weights[msg.result.Target])
+		for i, active := range m.active {
+			if active.name == msg.result.Target {
+				m.active = append(m.active[:i], m.active[i+1:]...)
+				break
+			}
+		}
+		return m, waitForCleanupResult(msg.run)
+
 	case cleanupCompleteMsg:
 		m.cleaning = false
-		m.results = msg.results
+		m.active = nil
+		m.space = msg.space
 		m.showingResults = true
 
-		// Calculate total freed
-		var totalFreed uint64
 		successCount := 0
 		for _, r := range m.results {
 			if r.Success {
-				totalFreed += r.Freed
 				successCount++
 			}
 		}
 
-		m.message = fmt.Sprintf("✓ Cleaned %d items, freed %.2f GB", successCount, float64(totalFreed)/1024/1024/1024)
+		m.message = fmt.Sprintf("✓ Cleaned %d items, %s now available", successCount, formatBytes(m.space.Gained()))
 
 		// Rescan to update sizes
-		return m, m.scanSizes()
+		return m, tea.Batch(m.scanSizes(), components.ResultToast(m.message, showResultsMsg{}))
+
+	case showResultsMsg:
+		// Results stay up until dismissed
+		m.showingResults = len(m.results) > 0
 	}
 
 	return m, nil
@@ -209,6 +329,7 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 
 // View renders the module
 func (m *Model) View() string {
+	m.rows.Reset()
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
 	}
@@ -229,7 +350,7 @@ func (m *Model) View() string {
 }
 
 func (m *Model) renderScanning() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("🧹 CLEANUP"))
@@ -241,19 +362,27 @@ func (m *Model) renderScanning() string {
 }
 
 func (m *Model) renderSelection() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
-	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00D9FF")).Bold(true)
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#DDD"))
-	msgStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#0FD976"))
-	controlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	msgStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("🧹 CLEANUP"))
 	b.WriteString("\n\n")
 	b.WriteString("Select items to clean:\n\n")
 
+	visible := m.visible()
+	if bar := m.filter.View(len(visible), len(m.targets)); bar != "" {
+		b.WriteString(bar)
+		b.WriteString("\n\n")
+	}
+
 	// Render targets
-	for i, target := range m.targets {
+	for i, index := range visible {
+		m.rows.Add(components.NextLine(b.String()), i)
+		target := m.targets[index]
 		cursor := "  "
 		if i == m.cursor {
 			cursor = "▶ "
@@ -275,20 +404,21 @@ func (m *Model) renderSelection() string {
 		}
 		b.WriteString("\n")
 	}
+	m.rows.End(components.NextLine(b.String()))
 
 	// Summary
 	b.WriteString("\n")
 	totalSelected := uint64(0)
-	for _, target := range m.targets {
-		if target.Selected {
-			totalSelected += target.Size
-		}
+	for _, target := range m.toClean() {
+		totalSelected += target.Size
 	}
 	b.WriteString(fmt.Sprintf("Total to clean: %s\n", formatBytes(totalSelected)))
 
 	// Controls
 	b.WriteString("\n")
-	b.WriteString(controlStyle.Render("↑/↓ Navigate • Space Toggle • A All • N None • Enter Clean • R Rescan"))
+	b.WriteString(controlStyle.Render("↑/↓ Navigate • Space Toggle • A All • N None • / Filter • Enter Clean • R Rescan"))
+	b.WriteString("\n")
+	b.WriteString(controlStyle.Render("O Reveal in Finder • T Open Terminal • E Open in Editor"))
 
 	// Message
 	if m.message != "" {
@@ -300,22 +430,60 @@ func (m *Model) renderSelection() string {
 }
 
 func (m *Model) renderCleaning() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	successStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("🧹 CLEANUP IN PROGRESS"))
 	b.WriteString("\n\n")
-	b.WriteString("⏳ Cleaning selected items...\n")
-	b.WriteString("\nPlease wait, this may take a minute.\n")
+	b.WriteString(m.progress.View(min(m.width-4, 72), time.Now()))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("%d of %d targets done\n\n", len(m.results), m.cleanTotal))
+
+	for _, result := range m.results {
+		if result.Success {
+			b.WriteString(successStyle.Render(fmt.Sprintf("✓ %s: %s deleted (%v)",
+				result.Target, formatBytes(result.Freed), result.Duration.Round(time.Millisecond))))
+			b.WriteString("\n")
+		} else {
+			b.WriteString(errorStyle.Render(fmt.Sprintf("✗ %s: %v", result.Target, result.Error)))
+			b.WriteString("\n")
+		}
+	}
+
+	activeStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	for _, active := range m.active {
+		if active.deadline.IsZero() {
+			b.WriteString(activeStyle.Render("⏳ " + active.name + ": cleaning..."))
+		} else {
+			left := max(time.Until(active.deadline), 0).Round(time.Second)
+			b.WriteString(activeStyle.Render("⏳ "+active.name+": deleting") + hintStyle.Render(fmt.Sprintf(" · %v left", left)))
+		}
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
 
+// setActive adds a target being cleaned, or updates it
+func (m *Model) setActive(target activeTarget) {
+	for i, active := range m.active {
+		if active.name == target.name {
+			m.active[i] = target
+			return
+		}
+	}
+	m.active = append(m.active, target)
+}
+
 func (m *Model) renderResults() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
-	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#0FD976"))
-	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B"))
-	controlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	successStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	noteStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("🧹 CLEANUP COMPLETE"))
@@ -325,28 +493,42 @@ func (m *Model) renderResults() string {
 	successCount := 0
 	failedCount := 0
 
-	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
+	warningStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
 
 	for _, result := range m.results {
+		line := resultLine(result)
 		if result.Success {
 			successCount++
 			totalFreed += result.Freed
 			if result.Freed == 0 {
-				b.WriteString(warningStyle.Render(fmt.Sprintf("⚠ %s: Nothing to clean (%v)\n",
-					result.Target, result.Duration.Round(time.Millisecond))))
+				b.WriteString(warningStyle.Render(line))
 			} else {
-				b.WriteString(successStyle.Render(fmt.Sprintf("✓ %s: %s freed (%v)\n",
-					result.Target, formatBytes(result.Freed), result.Duration.Round(time.Millisecond))))
+				b.WriteString(successStyle.Render(line))
 			}
 		} else {
 			failedCount++
-			b.WriteString(errorStyle.Render(fmt.Sprintf("✗ %s: %v\n", result.Target, result.Error)))
+			b.WriteString(errorStyle.Render(line))
 		}
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
 	b.WriteString(fmt.Sprintf("Summary: %d succeeded, %d failed\n", successCount, failedCount))
-	b.WriteString(fmt.Sprintf("Total freed: %s\n", formatBytes(totalFreed)))
+	b.WriteString(fmt.Sprintf("Deleted:          %s\n", formatBytes(totalFreed)))
+	if m.space != nil {
+		b.WriteString(fmt.Sprintf("Now available:    %s (volume free space %s → %s)\n",
+			formatBytes(m.space.Gained()), formatBytes(m.space.AvailableBefore), formatBytes(m.space.AvailableAfter)))
+		if m.space.PurgeableAfter > 0 {
+			b.WriteString(fmt.Sprintf("Purgeable:        %s (reclaimed by macOS on demand)\n", formatBytes(m.space.PurgeableAfter)))
+		}
+		if totalFreed > m.space.Gained() {
+			b.WriteString("\n")
+			b.WriteString(noteStyle.Render("Deleted space can exceed what becomes available: APFS clones share\n" +
+				"blocks with other files, local Time Machine snapshots keep deleted\n" +
+				"data, and purgeable space is released only when macOS needs it."))
+			b.WriteString("\n")
+		}
+	}
 
 	b.WriteString("\n")
 	b.WriteString(controlStyle.Render("Press any key to continue"))
@@ -354,14 +536,151 @@ func (m *Model) renderResults() string {
 	return b.String()
 }
 
+// resultLine describes one cleanup result
+func resultLine(result CleanupResult) string {
+	switch {
+	case !result.Success:
+		return fmt.Sprintf("✗ %s: %v", result.Target, result.Error)
+	case result.Freed == 0:
+		return fmt.Sprintf("⚠ %s: Nothing to clean (%v)", result.Target, result.Duration.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("✓ %s: %s deleted (%v)", result.Target, formatBytes(result.Freed), result.Duration.Round(time.Millisecond))
+}
+
+// Copyable returns the last run's results, or the selected target's path
+func (m *Model) Copyable() string {
+	if m.showingResults {
+		lines := make([]string, 0, len(m.results))
+		for _, result := range m.results {
+			lines = append(lines, resultLine(result))
+		}
+		return strings.Join(lines, "\n")
+	}
+	visible := m.visible()
+	if m.filter.Typing() || m.scanning || m.cleaning || m.cursor >= len(visible) {
+		return ""
+	}
+	return m.targets[visible[m.cursor]].Path
+}
+
 // Title returns the module title
 func (m *Model) Title() string {
 	return "Cleanup"
 }
 
+// Help describes the cleanup keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "CLEANUP",
+		Description: "Measure and clear caches, logs and build leftovers.",
+		Sections: []help.Section{
+			{Title: "Selection", Bindings: []help.Binding{
+				{Key: "↑/↓", Desc: "Navigate targets"},
+				{Key: "Space", Desc: "Toggle the selected target"},
+				{Key: "A / N", Desc: "Select all / none (matching targets when filtered)"},
+				{Key: "/", Desc: "Filter targets"},
+			}},
+			{Title: "Actions", Bindings: []help.Binding{
+				{Key: "Enter", Desc: "Clean the selected targets"},
+				{Key: "R", Desc: "Rescan sizes"},
+				{Key: "O / T / E", Desc: "Open the target in Finder / Terminal / editor"},
+				{Key: "y", Desc: "Copy the target path, or the results after a run"},
+			}},
+		},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
 func (m *Model) HasOpenModal() bool {
-	return m.showingResults
+	return m.showingResults || m.filter.Active()
+}
+
+// visible returns the indexes of the targets matching the filter, in list
+// order when there is no query
+func (m *Model) visible() []int {
+	texts := make([]string, len(m.targets))
+	for i, target := range m.targets {
+		texts[i] = target.Name + " " + target.Description
+	}
+	return m.filter.Matches(texts)
+}
+
+// savedState is what the module keeps between launches
+type savedState struct {
+	Cursor   int      `json:"cursor"`
+	Selected []string `json:"selected,omitempty"` // target names
+}
+
+// SaveState returns the cursor and the selected targets
+func (m *Model) SaveState() (json.RawMessage, error) {
+	var saved savedState
+	// The filter isn't saved, so store the target's index in the full list
+	if visible := m.visible(); m.cursor < len(visible) {
+		saved.Cursor = visible[m.cursor]
+	}
+	for _, target := range m.targets {
+		if target.Selected {
+			saved.Selected = append(saved.Selected, target.Name)
+		}
+	}
+	return json.Marshal(saved)
+}
+
+// RestoreState applies state saved by the previous launch
+func (m *Model) RestoreState(data json.RawMessage) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	m.cursor = saved.Cursor
+	selected := make(map[string]bool, len(saved.Selected))
+	for _, name := range saved.Selected {
+		selected[name] = true
+	}
+	for i := range m.targets {
+		m.targets[i].Selected = selected[m.targets[i].Name]
+	}
+	return nil
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Rescan cleanup targets", Hint: "Recalculate the size of every cleanup target", Msg: palette.Key("r")},
+		{Title: "Select all cleanup targets", Hint: "Mark every target for cleaning", Msg: palette.Key("a")},
+		{Title: "Reveal cleanup target in Finder", Hint: "Open the selected target's folder", Msg: palette.Key("o")},
+		{Title: "Open terminal at cleanup target", Hint: "Uses open.terminal from config.yaml", Msg: palette.Key("t")},
+	}
+}
+
+// openTargets maps the open keys to where the target is opened
+var openTargets = map[string]opener.Target{
+	"o": opener.Finder,
+	"t": opener.Terminal,
+	"e": opener.Editor,
+}
+
+// openTarget opens a cleanup target's directory outside the TUI
+func (m *Model) openTarget(target CleanupTarget, where opener.Target) tea.Cmd {
+	return func() tea.Msg {
+		if err := opener.Open(m.runner, m.config, where, target.Path); err != nil {
+			return openMsg{note: fmt.Sprintf("✗ %v", err)}
+		}
+		return openMsg{note: fmt.Sprintf("Opened %s in %s", target.Name, where)}
+	}
+}
+
+// SearchItems returns cleanup targets for global search
+func (m *Model) SearchItems() []palette.Command {
+	items := make([]palette.Command, 0, len(m.targets))
+	for _, target := range m.targets {
+		items = append(items, palette.Command{
+			Title: target.Name,
+			Hint:  fmt.Sprintf("%s • %s", target.Description, formatBytes(target.Size)),
+			Msg:   selectTargetMsg{name: target.Name},
+		})
+	}
+	return items
 }
 
 func (m *Model) getTotalSize() uint64 {
@@ -382,7 +701,7 @@ func (m *Model) scanSizes() tea.Cmd {
 			}
 
 			// Get size with timeout
-			size := getSizeWithTimeout(m.targets[i].Path, m.targets[i].Timeout)
+			size := getSizeWithTimeout(m.runner, m.targets[i].Path, m.targets[i].Timeout)
 			m.targets[i].Size = size
 		}
 
@@ -390,68 +709,225 @@ func (m *Model) scanSizes() tea.Cmd {
 	}
 }
 
+// startCleanupMsg is sent when the user confirms the cleanup
+type startCleanupMsg struct{}
+
+// showResultsMsg shows the last cleanup's results, from its toast
+type showResultsMsg struct{}
+
+// toClean returns the selected targets, leaving out those inside another
+// selected one: User Caches holds the Homebrew, Yarn and Go caches, and
+// cleaning both at once would race rm over the same files and count what
+// they free twice
+func (m *Model) toClean() []CleanupTarget {
+	var selected []CleanupTarget
+	for _, target := range m.targets {
+		if target.Selected && !m.insideSelected(target.Path) {
+			selected = append(selected, target)
+		}
+	}
+	return selected
+}
+
+// insideSelected reports whether path is within another selected target
+func (m *Model) insideSelected(path string) bool {
+	for _, target := range m.targets {
+		if !target.Selected || target.Path == path {
+			continue
+		}
+		if rel, err := filepath.Rel(target.Path, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmCleanup asks before deleting the selected targets; ok is false when
+// nothing is selected
+func (m *Model) confirmCleanup() (dialog.Request, bool) {
+	var names []string
+	var total uint64
+	for _, target := range m.toClean() {
+		names = append(names, target.Name)
+		total += target.Size
+	}
+	if len(names) == 0 {
+		return dialog.Request{}, false
+	}
+	return dialog.Request{
+		Title:        fmt.Sprintf("Delete %s from %d item(s)?", formatBytes(total), len(names)),
+		Detail:       strings.Join(names, ", ") + "\n\nThe contents are deleted, not moved to the Trash.",
+		ConfirmLabel: "Clean",
+		Destructive:  true,
+		OnConfirm:    startCleanupMsg{},
+	}, true
+}
+
 func (m *Model) performCleanup() tea.Cmd {
+	selected := m.toClean()
+
 	m.cleaning = true
+	m.cleanTotal = len(selected)
 	m.results = []CleanupResult{}
+	m.active = nil
+
+	// Bigger targets take longer, so they count for more of the bar. Empty
+	// ones still take a du and an rm.
+	m.weights = make(map[string]int64, len(selected))
+	var total int64
+	for _, target := range selected {
+		m.weights[target.Name] = max(int64(target.Size), minTargetWeight)
+		total += m.weights[target.Name]
+	}
+	m.progress = components.NewProgress(total)
+
+	// Targets are independent, so clean them concurrently and stream each
+	// result back as it finishes.
+	run := &cleanupRun{updates: make(chan cleanupProgressMsg, 3*len(selected))}
+	volume := volumePath(selected)
+	large := total >= largeCleanup
+	r := m.runner
+	go func() {
+		if large {
+			hold := awake.Start(r)
+			defer hold.Release()
+		}
+		before := readVolumeSpace(r, volume)
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, maxParallelCleanups)
+		for _, target := range selected {
+			wg.Add(1)
+			go func(target CleanupTarget) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				run.updates <- cleanupProgressMsg{started: target.Name}
+				result := cleanOne(r, target, func(deadline time.Time) {
+					run.updates <- cleanupProgressMsg{started: target.Name, deadline: deadline}
+				})
+				run.updates <- cleanupProgressMsg{result: &result}
+			}(target)
+		}
+		wg.Wait()
 
-	return func() tea.Msg {
-		var results []CleanupResult
+		after := readVolumeSpace(r, volume)
+		run.space = &SpaceReport{
+			AvailableBefore: before.available,
+			AvailableAfter:  after.available,
+			PurgeableAfter:  after.purgeable,
+		}
+		close(run.updates)
+	}()
 
-		for _, target := range m.targets {
-			if !target.Selected {
-				continue
-			}
+	if large {
+		return tea.Batch(waitForCleanupResult(run), m.sleepWarning())
+	}
+	return waitForCleanupResult(run)
+}
 
-			start := time.Now()
+// largeCleanup is the total size from which a cleanup keeps the Mac awake
+// and warns about what could still stop it
+const largeCleanup = 5 * 1024 * 1024 * 1024
 
-			// Check if path exists
-			if _, err := os.Stat(target.Path); os.IsNotExist(err) {
-				results = append(results, CleanupResult{
-					Target:   target.Name,
-					Success:  true,
-					Freed:    0,
-					Duration: time.Since(start),
-				})
-				continue
-			}
+// sleepWarning is a toast about what could stop the cleanup, like a low
+// battery, or nothing
+func (m *Model) sleepWarning() tea.Cmd {
+	r := m.runner
+	return func() tea.Msg {
+		if warning := awake.Warning(r, "the cleanup"); warning != "" {
+			return components.Toast(components.ToastWarning, warning)()
+		}
+		return nil
+	}
+}
 
-			// Get size before cleanup
-			sizeBefore := getSizeWithTimeout(target.Path, target.Timeout)
+// minTargetWeight is how much an empty or tiny target counts toward the
+// progress bar, in bytes
+const minTargetWeight = 64 * 1024 * 1024
 
-			// Perform cleanup
-			err := cleanTarget(target.Path, target.Timeout)
+// cleanupRun carries progress from the workers; space is set before
+// updates is closed.
+type cleanupRun struct {
+	updates chan cleanupProgressMsg
+	space   *SpaceReport
+}
 
-			// Get size after cleanup
-			sizeAfter := uint64(0)
-			if err == nil {
-				sizeAfter = getSizeWithTimeout(target.Path, target.Timeout)
-			}
+// waitForCleanupResult delivers the next target started or finished, or
+// completion once every target has reported.
+func waitForCleanupResult(run *cleanupRun) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-run.updates
+		if !ok {
+			return cleanupCompleteMsg{space: run.space}
+		}
+		update.run = run
+		return update
+	}
+}
 
-			freed := sizeBefore - sizeAfter
+// cleanOne cleans a single target within its own timeout, which covers
+// measuring it before and after too, calling deleting with the deadline
+// once it starts deleting
+func cleanOne(r runner.Runner, target CleanupTarget, deleting func(deadline time.Time)) CleanupResult {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), target.Timeout)
+	defer cancel()
 
-			results = append(results, CleanupResult{
-				Target:   target.Name,
-				Success:  err == nil,
-				Freed:    freed,
-				Error:    err,
-				Duration: time.Since(start),
-			})
+	// Check if path exists
+	if _, err := os.Stat(target.Path); os.IsNotExist(err) {
+		return CleanupResult{
+			Target:   target.Name,
+			Success:  true,
+			Freed:    0,
+			Duration: time.Since(start),
 		}
+	}
+
+	// Get size before cleanup
+	sizeBefore := dirSize(ctx, r, target.Path)
+
+	// Perform cleanup
+	deadline, _ := ctx.Deadline()
+	deleting(deadline)
+	err := cleanTarget(ctx, r, target.Path)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("cleanup timed out after %v", target.Timeout)
+	}
 
-		return cleanupCompleteMsg{results: results}
+	// Get size after cleanup
+	sizeAfter := uint64(0)
+	if err == nil {
+		sizeAfter = dirSize(ctx, r, target.Path)
+	}
+
+	freed := uint64(0)
+	if sizeBefore > sizeAfter {
+		freed = sizeBefore - sizeAfter
+	}
+
+	return CleanupResult{
+		Target:   target.Name,
+		Success:  err == nil,
+		Freed:    freed,
+		Error:    err,
+		Duration: time.Since(start),
 	}
 }
 
 // getSizeWithTimeout calculates directory size with a timeout
-func getSizeWithTimeout(path string, timeout time.Duration) uint64 {
+func getSizeWithTimeout(r runner.Runner, path string, timeout time.Duration) uint64 {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	return dirSize(ctx, r, path)
+}
 
-	done := make(chan uint64)
+// dirSize measures a directory with du, or returns 0 once ctx is done
+func dirSize(ctx context.Context, r runner.Runner, path string) uint64 {
+	done := make(chan uint64, 1)
 
 	go func() {
-		cmd := exec.Command("du", "-sk", path)
-		output, err := cmd.Output()
+		output, err := r.Output(exec.CommandContext(ctx, "du", "-sk", path))
 		if err != nil {
 			done <- 0
 			return
@@ -471,20 +947,69 @@ func getSizeWithTimeout(path string, timeout time.Duration) uint64 {
 	}
 }
 
-// cleanTarget removes all contents of a directory
-func cleanTarget(path string, timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+// cleanTarget removes all contents of a directory until ctx is done
+func cleanTarget(ctx context.Context, r runner.Runner, path string) error {
+	// Use rm -rf to clean the directory contents; the path is passed as
+	// $1 so spaces in it survive the shell
+	cmd := exec.CommandContext(ctx, "sh", "-c", `rm -rf "$1"/* 2>/dev/null`, "sh", path)
+	_, err := r.CombinedOutput(cmd)
+	return err
+}
+
+type volumeSpace struct {
+	available uint64
+	purgeable uint64
+}
+
+// volumePath picks a path on the volume the targets live on
+func volumePath(targets []CleanupTarget) string {
+	homeDir, _ := os.UserHomeDir()
+	if homeDir != "" {
+		return homeDir
+	}
+	if len(targets) > 0 {
+		return filepath.Dir(targets[0].Path)
+	}
+	return "/"
+}
+
+// readVolumeSpace reads free space from statfs and, on macOS, the capacity
+// available for important usage, which also counts purgeable space.
+func readVolumeSpace(r runner.Runner, path string) volumeSpace {
+	var space volumeSpace
+	if usage, err := disk.Usage(path); err == nil {
+		space.available = usage.Free
+	}
+
+	if important := importantUsageCapacity(r, path); important > space.available {
+		space.purgeable = important - space.available
+	}
+
+	return space
+}
+
+// importantUsageCapacity asks Foundation for
+// NSURLVolumeAvailableCapacityForImportantUsageKey; returns 0 if unavailable.
+func importantUsageCapacity(r runner.Runner, path string) uint64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Use rm -rf to clean the directory contents
-	cmd := exec.CommandContext(ctx, "sh", "-c", fmt.Sprintf("rm -rf %s/* 2>/dev/null", path))
-	err := cmd.Run()
+	script := fmt.Sprintf(`ObjC.import('Foundation');
+var key = $.NSURLVolumeAvailableCapacityForImportantUsageKey;
+var url = $.NSURL.fileURLWithPath(%s);
+var values = url.resourceValuesForKeysError($([key]), null);
+String(ObjC.unwrap(values.objectForKey(key)));`, strconv.Quote(path))
 
-	if ctx.Err() == context.DeadlineExceeded {
-		return fmt.Errorf("cleanup timed out after 60 seconds")
+	output, err := r.Output(exec.CommandContext(ctx, "osascript", "-l", "JavaScript", "-e", script))
+	if err != nil {
+		return 0
 	}
 
-	return err
+	value, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil || value <= 0 {
+		return 0
+	}
+	return uint64(value)
 }
 
 // formatBytes formats bytes into human-readable format
@@ -508,6 +1033,22 @@ type scanCompleteMsg struct {
 	targets []CleanupTarget
 }
 
+type selectTargetMsg struct{ name string }
+
+type openMsg struct{ note string }
+
+// cleanupProgressMsg reports a target started, or deleting when deadline
+// is set, or finished when result is set
+type cleanupProgressMsg struct {
+	started  string
+	deadline time.Time
+	result   *CleanupResult
+	run      *cleanupRun
+}
+
+// progressTickMsg redraws the progress bar
+type progressTickMsg struct{}
+
 type cleanupCompleteMsg struct {
-	results []CleanupResult
+	space *SpaceReport
 }
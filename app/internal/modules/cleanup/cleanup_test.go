@@ -0,0 +1,300 @@
+package cleanup
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snapshotModel returns the module with fixed target sizes instead of a scan
+func snapshotModel(t *testing.T) *Model {
+	t.Helper()
+	t.Setenv("HOME", "/Users/dev")
+
+	m := New(nil)
+	m.scanning = false
+	for i := range m.targets {
+		m.targets[i].Size = uint64(i+1) * 150 * 1024 * 1024
+	}
+	m.targets[0].Selected = true
+	return m
+}
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := snapshotModel(t)
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			golden.RequireEqual(t, m.View())
+		})
+	}
+}
+
+func TestResultsSnapshot(t *testing.T) {
+	m := snapshotModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.showingResults = true
+	m.results = []CleanupResult{
+		{Target: m.targets[0].Name, Success: true, Freed: 512 * 1024 * 1024, Duration: 1200 * time.Millisecond},
+		{Target: m.targets[1].Name, Error: errors.New("cleanup timed out after 5s"), Duration: 5 * time.Second},
+	}
+	m.space = &SpaceReport{
+		AvailableBefore: 100 * 1024 * 1024 * 1024,
+		AvailableAfter:  100*1024*1024*1024 + 128*1024*1024,
+		PurgeableAfter:  384 * 1024 * 1024,
+	}
+	golden.RequireEqual(t, m.View())
+
+	// Navigation, from the wheel too, leaves the results up
+	m.Update(events.NavDown)
+	if !m.showingResults || len(m.results) != 2 {
+		t.Error("navigation dismissed the results")
+	}
+}
+
+func TestOpenTargetInFinder(t *testing.T) {
+	m := snapshotModel(t)
+	m.targets[0].Path = t.TempDir()
+	fake := runner.NewFake().Set("open "+m.targets[0].Path, "", nil)
+	m.runner = fake
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m.Update(cmd())
+
+	if m.message != "Opened User Caches in Finder" {
+		t.Errorf("message = %q", m.message)
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("calls = %q, want one open", fake.Calls())
+	}
+}
+
+func TestStateRestoresSelection(t *testing.T) {
+	m := New(nil)
+	m.targets[1].Selected = true
+	m.targets[4].Selected = true
+	m.cursor = 4
+
+	data, err := m.SaveState()
+	if err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	restored := New(nil)
+	if err := restored.RestoreState(data); err != nil {
+		t.Fatalf("RestoreState: %v", err)
+	}
+	if restored.cursor != 4 {
+		t.Errorf("cursor = %d, want 4", restored.cursor)
+	}
+	for i, target := range restored.targets {
+		if want := i == 1 || i == 4; target.Selected != want {
+			t.Errorf("%s selected = %v, want %v", target.Name, target.Selected, want)
+		}
+	}
+}
+
+func TestClickSelectsTarget(t *testing.T) {
+	m := snapshotModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	// The cursor row shows its description underneath, so find the third
+	// target by its name rather than by counting lines
+	lineOf := func(name string) int {
+		for i, line := range strings.Split(m.View(), "\n") {
+			if strings.Contains(line, name) {
+				return i
+			}
+		}
+		return -1
+	}
+	m.Update(events.Click{X: 4, Y: lineOf(m.targets[2].Name)})
+	if m.cursor != 2 {
+		t.Errorf("cursor = %d, want 2 after clicking its row", m.cursor)
+	}
+
+	// Clicking the description line keeps the row it belongs to
+	m.Update(events.Click{X: 4, Y: lineOf(m.targets[2].Name) + 1})
+	if m.cursor != 2 {
+		t.Errorf("cursor = %d, want 2 after clicking its description", m.cursor)
+	}
+}
+
+func TestCleaningProgress(t *testing.T) {
+	m := snapshotModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.targets[1].Selected = true // 300 MB next to the 150 MB first target
+	m.performCleanup()
+
+	m.Update(cleanupProgressMsg{started: m.targets[0].Name})
+	m.Update(cleanupProgressMsg{started: m.targets[1].Name})
+	m.Update(cleanupProgressMsg{result: &CleanupResult{Target: m.targets[1].Name, Success: true, Freed: 300 * 1024 * 1024}})
+
+	view := m.View()
+	for _, want := range []string{" 67%", "~", "1 of 2 targets done", "✓ " + m.targets[1].Name, "⏳ " + m.targets[0].Name + ": cleaning..."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("progress screen lacks %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "⏳ "+m.targets[1].Name) {
+		t.Error("a finished target is still shown as cleaning")
+	}
+
+	m.Update(cleanupProgressMsg{started: m.targets[0].Name, deadline: time.Now().Add(12 * time.Second)})
+	if view := m.View(); !strings.Contains(view, "⏳ "+m.targets[0].Name+": deleting · 12s left") {
+		t.Errorf("time left to delete not shown:\n%s", view)
+	}
+	if len(m.active) != 1 {
+		t.Errorf("active = %v, want the target once", m.active)
+	}
+}
+
+func TestTimeoutOverrides(t *testing.T) {
+	cfg := &config.Config{System: config.SystemConfig{
+		Timeouts: map[string]int{"xcodederiveddata": 120},
+	}}
+	for _, target := range New(cfg).targets {
+		want := 5 * time.Second
+		switch target.Name {
+		case "Xcode Derived Data":
+			want = 2 * time.Minute
+		case "Homebrew Cache":
+			want = 15 * time.Second // its default
+		}
+		if target.Timeout != want {
+			t.Errorf("%s timeout = %v, want %v", target.Name, target.Timeout, want)
+		}
+	}
+}
+
+// emptyingFake answers du with an empty folder once rm has run
+type emptyingFake struct {
+	*runner.Fake
+	path string
+}
+
+func (f *emptyingFake) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	output, err := f.Fake.CombinedOutput(cmd)
+	f.Set("du -sk "+f.path, "0\t"+f.path+"\n", nil)
+	return output, err
+}
+
+func TestCleanOne(t *testing.T) {
+	path := t.TempDir()
+	rm := `sh -c rm -rf "$1"/* 2>/dev/null sh ` + path
+	fake := &emptyingFake{Fake: runner.NewFake(), path: path}
+	fake.Set("du -sk "+path, "2048\t"+path+"\n", nil)
+	fake.Set(rm, "", nil)
+
+	target := CleanupTarget{Name: "Caches", Path: path, Timeout: time.Second}
+	var deleting bool
+	result := cleanOne(fake, target, func(time.Time) { deleting = true })
+	if !result.Success || result.Freed != 2*1024*1024 || !deleting {
+		t.Errorf("result = %+v, deleting = %v; want 2 MB freed", result, deleting)
+	}
+	if want := []string{"du -sk " + path, rm, "du -sk " + path}; !reflect.DeepEqual(fake.Calls(), want) {
+		t.Errorf("calls = %q, want %q", fake.Calls(), want)
+	}
+
+	// A failed rm is reported, and the folder isn't measured again
+	fake = &emptyingFake{Fake: runner.NewFake(), path: path}
+	fake.Set("du -sk "+path, "2048\t"+path+"\n", nil)
+	fake.Set(rm, "", errors.New("exit status 1"))
+	if result := cleanOne(fake, target, func(time.Time) {}); result.Success || result.Error == nil || len(fake.Calls()) != 2 {
+		t.Errorf("result = %+v, calls = %q", result, fake.Calls())
+	}
+}
+
+func TestNestedTargetsCleanedOnce(t *testing.T) {
+	m := snapshotModel(t)
+	for i := range m.targets {
+		switch m.targets[i].Name {
+		case "User Caches", "Homebrew Cache", "Go Build Cache", "npm Cache":
+			m.targets[i].Selected = true
+		default:
+			m.targets[i].Selected = false
+		}
+	}
+	var names []string
+	for _, target := range m.toClean() {
+		names = append(names, target.Name)
+	}
+	if want := []string{"User Caches", "npm Cache"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("cleaning %q, want the caches inside User Caches left to it: %q", names, want)
+	}
+	if req, ok := m.confirmCleanup(); !ok || !strings.Contains(req.Title, "from 2 item(s)") {
+		t.Errorf("confirmation = %q", req.Title)
+	}
+}
+
+// slowFake takes delay to answer each command
+type slowFake struct {
+	*runner.Fake
+	delay time.Duration
+}
+
+func (f *slowFake) Output(cmd *exec.Cmd) ([]byte, error) {
+	time.Sleep(f.delay)
+	return f.Fake.Output(cmd)
+}
+
+func (f *slowFake) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	time.Sleep(f.delay)
+	return f.Fake.CombinedOutput(cmd)
+}
+
+func TestCleanOneSharesItsTimeout(t *testing.T) {
+	path := t.TempDir()
+	fake := &slowFake{Fake: runner.NewFake(), delay: 60 * time.Millisecond}
+	fake.Set("du -sk "+path, "2048\t"+path+"\n", nil)
+	fake.Set(`sh -c rm -rf "$1"/* 2>/dev/null sh `+path, "", nil)
+
+	// Measuring and deleting each fit in the timeout, but not together
+	target := CleanupTarget{Name: "Caches", Path: path, Timeout: 100 * time.Millisecond}
+	result := cleanOne(fake, target, func(time.Time) {})
+	if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "timed out") {
+		t.Errorf("result = %+v, want a timeout", result)
+	}
+}
+
+// foundationFake answers the osascript that reads the capacity for
+// important usage with capacity
+type foundationFake struct {
+	*runner.Fake
+	capacity string
+}
+
+func (f *foundationFake) Output(cmd *exec.Cmd) ([]byte, error) {
+	if cmd.Args[0] == "osascript" {
+		return []byte(f.capacity + "\n"), nil
+	}
+	return f.Fake.Output(cmd)
+}
+
+func TestReadVolumeSpaceCountsPurgeable(t *testing.T) {
+	dir := t.TempDir()
+	free := readVolumeSpace(runner.NewFake(), dir)
+	if free.available == 0 || free.purgeable != 0 {
+		t.Fatalf("without Foundation = %+v, want only the free space", free)
+	}
+
+	fake := &foundationFake{Fake: runner.NewFake(), capacity: strconv.FormatUint(free.available+5<<30, 10)}
+	space := readVolumeSpace(fake, dir)
+	// The free space moves between the reads, so allow some slack
+	if space.purgeable < 4<<30 || space.purgeable > 6<<30 {
+		t.Errorf("purgeable = %d, want about 5 GB", space.purgeable)
+	}
+	if got := importantUsageCapacity(&foundationFake{Fake: runner.NewFake(), capacity: "undefined"}, dir); got != 0 {
+		t.Errorf("unreadable capacity = %d", got)
+	}
+}
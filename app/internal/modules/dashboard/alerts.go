@@ -0,0 +1,97 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/alerts"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// alertsMsg carries the alerts that fired in a check and the channels
+// that failed to deliver them
+type alertsMsg struct {
+	fired  []alerts.Alert
+	firing []alerts.Alert // fired in this check or before
+	failed []error
+}
+
+// alertsTask checks the alert rules from config in the background, or is
+// nil when there are none
+func (m *Model) alertsTask() *scheduler.Task {
+	if m.config == nil || len(m.config.Alerts.Rules) == 0 {
+		return nil
+	}
+	engine, err := alerts.New(m.config.Alerts.Rules)
+	if err != nil {
+		logger.Warn("Alerts: %v", err)
+	}
+	if engine.Empty() {
+		return nil
+	}
+	m.alerts = engine
+
+	every := time.Duration(m.config.Alerts.Interval) * time.Second
+	if every <= 0 {
+		every = 30 * time.Second
+	}
+	return &scheduler.Task{Name: "alerts", Every: every, Background: true, Run: m.checkAlerts}
+}
+
+// checkAlerts samples the metrics the rules watch and sends the alerts
+// that fire
+func (m *Model) checkAlerts() tea.Cmd {
+	engine, r, host, cpu := m.alerts, m.runner, m.hostname, m.alertsCPU
+	return func() tea.Msg {
+		sample := map[string]float64{}
+		// CPU usage since the last check; none in the first
+		cpu.Sample()
+		if percent, ok := cpu.Total(); ok {
+			sample["cpu"] = percent
+		}
+		if info, err := mem.VirtualMemory(); err == nil {
+			sample["memory"] = info.UsedPercent
+		}
+		if info, err := disk.Usage("/"); err == nil {
+			sample["disk"] = info.UsedPercent
+		}
+		if percent, ok := readGPU(r); ok {
+			sample["gpu"] = percent
+		}
+
+		var msg alertsMsg
+		for _, a := range engine.Check(sample, host, time.Now()) {
+			msg.fired = append(msg.fired, a)
+			if err := alerts.Send(r, a); err != nil {
+				logger.Warn("Alert %s: %v", a.Rule.Name, err)
+				msg.failed = append(msg.failed, err)
+			}
+		}
+		msg.firing = engine.Firing()
+		return msg
+	}
+}
+
+// alertToasts shows each alert, and any delivery failure, as a toast,
+// and tells the shell which alerts are firing
+func alertToasts(msg alertsMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	alerting := events.Alerting{}
+	for _, a := range msg.firing {
+		alerting.Firing = append(alerting.Firing, a.Short())
+	}
+	for _, a := range msg.fired {
+		cmds = append(cmds, components.Toast(components.ToastWarning, a.Text()))
+		alerting.Fired = append(alerting.Fired, a.Text())
+	}
+	cmds = append(cmds, func() tea.Msg { return alerting })
+	for _, err := range msg.failed {
+		cmds = append(cmds, components.Toast(components.ToastError, "Alert not delivered: "+err.Error()))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,117 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/battery"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// batteryEvery is how often the battery is read; powerHistory holds five
+// minutes of it
+const batteryEvery = 5 * time.Second
+
+type batteryMsg struct {
+	status battery.Status
+	err    error
+}
+
+// fetchBattery reads the battery, or stops once the Mac turns out not to
+// have one
+func (m *Model) fetchBattery() tea.Cmd {
+	if errors.Is(m.powerErr, battery.ErrNoBattery) {
+		return nil
+	}
+	r := m.runner
+	return func() tea.Msg {
+		status, err := battery.Read(r)
+		return batteryMsg{status: status, err: err}
+	}
+}
+
+func (m *Model) updateBattery(msg batteryMsg) {
+	m.power, m.powerErr = msg.status, msg.err
+	if msg.err == nil {
+		m.powerHistory = append(m.powerHistory[1:], math.Abs(msg.status.Watts()))
+	}
+}
+
+// renderBattery is the battery panel: charge, the power flowing in or
+// out with a graph of the last five minutes, time remaining and the
+// battery's health; nothing before the first reading or without a battery
+func (m *Model) renderBattery(width int) string {
+	if m.powerErr != nil || m.power == (battery.Status{}) {
+		return ""
+	}
+	s := m.power
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	labelStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle).Width(9)
+	valueStyle := lipgloss.NewStyle().Foreground(components.ColorBright)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	state, stateColor, flow := "Discharging", components.ColorWarning, "Draw"
+	switch {
+	case s.Charging:
+		state, stateColor, flow = "Charging", components.ColorSuccess, "Charging"
+	case s.FullyCharged:
+		state, stateColor = "Charged", components.ColorSuccess
+	case s.External:
+		state, stateColor = "On power adapter, not charging", components.ColorSubtle
+	}
+	// The level bar runs red for an empty battery, not a full one
+	level := lipgloss.NewStyle().Foreground(levelColor(100 - float64(s.Percent)))
+	barWidth := max(min(width-24, 20), 5)
+	filled := s.Percent * barWidth / 100
+
+	lines := []string{
+		headerStyle.Render("🔋 Battery ") + level.Render(fmt.Sprintf("%d%%", s.Percent)) +
+			lipgloss.NewStyle().Foreground(stateColor).Render(" ● "+state),
+		" [" + level.Render(strings.Repeat("█", filled)) +
+			lipgloss.NewStyle().Foreground(components.ColorBorder).Render(strings.Repeat("░", barWidth-filled)) + "]",
+	}
+
+	power := fmt.Sprintf("%.1f W", math.Abs(s.Watts()))
+	if s.TimeRemaining > 0 {
+		left := "left"
+		if s.Charging {
+			left = "to full"
+		}
+		power += fmt.Sprintf(" · %s %s", formatMinutes(s.TimeRemaining), left)
+	} else if s.Charging || !s.External {
+		power += " · estimating"
+	}
+	graphWidth := max(min(width-12, len(m.powerHistory)), 0)
+	lines = append(lines,
+		labelStyle.Render(flow)+valueStyle.Render(power),
+		labelStyle.Render("")+lipgloss.NewStyle().Foreground(stateColor).Render(components.Sparkline(m.powerHistory, graphWidth, 0))+hintStyle.Render(" 5m"),
+	)
+
+	health := fmt.Sprintf("%.0f%%", s.Health())
+	healthColor := components.ColorSuccess
+	if s.Condition() != "Normal" {
+		health += " · " + s.Condition()
+		healthColor = components.ColorError
+	}
+	lines = append(lines,
+		labelStyle.Render("Health")+lipgloss.NewStyle().Foreground(healthColor).Render(health)+
+			hintStyle.Render(fmt.Sprintf("  %d of %d mAh", s.MaxCapacity, s.DesignCapacity)),
+		labelStyle.Render("Cycles")+valueStyle.Render(fmt.Sprint(s.Cycles))+
+			hintStyle.Render(fmt.Sprintf("  %.1f°C", s.Temperature)),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// formatMinutes is a duration as "6h 52m", or "35m" under an hour
+func formatMinutes(d time.Duration) string {
+	minutes := int(d.Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
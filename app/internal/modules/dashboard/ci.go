@@ -0,0 +1,197 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ci"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ciEvery is how often the CI runs are fetched; each repository is a
+// request
+const ciEvery = 2 * time.Minute
+
+// ciAnonymousHourly is how many requests GitHub allows an hour without a
+// token, for all repositories together
+const ciAnonymousHourly = 60
+
+// ciDetected caps the repositories found in the workspace roots
+const ciDetected = 6
+
+type ciMsg struct {
+	runs []ci.Run
+	err  error     // the repositories that failed, shown under the rest
+	next time.Time // no fetch before then; zero when it can run every ciEvery
+}
+
+// ciOpenedMsg reports opening a run in the browser
+type ciOpenedMsg struct {
+	run ci.Run
+	err error
+}
+
+// fetchCI fetches the latest run of the configured repositories, or of
+// those checked out in the workspace roots. Without a token it spaces the
+// fetches to stay within GitHub's hourly allowance, and once rate limited
+// it waits for the reset GitHub gave.
+func (m *Model) fetchCI() tea.Cmd {
+	if time.Now().Before(m.ciNext) {
+		return nil
+	}
+	r, cfg := m.runner, m.config
+	return func() tea.Msg {
+		var repos []string
+		if cfg != nil {
+			repos = cfg.Modules.Dashboard.CIRepos
+		}
+		if len(repos) == 0 {
+			repos = ci.Detect(r, cfg.WorkspaceRoots(), ciDetected)
+		}
+		if len(repos) == 0 {
+			return ciMsg{}
+		}
+		token := ci.Token(r)
+		var msg ciMsg
+		if token == "" {
+			msg.next = time.Now().Add(time.Duration(len(repos)) * time.Hour / ciAnonymousHourly)
+		}
+		for _, repo := range repos {
+			run, err := ci.Latest(repo, token)
+			var limited *ci.RateLimitError
+			if errors.As(err, &limited) {
+				msg.err = errors.Join(msg.err, err)
+				msg.next = limited.Reset
+				break
+			}
+			if err != nil {
+				msg.err = errors.Join(msg.err, err)
+				continue
+			}
+			msg.runs = append(msg.runs, run)
+		}
+		return msg
+	}
+}
+
+// lastFailedRun is the failing run that finished most recently
+func (m *Model) lastFailedRun() (ci.Run, bool) {
+	var failed ci.Run
+	for _, run := range m.ciRuns {
+		if run.Failed() && run.Updated.After(failed.Updated) {
+			failed = run
+		}
+	}
+	return failed, failed.URL != ""
+}
+
+// openFailedRun opens the latest failing run's page in the browser
+func (m *Model) openFailedRun() tea.Cmd {
+	run, ok := m.lastFailedRun()
+	if !ok {
+		return components.Toast(components.ToastInfo, "No failing CI run to open")
+	}
+	r := m.runner
+	return func() tea.Msg {
+		_, err := r.CombinedOutput(exec.Command("open", run.URL))
+		return ciOpenedMsg{run: run, err: err}
+	}
+}
+
+func ciOpenedToast(msg ciOpenedMsg) tea.Cmd {
+	if msg.err != nil {
+		return components.Toast(components.ToastError, fmt.Sprintf("Couldn't open %s: %v", msg.run.URL, msg.err))
+	}
+	return components.Toast(components.ToastInfo, fmt.Sprintf("Opened %s #%d of %s", msg.run.Workflow, msg.run.Number, msg.run.Repo))
+}
+
+// ciStyle is the mark and color of a run's state
+func ciStyle(run ci.Run) (string, lipgloss.AdaptiveColor) {
+	switch run.State() {
+	case "passed":
+		return "✓", components.ColorSuccess
+	case "failed":
+		return "✗", components.ColorError
+	case "running", "queued":
+		return "●", components.ColorWarning
+	}
+	return "○", components.ColorMuted
+}
+
+// renderCI is the CI panel: each repository's latest run with its
+// workflow, branch and age; nothing until the first fetch finds any
+func (m *Model) renderCI(width int) string {
+	if len(m.ciRuns) == 0 && m.ciErr == nil {
+		return ""
+	}
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Padding(0, 1)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	repoWidth := max(min(width-30, 28), 12)
+
+	header := headerStyle.Render("🚦 CI")
+	if _, ok := m.lastFailedRun(); ok {
+		header += hintStyle.Render("O open failing run")
+	}
+	lines := []string{header}
+	for _, run := range m.ciRuns {
+		mark, color := ciStyle(run)
+		repo := fmt.Sprintf(" %-*s", repoWidth, components.TruncateString(run.Repo, repoWidth))
+		if run.Status == "" {
+			lines = append(lines, " "+hintStyle.Render("○")+repo+hintStyle.Render(" no runs"))
+			continue
+		}
+		detail := fmt.Sprintf(" %s · %s · %s", run.State(), components.TruncateString(run.Workflow+" on "+run.Branch, max(width-repoWidth-20, 8)), formatAge(time.Since(run.Updated)))
+		lines = append(lines, " "+lipgloss.NewStyle().Foreground(color).Render(mark)+repo+hintStyle.Render(detail))
+	}
+	if m.ciErr != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(components.ColorError).Width(width).Render(" "+m.ciErr.Error()))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderCompactCI counts the runs by state, naming the failing repos
+func (m *Model) renderCompactCI(label, hint lipgloss.Style) string {
+	if len(m.ciRuns) == 0 {
+		return ""
+	}
+	passed, running := 0, 0
+	var failed []string
+	for _, run := range m.ciRuns {
+		switch run.State() {
+		case "passed":
+			passed++
+		case "running", "queued":
+			running++
+		case "failed":
+			failed = append(failed, run.Repo)
+		}
+	}
+	line := label.Render("CI") + lipgloss.NewStyle().Foreground(components.ColorSuccess).Render(fmt.Sprintf("✓ %d", passed))
+	if running > 0 {
+		line += lipgloss.NewStyle().Foreground(components.ColorWarning).Render(fmt.Sprintf("  ● %d", running))
+	}
+	for _, repo := range failed {
+		line += lipgloss.NewStyle().Foreground(components.ColorError).Render("  ✗ " + repo)
+	}
+	if len(failed) > 0 {
+		line += hint.Render(" · O open")
+	}
+	return line
+}
+
+// formatAge is how long ago something happened, e.g. "5m ago"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
@@ -0,0 +1,83 @@
+package dashboard
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// coreGroup is a run of CPU cores of one kind, e.g. the efficiency cores
+type coreGroup struct {
+	Name   string
+	Prefix string // before each core's number, e.g. "E"
+	Count  int
+}
+
+// readCoreGroups returns the efficiency and performance cores of Apple
+// silicon, in the order macOS numbers them (efficiency first). Intel Macs
+// have one kind of core, so it returns nil.
+func readCoreGroups(r runner.Runner) []coreGroup {
+	output, err := r.Output(exec.Command("sysctl", "-n", "hw.perflevel0.logicalcpu", "hw.perflevel1.logicalcpu"))
+	if err != nil {
+		return nil
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return nil
+	}
+	performance, err1 := strconv.Atoi(fields[0])
+	efficiency, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+	return []coreGroup{
+		{Name: "Efficiency cores", Prefix: "E", Count: efficiency},
+		{Name: "Performance cores", Prefix: "P", Count: performance},
+	}
+}
+
+// renderCores draws a mini bar per core, grouped by kind when known
+func (m *Model) renderCores() string {
+	groups := m.coreGroups
+	total := 0
+	for _, g := range groups {
+		total += g.Count
+	}
+	if total != len(m.cpuPercent) {
+		// Unknown layout, or an Intel Mac
+		groups = []coreGroup{{Name: "Cores", Prefix: "", Count: len(m.cpuPercent)}}
+	}
+
+	headerStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+	nameStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	// Each cell is "E1  [██████░░░░]  62%", 22 columns with its gap
+	const cellWidth, barWidth = 22, 10
+	columns := max(1, min(4, (m.width-2)/cellWidth))
+
+	var lines []string
+	core := 0
+	for _, g := range groups {
+		lines = append(lines, headerStyle.Render(fmt.Sprintf(" %s (%d)", g.Name, g.Count)))
+		var row []string
+		for i := 0; i < g.Count; i++ {
+			percent := m.cpuPercent[core]
+			core++
+			filled := min(barWidth, max(0, int(percent/100*barWidth+0.5)))
+			bar := lipgloss.NewStyle().Foreground(levelColor(percent)).Render(strings.Repeat("█", filled)) +
+				lipgloss.NewStyle().Foreground(components.ColorBorder).Render(strings.Repeat("░", barWidth-filled))
+			row = append(row, nameStyle.Render(fmt.Sprintf(" %-3s", fmt.Sprintf("%s%d", g.Prefix, i+1)))+
+				"["+bar+"]"+fmt.Sprintf(" %3.0f%%  ", percent))
+			if len(row) == columns || i == g.Count-1 {
+				lines = append(lines, strings.Join(row, ""))
+				row = nil
+			}
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -1,17 +1,30 @@
 package dashboard
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/caioricciuti/dev-cockpit/internal/alerts"
+	"github.com/caioricciuti/dev-cockpit/internal/battery"
+	"github.com/caioricciuti/dev-cockpit/internal/ci"
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/cpusample"
+	"github.com/caioricciuti/dev-cockpit/internal/proctable"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/startup"
+	"github.com/caioricciuti/dev-cockpit/internal/thermal"
 	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/mem"
@@ -21,22 +34,58 @@ import (
 // Model represents the dashboard state
 type Model struct {
 	config *config.Config
+	runner runner.Runner
 	width  int
 	height int
 
 	// System metrics
-	cpuPercent    []float64
+	cpuPercent    []float64 // per core
+	coreGroups    []coreGroup
 	cpuHistory    []float64
 	memoryPercent float64
 	memoryHistory []float64
+	vm            vmStats // swap and memory pressure
+	vmOK          bool
+	swapinRate    float64 // pages per second
+	swapoutRate   float64
 	diskUsage     float64
 	diskHistory   []float64
-
-	// Network metrics
-	netStats     []net.IOCountersStat
-	netInRate    float64
-	netOutRate   float64
-	prevNetStats *net.IOCountersStat
+	gpuPercent    float64
+	gpuHistory    []float64
+	gpuAvailable  bool // the GPU reports its utilization
+
+	// Ten minutes of the same, for the W graphs
+	cpuLongHistory    []float64
+	memoryLongHistory []float64
+	diskLongHistory   []float64
+	gpuLongHistory    []float64
+	longSamples       int // how many of the ten minutes have been sampled
+
+	// Temperatures and fan speed
+	thermal            thermal.Reading
+	thermalErr         error
+	thermalAuthorizing bool // waiting for the password powermetrics needs
+
+	// Battery, with five minutes of the power flowing in or out
+	power        battery.Status
+	powerErr     error
+	powerHistory []float64
+
+	// Disk I/O, from the cumulative counters of each disk
+	diskRates        []diskRate
+	diskReadHistory  []float64
+	diskWriteHistory []float64
+	prevDiskIO       map[string]disk.IOCountersStat
+
+	// Network metrics, from the cumulative counters of each interface
+	netRates      []ifaceRate
+	netInRate     float64 // all interfaces but loopback
+	netOutRate    float64
+	netInHistory  []float64
+	netOutHistory []float64
+	prevNetIO     map[string]net.IOCountersStat
+	netActive     map[string]bool // interfaces that have carried traffic
+	defaultIface  string          // carries the default route
 
 	// System info
 	hostname   string
@@ -46,19 +95,57 @@ type Model struct {
 	totalMem   uint64
 	lastUpdate time.Time
 
+	// Heaviest processes, selectable for a kill
+	procs         []proctable.Process
+	procCursor    int
+	procsByMemory bool
+
+	// Latest GitHub Actions run of each repository
+	ciRuns []ci.Run
+	ciErr  error
+	ciNext time.Time // when the CI runs may be fetched again
+
 	// UI state
-	selectedMetric int
-	showDetails    bool
+	widgets     []string      // shown, in order, from modules.dashboard.widgets
+	compactMode string        // auto, always or never
+	refresh     time.Duration // between metric samples; + and - change it
+	showDetails bool          // per-core CPU bars
+	longHistory bool          // graph ten minutes instead of one
+	history     *historyView  // open History view of the recorded metrics
+
+	alerts *alerts.Engine // threshold rules from config, nil without any
+
+	// The background tasks read CPU usage between their own runs, as
+	// cpusample.Default stops while the dashboard is hidden
+	alertsCPU *cpusample.Sampler
+	recordCPU *cpusample.Sampler
 }
 
 // New creates a new dashboard module
 func New(cfg *config.Config) *Model {
 	m := &Model{
-		config:         cfg,
-		cpuHistory:     make([]float64, 60), // 60 seconds of history
-		memoryHistory:  make([]float64, 60),
-		diskHistory:    make([]float64, 60),
-		selectedMetric: 0,
+		config:        cfg,
+		runner:        runner.Default,
+		alertsCPU:     cpusample.New(cpusample.Times),
+		recordCPU:     cpusample.New(cpusample.Times),
+		cpuHistory:    make([]float64, 60), // 60 seconds of history
+		memoryHistory: make([]float64, 60),
+		diskHistory:   make([]float64, 60),
+		gpuHistory:    make([]float64, 60),
+		netInHistory:  make([]float64, 60),
+		netOutHistory: make([]float64, 60),
+
+		powerHistory:     make([]float64, 60),
+		diskReadHistory:  make([]float64, 60),
+		diskWriteHistory: make([]float64, 60),
+		refresh:          time.Second,
+		widgets:          readWidgets(cfg),
+	}
+	if cfg != nil && cfg.Modules.Dashboard.RefreshRate > 0 {
+		m.refresh = time.Duration(cfg.Modules.Dashboard.RefreshRate) * time.Second
+	}
+	if cfg != nil {
+		m.compactMode = cfg.Modules.Dashboard.Compact
 	}
 
 	// Initialize system info
@@ -67,12 +154,32 @@ func New(cfg *config.Config) *Model {
 	return m
 }
 
-// Init initializes the dashboard
+// Init initializes the dashboard. Metrics are refreshed by the scheduler.
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(
-		m.tickCmd(),
-		m.fetchMetrics(),
-	)
+	return nil
+}
+
+// Schedule refreshes the metrics every refresh_rate seconds, and samples
+// the CPU every second, while the dashboard is shown, and checks the alert
+// rules even while it's hidden
+func (m *Model) Schedule() []scheduler.Task {
+	tasks := []scheduler.Task{
+		cpusample.Default.Task(),
+		{Name: "metrics", Every: m.refresh, Run: m.fetchMetrics},
+		{Name: "thermal", Every: 5 * time.Second, Run: m.fetchThermal},
+		{Name: "route", Every: 10 * time.Second, Run: m.fetchRoute},
+		{Name: "battery", Every: batteryEvery, Run: m.fetchBattery},
+	}
+	if m.shows(widgetCI) {
+		tasks = append(tasks, scheduler.Task{Name: "ci", Every: ciEvery, Run: m.fetchCI})
+	}
+	if task := m.alertsTask(); task != nil {
+		tasks = append(tasks, *task)
+	}
+	if task := m.recordTask(); task != nil {
+		tasks = append(tasks, *task)
+	}
+	return tasks
 }
 
 // Update handles messages
@@ -82,28 +189,78 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case events.Nav:
+		m.procCursor = msg.Move(m.procCursor, len(m.procs))
+
 	case tea.KeyMsg:
+		if m.history != nil {
+			return m, m.handleHistoryKey(msg)
+		}
 		switch msg.String() {
-		case "up", "k":
-			m.selectedMetric--
-			if m.selectedMetric < 0 {
-				m.selectedMetric = 3
-			}
-		case "down", "j":
-			m.selectedMetric = (m.selectedMetric + 1) % 4
-		case "enter", " ":
+		case "h":
+			return m, m.openHistory()
+		case "enter", " ", "c":
 			m.showDetails = !m.showDetails
+		case "w":
+			m.longHistory = !m.longHistory
 		case "r":
 			return m, m.fetchMetrics()
+		case "+", "=":
+			return m, m.setRefresh(scheduler.Step(refreshSteps, m.refresh, true))
+		case "-":
+			return m, m.setRefresh(scheduler.Step(refreshSteps, m.refresh, false))
+		case "s":
+			return m, m.saveSnapshot()
+		case "t":
+			return m, m.authorizeThermal()
+		case "m":
+			m.procsByMemory = !m.procsByMemory
+			m.procCursor = 0
+			return m, m.fetchMetrics()
+		case "x":
+			return m, m.confirmSignal("TERM")
+		case "X":
+			return m, m.confirmSignal("KILL")
+		case "o":
+			return m, m.openFailedRun()
 		}
 
 	case metricsMsg:
 		m.updateMetrics(msg)
-		return m, tea.Batch(m.tickCmd(), m.fetchMetrics())
+		startup.Mark(startup.FirstMetrics)
+
+	case signalMsg:
+		return m, m.sendSignal(msg)
+
+	case signalledMsg:
+		return m, tea.Batch(signalToast(msg), m.fetchMetrics())
+
+	case snapshotSavedMsg:
+		return m, snapshotToast(msg)
+
+	case batteryMsg:
+		m.updateBattery(msg)
+
+	case ciMsg:
+		m.ciRuns, m.ciErr, m.ciNext = msg.runs, msg.err, msg.next
 
-	case tickMsg:
-		// Smooth scrolling for graphs
-		return m, m.tickCmd()
+	case ciOpenedMsg:
+		return m, ciOpenedToast(msg)
+
+	case routeMsg:
+		m.defaultIface = msg.iface
+
+	case thermalMsg:
+		m.updateThermal(msg)
+
+	case alertsMsg:
+		return m, alertToasts(msg)
+
+	case historyMsg:
+		if m.history != nil {
+			m.history.loading = false
+			m.history.to, m.history.samples, m.history.err = msg.to, msg.samples, msg.err
+		}
 	}
 
 	return m, nil
@@ -117,14 +274,32 @@ func (m *Model) View() string {
 
 	// Use Layout system to calculate available space
 	layout := components.NewLayout(m.width, m.height)
+	if m.history != nil {
+		return components.Viewport(m.renderHistoryView(), layout.ContentHeight)
+	}
+
+	if m.compact() {
+		return components.Viewport(m.renderCompact(), layout.ContentHeight)
+	}
+
+	// Wide terminals fit the processes beside the metrics
+	metrics := m.renderMetrics()
+	if metrics == "" {
+		metrics = m.renderSide(m.width)
+	} else if side := m.width - lipgloss.Width(metrics) - 4; side >= 45 {
+		metrics = lipgloss.JoinHorizontal(lipgloss.Top, metrics, "    ", m.renderSide(side))
+	} else if side := m.renderSide(m.width); side != "" {
+		metrics = lipgloss.JoinVertical(lipgloss.Left, metrics, "", side)
+	}
 
 	// Build all sections
 	sections := []string{
 		m.renderSystemInfo(),
 		"",
-		m.renderMetrics(),
-		"",
-		m.renderAdvancedMetrics(),
+		metrics,
+	}
+	if m.shows(widgetInsights) {
+		sections = append(sections, "", m.renderAdvancedMetrics())
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
@@ -133,22 +308,55 @@ func (m *Model) View() string {
 	return components.Viewport(content, layout.ContentHeight)
 }
 
+// renderSide is the column beside the metrics, or below them on narrow
+// terminals: the heaviest processes, the battery and CI, in the
+// configured order
+func (m *Model) renderSide(width int) string {
+	var panels []string
+	for _, widget := range m.widgets {
+		panel := ""
+		switch widget {
+		case widgetProcesses:
+			panel = m.renderProcesses(width)
+		case widgetBattery:
+			panel = m.renderBattery(width)
+		case widgetCI:
+			panel = m.renderCI(width)
+		}
+		if panel == "" {
+			continue
+		}
+		if len(panels) > 0 {
+			panels = append(panels, "")
+		}
+		panels = append(panels, panel)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, panels...)
+}
+
 func (m *Model) renderSystemInfo() string {
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Padding(0, 1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Width(12)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFF"))
+		Foreground(components.ColorBright)
+
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
 
 	// Build info lines vertically with proper spacing
 	infoLines := []string{
-		headerStyle.Render("📊 SYSTEM DASHBOARD"),
+		headerStyle.Render("📊 SYSTEM DASHBOARD") + hintStyle.Render(fmt.Sprintf("⟳ every %v · +/-", m.refresh)),
+	}
+	if !m.shows(widgetSystem) {
+		return infoLines[0]
+	}
+	infoLines = append(infoLines,
 		"",
 		fmt.Sprintf("%s %s", labelStyle.Render("Hostname:"), valueStyle.Render(m.hostname)),
 		fmt.Sprintf("%s %s", labelStyle.Render("Platform:"), valueStyle.Render(m.platform)),
@@ -156,12 +364,16 @@ func (m *Model) renderSystemInfo() string {
 		fmt.Sprintf("%s %.1f GB", labelStyle.Render("Memory:"), float64(m.totalMem)/1024/1024/1024),
 		fmt.Sprintf("%s %s", labelStyle.Render("Uptime:"), valueStyle.Render(m.formatUptime())),
 		"",
-	}
+	)
 
 	return lipgloss.JoinVertical(lipgloss.Left, infoLines...)
 }
 
 func (m *Model) renderMetrics() string {
+	if !m.showsMetrics() {
+		return ""
+	}
+
 	// Calculate average CPU
 	avgCPU := 0.0
 	for _, cpu := range m.cpuPercent {
@@ -172,25 +384,29 @@ func (m *Model) renderMetrics() string {
 	}
 
 	// Separator line
-	separatorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#444"))
-	separator := separatorStyle.Render(strings.Repeat("━", 60))
+	separatorStyle := lipgloss.NewStyle().Foreground(components.ColorFaint)
+	// Narrow in split view, where the dashboard gets a pane of its own
+	separator := separatorStyle.Render(strings.Repeat("━", min(60, m.width-4)))
 
 	// Label styles
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFF"))
+		Foreground(components.ColorBright)
 
 	statusStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#0FD976"))
+		Foreground(components.ColorSuccess)
 
 	warningStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFA500"))
+		Foreground(components.ColorWarning)
 
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF6B6B"))
+		Foreground(components.ColorError)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(components.ColorMuted)
 
 	// Build metrics lines
 	lines := []string{
@@ -198,72 +414,122 @@ func (m *Model) renderMetrics() string {
 		"",
 	}
 
-	// CPU Metric
-	cpuStatus := "● Normal"
-	cpuStatusStyle := statusStyle
-	if avgCPU >= 85 {
-		cpuStatus = "● Critical"
-		cpuStatusStyle = errorStyle
-	} else if avgCPU >= 70 {
-		cpuStatus = "● High"
-		cpuStatusStyle = warningStyle
-	}
-	lines = append(lines,
-		labelStyle.Render("⚡ CPU: ")+valueStyle.Render(fmt.Sprintf("%.1f%%", avgCPU)),
-		m.renderProgressBar(avgCPU)+" "+cpuStatusStyle.Render(cpuStatus),
-		"",
-	)
-
-	// Memory Metric
-	memStatus := "● Healthy"
-	memStatusStyle := statusStyle
-	if m.memoryPercent >= 90 {
-		memStatus = "● Critical"
-		memStatusStyle = errorStyle
-	} else if m.memoryPercent >= 75 {
-		memStatus = "● High"
-		memStatusStyle = warningStyle
-	}
-	lines = append(lines,
-		labelStyle.Render("💾 Memory: ")+valueStyle.Render(fmt.Sprintf("%.1f%%", m.memoryPercent)),
-		m.renderProgressBar(m.memoryPercent)+" "+memStatusStyle.Render(memStatus),
-		"",
-	)
-
-	// Disk Metric
-	diskStatus := "● Healthy"
-	diskStatusStyle := statusStyle
-	if m.diskUsage >= 90 {
-		diskStatus = "● Critical"
-		diskStatusStyle = errorStyle
-	} else if m.diskUsage >= 80 {
-		diskStatus = "● Low Space"
-		diskStatusStyle = warningStyle
-	}
-	lines = append(lines,
-		labelStyle.Render("💿 Disk: ")+valueStyle.Render(fmt.Sprintf("%.1f%%", m.diskUsage)),
-		m.renderProgressBar(m.diskUsage)+" "+diskStatusStyle.Render(diskStatus),
-		"",
-	)
+	for _, widget := range m.widgets {
+		switch widget {
+		case widgetCPU:
+			// CPU Metric
+			cpuStatus := "● Normal"
+			cpuStatusStyle := statusStyle
+			if avgCPU >= 85 {
+				cpuStatus = "● Critical"
+				cpuStatusStyle = errorStyle
+			} else if avgCPU >= 70 {
+				cpuStatus = "● High"
+				cpuStatusStyle = warningStyle
+			}
+			lines = append(lines,
+				labelStyle.Render("⚡ CPU: ")+valueStyle.Render(fmt.Sprintf("%.1f%%", avgCPU))+hintStyle.Render("  C per-core · W "+formatSpan(longHistoryLen*m.refresh)),
+				m.renderProgressBar(avgCPU)+" "+cpuStatusStyle.Render(cpuStatus),
+				m.renderHistory(m.cpuHistory, m.cpuLongHistory, avgCPU),
+			)
+			if m.showDetails && len(m.cpuPercent) > 0 {
+				lines = append(lines, "", m.renderCores())
+			}
+			lines = append(lines, "")
+		case widgetGPU:
+			// GPU Metric, on Macs whose GPU reports its utilization
+			if m.gpuAvailable {
+				gpuStatus := "● Idle"
+				gpuStatusStyle := statusStyle
+				if m.gpuPercent >= 85 {
+					gpuStatus = "● Saturated"
+					gpuStatusStyle = errorStyle
+				} else if m.gpuPercent >= 50 {
+					gpuStatus = "● Busy"
+					gpuStatusStyle = warningStyle
+				} else if m.gpuPercent >= 5 {
+					gpuStatus = "● Active"
+				}
+				lines = append(lines,
+					labelStyle.Render("🎮 GPU: ")+valueStyle.Render(fmt.Sprintf("%.1f%%", m.gpuPercent)),
+					m.renderProgressBar(m.gpuPercent)+" "+gpuStatusStyle.Render(gpuStatus),
+					m.renderHistory(m.gpuHistory, m.gpuLongHistory, m.gpuPercent),
+					"",
+				)
+			}
+		case widgetThermal:
+			lines = append(lines, m.renderThermal(labelStyle, hintStyle)...)
+		case widgetMemory:
+			// Memory Metric
+			memStatus := "● Healthy"
+			memStatusStyle := statusStyle
+			if m.memoryPercent >= 90 {
+				memStatus = "● Critical"
+				memStatusStyle = errorStyle
+			} else if m.memoryPercent >= 75 {
+				memStatus = "● High"
+				memStatusStyle = warningStyle
+			}
+			lines = append(lines,
+				labelStyle.Render("💾 Memory: ")+valueStyle.Render(fmt.Sprintf("%.1f%%", m.memoryPercent))+m.renderPressure(),
+				m.renderProgressBar(m.memoryPercent)+" "+memStatusStyle.Render(memStatus)+m.renderSwap(),
+				m.renderHistory(m.memoryHistory, m.memoryLongHistory, m.memoryPercent),
+				"",
+			)
+		case widgetDisk:
+			// Disk Metric
+			diskStatus := "● Healthy"
+			diskStatusStyle := statusStyle
+			if m.diskUsage >= 90 {
+				diskStatus = "● Critical"
+				diskStatusStyle = errorStyle
+			} else if m.diskUsage >= 80 {
+				diskStatus = "● Low Space"
+				diskStatusStyle = warningStyle
+			}
+			lines = append(lines,
+				labelStyle.Render("💿 Disk: ")+valueStyle.Render(fmt.Sprintf("%.1f%%", m.diskUsage)),
+				m.renderProgressBar(m.diskUsage)+" "+diskStatusStyle.Render(diskStatus),
+			)
+			// Throughput says more than the slow-moving usage history, so its
+			// graphs take the history's place once two samples are in
+			if io := m.renderDiskIO(min(38, m.width-28)); io != nil {
+				lines = append(lines, io...)
+			} else {
+				lines = append(lines, m.renderHistory(m.diskHistory, m.diskLongHistory, m.diskUsage))
+			}
+			lines = append(lines, "")
+		case widgetNetwork:
+			// Network Metric
+			totalRate := (m.netInRate + m.netOutRate) / 1024 / 1024
+			netStatus := "Idle"
+			if totalRate > 10 {
+				netStatus = "High Activity"
+			} else if totalRate > 1 {
+				netStatus = "Active"
+			} else if totalRate > 0.1 {
+				netStatus = "Light"
+			}
 
-	// Network Metric
-	totalRate := (m.netInRate + m.netOutRate) / 1024 / 1024
-	netStatus := "Idle"
-	if totalRate > 10 {
-		netStatus = "High Activity"
-	} else if totalRate > 1 {
-		netStatus = "Active"
-	} else if totalRate > 0.1 {
-		netStatus = "Light"
-	}
-
-	netSubStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888"))
-	lines = append(lines,
-		labelStyle.Render("🌐 Network: ")+valueStyle.Render(netStatus),
-		"  "+netSubStyle.Render(fmt.Sprintf("▼ Down: %.1f KB/s", m.netInRate/1024)),
-		"  "+netSubStyle.Render(fmt.Sprintf("▲ Up: %.1f KB/s", m.netOutRate/1024)),
-		"",
-	)
+			// Rates have no fixed range, so each graph scales to its own peak
+			netSubStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle).Width(22)
+			netGraphStyle := lipgloss.NewStyle().Foreground(components.ColorAccent)
+			netGraphWidth := min(38, m.width-28)
+			via := ""
+			if m.defaultIface != "" {
+				via = lipgloss.NewStyle().Foreground(components.ColorMuted).Render("  via " + m.defaultIface)
+			}
+			lines = append(lines,
+				labelStyle.Render("🌐 Network: ")+valueStyle.Render(netStatus)+via,
+				"  "+netSubStyle.Render(fmt.Sprintf("▼ Down: %.1f KB/s", m.netInRate/1024))+
+					netGraphStyle.Render(components.Sparkline(m.netInHistory, netGraphWidth, 0)),
+				"  "+netSubStyle.Render(fmt.Sprintf("▲ Up: %.1f KB/s", m.netOutRate/1024))+
+					netGraphStyle.Render(components.Sparkline(m.netOutHistory, netGraphWidth, 0)),
+			)
+			lines = append(lines, m.renderInterfaces()...)
+			lines = append(lines, "")
+		}
+	}
 
 	lines = append(lines, separator)
 
@@ -281,16 +547,9 @@ func (m *Model) renderProgressBar(percent float64) string {
 		filled = 0
 	}
 
-	// Choose color based on percentage
-	barColor := "#0FD976" // Green
-	if percent >= 85 {
-		barColor = "#FF6B6B" // Red
-	} else if percent >= 70 {
-		barColor = "#FFA500" // Orange
-	}
-
-	filledStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(barColor))
-	emptyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#333"))
+	barColor := levelColor(percent)
+	filledStyle := lipgloss.NewStyle().Foreground(barColor)
+	emptyStyle := lipgloss.NewStyle().Foreground(components.ColorBorder)
 
 	bar := "["
 	bar += filledStyle.Render(strings.Repeat("█", filled))
@@ -300,28 +559,51 @@ func (m *Model) renderProgressBar(percent float64) string {
 	return bar
 }
 
+// renderHistory graphs the last minute of a percentage under its bar, in
+// the color of the current level, or the last ten minutes averaged to the
+// same width with W
+func (m *Model) renderHistory(history, long []float64, current float64) string {
+	graphStyle := lipgloss.NewStyle().Foreground(levelColor(current))
+	labelStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	width := min(len(history), m.width-10)
+	label := " " + formatSpan(time.Duration(len(history))*m.refresh)
+	if m.longHistory && long != nil {
+		history, label = downsample(long, width), " "+formatSpan(longHistoryLen*m.refresh)
+	}
+	return " " + graphStyle.Render(components.Sparkline(history, width, 100)) + labelStyle.Render(label)
+}
+
+// levelColor is the color of a usage percentage: green, then yellow from
+// 70% and red from 85%
+func levelColor(percent float64) lipgloss.AdaptiveColor {
+	switch {
+	case percent >= 85:
+		return components.ColorError
+	case percent >= 70:
+		return components.ColorWarning
+	default:
+		return components.ColorSuccess
+	}
+}
+
 func (m *Model) renderAdvancedMetrics() string {
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Padding(0, 1)
 
+	// Wrap long insights to the available width
 	insightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#DDD"))
+		Foreground(components.ColorText).
+		Width(m.width - 4)
 
 	header := headerStyle.Render("💡 System Insights")
 
 	insights, score := m.generateAdvancedInsights()
 
 	// Format score with color
-	scoreColor := "#0FD976"
-	if score < 50 {
-		scoreColor = "#FF6B6B"
-	} else if score < 70 {
-		scoreColor = "#FFA500"
-	}
 	scoreText := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(scoreColor)).
+		Foreground(scoreColor(score)).
 		Bold(true).
 		Render(fmt.Sprintf("Performance Score: %d/100", score))
 
@@ -341,11 +623,17 @@ func (m *Model) renderAdvancedMetrics() string {
 	return lipgloss.JoinVertical(lipgloss.Left, insightLines...)
 }
 
-func (m *Model) getBoxColor(index int) lipgloss.Color {
-	if index == m.selectedMetric {
-		return lipgloss.Color("#00D9FF")
+// scoreColor is red for a performance score under 50, yellow under 70
+// and green otherwise
+func scoreColor(score int) lipgloss.AdaptiveColor {
+	switch {
+	case score < 50:
+		return components.ColorError
+	case score < 70:
+		return components.ColorWarning
+	default:
+		return components.ColorSuccess
 	}
-	return lipgloss.Color("#444")
 }
 
 func (m *Model) updateSystemInfo() {
@@ -355,6 +643,7 @@ func (m *Model) updateSystemInfo() {
 	}
 	m.platform = runtime.GOOS
 	m.numCPU = runtime.NumCPU()
+	m.coreGroups = readCoreGroups(m.runner)
 
 	if v, err := mem.VirtualMemory(); err == nil {
 		m.totalMem = v.Total
@@ -366,8 +655,15 @@ func (m *Model) updateSystemInfo() {
 }
 
 func (m *Model) updateMetrics(msg metricsMsg) {
-	// Update CPU
-	m.cpuPercent = msg.cpu
+	// Keep the last list when ps fails
+	if msg.procs != nil {
+		m.updateProcesses(msg.procs)
+	}
+
+	// Update CPU, keeping the last reading until the sampler has one
+	if msg.cpu != nil {
+		m.cpuPercent = msg.cpu
+	}
 	avgCPU := 0.0
 	for _, cpu := range m.cpuPercent {
 		avgCPU += cpu
@@ -376,28 +672,31 @@ func (m *Model) updateMetrics(msg metricsMsg) {
 		avgCPU /= float64(len(m.cpuPercent))
 	}
 	m.cpuHistory = append(m.cpuHistory[1:], avgCPU)
+	m.cpuLongHistory = appendLong(m.cpuLongHistory, avgCPU)
+	m.longSamples = min(m.longSamples+1, longHistoryLen)
 
 	// Update Memory
 	m.memoryPercent = msg.memory
 	m.memoryHistory = append(m.memoryHistory[1:], m.memoryPercent)
+	m.memoryLongHistory = appendLong(m.memoryLongHistory, m.memoryPercent)
+	m.updateVM(msg.vm, msg.vmOK, time.Since(m.lastUpdate).Seconds())
 
 	// Update Disk
 	m.diskUsage = msg.disk
 	m.diskHistory = append(m.diskHistory[1:], m.diskUsage)
+	m.diskLongHistory = appendLong(m.diskLongHistory, m.diskUsage)
+
+	// Update GPU
+	m.gpuAvailable = msg.gpuOK
+	m.gpuPercent = msg.gpu
+	m.gpuHistory = append(m.gpuHistory[1:], m.gpuPercent)
+	m.gpuLongHistory = appendLong(m.gpuLongHistory, m.gpuPercent)
+
+	// Update Disk I/O
+	m.updateDiskIO(msg.diskIO, time.Since(m.lastUpdate).Seconds())
 
 	// Update Network
-	m.netStats = msg.network
-	if len(msg.network) > 0 && m.prevNetStats != nil {
-		// Calculate rate (bytes per second)
-		timeDiff := time.Since(m.lastUpdate).Seconds()
-		if timeDiff > 0 {
-			m.netInRate = float64(msg.network[0].BytesRecv-m.prevNetStats.BytesRecv) / timeDiff
-			m.netOutRate = float64(msg.network[0].BytesSent-m.prevNetStats.BytesSent) / timeDiff
-		}
-	}
-	if len(msg.network) > 0 {
-		m.prevNetStats = &msg.network[0]
-	}
+	m.updateNetIO(msg.network, time.Since(m.lastUpdate).Seconds())
 
 	m.lastUpdate = time.Now()
 }
@@ -414,16 +713,17 @@ func (m *Model) formatUptime() string {
 }
 
 func (m *Model) generateAdvancedInsights() ([]string, int) {
-	cpuForecast, cpuSlope := m.forecastUsage(m.cpuHistory, 60)
-	memForecast, memSlope := m.forecastUsage(m.memoryHistory, 60)
+	minute := max(int(time.Minute/m.refresh), 1) // samples
+	cpuForecast, cpuSlope := m.forecastUsage(m.cpuHistory, minute)
+	memForecast, memSlope := m.forecastUsage(m.memoryHistory, minute)
 	diskLevel := m.diskUsage
 	cpuTrend := describeTrend(cpuSlope)
 	memTrend := describeTrend(memSlope)
 	cpuVolatility := calculateVolatility(m.cpuHistory)
 	memVolatility := calculateVolatility(m.memoryHistory)
 	avgVolatility := (cpuVolatility + memVolatility) / 2
-	cpuSaturation := timeToThreshold(m.cpuHistory, 85)
-	memSaturation := timeToThreshold(m.memoryHistory, 90)
+	cpuSaturation := timeToThreshold(m.cpuHistory, 85, m.refresh)
+	memSaturation := timeToThreshold(m.memoryHistory, 90, m.refresh)
 
 	riskLabel, riskReason := operationalRisk(cpuForecast, memForecast, diskLevel, avgVolatility)
 	recommendations := recommendActions(riskLabel, cpuForecast, memForecast, memSaturation, cpuSaturation)
@@ -433,6 +733,10 @@ func (m *Model) generateAdvancedInsights() ([]string, int) {
 		fmt.Sprintf("• Memory 60s forecast: %.1f%% (%s, σ=%.1f)", memForecast, memTrend, memVolatility),
 	}
 
+	if trend := m.longTrend(); trend != "" {
+		insights = append(insights, trend)
+	}
+
 	if cpuSaturation > 0 {
 		insights = append(insights, fmt.Sprintf("• CPU headroom: ~%s to reach 85%% load", formatShortDuration(cpuSaturation)))
 	} else {
@@ -457,7 +761,7 @@ func (m *Model) generateAdvancedInsights() ([]string, int) {
 	return insights, score
 }
 
-func (m *Model) forecastUsage(history []float64, horizonSeconds int) (float64, float64) {
+func (m *Model) forecastUsage(history []float64, horizon int) (float64, float64) {
 	if len(history) == 0 {
 		return 0, 0
 	}
@@ -468,7 +772,7 @@ func (m *Model) forecastUsage(history []float64, horizonSeconds int) (float64, f
 	}
 	recent := history[len(history)-window:]
 	slope, intercept := linearRegression(recent)
-	forecastIndex := float64(window - 1 + horizonSeconds)
+	forecastIndex := float64(window - 1 + horizon)
 	predicted := intercept + slope*forecastIndex
 	return clamp(predicted, 0, 100), slope
 }
@@ -534,7 +838,9 @@ func calculateVolatility(values []float64) float64 {
 	return math.Sqrt(variance)
 }
 
-func timeToThreshold(history []float64, threshold float64) time.Duration {
+// timeToThreshold is when history, sampled every interval, reaches
+// threshold at its recent pace, or 0 when it isn't heading there
+func timeToThreshold(history []float64, threshold float64, every time.Duration) time.Duration {
 	if len(history) < 2 {
 		return 0
 	}
@@ -551,12 +857,12 @@ func timeToThreshold(history []float64, threshold float64) time.Duration {
 		return 0
 	}
 
-	seconds := (threshold - current) / slope
-	if seconds <= 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+	samples := (threshold - current) / slope
+	if samples <= 0 || math.IsInf(samples, 0) || math.IsNaN(samples) {
 		return 0
 	}
 
-	return time.Duration(seconds) * time.Second
+	return time.Duration(samples * float64(every))
 }
 
 func operationalRisk(cpuForecast, memForecast, diskUsage, volatility float64) (string, string) {
@@ -684,48 +990,122 @@ func (m *Model) Title() string {
 	return "Dashboard"
 }
 
+// Help describes the dashboard's keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "DASHBOARD",
+		Description: "Live CPU, GPU, memory pressure and swap, disk usage and I/O, and network metrics with temperatures and the heaviest processes, refreshed every few seconds, and the latest CI run of your GitHub repositories.",
+		Sections: []help.Section{{Title: "Keys", Bindings: []help.Binding{
+			{Key: "↑/↓", Desc: "Select a process in Top Processes"},
+			{Key: "M", Desc: "List the heaviest processes by memory instead of CPU, or back"},
+			{Key: "X", Desc: "Quit the selected process (SIGTERM), after asking"},
+			{Key: "Shift+X", Desc: "Force quit it (SIGKILL); unsaved work in it is lost"},
+			{Key: "O", Desc: "Open the latest failing GitHub Actions run in the CI panel in the browser"},
+			{Key: "H", Desc: "Browse the metrics recorded every minute over the last hour to 30 days"},
+			{Key: "W", Desc: "Graph the last ten minutes instead of the last minute, or back"},
+			{Key: "C / Enter", Desc: "Show or hide a bar per CPU core, efficiency and performance cores apart on Apple silicon"},
+			{Key: "T", Desc: "Read temperatures and fan speed with powermetrics, which asks for your password (not needed with smctemp installed)"},
+			{Key: "S", Desc: "Save a health snapshot as JSON to the snapshots store, to compare against later"},
+			{Key: "y", Desc: "Copy the health snapshot as Markdown, for pasting into a chat or ticket"},
+			{Key: "R", Desc: "Refresh now"},
+			{Key: "+ / -", Desc: "Sample more or less often (1s to 30s); graphs then cover a longer span"},
+		}}},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
 func (m *Model) HasOpenModal() bool {
-	return false
+	return m.history != nil
+}
+
+// savedState is what the module keeps between launches
+type savedState struct {
+	Cores       bool `json:"cores,omitempty"`
+	ByMemory    bool `json:"by_memory,omitempty"`
+	LongHistory bool `json:"long_history,omitempty"`
+}
+
+// SaveState returns whether the per-core view is open, how processes are
+// ranked and how far back the graphs go
+func (m *Model) SaveState() (json.RawMessage, error) {
+	return json.Marshal(savedState{Cores: m.showDetails, ByMemory: m.procsByMemory, LongHistory: m.longHistory})
+}
+
+// RestoreState applies state saved by the previous launch
+func (m *Model) RestoreState(data json.RawMessage) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	m.showDetails = saved.Cores
+	m.procsByMemory = saved.ByMemory
+	m.longHistory = saved.LongHistory
+	return nil
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Refresh metrics", Hint: "Sample CPU, memory, disk and network now", Msg: palette.Key("r")},
+		{Title: "Toggle per-core CPU", Hint: "A bar for every CPU core", Msg: palette.Key("c")},
+		{Title: "Metrics history", Hint: "CPU, memory, disk and GPU over past hours and days", Msg: palette.Key("h")},
+		{Title: "Toggle 10-minute graphs", Hint: "Graph the last ten minutes of CPU, GPU, memory and disk", Msg: palette.Key("w")},
+		{Title: "Rank processes by memory", Hint: "Toggle Top Processes between CPU and memory", Msg: palette.Key("m")},
+		{Title: "Save health snapshot", Hint: "Metrics, insights and top processes as JSON", Msg: palette.Key("s")},
+		{Title: "Open failing CI run", Hint: "The latest failing GitHub Actions run, in the browser", Msg: palette.Key("o")},
+		{Title: "Read temperatures", Hint: "CPU/GPU die temperature and fan speed via powermetrics", Msg: palette.Key("t")},
+	}
 }
 
 // Messages
 type metricsMsg struct {
 	cpu     []float64
 	memory  float64
+	vm      vmStats
+	vmOK    bool
 	disk    float64
+	gpu     float64
+	gpuOK   bool
+	diskIO  map[string]disk.IOCountersStat
 	network []net.IOCountersStat
-}
-
-type tickMsg time.Time
-
-func (m *Model) tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	procs   []proctable.Process
 }
 
 func (m *Model) fetchMetrics() tea.Cmd {
 	return func() tea.Msg {
-		// Fetch CPU
-		cpuPercent, _ := cpu.Percent(time.Second, true)
+		// Fetch CPU, as of the sampler's last reading
+		cpuPercent := cpusample.Default.Cores()
 
 		// Fetch Memory
 		memInfo, _ := mem.VirtualMemory()
 		memPercent := memInfo.UsedPercent
+		vm, vmOK := readVM(m.runner)
 
 		// Fetch Disk
 		diskInfo, _ := disk.Usage("/")
 		diskPercent := diskInfo.UsedPercent
+		diskIO, _ := disk.IOCounters()
+
+		// Fetch GPU
+		gpuPercent, gpuOK := readGPU(m.runner)
 
 		// Fetch Network
-		netInfo, _ := net.IOCounters(false)
+		netInfo, _ := net.IOCounters(true)
+
+		// Fetch processes
+		procs, _ := proctable.Read(context.Background(), m.runner)
 
 		return metricsMsg{
 			cpu:     cpuPercent,
 			memory:  memPercent,
+			vm:      vm,
+			vmOK:    vmOK,
 			disk:    diskPercent,
+			diskIO:  diskIO,
+			gpu:     gpuPercent,
+			gpuOK:   gpuOK,
 			network: netInfo,
+			procs:   procs,
 		}
 	}
 }
@@ -0,0 +1,590 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/battery"
+	"github.com/caioricciuti/dev-cockpit/internal/ci"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/proctable"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/thermal"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// snapshotModel returns a dashboard with fixed metrics instead of host data
+func snapshotModel() *Model {
+	m := &Model{
+		cpuHistory:    make([]float64, 60),
+		memoryHistory: make([]float64, 60),
+		diskHistory:   make([]float64, 60),
+		gpuHistory:    make([]float64, 60),
+		netInHistory:  make([]float64, 60),
+		netOutHistory: make([]float64, 60),
+
+		diskReadHistory:  make([]float64, 60),
+		diskWriteHistory: make([]float64, 60),
+		refresh:          time.Second,
+		widgets:          allWidgets,
+		compactMode:      "never",
+		hostname:         "devbox.local",
+		platform:         "darwin",
+		numCPU:           10,
+		totalMem:         32 * 1024 * 1024 * 1024,
+		uptime:           49*time.Hour + 15*time.Minute,
+	}
+	// A minute of history: CPU ramping up, memory flat, the network
+	// idle for the first half
+	for i := range m.cpuHistory {
+		m.cpuHistory[i] = float64(20 + i)
+		m.memoryHistory[i] = 62
+		m.diskHistory[i] = 71
+		m.gpuHistory[i] = float64(i % 20 * 3)
+		if i >= 30 {
+			m.netInHistory[i] = float64(i%7+1) * 64 * 1024
+			m.netOutHistory[i] = float64(i%3+1) * 16 * 1024
+		}
+		m.diskReadHistory[i] = float64(i % 5 * 2 << 20)
+		m.diskWriteHistory[i] = float64(i % 4 << 20)
+	}
+	m.diskRates = []diskRate{{Name: "disk0", ReadBytes: 8 << 20, WriteBytes: 3 << 20, ReadOps: 410, WriteOps: 120}}
+	m.updateMetrics(metricsMsg{
+		cpu:    []float64{42, 28, 35, 31},
+		memory: 62.5,
+		disk:   71.2,
+		gpu:    23,
+		gpuOK:  true,
+		procs: []proctable.Process{
+			{PID: 412, CPU: 3.1, RSS: 220 << 20, Name: "WindowServer"},
+			{PID: 5120, CPU: 92.4, RSS: 1536 << 20, Name: "node"},
+			{PID: 871, CPU: 12.0, RSS: 2 << 30, Name: "Google Chrome Helper (Renderer)"},
+			{PID: 1, CPU: 0.1, RSS: 30 << 20, Name: "launchd"},
+		},
+	})
+	// An 8 GB Mac that has started swapping
+	m.vm = vmStats{SwapUsed: 1536 << 20, SwapTotal: 2 << 30, Pressure: 2}
+	m.vmOK = true
+	m.swapinRate, m.swapoutRate = 42, 120
+	// Wi-Fi carrying the default route, with a VPN tunnel inside it
+	m.setNetRates([]ifaceRate{
+		{Name: "en0", Recv: 448 * 1024, Sent: 40 * 1024},
+		{Name: "utun4", Recv: 64 * 1024, Sent: 8 * 1024},
+	})
+	m.defaultIface = "en0"
+	m.power = battery.Status{Percent: 87, Cycles: 142, DesignCapacity: 5103, MaxCapacity: 4891,
+		Voltage: 12.48, Amperage: -0.798, TimeRemaining: 412 * time.Minute, Temperature: 30.1}
+	m.powerHistory = make([]float64, 60)
+	for i := range m.powerHistory {
+		m.powerHistory[i] = 6 + float64(i%7)
+	}
+	return m
+}
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := snapshotModel()
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			golden.RequireEqual(t, m.View())
+		})
+	}
+}
+
+func TestReadGPU(t *testing.T) {
+	fake := runner.NewFake()
+	if err := fake.SetFixture("ioreg -r -d 1 -w 0 -c IOAccelerator", filepath.Join("testdata", "ioreg_accelerator.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if percent, ok := readGPU(fake); !ok || percent != 23 {
+		t.Errorf("readGPU = %v, %v; want 23, true", percent, ok)
+	}
+
+	// Virtual machines and some Intel GPUs don't report utilization
+	fake.Set("ioreg -r -d 1 -w 0 -c IOAccelerator", "", nil)
+	if _, ok := readGPU(fake); ok {
+		t.Error("no utilization should mean no GPU gauge")
+	}
+}
+
+func TestReadVM(t *testing.T) {
+	fake := runner.NewFake().
+		Set("sysctl -n vm.swapusage kern.memorystatus_vm_pressure_level", "total = 2048.00M  used = 1021.25M  free = 1026.75M  (encrypted)\n2\n", nil)
+	if err := fake.SetFixture("vm_stat", filepath.Join("testdata", "vm_stat.txt")); err != nil {
+		t.Fatal(err)
+	}
+	stats, ok := readVM(fake)
+	want := vmStats{SwapUsed: 1021.25 * 1024 * 1024, SwapTotal: 2 << 30, Swapins: 1843120, Swapouts: 2230417, Pressure: 2}
+	if !ok || stats != want {
+		t.Fatalf("readVM = %+v, %v; want %+v", stats, ok, want)
+	}
+
+	m := New(nil)
+	m.updateVM(stats, true, 1)
+	stats.Swapins += 30
+	stats.Swapouts += 90
+	m.updateVM(stats, true, 3)
+	if m.swapinRate != 10 || m.swapoutRate != 30 {
+		t.Errorf("swap rates = %v in, %v out; want 10, 30", m.swapinRate, m.swapoutRate)
+	}
+	if got := m.renderPressure(); !strings.Contains(got, "Warning pressure") || !strings.Contains(got, "swapping 10 in · 30 out/s") {
+		t.Errorf("renderPressure = %q", got)
+	}
+}
+
+func TestPerCoreView(t *testing.T) {
+	m := snapshotModel()
+	m.coreGroups = readCoreGroups(runner.NewFake().Set("sysctl -n hw.perflevel0.logicalcpu hw.perflevel1.logicalcpu", "2\n2\n", nil))
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	golden.RequireEqual(t, m.renderCores())
+
+	// Intel Macs have one kind of core
+	m.coreGroups = readCoreGroups(runner.NewFake())
+	if view := m.renderCores(); !strings.Contains(view, "Cores (4)") {
+		t.Errorf("ungrouped view:\n%s", view)
+	}
+}
+
+func TestThermalLine(t *testing.T) {
+	m := snapshotModel()
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	if strings.Contains(m.renderMetrics(), "Thermal") {
+		t.Error("no reading should hide the thermal line")
+	}
+
+	m.Update(thermalMsg{err: thermal.ErrNeedsSudo})
+	if !strings.Contains(m.renderMetrics(), "T read with powermetrics") || m.fetchThermal() != nil {
+		t.Error("without a sudo session the line should ask for T and stop polling")
+	}
+
+	m.Update(thermalMsg{reading: thermal.Reading{CPU: 71.9, FanRPM: 2159, Pressure: "Moderate"}})
+	if got := m.renderMetrics(); !strings.Contains(got, "CPU 72°C") || !strings.Contains(got, "Fan 2159 rpm") || !strings.Contains(got, "Moderate pressure") {
+		t.Errorf("thermal line:\n%s", got)
+	}
+}
+
+func TestTopProcesses(t *testing.T) {
+	fake := runner.NewFake().Set("ps -Aceo pid=,pcpu=,rss=,comm=", "    1   0.1  30720 launchd\n 5120  92.4 1572864 node\n  871  12.0 2097152 Google Chrome Helper\n", nil)
+	m := snapshotModel()
+	m.runner = fake
+	m.Update(events.NavDown)
+	if p := m.procs[m.procCursor]; p.Name != "Google Chrome Helper (Renderer)" {
+		t.Fatalf("selected %s, want the second heaviest by CPU", p.Name)
+	}
+	// A refresh keeps the same process selected even when it moves
+	m.updateProcesses(append(m.procs, proctable.Process{PID: 9, CPU: 50, Name: "swift-frontend"}))
+	if p := m.procs[m.procCursor]; p.PID != 871 {
+		t.Errorf("selection moved to %s", p.Name)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	req, ok := cmd().(dialog.Request)
+	if !ok || !req.Destructive || req.Title != "Force quit Google Chrome Helper (Renderer) (871)?" {
+		t.Fatalf("X should ask before a SIGKILL, got %+v", req)
+	}
+	fake.Set("kill -KILL 871", "", nil)
+	_, cmd = m.Update(req.OnConfirm)
+	if done := cmd().(signalledMsg); done.err != nil || fake.Calls()[len(fake.Calls())-1] != "kill -KILL 871" {
+		t.Errorf("signal = %+v, calls = %q", done, fake.Calls())
+	}
+}
+
+func TestDiskRates(t *testing.T) {
+	m := New(nil)
+	m.updateDiskIO(map[string]disk.IOCountersStat{
+		"disk0": {ReadBytes: 100 << 20, WriteBytes: 50 << 20, ReadCount: 1000, WriteCount: 400},
+	}, 0)
+	if len(m.diskRates) != 0 {
+		t.Fatalf("rates from a single sample: %+v", m.diskRates)
+	}
+
+	m.updateDiskIO(map[string]disk.IOCountersStat{
+		"disk0": {ReadBytes: 104 << 20, WriteBytes: 51 << 20, ReadCount: 1200, WriteCount: 440},
+		"disk4": {ReadBytes: 1 << 20, ReadCount: 10},
+	}, 2)
+	want := []diskRate{{Name: "disk0", ReadBytes: 2 << 20, WriteBytes: 512 << 10, ReadOps: 100, WriteOps: 20}}
+	if len(m.diskRates) != 1 || m.diskRates[0] != want[0] {
+		t.Errorf("rates = %+v, want %+v", m.diskRates, want)
+	}
+	if got := m.diskReadHistory[len(m.diskReadHistory)-1]; got != 2<<20 {
+		t.Errorf("read history ends with %v", got)
+	}
+
+	// A second disk gets a line of its own
+	m.updateDiskIO(map[string]disk.IOCountersStat{
+		"disk0": {ReadBytes: 105 << 20, WriteBytes: 51 << 20, ReadCount: 1300, WriteCount: 440},
+		"disk4": {ReadBytes: 2 << 20, ReadCount: 30},
+	}, 1)
+	if view := strings.Join(m.renderDiskIO(40), "\n"); !strings.Contains(view, "disk4   R 1.0 MB/s (20)") {
+		t.Errorf("no disk4 line in:\n%s", view)
+	}
+
+	// Counters going backwards mean the disk was remounted
+	m.updateDiskIO(map[string]disk.IOCountersStat{
+		"disk0": {ReadBytes: 1 << 20, ReadCount: 5},
+	}, 1)
+	if len(m.diskRates) != 0 {
+		t.Errorf("rates across a remount: %+v", m.diskRates)
+	}
+}
+
+func TestLongHistory(t *testing.T) {
+	if got := downsample([]float64{1, 3, 5, 7, 9, 11}, 3); !reflect.DeepEqual(got, []float64{2, 6, 10}) {
+		t.Errorf("downsample = %v, want [2 6 10]", got)
+	}
+
+	m := snapshotModel()
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	// Five minutes of CPU climbing from 10% to 40%, memory flat
+	for i := 0; i < 300; i++ {
+		m.cpuLongHistory = appendLong(m.cpuLongHistory, 10+float64(i)/10)
+		m.memoryLongHistory = appendLong(m.memoryLongHistory, 62)
+	}
+	m.longSamples = 300
+	if trend := m.longTrend(); trend != "" {
+		t.Errorf("trend shown with the one-minute graphs: %q", trend)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	view := m.View()
+	if !strings.Contains(view, " 10m") || strings.Contains(view, " 60s") {
+		t.Errorf("graphs not switched to ten minutes:\n%s", view)
+	}
+	if want := "• 5-minute trend: CPU rising (+6.0%/min), memory stable (+0.0%/min)"; m.longTrend() != want {
+		t.Errorf("longTrend = %q, want %q", m.longTrend(), want)
+	}
+}
+
+func TestMetricsHistory(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Storage.DataDir = t.TempDir()
+	cfg.Storage.MaxHistoryDays = 30
+
+	// Half an hour at 40% CPU, then half an hour at 80%
+	now := time.Now()
+	for i := 59; i >= 0; i-- {
+		cpu := 80.0
+		if i >= 30 {
+			cpu = 40
+		}
+		sample := storage.MetricSample{Time: now.Add(-time.Duration(i) * time.Minute), CPU: cpu, Memory: 60, Disk: 70}
+		if err := storage.AppendMetrics(cfg, sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A record cut short by a crash is skipped
+	day := filepath.Join(cfg.Storage.DataDir, storage.StoreMetrics, now.Format("2006-01-02")+".bin")
+	f, err := os.OpenFile(day, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{1, 2, 3})
+	f.Close()
+
+	m := snapshotModel()
+	m.config = cfg
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	if !m.HasOpenModal() {
+		t.Fatal("H didn't open the history")
+	}
+	m.Update(cmd())
+	if n := len(m.history.samples); n != 60 {
+		t.Fatalf("read %d samples, want 60", n)
+	}
+	view := m.View()
+	for _, want := range []string{"last 1 hour", "avg 60.0%  peak  80.0%", "avg 60.0%  peak  60.0%"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("history view lacks %q:\n%s", want, view)
+		}
+	}
+
+	graph := bucketSamples(m.history.samples, m.history.to, time.Hour, 4, func(s storage.MetricSample) float64 { return s.CPU })
+	if graph[0] != 40 || graph[3] != 80 {
+		t.Errorf("CPU by quarter hour = %v", graph)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.history != nil {
+		t.Error("esc didn't close the history")
+	}
+}
+
+func TestNetRates(t *testing.T) {
+	m := New(nil)
+	m.updateNetIO([]net.IOCountersStat{
+		{Name: "lo0", BytesRecv: 9 << 20, BytesSent: 9 << 20},
+		{Name: "en0", BytesRecv: 100 << 20, BytesSent: 10 << 20},
+		{Name: "utun4", BytesRecv: 5 << 20, BytesSent: 1 << 20},
+		{Name: "awdl0"},
+	}, 0)
+	m.updateNetIO([]net.IOCountersStat{
+		{Name: "lo0", BytesRecv: 99 << 20, BytesSent: 99 << 20},
+		{Name: "en0", BytesRecv: 102 << 20, BytesSent: 11 << 20},
+		{Name: "utun4", BytesRecv: 6 << 20, BytesSent: 1 << 20},
+		{Name: "awdl0"},
+	}, 2)
+	if m.netInRate != 1.5*(1<<20) || m.netOutRate != 512<<10 {
+		t.Errorf("totals = %v down, %v up; loopback should be left out", m.netInRate, m.netOutRate)
+	}
+
+	fake := runner.NewFake().Set("route -n get default", "   route to: default\ndestination: default\n  gateway: 192.168.1.1\n  interface: en0\n", nil)
+	m.runner = fake
+	m.Update(m.fetchRoute()())
+	view := strings.Join(m.renderInterfaces(), "\n")
+	if !strings.Contains(view, "en0       ▼ 1.0 MB/s   ▲ 512 KB/s   ◆ default route") || !strings.Contains(view, "utun4     ▼ 512 KB/s   ▲ 0 KB/s     VPN") {
+		t.Errorf("interfaces:\n%s", view)
+	}
+	if strings.Contains(view, "awdl0") {
+		t.Errorf("an interface that never carried traffic is listed:\n%s", view)
+	}
+
+	// A VPN tunnel recreated on reconnect starts its counters over
+	m.updateNetIO([]net.IOCountersStat{
+		{Name: "en0", BytesRecv: 103 << 20, BytesSent: 11 << 20},
+		{Name: "utun4", BytesRecv: 1 << 10},
+	}, 1)
+	if len(m.netRates) != 1 || m.netActive["utun4"] {
+		t.Errorf("rates across a reconnect: %+v", m.netRates)
+	}
+}
+
+func TestBatteryPanel(t *testing.T) {
+	m := New(nil)
+	fake := runner.NewFake().Set("ioreg -rw0 -n AppleSmartBattery", "", nil)
+	m.runner = fake
+	m.Update(m.fetchBattery()())
+	if m.fetchBattery() != nil || m.renderBattery(60) != "" {
+		t.Fatal("a Mac without a battery should get no panel and no more reads")
+	}
+
+	m = New(nil)
+	m.Update(batteryMsg{status: battery.Status{Percent: 64, Charging: true, External: true, Cycles: 811,
+		DesignCapacity: 6669, MaxCapacity: 3921, Voltage: 12.3, Amperage: 2.81, TimeRemaining: 95 * time.Minute}})
+	view := m.renderBattery(60)
+	for _, want := range []string{"64% ● Charging", "34.6 W · 1h 35m to full", "59% · Service Recommended", "3921 of 6669 mAh", "811"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel lacks %q:\n%s", want, view)
+		}
+	}
+	if got := m.powerHistory[len(m.powerHistory)-1]; math.Abs(got-34.563) > 0.001 {
+		t.Errorf("power history ends with %v", got)
+	}
+}
+
+func TestRefreshRate(t *testing.T) {
+	cfg := &config.Config{Modules: config.ModulesConfig{Dashboard: config.DashboardConfig{RefreshRate: 5}}}
+	m := New(cfg)
+	every := func() time.Duration {
+		for _, task := range m.Schedule() {
+			if task.Name == "metrics" {
+				return task.Every
+			}
+		}
+		return 0
+	}
+	if every() != 5*time.Second {
+		t.Fatalf("metrics every %v, want refresh_rate", every())
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	if every() != 2*time.Second || cmd == nil || cmd() != (scheduler.RescheduleMsg{}) {
+		t.Errorf("+ should reschedule the metrics sooner, every = %v", every())
+	}
+	for range refreshSteps {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")}); every() != 30*time.Second || cmd != nil {
+		t.Errorf("every = %v, want it to stop at 30s", every())
+	}
+
+	m.width = 120
+	if label := m.renderHistory(m.cpuHistory, nil, 0); !strings.HasSuffix(label, " 30m") {
+		t.Errorf("a minute of samples at 30s should be labelled 30m: %q", label)
+	}
+}
+
+func TestHealthSnapshot(t *testing.T) {
+	m := snapshotModel()
+	if got := New(nil).Export(); got != "" {
+		t.Errorf("Export() before the first sample = %q, want nothing", got)
+	}
+	m.lastUpdate = time.Date(2026, 3, 9, 14, 30, 0, 0, time.UTC)
+	golden.RequireEqual(t, m.Export())
+	if got := m.Copyable(); !strings.HasPrefix(got, "Machine health snapshot, 2026-03-09 14:30:00 UTC\n\n**devbox.local**") {
+		t.Errorf("Copyable() = %q", got)
+	}
+
+	m.config = &config.Config{}
+	m.config.Storage.DataDir = t.TempDir()
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	saved, ok := cmd().(snapshotSavedMsg)
+	if !ok || saved.err != nil {
+		t.Fatalf("s: %#v", saved)
+	}
+	if want := filepath.Join(m.config.Storage.DataDir, storage.StoreSnapshots, "dashboard-20260309-143000.json"); saved.path != want {
+		t.Errorf("saved to %s, want %s", saved.path, want)
+	}
+	data, err := os.ReadFile(saved.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Hostname != "devbox.local" || s.Memory != 62.5 || s.GPU == nil || *s.GPU != 23 || s.Battery == nil || s.Battery.Cycles != 142 {
+		t.Errorf("snapshot = %+v", s)
+	}
+	if len(s.TopProcesses) != 4 || s.TopProcesses[0].Name != "node" || s.Score == 0 || len(s.Insights) == 0 {
+		t.Errorf("top processes %+v, score %d, insights %q", s.TopProcesses, s.Score, s.Insights)
+	}
+}
+
+func TestWidgetLayout(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Modules.Dashboard.Widgets = []string{"battery", "CPU", "sparkles", "processes", "cpu", "insights"}
+	if got := readWidgets(cfg); !reflect.DeepEqual(got, []string{"battery", "cpu", "processes", "insights"}) {
+		t.Errorf("readWidgets() = %q, want known widgets in order without repeats", got)
+	}
+	if got := readWidgets(&config.Config{}); !reflect.DeepEqual(got, allWidgets) {
+		t.Errorf("without a list: %q, want every widget", got)
+	}
+
+	m := snapshotModel()
+	m.widgets = readWidgets(cfg)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	view := m.View()
+	for _, hidden := range []string{"Hostname:", "Memory:", "Network:", "GPU:"} {
+		if strings.Contains(view, hidden) {
+			t.Errorf("view shows %q, which isn't in the widgets list", hidden)
+		}
+	}
+	battery, procs := strings.Index(view, "🔋 Battery"), strings.Index(view, "Top Processes")
+	if !strings.Contains(view, "⚡ CPU:") || battery < 0 || procs < battery || !strings.Contains(view, "System Insights") {
+		t.Errorf("want CPU, then the battery above the processes, then insights:\n%s", view)
+	}
+}
+
+func TestCompactView(t *testing.T) {
+	m := snapshotModel()
+	m.compactMode = "auto"
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	if !m.compact() {
+		t.Fatal("a 24-row window should be compact")
+	}
+	golden.RequireEqual(t, m.View())
+
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if m.compact() {
+		t.Error("a 40-row window should get the full layout")
+	}
+	m.compactMode = "always"
+	if !m.compact() {
+		t.Error("compact: always should hold in any window")
+	}
+}
+
+func TestCIPanel(t *testing.T) {
+	m := snapshotModel()
+	const failing = "https://github.com/acme/api/actions/runs/9"
+	fake := runner.NewFake().Set("open "+failing, "", nil)
+	m.runner = fake
+	if view := m.renderCI(70); view != "" {
+		t.Errorf("the panel should stay hidden before a fetch:\n%s", view)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")}); cmd == nil {
+		t.Error("O without a failing run should say so")
+	}
+
+	now := time.Now()
+	m.Update(ciMsg{runs: []ci.Run{
+		{Repo: "acme/web", Workflow: "Deploy", Branch: "main", Number: 77, Status: "completed", Conclusion: "success", Updated: now.Add(-3 * time.Hour)},
+		{Repo: "acme/api", Workflow: "CI", Branch: "fix-auth", Number: 418, Status: "completed", Conclusion: "failure", URL: failing, Updated: now.Add(-5 * time.Minute)},
+		{Repo: "acme/cli", Workflow: "Test", Branch: "main", Status: "in_progress", Updated: now},
+	}})
+	view := m.renderCI(70)
+	for _, want := range []string{"O open failing run", "✓ acme/web", "passed · Deploy on main · 3h ago", "✗ acme/api", "failed · CI on fix-auth · 5m ago", "running"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel lacks %q:\n%s", want, view)
+		}
+	}
+	if line := m.renderCompactCI(lipgloss.NewStyle(), lipgloss.NewStyle()); !strings.Contains(line, "✓ 1  ● 1  ✗ acme/api") {
+		t.Errorf("compact line = %q", line)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if msg, ok := cmd().(ciOpenedMsg); !ok || msg.err != nil || msg.run.URL != failing {
+		t.Fatalf("O = %+v", msg)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0] != "open "+failing {
+		t.Errorf("ran %q", calls)
+	}
+}
+
+func TestCIPollingBudget(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/repos/acme/web/actions/runs" && len(requested) > 3 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			http.Error(w, "API rate limit exceeded", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"workflow_runs": []}`))
+	}))
+	defer srv.Close()
+	defer func(url string) { ci.APIURL = url }(ci.APIURL)
+	ci.APIURL = srv.URL
+
+	cfg := &config.Config{Modules: config.ModulesConfig{Dashboard: config.DashboardConfig{CIRepos: []string{"acme/api", "acme/web", "acme/cli"}}}}
+	m := New(cfg)
+	m.runner = runner.NewFake().Missing("gh")
+
+	start := time.Now()
+	m.Update(m.fetchCI()())
+	if len(requested) != 3 || len(m.ciRuns) != 3 {
+		t.Fatalf("requested %q", requested)
+	}
+	// Three repositories without a token: one fetch every three minutes
+	if wait := m.ciNext.Sub(start); wait < 3*time.Minute || wait > 3*time.Minute+time.Second {
+		t.Errorf("next fetch in %v", wait)
+	}
+	if cmd := m.fetchCI(); cmd != nil {
+		t.Error("fetched again before the next allowed time")
+	}
+
+	m.ciNext = time.Time{}
+	m.Update(m.fetchCI()())
+	if len(requested) != 5 {
+		t.Errorf("the repositories after the rate limit were still requested: %q", requested)
+	}
+	if !m.ciNext.Equal(reset) || m.ciErr == nil || !strings.Contains(m.ciErr.Error(), "rate limit") {
+		t.Errorf("next fetch at %v, error %v; want the reset %v", m.ciNext, m.ciErr, reset)
+	}
+	if cmd := m.fetchCI(); cmd != nil {
+		t.Error("fetched again before the rate limit reset")
+	}
+}
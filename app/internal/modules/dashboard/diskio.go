@@ -0,0 +1,107 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// diskRate is one disk's throughput since the previous sample
+type diskRate struct {
+	Name       string
+	ReadBytes  float64 // per second
+	WriteBytes float64
+	ReadOps    float64
+	WriteOps   float64
+}
+
+// diskRates turns two samples of the cumulative I/O counters into rates,
+// sorted by disk name. Disks missing from prev, or whose counters went
+// backwards because the disk was ejected and remounted, are left out.
+func diskRates(prev, cur map[string]disk.IOCountersStat, seconds float64) []diskRate {
+	if seconds <= 0 {
+		return nil
+	}
+	var rates []diskRate
+	for name, c := range cur {
+		p, ok := prev[name]
+		if !ok || c.ReadBytes < p.ReadBytes || c.WriteBytes < p.WriteBytes || c.ReadCount < p.ReadCount || c.WriteCount < p.WriteCount {
+			continue
+		}
+		rates = append(rates, diskRate{
+			Name:       name,
+			ReadBytes:  float64(c.ReadBytes-p.ReadBytes) / seconds,
+			WriteBytes: float64(c.WriteBytes-p.WriteBytes) / seconds,
+			ReadOps:    float64(c.ReadCount-p.ReadCount) / seconds,
+			WriteOps:   float64(c.WriteCount-p.WriteCount) / seconds,
+		})
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Name < rates[j].Name })
+	return rates
+}
+
+// updateDiskIO computes the disk rates from a new sample taken seconds
+// after the previous one
+func (m *Model) updateDiskIO(counters map[string]disk.IOCountersStat, seconds float64) {
+	if len(counters) == 0 {
+		return
+	}
+	if m.prevDiskIO != nil {
+		m.diskRates = diskRates(m.prevDiskIO, counters, seconds)
+	}
+	m.prevDiskIO = counters
+
+	var read, write float64
+	for _, r := range m.diskRates {
+		read += r.ReadBytes
+		write += r.WriteBytes
+	}
+	m.diskReadHistory = append(m.diskReadHistory[1:], read)
+	m.diskWriteHistory = append(m.diskWriteHistory[1:], write)
+}
+
+// renderDiskIO shows read and write throughput and IOPS, in total with a
+// graph and per disk when there's more than one; nothing until two
+// samples have been taken
+func (m *Model) renderDiskIO(graphWidth int) []string {
+	if len(m.diskRates) == 0 {
+		return nil
+	}
+	var total diskRate
+	for _, r := range m.diskRates {
+		total.ReadBytes += r.ReadBytes
+		total.WriteBytes += r.WriteBytes
+		total.ReadOps += r.ReadOps
+		total.WriteOps += r.WriteOps
+	}
+
+	subStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle).Width(30)
+	graphStyle := lipgloss.NewStyle().Foreground(components.ColorAccent)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	graphWidth = max(graphWidth-8, 0)
+
+	lines := []string{
+		"  " + subStyle.Render(fmt.Sprintf("⇣ Read: %s · %.0f IOPS", formatRate(total.ReadBytes), total.ReadOps)) +
+			graphStyle.Render(components.Sparkline(m.diskReadHistory, graphWidth, 0)),
+		"  " + subStyle.Render(fmt.Sprintf("⇡ Write: %s · %.0f IOPS", formatRate(total.WriteBytes), total.WriteOps)) +
+			graphStyle.Render(components.Sparkline(m.diskWriteHistory, graphWidth, 0)),
+	}
+	if len(m.diskRates) > 1 {
+		for _, r := range m.diskRates {
+			lines = append(lines, hintStyle.Render(fmt.Sprintf("    %-7s R %s (%.0f)  W %s (%.0f)",
+				r.Name, formatRate(r.ReadBytes), r.ReadOps, formatRate(r.WriteBytes), r.WriteOps)))
+		}
+	}
+	return lines
+}
+
+// formatRate is a byte rate in MB/s, or KB/s below a megabyte
+func formatRate(bytes float64) string {
+	if bytes >= 1024*1024 {
+		return fmt.Sprintf("%.1f MB/s", bytes/1024/1024)
+	}
+	return fmt.Sprintf("%.0f KB/s", bytes/1024)
+}
@@ -0,0 +1,32 @@
+package dashboard
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// gpuUtilization finds the busy share in an IOAccelerator's performance
+// statistics. ioreg reports it without root, unlike powermetrics.
+var gpuUtilization = regexp.MustCompile(`"Device Utilization %"\s*=\s*(\d+)`)
+
+// readGPU returns how busy the GPU is, in percent. With several GPUs, as
+// on Intel Macs with a discrete one, the busiest counts. ok is false when
+// no GPU reports its utilization.
+func readGPU(r runner.Runner) (percent float64, ok bool) {
+	output, err := r.Output(exec.Command("ioreg", "-r", "-d", "1", "-w", "0", "-c", "IOAccelerator"))
+	if err != nil {
+		return 0, false
+	}
+	for _, match := range gpuUtilization.FindAllSubmatch(output, -1) {
+		value, err := strconv.Atoi(string(match[1]))
+		if err != nil {
+			continue
+		}
+		percent = max(percent, float64(value))
+		ok = true
+	}
+	return min(percent, 100), ok
+}
@@ -0,0 +1,77 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// longHistoryLen is ten minutes of samples at one a second
+const longHistoryLen = 600
+
+// appendLong adds a sample to a ten-minute history, creating it on the
+// first one
+func appendLong(history []float64, value float64) []float64 {
+	if history == nil {
+		history = make([]float64, longHistoryLen)
+	}
+	return append(history[1:], value)
+}
+
+// downsample averages history into width buckets so ten minutes fit the
+// width of a one-minute graph; shorter histories are returned as they are
+func downsample(history []float64, width int) []float64 {
+	if width <= 0 || len(history) <= width {
+		return history
+	}
+	out := make([]float64, width)
+	for i := range out {
+		start, end := i*len(history)/width, (i+1)*len(history)/width
+		var sum float64
+		for _, v := range history[start:end] {
+			sum += v
+		}
+		out[i] = sum / float64(end-start)
+	}
+	return out
+}
+
+// longTrend is the insight the ten-minute graphs add: how CPU and memory
+// moved over what has been sampled of the last ten minutes. It's empty
+// with the one-minute graphs shown, or before two minutes are in.
+func (m *Model) longTrend() string {
+	sampled := time.Duration(m.longSamples) * m.refresh
+	if !m.longHistory || sampled < 2*time.Minute {
+		return ""
+	}
+	cpuSlope, _ := linearRegression(m.cpuLongHistory[longHistoryLen-m.longSamples:])
+	memSlope, _ := linearRegression(m.memoryLongHistory[longHistoryLen-m.longSamples:])
+	perMinute := float64(time.Minute / m.refresh)
+	return fmt.Sprintf("• %d-minute trend: CPU %s (%+.1f%%/min), memory %s (%+.1f%%/min)",
+		int(sampled.Minutes()), describeTrend(cpuSlope), cpuSlope*perMinute, describeTrend(memSlope), memSlope*perMinute)
+}
+
+// refreshSteps are the delays between samples + and - step through
+var refreshSteps = []time.Duration{time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second}
+
+// setRefresh samples the metrics every every from now on
+func (m *Model) setRefresh(every time.Duration) tea.Cmd {
+	if every == m.refresh {
+		return nil
+	}
+	m.refresh = every
+	return scheduler.Reschedule
+}
+
+// formatSpan is how long a graph covers, e.g. "60s", "10m" or "5h"
+func formatSpan(d time.Duration) string {
+	switch {
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
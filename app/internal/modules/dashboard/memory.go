@@ -0,0 +1,103 @@
+package dashboard
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/proctable"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// vmStats is how hard macOS works to find memory: swap in use, the
+// cumulative pages swapped in and out, and the kernel's pressure level.
+// On 8 GB Macs the used percentage sits near full either way, and these
+// tell a busy cache from actual swapping.
+type vmStats struct {
+	SwapUsed  uint64 // bytes
+	SwapTotal uint64
+	Swapins   uint64 // pages since boot
+	Swapouts  uint64
+	Pressure  int // kern.memorystatus_vm_pressure_level: 1 normal, 2 warning, 4 critical
+}
+
+var (
+	swapUsage      = regexp.MustCompile(`total = ([\d.]+)M\s+used = ([\d.]+)M`)
+	swapinsLine    = regexp.MustCompile(`Swapins:\s+(\d+)`)
+	swapoutsLine   = regexp.MustCompile(`Swapouts:\s+(\d+)`)
+	pressureLevels = map[int]string{1: "Normal", 2: "Warning", 4: "Critical"}
+)
+
+// readVM reads swap usage and pressure with sysctl, and the swap counters
+// with vm_stat. ok is false when sysctl fails.
+func readVM(r runner.Runner) (stats vmStats, ok bool) {
+	output, err := r.Output(exec.Command("sysctl", "-n", "vm.swapusage", "kern.memorystatus_vm_pressure_level"))
+	if err != nil {
+		return stats, false
+	}
+	// e.g. "total = 2048.00M  used = 1021.25M  free = 1026.75M  (encrypted)"
+	// followed by the pressure level on its own line
+	if match := swapUsage.FindStringSubmatch(string(output)); match != nil {
+		total, _ := strconv.ParseFloat(match[1], 64)
+		used, _ := strconv.ParseFloat(match[2], 64)
+		stats.SwapTotal = uint64(total * 1024 * 1024)
+		stats.SwapUsed = uint64(used * 1024 * 1024)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	stats.Pressure, _ = strconv.Atoi(strings.TrimSpace(lines[len(lines)-1]))
+
+	if output, err := r.Output(exec.Command("vm_stat")); err == nil {
+		if match := swapinsLine.FindSubmatch(output); match != nil {
+			stats.Swapins, _ = strconv.ParseUint(string(match[1]), 10, 64)
+		}
+		if match := swapoutsLine.FindSubmatch(output); match != nil {
+			stats.Swapouts, _ = strconv.ParseUint(string(match[1]), 10, 64)
+		}
+	}
+	return stats, true
+}
+
+// updateVM keeps the new sample and turns the swap counters into pages
+// per second over the seconds since the previous one
+func (m *Model) updateVM(stats vmStats, ok bool, seconds float64) {
+	if m.vmOK && ok && seconds > 0 && stats.Swapins >= m.vm.Swapins && stats.Swapouts >= m.vm.Swapouts {
+		m.swapinRate = float64(stats.Swapins-m.vm.Swapins) / seconds
+		m.swapoutRate = float64(stats.Swapouts-m.vm.Swapouts) / seconds
+	}
+	m.vm, m.vmOK = stats, ok
+}
+
+// renderPressure is the memory pressure shown beside the memory label,
+// with the swap rates while the Mac is swapping
+func (m *Model) renderPressure() string {
+	level, ok := pressureLevels[m.vm.Pressure]
+	if !m.vmOK || !ok {
+		return ""
+	}
+	color := components.ColorSuccess
+	switch m.vm.Pressure {
+	case 2:
+		color = components.ColorWarning
+	case 4:
+		color = components.ColorError
+	}
+	text := lipgloss.NewStyle().Foreground(color).Render("● " + level + " pressure")
+	if m.swapinRate >= 1 || m.swapoutRate >= 1 {
+		swapping := fmt.Sprintf("swapping %.0f in · %.0f out/s", m.swapinRate, m.swapoutRate)
+		text += "  " + lipgloss.NewStyle().Foreground(components.ColorWarning).Render(swapping)
+	}
+	return "  " + text
+}
+
+// renderSwap is the swap in use, shown after the memory status
+func (m *Model) renderSwap() string {
+	if !m.vmOK || m.vm.SwapTotal == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(components.ColorSubtle).
+		Render(fmt.Sprintf("  Swap %s of %s", proctable.FormatMemory(m.vm.SwapUsed), proctable.FormatMemory(m.vm.SwapTotal)))
+}
@@ -0,0 +1,172 @@
+package dashboard
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// netShown caps the per-interface lines under the network graphs
+const netShown = 4
+
+// ifaceRate is one interface's traffic since the previous sample
+type ifaceRate struct {
+	Name string
+	Recv float64 // bytes per second
+	Sent float64
+}
+
+type routeMsg struct{ iface string }
+
+// netRates turns two samples of the per-interface counters into rates.
+// Loopback is local traffic, not network, and is left out, as are
+// interfaces missing from prev or whose counters went backwards because
+// they were torn down and recreated, as VPN tunnels are.
+func netRates(prev map[string]net.IOCountersStat, cur []net.IOCountersStat, seconds float64) []ifaceRate {
+	if seconds <= 0 {
+		return nil
+	}
+	var rates []ifaceRate
+	for _, c := range cur {
+		p, ok := prev[c.Name]
+		if !ok || strings.HasPrefix(c.Name, "lo") || c.BytesRecv < p.BytesRecv || c.BytesSent < p.BytesSent {
+			continue
+		}
+		rates = append(rates, ifaceRate{
+			Name: c.Name,
+			Recv: float64(c.BytesRecv-p.BytesRecv) / seconds,
+			Sent: float64(c.BytesSent-p.BytesSent) / seconds,
+		})
+	}
+	return rates
+}
+
+// updateNetIO computes the interface rates and their totals from a new
+// sample taken seconds after the previous one. An interface is listed
+// from the first time it carries traffic until it goes away, so the
+// lines don't come and go with every quiet second.
+func (m *Model) updateNetIO(counters []net.IOCountersStat, seconds float64) {
+	if len(counters) > 0 {
+		if m.prevNetIO != nil {
+			m.setNetRates(netRates(m.prevNetIO, counters, seconds))
+		}
+		m.prevNetIO = make(map[string]net.IOCountersStat, len(counters))
+		for _, c := range counters {
+			m.prevNetIO[c.Name] = c
+		}
+	}
+	m.netInHistory = append(m.netInHistory[1:], m.netInRate)
+	m.netOutHistory = append(m.netOutHistory[1:], m.netOutRate)
+}
+
+// setNetRates takes new interface rates and their totals
+func (m *Model) setNetRates(rates []ifaceRate) {
+	m.netRates = rates
+	m.netInRate, m.netOutRate = 0, 0
+	if m.netActive == nil {
+		m.netActive = map[string]bool{}
+	}
+	present := make(map[string]bool, len(rates))
+	for _, r := range rates {
+		m.netInRate += r.Recv
+		m.netOutRate += r.Sent
+		present[r.Name] = true
+		if r.Recv > 0 || r.Sent > 0 {
+			m.netActive[r.Name] = true
+		}
+	}
+	for name := range m.netActive {
+		if !present[name] {
+			delete(m.netActive, name)
+		}
+	}
+}
+
+// shownInterfaces are the interfaces to list: the default route's first,
+// then the busiest of the others that have carried traffic
+func (m *Model) shownInterfaces() []ifaceRate {
+	var shown []ifaceRate
+	for _, r := range m.netRates {
+		if m.netActive[r.Name] || r.Name == m.defaultIface {
+			shown = append(shown, r)
+		}
+	}
+	sort.SliceStable(shown, func(i, j int) bool {
+		if (shown[i].Name == m.defaultIface) != (shown[j].Name == m.defaultIface) {
+			return shown[i].Name == m.defaultIface
+		}
+		if a, b := shown[i].Recv+shown[i].Sent, shown[j].Recv+shown[j].Sent; a != b {
+			return a > b
+		}
+		return shown[i].Name < shown[j].Name
+	})
+	return shown[:min(len(shown), netShown)]
+}
+
+// renderInterfaces lists the rates of each interface, marking the one
+// carrying the default route; nothing when there's only one to list
+func (m *Model) renderInterfaces() []string {
+	shown := m.shownInterfaces()
+	if len(shown) < 2 {
+		return nil
+	}
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	defaultStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary)
+
+	var lines []string
+	for _, r := range shown {
+		line := fmt.Sprintf("    %-9s ▼ %-10s ▲ %-10s ", r.Name, formatRate(r.Recv), formatRate(r.Sent))
+		if r.Name == m.defaultIface {
+			lines = append(lines, defaultStyle.Render(line+"◆ default route"))
+		} else {
+			lines = append(lines, hintStyle.Render(strings.TrimSpace(line+interfaceKind(r.Name))))
+		}
+	}
+	return lines
+}
+
+// interfaceKind names the virtual interfaces macOS creates by the prefix
+// of their name; physical ones (en0, en1) are left unnamed
+func interfaceKind(name string) string {
+	switch {
+	case strings.HasPrefix(name, "utun"), strings.HasPrefix(name, "ipsec"), strings.HasPrefix(name, "ppp"):
+		return "VPN"
+	case strings.HasPrefix(name, "bridge"), strings.HasPrefix(name, "vmenet"):
+		return "bridge"
+	case strings.HasPrefix(name, "awdl"), strings.HasPrefix(name, "llw"):
+		return "AirDrop"
+	case strings.HasPrefix(name, "ap"):
+		return "hotspot"
+	}
+	return ""
+}
+
+// fetchRoute reads which interface carries the default route
+func (m *Model) fetchRoute() tea.Cmd {
+	r := m.runner
+	return func() tea.Msg {
+		return routeMsg{iface: defaultInterface(r)}
+	}
+}
+
+// defaultInterface is the interface of the default route, or empty when
+// there is none (offline)
+func defaultInterface(r runner.Runner) string {
+	output, err := r.Output(exec.Command("route", "-n", "get", "default"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if iface, ok := strings.CutPrefix(strings.TrimSpace(line), "interface:"); ok {
+			return strings.TrimSpace(iface)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package dashboard
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/proctable"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// topProcesses is how many processes the panel lists
+const topProcesses = 8
+
+// heaviest returns the top processes by CPU, or by memory
+func heaviest(procs []proctable.Process, byMemory bool) []proctable.Process {
+	sorted := append([]proctable.Process(nil), procs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if byMemory {
+			return sorted[i].RSS > sorted[j].RSS
+		}
+		return sorted[i].CPU > sorted[j].CPU
+	})
+	return sorted[:min(len(sorted), topProcesses)]
+}
+
+// updateProcesses replaces the list, keeping the selected process
+// selected while it's still among the heaviest
+func (m *Model) updateProcesses(procs []proctable.Process) {
+	selected := -1
+	if m.procCursor < len(m.procs) {
+		selected = m.procs[m.procCursor].PID
+	}
+	m.procs = heaviest(procs, m.procsByMemory)
+	m.procCursor = min(m.procCursor, max(len(m.procs)-1, 0))
+	for i, p := range m.procs {
+		if p.PID == selected {
+			m.procCursor = i
+		}
+	}
+}
+
+// signalMsg asks to send a signal to a process once confirmed
+type signalMsg struct {
+	proc   proctable.Process
+	signal string // TERM or KILL
+}
+
+type signalledMsg struct {
+	proc   proctable.Process
+	signal string
+	err    error
+}
+
+// confirmSignal asks before sending signal to the selected process
+func (m *Model) confirmSignal(signal string) tea.Cmd {
+	if m.procCursor >= len(m.procs) {
+		return nil
+	}
+	p := m.procs[m.procCursor]
+	req := dialog.Request{
+		Title:        fmt.Sprintf("Quit %s (%d)?", p.Name, p.PID),
+		Detail:       "SIGTERM asks the process to quit, so it can save its work first.",
+		ConfirmLabel: "Quit",
+		OnConfirm:    signalMsg{proc: p, signal: signal},
+	}
+	if signal == "KILL" {
+		req.Title = fmt.Sprintf("Force quit %s (%d)?", p.Name, p.PID)
+		req.Detail = "SIGKILL stops the process at once. Unsaved work in it is lost."
+		req.ConfirmLabel = "Force Quit"
+		req.Destructive = true
+	}
+	return dialog.Confirm(req)
+}
+
+// sendSignal runs kill for a confirmed signalMsg
+func (m *Model) sendSignal(msg signalMsg) tea.Cmd {
+	r := m.runner
+	return func() tea.Msg {
+		cmd := exec.Command("kill", "-"+msg.signal, strconv.Itoa(msg.proc.PID))
+		out, err := r.CombinedOutput(cmd)
+		if err != nil {
+			if text := strings.TrimSpace(string(out)); text != "" {
+				err = fmt.Errorf("%s", text)
+			}
+		}
+		return signalledMsg{proc: msg.proc, signal: msg.signal, err: err}
+	}
+}
+
+// signalToast reports how a kill went
+func signalToast(msg signalledMsg) tea.Cmd {
+	if msg.err != nil {
+		return components.Toast(components.ToastError, fmt.Sprintf("Couldn't signal %s (%d): %v", msg.proc.Name, msg.proc.PID, msg.err))
+	}
+	return components.Toast(components.ToastSuccess, fmt.Sprintf("Sent SIG%s to %s (%d)", msg.signal, msg.proc.Name, msg.proc.PID))
+}
+
+// renderProcesses draws the Top Processes panel within width columns
+func (m *Model) renderProcesses(width int) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Padding(0, 1)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	columnStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+	selStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+
+	by := "CPU"
+	if m.procsByMemory {
+		by = "memory"
+	}
+	lines := []string{
+		headerStyle.Render("⚙  Top Processes") + hintStyle.Render("by "+by),
+		hintStyle.Render("  M sort · X quit · Shift+X force quit"),
+		"",
+	}
+	if len(m.procs) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, append(lines, hintStyle.Render("  Reading the process table..."))...)
+	}
+
+	// PID, CPU and memory take 29 columns with the cursor
+	nameWidth := min(max(width-30, 10), 40)
+	lines = append(lines, columnStyle.Render(fmt.Sprintf("  %7s %7s %9s  %s", "PID", "CPU", "MEMORY", "NAME")))
+	for i, p := range m.procs {
+		cpu := lipgloss.NewStyle().Foreground(levelColor(p.CPU)).Render(fmt.Sprintf("%6.1f%%", p.CPU))
+		row := fmt.Sprintf("%7d %s %9s  %s", p.PID, cpu, proctable.FormatMemory(p.RSS), components.TruncateString(p.Name, nameWidth))
+		if i == m.procCursor {
+			lines = append(lines, selStyle.Render("▶ ")+row)
+		} else {
+			lines = append(lines, "  "+row)
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -0,0 +1,193 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// recordEvery is how often a sample goes into the metrics store: about
+// 30 KB a day
+const recordEvery = time.Minute
+
+// historyRanges are the spans the History view steps through
+var historyRanges = []struct {
+	label string
+	span  time.Duration
+}{
+	{"1 hour", time.Hour},
+	{"6 hours", 6 * time.Hour},
+	{"24 hours", 24 * time.Hour},
+	{"7 days", 7 * 24 * time.Hour},
+	{"30 days", 30 * 24 * time.Hour},
+}
+
+// historyView is the History view of the recorded metrics
+type historyView struct {
+	rangeIndex int
+	to         time.Time
+	samples    []storage.MetricSample
+	loading    bool
+	err        error
+}
+
+type historyMsg struct {
+	to      time.Time
+	samples []storage.MetricSample
+	err     error
+}
+
+// recordTask samples the metrics into the store in the background, or is
+// nil without a config to find the store by
+func (m *Model) recordTask() *scheduler.Task {
+	if m.config == nil {
+		return nil
+	}
+	return &scheduler.Task{Name: "record", Every: recordEvery, Background: true, Run: m.recordMetrics}
+}
+
+// recordMetrics takes a sample and appends it to the metrics store. It
+// samples on its own, like the alerts, since the dashboard's refresh
+// stops while it's hidden; CPU usage is the average since the last sample.
+func (m *Model) recordMetrics() tea.Cmd {
+	cfg, r, cpu := m.config, m.runner, m.recordCPU
+	return func() tea.Msg {
+		sample := storage.MetricSample{Time: time.Now()}
+		cpu.Sample()
+		if percent, ok := cpu.Total(); ok {
+			sample.CPU = percent
+		}
+		if info, err := mem.VirtualMemory(); err == nil {
+			sample.Memory = info.UsedPercent
+		}
+		if info, err := disk.Usage("/"); err == nil {
+			sample.Disk = info.UsedPercent
+		}
+		sample.GPU, _ = readGPU(r)
+		if err := storage.AppendMetrics(cfg, sample); err != nil {
+			logger.Warn("Metrics history: %v", err)
+		}
+		return nil
+	}
+}
+
+// openHistory shows the recorded metrics, starting with the last hour
+func (m *Model) openHistory() tea.Cmd {
+	m.history = &historyView{}
+	return m.loadHistory()
+}
+
+func (m *Model) loadHistory() tea.Cmd {
+	h, cfg := m.history, m.config
+	h.loading = true
+	to := time.Now()
+	from := to.Add(-historyRanges[h.rangeIndex].span)
+	return func() tea.Msg {
+		samples, err := storage.ReadMetrics(cfg, from, to)
+		return historyMsg{to: to, samples: samples, err: err}
+	}
+}
+
+// handleHistoryKey handles keys while the History view is open
+func (m *Model) handleHistoryKey(msg tea.KeyMsg) tea.Cmd {
+	h := m.history
+	switch msg.String() {
+	case "esc", "h":
+		m.history = nil
+	case "left":
+		if h.rangeIndex > 0 {
+			h.rangeIndex--
+			return m.loadHistory()
+		}
+	case "right":
+		if h.rangeIndex < len(historyRanges)-1 {
+			h.rangeIndex++
+			return m.loadHistory()
+		}
+	case "r":
+		return m.loadHistory()
+	}
+	return nil
+}
+
+// bucketSamples averages one metric of the samples into width columns
+// spanning the span up to to. Columns without samples, while the Mac
+// slept or Dev Cockpit wasn't running, stay empty.
+func bucketSamples(samples []storage.MetricSample, to time.Time, span time.Duration, width int, metric func(storage.MetricSample) float64) []float64 {
+	sums := make([]float64, width)
+	counts := make([]int, width)
+	from := to.Add(-span)
+	for _, s := range samples {
+		i := int(float64(s.Time.Sub(from)) / float64(span) * float64(width))
+		if i < 0 || i >= width {
+			continue
+		}
+		sums[i] += metric(s)
+		counts[i]++
+	}
+	for i := range sums {
+		if counts[i] > 0 {
+			sums[i] /= float64(counts[i])
+		}
+	}
+	return sums
+}
+
+// renderHistoryView graphs each recorded metric over the selected range
+// with its average and peak
+func (m *Model) renderHistoryView() string {
+	h := m.history
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	labelStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true).Width(10)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	r := historyRanges[h.rangeIndex]
+	lines := []string{titleStyle.Render("📈 Metrics History") + hintStyle.Render("  last "+r.label), ""}
+	switch {
+	case h.loading && h.samples == nil:
+		lines = append(lines, hintStyle.Render("Reading the metrics history..."))
+	case h.err != nil:
+		lines = append(lines, lipgloss.NewStyle().Foreground(components.ColorError).Render("✗ "+h.err.Error()))
+	case len(h.samples) == 0:
+		lines = append(lines, hintStyle.Render(fmt.Sprintf("Nothing recorded in the last %s. A sample is stored every minute while Dev Cockpit runs.", r.label)))
+	default:
+		// No more columns than samples, or the graph turns into a comb
+		width := max(min(m.width-34, 90, int(r.span/recordEvery)), 10)
+		metrics := []struct {
+			name  string
+			value func(storage.MetricSample) float64
+		}{
+			{"CPU", func(s storage.MetricSample) float64 { return s.CPU }},
+			{"Memory", func(s storage.MetricSample) float64 { return s.Memory }},
+			{"Disk", func(s storage.MetricSample) float64 { return s.Disk }},
+			{"GPU", func(s storage.MetricSample) float64 { return s.GPU }},
+		}
+		for _, metric := range metrics {
+			var sum, peak float64
+			for _, s := range h.samples {
+				sum += metric.value(s)
+				peak = max(peak, metric.value(s))
+			}
+			avg := sum / float64(len(h.samples))
+			graph := bucketSamples(h.samples, h.to, r.span, width, metric.value)
+			lines = append(lines,
+				labelStyle.Render(metric.name)+
+					lipgloss.NewStyle().Foreground(levelColor(avg)).Render(components.Sparkline(graph, width, 100))+
+					hintStyle.Render(fmt.Sprintf("  avg %4.1f%%  peak %5.1f%%", avg, peak)),
+				"",
+			)
+		}
+		first := h.samples[0].Time
+		lines = append(lines, hintStyle.Render(fmt.Sprintf("%d samples since %s", len(h.samples), first.Format("Jan 2 15:04"))))
+	}
+	lines = append(lines, "", hintStyle.Render("←/→ Range (1 hour to 30 days) • R Reload • H/Esc Back"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
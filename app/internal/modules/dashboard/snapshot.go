@@ -0,0 +1,255 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/export"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/proctable"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Snapshot is the machine's health at one moment: the metrics, the
+// insights and performance score, and the heaviest processes. s saves it
+// as JSON to compare against later; e and y give it as Markdown.
+type Snapshot struct {
+	Time          time.Time `json:"time"`
+	Hostname      string    `json:"hostname"`
+	Platform      string    `json:"platform"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+
+	CPU            float64   `json:"cpu_percent"`
+	Cores          []float64 `json:"cpu_cores_percent"`
+	Memory         float64   `json:"memory_percent"`
+	MemoryTotal    uint64    `json:"memory_total_bytes"`
+	MemoryPressure string    `json:"memory_pressure,omitempty"`
+	SwapUsed       uint64    `json:"swap_used_bytes"`
+	Disk           float64   `json:"disk_percent"`
+	DiskRead       float64   `json:"disk_read_bytes_per_sec"`
+	DiskWrite      float64   `json:"disk_write_bytes_per_sec"`
+	GPU            *float64  `json:"gpu_percent,omitempty"`
+	NetworkIn      float64   `json:"network_in_bytes_per_sec"`
+	NetworkOut     float64   `json:"network_out_bytes_per_sec"`
+
+	Battery *snapshotBattery `json:"battery,omitempty"`
+	Thermal *snapshotThermal `json:"thermal,omitempty"`
+
+	Score        int               `json:"performance_score"`
+	Insights     []string          `json:"insights"`
+	ProcessesBy  string            `json:"processes_by"`
+	TopProcesses []snapshotProcess `json:"top_processes"`
+}
+
+type snapshotBattery struct {
+	Percent  int     `json:"percent"`
+	Charging bool    `json:"charging"`
+	External bool    `json:"external_power"`
+	Health   float64 `json:"health_percent"`
+	Cycles   int     `json:"cycles"`
+	Watts    float64 `json:"watts"`
+}
+
+type snapshotThermal struct {
+	CPU      float64 `json:"cpu_celsius,omitempty"`
+	GPU      float64 `json:"gpu_celsius,omitempty"`
+	FanRPM   int     `json:"fan_rpm,omitempty"`
+	Pressure string  `json:"pressure,omitempty"`
+}
+
+type snapshotProcess struct {
+	PID    int     `json:"pid"`
+	Name   string  `json:"name"`
+	CPU    float64 `json:"cpu_percent"`
+	Memory uint64  `json:"memory_bytes"`
+}
+
+type snapshotSavedMsg struct {
+	path string
+	err  error
+}
+
+// snapshot captures the dashboard as of its last sample
+func (m *Model) snapshot() Snapshot {
+	insights, score := m.generateAdvancedInsights()
+	s := Snapshot{
+		Time:          m.lastUpdate,
+		Hostname:      m.hostname,
+		Platform:      m.platform,
+		UptimeSeconds: int64(m.uptime.Seconds()),
+		CPU:           m.cpuHistory[len(m.cpuHistory)-1],
+		Cores:         append([]float64(nil), m.cpuPercent...),
+		Memory:        m.memoryPercent,
+		MemoryTotal:   m.totalMem,
+		Disk:          m.diskUsage,
+		NetworkIn:     m.netInRate,
+		NetworkOut:    m.netOutRate,
+		Score:         score,
+		ProcessesBy:   "cpu",
+	}
+	if m.vmOK {
+		s.MemoryPressure = pressureLevels[m.vm.Pressure]
+		s.SwapUsed = m.vm.SwapUsed
+	}
+	for _, rate := range m.diskRates {
+		s.DiskRead += rate.ReadBytes
+		s.DiskWrite += rate.WriteBytes
+	}
+	if m.gpuAvailable {
+		gpu := m.gpuPercent
+		s.GPU = &gpu
+	}
+	if m.powerErr == nil && m.power.Percent > 0 {
+		s.Battery = &snapshotBattery{
+			Percent:  m.power.Percent,
+			Charging: m.power.Charging,
+			External: m.power.External,
+			Health:   m.power.Health(),
+			Cycles:   m.power.Cycles,
+			Watts:    m.power.Watts(),
+		}
+	}
+	if m.thermalErr == nil && !m.thermal.Empty() {
+		s.Thermal = &snapshotThermal{CPU: m.thermal.CPU, GPU: m.thermal.GPU, FanRPM: m.thermal.FanRPM, Pressure: m.thermal.Pressure}
+	}
+	for _, insight := range insights {
+		s.Insights = append(s.Insights, strings.TrimPrefix(insight, "• "))
+	}
+	if m.procsByMemory {
+		s.ProcessesBy = "memory"
+	}
+	for _, p := range m.procs {
+		s.TopProcesses = append(s.TopProcesses, snapshotProcess{PID: p.PID, Name: p.Name, CPU: p.CPU, Memory: p.RSS})
+	}
+	return s
+}
+
+// Markdown renders the snapshot for pasting into a chat or ticket
+func (s Snapshot) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** (%s) · up %s · performance score %d/100\n\n",
+		s.Hostname, s.Platform, formatShortDuration(time.Duration(s.UptimeSeconds)*time.Second), s.Score)
+
+	memory := fmt.Sprintf("%.1f%% of %s", s.Memory, proctable.FormatMemory(s.MemoryTotal))
+	if s.MemoryPressure != "" {
+		memory += fmt.Sprintf(", %s pressure, %s swap", strings.ToLower(s.MemoryPressure), proctable.FormatMemory(s.SwapUsed))
+	}
+	rows := [][]string{
+		{"CPU", fmt.Sprintf("%.1f%% across %d cores", s.CPU, len(s.Cores))},
+		{"Memory", memory},
+		{"Disk", fmt.Sprintf("%.1f%% used, reading %s, writing %s", s.Disk, formatRate(s.DiskRead), formatRate(s.DiskWrite))},
+	}
+	if s.GPU != nil {
+		rows = append(rows, []string{"GPU", fmt.Sprintf("%.0f%%", *s.GPU)})
+	}
+	rows = append(rows, []string{"Network", fmt.Sprintf("%s in, %s out", formatRate(s.NetworkIn), formatRate(s.NetworkOut))})
+	if s.Battery != nil {
+		state := "discharging"
+		switch {
+		case s.Battery.Charging:
+			state = "charging"
+		case s.Battery.External:
+			state = "on power adapter"
+		}
+		rows = append(rows, []string{"Battery", fmt.Sprintf("%d%%, %s, %.0f%% health, %d cycles", s.Battery.Percent, state, s.Battery.Health, s.Battery.Cycles)})
+	}
+	if t := s.Thermal; t != nil {
+		var parts []string
+		if t.CPU > 0 {
+			parts = append(parts, fmt.Sprintf("CPU %.0f°C", t.CPU))
+		}
+		if t.GPU > 0 {
+			parts = append(parts, fmt.Sprintf("GPU %.0f°C", t.GPU))
+		}
+		if t.FanRPM > 0 {
+			parts = append(parts, fmt.Sprintf("fan %d rpm", t.FanRPM))
+		}
+		if t.Pressure != "" {
+			parts = append(parts, t.Pressure+" pressure")
+		}
+		rows = append(rows, []string{"Thermal", strings.Join(parts, ", ")})
+	}
+	b.WriteString(export.Table([]string{"Metric", "Value"}, rows))
+
+	b.WriteString("\n### Insights\n\n")
+	for _, insight := range s.Insights {
+		b.WriteString("- " + insight + "\n")
+	}
+
+	if len(s.TopProcesses) > 0 {
+		by := "CPU"
+		if s.ProcessesBy == "memory" {
+			by = "memory"
+		}
+		fmt.Fprintf(&b, "\n### Top processes by %s\n\n", by)
+		var procs [][]string
+		for _, p := range s.TopProcesses {
+			procs = append(procs, []string{fmt.Sprint(p.PID), p.Name, fmt.Sprintf("%.1f%%", p.CPU), proctable.FormatMemory(p.Memory)})
+		}
+		b.WriteString(export.Table([]string{"PID", "Process", "CPU", "Memory"}, procs))
+	}
+	return b.String()
+}
+
+// Export is the health snapshot as Markdown, or "" before the first sample
+func (m *Model) Export() string {
+	if m.lastUpdate.IsZero() {
+		return ""
+	}
+	return m.snapshot().Markdown()
+}
+
+// Copyable is the health snapshot as Markdown with the time it was taken,
+// for pasting into an incident channel
+func (m *Model) Copyable() string {
+	if m.history != nil || m.lastUpdate.IsZero() {
+		return ""
+	}
+	s := m.snapshot()
+	return fmt.Sprintf("Machine health snapshot, %s\n\n%s", s.Time.Format("2006-01-02 15:04:05 MST"), s.Markdown())
+}
+
+// saveSnapshot writes the snapshot as JSON to the snapshots store, where
+// retention keeps it alongside earlier ones to compare against
+func (m *Model) saveSnapshot() tea.Cmd {
+	if m.lastUpdate.IsZero() {
+		return components.Toast(components.ToastInfo, "Nothing sampled to snapshot yet")
+	}
+	cfg, s := m.config, m.snapshot()
+	return func() tea.Msg {
+		path, err := writeSnapshot(cfg, s)
+		return snapshotSavedMsg{path: path, err: err}
+	}
+}
+
+func writeSnapshot(cfg *config.Config, s Snapshot) (string, error) {
+	dir, err := storage.StoreDir(cfg, storage.StoreSnapshots)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "dashboard-"+s.Time.Format("20060102-150405")+".json")
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func snapshotToast(msg snapshotSavedMsg) tea.Cmd {
+	if msg.err != nil {
+		logger.Error("Saving the dashboard snapshot failed: %v", msg.err)
+		return components.Toast(components.ToastError, "Snapshot failed: "+msg.err.Error())
+	}
+	logger.Info("Saved dashboard snapshot to %s", msg.path)
+	return components.Toast(components.ToastSuccess, "Snapshot saved to "+msg.path)
+}
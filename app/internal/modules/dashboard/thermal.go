@@ -0,0 +1,92 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/thermal"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type thermalMsg struct {
+	reading thermal.Reading
+	err     error
+}
+
+// fetchThermal samples the temperatures, or skips while powermetrics
+// waits for the user to authorize it
+func (m *Model) fetchThermal() tea.Cmd {
+	if m.thermalAuthorizing || errors.Is(m.thermalErr, thermal.ErrNeedsSudo) {
+		return nil
+	}
+	r := m.runner
+	return func() tea.Msg {
+		reading, err := thermal.Read(r)
+		return thermalMsg{reading: reading, err: err}
+	}
+}
+
+// authorizeThermal asks for the administrator password powermetrics needs
+func (m *Model) authorizeThermal() tea.Cmd {
+	if m.thermalAuthorizing || !errors.Is(m.thermalErr, thermal.ErrNeedsSudo) {
+		return nil
+	}
+	m.thermalAuthorizing = true
+	return func() tea.Msg {
+		reading, err := thermal.Authorize()
+		return thermalMsg{reading: reading, err: err}
+	}
+}
+
+func (m *Model) updateThermal(msg thermalMsg) {
+	m.thermalAuthorizing = false
+	m.thermal, m.thermalErr = msg.reading, msg.err
+}
+
+// renderThermal shows the die temperatures, fan speed and thermal
+// pressure, or how to read them; nothing when the Mac doesn't report them
+func (m *Model) renderThermal(label, hint lipgloss.Style) []string {
+	switch {
+	case m.thermalAuthorizing:
+		return []string{label.Render("🌡  Thermal: ") + hint.Render("waiting for the administrator password..."), ""}
+	case errors.Is(m.thermalErr, thermal.ErrNeedsSudo):
+		return []string{label.Render("🌡  Thermal: ") + hint.Render("T read with powermetrics (needs your password)"), ""}
+	case m.thermalErr != nil || m.thermal.Empty():
+		return nil
+	}
+
+	var parts []string
+	temp := func(name string, celsius float64) {
+		if celsius > 0 {
+			// The 70/85 bands used for load suit die temperatures too
+			style := lipgloss.NewStyle().Foreground(levelColor(celsius))
+			parts = append(parts, name+" "+style.Render(fmt.Sprintf("%.0f°C", celsius)))
+		}
+	}
+	temp("CPU", m.thermal.CPU)
+	temp("GPU", m.thermal.GPU)
+	if m.thermal.FanRPM > 0 {
+		parts = append(parts, fmt.Sprintf("Fan %d rpm", m.thermal.FanRPM))
+	}
+	if m.thermal.Pressure != "" {
+		style := lipgloss.NewStyle().Foreground(pressureColor(m.thermal.Pressure))
+		parts = append(parts, style.Render("● "+m.thermal.Pressure+" pressure"))
+	}
+	return []string{label.Render("🌡  Thermal: ") + strings.Join(parts, "  ·  "), ""}
+}
+
+// pressureColor grades macOS thermal pressure levels, from Nominal up
+// through Moderate, Heavy and Trapping
+func pressureColor(level string) lipgloss.AdaptiveColor {
+	switch level {
+	case "Nominal":
+		return components.ColorSuccess
+	case "Moderate", "Fair":
+		return components.ColorWarning
+	default:
+		return components.ColorError
+	}
+}
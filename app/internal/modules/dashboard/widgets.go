@@ -0,0 +1,182 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/proctable"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Widgets, by the names modules.dashboard.widgets lists them under
+const (
+	widgetSystem    = "system"
+	widgetCPU       = "cpu"
+	widgetGPU       = "gpu"
+	widgetThermal   = "thermal"
+	widgetMemory    = "memory"
+	widgetDisk      = "disk"
+	widgetNetwork   = "network"
+	widgetProcesses = "processes"
+	widgetBattery   = "battery"
+	widgetCI        = "ci"
+	widgetInsights  = "insights"
+)
+
+// allWidgets is every widget in the order shown without a widgets list
+var allWidgets = []string{
+	widgetSystem, widgetCPU, widgetGPU, widgetThermal, widgetMemory, widgetDisk,
+	widgetNetwork, widgetProcesses, widgetBattery, widgetCI, widgetInsights,
+}
+
+// metricWidgets make up the metrics column; processes, battery and CI
+// sit beside it on wide terminals and below it otherwise
+var metricWidgets = []string{widgetCPU, widgetGPU, widgetThermal, widgetMemory, widgetDisk, widgetNetwork}
+
+// compactRows is the window height below which compact: auto gives each
+// widget a single line
+const compactRows = 30
+
+// readWidgets returns the configured widgets in order, leaving out
+// unknown names and repeats, or every widget when none are configured
+func readWidgets(cfg *config.Config) []string {
+	if cfg == nil || len(cfg.Modules.Dashboard.Widgets) == 0 {
+		return allWidgets
+	}
+	var widgets []string
+	for _, name := range cfg.Modules.Dashboard.Widgets {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !slices.Contains(allWidgets, name):
+			logger.Warn("Dashboard: ignoring unknown widget %q in modules.dashboard.widgets", name)
+		case !slices.Contains(widgets, name):
+			widgets = append(widgets, name)
+		}
+	}
+	if len(widgets) == 0 {
+		return allWidgets
+	}
+	return widgets
+}
+
+// shows reports whether the widget is in the layout
+func (m *Model) shows(widget string) bool {
+	return slices.Contains(m.widgets, widget)
+}
+
+// showsMetrics reports whether any widget of the metrics column is shown
+func (m *Model) showsMetrics() bool {
+	return slices.ContainsFunc(m.widgets, func(widget string) bool {
+		return slices.Contains(metricWidgets, widget)
+	})
+}
+
+// compact reports whether each widget gets a single line
+func (m *Model) compact() bool {
+	switch m.compactMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return m.height < compactRows
+}
+
+// renderCompact is the dashboard with a line per widget, for small
+// windows and split view
+func (m *Model) renderCompact() string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	labelStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true).Width(9)
+	hintStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	graphWidth := min(max(m.width-40, 0), 20)
+
+	meter := func(label string, percent float64, history []float64, extra string) string {
+		return labelStyle.Render(label) + fmt.Sprintf("%-7s", fmt.Sprintf("%.1f%%", percent)) + compactBar(percent, 10) + " " +
+			lipgloss.NewStyle().Foreground(levelColor(percent)).Render(components.Sparkline(history, graphWidth, 100)) + extra
+	}
+
+	header := headerStyle.Render("📊 DASHBOARD ") + hintStyle.Render(fmt.Sprintf("⟳ %v · +/-", m.refresh))
+	if m.shows(widgetSystem) {
+		header += hintStyle.Render(fmt.Sprintf(" · %s · up %s", m.hostname, m.formatUptime()))
+	}
+	lines := []string{header}
+	for _, widget := range m.widgets {
+		switch widget {
+		case widgetCPU:
+			lines = append(lines, meter("CPU", m.cpuHistory[len(m.cpuHistory)-1], m.cpuHistory, ""))
+		case widgetGPU:
+			if m.gpuAvailable {
+				lines = append(lines, meter("GPU", m.gpuPercent, m.gpuHistory, ""))
+			}
+		case widgetThermal:
+			if thermal := m.renderThermal(lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true), hintStyle); len(thermal) > 0 {
+				lines = append(lines, thermal[0])
+			}
+		case widgetMemory:
+			lines = append(lines, meter("Memory", m.memoryPercent, m.memoryHistory, m.renderPressure()))
+		case widgetDisk:
+			lines = append(lines, meter("Disk", m.diskUsage, m.diskHistory, ""))
+		case widgetNetwork:
+			lines = append(lines, labelStyle.Render("Network")+
+				fmt.Sprintf("▼ %s  ▲ %s", formatRate(m.netInRate), formatRate(m.netOutRate)))
+		case widgetProcesses:
+			lines = append(lines, m.renderCompactProcesses(labelStyle, hintStyle))
+		case widgetBattery:
+			if m.powerErr == nil && m.power.Percent > 0 {
+				line := labelStyle.Render("Battery") + fmt.Sprintf("%d%% ", m.power.Percent) +
+					hintStyle.Render(fmt.Sprintf("%.1f W", math.Abs(m.power.Watts())))
+				if m.power.TimeRemaining > 0 {
+					line += hintStyle.Render(" · " + formatMinutes(m.power.TimeRemaining))
+				}
+				lines = append(lines, line)
+			}
+		case widgetCI:
+			if line := m.renderCompactCI(labelStyle, hintStyle); line != "" {
+				lines = append(lines, line)
+			}
+		case widgetInsights:
+			insights, score := m.generateAdvancedInsights()
+			line := labelStyle.Render("Score") + lipgloss.NewStyle().Foreground(scoreColor(score)).Render(fmt.Sprintf("%d/100", score))
+			for _, insight := range insights {
+				if risk, ok := strings.CutPrefix(insight, "• Operational risk: "); ok {
+					line += hintStyle.Render(" · " + risk)
+				}
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderCompactProcesses names the three heaviest processes, marking the
+// one X would quit
+func (m *Model) renderCompactProcesses(label, hint lipgloss.Style) string {
+	if len(m.procs) == 0 {
+		return label.Render("Top") + hint.Render("reading the process table...")
+	}
+	selStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	var names []string
+	for i, p := range m.procs[:min(len(m.procs), 3)] {
+		name := fmt.Sprintf("%s %.0f%%", components.TruncateString(p.Name, 18), p.CPU)
+		if m.procsByMemory {
+			name = fmt.Sprintf("%s %s", components.TruncateString(p.Name, 18), proctable.FormatMemory(p.RSS))
+		}
+		if i == m.procCursor {
+			name = selStyle.Render("▶ " + name)
+		}
+		names = append(names, name)
+	}
+	return label.Render("Top") + strings.Join(names, hint.Render(" · "))
+}
+
+// compactBar is a usage bar width cells wide, without brackets
+func compactBar(percent float64, width int) string {
+	filled := min(max(int(math.Round(percent/100*float64(width))), 0), width)
+	return lipgloss.NewStyle().Foreground(levelColor(percent)).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(components.ColorBorder).Render(strings.Repeat("░", width-filled))
+}
@@ -0,0 +1,391 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Dockerfiles are looked for this many folders below a workspace root
+const contextDepth = 3
+
+// buildKept is how many lines of build output are kept for the view and y
+const buildKept = 2000
+
+// skippedDirs are never searched for Dockerfiles
+var skippedDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+type buildStage int
+
+const (
+	pickContext buildStage = iota
+	nameImage
+	building
+	built
+)
+
+// build is the image build flow: pick a folder with a Dockerfile from
+// the workspace roots, tag it, and watch docker build
+type build struct {
+	stage    buildStage
+	scanning bool
+	contexts []string
+	cursor   int
+
+	dir     string
+	tag     components.TextInput
+	message string
+
+	lines  []string
+	wait   tea.Cmd // waits for the next line, then the result
+	cancel context.CancelFunc
+	report string
+	failed bool
+}
+
+type contextsMsg struct{ dirs []string }
+
+// buildLineMsg is a line of docker build output
+type buildLineMsg struct {
+	build *build
+	line  string
+}
+
+// buildDoneMsg ends a build with the built image's size
+type buildDoneMsg struct {
+	build *build
+	size  int64
+	err   error
+}
+
+// openBuild starts the flow by looking for build contexts
+func (m *Model) openBuild() tea.Cmd {
+	m.build = &build{}
+	return m.findContexts()
+}
+
+func (m *Model) findContexts() tea.Cmd {
+	m.build.scanning = true
+	roots := m.config.WorkspaceRoots()
+	return func() tea.Msg {
+		return contextsMsg{dirs: findContexts(roots)}
+	}
+}
+
+// findContexts returns the folders holding a Dockerfile within
+// contextDepth of the roots, skipping hidden and dependency folders
+func findContexts(roots []string) []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, root := range roots {
+		root = filepath.Clean(expandHome(root))
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				depth := strings.Count(strings.TrimPrefix(path, root), string(filepath.Separator))
+				if path != root && (strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()] || depth > contextDepth) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if dir := filepath.Dir(path); d.Name() == "Dockerfile" && !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+			return nil
+		})
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// updateBuild handles the build flow's messages and keys
+func (m *Model) updateBuild(msg tea.Msg) tea.Cmd {
+	b := m.build
+	switch msg := msg.(type) {
+	case contextsMsg:
+		b.scanning, b.contexts = false, msg.dirs
+		b.cursor = min(b.cursor, max(len(b.contexts)-1, 0))
+
+	case buildLineMsg:
+		if msg.build != b {
+			return nil
+		}
+		b.lines = append(b.lines, msg.line)
+		if len(b.lines) > buildKept {
+			b.lines = b.lines[len(b.lines)-buildKept:]
+		}
+		return b.wait
+
+	case buildDoneMsg:
+		if msg.build != b {
+			return nil
+		}
+		return m.finishBuild(msg)
+
+	case events.Nav:
+		switch b.stage {
+		case pickContext:
+			b.cursor = msg.Move(b.cursor, len(b.contexts))
+		case nameImage:
+			b.tag.Nav(msg)
+		}
+
+	case tea.KeyMsg:
+		switch b.stage {
+		case pickContext:
+			switch msg.String() {
+			case "esc":
+				m.build = nil
+			case "r":
+				if !b.scanning {
+					return m.findContexts()
+				}
+			case "enter":
+				if b.cursor < len(b.contexts) {
+					b.dir = b.contexts[b.cursor]
+					b.tag.SetValue(defaultTag(b.dir))
+					b.message = ""
+					b.stage = nameImage
+				}
+			}
+		case nameImage:
+			switch msg.String() {
+			case "esc":
+				b.stage = pickContext
+			case "enter":
+				tag := strings.TrimSpace(b.tag.Value())
+				if !tagPattern.MatchString(tag) {
+					b.message = fmt.Sprintf("%q isn't an image tag, e.g. myapp:dev", tag)
+					return nil
+				}
+				return m.startBuild(tag)
+			default:
+				b.tag.HandleKey(msg)
+				b.message = ""
+			}
+		case building:
+			if msg.String() == "esc" && b.cancel != nil {
+				b.cancel()
+				b.message = "Cancelling..."
+			}
+		case built:
+			switch msg.String() {
+			case "esc", "enter":
+				m.build = nil
+			}
+		}
+	}
+	return nil
+}
+
+// startBuild runs docker build on the chosen folder, handing its output
+// to the view a line at a time
+func (m *Model) startBuild(tag string) tea.Cmd {
+	b := m.build
+	b.stage, b.message, b.lines = building, "", nil
+	b.tag.SetValue(tag)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	cmd := exec.CommandContext(ctx, "docker", "build", "-t", tag, b.dir)
+	// Plain progress is a line per step instead of a redrawn screen
+	cmd.Env = append(os.Environ(), "BUILDKIT_PROGRESS=plain")
+
+	lines := make(chan string, 64)
+	done := make(chan buildDoneMsg, 1)
+	go func() {
+		defer cancel()
+		err := runner.Stream(m.runner, cmd, func(line string) { lines <- line })
+		close(lines)
+		result := buildDoneMsg{build: b, err: err}
+		if ctx.Err() != nil {
+			result.err = context.Canceled
+		} else if err == nil {
+			result.size, result.err = m.imageSize(tag)
+		}
+		done <- result
+	}()
+	b.wait = func() tea.Msg {
+		if line, ok := <-lines; ok {
+			return buildLineMsg{build: b, line: line}
+		}
+		return <-done
+	}
+	return b.wait
+}
+
+// imageSize reads the size of a built image
+func (m *Model) imageSize(tag string) (int64, error) {
+	out, err := m.runner.Output(exec.Command("docker", "image", "inspect", "--format", "{{.Size}}", tag))
+	if err != nil {
+		return 0, fmt.Errorf("built, but couldn't read the image size: %w", err)
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+}
+
+// finishBuild reports the result, with a toast so a build left running
+// in another tab is noticed
+func (m *Model) finishBuild(msg buildDoneMsg) tea.Cmd {
+	b := m.build
+	b.stage, b.message, b.cancel = built, "", nil
+	tag := b.tag.Value()
+	kind := components.ToastSuccess
+	switch {
+	case errors.Is(msg.err, context.Canceled):
+		b.report, b.failed = "Build of "+tag+" cancelled", true
+		return nil
+	case msg.err != nil:
+		b.report, b.failed = fmt.Sprintf("Build of %s failed: %v", tag, msg.err), true
+		kind = components.ToastError
+	default:
+		b.report = fmt.Sprintf("Built %s · %s", tag, formatSize(msg.size))
+	}
+	return components.Toast(kind, b.report)
+}
+
+var (
+	tagPattern     = regexp.MustCompile(`^[a-z0-9]+(?:[._/-][a-z0-9]+)*(?::[\w][\w.-]{0,127})?$`)
+	tagUnsafeChars = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// defaultTag names the image after its folder, e.g. "My App" → my-app:latest
+func defaultTag(dir string) string {
+	name := strings.Trim(tagUnsafeChars.ReplaceAllString(strings.ToLower(filepath.Base(dir)), "-"), "-")
+	if name == "" {
+		name = "image"
+	}
+	return name + ":latest"
+}
+
+// renderBuild is the build flow's screen
+func (m *Model) renderBuild() string {
+	b := m.build
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🐳 DOCKER › BUILD  ")
+	if b.stage != pickContext {
+		title += lipgloss.NewStyle().Bold(true).Render(tildePath(b.dir))
+	}
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	subtle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+
+	var view strings.Builder
+	view.WriteString(title + "\n\n")
+	switch b.stage {
+	case pickContext:
+		roots := make([]string, len(m.config.WorkspaceRoots()))
+		for i, root := range m.config.WorkspaceRoots() {
+			roots[i] = tildePath(expandHome(root))
+		}
+		switch {
+		case b.scanning:
+			view.WriteString(muted.Render("Looking for Dockerfiles in "+strings.Join(roots, ", ")+"...") + "\n")
+			return view.String()
+		case len(b.contexts) == 0:
+			view.WriteString("No Dockerfiles found in " + strings.Join(roots, ", ") + ".\n")
+			view.WriteString(muted.Render("Set modules.docker.workspace_roots in the config to search elsewhere.") + "\n\n")
+		default:
+			view.WriteString(muted.Render("Folders with a Dockerfile in "+strings.Join(roots, ", ")) + "\n\n")
+			item := lipgloss.NewStyle().PaddingLeft(2)
+			sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
+			start, end := scrollWindow(b.cursor, len(b.contexts), max(m.height-12, 5))
+			for i := start; i < end; i++ {
+				if i == b.cursor {
+					view.WriteString(sel.Render("▶ "+tildePath(b.contexts[i])) + "\n")
+				} else {
+					view.WriteString(item.Render("  "+tildePath(b.contexts[i])) + "\n")
+				}
+			}
+			view.WriteString("\n")
+		}
+		view.WriteString(subtle.Render("[↑/↓] Select  [Enter] Choose  [r] Rescan  [Esc] Back"))
+
+	case nameImage:
+		label := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true).Width(10)
+		view.WriteString(label.Render("Tag") + b.tag.View(40) + "\n\n")
+		if b.message != "" {
+			view.WriteString(lipgloss.NewStyle().Foreground(components.ColorWarning).Render(b.message) + "\n\n")
+		}
+		view.WriteString(subtle.Render("[Enter] Build  [Esc] Back"))
+
+	case building, built:
+		switch {
+		case b.stage == building:
+			view.WriteString(muted.Render("Building "+b.tag.Value()+"... "+b.message) + "\n\n")
+		case b.failed:
+			view.WriteString(lipgloss.NewStyle().Foreground(components.ColorError).Bold(true).Render("✗ "+b.report) + "\n\n")
+		default:
+			view.WriteString(lipgloss.NewStyle().Foreground(components.ColorSuccess).Bold(true).Render("✓ "+b.report) + "\n\n")
+		}
+		shown := b.lines[max(len(b.lines)-max(m.height-10, 5), 0):]
+		line := lipgloss.NewStyle().MaxWidth(max(m.width-4, 20)).Foreground(components.ColorMuted)
+		for _, l := range shown {
+			view.WriteString(line.Render(l) + "\n")
+		}
+		view.WriteString("\n")
+		if b.stage == building {
+			view.WriteString(subtle.Render("[Esc] Cancel build"))
+		} else {
+			view.WriteString(subtle.Render("[y] Copy output  [Esc] Close"))
+		}
+	}
+	return view.String()
+}
+
+// scrollWindow returns the range of n items to show in height rows so
+// the cursor stays in view
+func scrollWindow(cursor, n, height int) (int, int) {
+	if n <= height {
+		return 0, n
+	}
+	start := min(max(cursor-height/2, 0), n-height)
+	return start, start + height
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// tildePath shortens a path under the home folder for display
+func tildePath(path string) string {
+	home, _ := os.UserHomeDir()
+	if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok && home != "" {
+		return "~/" + rest
+	}
+	return path
+}
+
+// formatSize formats an image size
+func formatSize(size int64) string {
+	const unit = 1000 // docker images reports decimal sizes
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "kMGTPE"[exp])
+}
@@ -2,38 +2,63 @@ package docker
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
 
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/opener"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 type Container struct {
-	ID     string
-	Name   string
-	Image  string
-	Status string
-	State  string
+	ID     string `json:"ID"`
+	Name   string `json:"Names"`
+	Image  string `json:"Image"`
+	Status string `json:"Status"`
+	State  string `json:"State"`
 }
 
 // Model represents the Docker module state
 type Model struct {
 	config     *config.Config
+	runner     runner.Runner
 	width      int
 	height     int
 	containers []Container
-	cursor     int
+	cursor     int // position in visible()
+	filter     components.ListFilter
+	table      components.Table
 	output     string
+	logs       string // full output of the last logs command, for y
 	runningCmd bool
 	dockerOK   bool
+	limits     *limits // open limits editor
+	build      *build  // open image build flow
+	files      *files  // open container file browser
 }
 
 // New creates a new Docker module
 func New(cfg *config.Config) *Model {
-	return &Model{config: cfg}
+	return &Model{
+		config: cfg,
+		runner: runner.Default,
+		table: components.NewTable(
+			components.Column{Title: "NAME", Width: 20, Key: "n"},
+			components.Column{Title: "IMAGE", Width: 18, Key: "i"},
+			components.Column{Title: "STATE", Width: 10, Key: "a"},
+			components.Column{Title: "STATUS", Key: "u"},
+		),
+	}
 }
 
 // Init initializes the module
@@ -47,41 +72,126 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+	case limitsMsg:
+		if m.limits != nil {
+			return m, m.updateLimits(msg)
+		}
+	case updateLimitsMsg:
+		return m, m.runUpdate(msg)
+	case contextsMsg, buildLineMsg, buildDoneMsg:
+		if m.build != nil {
+			return m, m.updateBuild(msg)
+		}
+	case filesMsg, copyInMsg, copiedMsg:
+		if m.files != nil {
+			return m, m.updateFiles(msg)
+		}
+	case events.Nav:
+		if m.files != nil {
+			return m, m.updateFiles(msg)
+		}
+		if m.build != nil {
+			return m, m.updateBuild(msg)
+		}
+		if m.limits != nil {
+			return m, m.updateLimits(msg)
+		}
+		if !m.runningCmd {
+			m.cursor = msg.Move(m.cursor, len(m.visible()))
+		}
 	case tea.KeyMsg:
+		if m.files != nil {
+			return m, m.updateFiles(msg)
+		}
+		if m.build != nil {
+			return m, m.updateBuild(msg)
+		}
 		if m.runningCmd {
 			return m, nil
 		}
+		if m.limits != nil {
+			return m, m.updateLimits(msg)
+		}
+		if m.filter.HandleKey(msg) {
+			m.cursor = 0
+			return m, nil
+		}
+		visible := m.visible()
+		if m.table.HandleKey(msg.String()) {
+			// Keep the selected container selected
+			if m.cursor < len(visible) {
+				m.cursor = position(m.visible(), visible[m.cursor])
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "r":
 			return m, m.refresh()
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down", "j":
-			if m.cursor < len(m.containers)-1 {
-				m.cursor++
-			}
 		case "s":
-			if m.cursor < len(m.containers) {
-				return m, m.toggleStartStop(m.containers[m.cursor])
+			if m.cursor < len(visible) {
+				c := m.containers[visible[m.cursor]]
+				if c.State == "running" {
+					return m, dialog.Confirm(dialog.Request{
+						Title:        "Stop " + c.Name + "?",
+						Detail:       "The container's processes are sent SIGTERM, then killed after 10 seconds.",
+						ConfirmLabel: "Stop",
+						Destructive:  true,
+						OnConfirm:    stopContainerMsg{c},
+					})
+				}
+				return m, m.toggleStartStop(c)
 			}
 		case "l":
-			if m.cursor < len(m.containers) {
-				return m, m.tailLogs(m.containers[m.cursor])
+			if m.cursor < len(visible) {
+				return m, m.tailLogs(m.containers[visible[m.cursor]])
+			}
+		case "m":
+			if m.cursor < len(visible) {
+				return m, m.editLimits(m.containers[visible[m.cursor]])
+			}
+		case "f":
+			if m.cursor < len(visible) {
+				return m, m.browseFiles(m.containers[visible[m.cursor]])
+			}
+		case "b":
+			return m, m.openBuild()
+		case "o", "t", "e":
+			if m.cursor < len(visible) {
+				return m, m.openMount(m.containers[visible[m.cursor]], openTargets[msg.String()])
 			}
 		}
 	case containersMsg:
 		m.containers = msg.items
 		m.output = msg.note
+		m.logs = ""
 		m.dockerOK = msg.ok
-		if m.cursor >= len(m.containers) {
+		if m.cursor >= len(m.visible()) {
 			m.cursor = 0
 		}
 		m.runningCmd = false
 	case actionMsg:
 		m.output = msg.note
+		m.logs = msg.logs
 		m.runningCmd = false
+		if msg.toast {
+			kind := components.ToastSuccess
+			if msg.failed {
+				kind = components.ToastError
+			}
+			toast := components.ToastMsg{Kind: kind, Text: msg.note, Result: selectContainerMsg{id: msg.id}}
+			return m, func() tea.Msg { return toast }
+		}
+	case stopContainerMsg:
+		if !m.runningCmd {
+			return m, m.toggleStartStop(msg.container)
+		}
+	case selectContainerMsg:
+		m.filter.Reset()
+		for i, c := range m.containers {
+			if c.ID == msg.id {
+				m.cursor = position(m.visible(), i)
+			}
+		}
 	}
 	return m, nil
 }
@@ -92,30 +202,46 @@ func (m *Model) View() string {
 		return "Loading..."
 	}
 
-	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF")).Render("🐳 DOCKER")
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("[r] Refresh  [s] Start/Stop  [l] Logs")
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🐳 DOCKER")
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[r] Refresh  [s] Start/Stop  [l] Logs  [m] Limits  [f] Files  [b] Build\n[/] Filter  " + m.table.SortHint() + "  [o/t/e] Open Mount")
 
 	if !m.dockerOK {
 		msg := "Docker not available. Install Docker Desktop and ensure the daemon is running."
 		return lipgloss.JoinVertical(lipgloss.Top, title, "", msg)
 	}
+	if m.limits != nil {
+		return m.renderLimits()
+	}
+	if m.build != nil {
+		return m.renderBuild()
+	}
+	if m.files != nil {
+		return m.renderFiles()
+	}
 
 	var b strings.Builder
 	b.WriteString(title + "\n\n")
 	if m.output != "" {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(m.output))
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorMuted).Render(m.output))
 		b.WriteString("\n\n")
 	}
 	b.WriteString(help + "\n\n")
 
+	visible := m.visible()
+	if bar := m.filter.View(len(visible), len(m.containers)); bar != "" {
+		b.WriteString(bar + "\n\n")
+	}
+
 	if len(m.containers) == 0 {
 		b.WriteString("No containers found.\n")
 	} else {
+		headerStyle := lipgloss.NewStyle().PaddingLeft(4).Bold(true).Foreground(components.ColorPrimary)
 		item := lipgloss.NewStyle().PaddingLeft(2)
-		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#00D9FF")).Bold(true)
+		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
 
-		for i, c := range m.containers {
-			line := fmt.Sprintf("%-20s %-18s %-10s %s", truncate(c.Name, 20), truncate(c.Image, 18), c.State, c.Status)
+		b.WriteString(headerStyle.Render(m.table.Header()) + "\n")
+		for i, index := range visible {
+			line := m.table.Row(containerCells(m.containers[index])...)
 			if i == m.cursor {
 				b.WriteString(sel.Render("▶ " + line))
 			} else {
@@ -138,8 +264,138 @@ func (m *Model) View() string {
 // Title returns the module title
 func (m *Model) Title() string { return "Docker" }
 
+// Help describes the Docker keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "DOCKER",
+		Description: "Containers from the local Docker daemon.",
+		Sections: []help.Section{{Title: "Keys", Bindings: []help.Binding{
+			{Key: "↑/↓", Desc: "Navigate containers"},
+			{Key: "S", Desc: "Start or stop the selected container"},
+			{Key: "L", Desc: "Show its recent logs"},
+			{Key: "M", Desc: "Change its CPU and memory limits (docker update), with its usage shown"},
+			{Key: "F", Desc: "Browse its files, copying them to the host (D) or from it (P) with docker cp"},
+			{Key: "B", Desc: "Build an image from a folder with a Dockerfile in the workspace roots"},
+			{Key: "O / T / E", Desc: "Open its first mount in Finder / Terminal / editor"},
+			{Key: "/", Desc: "Filter containers"},
+			{Key: "N / I / A / U", Desc: "Sort by name, image, state or status (again to reverse)"},
+			{Key: "y", Desc: "Copy the build output or logs shown, or the container name"},
+			{Key: "Y", Desc: "Copy the docker start or stop command for it"},
+			{Key: "R", Desc: "Refresh"},
+		}}},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
-func (m *Model) HasOpenModal() bool { return false }
+func (m *Model) HasOpenModal() bool {
+	return m.filter.Active() || m.limits != nil || m.build != nil || m.files != nil
+}
+
+// Copyable returns the build output or logs last shown, the selected
+// container path, or the selected container's name
+func (m *Model) Copyable() string {
+	if m.files != nil {
+		if m.files.copying != "" {
+			return ""
+		}
+		return m.files.selectedPath()
+	}
+	if m.build != nil {
+		if m.build.stage == nameImage {
+			return ""
+		}
+		return strings.Join(m.build.lines, "\n")
+	}
+	if m.filter.Typing() {
+		return ""
+	}
+	if m.logs != "" {
+		return m.logs
+	}
+	visible := m.visible()
+	if m.cursor < len(visible) {
+		return m.containers[visible[m.cursor]].Name
+	}
+	return ""
+}
+
+// visible returns the indexes of the containers matching the filter, in
+// the table's sort order, else best match first, else list order
+func (m *Model) visible() []int {
+	texts := make([]string, len(m.containers))
+	for i, c := range m.containers {
+		texts[i] = c.Name + " " + c.Image + " " + c.State
+	}
+	visible := m.filter.Matches(texts)
+	m.table.Sort(visible, func(index, column int) string {
+		return containerCells(m.containers[index])[column]
+	})
+	return visible
+}
+
+// containerCells are a container's cells in the table
+func containerCells(c Container) []string {
+	return []string{c.Name, c.Image, c.State, c.Status}
+}
+
+// position returns where index is shown in visible, or 0
+func position(visible []int, index int) int {
+	for i, v := range visible {
+		if v == index {
+			return i
+		}
+	}
+	return 0
+}
+
+// SearchItems returns containers for global search
+func (m *Model) SearchItems() []palette.Command {
+	items := make([]palette.Command, 0, len(m.containers))
+	for _, c := range m.containers {
+		items = append(items, palette.Command{
+			Title: c.Name,
+			Hint:  fmt.Sprintf("%s • %s", c.Image, c.State),
+			Msg:   selectContainerMsg{id: c.ID},
+		})
+	}
+	return items
+}
+
+// savedState is what the module keeps between launches
+type savedState struct {
+	Cursor int `json:"cursor"`
+}
+
+// SaveState returns the selected container position
+func (m *Model) SaveState() (json.RawMessage, error) {
+	// The filter isn't saved, so store the index in the full list
+	cursor := 0
+	if visible := m.visible(); m.cursor < len(visible) {
+		cursor = visible[m.cursor]
+	}
+	return json.Marshal(savedState{Cursor: cursor})
+}
+
+// RestoreState applies state saved by the previous launch
+func (m *Model) RestoreState(data json.RawMessage) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	m.cursor = saved.Cursor
+	return nil
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Refresh containers", Hint: "List containers with docker ps", Msg: palette.Key("r")},
+		{Title: "Open container mount in Finder", Hint: "Reveal the selected container's bind mount", Msg: palette.Key("o")},
+		{Title: "Browse container files", Hint: "List the selected container's files and copy them with docker cp", Msg: palette.Key("f")},
+		{Title: "Build image", Hint: "docker build a folder with a Dockerfile from the workspace roots", Msg: palette.Key("b")},
+		{Title: "Edit container limits", Hint: "Change the selected container's CPU and memory limits", Msg: palette.Key("m")},
+	}
+}
 
 // Messages
 type containersMsg struct {
@@ -147,85 +403,163 @@ type containersMsg struct {
 	note  string
 	ok    bool
 }
-type actionMsg struct{ note string }
+
+// actionMsg reports a finished action; toast is set for start/stop, which
+// can take a while, so the result is seen from other tabs too
+type actionMsg struct {
+	id     string // container acted on
+	note   string
+	logs   string
+	toast  bool
+	failed bool
+}
+
+type selectContainerMsg struct{ id string }
+
+// stopContainerMsg is the confirmed stop of a running container
+type stopContainerMsg struct{ container Container }
 
 func (m *Model) refresh() tea.Cmd {
 	m.runningCmd = true
 	return func() tea.Msg {
-		if _, err := exec.LookPath("docker"); err != nil {
+		if _, err := m.runner.LookPath("docker"); err != nil {
 			return containersMsg{ok: false, note: "docker CLI not found"}
 		}
-		out, err := exec.Command("docker", "ps", "-a", "--format", "{{.ID}}|{{.Names}}|{{.Image}}|{{.Status}}|{{.State}}").Output()
+		out, err := m.runner.Output(exec.Command("docker", "ps", "-a", "--format", "{{json .}}"))
 		if err != nil {
 			return containersMsg{ok: false, note: "Docker daemon not reachable"}
 		}
-		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-		items := []Container{}
-		for _, l := range lines {
-			if strings.TrimSpace(l) == "" {
-				continue
-			}
-			parts := strings.SplitN(l, "|", 5)
-			if len(parts) < 5 {
-				continue
-			}
-			items = append(items, Container{ID: parts[0], Name: parts[1], Image: parts[2], Status: parts[3], State: parts[4]})
+		items, err := parseContainers(out)
+		if err != nil {
+			return containersMsg{ok: false, note: fmt.Sprintf("Unexpected docker ps output: %v", err)}
 		}
 		note := fmt.Sprintf("%d containers", len(items))
 		return containersMsg{items: items, note: note, ok: true}
 	}
 }
 
+// parseContainers parses `docker ps --format "{{json .}}"` output, one
+// JSON object per line
+func parseContainers(output []byte) ([]Container, error) {
+	items := []Container{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var c Container
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, err
+		}
+		items = append(items, c)
+	}
+	return items, scanner.Err()
+}
+
+// startStopCmd stops a running container and starts a stopped one
+func startStopCmd(c Container) *exec.Cmd {
+	if c.State == "running" {
+		return exec.Command("docker", "stop", c.ID)
+	}
+	return exec.Command("docker", "start", c.ID)
+}
+
 func (m *Model) toggleStartStop(c Container) tea.Cmd {
 	m.runningCmd = true
 	return func() tea.Msg {
-		var cmd *exec.Cmd
-		if c.State == "running" {
-			cmd = exec.Command("docker", "stop", c.ID)
-		} else {
-			cmd = exec.Command("docker", "start", c.ID)
-		}
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return actionMsg{note: fmt.Sprintf("Error: %v: %s", err, string(out))}
+		if out, err := m.runner.CombinedOutput(startStopCmd(c)); err != nil {
+			return actionMsg{id: c.ID, note: fmt.Sprintf("Error: %v: %s", err, string(out)), toast: true, failed: true}
 		}
 		// Refresh after action
-		return actionMsg{note: fmt.Sprintf("Toggled %s", c.Name)}
+		return actionMsg{id: c.ID, note: fmt.Sprintf("Toggled %s", c.Name), toast: true}
 	}
 }
 
-func (m *Model) tailLogs(c Container) tea.Cmd {
+// CommandLines returns the docker command s runs on the selected container
+func (m *Model) CommandLines() []string {
+	if m.files != nil || m.build != nil || m.limits != nil || m.filter.Typing() {
+		return nil
+	}
+	visible := m.visible()
+	if m.cursor < len(visible) {
+		return []string{runner.Line(startStopCmd(m.containers[visible[m.cursor]]))}
+	}
+	return nil
+}
+
+// openTargets maps the open keys to where the mount is opened
+var openTargets = map[string]opener.Target{
+	"o": opener.Finder,
+	"t": opener.Terminal,
+	"e": opener.Editor,
+}
+
+// mount is the part of `docker inspect` .Mounts we read
+type mount struct {
+	Type        string `json:"Type"`
+	Source      string `json:"Source"`
+	Destination string `json:"Destination"`
+}
+
+// bindMounts returns the host directories bind-mounted into a container.
+// Named volumes live inside the Docker VM and can't be opened from macOS.
+func bindMounts(data []byte) ([]mount, error) {
+	var mounts []mount
+	if err := json.Unmarshal(bytes.TrimSpace(data), &mounts); err != nil {
+		return nil, err
+	}
+	var binds []mount
+	for _, mt := range mounts {
+		if mt.Type == "bind" {
+			binds = append(binds, mt)
+		}
+	}
+	return binds, nil
+}
+
+// openMount opens the first bind mount of c in target
+func (m *Model) openMount(c Container, target opener.Target) tea.Cmd {
 	m.runningCmd = true
 	return func() tea.Msg {
-		cmd := exec.Command("docker", "logs", "--tail", "50", c.ID)
-		pipe, err := cmd.StdoutPipe()
+		raw, err := m.runner.Output(exec.Command("docker", "inspect", "--format", "{{json .Mounts}}", c.ID))
 		if err != nil {
-			return actionMsg{note: err.Error()}
+			return actionMsg{note: fmt.Sprintf("Error: %v", err)}
 		}
-		_ = cmd.Start()
-		scanner := bufio.NewScanner(pipe)
-		var b strings.Builder
-		for scanner.Scan() {
-			b.WriteString(scanner.Text())
-			b.WriteString("\n")
+		binds, err := bindMounts(raw)
+		if err != nil {
+			return actionMsg{note: fmt.Sprintf("Unexpected docker inspect output: %v", err)}
+		}
+		if len(binds) == 0 {
+			return actionMsg{note: fmt.Sprintf("%s has no bind mounts to open", c.Name)}
 		}
-		_ = cmd.Wait()
-		out := b.String()
+
+		if err := opener.Open(m.runner, m.config, target, binds[0].Source); err != nil {
+			return actionMsg{note: fmt.Sprintf("Error: %v", err)}
+		}
+		note := fmt.Sprintf("Opened %s (%s) in %s", binds[0].Source, binds[0].Destination, target)
+		if len(binds) > 1 {
+			note += fmt.Sprintf(" • %d more bind mount(s)", len(binds)-1)
+		}
+		return actionMsg{note: note}
+	}
+}
+
+func (m *Model) tailLogs(c Container) tea.Cmd {
+	m.runningCmd = true
+	return func() tea.Msg {
+		raw, err := m.runner.Output(exec.Command("docker", "logs", "--tail", "50", c.ID))
+		if err != nil && len(raw) == 0 {
+			return actionMsg{note: err.Error()}
+		}
+		out := string(raw)
 		if len(out) > 500 {
 			lines := strings.Split(out, "\n")
 			if len(lines) > 10 {
 				out = strings.Join(lines[len(lines)-10:], "\n")
 			}
 		}
-		return actionMsg{note: fmt.Sprintf("Logs for %s:\n%s", c.Name, out)}
-	}
-}
-
-func truncate(s string, n int) string {
-	if len(s) <= n {
-		return s
-	}
-	if n <= 3 {
-		return s[:n]
+		return actionMsg{note: fmt.Sprintf("Logs for %s:\n%s", c.Name, out), logs: string(raw)}
 	}
-	return s[:n-3] + "..."
 }
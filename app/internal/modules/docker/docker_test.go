@@ -0,0 +1,465 @@
+package docker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const psCmdline = "docker ps -a --format {{json .}}"
+
+func newTestModel(t *testing.T, fake *runner.Fake) *Model {
+	t.Helper()
+	m := New(nil)
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	return m
+}
+
+func TestParseContainers(t *testing.T) {
+	fake := runner.NewFake()
+	if err := fake.SetFixture(psCmdline, filepath.Join("testdata", "docker_ps.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(t, fake)
+
+	msg, ok := m.refresh()().(containersMsg)
+	if !ok {
+		t.Fatalf("refresh returned %T, want containersMsg", msg)
+	}
+	if !msg.ok {
+		t.Fatalf("refresh reported docker unavailable: %s", msg.note)
+	}
+
+	want := []Container{
+		{ID: "3f4e1a2b9c01", Name: "postgres-dev", Image: "postgres:16-alpine", Status: "Up 3 hours", State: "running"},
+		{ID: "a81c55d0e7f2", Name: "redis-cache", Image: "redis:7", Status: "Exited (0) 2 days ago", State: "exited"},
+		{ID: "0bd9e3c4f5a6", Name: "api_gateway_with_a_long_name", Image: "ghcr.io/acme/gateway:latest", Status: "Created", State: "created"},
+	}
+	if len(msg.items) != len(want) {
+		t.Fatalf("got %d containers, want %d", len(msg.items), len(want))
+	}
+	for i := range want {
+		if msg.items[i] != want[i] {
+			t.Errorf("container %d = %+v, want %+v", i, msg.items[i], want[i])
+		}
+	}
+	if msg.note != "3 containers" {
+		t.Errorf("note = %q, want %q", msg.note, "3 containers")
+	}
+}
+
+func TestParseContainersSkipsBlankLines(t *testing.T) {
+	items, err := parseContainers([]byte("\n{\"ID\":\"123\",\"Names\":\"web\",\"Image\":\"nginx\",\"Status\":\"Up\",\"State\":\"running\"}\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "web" {
+		t.Fatalf("got %+v, want only the web container", items)
+	}
+}
+
+func TestParseContainersRejectsLegacyFormat(t *testing.T) {
+	if _, err := parseContainers([]byte("123|web|nginx|Up|running\n")); err == nil {
+		t.Fatal("expected an error for non-JSON output")
+	}
+}
+
+func TestRefreshWithoutDocker(t *testing.T) {
+	m := newTestModel(t, runner.NewFake().Missing("docker"))
+
+	msg := m.refresh()().(containersMsg)
+	if msg.ok || msg.note != "docker CLI not found" {
+		t.Fatalf("got %+v, want docker CLI not found", msg)
+	}
+}
+
+func TestRefreshDaemonDown(t *testing.T) {
+	fake := runner.NewFake().Set(psCmdline, "", errors.New("exit status 1"))
+	m := newTestModel(t, fake)
+
+	m.Update(m.refresh()())
+	if m.dockerOK {
+		t.Fatal("dockerOK should be false when the daemon is unreachable")
+	}
+	if m.runningCmd {
+		t.Fatal("runningCmd should be cleared once the refresh completes")
+	}
+}
+
+func TestToggleStartStop(t *testing.T) {
+	fake := runner.NewFake().
+		Set("docker stop 3f4e1a2b9c01", "3f4e1a2b9c01\n", nil).
+		Set("docker start a81c55d0e7f2", "a81c55d0e7f2\n", nil)
+	m := newTestModel(t, fake)
+	m.containers = []Container{
+		{ID: "3f4e1a2b9c01", Name: "postgres-dev", State: "running"},
+		{ID: "a81c55d0e7f2", Name: "redis-cache", State: "exited"},
+	}
+
+	// Stopping a running container asks first
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	req, ok := cmd().(dialog.Request)
+	if !ok || len(fake.Calls()) != 0 {
+		t.Fatalf("s on a running container returned %T, want a confirmation", req)
+	}
+	_, cmd = m.Update(req.OnConfirm)
+	m.Update(cmd())
+	m.Update(events.NavDown)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m.Update(cmd())
+
+	calls := fake.Calls()
+	want := []string{"docker stop 3f4e1a2b9c01", "docker start a81c55d0e7f2"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %q, want %q", i, calls[i], want[i])
+		}
+	}
+	if m.output != "Toggled redis-cache" {
+		t.Errorf("output = %q, want %q", m.output, "Toggled redis-cache")
+	}
+}
+
+func TestSearchSelectsContainer(t *testing.T) {
+	m := newTestModel(t, runner.NewFake())
+	m.containers = []Container{
+		{ID: "3f4e1a2b9c01", Name: "postgres-dev", Image: "postgres:16", State: "running"},
+		{ID: "a81c55d0e7f2", Name: "redis-cache", Image: "redis:7", State: "exited"},
+	}
+
+	items := m.SearchItems()
+	if len(items) != 2 || items[1].Title != "redis-cache" || items[1].Hint != "redis:7 • exited" {
+		t.Fatalf("items = %+v", items)
+	}
+	m.Update(items[1].Msg)
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+}
+
+func TestFilterSelectsMatchingContainer(t *testing.T) {
+	fake := runner.NewFake().Set("docker start a81c55d0e7f2", "a81c55d0e7f2\n", nil)
+	m := newTestModel(t, fake)
+	m.containers = []Container{
+		{ID: "3f4e1a2b9c01", Name: "postgres-dev", Image: "postgres:16", State: "running"},
+		{ID: "a81c55d0e7f2", Name: "redis-cache", Image: "redis:7", State: "exited"},
+	}
+
+	for _, key := range []string{"/", "r", "e", "d"} {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	if !m.HasOpenModal() {
+		t.Fatal("an active filter should keep Esc inside the module")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if cmd == nil {
+		t.Fatal("s should toggle the filtered container")
+	}
+	m.Update(cmd())
+
+	if calls := fake.Calls(); len(calls) != 1 || calls[0] != "docker start a81c55d0e7f2" {
+		t.Errorf("calls = %q, want redis-cache started", calls)
+	}
+}
+
+func TestOpenMount(t *testing.T) {
+	dir := t.TempDir()
+	mounts := `[{"Type":"volume","Source":"/var/lib/docker/volumes/pgdata/_data","Destination":"/var/lib/postgresql/data"},` +
+		`{"Type":"bind","Source":"` + dir + `","Destination":"/app"}]`
+	fake := runner.NewFake().
+		Set("docker inspect --format {{json .Mounts}} 3f4e1a2b9c01", mounts+"\n", nil).
+		Set("open -a Terminal "+dir, "", nil)
+	m := newTestModel(t, fake)
+	m.containers = []Container{{ID: "3f4e1a2b9c01", Name: "postgres-dev", State: "running"}}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m.Update(cmd())
+
+	if want := "Opened " + dir + " (/app) in Terminal"; m.output != want {
+		t.Errorf("output = %q, want %q", m.output, want)
+	}
+}
+
+func TestOpenMountWithoutBinds(t *testing.T) {
+	fake := runner.NewFake().Set("docker inspect --format {{json .Mounts}} a81c55d0e7f2", "[]\n", nil)
+	m := newTestModel(t, fake)
+	m.containers = []Container{{ID: "a81c55d0e7f2", Name: "redis-cache", State: "exited"}}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m.Update(cmd())
+
+	if m.output != "redis-cache has no bind mounts to open" {
+		t.Errorf("output = %q", m.output)
+	}
+}
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			fake := runner.NewFake()
+			if err := fake.SetFixture(psCmdline, filepath.Join("testdata", "docker_ps.jsonl")); err != nil {
+				t.Fatal(err)
+			}
+			m := New(nil)
+			m.runner = fake
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			m.Update(m.refresh()())
+			golden.RequireEqual(t, m.View())
+		})
+	}
+}
+
+func TestSortKeepsSelection(t *testing.T) {
+	m := newTestModel(t, runner.NewFake())
+	m.containers = []Container{
+		{ID: "3f4e1a2b9c01", Name: "postgres-dev", Image: "postgres:16", State: "running"},
+		{ID: "a81c55d0e7f2", Name: "redis-cache", Image: "redis:7", State: "exited"},
+		{ID: "c07b9e3d2a44", Name: "api", Image: "node:20", State: "running"},
+	}
+	m.cursor = 1
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	visible := m.visible()
+	if names := []string{m.containers[visible[0]].Name, m.containers[visible[1]].Name, m.containers[visible[2]].Name}; names[0] != "api" || names[2] != "redis-cache" {
+		t.Fatalf("sorted by name = %q", names)
+	}
+	if got := m.containers[visible[m.cursor]].Name; got != "redis-cache" {
+		t.Errorf("selected %q after sorting, want redis-cache", got)
+	}
+
+	m.Update(m.SearchItems()[2].Msg)
+	if got := m.containers[m.visible()[m.cursor]].Name; got != "api" {
+		t.Errorf("search selected %q in the sorted list, want api", got)
+	}
+}
+
+func TestEditLimits(t *testing.T) {
+	const id = "3f4e1a2b9c01"
+	fake := runner.NewFake().
+		Set("docker inspect --format {{json .HostConfig}} "+id, `{"NanoCpus":2000000000,"Memory":1073741824,"MemorySwap":2147483648}`+"\n", nil).
+		Set("docker stats --no-stream --format {{json .}} "+id, `{"CPUPerc":"97.42%","MemUsage":"812.5MiB / 1GiB","MemPerc":"79.35%","Name":"postgres-dev"}`+"\n", nil)
+	m := newTestModel(t, fake)
+	m.containers = []Container{{ID: id, Name: "postgres-dev", Image: "postgres:16-alpine", State: "running"}}
+	m.dockerOK = true
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m.Update(cmd())
+	if !m.HasOpenModal() {
+		t.Fatal("limits editor not open")
+	}
+	golden.RequireEqual(t, m.View())
+
+	typeInto := func(field int, text string) {
+		m.limits.field = field
+		m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+	}
+
+	// Below current usage, the update waits for confirmation
+	typeInto(0, "1.5")
+	typeInto(1, "512m")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	req, ok := cmd().(dialog.Request)
+	if !ok {
+		t.Fatalf("got %T, want a confirmation below usage", req)
+	}
+	update := req.OnConfirm.(updateLimitsMsg)
+	want := "update --cpus 1.5 --memory 536870912 --memory-swap -1 " + id
+	if got := strings.Join(update.args, " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+
+	// Above it, it runs at once
+	fake.Set("docker update --memory 2147483648 --memory-swap -1 "+id, id+"\n", nil)
+	typeInto(0, "2")
+	typeInto(1, "2g")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if m.HasOpenModal() || m.output != "Limited postgres-dev to 2g memory" {
+		t.Errorf("after applying: output = %q, editor open = %v", m.output, m.HasOpenModal())
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	for input, want := range map[string]int64{
+		"512m":     512 << 20,
+		"2g":       2 << 30,
+		"1.5GiB":   3 << 29,
+		"812.5MiB": 851968000,
+		"64k":      64 << 10,
+		"1048576":  1 << 20,
+	} {
+		if got, err := parseMemory(input); err != nil || got != want {
+			t.Errorf("parseMemory(%q) = %d, %v, want %d", input, got, err, want)
+		}
+	}
+	if _, err := parseMemory("lots"); err == nil {
+		t.Error("parseMemory(lots) succeeded")
+	}
+}
+
+func TestBuildImage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, dir := range []string{"dev/My App", "dev/api/deploy", "dev/web/node_modules/pkg", "dev/.cache/x", "dev/a/b/c/d"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(home, dir, "Dockerfile"), []byte("FROM scratch\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	context := filepath.Join(home, "dev", "My App")
+	fake := runner.NewFake().
+		Set("docker build -t my-app:latest "+context, "#1 [internal] load build definition from Dockerfile\r\n#1 DONE 0.0s\n#2 exporting to image\n#2 DONE 0.1s\n", nil).
+		Set("docker image inspect --format {{.Size}} my-app:latest", "52428800\n", nil)
+	m := newTestModel(t, fake)
+	m.config = &config.Config{Modules: config.ModulesConfig{Docker: config.DockerConfig{WorkspaceRoots: []string{"~/dev"}}}}
+	m.dockerOK = true
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m.Update(cmd())
+	want := []string{filepath.Join(home, "dev", "My App"), filepath.Join(home, "dev", "api", "deploy")}
+	if strings.Join(m.build.contexts, "|") != strings.Join(want, "|") {
+		t.Fatalf("contexts = %q, want %q (no dependency, hidden or too deep folders)", m.build.contexts, want)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.build.tag.Value(); got != "my-app:latest" {
+		t.Errorf("default tag = %q, want my-app:latest", got)
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// Output arrives a line at a time, then the image size
+	var lines int
+	for cmd != nil {
+		msg := cmd()
+		if _, ok := msg.(buildLineMsg); ok {
+			lines++
+		}
+		if toast, ok := msg.(components.ToastMsg); ok {
+			if toast.Kind != components.ToastSuccess {
+				t.Errorf("toast = %+v, want success", toast)
+			}
+			break
+		}
+		_, cmd = m.Update(msg)
+	}
+	if lines != 4 {
+		t.Errorf("got %d output lines, want 4", lines)
+	}
+	golden.RequireEqual(t, m.View())
+
+	if got := m.Copyable(); !strings.HasPrefix(got, "#1 [internal] load build definition from Dockerfile\n#1 DONE") {
+		t.Errorf("Copyable() = %q, want the build output", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.HasOpenModal() {
+		t.Error("build view still open after Esc")
+	}
+}
+
+func TestDefaultTag(t *testing.T) {
+	for dir, want := range map[string]string{
+		"/src/My App":     "my-app:latest",
+		"/src/api_server": "api-server:latest",
+		"/src/___":        "image:latest",
+	} {
+		if got := defaultTag(dir); got != want || !tagPattern.MatchString(got) {
+			t.Errorf("defaultTag(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestBrowseFiles(t *testing.T) {
+	const id = "3f4e1a2b9c01"
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	fake := runner.NewFake().
+		Set("docker exec "+id+" ls -1Ap /", ".dockerenv\nbin/\netc/\nvar/\n", nil).
+		Set("docker exec "+id+" ls -1Ap /var", "lib/\nlog/\n", nil).
+		Set("docker exec "+id+" ls -1Ap /var/log", "postgresql.log\n", nil).
+		Set("docker cp "+id+":/var/log/postgresql.log "+filepath.Join(home, "Downloads", "postgresql.log"), "", nil).
+		Set("docker cp "+filepath.Join(home, "init.sql")+" "+id+":/var/log/", "", nil)
+	m := newTestModel(t, fake)
+	m.containers = []Container{{ID: id, Name: "postgres-dev", Image: "postgres:16-alpine", State: "running"}}
+	m.dockerOK = true
+
+	var run func(tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		for cmd != nil {
+			msg := cmd()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, c := range batch {
+					run(c)
+				}
+				return
+			}
+			_, cmd = m.Update(msg)
+		}
+	}
+	key := func(k string) tea.Cmd {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		return cmd
+	}
+	run(key("f"))
+	m.Update(events.NavBottom)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	run(cmd)
+	m.Update(events.NavDown)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	run(cmd)
+	golden.RequireEqual(t, m.View())
+
+	// d copies the selection to ~/Downloads, p copies into the folder shown
+	key("d")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	run(cmd)
+	if want := "Copied postgres-dev:/var/log/postgresql.log to ~/Downloads/postgresql.log"; m.files.message != want {
+		t.Errorf("after copying out: %q, want %q", m.files.message, want)
+	}
+	// Copying in starts from an empty path, refuses the whole home folder
+	// and asks first, showing the source
+	key("p")
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("~/")})
+	if _, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !strings.Contains(m.files.message, "not all of it") {
+		t.Fatalf("copying in ~/: message %q", m.files.message)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("~/init.sql")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	req, ok := cmd().(dialog.Request)
+	if !ok || !strings.Contains(req.Detail, filepath.Join(home, "init.sql")) {
+		t.Fatalf("Enter returned %#v, want a confirmation naming the source", req)
+	}
+	if len(fake.Calls()) != 5 {
+		t.Errorf("copied before confirming: %q", fake.Calls())
+	}
+	_, cmd = m.Update(req.OnConfirm)
+	run(cmd)
+	if want := "Copied ~/init.sql to postgres-dev:/var/log"; m.files.message != want {
+		t.Errorf("after copying in: %q, want %q", m.files.message, want)
+	}
+
+	// Going up selects the folder we came from
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	run(cmd)
+	if got := m.Copyable(); got != "/var/log" {
+		t.Errorf("after going up, selected %q, want /var/log", got)
+	}
+}
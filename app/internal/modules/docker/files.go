@@ -0,0 +1,324 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// files browses a running container's filesystem with docker exec ls and
+// copies files in and out with docker cp
+type files struct {
+	container Container
+	dir       string // absolute path in the container
+	entries   []string
+	cursor    int
+	loading   bool
+	err       error
+
+	// copying is "out" or "in" while the path prompt is open
+	copying string
+	target  components.TextInput
+	message string
+}
+
+type filesMsg struct {
+	id      string
+	dir     string
+	entries []string
+	err     error
+}
+
+// copyInMsg confirms copying host into the folder shown
+type copyInMsg struct {
+	id   string
+	host string
+}
+
+// copiedMsg reports a finished docker cp
+type copiedMsg struct {
+	id     string
+	note   string
+	failed bool
+}
+
+// browseFiles opens the browser on c at its root
+func (m *Model) browseFiles(c Container) tea.Cmd {
+	if c.State != "running" {
+		m.output = c.Name + " isn't running; files are browsed in running containers"
+		return nil
+	}
+	m.files = &files{container: c}
+	return m.listFiles("/")
+}
+
+// listFiles lists dir with ls -1Ap, which busybox images have too; -p
+// marks folders with a trailing slash
+func (m *Model) listFiles(dir string) tea.Cmd {
+	f := m.files
+	f.loading, f.err, f.message = true, nil, ""
+	id := f.container.ID
+	return func() tea.Msg {
+		out, err := m.runner.CombinedOutput(exec.Command("docker", "exec", id, "ls", "-1Ap", dir))
+		if err != nil {
+			return filesMsg{id: id, dir: dir, err: errors.New(firstLine(out, err))}
+		}
+		var entries []string
+		for _, line := range strings.Split(string(out), "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				entries = append(entries, line)
+			}
+		}
+		return filesMsg{id: id, dir: dir, entries: entries}
+	}
+}
+
+// updateFiles handles the browser's messages and keys
+func (m *Model) updateFiles(msg tea.Msg) tea.Cmd {
+	f := m.files
+	switch msg := msg.(type) {
+	case filesMsg:
+		if msg.id != f.container.ID {
+			return nil
+		}
+		f.loading, f.err = false, msg.err
+		if msg.err == nil {
+			// A refresh keeps the selection; going up selects the folder
+			// we came from
+			cursor := 0
+			switch msg.dir {
+			case f.dir:
+				cursor = min(f.cursor, max(len(msg.entries)-1, 0))
+			case path.Dir(f.dir):
+				for i, entry := range msg.entries {
+					if entry == path.Base(f.dir)+"/" {
+						cursor = i
+					}
+				}
+			}
+			f.dir, f.entries, f.cursor = msg.dir, msg.entries, cursor
+		}
+
+	case copyInMsg:
+		if msg.id != f.container.ID {
+			return nil
+		}
+		f.loading = true
+		return m.copyFiles("in", msg.host)
+
+	case copiedMsg:
+		if msg.id != f.container.ID {
+			return nil
+		}
+		f.loading = false
+		kind, cmd := components.ToastSuccess, tea.Cmd(nil)
+		if msg.failed {
+			kind = components.ToastError
+		} else {
+			// Copying in changes the listing
+			cmd = m.listFiles(f.dir)
+		}
+		f.message = msg.note
+		return tea.Batch(components.Toast(kind, msg.note), cmd)
+
+	case events.Nav:
+		if f.copying != "" {
+			f.target.Nav(msg)
+		} else {
+			f.cursor = msg.Move(f.cursor, len(f.entries))
+		}
+
+	case tea.KeyMsg:
+		if f.copying != "" {
+			return m.handleCopyKey(msg)
+		}
+		if f.loading {
+			if msg.String() == "esc" {
+				m.files = nil
+			}
+			return nil
+		}
+		switch msg.String() {
+		case "esc":
+			m.files = nil
+		case "enter", "right":
+			if entry, ok := f.selected(); ok && strings.HasSuffix(entry, "/") {
+				return m.listFiles(path.Join(f.dir, entry))
+			}
+		case "backspace", "left", "u":
+			if f.dir != "/" {
+				return m.listFiles(path.Dir(f.dir))
+			}
+		case "r":
+			return m.listFiles(f.dir)
+		case "d":
+			if entry, ok := f.selected(); ok {
+				f.copying = "out"
+				f.target.SetValue(filepath.Join("~/Downloads", strings.TrimSuffix(entry, "/")))
+			}
+		case "p":
+			f.copying, f.message = "in", ""
+			f.target.Reset()
+		}
+	}
+	return nil
+}
+
+// handleCopyKey edits the host path of a copy and runs it on Enter
+func (m *Model) handleCopyKey(msg tea.KeyMsg) tea.Cmd {
+	f := m.files
+	switch msg.String() {
+	case "esc":
+		f.copying = ""
+		return nil
+	case "enter":
+		host := strings.TrimSpace(f.target.Value())
+		if host == "" {
+			return nil
+		}
+		if f.copying == "in" {
+			return m.confirmCopyIn(expandHome(host))
+		}
+		f.copying, f.loading = "", true
+		return m.copyFiles("out", expandHome(host))
+	}
+	f.target.HandleKey(msg)
+	return nil
+}
+
+// confirmCopyIn asks before copying host into the container. The home
+// folder and / are refused: docker cp takes a folder with everything in
+// it, ~/.ssh and keychains included.
+func (m *Model) confirmCopyIn(host string) tea.Cmd {
+	f := m.files
+	home, _ := os.UserHomeDir()
+	if clean := filepath.Clean(host); clean == "/" || home != "" && clean == filepath.Clean(home) {
+		f.message = "Pick a file or folder inside " + tildePath(host) + ", not all of it"
+		return nil
+	}
+	f.copying, f.message = "", ""
+	return dialog.Confirm(dialog.Request{
+		Title:        fmt.Sprintf("Copy %s into %s:%s?", tildePath(host), f.container.Name, f.dir),
+		Detail:       "Source: " + host + "\n\nFolders are copied with everything inside them, replacing files of the same name in the container.",
+		ConfirmLabel: "Copy",
+		OnConfirm:    copyInMsg{id: f.container.ID, host: host},
+	})
+}
+
+// copyFiles runs docker cp: "out" copies the selected entry to host,
+// "in" copies host into the folder shown
+func (m *Model) copyFiles(direction, host string) tea.Cmd {
+	f := m.files
+	c := f.container
+	var args []string
+	var note string
+	if direction == "out" {
+		entry, _ := f.selected()
+		source := path.Join(f.dir, strings.TrimSuffix(entry, "/"))
+		args = []string{"cp", c.ID + ":" + source, host}
+		note = fmt.Sprintf("Copied %s:%s to %s", c.Name, source, tildePath(host))
+	} else {
+		// A trailing slash copies into the folder, whatever host is called
+		args = []string{"cp", host, c.ID + ":" + strings.TrimSuffix(f.dir, "/") + "/"}
+		note = fmt.Sprintf("Copied %s to %s:%s", tildePath(host), c.Name, f.dir)
+	}
+	return func() tea.Msg {
+		if out, err := m.runner.CombinedOutput(exec.Command("docker", args...)); err != nil {
+			return copiedMsg{id: c.ID, note: "Copy failed: " + firstLine(out, err), failed: true}
+		}
+		return copiedMsg{id: c.ID, note: note}
+	}
+}
+
+// selected is the entry under the cursor
+func (f *files) selected() (string, bool) {
+	if f.cursor < len(f.entries) {
+		return f.entries[f.cursor], true
+	}
+	return "", false
+}
+
+// selectedPath is the container path under the cursor, for y
+func (f *files) selectedPath() string {
+	if entry, ok := f.selected(); ok {
+		return path.Join(f.dir, entry)
+	}
+	return f.dir
+}
+
+// renderFiles is the browser's screen
+func (m *Model) renderFiles() string {
+	f := m.files
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🐳 DOCKER › FILES  ") +
+		lipgloss.NewStyle().Bold(true).Render(f.container.Name+":"+f.dir)
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	subtle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+
+	var view strings.Builder
+	view.WriteString(title + "\n\n")
+	switch {
+	case f.err != nil:
+		view.WriteString(lipgloss.NewStyle().Foreground(components.ColorError).Render("Error: "+f.err.Error()) + "\n\n")
+	case f.loading && len(f.entries) == 0:
+		view.WriteString(muted.Render("Listing "+f.dir+"...") + "\n\n")
+	case len(f.entries) == 0:
+		view.WriteString(muted.Render("Empty folder") + "\n\n")
+	default:
+		item := lipgloss.NewStyle().PaddingLeft(2)
+		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
+		folder := lipgloss.NewStyle().Foreground(components.ColorPrimary)
+		start, end := scrollWindow(f.cursor, len(f.entries), max(m.height-12, 5))
+		for i := start; i < end; i++ {
+			entry := f.entries[i]
+			switch {
+			case i == f.cursor:
+				view.WriteString(sel.Render("▶ "+entry) + "\n")
+			case strings.HasSuffix(entry, "/"):
+				view.WriteString(item.Render("  "+folder.Render(entry)) + "\n")
+			default:
+				view.WriteString(item.Render("  "+entry) + "\n")
+			}
+		}
+		view.WriteString("\n")
+	}
+
+	switch f.copying {
+	case "out":
+		view.WriteString("Copy " + f.selectedPath() + " to " + f.target.View(40) + "\n\n")
+		view.WriteString(subtle.Render("[Enter] Copy  [Esc] Cancel"))
+		return view.String()
+	case "in":
+		view.WriteString("Copy " + f.target.View(40) + " into " + f.dir + "\n\n")
+		if f.message != "" {
+			view.WriteString(muted.Render(f.message) + "\n\n")
+		}
+		view.WriteString(subtle.Render("[Enter] Copy  [Esc] Cancel"))
+		return view.String()
+	}
+	if f.message != "" {
+		view.WriteString(muted.Render(f.message) + "\n\n")
+	}
+	view.WriteString(subtle.Render("[Enter] Open  [Backspace] Up  [d] Copy to host  [p] Copy from host  [r] Refresh  [Esc] Back"))
+	return view.String()
+}
+
+// firstLine is the first line of a command's output, or err when it printed
+// nothing
+func firstLine(output []byte, err error) string {
+	text := strings.TrimSpace(string(output))
+	if text == "" {
+		return err.Error()
+	}
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
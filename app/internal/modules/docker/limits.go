@@ -0,0 +1,317 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minMemory is the smallest memory limit Docker accepts
+const minMemory = 6 * 1024 * 1024
+
+// limits is the CPU and memory limit editor for a running container
+type limits struct {
+	container Container
+	loading   bool
+	err       error
+
+	cpus   float64 // current limit; 0 is none
+	memory int64   // current limit in bytes; 0 is none
+	stats  containerStats
+
+	fields  [2]components.TextInput // CPUs, memory
+	field   int
+	message string
+}
+
+// containerStats is the part of `docker stats` we show
+type containerStats struct {
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+	MemPerc  string `json:"MemPerc"`
+}
+
+// hostConfig is the part of `docker inspect` .HostConfig we read
+type hostConfig struct {
+	NanoCpus int64 `json:"NanoCpus"`
+	Memory   int64 `json:"Memory"`
+}
+
+type limitsMsg struct {
+	id     string
+	cpus   float64
+	memory int64
+	stats  containerStats
+	err    error
+}
+
+// updateLimitsMsg applies new limits, once confirmed when the memory
+// limit is below what the container uses
+type updateLimitsMsg struct {
+	container Container
+	args      []string
+	summary   string
+}
+
+// editLimits opens the editor on c and reads its limits and usage
+func (m *Model) editLimits(c Container) tea.Cmd {
+	if c.State != "running" {
+		m.output = c.Name + " isn't running; limits are changed on running containers"
+		return nil
+	}
+	m.limits = &limits{container: c, loading: true}
+	return func() tea.Msg {
+		msg := limitsMsg{id: c.ID}
+		raw, err := m.runner.Output(exec.Command("docker", "inspect", "--format", "{{json .HostConfig}}", c.ID))
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		var host hostConfig
+		if err := json.Unmarshal(raw, &host); err != nil {
+			msg.err = fmt.Errorf("unexpected docker inspect output: %w", err)
+			return msg
+		}
+		msg.cpus = float64(host.NanoCpus) / 1e9
+		msg.memory = host.Memory
+
+		// Usage is context only; the editor works without it
+		if raw, err := m.runner.Output(exec.Command("docker", "stats", "--no-stream", "--format", "{{json .}}", c.ID)); err == nil {
+			_ = json.Unmarshal(raw, &msg.stats)
+		}
+		return msg
+	}
+}
+
+// updateLimits handles the editor's messages and keys
+func (m *Model) updateLimits(msg tea.Msg) tea.Cmd {
+	l := m.limits
+	switch msg := msg.(type) {
+	case limitsMsg:
+		if msg.id != l.container.ID {
+			return nil
+		}
+		l.loading, l.err = false, msg.err
+		l.cpus, l.memory, l.stats = msg.cpus, msg.memory, msg.stats
+		if l.cpus > 0 {
+			l.fields[0].SetValue(strconv.FormatFloat(l.cpus, 'f', -1, 64))
+		}
+		if l.memory > 0 {
+			l.fields[1].SetValue(formatMemoryLimit(l.memory))
+		}
+
+	case events.Nav:
+		switch msg {
+		case events.NavUp, events.NavDown:
+			l.field = 1 - l.field
+		default:
+			l.fields[l.field].Nav(msg)
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.limits = nil
+			return nil
+		case "tab", "shift+tab":
+			l.field = 1 - l.field
+			return nil
+		case "enter":
+			if !l.loading && l.err == nil {
+				return m.applyLimits()
+			}
+			return nil
+		}
+		if !l.loading {
+			l.fields[l.field].HandleKey(msg)
+			l.message = ""
+		}
+	}
+	return nil
+}
+
+// applyLimits turns the edited fields into a docker update, asking first
+// when the new memory limit is below the container's current usage
+func (m *Model) applyLimits() tea.Cmd {
+	l := m.limits
+	args := []string{"update"}
+	var changes []string
+
+	cpuText := l.fields[0].Value()
+	cpus := 0.0
+	if cpuText != "" {
+		var err error
+		if cpus, err = strconv.ParseFloat(cpuText, 64); err != nil || cpus < 0 {
+			l.message = fmt.Sprintf("%q isn't a number of CPUs, e.g. 1.5", cpuText)
+			return nil
+		}
+	}
+	if cpus != l.cpus {
+		args = append(args, "--cpus", strconv.FormatFloat(cpus, 'f', -1, 64))
+		changes = append(changes, formatCPUs(cpus))
+	}
+
+	memText := l.fields[1].Value()
+	var memory int64
+	switch {
+	case memText == "" && l.memory > 0:
+		l.message = "Docker can't remove a memory limit from a running container; set a higher one instead"
+		return nil
+	case memText != "" && memText != formatMemoryLimit(l.memory):
+		var err error
+		if memory, err = parseMemory(memText); err != nil {
+			l.message = fmt.Sprintf("%q isn't a memory size, e.g. 512m or 2g", memText)
+			return nil
+		}
+		if memory < minMemory {
+			l.message = "Docker needs at least 6m of memory"
+			return nil
+		}
+		// Unlimited swap, so a lower limit isn't refused for being below
+		// an earlier swap limit
+		args = append(args, "--memory", strconv.FormatInt(memory, 10), "--memory-swap", "-1")
+		changes = append(changes, formatMemoryLimit(memory)+" memory")
+	}
+
+	if len(changes) == 0 {
+		l.message = "Nothing changed"
+		return nil
+	}
+	update := updateLimitsMsg{
+		container: l.container,
+		args:      append(args, l.container.ID),
+		summary:   strings.Join(changes, ", "),
+	}
+
+	if used, err := usedMemory(l.stats.MemUsage); err == nil && memory > 0 && memory < used {
+		return dialog.Confirm(dialog.Request{
+			Title:        "Limit " + l.container.Name + " below its usage?",
+			Detail:       fmt.Sprintf("It uses %s now. With a %s limit it has to shed memory at once, and it's killed if it can't.", strings.TrimSpace(strings.Split(l.stats.MemUsage, "/")[0]), formatMemoryLimit(memory)),
+			ConfirmLabel: "Apply",
+			Destructive:  true,
+			OnConfirm:    update,
+		})
+	}
+	return m.runUpdate(update)
+}
+
+// runUpdate runs docker update and closes the editor
+func (m *Model) runUpdate(update updateLimitsMsg) tea.Cmd {
+	m.limits = nil
+	m.runningCmd = true
+	c := update.container
+	return func() tea.Msg {
+		if out, err := m.runner.CombinedOutput(exec.Command("docker", update.args...)); err != nil {
+			return actionMsg{id: c.ID, note: fmt.Sprintf("Error: %v: %s", err, strings.TrimSpace(string(out))), toast: true, failed: true}
+		}
+		return actionMsg{id: c.ID, note: fmt.Sprintf("Limited %s to %s", c.Name, update.summary), toast: true}
+	}
+}
+
+// renderLimits is the editor, with current usage and limits for context
+func (m *Model) renderLimits() string {
+	l := m.limits
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🐳 DOCKER › LIMITS  ") +
+		lipgloss.NewStyle().Bold(true).Render(l.container.Name)
+	label := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true).Width(16)
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[Tab/↑/↓] Switch field  [Enter] Apply  [Esc] Back")
+
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	switch {
+	case l.loading:
+		b.WriteString(muted.Render("Reading limits and usage...") + "\n")
+		return b.String()
+	case l.err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorError).Render("Error: "+l.err.Error()) + "\n\n")
+		b.WriteString(help)
+		return b.String()
+	}
+
+	usage := "not available"
+	if l.stats.MemUsage != "" {
+		usage = fmt.Sprintf("CPU %s · Memory %s (%s)", l.stats.CPUPerc, l.stats.MemUsage, l.stats.MemPerc)
+	}
+	memory := "none"
+	if l.memory > 0 {
+		memory = formatMemoryLimit(l.memory)
+	}
+	b.WriteString(label.Render("Usage now") + usage + "\n")
+	b.WriteString(label.Render("Limits now") + fmt.Sprintf("%s · memory %s", formatCPUs(l.cpus), memory) + "\n\n")
+
+	hints := [2]string{"e.g. 1.5; empty for no limit", "e.g. 512m or 2g"}
+	for i, name := range []string{"CPUs", "Memory"} {
+		marker, style := "  ", lipgloss.NewStyle()
+		if i == l.field {
+			marker, style = "▶ ", style.Foreground(components.ColorPrimary).Bold(true)
+		}
+		value := l.fields[i].Value()
+		if i == l.field {
+			value = l.fields[i].View(16)
+		}
+		b.WriteString(style.Render(marker+fmt.Sprintf("%-8s", name)) + lipgloss.NewStyle().Width(18).Render(value) + muted.Render(hints[i]) + "\n")
+	}
+	b.WriteString("\n")
+	if l.message != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorWarning).Render(l.message) + "\n\n")
+	}
+	b.WriteString(help)
+	return b.String()
+}
+
+var memoryPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([kmgt]?)(i?b)?$`)
+
+// parseMemory reads a size the way docker does, e.g. 512m, 2g or 1.5GiB;
+// a bare number is bytes
+func parseMemory(s string) (int64, error) {
+	match := memoryPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	exp := strings.Index(" kmgt", match[2]) // "" is at 0
+	return int64(value * math.Pow(1024, float64(exp))), nil
+}
+
+// usedMemory is the usage half of docker stats' "45.3MiB / 7.67GiB"
+func usedMemory(memUsage string) (int64, error) {
+	used, _, _ := strings.Cut(memUsage, "/")
+	return parseMemory(used)
+}
+
+// formatMemoryLimit formats a limit the way it's typed, e.g. 512m or 2g
+func formatMemoryLimit(bytes int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}} {
+		if bytes >= unit.size && bytes%unit.size == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit.size, unit.suffix)
+		}
+	}
+	if bytes >= 1<<20 {
+		return fmt.Sprintf("%dm", bytes>>20)
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+func formatCPUs(cpus float64) string {
+	if cpus == 0 {
+		return "no CPU limit"
+	}
+	return strconv.FormatFloat(cpus, 'f', -1, 64) + " CPUs"
+}
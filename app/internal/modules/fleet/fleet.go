@@ -0,0 +1,324 @@
+// Package fleet is the Fleet tab: a health summary of other Macs checked
+// over SSH, with the details of one Mac a key away.
+package fleet
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Model represents the Fleet module state
+type Model struct {
+	config   *config.Config
+	runner   runner.Runner
+	width    int
+	height   int
+	hosts    []config.FleetHost
+	health   []Health // by host; zero CheckedAt until the first check
+	cursor   int
+	detail   bool // showing the selected host
+	checking bool
+	table    components.Table
+}
+
+// New creates the Fleet module for the hosts in cfg
+func New(cfg *config.Config) *Model {
+	m := &Model{
+		config: cfg,
+		runner: runner.Default,
+		table: components.NewTable(
+			components.Column{Title: "HOST", Width: 18},
+			components.Column{Title: "DISK", Width: 5, Align: lipgloss.Right},
+			components.Column{Title: "UPDATES", Width: 7, Align: lipgloss.Right},
+			components.Column{Title: "UPTIME", Width: 9},
+			components.Column{Title: "BACKUP", Width: 10},
+			components.Column{Title: "STATUS"},
+		),
+	}
+	if cfg != nil {
+		m.hosts = cfg.Fleet.Hosts
+	}
+	m.health = make([]Health, len(m.hosts))
+	return m
+}
+
+// Init initializes the module. Checks are run by the scheduler.
+func (m *Model) Init() tea.Cmd { return nil }
+
+// Schedule checks every host every fleet.interval minutes while the tab
+// is shown
+func (m *Model) Schedule() []scheduler.Task {
+	every := 5 * time.Minute
+	if m.config != nil && m.config.Fleet.Interval > 0 {
+		every = time.Duration(m.config.Fleet.Interval) * time.Minute
+	}
+	return []scheduler.Task{{Name: "check", Every: every, Run: m.checkAll}}
+}
+
+type fleetMsg struct{ health []Health }
+
+type hostMsg struct {
+	index  int
+	health Health
+}
+
+// checkAll probes the hosts in parallel
+func (m *Model) checkAll() tea.Cmd {
+	if m.checking || len(m.hosts) == 0 {
+		return nil
+	}
+	m.checking = true
+	r, hosts := m.runner, m.hosts
+	return func() tea.Msg {
+		health := make([]Health, len(hosts))
+		now := time.Now()
+		var wg sync.WaitGroup
+		for i, host := range hosts {
+			wg.Add(1)
+			go func(i int, host config.FleetHost) {
+				defer wg.Done()
+				health[i] = probe(r, host, now)
+			}(i, host)
+		}
+		wg.Wait()
+		return fleetMsg{health: health}
+	}
+}
+
+// checkHost probes the selected host again
+func (m *Model) checkHost() tea.Cmd {
+	if m.checking || m.cursor >= len(m.hosts) {
+		return nil
+	}
+	m.checking = true
+	r, index, host := m.runner, m.cursor, m.hosts[m.cursor]
+	return func() tea.Msg {
+		return hostMsg{index: index, health: probe(r, host, time.Now())}
+	}
+}
+
+// Update handles messages
+func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case events.Nav:
+		if !m.detail {
+			m.cursor = msg.Move(m.cursor, len(m.hosts))
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			m.detail = len(m.hosts) > 0
+		case "esc", "backspace", "q":
+			m.detail = false
+		case "r":
+			if m.detail {
+				return m, m.checkHost()
+			}
+			return m, m.checkAll()
+		}
+
+	case fleetMsg:
+		m.checking = false
+		m.health = msg.health
+
+	case hostMsg:
+		m.checking = false
+		if msg.index < len(m.health) {
+			m.health[msg.index] = msg.health
+		}
+	}
+	return m, nil
+}
+
+// View renders the module
+func (m *Model) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+	if m.detail && m.cursor < len(m.hosts) {
+		return m.renderDetail()
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🖥  FLEET")
+	hint := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[r] Check now  [Enter] Details  [y] Copy ssh command")
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	var b strings.Builder
+	b.WriteString(title + "\n\n" + hint + "\n\n")
+	if len(m.hosts) == 0 {
+		b.WriteString(muted.Render("No hosts. Add Macs under fleet.hosts in config.yaml."))
+		return b.String()
+	}
+	if m.checking {
+		b.WriteString(muted.Render(fmt.Sprintf("Checking %d host(s) over SSH...", len(m.hosts))) + "\n\n")
+	}
+
+	headerStyle := lipgloss.NewStyle().PaddingLeft(4).Bold(true).Foreground(components.ColorPrimary)
+	item := lipgloss.NewStyle().PaddingLeft(2)
+	sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
+
+	b.WriteString(headerStyle.Render(m.table.Header()) + "\n")
+	for i, host := range m.hosts {
+		cells, status := hostCells(host, m.health[i])
+		line := m.table.Row(append(cells, status)...)
+		if i == m.cursor {
+			b.WriteString(sel.Render("▶ " + line))
+		} else {
+			b.WriteString(item.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+
+	return lipgloss.NewStyle().MaxHeight(max(m.height-4, 10)).Render(b.String())
+}
+
+// hostCells are a host's cells in the table and its colored status
+func hostCells(host config.FleetHost, h Health) ([]string, string) {
+	name := host.Name
+	if name == "" {
+		name = host.SSH
+	}
+	switch {
+	case h.CheckedAt.IsZero():
+		return []string{name, "", "", "", ""}, lipgloss.NewStyle().Foreground(components.ColorMuted).Render("not checked yet")
+	case h.Err != nil:
+		return []string{name, "", "", "", ""}, lipgloss.NewStyle().Foreground(components.ColorError).Render("✗ " + h.Err.Error())
+	}
+
+	cells := []string{name, fmt.Sprintf("%d%%", h.Disk), fmt.Sprint(h.Updates), formatUptime(h.Uptime), backupAge(h)}
+	if warnings := h.Warnings(h.CheckedAt); len(warnings) > 0 {
+		return cells, lipgloss.NewStyle().Foreground(components.ColorWarning).Render("⚠ " + strings.Join(warnings, ", "))
+	}
+	return cells, lipgloss.NewStyle().Foreground(components.ColorSuccess).Render("✓ healthy")
+}
+
+// renderDetail shows everything the last check found on the selected host
+func (m *Model) renderDetail() string {
+	host, h := m.hosts[m.cursor], m.health[m.cursor]
+	name := host.Name
+	if name == "" {
+		name = host.SSH
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🖥  " + name)
+	label := lipgloss.NewStyle().Foreground(components.ColorSubtle).Width(10)
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	var b strings.Builder
+	b.WriteString(title + "  " + muted.Render(host.SSH) + "\n\n")
+	switch {
+	case m.checking:
+		b.WriteString(muted.Render("Checking over SSH...") + "\n\n")
+	case h.CheckedAt.IsZero():
+		b.WriteString(muted.Render("Not checked yet. Press r to check now.") + "\n\n")
+	case h.Err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorError).Render("✗ "+h.Err.Error()) + "\n\n")
+	}
+
+	if !h.CheckedAt.IsZero() && h.Err == nil {
+		b.WriteString(muted.Render(fmt.Sprintf("macOS %s · up %s · checked %s", h.OS, formatUptime(h.Uptime), h.CheckedAt.Format("15:04"))) + "\n\n")
+		rows := [][2]string{
+			{"CPU", h.CPU},
+			{"Load", h.Load},
+			{"Memory", h.MemoryFree + " free"},
+			{"Disk", components.NewBaseStyles().ProgressBar(float64(h.Disk), 20) + fmt.Sprintf(" %d%% used", h.Disk)},
+			{"Updates", fmt.Sprintf("%d pending", h.Updates)},
+			{"Backup", backupDetail(h)},
+		}
+		for _, row := range rows {
+			b.WriteString(label.Render(row[0]) + row[1] + "\n")
+		}
+		if warnings := h.Warnings(h.CheckedAt); len(warnings) > 0 {
+			b.WriteString("\n" + lipgloss.NewStyle().Foreground(components.ColorWarning).Render("⚠ "+strings.Join(warnings, " · ")) + "\n")
+		}
+	}
+
+	b.WriteString("\n" + lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[Esc] Back  [r] Check again  [y] Copy ssh command"))
+	return b.String()
+}
+
+// formatUptime is e.g. "12d 4h" or "3h 12m"
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(d.Minutes())%60)
+}
+
+// backupAge is how long before the check the last backup finished
+func backupAge(h Health) string {
+	if h.LastBackup.IsZero() {
+		return "never"
+	}
+	age := h.CheckedAt.Sub(h.LastBackup)
+	switch {
+	case age < time.Hour:
+		return "just now"
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours())/24)
+	}
+}
+
+func backupDetail(h Health) string {
+	if h.LastBackup.IsZero() {
+		return "no Time Machine backup found"
+	}
+	return h.LastBackup.Format("2006-01-02 15:04") + " (" + backupAge(h) + ")"
+}
+
+// Title returns the module title
+func (m *Model) Title() string { return "Fleet" }
+
+// Help describes the Fleet keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "FLEET",
+		Description: "Disk, pending updates, uptime and backup age of the Macs under fleet.hosts, checked over SSH.",
+		Sections: []help.Section{{Title: "Keys", Bindings: []help.Binding{
+			{Key: "↑/↓", Desc: "Select a host"},
+			{Key: "Enter", Desc: "Show its CPU, load, memory and the rest of the last check"},
+			{Key: "Esc", Desc: "Back to the summary"},
+			{Key: "R", Desc: "Check all hosts now, or the one shown"},
+			{Key: "y", Desc: "Copy the ssh command for the selected host"},
+		}}},
+	}
+}
+
+// HasOpenModal keeps Esc for closing the host details
+func (m *Model) HasOpenModal() bool { return m.detail }
+
+// Copyable returns the ssh command for the selected host
+func (m *Model) Copyable() string {
+	if m.cursor < len(m.hosts) {
+		return "ssh " + m.hosts[m.cursor].SSH
+	}
+	return ""
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Check fleet now", Hint: "Check every host under fleet.hosts over SSH", Msg: palette.Key("r")},
+	}
+}
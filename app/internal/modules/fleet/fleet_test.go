@@ -0,0 +1,103 @@
+package fleet
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var hosts = []config.FleetHost{
+	{Name: "Mac mini", SSH: "admin@mini.local"},
+	{Name: "Studio", SSH: "studio"},
+}
+
+func sshCmdline(dest string) string {
+	return "ssh -o BatchMode=yes -o ConnectTimeout=5 -o ServerAliveInterval=5 -o ServerAliveCountMax=3 " + dest + " " + probeScript
+}
+
+func TestFleetSummaryAndDetail(t *testing.T) {
+	fake := runner.NewFake().
+		Set(sshCmdline("studio"), "ssh: connect to host studio port 22: Operation timed out\n", errors.New("exit status 255"))
+	if err := fake.SetFixture(sshCmdline("admin@mini.local"), filepath.Join("testdata", "probe.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(&config.Config{Fleet: config.FleetConfig{Hosts: hosts}})
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	cmd := m.checkAll()
+	if cmd == nil || m.checkAll() != nil {
+		t.Fatal("a check should start once and not overlap")
+	}
+	msg := cmd().(fleetMsg)
+	// Pin the check time so uptime and backup age are stable
+	checked := time.Unix(1719396000, 0).Add(5*24*time.Hour + 3*time.Hour)
+	data, err := os.ReadFile(filepath.Join("testdata", "probe.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mini := parseProbe(string(data), checked)
+	mini.CheckedAt = checked
+	msg.health[0] = mini
+	m.Update(msg)
+
+	view := m.View()
+	for _, want := range []string{"Mac mini", "96%", "5d 3h", "d ago", "⚠ disk almost full, updates pending, backup out of date", "✗ ssh: connect to host studio port 22: Operation timed out"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("summary lacks %q:\n%s", want, view)
+		}
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.HasOpenModal() {
+		t.Fatal("Enter should open the host's details")
+	}
+	detail := m.View()
+	for _, want := range []string{"macOS 14.5", "1.52 1.61 1.70", "63% free", "5.26% user", "2 pending"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail lacks %q:\n%s", want, detail)
+		}
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m.Update(events.NavDown)
+	if m.HasOpenModal() || m.Copyable() != "ssh studio" {
+		t.Errorf("Esc should close the details; copyable = %q", m.Copyable())
+	}
+}
+
+// hangingFake answers each command only after delay, like a host that
+// stopped responding
+type hangingFake struct {
+	*runner.Fake
+	delay time.Duration
+}
+
+func (f *hangingFake) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	time.Sleep(f.delay)
+	return f.Fake.CombinedOutput(cmd)
+}
+
+func TestProbeGivesUp(t *testing.T) {
+	defer func(timeout time.Duration) { probeTimeout = timeout }(probeTimeout)
+	probeTimeout = 20 * time.Millisecond
+	fake := &hangingFake{Fake: runner.NewFake().Set(sshCmdline("studio"), "", nil), delay: 60 * time.Millisecond}
+
+	m := New(&config.Config{Fleet: config.FleetConfig{Hosts: hosts[1:]}})
+	m.runner = fake
+	m.Update(m.checkAll()())
+	if m.checking {
+		t.Error("a check past its deadline should still finish")
+	}
+	if err := m.health[0].Err; err == nil || !strings.Contains(err.Error(), "no answer within") {
+		t.Errorf("health = %+v, want a timeout", m.health[0])
+	}
+}
@@ -0,0 +1,130 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// probeScript prints one key=value line per indicator. It runs in the
+// remote login shell, so it sticks to tools every Mac has.
+const probeScript = `echo "os=$(sw_vers -productVersion)"
+echo "boot=$(sysctl -n kern.boottime)"
+echo "disk=$(df -k / | awk 'NR==2 {print $5}')"
+echo "load=$(sysctl -n vm.loadavg)"
+echo "memfree=$(memory_pressure -Q | awk -F': ' '/percentage/ {print $2}')"
+echo "cpu=$(top -l 1 -n 0 | awk -F': ' '/^CPU usage/ {print $2}')"
+echo "backup=$(tmutil latestbackup 2>/dev/null | tail -1)"
+echo "updates=$(softwareupdate -l 2>&1 | grep -c 'Label:')"`
+
+// Health is what a probe found on one Mac
+type Health struct {
+	OS         string
+	Uptime     time.Duration
+	Disk       int // percent used
+	Updates    int
+	LastBackup time.Time // zero without Time Machine backups
+	Load       string    // 1, 5 and 15 minute load averages
+	MemoryFree string
+	CPU        string
+	CheckedAt  time.Time
+	Err        error
+}
+
+// staleBackup is how old the last backup may get before a host is flagged
+const staleBackup = 7 * 24 * time.Hour
+
+// Warnings lists what needs attention on the host
+func (h Health) Warnings(now time.Time) []string {
+	var warnings []string
+	if h.Disk >= 90 {
+		warnings = append(warnings, "disk almost full")
+	}
+	if h.Updates > 0 {
+		warnings = append(warnings, "updates pending")
+	}
+	if h.LastBackup.IsZero() {
+		warnings = append(warnings, "no backup")
+	} else if now.Sub(h.LastBackup) > staleBackup {
+		warnings = append(warnings, "backup out of date")
+	}
+	return warnings
+}
+
+// probeTimeout is how long a probe may take in all; softwareupdate alone
+// can take most of a minute
+var probeTimeout = 90 * time.Second
+
+// probe runs probeScript on host over SSH. BatchMode makes ssh fail
+// instead of prompting for a password the TUI can't show, and the
+// keepalives notice a host that stops answering mid-probe.
+func probe(r runner.Runner, host config.FleetHost, now time.Time) Health {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "ssh", "-o", "BatchMode=yes", "-o", "ConnectTimeout=5",
+		"-o", "ServerAliveInterval=5", "-o", "ServerAliveCountMax=3", host.SSH, probeScript)
+	out, err := r.CombinedOutput(cmd)
+	if ctx.Err() == context.DeadlineExceeded {
+		return Health{CheckedAt: now, Err: fmt.Errorf("no answer within %v", probeTimeout)}
+	}
+	if err != nil {
+		line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		if line == "" {
+			line = err.Error()
+		}
+		return Health{CheckedAt: now, Err: errors.New(line)}
+	}
+	health := parseProbe(string(out), now)
+	health.CheckedAt = now
+	return health
+}
+
+var (
+	bootPattern   = regexp.MustCompile(`sec = (\d+)`)
+	backupPattern = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}-\d{6})`)
+)
+
+// parseProbe reads probeScript's output
+func parseProbe(out string, now time.Time) Health {
+	var h Health
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "os":
+			h.OS = value
+		case "boot":
+			if m := bootPattern.FindStringSubmatch(value); m != nil {
+				sec, _ := strconv.ParseInt(m[1], 10, 64)
+				h.Uptime = now.Sub(time.Unix(sec, 0)).Truncate(time.Minute)
+			}
+		case "disk":
+			h.Disk, _ = strconv.Atoi(strings.TrimSuffix(value, "%"))
+		case "load":
+			h.Load = strings.TrimSpace(strings.Trim(value, "{}"))
+		case "memfree":
+			h.MemoryFree = value
+		case "cpu":
+			h.CPU = value
+		case "backup":
+			// Backups are named by their local time, e.g. 2024-07-01-103000
+			if m := backupPattern.FindStringSubmatch(value); m != nil {
+				h.LastBackup, _ = time.ParseInLocation("2006-01-02-150405", m[1], time.Local)
+			}
+		case "updates":
+			h.Updates, _ = strconv.Atoi(value)
+		}
+	}
+	return h
+}
@@ -0,0 +1,196 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxTargets is how many targets one diagnostic runs against at once
+const maxTargets = 6
+
+// diagColumnWidth is the narrowest a side-by-side result may be before
+// results are stacked instead
+const diagColumnWidth = 32
+
+// diagResult is the outcome of a diagnostic against one target
+type diagResult struct {
+	target string
+	output string
+	err    error
+	done   bool
+}
+
+// parseTargets splits input on commas and spaces and expands @name to the
+// targets listed under modules.network.target_lists, dropping repeats
+func parseTargets(input string, lists map[string][]string) ([]string, error) {
+	var targets []string
+	seen := map[string]bool{}
+	add := func(target string) error {
+		if !isValidTarget(target) {
+			return fmt.Errorf("invalid target %q: use a domain name or IP address", target)
+		}
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+		return nil
+	}
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+	for _, field := range fields {
+		name, isList := strings.CutPrefix(field, "@")
+		if !isList {
+			if err := add(field); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		list, ok := lists[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("no target list %q in modules.network.target_lists", name)
+		}
+		for _, target := range list {
+			if err := add(target); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no targets: use a domain name or IP address")
+	}
+	if len(targets) > maxTargets {
+		return nil, fmt.Errorf("%d targets; at most %d run at once", len(targets), maxTargets)
+	}
+	return targets, nil
+}
+
+// targetLists returns the configured lists of diagnostics targets
+func (m *Model) targetLists() map[string][]string {
+	if m.config == nil {
+		return nil
+	}
+	return m.config.Modules.Network.TargetLists
+}
+
+// runDiagnostic runs the current diagnostic against every target at once;
+// each result arrives on its own and is shown as soon as it does
+func (m *Model) runDiagnostic(c tools.Capability, targets []string) tea.Cmd {
+	m.diagRun++
+	m.diagRunning = true
+	m.diagOutput = ""
+	m.diagResults = make([]diagResult, len(targets))
+	cmds := make([]tea.Cmd, len(targets))
+	for i, target := range targets {
+		m.diagResults[i].target = target
+		var cmd tea.Cmd
+		switch m.diagMode {
+		case DiagPing:
+			cmd = m.executePing(c, target)
+		case DiagTraceroute:
+			cmd = m.executeTraceroute(c, target)
+		case DiagDNS:
+			cmd = m.executeDNS(c, target)
+		}
+		run, index := m.diagRun, i
+		cmds[i] = func() tea.Msg {
+			msg := cmd().(diagCompleteMsg)
+			msg.run, msg.index = run, index
+			return msg
+		}
+	}
+	if len(cmds) == 1 {
+		return cmds[0]
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateDiagnostic records one target's result, ignoring results of a run
+// that has since been replaced
+func (m *Model) updateDiagnostic(msg diagCompleteMsg) {
+	if msg.run != m.diagRun || msg.index >= len(m.diagResults) {
+		return
+	}
+	m.diagResults[msg.index] = diagResult{target: msg.target, output: msg.output, err: msg.err, done: true}
+
+	m.diagRunning = false
+	for _, result := range m.diagResults {
+		if !result.done {
+			m.diagRunning = true
+		}
+	}
+	if len(m.diagResults) == 1 {
+		m.diagView.GotoTop()
+		m.diagOutput = m.diagResults[0].text()
+	}
+}
+
+// text is the result's output, or its error
+func (r diagResult) text() string {
+	if r.err != nil {
+		return fmt.Sprintf("Error: %v", r.err)
+	}
+	return r.output
+}
+
+// renderDiagResults lays the results of a multi-target run side by side
+// within width, or one under the other when the columns would be too
+// narrow to read
+func (m *Model) renderDiagResults(width int) string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	outputStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+
+	n := len(m.diagResults)
+	gap := 2
+	column := (width - gap*(n-1)) / n
+	sideBySide := column >= diagColumnWidth
+	if !sideBySide {
+		column = width
+	}
+
+	blocks := make([]string, n)
+	for i, result := range m.diagResults {
+		status := lipgloss.NewStyle().Foreground(components.ColorMuted).Render(" ⏳")
+		switch {
+		case result.err != nil:
+			status = lipgloss.NewStyle().Foreground(components.ColorError).Render(" ✗")
+		case result.done:
+			status = lipgloss.NewStyle().Foreground(components.ColorSuccess).Render(" ✓")
+		}
+		lines := []string{headerStyle.Render(components.TruncateString(result.target, column-2)) + status}
+		if result.done {
+			for _, line := range strings.Split(strings.TrimRight(result.text(), "\n"), "\n") {
+				lines = append(lines, outputStyle.Render(components.TruncateString(line, column)))
+			}
+		}
+		blocks[i] = lipgloss.NewStyle().Width(column).Render(strings.Join(lines, "\n"))
+	}
+
+	if !sideBySide {
+		return strings.Join(blocks, "\n\n")
+	}
+	spacer := strings.Repeat(" ", gap)
+	row := make([]string, 0, 2*n-1)
+	for i, block := range blocks {
+		if i > 0 {
+			row = append(row, spacer)
+		}
+		row = append(row, block)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, row...)
+}
+
+// diagCopy is every result of a multi-target run, each under its target
+func (m *Model) diagCopy() string {
+	var b strings.Builder
+	for _, result := range m.diagResults {
+		if !result.done {
+			continue
+		}
+		fmt.Fprintf(&b, "== %s ==\n%s\n\n", result.target, strings.TrimSpace(result.text()))
+	}
+	return strings.TrimSpace(b.String())
+}
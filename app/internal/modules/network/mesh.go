@@ -0,0 +1,473 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/clipboard"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Mesh VPN command line tools. The Mac App Store build of Tailscale only
+// ships its CLI inside the app bundle.
+var (
+	tailscaleFeature = tools.Feature{
+		Name:  "Tailscale",
+		Tools: []tools.Tool{{Name: "tailscale", Paths: []string{"/Applications/Tailscale.app/Contents/MacOS/Tailscale"}}},
+		Hint:  "install it from tailscale.com",
+	}
+	zerotierFeature = tools.Feature{
+		Name:  "ZeroTier",
+		Tools: []tools.Tool{{Name: "zerotier-cli", Paths: []string{"/usr/local/bin/zerotier-cli", "/Library/Application Support/ZeroTier/One/zerotier-cli"}}},
+		Hint:  "install it from zerotier.com",
+	}
+)
+
+// MeshStatus is the state of one mesh VPN on this Mac
+type MeshStatus struct {
+	Provider  string // "Tailscale" or "ZeroTier"
+	Connected bool
+	State     string // as the provider reports it, e.g. "Running" or "NeedsLogin"
+	Network   string // tailnet or joined network names
+	Self      string // this machine's name on the mesh
+	IPs       []string
+	ExitNode  string // peer routing this Mac's internet traffic, "" when none
+	Peers     []MeshPeer
+	Error     string // set when the status could not be read
+}
+
+// MeshPeer is another machine on the mesh
+type MeshPeer struct {
+	Name     string
+	IPs      []string
+	OS       string
+	Online   bool
+	ExitNode bool // this Mac routes its traffic through the peer
+}
+
+// PeersOnline counts the peers currently reachable
+func (s MeshStatus) PeersOnline() int {
+	online := 0
+	for _, p := range s.Peers {
+		if p.Online {
+			online++
+		}
+	}
+	return online
+}
+
+type meshMsg struct {
+	statuses []MeshStatus
+}
+
+type meshActionMsg struct {
+	note string
+	err  error
+}
+
+// copyMeshIPMsg copies this Mac's mesh IP; the palette sends it
+type copyMeshIPMsg struct{}
+
+// scanMesh reads the status of every installed mesh VPN
+func (m *Model) scanMesh() tea.Cmd {
+	m.meshLoading = true
+	tailscale := m.capability(tailscaleFeature)
+	zerotier := m.capability(zerotierFeature)
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var statuses []MeshStatus
+		if tailscale.Available() {
+			// status exits non-zero while logged out but still prints JSON
+			out, err := m.runner.Output(exec.CommandContext(ctx, tailscale.Path, "status", "--json"))
+			status, parseErr := parseTailscaleStatus(out)
+			if parseErr != nil {
+				status = MeshStatus{Provider: "Tailscale", Error: firstError(err, parseErr).Error()}
+			}
+			statuses = append(statuses, status)
+		}
+		if zerotier.Available() {
+			statuses = append(statuses, m.zerotierStatus(ctx, zerotier.Path))
+		}
+		return meshMsg{statuses: statuses}
+	}
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tailscaleStatus is the subset of `tailscale status --json` we show
+type tailscaleStatus struct {
+	BackendState   string
+	Self           *tailscalePeer
+	Peer           map[string]*tailscalePeer
+	CurrentTailnet *struct{ Name string }
+}
+
+type tailscalePeer struct {
+	HostName     string
+	DNSName      string
+	OS           string
+	TailscaleIPs []string
+	Online       bool
+	ExitNode     bool
+}
+
+func (p *tailscalePeer) name() string {
+	if name, _, _ := strings.Cut(p.DNSName, "."); name != "" {
+		return name
+	}
+	return p.HostName
+}
+
+// parseTailscaleStatus reads `tailscale status --json`
+func parseTailscaleStatus(data []byte) (MeshStatus, error) {
+	var raw tailscaleStatus
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return MeshStatus{}, fmt.Errorf("failed to parse tailscale status: %w", err)
+	}
+
+	status := MeshStatus{Provider: "Tailscale", State: raw.BackendState, Connected: raw.BackendState == "Running"}
+	if raw.CurrentTailnet != nil {
+		status.Network = raw.CurrentTailnet.Name
+	}
+	if raw.Self != nil {
+		status.Self = raw.Self.name()
+		status.IPs = raw.Self.TailscaleIPs
+	}
+	for _, p := range raw.Peer {
+		peer := MeshPeer{Name: p.name(), IPs: p.TailscaleIPs, OS: p.OS, Online: p.Online, ExitNode: p.ExitNode}
+		if peer.ExitNode {
+			status.ExitNode = peer.Name
+		}
+		status.Peers = append(status.Peers, peer)
+	}
+	sortPeers(status.Peers)
+	return status, nil
+}
+
+// sortPeers lists online peers first, then by name
+func sortPeers(peers []MeshPeer) {
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].Online != peers[j].Online {
+			return peers[i].Online
+		}
+		return peers[i].Name < peers[j].Name
+	})
+}
+
+// zerotierStatus reads the node, its networks and its peers. zerotier-cli
+// needs root for its auth token on some installs, so errors are shown
+// rather than hiding ZeroTier.
+func (m *Model) zerotierStatus(ctx context.Context, cli string) MeshStatus {
+	status := MeshStatus{Provider: "ZeroTier"}
+
+	out, err := m.runner.Output(exec.CommandContext(ctx, cli, "-j", "info"))
+	if err != nil {
+		status.Error = fmt.Sprintf("zerotier-cli info failed: %v", err)
+		return status
+	}
+	var info struct {
+		Address string `json:"address"`
+		Online  bool   `json:"online"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		status.Error = fmt.Sprintf("failed to parse zerotier info: %v", err)
+		return status
+	}
+	status.Self = info.Address
+	status.Connected = info.Online
+	status.State = "OFFLINE"
+	if info.Online {
+		status.State = "ONLINE"
+	}
+
+	if out, err := m.runner.Output(exec.CommandContext(ctx, cli, "-j", "listnetworks")); err == nil {
+		names, ips, err := parseZeroTierNetworks(out)
+		if err != nil {
+			logger.Warn("Failed to parse ZeroTier networks: %v", err)
+		}
+		status.Network = strings.Join(names, ", ")
+		status.IPs = ips
+	}
+	if out, err := m.runner.Output(exec.CommandContext(ctx, cli, "-j", "listpeers")); err == nil {
+		peers, err := parseZeroTierPeers(out)
+		if err != nil {
+			logger.Warn("Failed to parse ZeroTier peers: %v", err)
+		}
+		status.Peers = peers
+	}
+	return status
+}
+
+// parseZeroTierNetworks returns the joined network names and this node's
+// addresses on them, without prefix lengths
+func parseZeroTierNetworks(data []byte) (names, ips []string, err error) {
+	var networks []struct {
+		ID        string   `json:"nwid"`
+		Name      string   `json:"name"`
+		Status    string   `json:"status"`
+		Addresses []string `json:"assignedAddresses"`
+	}
+	if err := json.Unmarshal(data, &networks); err != nil {
+		return nil, nil, err
+	}
+	for _, n := range networks {
+		name := n.Name
+		if name == "" {
+			name = n.ID
+		}
+		names = append(names, name)
+		for _, addr := range n.Addresses {
+			ip, _, _ := strings.Cut(addr, "/")
+			ips = append(ips, ip)
+		}
+	}
+	return names, ips, nil
+}
+
+// parseZeroTierPeers returns the other nodes, skipping the planet and moon
+// root servers every node talks to
+func parseZeroTierPeers(data []byte) ([]MeshPeer, error) {
+	var raw []struct {
+		Address string `json:"address"`
+		Role    string `json:"role"`
+		Latency int    `json:"latency"`
+		Paths   []struct {
+			Active bool `json:"active"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var peers []MeshPeer
+	for _, p := range raw {
+		if p.Role != "LEAF" {
+			continue
+		}
+		online := false
+		for _, path := range p.Paths {
+			online = online || path.Active
+		}
+		peers = append(peers, MeshPeer{Name: p.Address, Online: online && p.Latency >= 0})
+	}
+	sortPeers(peers)
+	return peers, nil
+}
+
+// meshPeers lists every provider's peers in the order they're rendered
+func (m *Model) meshPeers() []MeshPeer {
+	var peers []MeshPeer
+	for _, s := range m.mesh {
+		peers = append(peers, s.Peers...)
+	}
+	return peers
+}
+
+// meshIP is this Mac's first mesh address, preferring a connected mesh
+func (m *Model) meshIP() string {
+	for _, connected := range []bool{true, false} {
+		for _, s := range m.mesh {
+			if s.Connected == connected && len(s.IPs) > 0 {
+				return s.IPs[0]
+			}
+		}
+	}
+	return ""
+}
+
+// tailscaleUp connects or disconnects Tailscale, then rescans
+func (m *Model) tailscaleUp(up bool) tea.Cmd {
+	tailscale := m.capability(tailscaleFeature)
+	if !tailscale.Available() {
+		m.meshMessage = "Connect and disconnect need Tailscale; use the ZeroTier app for ZeroTier"
+		return nil
+	}
+	command, verb := "up", "Connected"
+	if !up {
+		command, verb = "down", "Disconnected"
+	}
+	m.meshBusy = true
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		out, err := m.runner.CombinedOutput(exec.CommandContext(ctx, tailscale.Path, command))
+		if err != nil {
+			if detail := strings.TrimSpace(string(out)); detail != "" {
+				err = fmt.Errorf("%w: %s", err, detail)
+			}
+			return meshActionMsg{err: fmt.Errorf("tailscale %s failed: %w", command, err)}
+		}
+		return meshActionMsg{note: fmt.Sprintf("✓ %s Tailscale", verb)}
+	}
+}
+
+// copyMeshIP copies this Mac's mesh address
+func (m *Model) copyMeshIP() tea.Cmd {
+	ip := m.meshIP()
+	if ip == "" {
+		m.meshMessage = "No mesh IP to copy; connect Tailscale or join a ZeroTier network first"
+		return nil
+	}
+	if err := clipboard.Copy(m.Title(), ip); err != nil {
+		m.meshMessage = fmt.Sprintf("✗ %v", err)
+		return components.StatusToast(m.meshMessage)
+	}
+	m.meshMessage = fmt.Sprintf("✓ Copied %s", ip)
+	return components.StatusToast(m.meshMessage)
+}
+
+// handleMeshKeys handles keys on the Mesh view
+func (m *Model) handleMeshKeys(msg tea.KeyMsg) tea.Cmd {
+	if m.meshLoading || m.meshBusy {
+		return nil
+	}
+	switch msg.String() {
+	case "r":
+		m.meshMessage = ""
+		return m.scanMesh()
+	case "c":
+		return m.tailscaleUp(true)
+	case "d":
+		return m.tailscaleUp(false)
+	case "i":
+		return m.copyMeshIP()
+	}
+	return nil
+}
+
+// updateMesh applies mesh scan and action results
+func (m *Model) updateMesh(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case meshMsg:
+		m.meshLoading = false
+		m.meshScanned = true
+		m.mesh = msg.statuses
+		if m.meshCursor >= len(m.meshPeers()) {
+			m.meshCursor = 0
+		}
+	case meshActionMsg:
+		m.meshBusy = false
+		if msg.err != nil {
+			m.meshMessage = fmt.Sprintf("✗ %v", msg.err)
+			return components.StatusToast(m.meshMessage)
+		}
+		m.meshMessage = msg.note
+		return tea.Batch(m.scanMesh(), components.StatusToast(msg.note))
+	case copyMeshIPMsg:
+		m.activeView = ViewMesh
+		return m.copyMeshIP()
+	}
+	return nil
+}
+
+func (m *Model) renderMesh() string {
+	m.meshRows.Reset()
+	var b strings.Builder
+
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[C]onnect  [D]isconnect  [I] Copy my IP  [R]efresh  [1-7]Switch views")
+	b.WriteString(help + "\n\n")
+
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	okStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	warnStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	errStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+
+	switch {
+	case m.meshLoading && !m.meshScanned:
+		b.WriteString("⏳ Checking Tailscale and ZeroTier...\n")
+		return b.String()
+	case m.meshBusy:
+		b.WriteString("⏳ Updating Tailscale...\n\n")
+	}
+
+	if m.meshMessage != "" {
+		b.WriteString(mutedStyle.Render(m.meshMessage) + "\n\n")
+	}
+
+	if len(m.mesh) == 0 {
+		b.WriteString("No mesh VPN found.\n\n")
+		b.WriteString(m.renderUnavailable(tailscaleFeature, zerotierFeature))
+		return b.String()
+	}
+
+	row := 0
+	item := lipgloss.NewStyle().PaddingLeft(2)
+	sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
+	for _, s := range m.mesh {
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render(strings.ToUpper(s.Provider)) + "\n")
+		if s.Error != "" {
+			b.WriteString(errStyle.Render("  ✗ "+s.Error) + "\n\n")
+			continue
+		}
+
+		state := warnStyle.Render("○ " + s.State)
+		if s.Connected {
+			state = okStyle.Render("● " + s.State)
+		}
+		b.WriteString(fmt.Sprintf("  Status:    %s\n", state))
+		if s.Network != "" {
+			b.WriteString(fmt.Sprintf("  Network:   %s\n", s.Network))
+		}
+		if s.Self != "" {
+			b.WriteString(fmt.Sprintf("  This Mac:  %s\n", s.Self))
+		}
+		if len(s.IPs) > 0 {
+			b.WriteString(fmt.Sprintf("  IPs:       %s\n", strings.Join(s.IPs, ", ")))
+		}
+		if s.Provider == "Tailscale" {
+			exit := mutedStyle.Render("not in use")
+			if s.ExitNode != "" {
+				exit = okStyle.Render(s.ExitNode)
+			}
+			b.WriteString(fmt.Sprintf("  Exit node: %s\n", exit))
+		}
+		b.WriteString(fmt.Sprintf("  Peers:     %d of %d online\n\n", s.PeersOnline(), len(s.Peers)))
+
+		for _, peer := range s.Peers {
+			m.meshRows.Add(components.NextLine(b.String()), row)
+			mark := mutedStyle.Render("○")
+			if peer.Online {
+				mark = okStyle.Render("●")
+			}
+			ip := "—"
+			if len(peer.IPs) > 0 {
+				ip = peer.IPs[0]
+			}
+			var notes []string
+			if peer.OS != "" {
+				notes = append(notes, peer.OS)
+			}
+			if peer.ExitNode {
+				notes = append(notes, "exit node")
+			}
+			line := fmt.Sprintf("%-24s %-16s %s", components.TruncateString(peer.Name, 24), ip, strings.Join(notes, ", "))
+			if row == m.meshCursor {
+				b.WriteString(sel.Render("▶ "+mark+" "+line) + "\n")
+			} else {
+				b.WriteString(item.Render("  "+mark+" "+line) + "\n")
+			}
+			row++
+		}
+		m.meshRows.End(components.NextLine(b.String()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package network
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/clipboard"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	tailscaleStatusCmdline = "/usr/bin/tailscale status --json"
+	zerotierInfoCmdline    = "/usr/bin/zerotier-cli -j info"
+)
+
+// meshFake answers Tailscale and ZeroTier status from fixtures
+func meshFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	fake := runner.NewFake()
+	fixtures := map[string]string{
+		tailscaleStatusCmdline:                  "tailscale_status.json",
+		zerotierInfoCmdline:                     "zerotier_info.json",
+		"/usr/bin/zerotier-cli -j listnetworks": "zerotier_networks.json",
+		"/usr/bin/zerotier-cli -j listpeers":    "zerotier_peers.json",
+	}
+	for cmdline, name := range fixtures {
+		if err := fake.SetFixture(cmdline, filepath.Join("testdata", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fake
+}
+
+func TestScanMesh(t *testing.T) {
+	m := &Model{runner: meshFake(t), activeView: ViewMesh}
+	m.Update(m.scanMesh()())
+
+	if len(m.mesh) != 2 {
+		t.Fatalf("got %d mesh statuses, want Tailscale and ZeroTier: %+v", len(m.mesh), m.mesh)
+	}
+	ts := m.mesh[0]
+	if !ts.Connected || ts.Network != "caio@example.com" || ts.Self != "caios-macbook-pro" || ts.ExitNode != "build-box" {
+		t.Errorf("tailscale = %+v", ts)
+	}
+	if ts.PeersOnline() != 2 || len(ts.Peers) != 3 || ts.Peers[0].Name != "build-box" || ts.Peers[2].Name != "iphone" {
+		t.Errorf("tailscale peers = %+v, want online peers first by name", ts.Peers)
+	}
+
+	zt := m.mesh[1]
+	if !zt.Connected || zt.Network != "homelab" || len(zt.IPs) != 1 || zt.IPs[0] != "10.147.17.25" {
+		t.Errorf("zerotier = %+v", zt)
+	}
+	if zt.PeersOnline() != 1 || len(zt.Peers) != 2 {
+		t.Errorf("zerotier peers = %+v, want the two leaves with one online", zt.Peers)
+	}
+
+	view := m.renderMesh()
+	for _, want := range []string{"TAILSCALE", "Exit node: build-box", "Peers:     2 of 3 online", "ZEROTIER", "10.147.17.25"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+
+	m.Update(events.NavDown)
+	if got := m.Copyable(); got != "100.64.0.3" {
+		t.Errorf("Copyable() = %q, want the selected peer's IP", got)
+	}
+}
+
+func TestScanMeshNotInstalled(t *testing.T) {
+	m := &Model{runner: runner.NewFake().Missing("tailscale", "Tailscale", "zerotier-cli"), activeView: ViewMesh}
+	m.Update(m.scanMesh()())
+
+	view := m.renderMesh()
+	if len(m.mesh) != 0 || !strings.Contains(view, "No mesh VPN found") || !strings.Contains(view, "Tailscale unavailable") {
+		t.Errorf("mesh = %+v, view:\n%s", m.mesh, view)
+	}
+}
+
+func TestMeshDisconnectAndCopyIP(t *testing.T) {
+	fake := meshFake(t).Set("/usr/bin/tailscale down", "", nil).Set("pbcopy", "", nil)
+	clipboard.Runner = fake
+	clipboard.Clear()
+	t.Cleanup(func() {
+		clipboard.Runner = runner.Default
+		clipboard.Clear()
+	})
+	m := &Model{runner: fake, activeView: ViewMesh}
+	m.Update(m.scanMesh()())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if cmd == nil {
+		t.Fatal("D should disconnect Tailscale")
+	}
+	m.Update(cmd())
+	if m.meshMessage != "✓ Disconnected Tailscale" {
+		t.Errorf("message = %q", m.meshMessage)
+	}
+
+	m.Update(copyMeshIPMsg{})
+	if history := clipboard.History(); len(history) == 0 || history[0].Text != "100.101.102.103" {
+		t.Errorf("history = %+v, want this Mac's tailnet IP", history)
+	}
+}
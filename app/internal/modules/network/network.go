@@ -3,13 +3,26 @@ package network
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/caioricciuti/dev-cockpit/internal/awake"
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/export"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	gnet "github.com/shirou/gopsutil/v3/net"
@@ -24,6 +37,8 @@ const (
 	ViewDiagnostics
 	ViewQuality
 	ViewTools
+	ViewWiFi
+	ViewMesh
 )
 
 // DiagnosticMode represents different diagnostic tools
@@ -50,6 +65,16 @@ type PortInfo struct {
 	Protocol string
 	Address  string
 	Port     string
+
+	// Process details from ps, filled in after the lsof scan
+	Executable    string
+	PPID          string
+	ParentCommand string
+	Started       time.Time
+
+	// Set when the port is published by a Docker container
+	Container string
+	Service   string
 }
 
 // QualityResult represents network quality test results
@@ -65,8 +90,10 @@ type QualityResult struct {
 // Model represents the network module state
 type Model struct {
 	config *config.Config
+	runner runner.Runner
 	width  int
 	height int
+	top    int // lines View draws above the active view
 
 	// View management
 	activeView ViewMode
@@ -81,30 +108,63 @@ type Model struct {
 	// Port scanner
 	listeningPorts []PortInfo
 	portsLoading   bool
-	portsCursor    int
+	portsCursor    int // position in visiblePorts()
+	portsFilter    components.ListFilter
+	portsTable     components.Table
 	portsMessage   string
+	portRows       components.RowHits // port rows from the last render, for clicks
+
+	// LAN sharing of a listening port
+	share        *lanShare
+	shareBusy    bool
+	shareMessage string
 
 	// Diagnostics
 	diagMode        DiagnosticMode
 	diagInputActive bool
-	diagInputBuffer string
+	diagInputs      [DiagDNS + 1]components.TextInput // by mode, each with its own history
 	diagRunning     bool
-	diagOutput      string
-	diagTarget      string
+	diagOutput      string       // a single target's result, or why the input was refused
+	diagResults     []diagResult // by target, in the order typed
+	diagRun         int          // tells results of the current run from earlier ones
+	diagTarget      string       // the targets as last typed, for Enter to rerun
+	diagView        components.ScrollView
 
 	// Quality test
-	qualityRunning    bool
-	qualityResult     *QualityResult
-	qualityMessage    string
-	qualityAvailable  bool
+	qualityRunning bool
+	qualityResult  *QualityResult
+	qualityMessage string
+
+	// Resolved external tools, by feature name
+	caps map[string]tools.Capability
 
 	// Tools
 	toolMode        ToolMode
 	toolInputActive bool
-	toolInputBuffer string
+	toolInputs      [ToolWhois + 1]components.TextInput // by tool
 	toolRunning     bool
 	toolOutput      string
 	toolTarget      string
+	toolView        components.ScrollView
+
+	// Wi-Fi
+	wifiDevice   string
+	wifiNetworks []WiFiNetwork
+	wifiCursor   int
+	wifiLoading  bool
+	wifiJoining  string // SSID being joined
+	wifiMessage  string
+	wifiHistory  []WiFiJoin
+	wifiRows     components.RowHits
+
+	// Mesh VPNs (Tailscale, ZeroTier)
+	mesh        []MeshStatus
+	meshCursor  int // position in meshPeers()
+	meshLoading bool
+	meshScanned bool
+	meshBusy    bool // connecting or disconnecting
+	meshMessage string
+	meshRows    components.RowHits
 
 	// General
 	errorMsg string
@@ -117,6 +177,11 @@ type netMsg struct {
 	note    string
 }
 
+type selectPortMsg struct {
+	pid  string
+	port string
+}
+
 type portsMsg struct {
 	ports []PortInfo
 	err   error
@@ -127,6 +192,8 @@ type diagCompleteMsg struct {
 	output string
 	target string
 	err    error
+	run    int
+	index  int // of the target in diagResults
 }
 
 type qualityCompleteMsg struct {
@@ -145,16 +212,39 @@ type toolCompleteMsg struct {
 func New(cfg *config.Config) *Model {
 	return &Model{
 		config: cfg,
-		views:  []string{"Overview", "Ports", "Diagnostics", "Quality", "Tools"},
-		qualityAvailable: checkNetworkQualityAvailable(),
+		runner: runner.Default,
+		views:  []string{"Overview", "Ports", "Diagnostics", "Quality", "Tools", "Wi-Fi", "Mesh"},
+		portsTable: components.NewTable(
+			components.Column{Title: "COMMAND", Width: 15, Key: "c"},
+			components.Column{Title: "PID", Width: 8, Align: lipgloss.Right, Key: "d", Numeric: true},
+			components.Column{Title: "USER", Width: 10, Key: "u"},
+			components.Column{Title: "PORT", Width: 6, Align: lipgloss.Right, Key: "p", Numeric: true},
+			components.Column{Title: "ADDRESS", Key: "a"},
+		),
 	}
 }
 
 // Init initializes the module
 func (m *Model) Init() tea.Cmd {
+	// A restored session may reopen on the ports view
+	if m.activeView == ViewPorts && len(m.listeningPorts) == 0 && !m.portsLoading {
+		return tea.Batch(m.refresh(), m.scanPorts())
+	}
+	if m.activeView == ViewWiFi && m.wifiDevice == "" && !m.wifiLoading {
+		return tea.Batch(m.refresh(), m.scanWiFi())
+	}
+	if m.activeView == ViewMesh && !m.meshScanned && !m.meshLoading {
+		return tea.Batch(m.refresh(), m.scanMesh())
+	}
 	return m.refresh()
 }
 
+// StaleAfter keeps the interfaces, scans and diagnostic results across tab
+// switches for five minutes; R reloads the view sooner
+func (m *Model) StaleAfter() time.Duration {
+	return 5 * time.Minute
+}
+
 // Update handles messages
 func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -162,6 +252,37 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case events.Nav:
+		if m.diagInputActive || m.toolInputActive {
+			m.input().Nav(msg)
+			break
+		}
+		switch m.activeView {
+		case ViewOverview:
+			m.cursor = msg.Move(m.cursor, len(m.ifaces))
+		case ViewPorts:
+			m.portsCursor = msg.Move(m.portsCursor, len(m.visiblePorts()))
+		case ViewWiFi:
+			m.wifiCursor = msg.Move(m.wifiCursor, len(m.wifiNetworks))
+		case ViewMesh:
+			m.meshCursor = msg.Move(m.meshCursor, len(m.meshPeers()))
+		case ViewDiagnostics:
+			m.diagView.Nav(msg)
+		case ViewTools:
+			m.toolView.Nav(msg)
+		}
+
+	case events.Click:
+		if row, ok := m.portRows.Row(msg.Y); ok && m.activeView == ViewPorts {
+			m.portsCursor = row
+		}
+		if row, ok := m.wifiRows.Row(msg.Y); ok && m.activeView == ViewWiFi {
+			m.wifiCursor = row
+		}
+		if row, ok := m.meshRows.Row(msg.Y); ok && m.activeView == ViewMesh {
+			m.meshCursor = row
+		}
+
 	case tea.KeyMsg:
 		// Handle input mode for diagnostics
 		if m.diagInputActive {
@@ -173,6 +294,12 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			return m, m.handleToolInput(msg)
 		}
 
+		// The ports filter takes keys while typing
+		if m.activeView == ViewPorts && m.portsFilter.HandleKey(msg) {
+			m.portsCursor = 0
+			return m, nil
+		}
+
 		// Global navigation
 		switch msg.String() {
 		case "1":
@@ -185,19 +312,29 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		case "3":
 			m.activeView = ViewDiagnostics
 		case "4":
-			if m.qualityAvailable {
+			if m.qualityAvailable() {
 				m.activeView = ViewQuality
 			}
 		case "5":
 			m.activeView = ViewTools
+		case "6":
+			m.activeView = ViewWiFi
+			if m.wifiDevice == "" && !m.wifiLoading {
+				return m, m.scanWiFi()
+			}
+		case "7":
+			m.activeView = ViewMesh
+			if !m.meshScanned && !m.meshLoading {
+				return m, m.scanMesh()
+			}
 		case "tab", "l":
 			m.activeView = (m.activeView + 1) % ViewMode(len(m.views))
-			if m.activeView == ViewQuality && !m.qualityAvailable {
+			if m.activeView == ViewQuality && !m.qualityAvailable() {
 				m.activeView = (m.activeView + 1) % ViewMode(len(m.views))
 			}
 		case "shift+tab", "h":
 			m.activeView = (m.activeView - 1 + ViewMode(len(m.views))) % ViewMode(len(m.views))
-			if m.activeView == ViewQuality && !m.qualityAvailable {
+			if m.activeView == ViewQuality && !m.qualityAvailable() {
 				m.activeView = (m.activeView - 1 + ViewMode(len(m.views))) % ViewMode(len(m.views))
 			}
 		}
@@ -214,6 +351,10 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			return m, m.handleQualityKeys(msg)
 		case ViewTools:
 			return m, m.handleToolsKeys(msg)
+		case ViewWiFi:
+			return m, m.handleWiFiKeys(msg)
+		case ViewMesh:
+			return m, m.handleMeshKeys(msg)
 		}
 
 	case netMsg:
@@ -224,6 +365,16 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			m.cursor = 0
 		}
 
+	case selectPortMsg:
+		m.activeView = ViewPorts
+		m.portsFilter.Reset()
+		for i, index := range m.visiblePorts() {
+			if port := m.listeningPorts[index]; port.PID == msg.pid && port.Port == msg.port {
+				m.portsCursor = i
+				break
+			}
+		}
+
 	case portsMsg:
 		m.portsLoading = false
 		if msg.err != nil {
@@ -231,16 +382,48 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		} else {
 			m.listeningPorts = msg.ports
 			m.portsMessage = fmt.Sprintf("Found %d listening ports", len(msg.ports))
+			if m.portsCursor >= len(m.visiblePorts()) {
+				m.portsCursor = 0
+			}
 		}
 
-	case diagCompleteMsg:
-		m.diagRunning = false
-		m.diagTarget = msg.target
+	case shareStartedMsg, shareStoppedMsg:
+		return m, m.updateShare(msg)
+
+	case meshMsg, meshActionMsg, copyMeshIPMsg:
+		return m, m.updateMesh(msg)
+
+	case wifiMsg:
+		m.wifiLoading = false
+		m.wifiHistory = msg.history
 		if msg.err != nil {
-			m.diagOutput = fmt.Sprintf("Error: %v", msg.err)
-		} else {
-			m.diagOutput = msg.output
+			m.wifiMessage = fmt.Sprintf("Error: %v", msg.err)
+			break
 		}
+		m.wifiDevice = msg.device
+		m.wifiNetworks = msg.networks
+		if m.wifiCursor >= len(m.wifiNetworks) {
+			m.wifiCursor = 0
+		}
+
+	case wifiJoinedMsg:
+		m.wifiJoining = ""
+		switch {
+		case errors.Is(msg.err, errJoinCancelled):
+			m.wifiMessage = "Join cancelled"
+			return m, nil
+		case msg.err != nil:
+			m.wifiMessage = fmt.Sprintf("✗ Failed to join %s: %v", msg.join.SSID, msg.err)
+		case msg.join.Captive:
+			m.wifiMessage = fmt.Sprintf("✓ Joined %s • captive portal opened in your browser", msg.join.SSID)
+		default:
+			m.wifiMessage = fmt.Sprintf("✓ Joined %s", msg.join.SSID)
+		}
+		// Rescan for the new current network and history
+		return m, tea.Batch(m.scanWiFi(), components.StatusToast(m.wifiMessage))
+
+	case diagCompleteMsg:
+		m.updateDiagnostic(msg)
 
 	case qualityCompleteMsg:
 		m.qualityRunning = false
@@ -254,6 +437,7 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 	case toolCompleteMsg:
 		m.toolRunning = false
 		m.toolTarget = msg.target
+		m.toolView.GotoTop()
 		if msg.err != nil {
 			m.toolOutput = fmt.Sprintf("Error: %v", msg.err)
 		} else {
@@ -273,7 +457,7 @@ func (m *Model) View() string {
 	var content strings.Builder
 
 	// Title
-	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF")).Render("🌐 NETWORK")
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🌐 NETWORK")
 	content.WriteString(title + "\n\n")
 
 	// Tab navigation
@@ -281,26 +465,43 @@ func (m *Model) View() string {
 	content.WriteString(strings.Repeat("─", m.width-4) + "\n\n")
 
 	// View content
+	m.top = components.NextLine(content.String())
 	switch m.activeView {
 	case ViewOverview:
 		content.WriteString(m.renderOverview())
 	case ViewPorts:
+		top := components.NextLine(content.String())
 		content.WriteString(m.renderPorts())
+		m.portRows.Shift(top)
 	case ViewDiagnostics:
 		content.WriteString(m.renderDiagnostics())
 	case ViewQuality:
 		content.WriteString(m.renderQuality())
 	case ViewTools:
 		content.WriteString(m.renderTools())
+	case ViewWiFi:
+		top := components.NextLine(content.String())
+		content.WriteString(m.renderWiFi())
+		m.wifiRows.Shift(top)
+	case ViewMesh:
+		top := components.NextLine(content.String())
+		content.WriteString(m.renderMesh())
+		m.meshRows.Shift(top)
 	}
 
 	// Apply viewport to prevent overflow
-	maxHeight := m.height - 4
-	if maxHeight < 10 {
-		maxHeight = 10
-	}
+	return lipgloss.NewStyle().MaxHeight(m.maxHeight()).Render(content.String())
+}
+
+// maxHeight is how many lines View may draw
+func (m *Model) maxHeight() int {
+	return max(m.height-4, 10)
+}
 
-	return lipgloss.NewStyle().MaxHeight(maxHeight).Render(content.String())
+// outputHeight is how many lines of command output fit below above, the
+// part of the active view already rendered
+func (m *Model) outputHeight(above string) int {
+	return max(m.maxHeight()-m.top-components.NextLine(above), 5)
 }
 
 // Title returns the module title
@@ -308,9 +509,248 @@ func (m *Model) Title() string {
 	return "Network"
 }
 
+// Help describes the network keys for every view
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "NETWORK",
+		Description: "Interfaces, listening ports, diagnostics, quality tests and Wi-Fi.",
+		Sections: []help.Section{
+			{Title: "Views", Bindings: []help.Binding{
+				{Key: "1-7", Desc: "Overview, Ports, Diagnostics, Quality, Tools, Wi-Fi, Mesh"},
+				{Key: "Tab / Shift+Tab", Desc: "Cycle views"},
+				{Key: "y", Desc: "Copy the selection or last result in the view"},
+			}},
+			{Title: "Overview", Bindings: []help.Binding{
+				{Key: "R", Desc: "Refresh interfaces"},
+				{Key: "P", Desc: "Ping the gateway"},
+			}},
+			{Title: "Ports", Bindings: []help.Binding{
+				{Key: "R", Desc: "Rescan listening ports"},
+				{Key: "/", Desc: "Filter ports"},
+				{Key: "C / D / U / P / A", Desc: "Sort by command, PID, user, port or address (again to reverse)"},
+				{Key: "S / X", Desc: "Share the selected port on the LAN / stop sharing"},
+			}},
+			{Title: "Diagnostics and tools", Bindings: []help.Binding{
+				{Key: "P / T / D", Desc: "Ping / traceroute / DNS lookup; separate targets with commas, or type @name for a list from modules.network.target_lists, to compare them side by side"},
+				{Key: "W", Desc: "Whois (Tools view)"},
+				{Key: "↑/↓ / PgUp / PgDn", Desc: "Scroll long results (Home / End jump)"},
+				{Key: "S", Desc: "Start a quality test (Quality view)"},
+			}},
+			{Title: "Wi-Fi", Bindings: []help.Binding{
+				{Key: "Enter", Desc: "Join the selected network"},
+				{Key: "R", Desc: "Rescan networks"},
+			}},
+			{Title: "Mesh", Bindings: []help.Binding{
+				{Key: "C / D", Desc: "Connect / disconnect Tailscale"},
+				{Key: "I", Desc: "Copy this Mac's tailnet IP"},
+				{Key: "R", Desc: "Refresh status"},
+			}},
+		},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
 func (m *Model) HasOpenModal() bool {
-	return m.diagInputActive || m.toolInputActive
+	return m.diagInputActive || m.toolInputActive || m.activeView == ViewPorts && m.portsFilter.Active()
+}
+
+// Export lists the interfaces and the last port scan
+func (m *Model) Export() string {
+	if len(m.ifaces) == 0 && len(m.listeningPorts) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if len(m.ifaces) > 0 {
+		b.WriteString("## Interfaces\n\n")
+		var rows [][]string
+		for _, iface := range m.ifaces {
+			var addrs []string
+			for _, addr := range iface.Addrs {
+				addrs = append(addrs, addr.Addr)
+			}
+			rows = append(rows, []string{iface.Name, strings.Join(addrs, ", ")})
+		}
+		b.WriteString(export.Table([]string{"Interface", "Addresses"}, rows))
+		if m.gateway != "" {
+			fmt.Fprintf(&b, "\nDefault gateway: %s\n", m.gateway)
+		}
+	}
+
+	if len(m.listeningPorts) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## Listening ports\n\n")
+		var rows [][]string
+		for _, p := range m.listeningPorts {
+			rows = append(rows, []string{p.Port, p.Protocol, p.Address, p.Command, p.PID, p.User, p.Container})
+		}
+		b.WriteString(export.Table([]string{"Port", "Protocol", "Address", "Command", "PID", "User", "Container"}, rows))
+	}
+	return b.String()
+}
+
+// Copyable returns what y copies in the current view: the selected
+// interface's addresses, port, Wi-Fi network or mesh peer's IP, or the last
+// diagnostic, quality or tool result
+func (m *Model) Copyable() string {
+	if m.diagInputActive || m.toolInputActive {
+		return ""
+	}
+	switch m.activeView {
+	case ViewOverview:
+		if m.cursor < len(m.ifaces) {
+			var addrs []string
+			for _, addr := range m.ifaces[m.cursor].Addrs {
+				addrs = append(addrs, addr.Addr)
+			}
+			return strings.Join(addrs, "\n")
+		}
+	case ViewPorts:
+		visible := m.visiblePorts()
+		if m.portsFilter.Typing() || m.portsCursor >= len(visible) {
+			return ""
+		}
+		port := m.listeningPorts[visible[m.portsCursor]]
+		host := port.Address
+		if host == "*" || host == "" {
+			host = "localhost"
+		}
+		return host + ":" + port.Port
+	case ViewDiagnostics:
+		if len(m.diagResults) > 1 {
+			return m.diagCopy()
+		}
+		return strings.TrimSpace(m.diagOutput)
+	case ViewQuality:
+		if r := m.qualityResult; r != nil {
+			return fmt.Sprintf("Download: %.1f Mbps\nUpload: %.1f Mbps\nLatency: %.1f ms\nResponsiveness: %d RPM",
+				r.DownloadMbps, r.UploadMbps, r.LatencyMs, r.Responsiveness)
+		}
+	case ViewTools:
+		return strings.TrimSpace(m.toolOutput)
+	case ViewWiFi:
+		if m.wifiCursor < len(m.wifiNetworks) {
+			return m.wifiNetworks[m.wifiCursor].SSID
+		}
+	case ViewMesh:
+		if peers := m.meshPeers(); m.meshCursor < len(peers) && len(peers[m.meshCursor].IPs) > 0 {
+			return peers[m.meshCursor].IPs[0]
+		}
+	}
+	return ""
+}
+
+// savedState is what the module keeps between launches
+type savedState struct {
+	View        ViewMode       `json:"view"`
+	Cursor      int            `json:"cursor"`
+	PortsCursor int            `json:"ports_cursor"`
+	DiagMode    DiagnosticMode `json:"diag_mode"`
+	DiagTarget  string         `json:"diag_target,omitempty"`
+	ToolTarget  string         `json:"tool_target,omitempty"`
+	// Targets typed before, by feature name, oldest first
+	History map[string][]string `json:"history,omitempty"`
+}
+
+// SaveState returns the view, cursors and last diagnostics targets
+func (m *Model) SaveState() (json.RawMessage, error) {
+	// The filter isn't saved, so store the port's index in the full list
+	portsCursor := 0
+	if visible := m.visiblePorts(); m.portsCursor < len(visible) {
+		portsCursor = visible[m.portsCursor]
+	}
+	return json.Marshal(savedState{
+		View:        m.activeView,
+		Cursor:      m.cursor,
+		PortsCursor: portsCursor,
+		DiagMode:    m.diagMode,
+		DiagTarget:  m.diagTarget,
+		ToolTarget:  m.toolTarget,
+		History:     m.inputHistory(),
+	})
+}
+
+// RestoreState applies state saved by the previous launch
+func (m *Model) RestoreState(data json.RawMessage) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	if saved.View >= 0 && int(saved.View) < len(m.views) && (saved.View != ViewQuality || m.qualityAvailable()) {
+		m.activeView = saved.View
+	}
+	// The lists arrive after this and clamp the cursors to their length;
+	// one already loaded clamps them now
+	m.cursor = restoredCursor(saved.Cursor, len(m.ifaces))
+	m.portsCursor = restoredCursor(saved.PortsCursor, len(m.listeningPorts))
+	if saved.DiagMode >= 0 && int(saved.DiagMode) < len(m.diagInputs) {
+		m.diagMode = saved.DiagMode
+	}
+	m.diagTarget = saved.DiagTarget
+	m.toolTarget = saved.ToolTarget
+	for mode := range m.diagInputs {
+		m.diagInputs[mode].SetHistory(saved.History[diagFeatures[DiagnosticMode(mode)].Name])
+	}
+	for tool := range m.toolInputs {
+		m.toolInputs[tool].SetHistory(saved.History[toolFeatures[ToolMode(tool)].Name])
+	}
+	return nil
+}
+
+// restoredCursor is a saved cursor within a list of count items, or
+// within any list while it's still empty
+func restoredCursor(cursor, count int) int {
+	if count > 0 {
+		cursor = min(cursor, count-1)
+	}
+	return max(cursor, 0)
+}
+
+// inputHistory returns each input's history by feature name
+func (m *Model) inputHistory() map[string][]string {
+	history := map[string][]string{}
+	for mode := range m.diagInputs {
+		if entries := m.diagInputs[mode].History(); len(entries) > 0 {
+			history[diagFeatures[DiagnosticMode(mode)].Name] = entries
+		}
+	}
+	for tool := range m.toolInputs {
+		if entries := m.toolInputs[tool].History(); len(entries) > 0 {
+			history[toolFeatures[ToolMode(tool)].Name] = entries
+		}
+	}
+	return history
+}
+
+// SearchItems returns listening ports for global search
+func (m *Model) SearchItems() []palette.Command {
+	items := make([]palette.Command, 0, len(m.listeningPorts))
+	for _, port := range m.listeningPorts {
+		hint := fmt.Sprintf("Listening on %s • PID %s", port.Address, port.PID)
+		if port.Container != "" {
+			hint += " • container " + port.Container
+		}
+		items = append(items, palette.Command{
+			Title: fmt.Sprintf(":%s %s", port.Port, port.Command),
+			Hint:  hint,
+			Msg:   selectPortMsg{pid: port.PID, port: port.Port},
+		})
+	}
+	return items
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Show listening ports", Hint: "TCP ports in LISTEN state with their processes", Msg: palette.Key("2")},
+		{Title: "Network diagnostics", Hint: "Ping, traceroute and DNS lookup", Msg: palette.Key("3")},
+		{Title: "Network tools", Hint: "Whois and other lookups", Msg: palette.Key("5")},
+		{Title: "Switch Wi-Fi network", Hint: "Join a nearby or saved network, with join history", Msg: palette.Key("6")},
+		{Title: "Tailscale / ZeroTier status", Hint: "Mesh VPN peers, IPs and exit node", Msg: palette.Key("7")},
+		{Title: "Copy my tailnet IP", Hint: "This Mac's Tailscale or ZeroTier address", Msg: copyMeshIPMsg{}},
+	}
 }
 
 // renderTabs creates the tab navigation bar
@@ -319,17 +759,17 @@ func (m *Model) renderTabs() string {
 
 	activeStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00D9FF")).
-		Background(lipgloss.Color("#1a1a1a")).
+		Foreground(components.ColorPrimary).
+		Background(components.ColorTabBar).
 		Padding(0, 1)
 
 	inactiveStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666")).
+		Foreground(components.ColorMuted).
 		Padding(0, 1)
 
 	for i, view := range m.views {
 		// Skip quality view if not available
-		if ViewMode(i) == ViewQuality && !m.qualityAvailable {
+		if ViewMode(i) == ViewQuality && !m.qualityAvailable() {
 			continue
 		}
 
@@ -348,14 +788,6 @@ func (m *Model) handleOverviewKeys(msg tea.KeyMsg) tea.Cmd {
 	switch msg.String() {
 	case "r":
 		return m.refresh()
-	case "up", "k":
-		if m.cursor > 0 {
-			m.cursor--
-		}
-	case "down", "j":
-		if m.cursor < len(m.ifaces)-1 {
-			m.cursor++
-		}
 	case "p":
 		return m.pingGateway()
 	}
@@ -365,11 +797,11 @@ func (m *Model) handleOverviewKeys(msg tea.KeyMsg) tea.Cmd {
 func (m *Model) renderOverview() string {
 	var b strings.Builder
 
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("[R]efresh  [P]ing gateway  [↑/↓]Navigate  [1-5]Switch views")
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[R]efresh  [P]ing gateway  [↑/↓]Navigate  [1-7]Switch views")
 	b.WriteString(help + "\n\n")
 
 	if m.message != "" {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(m.message) + "\n\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorMuted).Render(m.message) + "\n\n")
 	}
 
 	if len(m.ifaces) == 0 {
@@ -382,7 +814,7 @@ func (m *Model) renderOverview() string {
 		b.WriteString(fmt.Sprintf("Default Gateway: %s\n\n", gw))
 
 		item := lipgloss.NewStyle().PaddingLeft(2)
-		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#00D9FF")).Bold(true)
+		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
 
 		b.WriteString("Network Interfaces:\n")
 		for i, ifc := range m.ifaces {
@@ -417,12 +849,17 @@ func (m *Model) pingGateway() tea.Cmd {
 	if target == "" {
 		target = "1.1.1.1"
 	}
+	ping := m.capability(pingFeature)
+	if !ping.Available() {
+		m.message = "Ping unavailable: " + ping.Reason
+		return nil
+	}
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		cmd := exec.CommandContext(ctx, "/sbin/ping", "-c", "2", target)
-		out, err := cmd.CombinedOutput()
+		cmd := exec.CommandContext(ctx, ping.Path, "-c", "2", target)
+		out, err := m.runner.CombinedOutput(cmd)
 		if err != nil {
 			errMsg := string(out)
 			if errMsg == "" {
@@ -441,26 +878,55 @@ func (m *Model) pingGateway() tea.Cmd {
 
 // Port scanner handlers
 func (m *Model) handlePortsKeys(msg tea.KeyMsg) tea.Cmd {
+	// Sorting keeps the selected port selected
+	visible := m.visiblePorts()
+	if m.portsTable.HandleKey(msg.String()) {
+		if m.portsCursor < len(visible) {
+			selected := visible[m.portsCursor]
+			for i, index := range m.visiblePorts() {
+				if index == selected {
+					m.portsCursor = i
+				}
+			}
+		}
+		return nil
+	}
+
 	switch msg.String() {
 	case "r":
 		return m.scanPorts()
-	case "up", "k":
-		if m.portsCursor > 0 {
-			m.portsCursor--
-		}
-	case "down", "j":
-		if m.portsCursor < len(m.listeningPorts)-1 {
-			m.portsCursor++
-		}
+	case "s", "x":
+		return m.handleShareKey(msg.String())
 	}
 	return nil
 }
 
+// visiblePorts returns the indexes of the ports matching the filter, in
+// the table's sort order, else best match first, else list order
+func (m *Model) visiblePorts() []int {
+	texts := make([]string, len(m.listeningPorts))
+	for i, port := range m.listeningPorts {
+		texts[i] = strings.Join([]string{port.Command, port.Port, port.PID, port.User, port.Address, port.Container}, " ")
+	}
+	visible := m.portsFilter.Matches(texts)
+	m.portsTable.Sort(visible, func(index, column int) string {
+		return portCells(m.listeningPorts[index])[column]
+	})
+	return visible
+}
+
+// portCells are a port's cells in the ports table
+func portCells(port PortInfo) []string {
+	return []string{port.Command, port.PID, port.User, port.Port, port.Address}
+}
+
 func (m *Model) renderPorts() string {
+	m.portRows.Reset()
 	var b strings.Builder
 
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("[R]efresh  [↑/↓]Navigate  [1-5]Switch views")
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[R]efresh  [↑/↓]Navigate  [/]Filter  [S]hare on LAN  [1-7]Switch views")
 	b.WriteString(help + "\n\n")
+	b.WriteString(m.renderShare())
 
 	if m.portsLoading {
 		b.WriteString("⏳ Scanning listening ports...\n")
@@ -468,22 +934,30 @@ func (m *Model) renderPorts() string {
 	}
 
 	if m.portsMessage != "" {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(m.portsMessage) + "\n\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorMuted).Render(m.portsMessage) + "\n\n")
+	}
+
+	visible := m.visiblePorts()
+	if bar := m.portsFilter.View(len(visible), len(m.listeningPorts)); bar != "" {
+		b.WriteString(bar + "\n\n")
 	}
 
 	if len(m.listeningPorts) == 0 {
 		b.WriteString("No listening ports found. Press [R] to scan.\n")
 	} else {
-		b.WriteString("LISTENING PORTS (TCP):\n\n")
+		b.WriteString("LISTENING PORTS (TCP):  " + lipgloss.NewStyle().Foreground(components.ColorSubtle).Render(m.portsTable.SortHint()) + "\n\n")
 
-		headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
-		b.WriteString(headerStyle.Render(fmt.Sprintf("%-15s %-8s %-10s %-8s %s\n", "COMMAND", "PID", "USER", "PORT", "ADDRESS")))
+		headerStyle := lipgloss.NewStyle().PaddingLeft(4).Bold(true).Foreground(components.ColorPrimary)
+		b.WriteString(headerStyle.Render(m.portsTable.Header()))
+		b.WriteString("\n")
 
 		item := lipgloss.NewStyle().PaddingLeft(2)
-		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#00D9FF")).Bold(true)
+		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
 
-		for i, port := range m.listeningPorts {
-			line := fmt.Sprintf("%-15s %-8s %-10s %-8s %s", port.Command, port.PID, port.User, port.Port, port.Address)
+		for i, index := range visible {
+			m.portRows.Add(components.NextLine(b.String()), i)
+			port := m.listeningPorts[index]
+			line := m.portsTable.Row(portCells(port)...)
 			if i == m.portsCursor {
 				b.WriteString(sel.Render("▶ " + line))
 			} else {
@@ -491,8 +965,47 @@ func (m *Model) renderPorts() string {
 			}
 			b.WriteString("\n")
 		}
+		m.portRows.End(components.NextLine(b.String()))
+
+		if m.portsCursor < len(visible) {
+			b.WriteString("\n")
+			b.WriteString(renderPortDetails(m.listeningPorts[visible[m.portsCursor]]))
+		}
+	}
+
+	return b.String()
+}
+
+// renderPortDetails describes the process behind the selected port
+func renderPortDetails(port PortInfo) string {
+	labelStyle := lipgloss.NewStyle().PaddingLeft(4).Foreground(components.ColorSubtle).Width(16)
+	valueStyle := lipgloss.NewStyle().Foreground(components.ColorBright)
+
+	var b strings.Builder
+	row := func(label, value string) {
+		b.WriteString(labelStyle.Render(label) + valueStyle.Render(value) + "\n")
 	}
 
+	if port.Container != "" {
+		container := port.Container
+		if port.Service != "" {
+			container += fmt.Sprintf(" (compose service %s)", port.Service)
+		}
+		row("Container:", container)
+	}
+	if port.Executable != "" {
+		row("Executable:", port.Executable)
+	}
+	if port.PPID != "" {
+		parent := "PID " + port.PPID
+		if port.ParentCommand != "" {
+			parent = fmt.Sprintf("%s (PID %s)", port.ParentCommand, port.PPID)
+		}
+		row("Parent:", parent)
+	}
+	if !port.Started.IsZero() {
+		row("Started:", port.Started.Format("2006-01-02 15:04:05"))
+	}
 	return b.String()
 }
 
@@ -504,48 +1017,207 @@ func (m *Model) scanPorts() tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		cmd := exec.CommandContext(ctx, "lsof", "-iTCP", "-sTCP:LISTEN", "-n", "-P")
-		output, err := cmd.CombinedOutput()
+		cmd := exec.CommandContext(ctx, "lsof", "-iTCP", "-sTCP:LISTEN", "-n", "-P", "-F", "pcuLPn")
+		output, err := m.runner.CombinedOutput(cmd)
 		if err != nil {
 			return portsMsg{err: err}
 		}
 
 		ports := parseListeningPorts(string(output))
+		m.describeProcesses(ctx, ports)
+		m.attributeContainers(ctx, ports)
 		return portsMsg{ports: ports}
 	}
 }
 
-func parseListeningPorts(output string) []PortInfo {
-	var ports []PortInfo
-	lines := strings.Split(output, "\n")
+// processInfo is one row of the ps process table
+type processInfo struct {
+	ppid       string
+	started    time.Time
+	executable string
+}
 
-	for _, line := range lines[1:] { // Skip header
-		if strings.TrimSpace(line) == "" {
+// describeProcesses fills in the executable, parent and start time of each
+// port's process. Failures leave the ports as lsof reported them.
+func (m *Model) describeProcesses(ctx context.Context, ports []PortInfo) {
+	if len(ports) == 0 {
+		return
+	}
+
+	cmd := exec.CommandContext(ctx, "ps", "-axww", "-o", "pid=,ppid=,lstart=,comm=")
+	// lstart is localized; pin the C locale so it parses
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	output, err := m.runner.Output(cmd)
+	if err != nil {
+		logger.Warn("Failed to read process table: %v", err)
+		return
+	}
+
+	procs := parseProcessTable(string(output))
+	for i := range ports {
+		proc, ok := procs[ports[i].PID]
+		if !ok {
 			continue
 		}
+		ports[i].Executable = proc.executable
+		ports[i].PPID = proc.ppid
+		ports[i].Started = proc.started
+		if parent, ok := procs[proc.ppid]; ok {
+			// Login shells are listed as e.g. "-zsh"
+			ports[i].ParentCommand = strings.TrimPrefix(filepath.Base(parent.executable), "-")
+		}
+	}
+}
 
+// parseProcessTable parses `ps -o pid=,ppid=,lstart=,comm=` output, where
+// lstart spans five fields such as "Mon Jul  1 09:30:00 2024".
+func parseProcessTable(output string) map[string]processInfo {
+	procs := make(map[string]processInfo)
+	for _, line := range strings.Split(output, "\n") {
 		fields := strings.Fields(line)
-		if len(fields) < 9 {
+		if len(fields) < 8 {
+			continue
+		}
+		started, err := time.ParseInLocation("Mon Jan 2 15:04:05 2006", strings.Join(fields[2:7], " "), time.Local)
+		if err != nil {
 			continue
 		}
+		procs[fields[0]] = processInfo{
+			ppid:       fields[1],
+			started:    started,
+			executable: strings.Join(fields[7:], " "),
+		}
+	}
+	return procs
+}
+
+// dockerContainer is the subset of `docker ps --format {{json .}}` used to
+// attribute ports to containers
+type dockerContainer struct {
+	Names  string `json:"Names"`
+	Ports  string `json:"Ports"`
+	Labels string `json:"Labels"`
+}
+
+// attributeContainers marks ports published by running Docker containers
+// with the container name and, for Compose projects, the service name
+func (m *Model) attributeContainers(ctx context.Context, ports []PortInfo) {
+	if len(ports) == 0 {
+		return
+	}
+	if _, err := m.runner.LookPath("docker"); err != nil {
+		return
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", "ps", "--format", "{{json .}}")
+	output, err := m.runner.Output(cmd)
+	if err != nil {
+		// Daemon not running; nothing is published
+		return
+	}
+
+	owners := parsePublishedPorts(string(output))
+	for i := range ports {
+		if owner, ok := owners[ports[i].Port]; ok {
+			ports[i].Container = owner.Names
+			ports[i].Service = composeService(owner.Labels)
+		}
+	}
+}
 
-		// Parse address:port from last field
-		addr := fields[8]
-		parts := strings.Split(addr, ":")
-		if len(parts) < 2 {
+// parsePublishedPorts maps each published host port to its container.
+// Port specs look like "0.0.0.0:8000-8001->8000-8001/tcp, :::5432->5432/tcp".
+func parsePublishedPorts(output string) map[string]dockerContainer {
+	owners := make(map[string]dockerContainer)
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
+		var c dockerContainer
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			continue
+		}
+		for _, spec := range strings.Split(c.Ports, ",") {
+			host, _, ok := strings.Cut(strings.TrimSpace(spec), "->")
+			if !ok || !strings.HasSuffix(spec, "/tcp") {
+				continue
+			}
+			host = host[strings.LastIndex(host, ":")+1:]
+			first, last, isRange := strings.Cut(host, "-")
+			if !isRange {
+				owners[first] = c
+				continue
+			}
+			from, err := strconv.Atoi(first)
+			if err != nil {
+				continue
+			}
+			to, err := strconv.Atoi(last)
+			if err != nil {
+				continue
+			}
+			for p := from; p <= to; p++ {
+				owners[strconv.Itoa(p)] = c
+			}
+		}
+	}
+	return owners
+}
 
-		port := PortInfo{
-			Command:  fields[0],
-			PID:      fields[1],
-			User:     fields[2],
-			Protocol: "TCP",
-			Address:  strings.Join(parts[:len(parts)-1], ":"),
-			Port:     parts[len(parts)-1],
+// composeService returns the com.docker.compose.service label, if any
+func composeService(labels string) string {
+	for _, label := range strings.Split(labels, ",") {
+		if value, ok := strings.CutPrefix(label, "com.docker.compose.service="); ok {
+			return value
 		}
+	}
+	return ""
+}
 
-		ports = append(ports, port)
+// parseListeningPorts parses lsof field output (-F pcuLPn). Each line
+// starts with a field letter: p/c/u/L describe the process and are
+// followed by one f/P/n group per open file.
+func parseListeningPorts(output string) []PortInfo {
+	var ports []PortInfo
+	var proc PortInfo
+	protocol := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		value := line[1:]
+		switch line[0] {
+		case 'p':
+			proc = PortInfo{PID: value}
+		case 'c':
+			proc.Command = value
+		case 'u':
+			if proc.User == "" {
+				proc.User = value
+			}
+		case 'L':
+			proc.User = value
+		case 'f':
+			protocol = ""
+		case 'P':
+			protocol = value
+		case 'n':
+			// IPv6 addresses contain colons; the port follows the last one
+			i := strings.LastIndex(value, ":")
+			if i < 0 {
+				continue
+			}
+			port := proc
+			port.Protocol = protocol
+			if port.Protocol == "" {
+				port.Protocol = "TCP"
+			}
+			port.Address = value[:i]
+			port.Port = value[i+1:]
+			ports = append(ports, port)
+		}
 	}
 
 	return ports
@@ -553,59 +1225,62 @@ func parseListeningPorts(output string) []PortInfo {
 
 // Diagnostics handlers
 func (m *Model) handleDiagnosticsKeys(msg tea.KeyMsg) tea.Cmd {
-	switch msg.String() {
-	case "p":
-		m.diagMode = DiagPing
-		m.diagInputActive = true
-		m.diagInputBuffer = ""
-	case "t":
-		m.diagMode = DiagTraceroute
-		m.diagInputActive = true
-		m.diagInputBuffer = ""
-	case "d":
-		m.diagMode = DiagDNS
+	if m.diagView.HandleKey(msg.String()) {
+		return nil
+	}
+	modes := map[string]DiagnosticMode{"p": DiagPing, "t": DiagTraceroute, "d": DiagDNS}
+	mode, ok := modes[msg.String()]
+	if !ok {
+		return nil
+	}
+	// Diagnostics whose tool is missing stay disabled; the view explains why
+	m.diagMode = mode
+	if m.capability(diagFeatures[mode]).Available() {
 		m.diagInputActive = true
-		m.diagInputBuffer = ""
+		m.input().Reset()
 	}
 	return nil
 }
 
+// input is the text input of the diagnostic or tool being typed for
+func (m *Model) input() *components.TextInput {
+	if m.toolInputActive {
+		return &m.toolInputs[m.toolMode]
+	}
+	return &m.diagInputs[m.diagMode]
+}
+
 func (m *Model) handleDiagInput(msg tea.KeyMsg) tea.Cmd {
+	input := m.input()
 	switch msg.String() {
 	case "esc":
 		m.diagInputActive = false
-		m.diagInputBuffer = ""
+		input.Reset()
 		return nil
 	case "enter":
-		if m.diagInputBuffer == "" {
+		// An empty input reruns the last target
+		target := input.Value()
+		if target == "" {
+			target = m.diagTarget
+		}
+		if target == "" {
 			return nil
 		}
-		target := m.diagInputBuffer
 		m.diagInputActive = false
-		m.diagInputBuffer = ""
+		input.Reset()
 
-		if !isValidTarget(target) {
-			m.diagOutput = "Invalid target. Use domain name or IP address."
+		targets, err := parseTargets(target, m.targetLists())
+		if err != nil {
+			m.diagResults = nil
+			m.diagOutput = fmt.Sprintf("Error: %v", err)
 			return nil
 		}
 
-		switch m.diagMode {
-		case DiagPing:
-			return m.executePing(target)
-		case DiagTraceroute:
-			return m.executeTraceroute(target)
-		case DiagDNS:
-			return m.executeDNS(target)
-		}
-	case "backspace":
-		if len(m.diagInputBuffer) > 0 {
-			m.diagInputBuffer = m.diagInputBuffer[:len(m.diagInputBuffer)-1]
-		}
+		input.Remember(target)
+		m.diagTarget = target
+		return m.runDiagnostic(m.capability(diagFeatures[m.diagMode]), targets)
 	default:
-		// Add printable characters
-		if len(msg.String()) == 1 && msg.String() >= " " && msg.String() <= "~" {
-			m.diagInputBuffer += msg.String()
-		}
+		input.HandleKey(msg)
 	}
 	return nil
 }
@@ -613,9 +1288,11 @@ func (m *Model) handleDiagInput(msg tea.KeyMsg) tea.Cmd {
 func (m *Model) renderDiagnostics() string {
 	var b strings.Builder
 
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("[P]ing  [T]raceroute  [D]NS Lookup  [1-5]Switch views")
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[P]ing  [T]raceroute  [D]NS Lookup  [PgUp/PgDn]Scroll  [1-7]Switch views")
 	b.WriteString(help + "\n\n")
 
+	b.WriteString(m.renderUnavailable(pingFeature, tracerouteFeature, dnsFeature))
+
 	// Mode indicator
 	modeMap := map[DiagnosticMode]string{
 		DiagPing:       "Ping",
@@ -626,57 +1303,70 @@ func (m *Model) renderDiagnostics() string {
 	b.WriteString(fmt.Sprintf("Mode: %s\n\n", modeMap[m.diagMode]))
 
 	// Input box
-	b.WriteString(m.renderInputBox("Enter target (domain or IP)") + "\n\n")
+	b.WriteString(m.renderInputBox("Enter targets (domains or IPs, or @list)") + "\n\n")
 
 	if m.diagInputActive {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("Press ENTER to start, ESC to cancel") + "\n\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorSubtle).Render(inputHint(m.input(), m.diagTarget)) + "\n\n")
 	}
 
 	// Results
-	if m.diagRunning {
+	if len(m.diagResults) > 1 {
+		b.WriteString(fmt.Sprintf("Results for %d targets:\n", len(m.diagResults)))
+		m.diagView.Height = m.outputHeight(b.String())
+		m.diagView.SetContent(m.renderDiagResults(m.width - 8))
+		b.WriteString(m.diagView.View() + "\n")
+	} else if m.diagRunning {
 		b.WriteString("⏳ Running diagnostic...\n")
 	} else if m.diagOutput != "" {
-		b.WriteString(fmt.Sprintf("Last Result (target: %s):\n", m.diagTarget))
-		outputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#aaa")).MaxHeight(20)
-		b.WriteString(outputStyle.Render(m.diagOutput) + "\n")
+		if len(m.diagResults) == 1 {
+			b.WriteString(fmt.Sprintf("Last Result (target: %s):\n", m.diagResults[0].target))
+		}
+		outputStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+		m.diagView.Height = m.outputHeight(b.String())
+		m.diagView.SetContent(outputStyle.Render(m.diagOutput))
+		b.WriteString(m.diagView.View() + "\n")
 	}
 
 	return b.String()
 }
 
-func (m *Model) renderInputBox(placeholder string) string {
-	text := m.diagInputBuffer
-	if m.toolInputActive {
-		text = m.toolInputBuffer
+// inputHint explains Enter, which reruns the last target on empty input,
+// and ↑ when there are earlier targets to recall
+func inputHint(input *components.TextInput, last string) string {
+	hint := "Press ENTER to start, ESC to cancel"
+	if input.Value() == "" && last != "" {
+		hint = fmt.Sprintf("Press ENTER to rerun %s, ESC to cancel", last)
 	}
-
-	if text == "" && !m.diagInputActive && !m.toolInputActive {
-		text = placeholder
+	if len(input.History()) > 0 {
+		hint += ", ↑ for earlier targets"
 	}
+	return hint
+}
 
-	cursor := ""
+func (m *Model) renderInputBox(placeholder string) string {
+	width := min(m.width-8, 50)
+	text := placeholder
 	if m.diagInputActive || m.toolInputActive {
-		cursor = "▊"
+		// The box's padding takes two columns
+		text = m.input().View(width - 2)
 	}
 
 	inputStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00D9FF")).
+		BorderForeground(components.ColorPrimary).
 		Padding(0, 1).
-		Width(min(m.width-8, 50))
+		Width(width)
 
-	return inputStyle.Render(text + cursor)
+	return inputStyle.Render(text)
 }
 
-func (m *Model) executePing(target string) tea.Cmd {
-	m.diagRunning = true
-
+func (m *Model) executePing(ping tools.Capability, target string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		cmd := exec.CommandContext(ctx, "/sbin/ping", "-c", "4", target)
-		output, err := cmd.CombinedOutput()
+		cmd := exec.CommandContext(ctx, ping.Path, "-c", "4", target)
+		output, err := m.runner.CombinedOutput(cmd)
 
 		if err != nil {
 			errMsg := string(output)
@@ -700,15 +1390,17 @@ func (m *Model) executePing(target string) tea.Cmd {
 	}
 }
 
-func (m *Model) executeTraceroute(target string) tea.Cmd {
-	m.diagRunning = true
-
+func (m *Model) executeTraceroute(trace tools.Capability, target string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		cmd := exec.CommandContext(ctx, "/usr/sbin/traceroute", "-m", "15", target)
-		output, err := cmd.CombinedOutput()
+		args := []string{"-m", "15", target}
+		if trace.Tool == "mtr" {
+			args = []string{"--report", "--report-cycles", "1", "-m", "15", target}
+		}
+		cmd := exec.CommandContext(ctx, trace.Path, args...)
+		output, err := m.runner.CombinedOutput(cmd)
 
 		if err != nil {
 			errMsg := string(output)
@@ -732,33 +1424,29 @@ func (m *Model) executeTraceroute(target string) tea.Cmd {
 	}
 }
 
-func (m *Model) executeDNS(target string) tea.Cmd {
-	m.diagRunning = true
-
+func (m *Model) executeDNS(lookup tools.Capability, target string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		// Try dig first, fall back to nslookup
-		cmd := exec.CommandContext(ctx, "/usr/bin/dig", "+short", target)
-		output, err := cmd.CombinedOutput()
+		// dig prints just the addresses; nslookup and host take the name alone
+		args := []string{target}
+		if lookup.Tool == "dig" {
+			args = []string{"+short", target}
+		}
+		cmd := exec.CommandContext(ctx, lookup.Path, args...)
+		output, err := m.runner.CombinedOutput(cmd)
 
 		if err != nil {
-			// Fall back to nslookup
-			cmd = exec.CommandContext(ctx, "/usr/bin/nslookup", target)
-			output, err = cmd.CombinedOutput()
-
-			if err != nil {
-				errMsg := string(output)
-				if errMsg == "" {
-					errMsg = err.Error()
-				}
-				return diagCompleteMsg{
-					mode:   DiagDNS,
-					output: "",
-					target: target,
-					err:    fmt.Errorf("%s", errMsg),
-				}
+			errMsg := string(output)
+			if errMsg == "" {
+				errMsg = err.Error()
+			}
+			return diagCompleteMsg{
+				mode:   DiagDNS,
+				output: "",
+				target: target,
+				err:    fmt.Errorf("%s", errMsg),
 			}
 		}
 
@@ -785,7 +1473,7 @@ func (m *Model) handleQualityKeys(msg tea.KeyMsg) tea.Cmd {
 func (m *Model) renderQuality() string {
 	var b strings.Builder
 
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("[S]tart test  [1-5]Switch views")
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[S]tart test  [1-7]Switch views")
 	b.WriteString(help + "\n\n")
 
 	b.WriteString("NETWORK QUALITY TEST\n\n")
@@ -796,7 +1484,7 @@ func (m *Model) renderQuality() string {
 	} else if m.qualityResult != nil {
 		b.WriteString("Last Test Results:\n\n")
 
-		resultStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00D9FF")).Bold(true)
+		resultStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
 
 		b.WriteString(fmt.Sprintf("  Download:       %s\n", resultStyle.Render(fmt.Sprintf("%.1f Mbps", m.qualityResult.DownloadMbps))))
 		b.WriteString(fmt.Sprintf("  Upload:         %s\n", resultStyle.Render(fmt.Sprintf("%.1f Mbps", m.qualityResult.UploadMbps))))
@@ -822,22 +1510,34 @@ func (m *Model) renderQuality() string {
 	}
 
 	if m.qualityMessage != "" {
-		b.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(m.qualityMessage) + "\n")
+		b.WriteString("\n" + lipgloss.NewStyle().Foreground(components.ColorMuted).Render(m.qualityMessage) + "\n")
 	}
 
 	return b.String()
 }
 
 func (m *Model) executeQualityTest() tea.Cmd {
+	quality := m.capability(qualityFeature)
 	m.qualityRunning = true
 	m.qualityMessage = "Running test..."
+	r := m.runner
 
-	return func() tea.Msg {
+	// The test saturates the connection for up to 30 seconds; sleeping
+	// midway would leave a meaningless result
+	warn := func() tea.Msg {
+		if warning := awake.Warning(r, "the test"); warning != "" {
+			return components.Toast(components.ToastWarning, warning)()
+		}
+		return nil
+	}
+	return tea.Batch(warn, func() tea.Msg {
+		hold := awake.Start(r)
+		defer hold.Release()
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		cmd := exec.CommandContext(ctx, "networkQuality", "-c")
-		output, err := cmd.Output()
+		cmd := exec.CommandContext(ctx, quality.Path, "-c")
+		output, err := m.runner.Output(cmd)
 
 		if err != nil {
 			return qualityCompleteMsg{err: err}
@@ -871,52 +1571,56 @@ func (m *Model) executeQualityTest() tea.Cmd {
 		}
 
 		return qualityCompleteMsg{result: result}
-	}
+	})
 }
 
 // Tools handlers
 func (m *Model) handleToolsKeys(msg tea.KeyMsg) tea.Cmd {
+	if m.toolView.HandleKey(msg.String()) {
+		return nil
+	}
 	switch msg.String() {
 	case "w":
 		m.toolMode = ToolWhois
-		m.toolInputActive = true
-		m.toolInputBuffer = ""
+		if m.capability(whoisFeature).Available() {
+			m.toolInputActive = true
+			m.input().Reset()
+		}
 	}
 	return nil
 }
 
 func (m *Model) handleToolInput(msg tea.KeyMsg) tea.Cmd {
+	input := m.input()
 	switch msg.String() {
 	case "esc":
 		m.toolInputActive = false
-		m.toolInputBuffer = ""
+		input.Reset()
 		return nil
 	case "enter":
-		if m.toolInputBuffer == "" {
+		// An empty input reruns the last target
+		target := input.Value()
+		if target == "" {
+			target = m.toolTarget
+		}
+		if target == "" {
 			return nil
 		}
-		target := m.toolInputBuffer
 		m.toolInputActive = false
-		m.toolInputBuffer = ""
+		input.Reset()
 
 		if !isValidTarget(target) {
 			m.toolOutput = "Invalid target. Use a domain name."
 			return nil
 		}
 
+		input.Remember(target)
 		switch m.toolMode {
 		case ToolWhois:
-			return m.executeWhois(target)
-		}
-	case "backspace":
-		if len(m.toolInputBuffer) > 0 {
-			m.toolInputBuffer = m.toolInputBuffer[:len(m.toolInputBuffer)-1]
+			return m.executeWhois(m.capability(whoisFeature), target)
 		}
 	default:
-		// Add printable characters
-		if len(msg.String()) == 1 && msg.String() >= " " && msg.String() <= "~" {
-			m.toolInputBuffer += msg.String()
-		}
+		input.HandleKey(msg)
 	}
 	return nil
 }
@@ -924,11 +1628,14 @@ func (m *Model) handleToolInput(msg tea.KeyMsg) tea.Cmd {
 func (m *Model) renderTools() string {
 	var b strings.Builder
 
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("[W]hois  [1-5]Switch views")
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[W]hois  [PgUp/PgDn]Scroll  [1-7]Switch views")
 	b.WriteString(help + "\n\n")
 
 	b.WriteString("NETWORK TOOLS\n\n")
 
+	// The hidden Quality view is explained here too
+	b.WriteString(m.renderUnavailable(whoisFeature, qualityFeature))
+
 	// Mode indicator
 	toolMap := map[ToolMode]string{
 		ToolWhois: "Whois",
@@ -939,7 +1646,7 @@ func (m *Model) renderTools() string {
 	b.WriteString(m.renderInputBox("Enter domain name") + "\n\n")
 
 	if m.toolInputActive {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("Press ENTER to query, ESC to cancel") + "\n\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorSubtle).Render(inputHint(m.input(), m.toolTarget)) + "\n\n")
 	}
 
 	// Results
@@ -947,22 +1654,24 @@ func (m *Model) renderTools() string {
 		b.WriteString("⏳ Running query...\n")
 	} else if m.toolOutput != "" {
 		b.WriteString(fmt.Sprintf("Results (target: %s):\n", m.toolTarget))
-		outputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#aaa")).MaxHeight(20)
-		b.WriteString(outputStyle.Render(m.toolOutput) + "\n")
+		outputStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+		m.toolView.Height = m.outputHeight(b.String())
+		m.toolView.SetContent(outputStyle.Render(m.toolOutput))
+		b.WriteString(m.toolView.View() + "\n")
 	}
 
 	return b.String()
 }
 
-func (m *Model) executeWhois(target string) tea.Cmd {
+func (m *Model) executeWhois(whois tools.Capability, target string) tea.Cmd {
 	m.toolRunning = true
 
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		cmd := exec.CommandContext(ctx, "/usr/bin/whois", target)
-		output, err := cmd.CombinedOutput()
+		cmd := exec.CommandContext(ctx, whois.Path, target)
+		output, err := m.runner.CombinedOutput(cmd)
 
 		if err != nil {
 			errMsg := string(output)
@@ -1005,9 +1714,88 @@ func isValidTarget(target string) bool {
 	return validPattern.MatchString(target)
 }
 
-func checkNetworkQualityAvailable() bool {
-	_, err := exec.LookPath("networkQuality")
-	return err == nil
+// Features backed by external tools. macOS ships them in /sbin and /usr/bin;
+// Homebrew alternatives cover setups where they were removed.
+var (
+	pingFeature = tools.Feature{
+		Name:  "Ping",
+		Tools: []tools.Tool{{Name: "ping", Paths: []string{"/sbin/ping"}}},
+		Hint:  "ping ships with macOS in /sbin",
+	}
+	tracerouteFeature = tools.Feature{
+		Name: "Traceroute",
+		Tools: []tools.Tool{
+			{Name: "traceroute", Paths: []string{"/usr/sbin/traceroute"}},
+			{Name: "mtr", Paths: []string{"/opt/homebrew/sbin/mtr", "/usr/local/sbin/mtr"}},
+		},
+		Hint: "brew install mtr",
+	}
+	dnsFeature = tools.Feature{
+		Name: "DNS Lookup",
+		Tools: []tools.Tool{
+			{Name: "dig", Paths: []string{"/usr/bin/dig"}},
+			{Name: "nslookup", Paths: []string{"/usr/bin/nslookup"}},
+			{Name: "host", Paths: []string{"/usr/bin/host"}},
+		},
+		Hint: "brew install bind",
+	}
+	whoisFeature = tools.Feature{
+		Name:  "Whois",
+		Tools: []tools.Tool{{Name: "whois", Paths: []string{"/usr/bin/whois"}}},
+		Hint:  "brew install whois",
+	}
+	qualityFeature = tools.Feature{
+		Name:  "Network Quality",
+		Tools: []tools.Tool{{Name: "networkQuality", Paths: []string{"/usr/bin/networkQuality"}}},
+		Hint:  "needs macOS 12 or later",
+	}
+)
+
+// qualityAvailable reports whether networkQuality is installed; the Quality
+// view is hidden without it
+func (m *Model) qualityAvailable() bool {
+	return m.capability(qualityFeature).Available()
+}
+
+// diagFeatures maps each diagnostic to the feature it runs
+var diagFeatures = map[DiagnosticMode]tools.Feature{
+	DiagPing:       pingFeature,
+	DiagTraceroute: tracerouteFeature,
+	DiagDNS:        dnsFeature,
+}
+
+// toolFeatures maps each tool to the feature it needs
+var toolFeatures = map[ToolMode]tools.Feature{
+	ToolWhois: whoisFeature,
+}
+
+// renderUnavailable explains which features are disabled by a missing
+// tool, or returns "" when all are available
+func (m *Model) renderUnavailable(features ...tools.Feature) string {
+	var b strings.Builder
+	warnStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	for _, f := range features {
+		if c := m.capability(f); !c.Available() {
+			b.WriteString(warnStyle.Render(fmt.Sprintf("⚠ %s unavailable: %s", c.Feature, c.Reason)) + "\n")
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// capability resolves f once and remembers the result
+func (m *Model) capability(f tools.Feature) tools.Capability {
+	if c, ok := m.caps[f.Name]; ok {
+		return c
+	}
+	if m.caps == nil {
+		m.caps = make(map[string]tools.Capability)
+	}
+	c := tools.Resolve(m.runner, f)
+	m.caps[f.Name] = c
+	return c
 }
 
 func min(a, b int) int {
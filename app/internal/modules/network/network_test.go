@@ -0,0 +1,420 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+	gnet "github.com/shirou/gopsutil/v3/net"
+)
+
+const (
+	lsofCmdline     = "lsof -iTCP -sTCP:LISTEN -n -P -F pcuLPn"
+	psCmdline       = "ps -axww -o pid=,ppid=,lstart=,comm="
+	dockerPsCmdline = "docker ps --format {{json .}}"
+)
+
+// portsFake answers the lsof, ps and docker calls of a port scan from fixtures
+func portsFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	fake := runner.NewFake()
+	fixtures := map[string]string{
+		lsofCmdline:     "lsof_listen.txt",
+		psCmdline:       "ps_processes.txt",
+		dockerPsCmdline: "docker_ps.jsonl",
+	}
+	for cmdline, name := range fixtures {
+		if err := fake.SetFixture(cmdline, filepath.Join("testdata", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fake
+}
+
+func TestParseListeningPorts(t *testing.T) {
+	fake := runner.NewFake()
+	if err := fake.SetFixture(lsofCmdline, filepath.Join("testdata", "lsof_listen.txt")); err != nil {
+		t.Fatal(err)
+	}
+	m := &Model{runner: fake}
+
+	msg, ok := m.scanPorts()().(portsMsg)
+	if !ok {
+		t.Fatalf("scanPorts returned %T, want portsMsg", msg)
+	}
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+
+	want := []PortInfo{
+		{Command: "rapportd", PID: "512", User: "caio", Protocol: "TCP", Address: "*", Port: "49152"},
+		{Command: "rapportd", PID: "512", User: "caio", Protocol: "TCP", Address: "*", Port: "49152"},
+		{Command: "ControlCenter", PID: "634", User: "caio", Protocol: "TCP", Address: "*", Port: "7000"},
+		{Command: "node", PID: "4821", User: "caio", Protocol: "TCP", Address: "[::1]", Port: "3000"},
+		{Command: "postgres", PID: "9102", User: "caio", Protocol: "TCP", Address: "127.0.0.1", Port: "5432"},
+		{Command: "com.docker.backend", PID: "1337", User: "caio", Protocol: "TCP", Address: "*", Port: "6379"},
+		{Command: "mDNSResponder", PID: "88", User: "65", Protocol: "TCP", Address: "*", Port: "53"},
+	}
+	if len(msg.ports) != len(want) {
+		t.Fatalf("got %d ports, want %d", len(msg.ports), len(want))
+	}
+	for i := range want {
+		if msg.ports[i] != want[i] {
+			t.Errorf("port %d = %+v, want %+v", i, msg.ports[i], want[i])
+		}
+	}
+}
+
+func TestScanPortsUpdatesModel(t *testing.T) {
+	fake := runner.NewFake()
+	if err := fake.SetFixture(lsofCmdline, filepath.Join("testdata", "lsof_listen.txt")); err != nil {
+		t.Fatal(err)
+	}
+	m := &Model{runner: fake}
+
+	cmd := m.scanPorts()
+	if !m.portsLoading {
+		t.Fatal("portsLoading should be set while scanning")
+	}
+	m.Update(cmd())
+
+	if m.portsLoading {
+		t.Error("portsLoading should be cleared after the scan")
+	}
+	if m.portsMessage != "Found 7 listening ports" {
+		t.Errorf("portsMessage = %q", m.portsMessage)
+	}
+}
+
+func TestScanPortsDescribesProcesses(t *testing.T) {
+	m := &Model{runner: portsFake(t)}
+	m.Update(m.scanPorts()())
+
+	node := m.listeningPorts[3]
+	if node.Executable != "/opt/homebrew/bin/node" || node.PPID != "4410" || node.ParentCommand != "zsh" {
+		t.Errorf("node = %+v", node)
+	}
+	if want := time.Date(2024, 7, 1, 9, 2, 17, 0, time.Local); !node.Started.Equal(want) {
+		t.Errorf("node started = %v, want %v", node.Started, want)
+	}
+	if node.Container != "" {
+		t.Errorf("node container = %q, want none", node.Container)
+	}
+
+	redis := m.listeningPorts[5]
+	if redis.Container != "shop-cache-1" || redis.Service != "cache" || redis.ParentCommand != "com.docker.backend" {
+		t.Errorf("redis = %+v", redis)
+	}
+}
+
+func TestScanPortsWithoutDocker(t *testing.T) {
+	fake := portsFake(t).Missing("docker")
+	m := &Model{runner: fake}
+	m.Update(m.scanPorts()())
+
+	if len(m.listeningPorts) != 7 || m.listeningPorts[5].Container != "" {
+		t.Fatalf("ports = %+v", m.listeningPorts)
+	}
+	for _, call := range fake.Calls() {
+		if call == dockerPsCmdline {
+			t.Error("docker ps should not run when docker is missing")
+		}
+	}
+}
+
+func TestParsePublishedPorts(t *testing.T) {
+	output := `{"Names":"web","Ports":"0.0.0.0:8080-8081->80-81/tcp, 0.0.0.0:9000->9000/udp","Labels":""}`
+	owners := parsePublishedPorts(output)
+
+	for _, port := range []string{"8080", "8081"} {
+		if owners[port].Names != "web" {
+			t.Errorf("port %s owner = %q, want web", port, owners[port].Names)
+		}
+	}
+	if _, ok := owners["9000"]; ok {
+		t.Error("UDP ports should not be attributed to TCP listeners")
+	}
+}
+
+func TestScanPortsError(t *testing.T) {
+	m := &Model{runner: runner.NewFake().Set(lsofCmdline, "", errors.New("exit status 1"))}
+
+	m.Update(m.scanPorts()())
+
+	if len(m.listeningPorts) != 0 {
+		t.Errorf("listeningPorts = %+v, want none", m.listeningPorts)
+	}
+	if m.portsMessage != "Error: exit status 1" {
+		t.Errorf("portsMessage = %q", m.portsMessage)
+	}
+}
+
+// snapshotModel returns the module with fixed interfaces and recorded ports
+func snapshotModel(t *testing.T) *Model {
+	t.Helper()
+	m := New(nil)
+	m.runner = portsFake(t).Missing("networkQuality")
+	m.Update(netMsg{
+		ifaces: []gnet.InterfaceStat{
+			{Name: "lo0", Addrs: gnet.InterfaceAddrList{{Addr: "127.0.0.1/8"}, {Addr: "::1/128"}}},
+			{Name: "en0", Addrs: gnet.InterfaceAddrList{{Addr: "192.168.1.42/24"}, {Addr: "fe80::1c2a:3bff:fe4d:5e6f/64"}}},
+			{Name: "utun3", Addrs: gnet.InterfaceAddrList{{Addr: "100.101.102.103/32"}}},
+		},
+		gateway: "192.168.1.1",
+		note:    "3 interfaces found",
+	})
+	m.Update(m.scanPorts()())
+	// Select the containerized port so its details are in the snapshot
+	m.portsCursor = 5
+	return m
+}
+
+func TestViewSnapshots(t *testing.T) {
+	views := map[ViewMode]string{ViewOverview: "Overview", ViewPorts: "Ports"}
+	for _, size := range golden.Sizes {
+		for view, name := range views {
+			t.Run(size.String()+"/"+name, func(t *testing.T) {
+				m := snapshotModel(t)
+				m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+				m.activeView = view
+				golden.RequireEqual(t, m.View())
+			})
+		}
+	}
+}
+
+func TestDNSFallsBackToNslookup(t *testing.T) {
+	fake := runner.NewFake().Missing("dig").Set("/usr/bin/nslookup example.com", "Address: 93.184.216.34", nil)
+	m := &Model{runner: fake, activeView: ViewDiagnostics}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	for _, r := range "example.com" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+
+	if m.diagOutput != "Address: 93.184.216.34" {
+		t.Errorf("diagOutput = %q, want the nslookup answer", m.diagOutput)
+	}
+}
+
+func TestTargetHistory(t *testing.T) {
+	fake := runner.NewFake().Missing("dig").
+		Set("/usr/bin/nslookup example.com", "Address: 93.184.216.34", nil).
+		Set("/usr/bin/nslookup golang.org", "Address: 142.250.74.113", nil)
+	m := &Model{runner: fake, activeView: ViewDiagnostics}
+	for _, target := range []string{"example.com", "golang.org"} {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(target)}) // pasted
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m.Update(cmd())
+	}
+
+	// ↑↑ recalls the first DNS target; ping keeps its own history
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	m.Update(events.NavUp)
+	m.Update(events.NavUp)
+	if got := m.input().Value(); got != "example.com" {
+		t.Errorf("↑↑ recalled %q", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if m.Update(events.NavUp); m.input().Value() != "" {
+		t.Errorf("ping recalled %q from the DNS history", m.input().Value())
+	}
+
+	data, err := m.SaveState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &Model{}
+	if err := restored.RestoreState(data); err != nil {
+		t.Fatal(err)
+	}
+	if h := restored.diagInputs[DiagDNS].History(); len(h) != 2 || h[1] != "golang.org" {
+		t.Errorf("restored DNS history = %q", h)
+	}
+}
+
+func TestRestoreStateOutOfRange(t *testing.T) {
+	m := &Model{}
+	if err := m.RestoreState([]byte(`{"view":1,"cursor":-3,"ports_cursor":99,"diag_mode":7}`)); err != nil {
+		t.Fatal(err)
+	}
+	if m.diagMode != DiagPing || m.cursor != 0 || m.portsCursor != 99 {
+		t.Errorf("diag mode = %d cursor = %d ports cursor = %d", m.diagMode, m.cursor, m.portsCursor)
+	}
+	m.input() // would panic on the saved mode
+
+	// Ports that are already listed clamp the cursor now
+	m.listeningPorts = []PortInfo{{Port: "5432"}, {Port: "8080"}}
+	m.RestoreState([]byte(`{"ports_cursor":99}`))
+	if m.portsCursor != 1 {
+		t.Errorf("ports cursor = %d, want the last port", m.portsCursor)
+	}
+}
+
+func TestMissingToolDisablesDiagnostic(t *testing.T) {
+	m := &Model{runner: runner.NewFake().Missing("traceroute", "mtr"), activeView: ViewDiagnostics}
+	m.width, m.height = 100, 40
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if m.diagInputActive {
+		t.Error("traceroute input opened without a traceroute tool")
+	}
+	if view := m.renderDiagnostics(); !strings.Contains(view, "Traceroute unavailable: traceroute or mtr not found • brew install mtr") {
+		t.Errorf("view doesn't explain the missing tool:\n%s", view)
+	}
+
+	// Other diagnostics keep working
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if !m.diagInputActive {
+		t.Error("ping input should open")
+	}
+}
+
+func TestCopyable(t *testing.T) {
+	m := &Model{
+		activeView:     ViewPorts,
+		listeningPorts: []PortInfo{{Command: "node", Address: "*", Port: "3000"}, {Command: "redis", Address: "127.0.0.1", Port: "6379"}},
+		portsCursor:    1,
+	}
+	if got := m.Copyable(); got != "127.0.0.1:6379" {
+		t.Errorf("ports: Copyable() = %q", got)
+	}
+	m.portsFilter.Start()
+	if got := m.Copyable(); got != "" {
+		t.Errorf("typing a filter: Copyable() = %q, want y left to the filter", got)
+	}
+
+	m.activeView = ViewDiagnostics
+	m.diagOutput = "PING example.com: 3 packets\n"
+	if got := m.Copyable(); got != "PING example.com: 3 packets" {
+		t.Errorf("diagnostics: Copyable() = %q", got)
+	}
+	m.diagInputActive = true
+	if got := m.Copyable(); got != "" {
+		t.Errorf("diagnostic input: Copyable() = %q", got)
+	}
+}
+
+func TestExport(t *testing.T) {
+	out := snapshotModel(t).Export()
+	for _, want := range []string{
+		"| en0 | 192.168.1.42/24, fe80::1c2a:3bff:fe4d:5e6f/64 |",
+		"Default gateway: 192.168.1.1",
+		"| 6379 | TCP | * | com.docker.backend | 1337 | caio | shop-cache-1 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("export lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLongResultScrolls(t *testing.T) {
+	hops := make([]string, 40)
+	for i := range hops {
+		hops[i] = fmt.Sprintf("%2d  hop-%d.example.net", i+1, i+1)
+	}
+	fake := runner.NewFake().Set("/usr/bin/traceroute -m 15 example.com", strings.Join(hops, "\n"), nil)
+	m := &Model{runner: fake, activeView: ViewDiagnostics}
+	m.width, m.height = 100, 30
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	for _, r := range "example.com" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+
+	view := m.View()
+	if !strings.Contains(view, "hop-1.example.net") || strings.Contains(view, "hop-40.example.net") {
+		t.Fatalf("a new result should show its first lines:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if view := m.View(); strings.Contains(view, " 1  hop-1.example.net") {
+		t.Errorf("PgDn should scroll past the first hop:\n%s", view)
+	}
+	m.Update(events.NavBottom)
+	if view := m.View(); !strings.Contains(view, "hop-40.example.net") {
+		t.Errorf("End should show the last hop:\n%s", view)
+	}
+}
+
+func TestMultiTargetDiagnostics(t *testing.T) {
+	fake := runner.NewFake().Missing("dig").
+		Set("/usr/bin/nslookup example.com", "Address: 93.184.216.34", nil).
+		Set("/usr/bin/nslookup 10.0.0.1", "", errors.New("NXDOMAIN")).
+		Set("/usr/bin/nslookup intranet.corp", "Address: 10.8.0.12", nil)
+	cfg := &config.Config{}
+	cfg.Modules.Network.TargetLists = map[string][]string{"vpn": {"10.0.0.1", "intranet.corp"}}
+	m := &Model{config: cfg, runner: fake, activeView: ViewDiagnostics}
+	m.width, m.height = 120, 40
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	for _, r := range "example.com, @vpn" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 3 {
+		t.Fatalf("want a command per target, got %#v", cmd())
+	}
+
+	// Results arrive in any order and show as they do
+	m.Update(batch[2]())
+	if view := m.View(); !m.diagRunning || !strings.Contains(view, "Address: 10.8.0.12") || strings.Contains(view, "93.184.216.34") {
+		t.Errorf("the first result should show while the others run:\n%s", view)
+	}
+	m.Update(batch[0]())
+	m.Update(batch[1]())
+	if m.diagRunning {
+		t.Error("still running after every result came in")
+	}
+	view := m.View()
+	for _, want := range []string{"example.com ✓", "10.0.0.1 ✗", "intranet.corp ✓", "Error: NXDOMAIN"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view lacks %q:\n%s", want, view)
+		}
+	}
+	if line := strings.Split(view[strings.Index(view, "example.com ✓"):], "\n")[0]; !strings.Contains(line, "intranet.corp ✓") {
+		t.Errorf("results should sit side by side: %q", line)
+	}
+	if got := m.Copyable(); !strings.HasPrefix(got, "== example.com ==\nAddress: 93.184.216.34\n\n== 10.0.0.1 ==\nError: NXDOMAIN") {
+		t.Errorf("Copyable() = %q", got)
+	}
+
+	// A result from a replaced run is dropped
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(batch[0]())
+	if m.diagResults[0].done {
+		t.Error("a result from the earlier run landed in the rerun")
+	}
+	if m.diagTarget != "example.com, @vpn" || cmd == nil {
+		t.Errorf("Enter should rerun %q", m.diagTarget)
+	}
+}
+
+func TestParseTargets(t *testing.T) {
+	lists := map[string][]string{"isp": {"1.1.1.1", "8.8.8.8"}}
+	targets, err := parseTargets("1.1.1.1,example.com @isp", lists)
+	if err != nil || !reflect.DeepEqual(targets, []string{"1.1.1.1", "example.com", "8.8.8.8"}) {
+		t.Errorf("targets = %q, %v", targets, err)
+	}
+	for _, input := range []string{"@office", "example.com, bad;target", " , ", "a.com b.com c.com d.com e.com f.com g.com"} {
+		if _, err := parseTargets(input, lists); err == nil {
+			t.Errorf("parseTargets(%q) should fail", input)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/clipboard"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	gnet "github.com/shirou/gopsutil/v3/net"
+)
+
+// firewallFeature is the application firewall's command line tool
+var firewallFeature = tools.Feature{
+	Name:  "Application Firewall",
+	Tools: []tools.Tool{{Name: "socketfilterfw", Paths: []string{"/usr/libexec/ApplicationFirewall/socketfilterfw"}}},
+	Hint:  "ships with macOS in /usr/libexec/ApplicationFirewall",
+}
+
+// runPrivileged is replaced in tests
+var runPrivileged = sudo.Run
+
+// lanShare is a local port exposed to the LAN until it's stopped
+type lanShare struct {
+	port    PortInfo
+	url     string
+	started time.Time
+	// forwarder relays LAN connections to a server that only listens on
+	// localhost; nil when the server already listens on every interface
+	forwarder *forwarder
+	// allowedApp was added to the application firewall for the share and
+	// is removed when it stops; "" when no rule was needed
+	allowedApp string
+	firewall   string // socketfilterfw path, for removing the rule
+}
+
+type shareStartedMsg struct {
+	share *lanShare
+	err   error
+}
+
+type shareStoppedMsg struct {
+	port string
+	err  error
+}
+
+// localOnly reports whether a listening address is unreachable from the LAN
+func localOnly(address string) bool {
+	switch strings.Trim(address, "[]") {
+	case "127.0.0.1", "::1", "localhost":
+		return true
+	}
+	return false
+}
+
+// lanAddress picks the IPv4 address peers on the LAN can reach, preferring
+// the configured default interface
+func lanAddress(ifaces []gnet.InterfaceStat, preferred string) string {
+	var fallback string
+	for _, iface := range ifaces {
+		for _, addr := range iface.Addrs {
+			ip, _, err := net.ParseCIDR(addr.Addr)
+			if err != nil || ip.To4() == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || !ip.IsPrivate() {
+				continue
+			}
+			if iface.Name == preferred {
+				return ip.String()
+			}
+			if fallback == "" {
+				fallback = ip.String()
+			}
+		}
+	}
+	return fallback
+}
+
+// startShare exposes port on the LAN: it relays connections when the server
+// only listens on localhost and allows the listening app through the
+// application firewall when the firewall is on
+func (m *Model) startShare(port PortInfo) tea.Cmd {
+	preferred := "en0"
+	if m.config != nil && m.config.Modules.Network.DefaultInterface != "" {
+		preferred = m.config.Modules.Network.DefaultInterface
+	}
+	lanIP := lanAddress(m.ifaces, preferred)
+	if lanIP == "" {
+		m.shareMessage = "✗ No LAN address found; connect to a network first"
+		return nil
+	}
+	firewall := m.capability(firewallFeature)
+	m.shareBusy = true
+	m.shareMessage = ""
+
+	return func() tea.Msg {
+		share := &lanShare{port: port, started: time.Now(), url: fmt.Sprintf("http://%s:%s", lanIP, port.Port)}
+
+		app := port.Executable
+		if localOnly(port.Address) {
+			fw, err := startForwarder(lanIP, port.Port, net.JoinHostPort(strings.Trim(port.Address, "[]"), port.Port))
+			if err != nil {
+				return shareStartedMsg{err: err}
+			}
+			share.forwarder = fw
+			share.url = "http://" + fw.addr()
+			// Peers now connect to Dev Cockpit, so it's the app the firewall must allow
+			app, _ = os.Executable()
+		}
+
+		if firewall.Available() && app != "" {
+			added, err := m.allowThroughFirewall(firewall.Path, app)
+			if err != nil {
+				share.forwarder.close()
+				return shareStartedMsg{err: err}
+			}
+			if added {
+				share.allowedApp = app
+				share.firewall = firewall.Path
+			}
+		}
+
+		if err := clipboard.Copy("Network", share.url); err != nil {
+			logger.Warn("Failed to copy share URL: %v", err)
+		}
+		logger.Info("Sharing port %s on the LAN at %s", port.Port, share.url)
+		return shareStartedMsg{share: share}
+	}
+}
+
+// allowThroughFirewall permits app to accept incoming connections. added is
+// false when the firewall is off or already permits the app, so stopping
+// the share leaves the user's own rules alone.
+func (m *Model) allowThroughFirewall(firewall, app string) (added bool, err error) {
+	out, err := m.runner.CombinedOutput(exec.Command(firewall, "--getglobalstate"))
+	if err != nil {
+		return false, fmt.Errorf("failed to read firewall state: %w", err)
+	}
+	if !strings.Contains(string(out), "enabled") {
+		return false, nil
+	}
+	out, err = m.runner.CombinedOutput(exec.Command(firewall, "--getappblocked", app))
+	if err == nil && strings.Contains(string(out), "permitted") {
+		return false, nil
+	}
+
+	if _, err := runPrivileged(firewall, "--add", app); err != nil {
+		return false, fmt.Errorf("failed to add firewall rule: %w", err)
+	}
+	if _, err := runPrivileged(firewall, "--unblockapp", app); err != nil {
+		runPrivileged(firewall, "--remove", app)
+		return false, fmt.Errorf("failed to allow %s through the firewall: %w", app, err)
+	}
+	return true, nil
+}
+
+// stop closes the relay and removes the firewall rule the share added
+func (s *lanShare) stop() error {
+	s.forwarder.close()
+	if s.allowedApp == "" {
+		return nil
+	}
+	if _, err := runPrivileged(s.firewall, "--remove", s.allowedApp); err != nil {
+		return fmt.Errorf("failed to remove firewall rule for %s: %w", s.allowedApp, err)
+	}
+	return nil
+}
+
+func (m *Model) stopShare() tea.Cmd {
+	share := m.share
+	if share == nil {
+		return nil
+	}
+	m.shareBusy = true
+	return func() tea.Msg {
+		return shareStoppedMsg{port: share.port.Port, err: share.stop()}
+	}
+}
+
+// Shutdown stops sharing when Dev Cockpit quits, so no firewall rule or
+// relay outlives the session
+func (m *Model) Shutdown() {
+	if m.share == nil {
+		return
+	}
+	if err := m.share.stop(); err != nil {
+		logger.Error("Failed to stop LAN share: %v", err)
+	}
+	m.share = nil
+}
+
+// handleShareKey starts or stops sharing the selected port
+func (m *Model) handleShareKey(key string) tea.Cmd {
+	if m.shareBusy {
+		return nil
+	}
+	switch key {
+	case "s":
+		if m.share != nil {
+			m.shareMessage = fmt.Sprintf("Already sharing :%s • press X to stop it first", m.share.port.Port)
+			return nil
+		}
+		visible := m.visiblePorts()
+		if m.portsCursor >= len(visible) {
+			return nil
+		}
+		return m.startShare(m.listeningPorts[visible[m.portsCursor]])
+	case "x":
+		return m.stopShare()
+	}
+	return nil
+}
+
+// updateShare applies the result of starting or stopping a share
+func (m *Model) updateShare(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case shareStartedMsg:
+		m.shareBusy = false
+		switch {
+		case errors.Is(msg.err, sudo.ErrCancelled):
+			m.shareMessage = "Sharing cancelled"
+			return nil
+		case msg.err != nil:
+			m.shareMessage = fmt.Sprintf("✗ Failed to share: %v", msg.err)
+		default:
+			m.share = msg.share
+			m.shareMessage = fmt.Sprintf("✓ Sharing :%s at %s (URL copied)", msg.share.port.Port, msg.share.url)
+		}
+		return components.StatusToast(m.shareMessage)
+
+	case shareStoppedMsg:
+		m.shareBusy = false
+		m.share = nil
+		if msg.err != nil {
+			m.shareMessage = fmt.Sprintf("✗ Stopped sharing :%s, but %v", msg.port, msg.err)
+		} else {
+			m.shareMessage = fmt.Sprintf("✓ Stopped sharing :%s", msg.port)
+		}
+		return components.StatusToast(m.shareMessage)
+	}
+	return nil
+}
+
+// renderShare shows the active share above the ports list
+func (m *Model) renderShare() string {
+	var b strings.Builder
+	if m.shareBusy {
+		b.WriteString("⏳ Updating LAN share...\n\n")
+	} else if m.share != nil {
+		box := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(components.ColorSuccess).
+			Padding(0, 1)
+		lines := []string{
+			lipgloss.NewStyle().Bold(true).Foreground(components.ColorSuccess).Render("🔗 SHARING ON LAN"),
+			fmt.Sprintf("%s :%s → %s", m.share.port.Command, m.share.port.Port, m.share.url),
+		}
+		var notes []string
+		if m.share.forwarder != nil {
+			notes = append(notes, "relayed (server only listens on localhost)")
+		}
+		if m.share.allowedApp != "" {
+			notes = append(notes, "firewall rule added")
+		}
+		notes = append(notes, fmt.Sprintf("for %s", time.Since(m.share.started).Round(time.Second)))
+		lines = append(lines, lipgloss.NewStyle().Foreground(components.ColorSubtle).Render(strings.Join(notes, " • ")+" • [X] Stop"))
+		b.WriteString(box.Render(strings.Join(lines, "\n")) + "\n\n")
+	}
+	if m.shareMessage != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorSubtle).Render(m.shareMessage) + "\n\n")
+	}
+	return b.String()
+}
+
+// forwarder relays TCP connections from a LAN address to a local server
+type forwarder struct {
+	listener net.Listener
+	target   string
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+}
+
+// startForwarder listens on host:port, or on a free port when that's taken,
+// and relays every connection to target
+func startForwarder(host, port, target string) (*forwarder, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		listener, err = net.Listen("tcp", net.JoinHostPort(host, "0"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", host, err)
+	}
+	f := &forwarder{listener: listener, target: target, conns: make(map[net.Conn]bool)}
+	go f.serve()
+	return f, nil
+}
+
+// addr is where peers connect
+func (f *forwarder) addr() string {
+	return f.listener.Addr().String()
+}
+
+func (f *forwarder) serve() {
+	for {
+		client, err := f.listener.Accept()
+		if err != nil {
+			return // closed
+		}
+		go f.relay(client)
+	}
+}
+
+func (f *forwarder) relay(client net.Conn) {
+	server, err := net.DialTimeout("tcp", f.target, 5*time.Second)
+	if err != nil {
+		logger.Warn("LAN share: failed to reach %s: %v", f.target, err)
+		client.Close()
+		return
+	}
+	if !f.track(client, server) {
+		return
+	}
+	defer f.untrack(client, server)
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst, src net.Conn) {
+		io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go pipe(server, client)
+	go pipe(client, server)
+	<-done
+}
+
+// track registers a relayed pair so close can end it; it refuses once the
+// forwarder is closed
+func (f *forwarder) track(conns ...net.Conn) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conns == nil {
+		for _, c := range conns {
+			c.Close()
+		}
+		return false
+	}
+	for _, c := range conns {
+		f.conns[c] = true
+	}
+	return true
+}
+
+func (f *forwarder) untrack(conns ...net.Conn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range conns {
+		c.Close()
+		delete(f.conns, c)
+	}
+}
+
+// close stops listening and ends every relayed connection. It's safe on a
+// nil forwarder.
+func (f *forwarder) close() {
+	if f == nil {
+		return
+	}
+	f.listener.Close()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for c := range f.conns {
+		c.Close()
+	}
+	f.conns = nil
+}
@@ -0,0 +1,164 @@
+package network
+
+import (
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	gnet "github.com/shirou/gopsutil/v3/net"
+)
+
+const firewallPath = "/usr/bin/socketfilterfw"
+
+// stubPrivileged records privileged commands instead of prompting for them
+func stubPrivileged(t *testing.T, err error) *[]string {
+	t.Helper()
+	var calls []string
+	runPrivileged = func(command string, args ...string) (string, error) {
+		calls = append(calls, strings.Join(append([]string{command}, args...), " "))
+		return "", err
+	}
+	t.Cleanup(func() { runPrivileged = sudo.Run })
+	return &calls
+}
+
+// shareModel lists one node server on every interface, with the firewall on
+func shareModel(t *testing.T) (*Model, *runner.Fake) {
+	t.Helper()
+	fake := runner.NewFake()
+	fake.Set(firewallPath+" --getglobalstate", "Firewall is enabled. (State = 1)\n", nil)
+	fake.Set(firewallPath+" --getappblocked /usr/local/bin/node", "The application is blocked\n", nil)
+	clipboard.Runner = fake
+	t.Cleanup(func() { clipboard.Runner = runner.Default })
+
+	m := &Model{
+		runner:     fake,
+		activeView: ViewPorts,
+		ifaces: []gnet.InterfaceStat{
+			{Name: "lo0", Addrs: gnet.InterfaceAddrList{{Addr: "127.0.0.1/8"}}},
+			{Name: "en0", Addrs: gnet.InterfaceAddrList{{Addr: "fe80::1/64"}, {Addr: "192.168.1.20/24"}}},
+		},
+		listeningPorts: []PortInfo{{Command: "node", Address: "*", Port: "3000", Executable: "/usr/local/bin/node"}},
+	}
+	return m, fake
+}
+
+func TestLanAddress(t *testing.T) {
+	ifaces := []gnet.InterfaceStat{
+		{Name: "lo0", Addrs: gnet.InterfaceAddrList{{Addr: "127.0.0.1/8"}}},
+		{Name: "utun0", Addrs: gnet.InterfaceAddrList{{Addr: "100.64.0.2/32"}}},
+		{Name: "bridge0", Addrs: gnet.InterfaceAddrList{{Addr: "10.0.0.5/24"}}},
+		{Name: "en0", Addrs: gnet.InterfaceAddrList{{Addr: "192.168.1.20/24"}}},
+	}
+	if got := lanAddress(ifaces, "en0"); got != "192.168.1.20" {
+		t.Errorf("preferred interface: got %q", got)
+	}
+	if got := lanAddress(ifaces, "en1"); got != "10.0.0.5" {
+		t.Errorf("fallback: got %q, want the first private address", got)
+	}
+	if got := lanAddress(ifaces[:2], "en0"); got != "" {
+		t.Errorf("no private address: got %q", got)
+	}
+}
+
+func TestShareAddsAndRemovesFirewallRule(t *testing.T) {
+	privileged := stubPrivileged(t, nil)
+	m, _ := shareModel(t)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if cmd == nil {
+		t.Fatal("S should start sharing the selected port")
+	}
+	m.Update(cmd())
+	if m.share == nil {
+		t.Fatalf("share not started: %s", m.shareMessage)
+	}
+	if m.share.url != "http://192.168.1.20:3000" || m.share.forwarder != nil {
+		t.Errorf("share = %+v, want a direct URL without a relay", m.share)
+	}
+	if !strings.Contains(m.renderPorts(), "SHARING ON LAN") {
+		t.Error("view should show the active share")
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m.Update(cmd())
+	if m.share != nil {
+		t.Error("X should stop sharing")
+	}
+	want := []string{
+		firewallPath + " --add /usr/local/bin/node",
+		firewallPath + " --unblockapp /usr/local/bin/node",
+		firewallPath + " --remove /usr/local/bin/node",
+	}
+	if !reflect.DeepEqual(*privileged, want) {
+		t.Errorf("privileged calls = %q, want %q", *privileged, want)
+	}
+}
+
+func TestShareKeepsExistingFirewallRules(t *testing.T) {
+	privileged := stubPrivileged(t, nil)
+	m, fake := shareModel(t)
+	fake.Set(firewallPath+" --getappblocked /usr/local/bin/node", "The application is permitted\n", nil)
+
+	m.Update(m.startShare(m.listeningPorts[0])())
+	m.Shutdown()
+	if len(*privileged) != 0 {
+		t.Errorf("an app the firewall already permits needs no rule, got %q", *privileged)
+	}
+}
+
+func TestShareCancelled(t *testing.T) {
+	stubPrivileged(t, sudo.ErrCancelled)
+	m, _ := shareModel(t)
+
+	m.Update(m.startShare(m.listeningPorts[0])())
+	if m.share != nil || m.shareMessage != "Sharing cancelled" {
+		t.Errorf("share = %+v message = %q, want a cancelled share", m.share, m.shareMessage)
+	}
+}
+
+func TestForwarderRelaysToLocalServer(t *testing.T) {
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	go func() {
+		conn, err := server.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "hello from the dev server")
+	}()
+
+	f, err := startForwarder("127.0.0.1", "0", server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.close()
+
+	conn, err := net.Dial("tcp", f.addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	got, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello from the dev server" {
+		t.Errorf("relayed %q", got)
+	}
+
+	f.close()
+	if _, err := net.Dial("tcp", f.addr()); err == nil {
+		t.Error("closed forwarder should stop listening")
+	}
+}
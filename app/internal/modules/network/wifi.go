@@ -0,0 +1,531 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// WiFiNetwork is a network the Wi-Fi view can join: nearby from the last
+// scan, known from the preferred networks list, or both
+type WiFiNetwork struct {
+	SSID     string
+	Security string // "" when unknown (known networks that aren't nearby)
+	Signal   int    // RSSI in dBm, 0 when unknown
+	Known    bool
+	Nearby   bool
+	Current  bool
+}
+
+// Open reports whether joining needs no password
+func (n WiFiNetwork) Open() bool {
+	return n.Security == "None" || n.Security == "Open"
+}
+
+// needsPassword reports whether joining must prompt: saved networks use
+// the keychain and open ones have none
+func (n WiFiNetwork) needsPassword() bool {
+	return !n.Known && !n.Open()
+}
+
+// WiFiJoin is an entry in the join history
+type WiFiJoin struct {
+	Time    time.Time `json:"time"`
+	SSID    string    `json:"ssid"`
+	OK      bool      `json:"ok"`
+	Error   string    `json:"error,omitempty"`
+	Captive bool      `json:"captive,omitempty"`
+}
+
+// wifiHistoryFile holds join history in the audit store, so retention
+// applies to it
+const wifiHistoryFile = "wifi_joins.jsonl"
+
+// wifiHistoryShown is how many recent joins the view lists
+const wifiHistoryShown = 5
+
+// errJoinCancelled is returned when the password dialog is cancelled
+var errJoinCancelled = errors.New("cancelled")
+
+type wifiMsg struct {
+	device   string
+	networks []WiFiNetwork
+	history  []WiFiJoin
+	err      error
+}
+
+type wifiJoinedMsg struct {
+	join WiFiJoin
+	err  error
+}
+
+// scanWiFi finds the Wi-Fi device, nearby and known networks and the join
+// history
+func (m *Model) scanWiFi() tea.Cmd {
+	m.wifiLoading = true
+	historyPath := m.wifiHistoryPath()
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+
+		history, err := loadWiFiHistory(historyPath)
+		if err != nil {
+			logger.Warn("Failed to read Wi-Fi history: %v", err)
+		}
+
+		out, err := m.runner.Output(exec.CommandContext(ctx, "networksetup", "-listallhardwareports"))
+		if err != nil {
+			return wifiMsg{err: fmt.Errorf("failed to list hardware ports: %w", err), history: history}
+		}
+		device := parseWiFiDevice(string(out))
+		if device == "" {
+			return wifiMsg{err: errors.New("no Wi-Fi interface found"), history: history}
+		}
+
+		var known []string
+		if out, err := m.runner.Output(exec.CommandContext(ctx, "networksetup", "-listpreferredwirelessnetworks", device)); err == nil {
+			known = parsePreferredNetworks(string(out))
+		}
+
+		// system_profiler is slow (a few seconds) but, unlike the removed
+		// airport tool, works on every macOS version
+		out, err = m.runner.Output(exec.CommandContext(ctx, "system_profiler", "SPAirPortDataType"))
+		if err != nil {
+			return wifiMsg{err: fmt.Errorf("failed to scan Wi-Fi networks: %w", err), history: history}
+		}
+		current, nearby := parseAirPortData(string(out))
+
+		return wifiMsg{device: device, networks: mergeNetworks(current, nearby, known), history: history}
+	}
+}
+
+// joinWiFi joins network, asking for its password in a macOS dialog when
+// it isn't saved, and records the attempt in the history
+func (m *Model) joinWiFi(network WiFiNetwork) tea.Cmd {
+	m.wifiJoining = network.SSID
+	m.wifiMessage = ""
+	device := m.wifiDevice
+	historyPath := m.wifiHistoryPath()
+
+	return func() tea.Msg {
+		join := WiFiJoin{Time: time.Now(), SSID: network.SSID}
+
+		args := []string{"-setairportnetwork", device, network.SSID}
+		if network.needsPassword() {
+			password, err := m.promptPassword(network.SSID)
+			if err != nil {
+				return wifiJoinedMsg{join: join, err: err}
+			}
+			// networksetup only takes the password as an argument
+			args = append(args, password)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		out, err := m.runner.CombinedOutput(exec.CommandContext(ctx, "networksetup", args...))
+		// networksetup reports failures on stdout and still exits 0
+		if msg := strings.TrimSpace(string(out)); err == nil && msg != "" {
+			err = errors.New(msg)
+		}
+
+		if err == nil {
+			join.OK = true
+			if network.Open() && captivePortal() {
+				join.Captive = true
+				if _, err := m.runner.CombinedOutput(exec.Command("open", captiveURL)); err != nil {
+					logger.Warn("Failed to open captive portal: %v", err)
+				}
+			}
+		} else {
+			join.Error = err.Error()
+		}
+
+		if err := appendWiFiHistory(historyPath, join); err != nil {
+			logger.Warn("Failed to record Wi-Fi join: %v", err)
+		}
+		return wifiJoinedMsg{join: join, err: err}
+	}
+}
+
+// promptPassword asks for a network's password in a native dialog with a
+// hidden answer, so it never appears in the terminal
+func (m *Model) promptPassword(ssid string) (string, error) {
+	script := fmt.Sprintf(`text returned of (display dialog %s default answer "" with hidden answer with title "Dev Cockpit" with icon caution)`,
+		appleScriptString(fmt.Sprintf("Password for Wi-Fi network %q", ssid)))
+	out, err := m.runner.Output(exec.Command("osascript", "-e", script))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "-128") {
+			return "", errJoinCancelled
+		}
+		return "", fmt.Errorf("password dialog failed: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// captiveURL answers "Success" unless a captive portal intercepts it; it's
+// the page macOS itself probes
+const captiveURL = "http://captive.apple.com/hotspot-detect.html"
+
+// captivePortal is replaced in tests
+var captivePortal = detectCaptivePortal
+
+// detectCaptivePortal reports whether the network intercepts web traffic
+// with a login page
+func detectCaptivePortal() bool {
+	// Give DHCP a moment after joining
+	time.Sleep(2 * time.Second)
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(captiveURL)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return !strings.Contains(string(body), "Success")
+}
+
+func (m *Model) wifiHistoryPath() string {
+	return filepath.Join(storage.DataDir(m.config), storage.StoreAudit, wifiHistoryFile)
+}
+
+// loadWiFiHistory returns the most recent joins, newest first. A missing
+// file is an empty history.
+func loadWiFiHistory(path string) ([]WiFiJoin, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var joins []WiFiJoin
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var join WiFiJoin
+		if err := json.Unmarshal(scanner.Bytes(), &join); err != nil {
+			continue // skip a line cut short by a crash
+		}
+		joins = append(joins, join)
+	}
+	if len(joins) > wifiHistoryShown {
+		joins = joins[len(joins)-wifiHistoryShown:]
+	}
+	for i, j := 0, len(joins)-1; i < j; i, j = i+1, j-1 {
+		joins[i], joins[j] = joins[j], joins[i]
+	}
+	return joins, scanner.Err()
+}
+
+func appendWiFiHistory(path string, join WiFiJoin) error {
+	data, err := json.Marshal(join)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// parseWiFiDevice finds the Wi-Fi device in `networksetup -listallhardwareports`
+func parseWiFiDevice(output string) string {
+	wifi := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if port, ok := strings.CutPrefix(line, "Hardware Port: "); ok {
+			wifi = port == "Wi-Fi" || port == "AirPort"
+			continue
+		}
+		if device, ok := strings.CutPrefix(line, "Device: "); ok && wifi {
+			return device
+		}
+	}
+	return ""
+}
+
+// parsePreferredNetworks reads `networksetup -listpreferredwirelessnetworks`
+func parsePreferredNetworks(output string) []string {
+	var ssids []string
+	for _, line := range strings.Split(output, "\n") {
+		// Networks are tab-indented under a "Preferred networks on en0:" header
+		if !strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if ssid := strings.TrimSpace(line); ssid != "" {
+			ssids = append(ssids, ssid)
+		}
+	}
+	return ssids
+}
+
+// parseAirPortData reads the current and nearby networks from
+// `system_profiler SPAirPortDataType`. Networks are nested by indentation:
+//
+//	Current Network Information:
+//	  HomeNet:
+//	    Security: WPA2 Personal
+//	    Signal / Noise: -52 dBm / -92 dBm
+//	Other Local Wi-Fi Networks:
+//	  CafeGuest:
+//	    ...
+func parseAirPortData(output string) (current *WiFiNetwork, nearby []WiFiNetwork) {
+	var (
+		section   string // "current" or "other" while inside one
+		sectionIn int    // indentation of the section header
+		networkIn = -1   // indentation of network names in the section
+		network   *WiFiNetwork
+		finish    = func() {
+			if network == nil {
+				return
+			}
+			if section == "current" {
+				network.Current = true
+				current = network
+			}
+			nearby = append(nearby, *network)
+			network = nil
+		}
+	)
+
+	for _, line := range strings.Split(output, "\n") {
+		text := strings.TrimSpace(line)
+		if text == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch text {
+		case "Current Network Information:", "Other Local Wi-Fi Networks:":
+			finish()
+			section, sectionIn, networkIn = "other", indent, -1
+			if text == "Current Network Information:" {
+				section = "current"
+			}
+			continue
+		}
+		if section == "" {
+			continue
+		}
+		if indent <= sectionIn {
+			finish()
+			section = ""
+			continue
+		}
+
+		if networkIn < 0 {
+			networkIn = indent
+		}
+		if indent == networkIn && strings.HasSuffix(text, ":") {
+			finish()
+			network = &WiFiNetwork{SSID: strings.TrimSuffix(text, ":"), Nearby: true}
+			continue
+		}
+		if network == nil {
+			continue
+		}
+		key, value, ok := strings.Cut(text, ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Security":
+			network.Security = value
+		case "Signal / Noise":
+			if dbm, _, ok := strings.Cut(value, " dBm"); ok {
+				network.Signal, _ = strconv.Atoi(dbm)
+			}
+		}
+	}
+	finish()
+
+	// The current network shows up once in its own section; drop a repeat
+	// from the nearby list
+	if current != nil {
+		kept := nearby[:0]
+		seen := false
+		for _, n := range nearby {
+			if n.SSID == current.SSID {
+				if seen {
+					continue
+				}
+				n = *current
+				seen = true
+			}
+			kept = append(kept, n)
+		}
+		nearby = kept
+	}
+	return current, nearby
+}
+
+// mergeNetworks lists the current network first, then nearby networks by
+// signal strength, then known networks that are out of range
+func mergeNetworks(current *WiFiNetwork, nearby []WiFiNetwork, known []string) []WiFiNetwork {
+	isKnown := make(map[string]bool, len(known))
+	for _, ssid := range known {
+		isKnown[ssid] = true
+	}
+
+	networks := make([]WiFiNetwork, 0, len(nearby)+len(known))
+	seen := make(map[string]bool)
+	for _, n := range nearby {
+		n.Known = isKnown[n.SSID]
+		networks = append(networks, n)
+		seen[n.SSID] = true
+	}
+	sort.SliceStable(networks, func(i, j int) bool {
+		if networks[i].Current != networks[j].Current {
+			return networks[i].Current
+		}
+		return networks[i].Signal > networks[j].Signal
+	})
+	for _, ssid := range known {
+		if !seen[ssid] {
+			networks = append(networks, WiFiNetwork{SSID: ssid, Known: true})
+		}
+	}
+	return networks
+}
+
+// handleWiFiKeys handles keys on the Wi-Fi view
+func (m *Model) handleWiFiKeys(msg tea.KeyMsg) tea.Cmd {
+	if m.wifiLoading || m.wifiJoining != "" {
+		return nil
+	}
+	switch msg.String() {
+	case "r":
+		m.wifiMessage = ""
+		return m.scanWiFi()
+	case "enter":
+		if m.wifiCursor >= len(m.wifiNetworks) {
+			return nil
+		}
+		network := m.wifiNetworks[m.wifiCursor]
+		if network.Current {
+			m.wifiMessage = fmt.Sprintf("Already connected to %s", network.SSID)
+			return nil
+		}
+		return m.joinWiFi(network)
+	}
+	return nil
+}
+
+func (m *Model) renderWiFi() string {
+	m.wifiRows.Reset()
+	var b strings.Builder
+
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[Enter]Join  [R]escan  [↑/↓]Navigate  [1-7]Switch views")
+	b.WriteString(help + "\n\n")
+
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	okStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	errStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+
+	switch {
+	case m.wifiLoading:
+		b.WriteString("⏳ Scanning Wi-Fi networks...\n")
+		return b.String()
+	case m.wifiJoining != "":
+		b.WriteString(fmt.Sprintf("⏳ Joining %s...\n", m.wifiJoining))
+		return b.String()
+	}
+
+	if m.wifiMessage != "" {
+		b.WriteString(mutedStyle.Render(m.wifiMessage) + "\n\n")
+	}
+
+	if len(m.wifiNetworks) > 0 {
+		b.WriteString(fmt.Sprintf("WI-FI NETWORKS (%s):\n\n", m.wifiDevice))
+		headerStyle := lipgloss.NewStyle().PaddingLeft(4).Bold(true).Foreground(components.ColorPrimary)
+		b.WriteString(headerStyle.Render(fmt.Sprintf("%-28s %-8s %-18s %s", "SSID", "SIGNAL", "SECURITY", "STATUS")))
+		b.WriteString("\n")
+
+		item := lipgloss.NewStyle().PaddingLeft(2)
+		sel := lipgloss.NewStyle().PaddingLeft(2).Foreground(components.ColorPrimary).Bold(true)
+		for i, network := range m.wifiNetworks {
+			m.wifiRows.Add(components.NextLine(b.String()), i)
+			signal, security := "—", network.Security
+			if network.Signal != 0 {
+				signal = fmt.Sprintf("%d dBm", network.Signal)
+			}
+			if security == "" {
+				security = "—"
+			}
+			var status []string
+			if network.Current {
+				status = append(status, "● connected")
+			}
+			if network.Known {
+				status = append(status, "saved")
+			}
+			if !network.Nearby {
+				status = append(status, "out of range")
+			}
+			line := fmt.Sprintf("%-28s %-8s %-18s %s", components.TruncateString(network.SSID, 28), signal, components.TruncateString(security, 18), strings.Join(status, ", "))
+			if i == m.wifiCursor {
+				b.WriteString(sel.Render("▶ " + line))
+			} else {
+				b.WriteString(item.Render("  " + line))
+			}
+			b.WriteString("\n")
+		}
+		m.wifiRows.End(components.NextLine(b.String()))
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("Saved networks join with the keychain password; others ask for it in a macOS dialog."))
+		b.WriteString("\n\n")
+	} else if m.wifiDevice != "" {
+		b.WriteString("No Wi-Fi networks found. Press [R] to rescan.\n\n")
+	}
+
+	b.WriteString("RECENT JOINS:\n")
+	if len(m.wifiHistory) == 0 {
+		b.WriteString(mutedStyle.Render("  No joins yet") + "\n")
+	}
+	for _, join := range m.wifiHistory {
+		when := join.Time.Local().Format("Jan 2 15:04")
+		switch {
+		case join.OK && join.Captive:
+			b.WriteString(okStyle.Render(fmt.Sprintf("  ✓ %s  %s (captive portal)", when, join.SSID)) + "\n")
+		case join.OK:
+			b.WriteString(okStyle.Render(fmt.Sprintf("  ✓ %s  %s", when, join.SSID)) + "\n")
+		default:
+			b.WriteString(errStyle.Render(fmt.Sprintf("  ✗ %s  %s: %s", when, join.SSID, join.Error)) + "\n")
+		}
+	}
+
+	return b.String()
+}
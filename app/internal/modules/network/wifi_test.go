@@ -0,0 +1,124 @@
+package network
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	hardwarePortsCmdline = "networksetup -listallhardwareports"
+	preferredCmdline     = "networksetup -listpreferredwirelessnetworks en0"
+	airportCmdline       = "system_profiler SPAirPortDataType"
+)
+
+// wifiFake answers a Wi-Fi scan from fixtures
+func wifiFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	fake := runner.NewFake()
+	fixtures := map[string]string{
+		hardwarePortsCmdline: "hardware_ports.txt",
+		preferredCmdline:     "preferred_networks.txt",
+		airportCmdline:       "system_profiler_airport.txt",
+	}
+	for cmdline, name := range fixtures {
+		if err := fake.SetFixture(cmdline, filepath.Join("testdata", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fake
+}
+
+func TestScanWiFi(t *testing.T) {
+	m := &Model{runner: wifiFake(t)}
+	m.Update(m.scanWiFi()())
+
+	if m.wifiDevice != "en0" {
+		t.Errorf("device = %q, want en0", m.wifiDevice)
+	}
+	want := []WiFiNetwork{
+		{SSID: "HomeNet", Security: "WPA2 Personal", Signal: -52, Known: true, Nearby: true, Current: true},
+		{SSID: "Neighbor 5G", Security: "WPA2/WPA3 Personal", Signal: -61, Nearby: true},
+		{SSID: "CafeGuest", Security: "None", Signal: -70, Known: true, Nearby: true},
+		{SSID: "Office WiFi", Known: true},
+	}
+	if len(m.wifiNetworks) != len(want) {
+		t.Fatalf("got %d networks, want %d: %+v", len(m.wifiNetworks), len(want), m.wifiNetworks)
+	}
+	for i := range want {
+		if m.wifiNetworks[i] != want[i] {
+			t.Errorf("network %d = %+v, want %+v", i, m.wifiNetworks[i], want[i])
+		}
+	}
+}
+
+// joinWith scans, selects ssid and presses Enter, returning the join result
+func joinWith(t *testing.T, fake *runner.Fake, ssid string) (*Model, wifiJoinedMsg) {
+	t.Helper()
+	m := &Model{runner: fake, activeView: ViewWiFi}
+	m.Update(m.scanWiFi()())
+	for i, network := range m.wifiNetworks {
+		if network.SSID == ssid {
+			m.wifiCursor = i
+		}
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter should start a join")
+	}
+	msg, ok := cmd().(wifiJoinedMsg)
+	if !ok {
+		t.Fatalf("join returned %T, want wifiJoinedMsg", msg)
+	}
+	m.Update(msg)
+	return m, msg
+}
+
+func TestJoinNewNetworkPromptsForPassword(t *testing.T) {
+	fake := wifiFake(t)
+	fake.Set(`osascript -e text returned of (display dialog "Password for Wi-Fi network \"Neighbor 5G\"" default answer "" with hidden answer with title "Dev Cockpit" with icon caution)`, "hunter2\n", nil)
+	fake.Set("networksetup -setairportnetwork en0 Neighbor 5G hunter2", "", nil)
+
+	m, msg := joinWith(t, fake, "Neighbor 5G")
+	if msg.err != nil || !msg.join.OK {
+		t.Fatalf("join failed: %v", msg.err)
+	}
+	// The rescan after joining picks up the recorded history
+	m.Update(m.scanWiFi()())
+	if len(m.wifiHistory) != 1 || m.wifiHistory[0].SSID != "Neighbor 5G" || !m.wifiHistory[0].OK {
+		t.Errorf("history = %+v, want the successful join", m.wifiHistory)
+	}
+}
+
+func TestJoinSavedNetworkUsesKeychain(t *testing.T) {
+	fake := wifiFake(t)
+	fake.Set("networksetup -setairportnetwork en0 Office WiFi", "Could not find network Office WiFi.", nil)
+
+	m, msg := joinWith(t, fake, "Office WiFi")
+	if msg.err == nil || msg.join.Error != "Could not find network Office WiFi." {
+		t.Fatalf("join = %+v, err %v; want networksetup's failure", msg.join, msg.err)
+	}
+	if m.wifiMessage != "✗ Failed to join Office WiFi: Could not find network Office WiFi." {
+		t.Errorf("message = %q", m.wifiMessage)
+	}
+}
+
+func TestJoinOpenNetworkOpensCaptivePortal(t *testing.T) {
+	captivePortal = func() bool { return true }
+	t.Cleanup(func() { captivePortal = detectCaptivePortal })
+
+	fake := wifiFake(t)
+	fake.Set("networksetup -setairportnetwork en0 CafeGuest", "", nil)
+	fake.Set("open "+captiveURL, "", nil)
+
+	m, msg := joinWith(t, fake, "CafeGuest")
+	if !msg.join.Captive {
+		t.Errorf("join = %+v, want a captive portal", msg.join)
+	}
+	if m.wifiMessage != "✓ Joined CafeGuest • captive portal opened in your browser" {
+		t.Errorf("message = %q", m.wifiMessage)
+	}
+}
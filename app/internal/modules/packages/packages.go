@@ -1,15 +1,25 @@
 package packages
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/export"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -21,6 +31,7 @@ type PackageManager struct {
 	Installed   bool
 	Version     string
 	PackageCount int
+	Packages     []string // installed package names, for global search
 	Outdated    int
 	CacheSize   string
 }
@@ -28,25 +39,33 @@ type PackageManager struct {
 // Model represents the packages module state
 type Model struct {
 	config        *config.Config
+	runner        runner.Runner
 	width         int
 	height        int
 	managers      []PackageManager
 	cursor        int
 	loading       bool
+	detecting     components.Progress // over detectSteps
+	detectStep    string              // what detection is doing now
 	executing     bool
+	executedAt    time.Time // when the running action started
 	output        string
 	message       string
 	showingList   bool
 	showingOutput bool
 	packageList   []string
 	listScroll    int
-	searchFilter  string
+	listView      components.ScrollView
+	rows          components.RowHits // manager or package rows from the last render, for clicks
+	listFilter    components.ListFilter
+	pendingFilter string // applied to the next package list, set by global search
 }
 
 // New creates a new packages module
 func New(cfg *config.Config) *Model {
 	return &Model{
 		config:  cfg,
+		runner:  runner.Default,
 		loading: true,
 	}
 }
@@ -56,6 +75,25 @@ func (m *Model) Init() tea.Cmd {
 	return m.detectManagers()
 }
 
+// StaleAfter keeps the detected managers and package lists across tab
+// switches for half an hour; R detects them again sooner
+func (m *Model) StaleAfter() time.Duration {
+	return 30 * time.Minute
+}
+
+// Schedule redraws the detection progress and the running action's elapsed
+// time every second
+func (m *Model) Schedule() []scheduler.Task {
+	return []scheduler.Task{{Name: "progress", Every: time.Second, Run: m.progressTick}}
+}
+
+func (m *Model) progressTick() tea.Cmd {
+	if !m.loading && !m.executing {
+		return nil
+	}
+	return func() tea.Msg { return progressTickMsg{} }
+}
+
 // Update handles messages
 func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -63,6 +101,26 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case events.Click:
+		if row, ok := m.rows.Row(msg.Y); ok && !m.showingOutput {
+			if m.showingList {
+				m.listScroll = row
+			} else {
+				m.cursor = row
+			}
+		}
+
+	case events.Nav:
+		switch {
+		case m.showingOutput:
+			m.showingOutput = false
+			m.output = ""
+		case m.showingList:
+			m.listScroll = msg.Move(m.listScroll, len(m.getFilteredPackages()))
+		case !m.loading && !m.executing:
+			m.cursor = msg.Move(m.cursor, len(m.managers))
+		}
+
 	case tea.KeyMsg:
 		// Handle output screen - any key dismisses
 		if m.showingOutput {
@@ -73,35 +131,27 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 
 		// Handle package list modal separately
 		if m.showingList {
-			key := strings.ToLower(msg.String())
-			switch key {
-			case "esc", "q":
+			switch msg.String() {
+			case "esc":
 				m.showingList = false
 				m.packageList = nil
 				m.listScroll = 0
-				m.searchFilter = ""
+				m.listFilter.Reset()
 				return m, nil
 
-			case "up", "k":
-				if m.listScroll > 0 {
-					m.listScroll--
-				}
-
-			case "down", "j":
-				if m.listScroll < len(m.getFilteredPackages())-1 {
-					m.listScroll++
-				}
-
-			case "backspace":
-				if len(m.searchFilter) > 0 {
-					m.searchFilter = m.searchFilter[:len(m.searchFilter)-1]
-					m.listScroll = 0
+			case "pgup", "pgdown":
+				// Page the cursor; the list scrolls to keep it in view
+				page := m.listView.PageSize()
+				if msg.String() == "pgup" {
+					page = -page
 				}
+				m.listScroll = max(0, min(m.listScroll+page, len(m.getFilteredPackages())-1))
+				return m, nil
 
 			default:
-				// Add character to search filter
-				if len(msg.String()) == 1 && msg.String() >= " " {
-					m.searchFilter += msg.String()
+				// Every other key edits the filter; Enter keeps the query
+				// so y copies the highlighted package
+				if m.listFilter.HandleKey(msg) {
 					m.listScroll = 0
 				}
 			}
@@ -115,16 +165,6 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		key := strings.ToLower(msg.String())
 
 		switch key {
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-
-		case "down", "j":
-			if m.cursor < len(m.managers)-1 {
-				m.cursor++
-			}
-
 		case "c":
 			// Cleanup cache for current manager
 			if m.cursor < len(m.managers) && m.managers[m.cursor].Installed {
@@ -157,6 +197,11 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			return m, m.detectManagers()
 		}
 
+	case detectProgressMsg:
+		m.detecting.Set(int64(msg.done))
+		m.detectStep = msg.next
+		return m, waitForDetectStep(msg.run)
+
 	case detectCompleteMsg:
 		m.managers = msg.managers
 		m.loading = false
@@ -173,9 +218,15 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.output = msg.output
 		m.message = msg.message
 		m.showingOutput = true
+		return m, components.ResultToast(msg.message, showOutputMsg{})
+
+	case showOutputMsg:
+		// The output stays up until dismissed, so it's still there
+		m.showingOutput = m.output != ""
 
 	case actionStartMsg:
 		m.executing = true
+		m.executedAt = time.Now()
 		m.output = msg.message
 		m.message = msg.message
 
@@ -184,8 +235,20 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.showingList = true
 		m.packageList = msg.packages
 		m.listScroll = 0
-		m.searchFilter = ""
+		m.listFilter.Start()
+		m.listFilter.SetQuery(m.pendingFilter)
+		m.pendingFilter = ""
 		m.message = fmt.Sprintf("Loaded %d packages from %s", len(msg.packages), msg.manager)
+
+	case showPackageMsg:
+		for i, mgr := range m.managers {
+			if mgr.Binary == msg.manager && mgr.Installed {
+				m.cursor = i
+				m.showingOutput = false
+				m.pendingFilter = msg.name
+				return m, m.listPackages()
+			}
+		}
 	}
 
 	return m, nil
@@ -193,6 +256,7 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 
 // View renders the module
 func (m *Model) View() string {
+	m.rows.Reset()
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
 	}
@@ -209,35 +273,41 @@ func (m *Model) View() string {
 }
 
 func (m *Model) renderLoading() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("📦 PACKAGE MANAGEMENT"))
 	b.WriteString("\n\n")
-	b.WriteString("⏳ Detecting package managers...\n")
+	b.WriteString("⏳ Detecting package managers...\n\n")
+	b.WriteString(m.detecting.View(min(m.width-4, 72), time.Now()))
+	b.WriteString("\n")
+	if m.detectStep != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorMuted).Render(m.detectStep + "..."))
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
 
 func (m *Model) renderManagers() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
-	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00D9FF")).Bold(true)
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#DDD"))
-	grayStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-	msgStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#0FD976"))
-	controlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	grayStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	msgStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("📦 PACKAGE MANAGEMENT"))
 	b.WriteString("\n\n")
 
 	if m.executing {
-		b.WriteString("⏳ Executing command...\n\n")
+		b.WriteString(fmt.Sprintf("⏳ Executing command... %s\n\n", time.Since(m.executedAt).Round(time.Second)))
 		if m.output != "" {
 			// Show output in a box
 			outputBox := lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#444")).
+				BorderForeground(components.ColorFaint).
 				Padding(1).
 				Width(m.width - 4).
 				Render(m.output)
@@ -247,6 +317,7 @@ func (m *Model) renderManagers() string {
 	}
 
 	for i, mgr := range m.managers {
+		m.rows.Add(components.NextLine(b.String()), i)
 		status := "✗ Not Installed"
 		statusStyle := grayStyle
 		if mgr.Installed {
@@ -295,6 +366,7 @@ func (m *Model) renderManagers() string {
 
 		b.WriteString("\n")
 	}
+	m.rows.End(components.NextLine(b.String()))
 
 	// Controls
 	b.WriteString(controlStyle.Render("↑/↓ Navigate • C/L/O/U Actions • R Refresh"))
@@ -313,14 +385,136 @@ func (m *Model) Title() string {
 	return "Packages"
 }
 
+// Help describes the package manager keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "PACKAGES",
+		Description: "Homebrew, npm and other package managers found on this Mac.",
+		Sections: []help.Section{
+			{Title: "Managers", Bindings: []help.Binding{
+				{Key: "↑/↓", Desc: "Navigate package managers"},
+				{Key: "L", Desc: "List installed packages"},
+				{Key: "O", Desc: "Show outdated packages"},
+				{Key: "U", Desc: "Update the package manager"},
+				{Key: "C", Desc: "Clean its cache"},
+				{Key: "R", Desc: "Detect package managers again"},
+			}},
+			{Title: "Package list", Bindings: []help.Binding{
+				{Key: "Type", Desc: "Filter packages"},
+				{Key: "PgUp / PgDn", Desc: "Move a page (Home / End jump to either end)"},
+				{Key: "Enter", Desc: "Keep the filter"},
+				{Key: "y", Desc: "Copy the highlighted package (after Enter)"},
+				{Key: "Esc", Desc: "Close the list"},
+			}},
+		},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
 func (m *Model) HasOpenModal() bool {
 	return m.showingList || m.showingOutput
 }
 
+// Copyable returns the last command's output or the highlighted package
+func (m *Model) Copyable() string {
+	switch {
+	case m.showingOutput:
+		return strings.TrimSpace(m.output)
+	case m.showingList:
+		filtered := m.getFilteredPackages()
+		if m.listFilter.Typing() || m.listScroll >= len(filtered) {
+			return ""
+		}
+		return filtered[m.listScroll]
+	}
+	return ""
+}
+
+// Export lists the detected package managers and their packages
+func (m *Model) Export() string {
+	var rows [][]string
+	for _, mgr := range m.managers {
+		if mgr.Installed {
+			rows = append(rows, []string{mgr.Name, mgr.Version, fmt.Sprint(mgr.PackageCount), fmt.Sprint(mgr.Outdated), mgr.CacheSize})
+		}
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(export.Table([]string{"Manager", "Version", "Packages", "Outdated", "Cache"}, rows))
+	for _, mgr := range m.managers {
+		if len(mgr.Packages) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s packages\n\n", mgr.Name)
+		for _, pkg := range mgr.Packages {
+			b.WriteString("- " + pkg + "\n")
+		}
+	}
+	return b.String()
+}
+
+// SearchItems returns installed packages for global search
+func (m *Model) SearchItems() []palette.Command {
+	var items []palette.Command
+	for _, mgr := range m.managers {
+		for _, pkg := range mgr.Packages {
+			items = append(items, palette.Command{
+				Title: pkg,
+				Hint:  mgr.Name + " package",
+				Msg:   showPackageMsg{manager: mgr.Binary, name: pkg},
+			})
+		}
+	}
+	return items
+}
+
+// savedState is what the module keeps between launches
+type savedState struct {
+	Cursor int `json:"cursor"`
+}
+
+// SaveState returns the selected package manager position
+func (m *Model) SaveState() (json.RawMessage, error) {
+	return json.Marshal(savedState{Cursor: m.cursor})
+}
+
+// RestoreState applies state saved by the previous launch
+func (m *Model) RestoreState(data json.RawMessage) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	m.cursor = saved.Cursor
+	return nil
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Rescan package managers", Hint: "Detect Homebrew and npm, count packages", Msg: palette.Key("r")},
+	}
+}
+
+// detectSteps is how many checks detection runs: five for Homebrew and
+// four for npm
+const detectSteps = 9
+
+// detectManagers checks each package manager in the background, reporting
+// every step so the loading screen can show progress
 func (m *Model) detectManagers() tea.Cmd {
-	return func() tea.Msg {
-		var managers []PackageManager
+	m.detecting = components.NewProgress(detectSteps)
+	m.detectStep = "Looking for Homebrew"
+
+	run := &detectRun{updates: make(chan detectProgressMsg, detectSteps)}
+	go func() {
+		done := 0
+		step := func(n int, next string) {
+			done += n
+			run.updates <- detectProgressMsg{done: done, next: next}
+		}
 
 		// Check Homebrew
 		brew := PackageManager{
@@ -328,32 +522,63 @@ func (m *Model) detectManagers() tea.Cmd {
 			Binary: "brew",
 		}
 
-		if checkBinary("brew", 2*time.Second) {
+		if m.checkBinary("brew", 2*time.Second) {
+			step(1, "Reading the Homebrew version")
 			brew.Installed = true
-			brew.Version = getBrewVersion()
-			brew.PackageCount = getBrewPackageCount()
-			brew.Outdated = getBrewOutdatedCount()
-			brew.CacheSize = getBrewCacheSize()
+			brew.Version = m.getBrewVersion()
+			step(1, "Listing Homebrew formulae")
+			brew.Packages = m.getBrewPackages()
+			brew.PackageCount = len(brew.Packages)
+			step(1, "Checking for outdated formulae")
+			brew.Outdated = m.getBrewOutdatedCount()
+			step(1, "Measuring the Homebrew cache")
+			brew.CacheSize = m.getBrewCacheSize()
+			step(1, "Looking for npm")
+		} else {
+			step(5, "Looking for npm")
 		}
 
-		managers = append(managers, brew)
-
 		// Check npm
 		npm := PackageManager{
 			Name:   "npm",
 			Binary: "npm",
 		}
 
-		if checkBinary("npm", 2*time.Second) {
+		if m.checkBinary("npm", 2*time.Second) {
+			step(1, "Reading the npm version")
 			npm.Installed = true
-			npm.Version = getNpmVersion()
-			npm.PackageCount = getNpmGlobalCount()
-			npm.CacheSize = getNpmCacheSize()
+			npm.Version = m.getNpmVersion()
+			step(1, "Listing global npm packages")
+			npm.Packages = m.getNpmGlobalPackages()
+			npm.PackageCount = len(npm.Packages)
+			step(1, "Measuring the npm cache")
+			npm.CacheSize = m.getNpmCacheSize()
 		}
 
-		managers = append(managers, npm)
+		run.managers = []PackageManager{brew, npm}
+		close(run.updates)
+	}()
+
+	return waitForDetectStep(run)
+}
+
+// detectRun carries detection progress; managers is set before updates
+// is closed
+type detectRun struct {
+	updates  chan detectProgressMsg
+	managers []PackageManager
+}
 
-		return detectCompleteMsg{managers: managers}
+// waitForDetectStep delivers the next finished step, or the managers once
+// detection is done
+func waitForDetectStep(run *detectRun) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-run.updates
+		if !ok {
+			return detectCompleteMsg{managers: run.managers}
+		}
+		update.run = run
+		return update
 	}
 }
 
@@ -378,7 +603,7 @@ func (m *Model) cleanupCache() tea.Cmd {
 		}
 
 		setCommandPath(cmd)
-		output, err := cmd.CombinedOutput()
+		output, err := m.runner.CombinedOutput(cmd)
 
 		if err != nil {
 			if ctx.Err() == context.DeadlineExceeded {
@@ -412,11 +637,14 @@ func (m *Model) listPackages() tea.Cmd {
 			defer cancel()
 
 			var cmd *exec.Cmd
+			var parse func([]byte) ([]string, error)
 			switch mgr.Binary {
 			case "brew":
-				cmd = exec.CommandContext(ctx, "brew", "list", "--versions")
+				cmd = exec.CommandContext(ctx, "brew", "info", "--json=v2", "--installed")
+				parse = parseBrewInfo
 			case "npm":
-				cmd = exec.CommandContext(ctx, "npm", "list", "-g", "--depth=0")
+				cmd = exec.CommandContext(ctx, "npm", "ls", "-g", "--depth=0", "--json")
+				parse = parseNpmList
 			default:
 				return actionCompleteMsg{
 					output:  "",
@@ -424,24 +652,31 @@ func (m *Model) listPackages() tea.Cmd {
 				}
 			}
 
+			// Only stdout carries the JSON document; npm exits non-zero on
+			// warnings such as extraneous packages but still prints it
 			setCommandPath(cmd)
-			output, err := cmd.CombinedOutput()
+			output, err := m.runner.Output(cmd)
 
-			if err != nil {
-				if ctx.Err() == context.DeadlineExceeded {
-					return actionCompleteMsg{
-						output:  string(output),
-						message: fmt.Sprintf("✗ Listing timed out after 10 seconds"),
-					}
-				}
+			if ctx.Err() == context.DeadlineExceeded {
 				return actionCompleteMsg{
 					output:  string(output),
+					message: fmt.Sprintf("✗ Listing timed out after 10 seconds"),
+				}
+			}
+			if err != nil && len(output) == 0 {
+				return actionCompleteMsg{
+					output:  "",
 					message: fmt.Sprintf("✗ Failed to list packages: %v", err),
 				}
 			}
 
-			// Parse package list
-			packages := parsePackageList(mgr.Binary, string(output))
+			packages, err := parse(output)
+			if err != nil {
+				return actionCompleteMsg{
+					output:  string(output),
+					message: fmt.Sprintf("✗ Failed to parse package list: %v", err),
+				}
+			}
 
 			return packageListMsg{
 				packages: packages,
@@ -463,11 +698,14 @@ func (m *Model) showOutdated() tea.Cmd {
 			defer cancel()
 
 			var cmd *exec.Cmd
+			var parse func([]byte) ([]string, error)
 			switch mgr.Binary {
 			case "brew":
-				cmd = exec.CommandContext(ctx, "brew", "outdated")
+				cmd = exec.CommandContext(ctx, "brew", "outdated", "--json=v2")
+				parse = parseBrewOutdated
 			case "npm":
-				cmd = exec.CommandContext(ctx, "npm", "outdated", "-g")
+				cmd = exec.CommandContext(ctx, "npm", "outdated", "-g", "--json")
+				parse = parseNpmOutdated
 			default:
 				return actionCompleteMsg{
 					output:  "",
@@ -475,29 +713,34 @@ func (m *Model) showOutdated() tea.Cmd {
 				}
 			}
 
+			// npm outdated exits 1 when it finds anything, so judge by output
 			setCommandPath(cmd)
-			output, err := cmd.CombinedOutput()
+			output, _ := m.runner.Output(cmd)
 
-			if err != nil && ctx.Err() != context.DeadlineExceeded {
-				// Command error is okay - might mean no outdated packages
-				if len(output) == 0 {
-					return actionCompleteMsg{
-						output:  "No outdated packages",
-						message: "✓ All packages are up to date",
-					}
+			if ctx.Err() == context.DeadlineExceeded {
+				return actionCompleteMsg{
+					output:  string(output),
+					message: "✗ Check timed out after 15 seconds",
 				}
 			}
 
-			if ctx.Err() == context.DeadlineExceeded {
+			outdated, err := parse(output)
+			if err != nil {
 				return actionCompleteMsg{
 					output:  string(output),
-					message: "✗ Check timed out after 15 seconds",
+					message: fmt.Sprintf("✗ Failed to parse outdated packages: %v", err),
+				}
+			}
+			if len(outdated) == 0 {
+				return actionCompleteMsg{
+					output:  "No outdated packages",
+					message: "✓ All packages are up to date",
 				}
 			}
 
 			return actionCompleteMsg{
-				output:  string(output),
-				message: fmt.Sprintf("Found %d outdated package(s)", mgr.Outdated),
+				output:  strings.Join(outdated, "\n"),
+				message: fmt.Sprintf("Found %d outdated package(s)", len(outdated)),
 			}
 		},
 	)
@@ -528,7 +771,7 @@ func (m *Model) updateManager() tea.Cmd {
 			}
 
 			setCommandPath(cmd)
-			output, err := cmd.CombinedOutput()
+			output, err := m.runner.CombinedOutput(cmd)
 
 			if err != nil {
 				if ctx.Err() == context.DeadlineExceeded {
@@ -599,22 +842,22 @@ func setCommandPath(cmd *exec.Cmd) {
 	}
 }
 
-func checkBinary(name string, timeout time.Duration) bool {
+func (m *Model) checkBinary(name string, timeout time.Duration) bool {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "which", name)
-	err := cmd.Run()
+	_, err := m.runner.Output(cmd)
 	return err == nil
 }
 
-func getBrewVersion() string {
+func (m *Model) getBrewVersion() string {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "brew", "--version")
 	setCommandPath(cmd)
-	output, err := cmd.Output()
+	output, err := m.runner.Output(cmd)
 	if err != nil {
 		return "unknown"
 	}
@@ -631,48 +874,43 @@ func getBrewVersion() string {
 	return "unknown"
 }
 
-func getBrewPackageCount() int {
+func (m *Model) getBrewPackages() []string {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "brew", "list", "--formula")
 	setCommandPath(cmd)
-	output, err := cmd.Output()
+	output, err := m.runner.Output(cmd)
 	if err != nil {
-		return 0
+		return nil
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	count := 0
-	for _, line := range lines {
-		if strings.TrimSpace(line) != "" {
-			count++
+	var packages []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			packages = append(packages, name)
 		}
 	}
 
-	return count
+	return packages
 }
 
-func getBrewOutdatedCount() int {
+func (m *Model) getBrewOutdatedCount() int {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "brew", "outdated")
+	cmd := exec.CommandContext(ctx, "brew", "outdated", "--json=v2")
 	setCommandPath(cmd)
-	output, _ := cmd.Output()
+	output, _ := m.runner.Output(cmd)
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	count := 0
-	for _, line := range lines {
-		if strings.TrimSpace(line) != "" {
-			count++
-		}
+	outdated, err := parseBrewOutdated(output)
+	if err != nil {
+		return 0
 	}
-
-	return count
+	return len(outdated)
 }
 
-func getBrewCacheSize() string {
+func (m *Model) getBrewCacheSize() string {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
@@ -681,7 +919,7 @@ func getBrewCacheSize() string {
 
 	cmd := exec.CommandContext(ctx, "du", "-sh", cachePath)
 	setCommandPath(cmd)
-	output, err := cmd.Output()
+	output, err := m.runner.Output(cmd)
 	if err != nil {
 		return "unknown"
 	}
@@ -694,13 +932,13 @@ func getBrewCacheSize() string {
 	return "unknown"
 }
 
-func getNpmVersion() string {
+func (m *Model) getNpmVersion() string {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "npm", "--version")
 	setCommandPath(cmd)
-	output, err := cmd.Output()
+	output, err := m.runner.Output(cmd)
 	if err != nil {
 		return "unknown"
 	}
@@ -708,33 +946,22 @@ func getNpmVersion() string {
 	return strings.TrimSpace(string(output))
 }
 
-func getNpmGlobalCount() int {
+func (m *Model) getNpmGlobalPackages() []string {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "npm", "list", "-g", "--depth=0")
+	cmd := exec.CommandContext(ctx, "npm", "ls", "-g", "--depth=0", "--json")
 	setCommandPath(cmd)
-	output, _ := cmd.CombinedOutput()
+	output, _ := m.runner.Output(cmd)
 
-	lines := strings.Split(string(output), "\n")
-	count := 0
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Skip empty lines and header/footer lines
-		if line == "" || strings.HasPrefix(line, "/") || strings.Contains(line, "node_modules") {
-			continue
-		}
-		// Count lines that start with ├──, └── or contain @ version
-		if strings.HasPrefix(line, "├──") || strings.HasPrefix(line, "└──") ||
-		   (strings.Contains(line, "@") && !strings.HasPrefix(line, "npm")) {
-			count++
-		}
+	packages, err := parseNpmList(output)
+	if err != nil {
+		return nil
 	}
-
-	return count
+	return packages
 }
 
-func getNpmCacheSize() string {
+func (m *Model) getNpmCacheSize() string {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
@@ -743,7 +970,7 @@ func getNpmCacheSize() string {
 
 	cmd := exec.CommandContext(ctx, "du", "-sh", cachePath)
 	setCommandPath(cmd)
-	output, err := cmd.Output()
+	output, err := m.runner.Output(cmd)
 	if err != nil {
 		return "unknown"
 	}
@@ -761,6 +988,16 @@ type detectCompleteMsg struct {
 	managers []PackageManager
 }
 
+// detectProgressMsg reports done of detectSteps finished, and the next one
+type detectProgressMsg struct {
+	done int
+	next string
+	run  *detectRun
+}
+
+// progressTickMsg redraws the progress shown while detecting or executing
+type progressTickMsg struct{}
+
 type actionCompleteMsg struct {
 	output  string
 	message string
@@ -775,67 +1012,147 @@ type packageListMsg struct {
 	manager  string
 }
 
+// showPackageMsg opens a manager's package list filtered to one package
+type showPackageMsg struct {
+	manager string
+	name    string
+}
+
+// showOutputMsg shows the last action's output, from its toast
+type showOutputMsg struct{}
+
 // Helper functions for package list modal
 
 func (m *Model) getFilteredPackages() []string {
-	if m.searchFilter == "" {
-		return m.packageList
-	}
-
 	filtered := []string{}
-	searchLower := strings.ToLower(m.searchFilter)
-	for _, pkg := range m.packageList {
-		if strings.Contains(strings.ToLower(pkg), searchLower) {
-			filtered = append(filtered, pkg)
-		}
+	for _, i := range m.listFilter.Matches(m.packageList) {
+		filtered = append(filtered, m.packageList[i])
 	}
 	return filtered
 }
 
-func parsePackageList(binary, output string) []string {
-	packages := []string{}
-	lines := strings.Split(output, "\n")
+// brewInfo is the part of `brew info --json=v2 --installed` we read
+type brewInfo struct {
+	Formulae []struct {
+		Name      string `json:"name"`
+		Installed []struct {
+			Version string `json:"version"`
+		} `json:"installed"`
+	} `json:"formulae"`
+	Casks []struct {
+		Token     string `json:"token"`
+		Installed string `json:"installed"`
+	} `json:"casks"`
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+// brewOutdated is the output of `brew outdated --json=v2`
+type brewOutdated struct {
+	Formulae []brewOutdatedItem `json:"formulae"`
+	Casks    []brewOutdatedItem `json:"casks"`
+}
 
-		switch binary {
-		case "brew":
-			// Brew format: "package_name version"
-			if !strings.HasPrefix(line, "=") && !strings.Contains(line, "Formulae") {
-				packages = append(packages, line)
-			}
+type brewOutdatedItem struct {
+	Name              string   `json:"name"`
+	InstalledVersions []string `json:"installed_versions"`
+	CurrentVersion    string   `json:"current_version"`
+}
 
-		case "npm":
-			// npm format: various tree formats
-			// Skip header lines and paths
-			if strings.HasPrefix(line, "/") || strings.Contains(line, "node_modules") {
-				continue
-			}
-			// Remove tree characters
-			line = strings.TrimPrefix(line, "├── ")
-			line = strings.TrimPrefix(line, "└── ")
-			line = strings.TrimPrefix(line, "│   ")
-			line = strings.TrimSpace(line)
-
-			if line != "" && !strings.HasPrefix(line, "npm") {
-				packages = append(packages, line)
-			}
+// npmList is the output of `npm ls -g --depth=0 --json`
+type npmList struct {
+	Dependencies map[string]struct {
+		Version string `json:"version"`
+	} `json:"dependencies"`
+}
+
+// npmOutdatedItem is one entry of `npm outdated -g --json`, keyed by name
+type npmOutdatedItem struct {
+	Current string `json:"current"`
+	Wanted  string `json:"wanted"`
+	Latest  string `json:"latest"`
+}
+
+// parseBrewInfo returns "name version" for every installed formula and cask
+func parseBrewInfo(output []byte) ([]string, error) {
+	var info brewInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, err
+	}
+
+	packages := []string{}
+	for _, f := range info.Formulae {
+		versions := make([]string, 0, len(f.Installed))
+		for _, v := range f.Installed {
+			versions = append(versions, v.Version)
 		}
+		packages = append(packages, strings.TrimSpace(f.Name+" "+strings.Join(versions, " ")))
+	}
+	for _, c := range info.Casks {
+		packages = append(packages, strings.TrimSpace(c.Token+" "+c.Installed)+" (cask)")
 	}
+	return packages, nil
+}
 
-	return packages
+// parseBrewOutdated returns "name (installed) < current" for every
+// outdated formula and cask
+func parseBrewOutdated(output []byte) ([]string, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var outdated brewOutdated
+	if err := json.Unmarshal(output, &outdated); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, item := range append(outdated.Formulae, outdated.Casks...) {
+		lines = append(lines, fmt.Sprintf("%s (%s) < %s",
+			item.Name, strings.Join(item.InstalledVersions, ", "), item.CurrentVersion))
+	}
+	return lines, nil
+}
+
+// parseNpmList returns "name@version" for every global package, sorted
+func parseNpmList(output []byte) ([]string, error) {
+	var list npmList
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, err
+	}
+
+	packages := []string{}
+	for name, dep := range list.Dependencies {
+		packages = append(packages, name+"@"+dep.Version)
+	}
+	sort.Strings(packages)
+	return packages, nil
+}
+
+// parseNpmOutdated returns "name current → latest" for every outdated
+// global package, sorted
+func parseNpmOutdated(output []byte) ([]string, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var outdated map[string]npmOutdatedItem
+	if err := json.Unmarshal(output, &outdated); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for name, item := range outdated {
+		lines = append(lines, fmt.Sprintf("%s %s → %s", name, item.Current, item.Latest))
+	}
+	sort.Strings(lines)
+	return lines, nil
 }
 
 func (m *Model) renderPackageList() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF"))
-	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00D9FF")).Bold(true)
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#DDD"))
-	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-	searchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#0FD976"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	highlightStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	borderStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	searchStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
 
 	filtered := m.getFilteredPackages()
 
@@ -844,8 +1161,8 @@ func (m *Model) renderPackageList() string {
 	b.WriteString("\n\n")
 
 	// Search bar
-	if m.searchFilter != "" {
-		b.WriteString(searchStyle.Render(fmt.Sprintf("🔍 Filter: %s", m.searchFilter)))
+	if query := m.listFilter.Query(); query != "" {
+		b.WriteString(searchStyle.Render(fmt.Sprintf("🔍 Filter: %s", query)))
 	} else {
 		b.WriteString(borderStyle.Render("Type to filter packages..."))
 	}
@@ -865,49 +1182,45 @@ func (m *Model) renderPackageList() string {
 		m.listScroll = 0
 	}
 
-	// Calculate visible window
-	start := m.listScroll
-	end := start + maxVisible
-	if end > len(filtered) {
-		end = len(filtered)
-	}
-	if start > 0 && end-start < maxVisible {
-		start = end - maxVisible
-		if start < 0 {
-			start = 0
-		}
-	}
-
-	// Render visible packages
+	// Render packages, scrolled to keep the cursor in view
 	if len(filtered) == 0 {
 		b.WriteString(borderStyle.Render("No packages match your filter"))
 	} else {
-		for i := start; i < end; i++ {
-			cursor := "  "
-			if i == m.listScroll {
-				cursor = "▶ "
-			}
-
-			line := cursor + filtered[i]
+		lines := make([]string, len(filtered))
+		for i, name := range filtered {
 			if i == m.listScroll {
-				b.WriteString(highlightStyle.Render(line))
+				lines[i] = highlightStyle.Render("▶ " + name)
 			} else {
-				b.WriteString(normalStyle.Render(line))
+				lines[i] = normalStyle.Render("  " + name)
 			}
-			b.WriteString("\n")
 		}
+		m.listView.Height = maxVisible
+		m.listView.SetLines(lines)
+		m.listView.EnsureVisible(m.listScroll)
 
-		// Scroll indicator
-		if len(filtered) > maxVisible {
-			percentage := float64(m.listScroll) / float64(len(filtered)-1) * 100
+		top := components.NextLine(b.String())
+		start := m.listView.Offset()
+		end := min(start+maxVisible, len(filtered))
+		for i := start; i < end; i++ {
+			m.rows.Add(top+i-start, i)
+		}
+		m.rows.End(top + end - start)
+		b.WriteString(m.listView.View())
+		b.WriteString("\n")
+
+		if m.listView.Scrollable() {
 			b.WriteString("\n")
-			b.WriteString(borderStyle.Render(fmt.Sprintf("Showing %d-%d of %d (%.0f%%)", start+1, end, len(filtered), percentage)))
+			b.WriteString(borderStyle.Render(fmt.Sprintf("Showing %d-%d of %d", start+1, end, len(filtered))))
 		}
 	}
 
 	// Controls
 	b.WriteString("\n\n")
-	b.WriteString(borderStyle.Render("↑/↓ Scroll • Type to filter • Backspace Clear • Esc/Q Close"))
+	if m.listFilter.Typing() {
+		b.WriteString(borderStyle.Render("↑/↓ Scroll • Type to filter • Enter Keep • Ctrl+U Clear • Esc Close"))
+	} else {
+		b.WriteString(borderStyle.Render("↑/↓ PgUp/PgDn Scroll • y Copy • / Filter • Esc Close"))
+	}
 
 	return b.String()
 }
@@ -0,0 +1,272 @@
+package packages
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// brewFake returns a runner with recorded Homebrew output and no npm
+func brewFake(t *testing.T) *runner.Fake {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	fake := runner.NewFake().
+		Set("which brew", "/opt/homebrew/bin/brew\n", nil).
+		Set("which npm", "", errors.New("exit status 1"))
+
+	fixtures := map[string]string{
+		"brew --version":                                           "brew_version.txt",
+		"brew list --formula":                                      "brew_list_formula.txt",
+		"brew info --json=v2 --installed":                          "brew_info.json",
+		"brew outdated --json=v2":                                  "brew_outdated.json",
+		"du -sh " + filepath.Join(home, "Library/Caches/Homebrew"): "du_brew_cache.txt",
+	}
+	for cmdline, file := range fixtures {
+		if err := fake.SetFixture(cmdline, filepath.Join("testdata", file)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fake
+}
+
+// detect runs detection to the end, delivering each progress step
+func detect(t *testing.T, m *Model) {
+	t.Helper()
+	cmd := m.detectManagers()
+	for {
+		switch msg := cmd().(type) {
+		case detectProgressMsg:
+			_, cmd = m.Update(msg)
+		case detectCompleteMsg:
+			m.Update(msg)
+			return
+		default:
+			t.Fatalf("detection returned %T", msg)
+		}
+	}
+}
+
+func TestDetectManagers(t *testing.T) {
+	m := New(nil)
+	m.runner = brewFake(t)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	cmd := m.detectManagers()
+	first, ok := cmd().(detectProgressMsg)
+	if !ok || first.done != 1 || first.next != "Reading the Homebrew version" {
+		t.Fatalf("first step = %+v", first)
+	}
+	_, cmd = m.Update(first)
+	if view := m.View(); !strings.Contains(view, " 11%") || !strings.Contains(view, "Reading the Homebrew version...") {
+		t.Errorf("loading screen:\n%s", view)
+	}
+	for done := false; !done; {
+		switch msg := cmd().(type) {
+		case detectProgressMsg:
+			_, cmd = m.Update(msg)
+		case detectCompleteMsg:
+			m.Update(msg)
+			done = true
+		}
+	}
+
+	if len(m.managers) != 2 {
+		t.Fatalf("got %d managers, want 2", len(m.managers))
+	}
+
+	brew := m.managers[0]
+	if brew.Name != "Homebrew" || !brew.Installed || brew.Version != "4.3.9" ||
+		brew.PackageCount != 6 || brew.Outdated != 2 || brew.CacheSize != "1.2G" {
+		t.Errorf("brew = %+v", brew)
+	}
+	if len(brew.Packages) != 6 || brew.Packages[0] != "ca-certificates" {
+		t.Errorf("brew packages = %q", brew.Packages)
+	}
+	if m.managers[1].Installed {
+		t.Error("npm should not be detected when `which npm` fails")
+	}
+	if m.loading {
+		t.Error("loading should be cleared after detection")
+	}
+	if m.message != "Found 1 package manager(s)" {
+		t.Errorf("message = %q", m.message)
+	}
+}
+
+func TestListPackages(t *testing.T) {
+	m := New(nil)
+	m.runner = brewFake(t)
+	m.managers = []PackageManager{{Name: "Homebrew", Binary: "brew", Installed: true}}
+
+	cmd := m.listPackages()
+	for _, c := range cmd().(tea.BatchMsg) {
+		m.Update(c())
+	}
+
+	if !m.showingList || !m.HasOpenModal() {
+		t.Fatal("package list should be open")
+	}
+	if len(m.packageList) != 7 || m.packageList[1] != "git 2.45.1" || m.packageList[6] != "iterm2 3.5.2 (cask)" {
+		t.Fatalf("packageList = %q", m.packageList)
+	}
+
+	for _, r := range "ssl" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := m.getFilteredPackages(); len(got) != 1 || got[0] != "openssl@3 3.3.1" {
+		t.Errorf("filtered = %q, want openssl only", got)
+	}
+	if got := m.Copyable(); got != "" {
+		t.Errorf("Copyable() = %q while typing, want y left to the filter", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.Copyable(); got != "openssl@3 3.3.1" || !m.showingList {
+		t.Errorf("Copyable() = %q after Enter, want the highlighted package", got)
+	}
+}
+
+func TestSearchShowsPackage(t *testing.T) {
+	m := New(nil)
+	m.runner = brewFake(t)
+	m.managers = []PackageManager{
+		{Name: "npm", Binary: "npm"},
+		{Name: "Homebrew", Binary: "brew", Installed: true, Packages: []string{"git", "openssl@3"}},
+	}
+
+	items := m.SearchItems()
+	if len(items) != 2 || items[1].Title != "openssl@3" || items[1].Hint != "Homebrew package" {
+		t.Fatalf("items = %+v", items)
+	}
+	_, cmd := m.Update(items[1].Msg)
+	for _, c := range cmd().(tea.BatchMsg) {
+		m.Update(c())
+	}
+
+	if m.cursor != 1 || !m.showingList {
+		t.Fatalf("cursor = %d showingList = %v, want Homebrew list open", m.cursor, m.showingList)
+	}
+	if got := m.getFilteredPackages(); len(got) != 1 || got[0] != "openssl@3 3.3.1" {
+		t.Errorf("filtered = %q, want openssl only", got)
+	}
+}
+
+func TestShowOutdatedBrew(t *testing.T) {
+	m := New(nil)
+	m.runner = brewFake(t)
+	m.managers = []PackageManager{{Name: "Homebrew", Binary: "brew", Installed: true}}
+
+	for _, c := range m.showOutdated()().(tea.BatchMsg) {
+		m.Update(c())
+	}
+
+	want := "git (2.45.1) < 2.45.2\ngo (1.22.4) < 1.22.5"
+	if m.output != want {
+		t.Errorf("output = %q, want %q", m.output, want)
+	}
+	if m.message != "Found 2 outdated package(s)" {
+		t.Errorf("message = %q", m.message)
+	}
+}
+
+func TestNpmJSON(t *testing.T) {
+	fake := runner.NewFake()
+	if err := fake.SetFixture("npm ls -g --depth=0 --json", filepath.Join("testdata", "npm_ls.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.SetFixture("npm outdated -g --json", filepath.Join("testdata", "npm_outdated.json")); err != nil {
+		t.Fatal(err)
+	}
+	m := New(nil)
+	m.runner = fake
+	m.managers = []PackageManager{{Name: "npm", Binary: "npm", Installed: true}}
+
+	if got := m.getNpmGlobalPackages(); len(got) != 3 {
+		t.Errorf("getNpmGlobalPackages() = %q, want 3 packages", got)
+	}
+
+	for _, c := range m.listPackages()().(tea.BatchMsg) {
+		m.Update(c())
+	}
+	want := []string{"corepack@0.28.0", "npm@10.8.1", "typescript@5.5.3"}
+	if len(m.packageList) != len(want) {
+		t.Fatalf("packageList = %q, want %q", m.packageList, want)
+	}
+	for i := range want {
+		if m.packageList[i] != want[i] {
+			t.Errorf("package %d = %q, want %q", i, m.packageList[i], want[i])
+		}
+	}
+
+	m.showingList = false
+	for _, c := range m.showOutdated()().(tea.BatchMsg) {
+		m.Update(c())
+	}
+	if m.output != "typescript 5.5.3 → 5.5.4" {
+		t.Errorf("outdated output = %q", m.output)
+	}
+}
+
+func TestParseErrorsAreReported(t *testing.T) {
+	if _, err := parseNpmList([]byte("├── typescript@5.5.3")); err == nil {
+		t.Error("parseNpmList should reject tree output")
+	}
+	if got, err := parseBrewOutdated(nil); err != nil || len(got) != 0 {
+		t.Errorf("parseBrewOutdated(empty) = %q, %v; want nothing", got, err)
+	}
+}
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := New(nil)
+			m.runner = brewFake(t)
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			detect(t, m)
+			golden.RequireEqual(t, m.View())
+		})
+	}
+}
+
+func TestPackageListPages(t *testing.T) {
+	m := New(nil)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 13})
+	m.showingList = true
+	for i := 0; i < 30; i++ {
+		m.packageList = append(m.packageList, fmt.Sprintf("pkg-%02d 1.0", i))
+	}
+	m.listFilter.Reset()
+
+	if view := m.View(); !strings.Contains(view, "pkg-00") || strings.Contains(view, "pkg-05") {
+		t.Fatalf("the list should open on its first page:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.listScroll != 4 {
+		t.Errorf("PgDn moved the cursor to %d, want 4", m.listScroll)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	view := m.View()
+	if !strings.Contains(view, "▶ pkg-08") || strings.Contains(view, "pkg-00") {
+		t.Errorf("the list should scroll to keep the cursor in view:\n%s", view)
+	}
+	if !strings.Contains(view, "Showing 5-9 of 30") {
+		t.Errorf("view should say which packages are shown:\n%s", view)
+	}
+
+	m.Update(events.NavBottom)
+	if view := m.View(); !strings.Contains(view, "▶ pkg-29") {
+		t.Errorf("End should select the last package:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.listScroll != 25 {
+		t.Errorf("PgUp moved the cursor to %d, want 25", m.listScroll)
+	}
+}
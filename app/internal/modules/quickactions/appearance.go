@@ -0,0 +1,339 @@
+package quickactions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// interfaceStyle is set to "Dark" in dark mode and missing in light mode
+var interfaceStyle = defaults.Key{Domain: "-g", Name: "AppleInterfaceStyle", Type: defaults.String}
+
+// wallpaperExtensions are the image types the wallpaper rotation picks up
+var wallpaperExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".heic": true, ".tif": true, ".tiff": true,
+}
+
+// statesMsg carries the current state of the actions that report one,
+// keyed by action name
+type statesMsg map[string]string
+
+// readStates reads the state of every action that has a State func
+func (m *Model) readStates() tea.Cmd {
+	var actions []Action
+	for _, action := range m.actions {
+		if action.State != nil {
+			actions = append(actions, action)
+		}
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		states := statesMsg{}
+		for _, action := range actions {
+			states[action.Name] = action.State()
+		}
+		return states
+	}
+}
+
+func (m *Model) darkModeState() string {
+	value, err := defaults.Read(m.runner, interfaceStyle)
+	switch {
+	case err != nil:
+		return "unknown"
+	case value.Set && value.Text == "Dark":
+		return "Dark"
+	default:
+		return "Light"
+	}
+}
+
+func (m *Model) toggleDarkMode() error {
+	before := m.darkModeState()
+	dark := before != "Dark"
+	if err := m.setDarkMode(dark); err != nil {
+		return err
+	}
+	after := "Light"
+	if dark {
+		after = "Dark"
+	}
+	changes.Record(changes.Change{
+		Source: m.Title(),
+		What:   "Appearance",
+		Before: before,
+		After:  after,
+		Revert: func() error { return m.setDarkMode(!dark) },
+	})
+	return nil
+}
+
+func (m *Model) setDarkMode(dark bool) error {
+	args := darkModeArgs(dark)
+	if output, err := m.runner.CombinedOutput(exec.Command(args[0], args[1:]...)); err != nil {
+		return fmt.Errorf("osascript: %s", firstLine(output, err))
+	}
+	return nil
+}
+
+func darkModeArgs(dark bool) []string {
+	return []string{"osascript", "-e", fmt.Sprintf(`tell application "System Events" to tell appearance preferences to set dark mode to %t`, dark)}
+}
+
+// Night Shift has no built-in command line switch; the nightlight CLI
+// (brew install smudge/smudge/nightlight) drives the same private API as
+// System Settings. True Tone has no scriptable switch at all.
+func (m *Model) nightShiftState() string {
+	if _, err := m.runner.LookPath("nightlight"); err != nil {
+		return "needs nightlight"
+	}
+	output, err := m.runner.Output(exec.Command("nightlight", "status"))
+	if err != nil {
+		return "unknown"
+	}
+	for _, field := range strings.Fields(strings.ToLower(string(output))) {
+		switch field {
+		case "on":
+			return "On"
+		case "off":
+			return "Off"
+		}
+	}
+	return "unknown"
+}
+
+func (m *Model) toggleNightShift() error {
+	if _, err := m.runner.LookPath("nightlight"); err != nil {
+		return fmt.Errorf("nightlight not found: brew install smudge/smudge/nightlight")
+	}
+	if output, err := m.runner.CombinedOutput(exec.Command("nightlight", "toggle")); err != nil {
+		return fmt.Errorf("nightlight: %s", firstLine(output, err))
+	}
+	return nil
+}
+
+// defaultFocusShortcut is the shortcut Toggle Do Not Disturb runs when
+// the config names none
+const defaultFocusShortcut = "Toggle Do Not Disturb"
+
+// focusAssertions records the Focus turned on by hand, from Control
+// Center or a shortcut; scheduled ones aren't in it
+var focusAssertions = filepath.Join("Library", "DoNotDisturb", "DB", "Assertions.json")
+
+// Focus has no command line switch since macOS 12. A shortcut with the
+// Set Focus action can toggle it, and the shortcuts CLI runs shortcuts.
+func (m *Model) focusShortcut() string {
+	if m.config != nil && m.config.Modules.QuickActions.FocusShortcut != "" {
+		return m.config.Modules.QuickActions.FocusShortcut
+	}
+	return defaultFocusShortcut
+}
+
+func (m *Model) doNotDisturbState() string {
+	home, _ := os.UserHomeDir()
+	data, err := os.ReadFile(filepath.Join(home, focusAssertions))
+	if errors.Is(err, os.ErrNotExist) {
+		return "Off"
+	}
+	if err != nil {
+		return "unknown" // reading it needs Full Disk Access
+	}
+	var db struct {
+		Data []struct {
+			Records []json.RawMessage `json:"storeAssertionRecords"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return "unknown"
+	}
+	for _, d := range db.Data {
+		if len(d.Records) > 0 {
+			return "On"
+		}
+	}
+	return "Off"
+}
+
+func (m *Model) toggleDoNotDisturb() error {
+	before := m.doNotDisturbState()
+	if err := m.runFocusShortcut(); err != nil {
+		return err
+	}
+	after := "toggled"
+	switch before {
+	case "On":
+		after = "Off"
+	case "Off":
+		after = "On"
+	}
+	changes.Record(changes.Change{
+		Source: m.Title(),
+		What:   "Do Not Disturb",
+		Before: before,
+		After:  after,
+		Revert: m.runFocusShortcut,
+	})
+	return nil
+}
+
+// runFocusShortcut runs the Focus shortcut, explaining how to create it
+// when it's missing
+func (m *Model) runFocusShortcut() error {
+	name := m.focusShortcut()
+	if _, err := m.runner.LookPath("shortcuts"); err != nil {
+		return fmt.Errorf("the shortcuts command needs macOS 12 or later")
+	}
+	output, err := m.runner.Output(exec.Command("shortcuts", "list"))
+	if err != nil {
+		return fmt.Errorf("shortcuts: %s", firstLine(output, err))
+	}
+	found := false
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) == name {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("no shortcut named %q: create it in Shortcuts with a Set Focus action that toggles Do Not Disturb", name)
+	}
+	if output, err := m.runner.CombinedOutput(exec.Command("shortcuts", "run", name)); err != nil {
+		return fmt.Errorf("shortcuts: %s", firstLine(output, err))
+	}
+	return nil
+}
+
+// wallpaperDir is the folder the wallpaper rotation cycles through
+func (m *Model) wallpaperDir() string {
+	if m.config != nil && m.config.Modules.QuickActions.WallpaperDir != "" {
+		return expandHome(m.config.Modules.QuickActions.WallpaperDir)
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "Pictures", "Wallpapers")
+}
+
+// currentWallpaper returns the picture of the desktop in front
+func (m *Model) currentWallpaper() (string, error) {
+	script := `tell application "System Events" to get picture of current desktop`
+	output, err := m.runner.Output(exec.Command("osascript", "-e", script))
+	if err != nil {
+		return "", fmt.Errorf("osascript: %s", firstLine(output, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (m *Model) wallpaperState() string {
+	current, err := m.currentWallpaper()
+	if err != nil || current == "" {
+		return "unknown"
+	}
+	return filepath.Base(current)
+}
+
+// nextWallpaper sets every desktop to the image after the current one in
+// the wallpaper folder, in name order, wrapping around at the end
+func (m *Model) nextWallpaper() error {
+	next, current, err := m.wallpaperAfter()
+	if err != nil {
+		return err
+	}
+	if err := m.setWallpaper(next); err != nil {
+		return err
+	}
+	change := changes.Change{Source: m.Title(), What: "Wallpaper", Before: changes.NotSet, After: filepath.Base(next)}
+	if current != "" {
+		change.Before = filepath.Base(current)
+		change.Revert = func() error { return m.setWallpaper(current) }
+	}
+	changes.Record(change)
+	return nil
+}
+
+// wallpaperAfter returns the image after the current one, and the current
+// one when it can be read
+func (m *Model) wallpaperAfter() (next, current string, err error) {
+	dir := m.wallpaperDir()
+	images, err := wallpapers(dir)
+	if err != nil {
+		return "", "", err
+	}
+	if len(images) == 0 {
+		return "", "", fmt.Errorf("no images in %s", dir)
+	}
+
+	next = images[0]
+	current, err = m.currentWallpaper()
+	if err != nil {
+		return next, "", nil
+	}
+	for i, image := range images {
+		if image == current {
+			next = images[(i+1)%len(images)]
+			break
+		}
+	}
+	return next, current, nil
+}
+
+// setWallpaper sets the picture of every desktop
+func (m *Model) setWallpaper(path string) error {
+	args := wallpaperArgs(path)
+	if output, err := m.runner.CombinedOutput(exec.Command(args[0], args[1:]...)); err != nil {
+		return fmt.Errorf("osascript: %s", firstLine(output, err))
+	}
+	return nil
+}
+
+func wallpaperArgs(path string) []string {
+	// The path is passed as an argument so it needs no AppleScript quoting
+	return []string{"osascript",
+		"-e", "on run argv",
+		"-e", `tell application "System Events" to tell every desktop to set picture to item 1 of argv`,
+		"-e", "end run",
+		path}
+}
+
+// wallpapers lists the images in dir, sorted by name
+func wallpapers(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("wallpaper folder: %w", err)
+	}
+	var images []string
+	for _, entry := range entries {
+		if !entry.IsDir() && wallpaperExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			images = append(images, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
+
+// firstLine is the first line of a command's output, or err when it printed
+// nothing
+func firstLine(output []byte, err error) string {
+	text := strings.TrimSpace(string(output))
+	if text == "" {
+		return err.Error()
+	}
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,207 @@
+package quickactions
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// CommandLines returns the command lines the selected action runs, those
+// undoing the selected run in the History view, or the kill commands for
+// the picked processes. Commands that only need
+// root when the first try fails are listed with sudo, and fallbacks tried
+// after a failure aren't listed.
+func (m *Model) CommandLines() []string {
+	if m.filter.Typing() || m.perms != nil {
+		return nil
+	}
+	if m.history != nil {
+		if run, ok := m.history.selected(); ok && len(run.Defaults) > 0 {
+			return undoCommands(run)
+		}
+		return nil
+	}
+	if m.procs != nil {
+		var commands [][]string
+		for _, proc := range m.procs.targets() {
+			commands = append(commands, signalArgs(proc, "TERM"))
+		}
+		return quoteAll(commands...)
+	}
+	visible := m.visible()
+	if m.actionIndex >= len(visible) {
+		return nil
+	}
+	action := m.actions[visible[m.actionIndex]]
+	switch {
+	case action.CommandLines != nil:
+		return action.CommandLines()
+	case action.Plan != nil:
+		plan, err := action.Plan()
+		if err != nil {
+			return nil
+		}
+		return plan.Commands
+	}
+	return nil
+}
+
+// lines returns a Commands func for actions that always run the same
+// commands
+func lines(commands ...[]string) func() []string {
+	return func() []string {
+		return quoteAll(commands...)
+	}
+}
+
+func quoteAll(commands ...[]string) []string {
+	quoted := make([]string, len(commands))
+	for i, args := range commands {
+		quoted[i] = runner.Quote(args...)
+	}
+	return quoted
+}
+
+func sudo(args ...string) []string {
+	return append([]string{"sudo"}, args...)
+}
+
+func killall(process string) []string {
+	return []string{"killall", process}
+}
+
+// Commands shared by several built-in actions
+var (
+	flushDNSCommands = [][]string{sudo("dscacheutil", "-flushcache"), sudo("killall", "-HUP", "mDNSResponder")}
+	wifiCommands     = [][]string{
+		{"networksetup", "-setairportpower", "Wi-Fi", "off"},
+		{"sleep", "2"},
+		{"networksetup", "-setairportpower", "Wi-Fi", "on"},
+	}
+)
+
+func fixTimeMachineCommands() []string {
+	home, _ := os.UserHomeDir()
+	return quoteAll(
+		[]string{"tmutil", "status"},
+		[]string{"tmutil", "destinationinfo"},
+		[]string{"rm", filepath.Join(home, "Library/Preferences/com.apple.TimeMachine.plist")},
+		[]string{"tmutil", "startbackup", "-b"},
+	)
+}
+
+func resetNetworkCommands() []string {
+	commands := append(append([][]string{}, flushDNSCommands...), wifiCommands...)
+	return quoteAll(append(commands, []string{"networksetup", "-setdhcp", "Wi-Fi"})...)
+}
+
+func disableAnimationsCommands() []string {
+	var commands [][]string
+	for _, s := range animationSettings {
+		commands = append(commands, defaults.WriteArgs(s.key, s.value))
+	}
+	return quoteAll(append(commands, killall("Dock"))...)
+}
+
+// restoreAnimationsCommands undo the last Disable Animations, as
+// restoreAnimations does
+func (m *Model) restoreAnimationsCommands() []string {
+	runs, _ := loadHistory(m.historyPath())
+	for _, run := range runs {
+		if run.Action == "Disable Animations" && len(run.Defaults) > 0 {
+			return undoCommands(run)
+		}
+	}
+	var commands [][]string
+	for _, s := range animationSettings {
+		commands = append(commands, defaults.DeleteArgs(s.key))
+	}
+	return quoteAll(append(commands, killall("Dock"))...)
+}
+
+// undoCommands put back the values a run replaced, as undoRun does
+func undoCommands(run Run) []string {
+	var commands [][]string
+	for i := len(run.Defaults) - 1; i >= 0; i-- {
+		d := run.Defaults[i]
+		commands = append(commands, defaults.RestoreArgs(d.key(), defaults.Value{Text: d.Value, Set: d.Set}))
+	}
+	if run.Restart != "" {
+		commands = append(commands, killall(run.Restart))
+	}
+	return quoteAll(commands...)
+}
+
+func (m *Model) darkModeCommands() []string {
+	return quoteAll(darkModeArgs(m.darkModeState() != "Dark"))
+}
+
+func (m *Model) doNotDisturbCommands() []string {
+	return quoteAll([]string{"shortcuts", "run", m.focusShortcut()})
+}
+
+func (m *Model) nextWallpaperCommands() []string {
+	next, _, err := m.wallpaperAfter()
+	if err != nil {
+		return nil
+	}
+	return quoteAll(wallpaperArgs(next))
+}
+
+func (m *Model) dnsCommands(p dnsPreset) []string {
+	service, err := m.activeNetworkService()
+	if err != nil {
+		return nil
+	}
+	return quoteAll(dnsArgs(service, p.Servers))
+}
+
+func (m *Model) hiddenFilesCommands() []string {
+	show := strconv.FormatBool(m.boolDefaultState(showAllFiles) != "On")
+	return quoteAll(defaults.WriteArgs(showAllFiles, show), killall("Finder"))
+}
+
+func (m *Model) safariDevelopCommands() []string {
+	on := strconv.FormatBool(m.boolDefaultState(safariDevelop[0]) != "On")
+	commands := make([][]string, len(safariDevelop))
+	for i, key := range safariDevelop {
+		commands[i] = defaults.WriteArgs(key, on)
+	}
+	return quoteAll(commands...)
+}
+
+func restartCommands(s uiService) []string {
+	if s.Session {
+		return quoteAll(sudo("killall", "-HUP", s.Process))
+	}
+	return quoteAll(killall(s.Process))
+}
+
+// presetCommands apply p, or put back what it replaced when it's on, as
+// togglePreset does
+func (m *Model) presetCommands(p preset) []string {
+	var commands [][]string
+	if m.presetState(p) == "On" {
+		m.presetMu.Lock()
+		before, ok := m.presetSaved[p.Name]
+		m.presetMu.Unlock()
+		for i, s := range p.Settings {
+			value := defaults.Value{}
+			if ok {
+				value = before[i]
+			}
+			commands = append(commands, defaults.RestoreArgs(s.Key, value))
+		}
+	} else {
+		for _, s := range p.Settings {
+			commands = append(commands, defaults.WriteArgs(s.Key, s.Value))
+		}
+	}
+	if p.Restart != "" {
+		commands = append(commands, killall(p.Restart))
+	}
+	return quoteAll(commands...)
+}
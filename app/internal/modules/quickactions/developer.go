@@ -0,0 +1,208 @@
+package quickactions
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+)
+
+// showAllFiles makes Finder show dot files and other hidden files
+var showAllFiles = defaults.Key{Domain: "com.apple.finder", Name: "AppleShowAllFiles", Type: defaults.Bool}
+
+// safariDevelop are the keys behind Safari's Develop menu: the menu itself
+// and the Web Inspector it opens
+var safariDevelop = []defaults.Key{
+	{Domain: "com.apple.Safari", Name: "IncludeDevelopMenu", Type: defaults.Bool},
+	{Domain: "com.apple.Safari", Name: "WebKitDeveloperExtrasEnabledPreferenceKey", Type: defaults.Bool},
+	{Domain: "com.apple.Safari", Name: "com.apple.Safari.ContentPageGroupIdentifier.WebKit2DeveloperExtrasEnabled", Type: defaults.Bool},
+}
+
+// Where Switch Xcode looks for developer folders; variables for tests
+var (
+	xcodeApps        = "/Applications/Xcode*.app"
+	commandLineTools = "/Library/Developer/CommandLineTools"
+)
+
+// developerActions returns the onboarding chores of a new Mac for
+// development, a keypress each
+func (m *Model) developerActions() []Action {
+	return []Action{
+		{
+			Name:         "Toggle Hidden Files",
+			Description:  "Show or hide dot files in Finder",
+			Category:     "Developer",
+			Command:      m.toggleHiddenFiles,
+			State:        func() string { return m.boolDefaultState(showAllFiles) },
+			CommandLines: m.hiddenFilesCommands,
+			Restart:      "Finder",
+		},
+		{
+			Name:         "Toggle Safari Develop Menu",
+			Description:  "Show Safari's Develop menu and Web Inspector",
+			Category:     "Developer",
+			Command:      m.toggleSafariDevelop,
+			State:        func() string { return m.boolDefaultState(safariDevelop[0]) },
+			CommandLines: m.safariDevelopCommands,
+		},
+		{
+			Name:         "Switch Xcode",
+			Description:  "Point xcode-select at the next installed Xcode or the Command Line Tools",
+			Category:     "Developer",
+			Plan:         m.planSwitchXcode,
+			State:        m.xcodeState,
+			RequiresSudo: true,
+		},
+		{
+			Name:         "Reset iOS Simulator",
+			Description:  "Shut down every simulator and erase its content and settings",
+			Category:     "Developer",
+			Command:      m.resetSimulators,
+			CommandLines: lines(simctlCommands...),
+			Confirm:      "Every simulator device loses its installed apps, their data and its settings.",
+			Timeout:      longCommandTimeout,
+		},
+	}
+}
+
+// boolDefaultState is On when a boolean key is set to true
+func (m *Model) boolDefaultState(key defaults.Key) string {
+	value, err := defaults.Read(m.runner, key)
+	switch {
+	case err != nil:
+		return "unknown"
+	case value.Set && value.Text == "true":
+		return "On"
+	}
+	return "Off"
+}
+
+func (m *Model) toggleHiddenFiles() error {
+	show := m.boolDefaultState(showAllFiles) != "On"
+	restart := func() error { return m.restartProcess("Finder") }
+	if err := changes.WriteDefault(m.runner, m.Title(), showAllFiles, strconv.FormatBool(show), restart); err != nil {
+		return err
+	}
+	return restart()
+}
+
+// toggleSafariDevelop turns the Develop menu on or off. Safari's settings
+// live in its sandbox, which the terminal can only write to with Full Disk
+// Access.
+func (m *Model) toggleSafariDevelop() error {
+	on := strconv.FormatBool(m.boolDefaultState(safariDevelop[0]) != "On")
+	for _, key := range safariDevelop {
+		if err := changes.WriteDefault(m.runner, m.Title(), key, on, nil); err != nil {
+			return fmt.Errorf("%w (give your terminal Full Disk Access in System Settings › Privacy & Security)", err)
+		}
+	}
+	liveOutput.line("Safari picks this up the next time it opens.")
+	return nil
+}
+
+// xcodePath is the active developer folder, as xcode-select prints it
+func (m *Model) xcodePath() (string, error) {
+	output, err := m.runner.Output(exec.Command("xcode-select", "-p"))
+	if err != nil {
+		return "", fmt.Errorf("xcode-select: %s", firstLine(output, err))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (m *Model) xcodeState() string {
+	path, err := m.xcodePath()
+	if err != nil {
+		return "none"
+	}
+	return developerDirName(path)
+}
+
+// developerDirName names a developer folder by its app, e.g.
+// Xcode-beta.app, or CommandLineTools
+func developerDirName(path string) string {
+	if app, _, ok := strings.Cut(path, ".app/"); ok {
+		return filepath.Base(app) + ".app"
+	}
+	return filepath.Base(path)
+}
+
+// developerDirs lists the developer folders xcode-select can switch to:
+// each Xcode in /Applications by name, then the Command Line Tools
+func developerDirs() []string {
+	apps, _ := filepath.Glob(xcodeApps)
+	sort.Strings(apps)
+	var dirs []string
+	for _, app := range apps {
+		dirs = append(dirs, filepath.Join(app, "Contents", "Developer"))
+	}
+	if info, err := os.Stat(commandLineTools); err == nil && info.IsDir() {
+		dirs = append(dirs, commandLineTools)
+	}
+	return dirs
+}
+
+// planSwitchXcode picks the developer folder after the active one,
+// wrapping around, so each run moves on to the next
+func (m *Model) planSwitchXcode() (Plan, error) {
+	dirs := developerDirs()
+	if len(dirs) == 0 {
+		return Plan{}, fmt.Errorf("no Xcode in /Applications and no Command Line Tools (xcode-select --install)")
+	}
+	current, _ := m.xcodePath()
+	next := dirs[0]
+	for i, dir := range dirs {
+		if dir == current {
+			next = dirs[(i+1)%len(dirs)]
+		}
+	}
+	if next == current {
+		return Plan{Summary: "Only " + developerDirName(current) + " is installed, and it's already in use"}, nil
+	}
+	before := "none"
+	if current != "" {
+		before = developerDirName(current)
+	}
+	timeout := m.timeoutFor("Switch Xcode")
+	return Plan{
+		Summary:  fmt.Sprintf("Switches from %s to %s for xcodebuild, git, clang and the rest", before, developerDirName(next)),
+		Commands: []string{"sudo xcode-select -s " + next},
+		Run: func() error {
+			if err := executeSudoCommand(timeout, "xcode-select", "-s", next); err != nil {
+				return err
+			}
+			change := changes.Change{Source: m.Title(), What: "xcode-select path", Before: changes.NotSet, After: next}
+			if current != "" {
+				change.Before = current
+				change.Revert = func() error { return executeSudoCommand(timeout, "xcode-select", "-s", current) }
+			}
+			changes.Record(change)
+			return nil
+		},
+	}, nil
+}
+
+// simctlCommands are what Reset iOS Simulator runs
+var simctlCommands = [][]string{{"xcrun", "simctl", "shutdown", "all"}, {"xcrun", "simctl", "erase", "all"}}
+
+// resetSimulators shuts every simulator down, as erasing needs, and
+// erases them all
+func (m *Model) resetSimulators() error {
+	if _, err := m.runner.LookPath("xcrun"); err != nil {
+		return fmt.Errorf("xcrun not found: install Xcode to get the iOS Simulator")
+	}
+	for _, args := range simctlCommands {
+		liveOutput.command(args...)
+		output, err := m.runner.CombinedOutput(exec.Command(args[0], args[1:]...))
+		liveOutput.text(string(output))
+		if err != nil {
+			return fmt.Errorf("%s: %s", strings.Join(args[1:3], " "), firstLine(output, err))
+		}
+	}
+	return nil
+}
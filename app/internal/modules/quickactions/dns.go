@@ -0,0 +1,219 @@
+package quickactions
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	sudohelper "github.com/caioricciuti/dev-cockpit/internal/sudo"
+)
+
+// dnsPreset is a set of DNS servers the resolver actions switch to; no
+// servers means the ones DHCP hands out
+type dnsPreset struct {
+	Name    string
+	Servers []string
+}
+
+var dnsPresets = []dnsPreset{
+	{Name: "Cloudflare", Servers: []string{"1.1.1.1", "1.0.0.1"}},
+	{Name: "Google", Servers: []string{"8.8.8.8", "8.8.4.4"}},
+	{Name: "Quad9", Servers: []string{"9.9.9.9", "149.112.112.112"}},
+}
+
+// dhcpPreset hands DNS back to the network
+var dhcpPreset = dnsPreset{Name: "DHCP"}
+
+// resolvers returns the built-in presets, those in
+// modules.quickactions.dns_presets by name (replacing a built-in one of the
+// same name) and DHCP last
+func (m *Model) resolvers() []dnsPreset {
+	resolvers := slices.Clone(dnsPresets)
+	if m.config != nil {
+		custom := m.config.Modules.QuickActions.DNSPresets
+		names := make([]string, 0, len(custom))
+		for name := range custom {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p := dnsPreset{Name: name, Servers: custom[name]}
+			if i := slices.IndexFunc(resolvers, func(r dnsPreset) bool { return strings.EqualFold(r.Name, name) }); i >= 0 {
+				resolvers[i] = p
+			} else {
+				resolvers = append(resolvers, p)
+			}
+		}
+	}
+	return append(resolvers, dhcpPreset)
+}
+
+// dnsActions returns an action per resolver that switches the active
+// network service to it
+func (m *Model) dnsActions() []Action {
+	resolvers := m.resolvers()
+	actions := make([]Action, 0, len(resolvers))
+	for _, p := range resolvers {
+		p := p
+		description := "Use the DNS servers the network hands out"
+		if len(p.Servers) > 0 {
+			description = "Resolve names with " + strings.Join(p.Servers, ", ")
+		}
+		actions = append(actions, Action{
+			Name:         "DNS: " + p.Name,
+			Description:  description,
+			Category:     "Network",
+			Command:      func() error { return m.switchDNS(p) },
+			State:        func() string { return m.dnsState(p) },
+			CommandLines: func() []string { return m.dnsCommands(p) },
+		})
+	}
+	return actions
+}
+
+// dnsState marks the resolver in use. DHCP's row also names servers set by
+// hand that match no preset, so the current ones always show somewhere.
+func (m *Model) dnsState(p dnsPreset) string {
+	service, err := m.activeNetworkService()
+	if err != nil {
+		return ""
+	}
+	current, err := m.dnsServers(service)
+	switch {
+	case err != nil:
+		return ""
+	case slices.Equal(current, p.Servers):
+		return "in use"
+	case len(p.Servers) == 0 && !slices.ContainsFunc(m.resolvers(), func(r dnsPreset) bool { return slices.Equal(r.Servers, current) }):
+		return "now " + strings.Join(current, ", ")
+	}
+	return ""
+}
+
+// switchDNS sets the active network service's DNS servers to p's
+func (m *Model) switchDNS(p dnsPreset) error {
+	service, err := m.activeNetworkService()
+	if err != nil {
+		return err
+	}
+	before, err := m.dnsServers(service)
+	if err != nil {
+		return err
+	}
+	if err := m.setDNSServers(service, p.Servers); err != nil {
+		return err
+	}
+	changes.Record(changes.Change{
+		Source: m.Title(),
+		What:   "DNS servers of " + service,
+		Before: dnsLabel(before),
+		After:  dnsLabel(p.Servers),
+		Revert: func() error { return m.setDNSServers(service, before) },
+	})
+	return nil
+}
+
+// setDNSServers sets a service's DNS servers, or hands them back to DHCP
+// when there are none. Accounts that aren't admins need sudo for it.
+func (m *Model) setDNSServers(service string, servers []string) error {
+	args := dnsArgs(service, servers)[1:]
+	liveOutput.command(append([]string{"networksetup"}, args...)...)
+	output, err := m.runner.CombinedOutput(exec.Command("networksetup", args...))
+	// networksetup reports failures on stdout and still exits 0
+	if err == nil && len(strings.TrimSpace(string(output))) == 0 {
+		return nil
+	}
+	liveOutput.command(append([]string{"sudo", "networksetup"}, args...)...)
+	if sudoOutput, err := sudohelper.Run("networksetup", args...); err != nil || strings.TrimSpace(sudoOutput) != "" {
+		return fmt.Errorf("networksetup: %s", firstLine([]byte(sudoOutput), err))
+	}
+	return nil
+}
+
+// dnsArgs is the networksetup command line that sets a service's servers
+func dnsArgs(service string, servers []string) []string {
+	args := append([]string{"networksetup", "-setdnsservers", service}, servers...)
+	if len(servers) == 0 {
+		args = append(args, "Empty")
+	}
+	return args
+}
+
+// dnsServers lists the DNS servers set on a service, none when it uses
+// DHCP's
+func (m *Model) dnsServers(service string) ([]string, error) {
+	output, err := m.runner.Output(exec.Command("networksetup", "-getdnsservers", service))
+	if err != nil {
+		return nil, fmt.Errorf("networksetup: %s", firstLine(output, err))
+	}
+	var servers []string
+	for _, line := range strings.Split(string(output), "\n") {
+		// "There aren't any DNS Servers set on Wi-Fi." lists none
+		if line = strings.TrimSpace(line); net.ParseIP(line) != nil {
+			servers = append(servers, line)
+		}
+	}
+	return servers, nil
+}
+
+// activeNetworkService is the network service, e.g. Wi-Fi, of the
+// interface the default route goes through
+func (m *Model) activeNetworkService() (string, error) {
+	output, err := m.runner.Output(exec.Command("route", "-n", "get", "default"))
+	if err != nil {
+		return "", fmt.Errorf("no default route: %s", firstLine(output, err))
+	}
+	device := ""
+	for _, line := range strings.Split(string(output), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "interface:"); ok {
+			device = strings.TrimSpace(value)
+		}
+	}
+	if device == "" {
+		return "", fmt.Errorf("no interface in the default route")
+	}
+	output, err = m.runner.Output(exec.Command("networksetup", "-listnetworkserviceorder"))
+	if err != nil {
+		return "", fmt.Errorf("networksetup: %s", firstLine(output, err))
+	}
+	if service := parseServiceOrder(string(output), device); service != "" {
+		return service, nil
+	}
+	return "", fmt.Errorf("no network service uses %s", device)
+}
+
+// parseServiceOrder finds the service of device in
+// `networksetup -listnetworkserviceorder`, which lists each as
+//
+//	(1) Wi-Fi
+//	(Hardware Port: Wi-Fi, Device: en0)
+func parseServiceOrder(output, device string) string {
+	service := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "(Hardware Port:"):
+			if strings.HasSuffix(line, "Device: "+device+")") {
+				return service
+			}
+		case strings.HasPrefix(line, "("):
+			// "(*)" marks a disabled service
+			if _, name, ok := strings.Cut(line, ") "); ok {
+				service = name
+			}
+		}
+	}
+	return ""
+}
+
+// dnsLabel names a list of servers for the Changes journal
+func dnsLabel(servers []string) string {
+	if len(servers) == 0 {
+		return "DHCP"
+	}
+	return strings.Join(servers, ", ")
+}
@@ -0,0 +1,309 @@
+package quickactions
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// historyFile holds the actions run in the audit store, so retention
+// applies to it like the other audit logs
+const historyFile = "quick_actions.jsonl"
+
+// historyShown is how many recent runs the History view loads
+const historyShown = 200
+
+// historyTail is how many of an action's last output lines are kept
+const historyTail = 5
+
+// Run is an action run, as kept in the history
+type Run struct {
+	Time     time.Time     `json:"time"`
+	Action   string        `json:"action"`
+	Duration time.Duration `json:"duration"`
+	Success  bool          `json:"success"`
+	Result   string        `json:"result"`
+	// Output is the end of what the action's commands printed
+	Output []string `json:"output,omitempty"`
+	// Defaults are the defaults keys the action wrote, with the values
+	// they had, so it can be undone
+	Defaults []SavedDefault `json:"defaults,omitempty"`
+	// Restart is the app relaunched after an undo, e.g. Dock
+	Restart string `json:"restart,omitempty"`
+}
+
+// SavedDefault is a defaults key as it was before an action wrote it
+type SavedDefault struct {
+	Domain string        `json:"domain"`
+	Name   string        `json:"name"`
+	Type   defaults.Type `json:"type"`
+	Value  string        `json:"value,omitempty"`
+	Set    bool          `json:"set"`
+}
+
+func (d SavedDefault) key() defaults.Key {
+	return defaults.Key{Domain: d.Domain, Name: d.Name, Type: d.Type}
+}
+
+// before is the value as shown in the undo confirmation
+func (d SavedDefault) before() string {
+	if !d.Set {
+		return changes.NotSet
+	}
+	return d.Value
+}
+
+// historyView is the History view: the runs, newest first
+type historyView struct {
+	runs   []Run
+	cursor int
+	err    error
+}
+
+type historyMsg struct {
+	runs []Run
+	err  error
+}
+
+// undoRunMsg undoes a run once its confirmation is accepted
+type undoRunMsg struct{ run Run }
+
+// lastChangeID is the ID of the latest change recorded this session
+func lastChangeID() int {
+	if list := changes.List(); len(list) > 0 {
+		return list[0].ID
+	}
+	return 0
+}
+
+// defaultsSince returns the defaults keys this module's changes after the
+// change with ID mark wrote, in the order they were written
+func (m *Model) defaultsSince(mark int) []SavedDefault {
+	var saved []SavedDefault
+	list := changes.List()
+	for i := len(list) - 1; i >= 0; i-- {
+		c := list[i]
+		if c.ID <= mark || c.Source != m.Title() {
+			continue
+		}
+		for _, d := range c.Defaults {
+			saved = append(saved, SavedDefault{Domain: d.Key.Domain, Name: d.Key.Name, Type: d.Key.Type, Value: d.Before.Text, Set: d.Before.Set})
+		}
+	}
+	return saved
+}
+
+// undoRun puts back the values a run's defaults writes replaced, last
+// first so a key written twice gets its original value, then relaunches
+// the app that reads them
+func (m *Model) undoRun(run Run) error {
+	for i := len(run.Defaults) - 1; i >= 0; i-- {
+		d := run.Defaults[i]
+		liveOutput.line(fmt.Sprintf("Restoring %s to %s", d.key(), d.before()))
+		if err := defaults.Restore(m.runner, d.key(), defaults.Value{Text: d.Value, Set: d.Set}); err != nil {
+			return err
+		}
+	}
+	if run.Restart == "" {
+		return nil
+	}
+	return m.restartProcess(run.Restart)
+}
+
+// confirmUndo lists what undoing run puts back
+func confirmUndo(run Run) tea.Cmd {
+	lines := make([]string, 0, len(run.Defaults)+2)
+	for _, d := range run.Defaults {
+		lines = append(lines, fmt.Sprintf("%s → %s", d.key(), d.before()))
+	}
+	if run.Restart != "" {
+		lines = append(lines, "", run.Restart+" restarts to apply them.")
+	}
+	return dialog.Confirm(dialog.Request{
+		Title:        "Undo " + run.Action + "?",
+		Detail:       "Puts back the values it replaced:\n" + strings.Join(lines, "\n"),
+		ConfirmLabel: "Undo",
+		OnConfirm:    undoRunMsg{run: run},
+	})
+}
+
+func (m *Model) historyPath() string {
+	return filepath.Join(storage.DataDir(m.config), storage.StoreAudit, historyFile)
+}
+
+// recordRun appends a run to the history; a failure to write is logged,
+// as it shouldn't fail the action
+func (m *Model) recordRun(run Run) {
+	data, err := json.Marshal(run)
+	if err == nil {
+		err = appendLine(m.historyPath(), data)
+	}
+	if err != nil {
+		logger.Warn("Failed to record %s in the quick action history: %v", run.Action, err)
+	}
+}
+
+func appendLine(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// loadHistory returns the most recent runs, newest first. A missing file
+// is an empty history.
+func loadHistory(path string) ([]Run, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			continue // skip a line cut short by a crash
+		}
+		runs = append(runs, run)
+	}
+	if len(runs) > historyShown {
+		runs = runs[len(runs)-historyShown:]
+	}
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+	return runs, scanner.Err()
+}
+
+// openHistory opens the History view and loads the runs
+func (m *Model) openHistory() tea.Cmd {
+	m.history = &historyView{}
+	path := m.historyPath()
+	return func() tea.Msg {
+		runs, err := loadHistory(path)
+		return historyMsg{runs: runs, err: err}
+	}
+}
+
+// updateHistory handles the History view's messages and keys
+func (m *Model) updateHistory(msg tea.Msg) tea.Cmd {
+	h := m.history
+	switch msg := msg.(type) {
+	case historyMsg:
+		h.runs, h.err = msg.runs, msg.err
+	case events.Nav:
+		h.cursor = msg.Move(h.cursor, len(h.runs))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "h":
+			m.history = nil
+		case "u":
+			if run, ok := h.selected(); ok && len(run.Defaults) > 0 {
+				return confirmUndo(run)
+			}
+		}
+	}
+	return nil
+}
+
+// selected is the run under the cursor
+func (h *historyView) selected() (Run, bool) {
+	if h.cursor < len(h.runs) {
+		return h.runs[h.cursor], true
+	}
+	return Run{}, false
+}
+
+// String is a run as copied with y
+func (r Run) String() string {
+	text := fmt.Sprintf("%s  %s (%s)\n%s", r.Time.Local().Format("2006-01-02 15:04:05"), r.Action, formatDuration(r.Duration), r.Result)
+	if len(r.Output) > 0 {
+		text += "\n\n" + strings.Join(r.Output, "\n")
+	}
+	return text
+}
+
+// renderHistory lists the runs with the selected one's result and output
+func (m *Model) renderHistory() string {
+	h := m.history
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	okStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	errStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+
+	lines := []string{titleStyle.Render("⚡ QUICK ACTIONS › HISTORY"), ""}
+	switch {
+	case h.err != nil:
+		lines = append(lines, errStyle.Render("✗ "+h.err.Error()))
+	case len(h.runs) == 0:
+		lines = append(lines, mutedStyle.Render("No actions run yet"))
+	default:
+		// The selected run's details take the last lines
+		rows := max(m.height-12-historyTail, 5)
+		start := min(max(h.cursor-rows/2, 0), max(len(h.runs)-rows, 0))
+		for i := start; i < min(start+rows, len(h.runs)); i++ {
+			run := h.runs[i]
+			mark, style := okStyle.Render("✓"), lipgloss.NewStyle()
+			if !run.Success {
+				mark = errStyle.Render("✗")
+			}
+			prefix := "  "
+			if i == h.cursor {
+				prefix, style = "▶ ", selectedStyle
+			}
+			when := run.Time.Local().Format("Jan 2 15:04")
+			lines = append(lines, style.Render(prefix+when+"  ")+mark+style.Render(fmt.Sprintf(" %-28s", run.Action))+mutedStyle.Render(formatDuration(run.Duration)))
+		}
+		if run, ok := h.selected(); ok {
+			result := okStyle
+			if !run.Success {
+				result = errStyle
+			}
+			lines = append(lines, "", result.Render(run.Result))
+			width := max(m.width-6, 20)
+			for _, line := range run.Output {
+				lines = append(lines, mutedStyle.MaxWidth(width).Render("  "+line))
+			}
+			if len(run.Defaults) > 0 {
+				lines = append(lines, mutedStyle.Render(fmt.Sprintf("  Wrote %d defaults key(s); U puts back the values it replaced", len(run.Defaults))))
+			}
+		}
+	}
+	lines = append(lines, "", mutedStyle.Render("↑/↓ Navigate • U Undo • y Copy run • Esc Back"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// formatDuration rounds a run's duration for the list
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
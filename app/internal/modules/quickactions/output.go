@@ -0,0 +1,149 @@
+package quickactions
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// outputKept is how many lines of command output the pane keeps
+const outputKept = 1000
+
+// actionOutput hands the output of the commands a running action runs to
+// the output pane. The command helpers are shared with the CLI and take
+// no model, and only one action runs at a time, so they write here; with
+// no action running, output is only logged as before.
+type actionOutput struct {
+	mu    sync.Mutex
+	lines chan string
+	tail  []string // the last historyTail lines, for the history
+}
+
+var liveOutput actionOutput
+
+// start opens a new stream of output lines, ending any earlier one
+func (o *actionOutput) start() chan string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.lines != nil {
+		close(o.lines)
+	}
+	o.lines = make(chan string, 256)
+	o.tail = nil
+	return o.lines
+}
+
+// stop ends the stream once lines's action is done and returns its last
+// lines. A command still running after its action timed out writes nowhere.
+func (o *actionOutput) stop(lines chan string) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.lines != lines {
+		return nil
+	}
+	close(o.lines)
+	o.lines = nil
+	return o.tail
+}
+
+// line adds a line to the stream, if there is one. The pane reads
+// continuously, so a full buffer only drops lines in a burst.
+func (o *actionOutput) line(text string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.lines == nil {
+		return
+	}
+	o.tail = append(o.tail, text)
+	if len(o.tail) > historyTail {
+		o.tail = o.tail[1:]
+	}
+	select {
+	case o.lines <- text:
+	default:
+	}
+}
+
+// text adds each line of output that arrived in one piece
+func (o *actionOutput) text(output string) {
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if line != "" {
+			o.line(line)
+		}
+	}
+}
+
+// command echoes a command line to the stream before it runs
+func (o *actionOutput) command(args ...string) {
+	o.line("$ " + strings.Join(args, " "))
+}
+
+// stream runs cmd, passing its output to the stream as it's written, and
+// returns the output for callers that read it
+func (o *actionOutput) stream(cmd *exec.Cmd) ([]byte, error) {
+	o.command(cmd.Args...)
+	var out strings.Builder
+	err := runner.Stream(runner.System{}, cmd, func(text string) {
+		out.WriteString(text + "\n")
+		o.line(text)
+	})
+	return []byte(out.String()), err
+}
+
+// outputLineMsg is a line of output from the running action
+type outputLineMsg struct {
+	lines chan string
+	text  string
+}
+
+// waitOutput waits for the next line from lines
+func waitOutput(lines chan string) tea.Cmd {
+	return func() tea.Msg {
+		text, ok := <-lines
+		if !ok {
+			return nil
+		}
+		return outputLineMsg{lines: lines, text: text}
+	}
+}
+
+// startOutput clears the pane and starts reading the action's output
+func (m *Model) startOutput() (chan string, tea.Cmd) {
+	lines := liveOutput.start()
+	m.outputLines = lines
+	m.outputLog = nil
+	m.outputView.SetLines(nil)
+	m.outputView.GotoBottom()
+	return lines, waitOutput(lines)
+}
+
+// addOutput shows a line in the pane, following the end unless scrolled up
+func (m *Model) addOutput(msg outputLineMsg) tea.Cmd {
+	if msg.lines != m.outputLines {
+		return nil
+	}
+	m.outputLog = append(m.outputLog, msg.text)
+	if len(m.outputLog) > outputKept {
+		m.outputLog = m.outputLog[len(m.outputLog)-outputKept:]
+	}
+	m.outputView.SetLines(m.outputLog)
+	return waitOutput(msg.lines)
+}
+
+// renderOutput is the output pane under the action list
+func (m *Model) renderOutput() string {
+	width := max(m.width-6, 20)
+	line := lipgloss.NewStyle().MaxWidth(width).Foreground(components.ColorMuted)
+	shown := make([]string, len(m.outputLog))
+	for i, text := range m.outputLog {
+		shown[i] = line.Render(text)
+	}
+	m.outputView.SetLines(shown)
+	title := lipgloss.NewStyle().Foreground(components.ColorSubtle).Bold(true).Render("Output")
+	return title + "\n" + m.outputView.View()
+}
@@ -0,0 +1,439 @@
+package quickactions
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	sudohelper "github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var runPrivileged = sudohelper.Run // replaced in tests
+
+// anomalyDepth is how deep below the inspected folder anomalies are
+// searched for, so a scan of $HOME stays quick
+const anomalyDepth = 4
+
+// permEntry is one line of `ls -lAeO`: a file with its mode, owner,
+// file flags and ACL entries
+type permEntry struct {
+	Name  string
+	Mode  string // e.g. drwx------+
+	Owner string
+	Group string
+	Flags string // e.g. uchg,hidden; "-" without any
+	ACL   []string
+}
+
+// anomaly is a file below the inspected folder that the user can't
+// fully manage: owned by someone else, or locked with the uchg flag
+type anomaly struct {
+	Path   string
+	Owner  string
+	Group  string
+	Locked bool
+}
+
+// permInspector is the Inspect Permissions view. Only files under home
+// are flagged and fixed: elsewhere, other owners are expected.
+type permInspector struct {
+	user      string
+	group     string // the user's primary group, from id -gn
+	home      string
+	path      string
+	entries   []permEntry
+	anomalies []anomaly
+	cursor    int
+	loading   bool
+	err       error
+	editing   bool // typing a path
+	input     string
+	message   string
+}
+
+type permsMsg struct {
+	path      string
+	group     string
+	entries   []permEntry
+	anomalies []anomaly
+	err       error
+}
+
+// fixPermsMsg applies the fixes once the preview is confirmed
+type fixPermsMsg struct{ anomalies []anomaly }
+
+type permsFixedMsg struct {
+	note string
+	err  error
+}
+
+// openPermissions opens the inspector on the home folder
+func (m *Model) openPermissions() tea.Cmd {
+	home, _ := os.UserHomeDir()
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	m.perms = &permInspector{user: name, home: home}
+	return m.inspect(home)
+}
+
+// inspect lists path and, under home, searches below it for anomalies
+func (m *Model) inspect(path string) tea.Cmd {
+	p := m.perms
+	p.path, p.loading, p.cursor, p.err = path, true, 0, nil
+	r, name, group, home := m.runner, p.user, p.group, p.home
+	return func() tea.Msg {
+		if group == "" {
+			group = primaryGroup(r)
+		}
+		entries, err := listPermissions(r, path)
+		if err != nil {
+			return permsMsg{path: path, group: group, err: err}
+		}
+		if !within(home, path) {
+			return permsMsg{path: path, group: group, entries: entries}
+		}
+		anomalies, err := findAnomalies(r, path, name)
+		return permsMsg{path: path, group: group, entries: entries, anomalies: anomalies, err: err}
+	}
+}
+
+// primaryGroup is the user's primary group, or "" when id fails
+func primaryGroup(r runner.Runner) string {
+	output, err := r.Output(exec.Command("id", "-gn"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// within reports whether path is home or below it
+func within(home, path string) bool {
+	rel, err := filepath.Rel(home, path)
+	return home != "" && err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// listPermissions parses `ls -lAeO`, whose entries look like
+//
+//	drwx------+  5 caio  staff  -  160 Jul  1 10:00 Desktop
+//	 0: group:everyone deny delete
+func listPermissions(r runner.Runner, path string) ([]permEntry, error) {
+	output, err := r.CombinedOutput(exec.Command("ls", "-lAeO", path))
+	if err != nil {
+		return nil, fmt.Errorf("ls: %s", firstLine(output, err))
+	}
+	var entries []permEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "total ") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(entries); n > 0 && strings.HasPrefix(line, " ") {
+			_, rule, _ := strings.Cut(line, ": ")
+			entries[n-1].ACL = append(entries[n-1].ACL, strings.TrimSpace(rule))
+			continue
+		}
+		// Mode, links, owner, group, flags, size and three date fields
+		// come before the name, which may contain spaces
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		name := strings.Join(fields[9:], " ")
+		entries = append(entries, permEntry{Name: name, Mode: fields[0], Owner: fields[2], Group: fields[3], Flags: fields[4]})
+	}
+	return entries, nil
+}
+
+// findAnomalies lists what below path isn't owned by name or is locked.
+// -xdev keeps the search off other volumes mounted inside it.
+func findAnomalies(r runner.Runner, path, name string) ([]anomaly, error) {
+	cmd := exec.Command("find", path, "-xdev", "-maxdepth", fmt.Sprint(anomalyDepth),
+		"(", "!", "-user", name, "-o", "-flags", "+uchg", ")",
+		"-exec", "stat", "-f", "%Su:%Sg:%Sf:%N", "{}", "+")
+	// find exits non-zero after unreadable folders but still prints the rest
+	output, _ := r.CombinedOutput(cmd)
+	var anomalies []anomaly
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.SplitN(line, ":", 4)
+		if len(parts) < 4 || !filepath.IsAbs(parts[3]) {
+			continue
+		}
+		a := anomaly{Owner: parts[0], Group: parts[1], Path: parts[3]}
+		for _, flag := range strings.Split(parts[2], ",") {
+			a.Locked = a.Locked || flag == "uchg"
+		}
+		if a.Owner != name || a.Locked {
+			anomalies = append(anomalies, a)
+		}
+	}
+	return anomalies, nil
+}
+
+// fixes are the commands that clear anomalies: unlock, then give the
+// files back to the user. -h changes symlinks themselves, never what they
+// point to.
+func fixes(anomalies []anomaly, name, group string) [][]string {
+	var locked, foreign []string
+	for _, a := range anomalies {
+		if a.Locked {
+			locked = append(locked, a.Path)
+		}
+		if a.Owner != name {
+			foreign = append(foreign, a.Path)
+		}
+	}
+	var commands [][]string
+	if len(locked) > 0 {
+		commands = append(commands, append([]string{"chflags", "-h", "nouchg"}, locked...))
+	}
+	if len(foreign) > 0 {
+		commands = append(commands, append([]string{"chown", "-h", name + ":" + group}, foreign...))
+	}
+	return commands
+}
+
+// previewFixes shows the commands the fix will run before running them
+func (m *Model) previewFixes() tea.Cmd {
+	p := m.perms
+	switch {
+	case !within(p.home, p.path):
+		p.message = "Only files under " + p.home + " can be fixed"
+		return nil
+	case len(p.anomalies) == 0:
+		p.message = "Nothing to fix under " + p.path
+		return nil
+	case p.group == "":
+		p.message = "✗ Couldn't read your primary group with id -gn"
+		return nil
+	}
+	var detail strings.Builder
+	for _, command := range fixes(p.anomalies, p.user, p.group) {
+		paths := command[3:]
+		shown := strings.Join(paths[:min(len(paths), 5)], " ")
+		if len(paths) > 5 {
+			shown += fmt.Sprintf(" … and %d more", len(paths)-5)
+		}
+		detail.WriteString(fmt.Sprintf("sudo %s %s\n", strings.Join(command[:3], " "), shown))
+	}
+	return dialog.Confirm(dialog.Request{
+		Title:        fmt.Sprintf("Fix %d file(s) under %s?", len(p.anomalies), p.path),
+		Detail:       strings.TrimSpace(detail.String()),
+		ConfirmLabel: "Fix",
+		Destructive:  true,
+		OnConfirm:    fixPermsMsg{anomalies: p.anomalies},
+	})
+}
+
+// applyFixes runs the previewed commands and records how to undo them
+func (m *Model) applyFixes(anomalies []anomaly) tea.Cmd {
+	p := m.perms
+	p.loading = true
+	name, group := p.user, p.group
+	return func() tea.Msg {
+		for _, command := range fixes(anomalies, name, group) {
+			if _, err := runPrivileged(command[0], command[1:]...); err != nil {
+				return permsFixedMsg{err: fmt.Errorf("%s: %w", command[0], err)}
+			}
+		}
+		changes.Record(changes.Change{
+			Source: m.Title(),
+			What:   fmt.Sprintf("Permissions of %d file(s)", len(anomalies)),
+			Before: "foreign owners or locked",
+			After:  "owned by " + name + ", unlocked",
+			Revert: func() error {
+				for _, a := range anomalies {
+					if a.Owner != name {
+						if _, err := runPrivileged("chown", "-h", a.Owner+":"+a.Group, a.Path); err != nil {
+							return err
+						}
+					}
+					if a.Locked {
+						if _, err := runPrivileged("chflags", "-h", "uchg", a.Path); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		})
+		return permsFixedMsg{note: fmt.Sprintf("✓ Fixed %d file(s)", len(anomalies))}
+	}
+}
+
+// updatePerms handles the inspector's messages
+func (m *Model) updatePerms(msg tea.Msg) tea.Cmd {
+	p := m.perms
+	if p == nil {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case permsMsg:
+		if msg.path != p.path {
+			return nil
+		}
+		p.loading = false
+		p.entries, p.anomalies, p.err = msg.entries, msg.anomalies, msg.err
+		if msg.group != "" {
+			p.group = msg.group
+		}
+	case fixPermsMsg:
+		return m.applyFixes(msg.anomalies)
+	case permsFixedMsg:
+		p.message = msg.note
+		if msg.err != nil {
+			p.message = "✗ " + msg.err.Error()
+		}
+		return tea.Batch(components.StatusToast(p.message), m.inspect(p.path))
+	}
+	return nil
+}
+
+// handlePermsKey handles keys while the inspector is open
+func (m *Model) handlePermsKey(msg tea.KeyMsg) tea.Cmd {
+	p := m.perms
+	if p.editing {
+		switch msg.Type {
+		case tea.KeyEsc:
+			p.editing = false
+		case tea.KeyEnter:
+			p.editing = false
+			if path := expandHome(strings.TrimSpace(p.input)); path != "" {
+				return m.inspect(filepath.Clean(path))
+			}
+		case tea.KeyBackspace:
+			if runes := []rune(p.input); len(runes) > 0 {
+				p.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeyCtrlU:
+			p.input = ""
+		case tea.KeySpace:
+			p.input += " "
+		case tea.KeyRunes:
+			p.input += string(msg.Runes)
+		}
+		return nil
+	}
+	if p.loading {
+		if msg.String() == "esc" {
+			m.perms = nil
+		}
+		return nil
+	}
+
+	p.message = ""
+	switch msg.String() {
+	case "esc", "q":
+		m.perms = nil
+	case "enter":
+		if p.cursor < len(p.entries) && strings.HasPrefix(p.entries[p.cursor].Mode, "d") {
+			return m.inspect(filepath.Join(p.path, p.entries[p.cursor].Name))
+		}
+	case "backspace", "u":
+		if parent := filepath.Dir(p.path); parent != p.path {
+			return m.inspect(parent)
+		}
+	case "p":
+		p.editing, p.input = true, p.path
+	case "f":
+		return m.previewFixes()
+	case "r":
+		return m.inspect(p.path)
+	}
+	return nil
+}
+
+// renderPerms draws the inspector
+func (m *Model) renderPerms() string {
+	p := m.perms
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+
+	path := p.path
+	if p.editing {
+		path = lipgloss.NewStyle().Foreground(components.ColorBright).Render(p.input + "█")
+	}
+	lines := []string{titleStyle.Render("🔐 PERMISSIONS  ") + path, ""}
+	switch {
+	case p.loading:
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("Reading %s and searching %d levels below it...", p.path, anomalyDepth)))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	case p.err != nil:
+		lines = append(lines, errorStyle.Render("✗ "+p.err.Error()), "", mutedStyle.Render("P Change path • U Up • Esc Back"))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	// Anomalies get the rows they need, up to a third of the view; the
+	// listing scrolls in the rest
+	home := within(p.home, p.path)
+	anomalyRows := min(len(p.anomalies), max(m.height/3, 3))
+	rows := max(m.height-12-anomalyRows, 5)
+	start := min(max(p.cursor-rows/2, 0), max(len(p.entries)-rows, 0))
+	for i := start; i < min(start+rows, len(p.entries)); i++ {
+		e := p.entries[i]
+		flags := e.Flags
+		if flags == "-" {
+			flags = ""
+		}
+		owner := e.Owner + ":" + e.Group
+		line := fmt.Sprintf("%-12s %-20s %-14s %s", e.Mode, components.TruncateString(owner, 20), components.TruncateString(flags, 14), e.Name)
+		if home && (e.Owner != p.user || strings.Contains(e.Flags, "uchg")) {
+			line = errorStyle.Render(line)
+		}
+		if len(e.ACL) > 0 {
+			line += mutedStyle.Render("  ACL: " + strings.Join(e.ACL, "; "))
+		}
+		if i == p.cursor {
+			lines = append(lines, selectedStyle.Render("▶ ")+line)
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if len(p.entries) == 0 {
+		lines = append(lines, mutedStyle.Render("  (empty folder)"))
+	}
+
+	if home {
+		lines = append(lines, "", headerStyle.Render(fmt.Sprintf("Anomalies within %d levels: %d", anomalyDepth, len(p.anomalies))))
+	} else {
+		lines = append(lines, "", mutedStyle.Render("Outside "+p.home+": ownership isn't checked or fixed here"))
+	}
+	if home && len(p.anomalies) == 0 {
+		lines = append(lines, mutedStyle.Render("  Everything is yours and unlocked"))
+	}
+	for _, a := range p.anomalies[:anomalyRows] {
+		var why []string
+		if a.Owner != p.user {
+			why = append(why, "owned by "+a.Owner)
+		}
+		if a.Locked {
+			why = append(why, "locked")
+		}
+		rel, err := filepath.Rel(p.path, a.Path)
+		if err != nil {
+			rel = a.Path
+		}
+		lines = append(lines, "  "+errorStyle.Render(fmt.Sprintf("%-24s", strings.Join(why, ", ")))+" "+rel)
+	}
+	if more := len(p.anomalies) - anomalyRows; more > 0 {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … and %d more", more)))
+	}
+
+	if p.message != "" {
+		lines = append(lines, "", p.message)
+	}
+	lines = append(lines, "", mutedStyle.Render("Enter Open folder • U Up • P Change path • F Fix anomalies (preview first) • R Rescan • Esc Back"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
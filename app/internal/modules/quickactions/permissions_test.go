@@ -0,0 +1,108 @@
+package quickactions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	sudohelper "github.com/caioricciuti/dev-cockpit/internal/sudo"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const findHome = "find /Users/caio -xdev -maxdepth 4 ( ! -user caio -o -flags +uchg ) -exec stat -f %Su:%Sg:%Sf:%N {} +"
+
+func TestInspectPermissions(t *testing.T) {
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+	fake := runner.NewFake()
+	if err := fake.SetFixture("ls -lAeO /Users/caio", "testdata/ls_home.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.SetFixture(findHome, "testdata/find_home.txt"); err != nil {
+		t.Fatal(err)
+	}
+	fake.Set("id -gn", "devs\n", nil)
+	fake.Set("ls -lAeO /usr/local", "drwxr-xr-x  5 root  wheel  -  160 Jul  1 10:00 bin\n", nil)
+	var ran []string
+	runPrivileged = func(name string, args ...string) (string, error) {
+		ran = append(ran, strings.Join(append([]string{name}, args...), " "))
+		return "", nil
+	}
+	t.Cleanup(func() { runPrivileged = sudohelper.Run })
+
+	m := New(nil)
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.perms = &permInspector{user: "caio", home: "/Users/caio"}
+	m.Update(m.inspect("/Users/caio")())
+
+	p := m.perms
+	if len(p.entries) != 4 || p.entries[3].Name != "Old Projects" || p.entries[3].Flags != "uchg" {
+		t.Fatalf("entries = %+v", p.entries)
+	}
+	if acl := p.entries[0].ACL; !reflect.DeepEqual(acl, []string{"group:everyone deny delete"}) {
+		t.Errorf("Desktop ACL = %q", acl)
+	}
+	if len(p.anomalies) != 3 || !p.anomalies[1].Locked || p.anomalies[0].Owner != "root" {
+		t.Fatalf("anomalies = %+v", p.anomalies)
+	}
+	if view := m.View(); !strings.Contains(view, "owned by root, locked") || !strings.Contains(view, "Anomalies within 4 levels: 3") {
+		t.Errorf("anomalies not shown:\n%s", view)
+	}
+
+	want := [][]string{
+		{"chflags", "-h", "nouchg", "/Users/caio/Old Projects", "/Users/caio/.npm/_cacache/index"},
+		{"chown", "-h", "caio:devs", "/Users/caio/npm-debug.log", "/Users/caio/.npm/_cacache/index"},
+	}
+	if got := fixes(p.anomalies, p.user, p.group); !reflect.DeepEqual(got, want) {
+		t.Errorf("fixes = %q, want %q", got, want)
+	}
+	if m.previewFixes() == nil {
+		t.Fatal("no preview for the fixes")
+	}
+
+	_, cmd := m.Update(fixPermsMsg{anomalies: p.anomalies})
+	m.Update(cmd())
+	if len(ran) != 2 || !strings.HasPrefix(ran[0], "chflags -h nouchg") || !strings.HasPrefix(ran[1], "chown -h caio:devs") {
+		t.Fatalf("ran %q", ran)
+	}
+
+	// Undoing gives the files back to their owners and locks them again
+	ran = nil
+	list := changes.List()
+	if len(list) != 1 {
+		t.Fatalf("journal = %+v", list)
+	}
+	if err := list[0].Revert(); err != nil {
+		t.Fatal(err)
+	}
+	wantRevert := []string{
+		"chown -h root:staff /Users/caio/npm-debug.log",
+		"chflags -h uchg /Users/caio/Old Projects",
+		"chown -h root:wheel /Users/caio/.npm/_cacache/index",
+		"chflags -h uchg /Users/caio/.npm/_cacache/index",
+	}
+	if !reflect.DeepEqual(ran, wantRevert) {
+		t.Errorf("revert ran %q, want %q", ran, wantRevert)
+	}
+
+	// Outside the home folder nothing is flagged, and F refuses
+	m.Update(m.inspect("/usr/local")())
+	if len(p.entries) != 1 || len(p.anomalies) != 0 || p.err != nil {
+		t.Fatalf("/usr/local: entries = %+v, anomalies = %+v, err = %v", p.entries, p.anomalies, p.err)
+	}
+	for _, call := range fake.Calls() {
+		if strings.HasPrefix(call, "find /usr/local") {
+			t.Errorf("searched outside home: %s", call)
+		}
+	}
+	if cmd := m.handlePermsKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")}); cmd != nil || !strings.Contains(p.message, "Only files under /Users/caio") {
+		t.Errorf("f outside home: message %q", p.message)
+	}
+
+	if m.Update(tea.KeyMsg{Type: tea.KeyEsc}); m.perms != nil || m.HasOpenModal() {
+		t.Error("esc didn't close the inspector")
+	}
+}
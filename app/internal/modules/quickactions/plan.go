@@ -0,0 +1,187 @@
+package quickactions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Plan is what a destructive action is about to do, worked out before
+// asking: the commands it runs and the files or processes they touch.
+// Confirming runs Run on what was listed.
+type Plan struct {
+	// Summary heads the list, or says why there's nothing to do
+	Summary  string
+	Commands []string
+	Run      func() error
+}
+
+// planShown is how many commands the dialog lists before counting the rest
+const planShown = 8
+
+// downloadsAge is how long a download sits untouched before Clean
+// Downloads removes it
+const downloadsAge = 30 * 24 * time.Hour
+
+// planMsg carries a worked-out plan back for confirmation
+type planMsg struct {
+	name string
+	plan Plan
+	err  error
+}
+
+// runPlanMsg runs a plan once its dialog is confirmed
+type runPlanMsg struct {
+	name string
+	plan Plan
+}
+
+// planAction works out what action would touch, off the UI thread since
+// it may scan a folder or the process table
+func (m *Model) planAction(action Action) tea.Cmd {
+	m.status, m.statusType = "Checking what "+action.Name+" would touch...", "info"
+	return func() tea.Msg {
+		plan, err := action.Plan()
+		return planMsg{name: action.Name, plan: plan, err: err}
+	}
+}
+
+// confirmPlan shows the plan in a confirmation dialog, or says there's
+// nothing to do
+func (m *Model) confirmPlan(msg planMsg) tea.Cmd {
+	m.status, m.statusType = "", ""
+	switch {
+	case msg.err != nil:
+		m.status, m.statusType = fmt.Sprintf("✗ %s failed: %v", msg.name, msg.err), "error"
+		return nil
+	case len(msg.plan.Commands) == 0:
+		m.status, m.statusType = "✓ "+msg.plan.Summary, "success"
+		return nil
+	}
+
+	var detail strings.Builder
+	for _, action := range m.actions {
+		if action.Name == msg.name && action.Confirm != "" {
+			detail.WriteString(action.Confirm + "\n\n")
+		}
+	}
+	detail.WriteString(msg.plan.Summary + ":\n")
+	commands := msg.plan.Commands
+	detail.WriteString(strings.Join(commands[:min(len(commands), planShown)], "\n"))
+	if len(commands) > planShown {
+		fmt.Fprintf(&detail, "\n… and %d more", len(commands)-planShown)
+	}
+	return dialog.Confirm(dialog.Request{
+		Title:        msg.name + "?",
+		Detail:       detail.String(),
+		ConfirmLabel: "Run",
+		Destructive:  true,
+		OnConfirm:    runPlanMsg{name: msg.name, plan: msg.plan},
+	})
+}
+
+// tildePath shortens a path under the home folder for display
+func tildePath(path string) string {
+	home, _ := os.UserHomeDir()
+	if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok && home != "" {
+		return "~/" + rest
+	}
+	return path
+}
+
+// planCleanDownloads lists the files in ~/Downloads untouched for 30 days
+func (m *Model) planCleanDownloads() (Plan, error) {
+	homeDir, _ := os.UserHomeDir()
+	dir := filepath.Join(homeDir, "Downloads")
+	if _, err := os.Stat(dir); err != nil {
+		return Plan{}, fmt.Errorf("downloads directory not found")
+	}
+
+	cutoff := time.Now().Add(-downloadsAge)
+	var old []string
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().Before(cutoff) {
+			old = append(old, path)
+			size += info.Size()
+		}
+		return nil
+	})
+
+	plan := Plan{Summary: "No downloads older than 30 days"}
+	if len(old) == 0 {
+		return plan, nil
+	}
+	plan.Summary = fmt.Sprintf("Deletes %d file(s), %s, untouched for 30 days", len(old), formatSize(size))
+	for _, path := range old {
+		plan.Commands = append(plan.Commands, "rm "+tildePath(path))
+	}
+	plan.Run = func() error {
+		failed := 0
+		for _, path := range old {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				logger.Warn("Failed to remove %s: %v", path, err)
+				failed++
+			}
+		}
+		logger.Info("Removed %d old files from Downloads", len(old)-failed)
+		if failed > 0 {
+			return fmt.Errorf("could not remove %d of %d files (they may be in use)", failed, len(old))
+		}
+		return nil
+	}
+	return plan, nil
+}
+
+// planEmptyTrash lists what's in the Trash. Emptying it takes whatever
+// is there by then, as Finder's Empty Trash does.
+func (m *Model) planEmptyTrash() (Plan, error) {
+	homeDir, _ := os.UserHomeDir()
+	trash := filepath.Join(homeDir, ".Trash")
+	entries, err := os.ReadDir(trash)
+	if err != nil {
+		return Plan{}, fmt.Errorf("trash directory not found")
+	}
+
+	plan := Plan{Summary: "The Trash is already empty"}
+	var items []string
+	for _, entry := range entries {
+		if entry.Name() != ".DS_Store" {
+			items = append(items, filepath.Join(trash, entry.Name()))
+		}
+	}
+	if len(items) == 0 {
+		return plan, nil
+	}
+	plan.Summary = fmt.Sprintf("Permanently deletes %d item(s)", len(items))
+	for _, item := range items {
+		plan.Commands = append(plan.Commands, "rm -rf "+tildePath(item))
+	}
+	timeout := m.timeoutFor("Empty Trash")
+	plan.Run = func() error { return emptyTrashInternal(timeout) }
+	return plan, nil
+}
+
+// formatSize formats a file size for the plan summary
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
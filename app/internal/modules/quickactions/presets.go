@@ -0,0 +1,230 @@
+package quickactions
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+)
+
+// preset is a named set of preferences for one way of working, applied
+// and reverted as a whole
+type preset struct {
+	Name        string
+	Description string
+	Settings    []presetSetting
+	// Restart names the app relaunched after a change, e.g. Dock
+	Restart string
+	// Note says what else it takes, e.g. logging out
+	Note string
+}
+
+// presetSetting is one defaults key of a preset and the value it sets
+type presetSetting struct {
+	Label string
+	Key   defaults.Key
+	Value string
+}
+
+var presets = []preset{
+	{
+		Name:        "Fast Keyboard",
+		Description: "Fastest key repeat, short delay, Tab through all controls",
+		Settings: []presetSetting{
+			{"Key repeat: fastest", defaults.Key{Domain: "NSGlobalDomain", Name: "KeyRepeat", Type: defaults.Int}, "2"},
+			{"Delay until repeat: short", defaults.Key{Domain: "NSGlobalDomain", Name: "InitialKeyRepeat", Type: defaults.Int}, "15"},
+			{"Holding a key repeats it instead of showing accents", defaults.Key{Domain: "NSGlobalDomain", Name: "ApplePressAndHoldEnabled", Type: defaults.Bool}, "false"},
+			{"Tab moves between all controls", defaults.Key{Domain: "NSGlobalDomain", Name: "AppleKeyboardUIMode", Type: defaults.Int}, "3"},
+		},
+		Note: "Key repeat changes take effect after logging out and back in.",
+	},
+	{
+		Name:        "Trackpad Power User",
+		Description: "Three-finger drag and tap to click",
+		Settings: []presetSetting{
+			{"Three-finger drag", defaults.Key{Domain: "com.apple.AppleMultitouchTrackpad", Name: "TrackpadThreeFingerDrag", Type: defaults.Bool}, "true"},
+			{"Three-finger drag (Bluetooth trackpads)", defaults.Key{Domain: "com.apple.driver.AppleBluetoothMultitouch.trackpad", Name: "TrackpadThreeFingerDrag", Type: defaults.Bool}, "true"},
+			{"Tap to click", defaults.Key{Domain: "com.apple.AppleMultitouchTrackpad", Name: "Clicking", Type: defaults.Bool}, "true"},
+		},
+		Note: "Trackpad changes take effect after logging out and back in.",
+	},
+	{
+		Name:        "Calm Motion",
+		Description: "Reduce motion and skip window and app launch animations",
+		Settings: []presetSetting{
+			{"Reduce motion", defaults.Key{Domain: "com.apple.universalaccess", Name: "reduceMotion", Type: defaults.Bool}, "true"},
+			{"Window open and close animations off", defaults.Key{Domain: "NSGlobalDomain", Name: "NSAutomaticWindowAnimationsEnabled", Type: defaults.Bool}, "false"},
+			{"No bouncing app icons at launch", defaults.Key{Domain: "com.apple.dock", Name: "launchanim", Type: defaults.Bool}, "false"},
+		},
+		Restart: "Dock",
+	},
+	{
+		Name:        "Zoom for Demos",
+		Description: "Control-scroll to zoom the screen, following the keyboard focus",
+		Settings: []presetSetting{
+			{"Zoom with Control and scroll", defaults.Key{Domain: "com.apple.universalaccess", Name: "closeViewScrollWheelToggle", Type: defaults.Bool}, "true"},
+			{"Scroll modifier: Control", defaults.Key{Domain: "com.apple.universalaccess", Name: "HIDScrollZoomModifierMask", Type: defaults.Int}, "262144"},
+			{"Zoom follows the keyboard focus", defaults.Key{Domain: "com.apple.universalaccess", Name: "closeViewZoomFollowsFocus", Type: defaults.Bool}, "true"},
+		},
+	},
+}
+
+// presetActions returns an action per preset that applies it, or reverts
+// it when it's already on
+func (m *Model) presetActions() []Action {
+	actions := make([]Action, 0, len(presets))
+	for _, p := range presets {
+		p := p
+		actions = append(actions, Action{
+			Name:         p.Name,
+			Description:  p.Description,
+			Category:     "Presets",
+			Command:      func() error { return m.togglePreset(p) },
+			State:        func() string { return m.presetState(p) },
+			Confirm:      p.summary(),
+			Restart:      p.Restart,
+			CommandLines: func() []string { return m.presetCommands(p) },
+		})
+	}
+	return actions
+}
+
+// summary lists what the preset sets, for the confirmation dialog
+func (p preset) summary() string {
+	lines := make([]string, 0, len(p.Settings)+2)
+	for _, s := range p.Settings {
+		lines = append(lines, "• "+s.Label)
+	}
+	if p.Note != "" {
+		lines = append(lines, "", p.Note)
+	}
+	lines = append(lines, "", "Run it again to put your previous settings back.")
+	return strings.Join(lines, "\n")
+}
+
+// presetState is On when every setting has the preset's value, Partly when
+// some do and Off otherwise
+func (m *Model) presetState(p preset) string {
+	matches := 0
+	for _, s := range p.Settings {
+		value, err := defaults.Read(m.runner, s.Key)
+		if err != nil {
+			return "unknown"
+		}
+		if value.Set && value.Text == s.Value {
+			matches++
+		}
+	}
+	switch matches {
+	case len(p.Settings):
+		return "On"
+	case 0:
+		return "Off"
+	default:
+		return "Partly"
+	}
+}
+
+// togglePreset applies p, or reverts it when it's fully on. Both are
+// recorded in Settings › Changes.
+func (m *Model) togglePreset(p preset) error {
+	if m.presetState(p) == "On" {
+		if err := m.restorePreset(p); err != nil {
+			return err
+		}
+		on := make([]changes.Default, len(p.Settings))
+		for i, s := range p.Settings {
+			on[i] = changes.Default{Key: s.Key, Before: defaults.Value{Text: s.Value, Set: true}}
+		}
+		changes.Record(changes.Change{
+			Source: m.Title(), What: p.Name + " preset", Before: "On", After: "Off",
+			Revert:   func() error { return m.applyPreset(p) },
+			Defaults: on,
+		})
+		return nil
+	}
+
+	if err := m.applyPreset(p); err != nil {
+		return err
+	}
+	m.presetMu.Lock()
+	before := make([]changes.Default, len(p.Settings))
+	for i, s := range p.Settings {
+		before[i] = changes.Default{Key: s.Key, Before: m.presetSaved[p.Name][i]}
+	}
+	m.presetMu.Unlock()
+	changes.Record(changes.Change{
+		Source: m.Title(), What: p.Name + " preset", Before: "Off", After: "On",
+		Revert:   func() error { return m.restorePreset(p) },
+		Defaults: before,
+	})
+	return nil
+}
+
+// applyPreset writes every setting, keeping the values it replaces so the
+// preset can be reverted. A failed write puts back the ones already made.
+func (m *Model) applyPreset(p preset) error {
+	before := make([]defaults.Value, len(p.Settings))
+	for i, s := range p.Settings {
+		value, err := defaults.Read(m.runner, s.Key)
+		if err != nil {
+			return err
+		}
+		before[i] = value
+	}
+	for i, s := range p.Settings {
+		if err := defaults.Write(m.runner, s.Key, s.Value); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				defaults.Restore(m.runner, p.Settings[j].Key, before[j])
+			}
+			if s.Key.Domain == "com.apple.universalaccess" {
+				return fmt.Errorf("%w (accessibility settings need Full Disk Access for your terminal: System Settings › Privacy & Security)", err)
+			}
+			return err
+		}
+	}
+
+	m.presetMu.Lock()
+	if m.presetSaved == nil {
+		m.presetSaved = make(map[string][]defaults.Value)
+	}
+	// Re-applying a partly applied preset keeps the values from before the
+	// first time
+	if _, ok := m.presetSaved[p.Name]; !ok {
+		m.presetSaved[p.Name] = before
+	}
+	m.presetMu.Unlock()
+	return m.restartFor(p)
+}
+
+// restorePreset puts back the values the preset replaced, or macOS's own
+// defaults when it was applied in an earlier session
+func (m *Model) restorePreset(p preset) error {
+	m.presetMu.Lock()
+	before, ok := m.presetSaved[p.Name]
+	delete(m.presetSaved, p.Name)
+	m.presetMu.Unlock()
+
+	for i, s := range p.Settings {
+		value := defaults.Value{}
+		if ok {
+			value = before[i]
+		}
+		if err := defaults.Restore(m.runner, s.Key, value); err != nil {
+			return err
+		}
+	}
+	return m.restartFor(p)
+}
+
+func (m *Model) restartFor(p preset) error {
+	if p.Restart == "" {
+		return nil
+	}
+	if output, err := m.runner.CombinedOutput(exec.Command("killall", p.Restart)); err != nil {
+		return fmt.Errorf("restarting %s: %s", p.Restart, firstLine(output, err))
+	}
+	return nil
+}
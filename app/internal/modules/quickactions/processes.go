@@ -0,0 +1,250 @@
+package quickactions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/proctable"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The Kill Heavy Processes picker lists the heavyShown busiest processes,
+// and A marks those above heavyCPU
+const (
+	heavyShown = 15
+	heavyCPU   = 80.0
+)
+
+// procPicker is the Kill Heavy Processes view: the busiest processes, of
+// which the marked ones are signalled
+type procPicker struct {
+	procs   []proctable.Process
+	cursor  int
+	marked  map[int]bool // by PID
+	loading bool
+	err     error
+}
+
+type procsMsg struct {
+	procs []proctable.Process
+	err   error
+}
+
+// signalProcsMsg signals the picked processes once confirmed
+type signalProcsMsg struct {
+	procs  []proctable.Process
+	signal string // TERM or KILL
+}
+
+// readProcesses lists every process but Dev Cockpit itself
+func (m *Model) readProcesses() ([]proctable.Process, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), shortCommandTimeout)
+	defer cancel()
+	procs, err := proctable.Read(ctx, m.runner)
+	if err != nil {
+		return nil, err
+	}
+	own := os.Getpid()
+	others := procs[:0]
+	for _, proc := range procs {
+		if proc.PID != own {
+			others = append(others, proc)
+		}
+	}
+	return others, nil
+}
+
+// heavyProcesses returns the busiest processes, by CPU
+func (m *Model) heavyProcesses() ([]proctable.Process, error) {
+	procs, err := m.readProcesses()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(procs, func(i, j int) bool { return procs[i].CPU > procs[j].CPU })
+	return procs[:min(len(procs), heavyShown)], nil
+}
+
+// openProcesses opens the Kill Heavy Processes picker with nothing marked
+func (m *Model) openProcesses() tea.Cmd {
+	m.procs = &procPicker{marked: map[int]bool{}}
+	return m.loadProcesses()
+}
+
+func (m *Model) loadProcesses() tea.Cmd {
+	m.procs.loading = true
+	return func() tea.Msg {
+		procs, err := m.heavyProcesses()
+		return procsMsg{procs: procs, err: err}
+	}
+}
+
+// updateProcesses handles the picker's messages and keys
+func (m *Model) updateProcesses(msg tea.Msg) tea.Cmd {
+	p := m.procs
+	switch msg := msg.(type) {
+	case procsMsg:
+		p.procs, p.err, p.loading = msg.procs, msg.err, false
+		// Marks stay on the processes still listed
+		listed := map[int]bool{}
+		for _, proc := range p.procs {
+			listed[proc.PID] = p.marked[proc.PID]
+		}
+		p.marked = listed
+		p.cursor = min(p.cursor, max(len(p.procs)-1, 0))
+	case events.Nav:
+		p.cursor = msg.Move(p.cursor, len(p.procs))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.procs = nil
+		case " ":
+			if p.cursor < len(p.procs) {
+				pid := p.procs[p.cursor].PID
+				p.marked[pid] = !p.marked[pid]
+				p.cursor = min(p.cursor+1, len(p.procs)-1)
+			}
+		case "a":
+			for _, proc := range p.procs {
+				p.marked[proc.PID] = proc.CPU > heavyCPU
+			}
+		case "r":
+			return m.loadProcesses()
+		case "x":
+			return m.confirmSignal("TERM")
+		case "X":
+			return m.confirmSignal("KILL")
+		}
+	}
+	return nil
+}
+
+// targets are the marked processes, or the one under the cursor when none
+// is marked
+func (p *procPicker) targets() []proctable.Process {
+	var procs []proctable.Process
+	for _, proc := range p.procs {
+		if p.marked[proc.PID] {
+			procs = append(procs, proc)
+		}
+	}
+	if len(procs) == 0 && p.cursor < len(p.procs) {
+		procs = append(procs, p.procs[p.cursor])
+	}
+	return procs
+}
+
+// signalArgs is the kill command line sending signal to proc
+func signalArgs(proc proctable.Process, signal string) []string {
+	return []string{"kill", "-" + signal, strconv.Itoa(proc.PID)}
+}
+
+// confirmSignal lists the kill commands for the targets and asks first
+func (m *Model) confirmSignal(signal string) tea.Cmd {
+	procs := m.procs.targets()
+	if len(procs) == 0 {
+		return nil
+	}
+	lines := make([]string, len(procs))
+	for i, proc := range procs {
+		lines[i] = fmt.Sprintf("%s  # %s, %.0f%% CPU, %s", strings.Join(signalArgs(proc, signal), " "), proc.Name, proc.CPU, proctable.FormatMemory(proc.RSS))
+	}
+	req := dialog.Request{
+		Title:        fmt.Sprintf("Quit %d process(es)?", len(procs)),
+		Detail:       strings.Join(lines, "\n") + "\n\nSIGTERM asks each process to quit, so it can save its work first.",
+		ConfirmLabel: "Quit",
+		OnConfirm:    signalProcsMsg{procs: procs, signal: signal},
+	}
+	if signal == "KILL" {
+		req.Title = fmt.Sprintf("Force quit %d process(es)?", len(procs))
+		req.Detail = strings.Join(lines, "\n") + "\n\nSIGKILL stops each process at once. Unsaved work in it is lost."
+		req.ConfirmLabel = "Force Quit"
+		req.Destructive = true
+	}
+	return dialog.Confirm(req)
+}
+
+// signalProcesses signals the picked processes still running under the
+// same PID and name, so a PID taken by another program since isn't touched
+func (m *Model) signalProcesses(procs []proctable.Process, signal string) error {
+	current, err := m.readProcesses()
+	if err != nil {
+		return err
+	}
+	running := map[int]string{}
+	for _, proc := range current {
+		running[proc.PID] = proc.Name
+	}
+	signalled := 0
+	for _, proc := range procs {
+		if running[proc.PID] != proc.Name {
+			liveOutput.line(fmt.Sprintf("Skipped %s (%d): no longer running", proc.Name, proc.PID))
+			continue
+		}
+		args := signalArgs(proc, signal)
+		liveOutput.command(args...)
+		ctx, cancel := context.WithTimeout(context.Background(), shortCommandTimeout)
+		output, err := m.runner.CombinedOutput(exec.CommandContext(ctx, args[0], args[1:]...))
+		cancel()
+		if err != nil {
+			liveOutput.line(fmt.Sprintf("Couldn't signal %s (%d): %s", proc.Name, proc.PID, firstLine(output, err)))
+			continue
+		}
+		signalled++
+		logger.Info("Sent SIG%s to process %d (%s)", signal, proc.PID, proc.Name)
+	}
+	if signalled == 0 {
+		return fmt.Errorf("no process signalled")
+	}
+	return nil
+}
+
+// renderProcesses lists the busiest processes with their marks
+func (m *Model) renderProcesses() string {
+	p := m.procs
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	columnStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+	errStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+
+	lines := []string{titleStyle.Render("⚡ QUICK ACTIONS › KILL HEAVY PROCESSES"), ""}
+	switch {
+	case p.err != nil:
+		lines = append(lines, errStyle.Render("✗ "+p.err.Error()))
+	case p.loading && len(p.procs) == 0:
+		lines = append(lines, mutedStyle.Render("Reading the process table..."))
+	case len(p.procs) == 0:
+		lines = append(lines, mutedStyle.Render("No processes found"))
+	default:
+		nameWidth := min(max(m.width-36, 10), 40)
+		lines = append(lines, columnStyle.Render(fmt.Sprintf("      %7s %7s %9s  %s", "PID", "CPU", "MEMORY", "NAME")))
+		for i, proc := range p.procs {
+			mark := "[ ]"
+			if p.marked[proc.PID] {
+				mark = "[x]"
+			}
+			cpu := fmt.Sprintf("%6.1f%%", proc.CPU)
+			if proc.CPU > heavyCPU {
+				cpu = errStyle.Render(cpu)
+			}
+			row := fmt.Sprintf("%s %7d %s %9s  %s", mark, proc.PID, cpu, proctable.FormatMemory(proc.RSS), components.TruncateString(proc.Name, nameWidth))
+			if i == p.cursor {
+				lines = append(lines, selectedStyle.Render("▶ ")+row)
+			} else {
+				lines = append(lines, "  "+row)
+			}
+		}
+	}
+	lines = append(lines, "", mutedStyle.Render("↑/↓ Navigate • Space Mark • A Mark above 80% • X Quit • Shift+X Force quit • R Refresh • Esc Back"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
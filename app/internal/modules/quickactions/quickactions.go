@@ -2,24 +2,37 @@ package quickactions
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
 	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
 	sudohelper "github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
 	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const (
-	// Default timeout for commands to prevent hanging
+	// Default timeout for commands to prevent hanging, unless
+	// system.command_timeout sets another
 	defaultCommandTimeout = 30 * time.Second
 	shortCommandTimeout   = 5 * time.Second
 	longCommandTimeout    = 60 * time.Second
@@ -32,30 +45,64 @@ type Action struct {
 	Category     string
 	Command      func() error
 	RequiresSudo bool
+	// Confirm, when set, is shown in a confirmation dialog before running
+	Confirm string
+	// Plan, when set, works out the commands the action will run and what
+	// they touch, to list in the confirmation dialog; confirming runs the
+	// plan instead of Command
+	Plan func() (Plan, error)
+	// State, when set, reads what the action toggles, e.g. "Dark"
+	State func() string
+	// Open, when set, opens a view instead of running Command
+	Open func() tea.Cmd
+	// Timeout, when set, is how long the action usually needs, if longer
+	// than system.command_timeout
+	Timeout time.Duration
+	// Restart names the app relaunched after the defaults the action wrote
+	// are undone, e.g. Dock
+	Restart string
+	// CommandLines, when set, lists the command lines Command runs, for
+	// copying; a Plan lists its own
+	CommandLines func() []string
 }
 
 // Model represents the quick actions module state
 type Model struct {
 	config        *config.Config
+	runner        runner.Runner
 	width         int
 	height        int
 	actions       []Action
 	grouped       map[string][]Action
 	categories    []string
 	categoryIndex int
-	actionIndex   int
+	actionIndex   int // position in visible()
+	filter        components.ListFilter
 	running       bool
 	runningAction string
+	deadline      time.Time // when the running action times out
 	status        string
 	statusType    string // "success", "error", "info"
 	spinnerFrame  int
+	outputView    components.ScrollView // output of the running or last action
+	outputLog     []string
+	outputLines   chan string       // the stream outputLog is read from
+	states        map[string]string // from State, by action name
+	perms         *permInspector    // open Inspect Permissions view
+	history       *historyView      // open History view
+	procs         *procPicker       // open Kill Heavy Processes view
+
+	presetMu    sync.Mutex
+	presetSaved map[string][]defaults.Value // values a preset replaced, by preset name
 }
 
 // New creates a new quick actions module
 func New(cfg *config.Config) *Model {
 	m := &Model{
-		config:  cfg,
-		grouped: make(map[string][]Action),
+		config:     cfg,
+		runner:     runner.Default,
+		grouped:    make(map[string][]Action),
+		outputView: components.ScrollView{Height: 8, Follow: true},
 	}
 	m.initActions()
 	return m
@@ -66,49 +113,65 @@ func (m *Model) initActions() {
 		// Performance
 		{
 			Name:        "Kill Heavy Processes",
-			Description: "Terminate resource-intensive processes",
+			Description: "Pick busy processes to quit or force quit",
 			Category:    "Performance",
-			Command:     m.killHeavyProcesses,
+			Open:        m.openProcesses,
 		},
 		{
 			Name:         "Clear RAM",
 			Description:  "Purge inactive memory",
 			Category:     "Performance",
 			Command:      m.clearRAM,
+			CommandLines: lines(sudo("purge")),
 			RequiresSudo: true,
 		},
 		{
-			Name:        "Disable Animations",
-			Description: "Speed up UI by disabling animations",
-			Category:    "Performance",
-			Command:     m.disableAnimations,
+			Name:         "Disable Animations",
+			Description:  "Speed up UI by disabling animations",
+			Category:     "Performance",
+			Command:      m.disableAnimations,
+			CommandLines: disableAnimationsCommands,
+			Restart:      "Dock",
 		},
 		{
-			Name:        "Rebuild Launch Services",
-			Description: "Fix app associations and duplicates",
-			Category:    "Performance",
-			Command:     m.rebuildLaunchServices,
+			Name:         "Restore Animations",
+			Description:  "Put back the animation settings Disable Animations replaced",
+			Category:     "Performance",
+			Command:      m.restoreAnimations,
+			CommandLines: m.restoreAnimationsCommands,
+			Restart:      "Dock",
+		},
+		{
+			Name:         "Rebuild Launch Services",
+			Description:  "Fix app associations and duplicates",
+			Category:     "Performance",
+			Command:      m.rebuildLaunchServices,
+			CommandLines: lines(lsregister),
+			Timeout:      longCommandTimeout,
 		},
 
 		// Network Fixes
 		{
-			Name:        "Fix WiFi",
-			Description: "Reset WiFi configuration",
-			Category:    "Network",
-			Command:     m.fixWiFi,
+			Name:         "Fix WiFi",
+			Description:  "Reset WiFi configuration",
+			Category:     "Network",
+			Command:      m.fixWiFi,
+			CommandLines: lines(wifiCommands...),
 		},
 		{
 			Name:         "Flush DNS",
 			Description:  "Clear DNS cache",
 			Category:     "Network",
 			Command:      m.flushDNS,
+			CommandLines: lines(flushDNSCommands...),
 			RequiresSudo: true,
 		},
 		{
-			Name:        "Reset Network",
-			Description: "Complete network reset",
-			Category:    "Network",
-			Command:     m.resetNetwork,
+			Name:         "Reset Network",
+			Description:  "Complete network reset",
+			Category:     "Network",
+			Command:      m.resetNetwork,
+			CommandLines: resetNetworkCommands,
 		},
 
 		// System Fixes
@@ -117,6 +180,7 @@ func (m *Model) initActions() {
 			Description:  "Reset Bluetooth module",
 			Category:     "System",
 			Command:      m.fixBluetooth,
+			CommandLines: lines(sudo("pkill", "-9", "bluetoothd")),
 			RequiresSudo: true,
 		},
 		{
@@ -124,39 +188,65 @@ func (m *Model) initActions() {
 			Description:  "Reset Core Audio",
 			Category:     "System",
 			Command:      m.fixAudio,
+			CommandLines: lines(sudo("killall", "-9", "coreaudiod")),
 			RequiresSudo: true,
 		},
-		{
-			Name:        "Reset SMC",
-			Description: "Reset System Management Controller",
-			Category:    "System",
-			Command:     m.resetSMC,
-		},
-		{
-			Name:        "Reset NVRAM",
-			Description: "Reset Non-Volatile RAM",
-			Category:    "System",
-			Command:     m.resetNVRAM,
-		},
 		{
 			Name:         "Fix Spotlight",
 			Description:  "Rebuild Spotlight index",
 			Category:     "System",
 			Command:      m.fixSpotlight,
+			CommandLines: lines(sudo("mdutil", "-i", "off", "/"), sudo("mdutil", "-E", "/"), []string{"sleep", "2"}, sudo("mdutil", "-i", "on", "/")),
 			RequiresSudo: true,
+			Timeout:      longCommandTimeout,
 		},
 		{
 			Name:         "Fix Time Machine",
 			Description:  "Reset Time Machine and optimize",
 			Category:     "System",
 			Command:      m.fixTimeMachine,
+			CommandLines: fixTimeMachineCommands,
 			RequiresSudo: true,
 		},
 		{
-			Name:        "Fix Permissions",
-			Description: "Repair file permissions",
+			Name:        "Inspect Permissions",
+			Description: "Find files in a folder you don't own or can't change, and fix them",
 			Category:    "System",
-			Command:     m.fixPermissions,
+			Open:        m.openPermissions,
+		},
+
+		// Appearance
+		{
+			Name:         "Toggle Dark Mode",
+			Description:  "Switch between light and dark appearance",
+			Category:     "Appearance",
+			Command:      m.toggleDarkMode,
+			CommandLines: m.darkModeCommands,
+			State:        m.darkModeState,
+		},
+		{
+			Name:         "Toggle Night Shift",
+			Description:  "Warm the display colors (needs the nightlight CLI)",
+			Category:     "Appearance",
+			Command:      m.toggleNightShift,
+			CommandLines: lines([]string{"nightlight", "toggle"}),
+			State:        m.nightShiftState,
+		},
+		{
+			Name:         "Toggle Do Not Disturb",
+			Description:  "Silence notifications, e.g. while screen sharing (runs a Focus shortcut)",
+			Category:     "Appearance",
+			Command:      m.toggleDoNotDisturb,
+			CommandLines: m.doNotDisturbCommands,
+			State:        m.doNotDisturbState,
+		},
+		{
+			Name:         "Next Wallpaper",
+			Description:  "Rotate to the next image in the wallpaper folder",
+			Category:     "Appearance",
+			Command:      m.nextWallpaper,
+			CommandLines: m.nextWallpaperCommands,
+			State:        m.wallpaperState,
 		},
 
 		// Cleanup
@@ -164,24 +254,36 @@ func (m *Model) initActions() {
 			Name:        "Empty Trash",
 			Description: "Securely empty trash",
 			Category:    "Cleanup",
-			Command:     m.emptyTrash,
+			Plan:        m.planEmptyTrash,
 		},
 		{
 			Name:        "Clean Downloads",
 			Description: "Remove old downloads",
 			Category:    "Cleanup",
-			Command:     m.cleanDownloads,
+			Plan:        m.planCleanDownloads,
+			Confirm:     "Deleted files skip the Trash.",
 		},
 		{
 			Name:         "Purge Memory",
 			Description:  "Free up inactive RAM",
 			Category:     "Cleanup",
 			Command:      m.purgeMemory,
+			CommandLines: lines(sudo("purge")),
 			RequiresSudo: true,
 		},
 	}
 
-	m.categories = []string{"All", "Performance", "Network", "System", "Cleanup"}
+	m.actions = append(m.actions, m.dnsActions()...)
+	m.actions = append(m.actions, m.developerActions()...)
+	m.actions = append(m.actions, m.restartActions()...)
+	m.actions = append(m.actions, m.presetActions()...)
+
+	m.categories = []string{"All", "Performance", "Network", "System", "Developer", "Appearance", "Cleanup", "Restart", "Presets"}
+	// The grouped list counts the cursor through the categories in turn,
+	// so the actions keep to the same order
+	sort.SliceStable(m.actions, func(i, j int) bool {
+		return slices.Index(m.categories, m.actions[i].Category) < slices.Index(m.categories, m.actions[j].Category)
+	})
 	m.rebuildGroups()
 }
 
@@ -197,6 +299,7 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.outputView.Height = max(min(m.height/4, 10), 3)
 	case events.Focus:
 		m.running = false
 		m.runningAction = ""
@@ -204,9 +307,14 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.statusType = ""
 		m.spinnerFrame = 0
 		m.clampSelection()
+		return m, m.readStates()
 	case events.Blur:
 		m.running = false
 		m.runningAction = ""
+		m.filter.Reset()
+		m.perms = nil
+		m.history = nil
+		m.procs = nil
 
 	case spinnerTickMsg:
 		if m.running {
@@ -214,32 +322,85 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			return m, m.tickSpinner()
 		}
 
+	case events.Nav:
+		if m.history != nil {
+			return m, m.updateHistory(msg)
+		}
+		if m.procs != nil {
+			return m, m.updateProcesses(msg)
+		}
+		if m.perms != nil {
+			m.perms.cursor = msg.Move(m.perms.cursor, len(m.perms.entries))
+		} else if m.running {
+			m.outputView.Nav(msg)
+		} else {
+			m.actionIndex = msg.Move(m.actionIndex, len(m.visible()))
+		}
+
 	case tea.KeyMsg:
 		if m.running {
+			m.outputView.HandleKey(msg.String())
+			return m, nil
+		}
+		if m.perms != nil {
+			return m, m.handlePermsKey(msg)
+		}
+		if m.history != nil {
+			return m, m.updateHistory(msg)
+		}
+		if m.procs != nil {
+			return m, m.updateProcesses(msg)
+		}
+
+		if m.filter.HandleKey(msg) {
+			m.actionIndex = 0
+			return m, nil
+		}
+		if len(m.outputLog) > 0 && m.outputView.HandleKey(msg.String()) {
 			return m, nil
 		}
 
-		totalActions := len(m.actions)
+		visible := m.visible()
+		totalActions := len(visible)
 
 		switch msg.String() {
-		case "up", "k":
-			if m.actionIndex > 0 {
-				m.actionIndex--
-			}
-		case "down", "j":
-			if m.actionIndex < totalActions-1 {
-				m.actionIndex++
-			}
 		case "enter", " ":
 			if m.actionIndex < totalActions {
-				return m, m.executeAction(m.actions[m.actionIndex])
+				return m, m.startAction(m.actions[visible[m.actionIndex]])
 			}
 		case "f":
 			return m, m.fixAllCommon()
-		case "g":
-			m.actionIndex = 0
-		case "G":
-			m.actionIndex = totalActions - 1
+		case "h":
+			return m, m.openHistory()
+		}
+
+	case runActionMsg:
+		if m.running {
+			return m, nil
+		}
+		m.filter.Reset()
+		for i, action := range m.actions {
+			if action.Name == msg.name {
+				m.actionIndex = i
+				if msg.confirmed {
+					return m, m.executeAction(action)
+				}
+				return m, m.startAction(action)
+			}
+		}
+
+	case planMsg:
+		return m, m.confirmPlan(msg)
+
+	case runPlanMsg:
+		if m.running {
+			return m, nil
+		}
+		for _, action := range m.actions {
+			if action.Name == msg.name {
+				action.Command = msg.plan.Run
+				return m, m.executeAction(action)
+			}
 		}
 
 	case actionCompleteMsg:
@@ -252,6 +413,49 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		} else {
 			m.statusType = "error"
 		}
+		return m, tea.Batch(components.ResultToast(msg.message, showStatusMsg(msg)), m.readStates())
+
+	case showStatusMsg:
+		// Focus clears the status, so the toast brings it back
+		m.status = msg.message
+		m.statusType = "error"
+		if msg.success {
+			m.statusType = "success"
+		}
+
+	case statesMsg:
+		m.states = msg
+
+	case outputLineMsg:
+		return m, m.addOutput(msg)
+
+	case historyMsg:
+		if m.history != nil {
+			return m, m.updateHistory(msg)
+		}
+
+	case undoRunMsg:
+		if m.running {
+			return m, nil
+		}
+		m.history = nil
+		run := msg.run
+		return m, m.executeAction(Action{Name: "Undo " + run.Action, Command: func() error { return m.undoRun(run) }})
+
+	case procsMsg:
+		if m.procs != nil {
+			return m, m.updateProcesses(msg)
+		}
+
+	case signalProcsMsg:
+		if m.running {
+			return m, nil
+		}
+		m.procs = nil
+		return m, m.executeAction(Action{Name: "Kill Heavy Processes", Command: func() error { return m.signalProcesses(msg.procs, msg.signal) }})
+
+	case permsMsg, fixPermsMsg, permsFixedMsg:
+		return m, m.updatePerms(msg)
 	}
 
 	return m, nil
@@ -262,6 +466,15 @@ func (m *Model) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Loading..."
 	}
+	if m.perms != nil {
+		return m.renderPerms()
+	}
+	if m.history != nil {
+		return m.renderHistory()
+	}
+	if m.procs != nil {
+		return m.renderProcesses()
+	}
 
 	return m.renderSimpleList()
 }
@@ -271,22 +484,22 @@ func (m *Model) renderSimpleList() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00D9FF"))
+		Foreground(components.ColorPrimary)
 
 	categoryHeaderStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFA500")).
+		Foreground(components.ColorWarning).
 		MarginTop(1)
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666"))
+		Foreground(components.ColorMuted)
 
 	selectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	itemStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#DDD"))
+		Foreground(components.ColorText)
 
 	// Status line with proper styling
 	statusLine := ""
@@ -294,18 +507,23 @@ func (m *Model) renderSimpleList() string {
 		spinnerFrames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 		spinner := spinnerFrames[m.spinnerFrame%len(spinnerFrames)]
 		statusLine = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFA500")).
+			Foreground(components.ColorWarning).
 			Bold(true).
 			Render(fmt.Sprintf("%s Executing: %s", spinner, m.runningAction))
+		if left := time.Until(m.deadline); left > 0 {
+			statusLine += lipgloss.NewStyle().
+				Foreground(components.ColorMuted).
+				Render(fmt.Sprintf(" · %s left", left.Round(time.Second)))
+		}
 	} else if m.status != "" {
-		statusColor := "#0FD976"
+		statusColor := components.ColorSuccess
 		if m.statusType == "error" {
-			statusColor = "#FF6B6B"
+			statusColor = components.ColorError
 		} else if m.statusType == "info" {
-			statusColor = "#FFA500"
+			statusColor = components.ColorWarning
 		}
 		statusLine = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(statusColor)).
+			Foreground(statusColor).
 			Bold(true).
 			Render(m.status)
 	}
@@ -315,11 +533,68 @@ func (m *Model) renderSimpleList() string {
 	content = append(content, titleStyle.Render("⚡ QUICK ACTIONS"))
 	content = append(content, "")
 
-	// Group actions by category for display
-	categories := []string{"Performance", "Network", "System", "Cleanup"}
+	if bar := m.filter.View(len(m.visible()), len(m.actions)); bar != "" {
+		content = append(content, bar, "")
+	}
+
+	if m.filter.Query() != "" {
+		content = append(content, m.renderMatches(selectedStyle, itemStyle)...)
+	} else {
+		content = append(content, m.renderGrouped(categoryHeaderStyle, selectedStyle, itemStyle)...)
+	}
+
+	// Status and help at bottom
+	if statusLine != "" {
+		content = append(content, "")
+		content = append(content, statusLine)
+	}
+	help := "↑/↓ Navigate • Enter Execute • / Filter • F Fix All Common • H History • Esc Back"
+	if len(m.outputLog) > 0 {
+		content = append(content, "", m.renderOutput())
+		help = "↑/↓ Navigate • Enter Execute • PgUp/PgDn Output • / Filter • F Fix All • H History"
+		if m.running {
+			help = "↑/↓ PgUp/PgDn Scroll output"
+		}
+	}
+	content = append(content, "")
+	content = append(content, helpStyle.Render(help))
+
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderMatches lists filtered actions flat, best match first
+func (m *Model) renderMatches(selectedStyle, itemStyle lipgloss.Style) []string {
+	categoryStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	var content []string
+	for i, index := range m.visible() {
+		action := m.actions[index]
+		prefix := "     " // as wide as the lock
+		if action.RequiresSudo {
+			prefix = "  🔒 "
+		}
+		line := fmt.Sprintf("%s%-28s", prefix, action.Name)
+		if i == m.actionIndex {
+			content = append(content, selectedStyle.Render("▶ "+line)+categoryStyle.Render(action.Category))
+		} else {
+			content = append(content, itemStyle.Render("  "+line)+categoryStyle.Render(action.Category))
+		}
+	}
+	if len(content) == 0 {
+		content = append(content, categoryStyle.Render("  No matching actions"))
+	}
+	return content
+}
+
+// renderGrouped lists every action under its category header
+func (m *Model) renderGrouped(categoryHeaderStyle, selectedStyle, itemStyle lipgloss.Style) []string {
+	var content []string
+
+	stateStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
 	currentIndex := 0
 
-	for _, category := range categories {
+	for _, category := range m.categories[1:] {
 		categoryActions := m.grouped[category]
 		if len(categoryActions) == 0 {
 			continue
@@ -338,6 +613,9 @@ func (m *Model) renderSimpleList() string {
 			}
 
 			line := prefix + action.Name
+			if state := m.states[action.Name]; state != "" {
+				line = fmt.Sprintf("%s%-24s%s", prefix, action.Name, stateStyle.Render(state))
+			}
 
 			if currentIndex == m.actionIndex {
 				content = append(content, selectedStyle.Render("▶ "+line))
@@ -349,30 +627,21 @@ func (m *Model) renderSimpleList() string {
 		}
 		content = append(content, "") // spacing between categories
 	}
-
-	// Status and help at bottom
-	if statusLine != "" {
-		content = append(content, "")
-		content = append(content, statusLine)
-	}
-	content = append(content, "")
-	content = append(content, helpStyle.Render("↑/↓ Navigate • Enter Execute • F Fix All Common • Esc Back"))
-
-	return lipgloss.JoinVertical(lipgloss.Left, content...)
+	return content
 }
 
 func (m *Model) renderCategories(width int) string {
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#333")).
+		BorderForeground(components.ColorBorder).
 		Padding(1, 1).
 		Width(width)
 
 	itemStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888"))
+		Foreground(components.ColorSubtle)
 
 	activeStyle := itemStyle.Copy().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	var lines []string
@@ -392,19 +661,19 @@ func (m *Model) renderCategories(width int) string {
 func (m *Model) renderActions(width int) string {
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00D9FF")).
+		BorderForeground(components.ColorPrimary).
 		Padding(1, 2).
 		Width(width)
 
 	itemStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#DDD"))
+		Foreground(components.ColorText)
 
 	activeStyle := itemStyle.Copy().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888"))
+		Foreground(components.ColorSubtle)
 
 	actions := m.visibleActions()
 	if len(actions) == 0 {
@@ -440,7 +709,7 @@ func (m *Model) rebuildGroups() {
 	copy(all, m.actions)
 	groups["All"] = all
 
-	for _, category := range []string{"Performance", "Network", "System", "Cleanup"} {
+	for _, category := range m.categories[1:] {
 		groups[category] = []Action{}
 	}
 
@@ -462,8 +731,18 @@ func (m *Model) visibleActions() []Action {
 	return m.grouped[category]
 }
 
+// visible returns the indexes of the actions matching the filter, in list
+// order when there is no query
+func (m *Model) visible() []int {
+	texts := make([]string, len(m.actions))
+	for i, action := range m.actions {
+		texts[i] = action.Name + " " + action.Description + " " + action.Category
+	}
+	return m.filter.Matches(texts)
+}
+
 func (m *Model) clampSelection() {
-	actions := m.visibleActions()
+	actions := m.visible()
 	if len(actions) == 0 {
 		m.actionIndex = 0
 		return
@@ -490,9 +769,138 @@ func (m *Model) Title() string {
 	return "Quick Actions"
 }
 
+// Help describes the quick actions keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "QUICK ACTIONS",
+		Description: "One-key maintenance tasks grouped by category.",
+		Sections: []help.Section{{Title: "Keys", Bindings: []help.Binding{
+			{Key: "↑/↓", Desc: "Navigate actions"},
+			{Key: "Enter / Space", Desc: "Run the selected action"},
+			{Key: "F", Desc: "Fix all common issues"},
+			{Key: "H", Desc: "History of the actions run on this Mac, with their results and output"},
+			{Key: "PgUp / PgDn", Desc: "Scroll the output of the running or last action (↑/↓ too while it runs)"},
+			{Key: "/", Desc: "Filter actions"},
+			{Key: "y", Desc: "Copy the last result and its output"},
+			{Key: "Y", Desc: "Copy the commands the selected action runs"},
+		}}, {Title: "History", Bindings: []help.Binding{
+			{Key: "U", Desc: "Undo the selected run: put back the defaults values it replaced"},
+			{Key: "y", Desc: "Copy the selected run"},
+			{Key: "Y", Desc: "Copy the commands that undo it"},
+			{Key: "Esc", Desc: "Back to the actions"},
+		}}, {Title: "Kill Heavy Processes", Bindings: []help.Binding{
+			{Key: "Space", Desc: "Mark or unmark the selected process"},
+			{Key: "A", Desc: "Mark the processes above 80% CPU"},
+			{Key: "X", Desc: "Quit the marked processes, or the selected one, with SIGTERM"},
+			{Key: "Shift+X", Desc: "Force quit them with SIGKILL; unsaved work in them is lost"},
+			{Key: "Y", Desc: "Copy the kill commands"},
+			{Key: "R", Desc: "Refresh"},
+			{Key: "Esc", Desc: "Back to the actions"},
+		}}, {Title: "Inspect Permissions", Bindings: []help.Binding{
+			{Key: "Enter", Desc: "Open the selected folder"},
+			{Key: "U / Backspace", Desc: "Go up a folder"},
+			{Key: "P", Desc: "Type a path to inspect"},
+			{Key: "F", Desc: "Preview and apply fixes for the anomalies found"},
+			{Key: "R", Desc: "Rescan"},
+			{Key: "Esc", Desc: "Back to the actions"},
+		}}},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
 func (m *Model) HasOpenModal() bool {
-	return false
+	return m.filter.Active() || m.perms != nil || m.history != nil || m.procs != nil
+}
+
+// Copyable returns the last action's result and its commands' output
+func (m *Model) Copyable() string {
+	if m.filter.Typing() || m.running {
+		return ""
+	}
+	if m.history != nil {
+		if run, ok := m.history.selected(); ok {
+			return run.String()
+		}
+		return ""
+	}
+	if m.procs != nil {
+		if p := m.procs; p.cursor < len(p.procs) {
+			return p.procs[p.cursor].Name
+		}
+		return ""
+	}
+	if len(m.outputLog) > 0 && m.status != "" {
+		return m.status + "\n\n" + strings.Join(m.outputLog, "\n")
+	}
+	return m.status
+}
+
+// savedState is what the module keeps between launches
+type savedState struct {
+	Cursor int `json:"cursor"`
+}
+
+// SaveState returns the selected action position
+func (m *Model) SaveState() (json.RawMessage, error) {
+	// The filter isn't saved, so store the index in the full list
+	cursor := 0
+	if visible := m.visible(); m.actionIndex < len(visible) {
+		cursor = visible[m.actionIndex]
+	}
+	return json.Marshal(savedState{Cursor: cursor})
+}
+
+// RestoreState applies state saved by the previous launch
+func (m *Model) RestoreState(data json.RawMessage) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	m.actionIndex = saved.Cursor
+	return nil
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	commands := make([]palette.Command, 0, len(m.actions)+2)
+	for _, action := range m.actions {
+		commands = append(commands, palette.Command{
+			Title: action.Name,
+			Hint:  action.Description,
+			Msg:   runActionMsg{name: action.Name},
+		})
+	}
+	commands = append(commands, palette.Command{
+		Title: "Fix All Common Issues",
+		Hint:  "Run the most common fixes in one go",
+		Msg:   palette.Key("f"),
+	}, palette.Command{
+		Title: "Quick action history",
+		Hint:  "What was run on this Mac, when, and how it went",
+		Msg:   palette.Key("h"),
+	})
+	return commands
+}
+
+// startAction runs action, asking first if it has a Plan or Confirm
+// text, or opens its view
+func (m *Model) startAction(action Action) tea.Cmd {
+	if action.Open != nil {
+		return action.Open()
+	}
+	if action.Plan != nil {
+		return m.planAction(action)
+	}
+	if action.Confirm == "" {
+		return m.executeAction(action)
+	}
+	return dialog.Confirm(dialog.Request{
+		Title:        action.Name + "?",
+		Detail:       action.Confirm,
+		ConfirmLabel: "Run",
+		Destructive:  true,
+		OnConfirm:    runActionMsg{name: action.Name, confirmed: true},
+	})
 }
 
 func (m *Model) executeAction(action Action) tea.Cmd {
@@ -501,14 +909,23 @@ func (m *Model) executeAction(action Action) tea.Cmd {
 	m.status = ""
 	m.statusType = ""
 	m.spinnerFrame = 0
+	timeout := m.timeout(action)
+	m.deadline = time.Now().Add(timeout)
 	logger.Info("User triggered action: %s", action.Name)
 
 	// Start spinner animation
 	spinnerCmd := m.tickSpinner()
+	lines, waitCmd := m.startOutput()
 
 	return tea.Batch(spinnerCmd, func() tea.Msg {
 		logger.Debug("Executing action: %s (RequiresSudo: %v)", action.Name, action.RequiresSudo)
-		err := action.Command()
+		started, mark := time.Now(), lastChangeID()
+		err := runWithin(timeout, action.Command)
+		output := liveOutput.stop(lines)
+		if errors.Is(err, errTimedOut) {
+			err = fmt.Errorf("timed out after %v; set system.timeouts.%s in config.yaml to allow longer",
+				timeout, config.ModuleKey(action.Name))
+		}
 
 		success := err == nil
 		message := ""
@@ -519,9 +936,13 @@ func (m *Model) executeAction(action Action) tea.Cmd {
 			message = fmt.Sprintf("✓ %s completed successfully", action.Name)
 			logger.Info("Action completed successfully: %s", action.Name)
 		}
+		m.recordRun(Run{
+			Time: started, Action: action.Name, Duration: time.Since(started), Success: success, Result: message, Output: output,
+			Defaults: m.defaultsSince(mark), Restart: action.Restart,
+		})
 
 		return actionCompleteMsg{message: message, success: success}
-	})
+	}, waitCmd)
 }
 
 func (m *Model) fixAllCommon() tea.Cmd {
@@ -530,12 +951,15 @@ func (m *Model) fixAllCommon() tea.Cmd {
 	m.status = ""
 	m.statusType = ""
 	m.spinnerFrame = 0
+	m.deadline = time.Time{}
 	logger.Info("Starting common quick fixes")
 
 	// Start spinner animation
 	spinnerCmd := m.tickSpinner()
+	lines, waitCmd := m.startOutput()
 
 	return tea.Batch(spinnerCmd, func() tea.Msg {
+		started := time.Now()
 		fixed := 0
 		failed := 0
 
@@ -543,7 +967,6 @@ func (m *Model) fixAllCommon() tea.Cmd {
 		fixes := []func() error{
 			m.flushDNS,
 			m.clearRAM,
-			m.fixPermissions,
 			m.rebuildLaunchServices,
 		}
 
@@ -561,74 +984,76 @@ func (m *Model) fixAllCommon() tea.Cmd {
 			success = false
 			message = fmt.Sprintf("⚠ Fixed %d issues (%d failed)", fixed, failed)
 		}
+		output := liveOutput.stop(lines)
+		m.recordRun(Run{Time: started, Action: "Fix All Common Issues", Duration: time.Since(started), Success: success, Result: message, Output: output})
 
 		return actionCompleteMsg{message: message, success: success}
-	})
+	}, waitCmd)
 }
 
 // Action implementations
-func (m *Model) killHeavyProcesses() error {
-	// Get processes using more than 80% CPU
-	cmd := "ps aux | awk '$3 > 80 && NR > 1 {print $2}' | head -5"
-	output, err := runShellWithTimeoutOutput(shortCommandTimeout, cmd)
-	if err != nil {
-		return fmt.Errorf("failed to get heavy processes: %v", err)
-	}
-
-	pids := strings.Fields(strings.TrimSpace(string(output)))
-	killed := 0
-
-	for _, pid := range pids {
-		if pid != "" {
-			logger.Debug("Attempting to kill process %s", pid)
-			if err := runCommandWithTimeout(shortCommandTimeout, "kill", "-9", pid); err == nil {
-				killed++
-				logger.Info("Killed process %s", pid)
-			} else {
-				logger.Warn("Failed to kill process %s: %v", pid, err)
-			}
-		}
-	}
-
-	if killed == 0 {
-		return fmt.Errorf("no heavy processes found or killed")
-	}
-
-	logger.Info("Killed %d heavy processes", killed)
-	return nil
-}
-
 func (m *Model) clearRAM() error {
 	// The ONLY reliable way to clear RAM on macOS is with sudo purge
-	return executeSudoCommand("purge")
+	return executeSudoCommand(m.timeoutFor("Clear RAM"), "purge")
 }
 
-func (m *Model) disableAnimations() error {
-	commands := [][]string{
-		{"defaults", "write", "NSGlobalDomain", "NSAutomaticWindowAnimationsEnabled", "-bool", "false"},
-		{"defaults", "write", "com.apple.dock", "expose-animation-duration", "-float", "0.1"},
-		{"defaults", "write", "com.apple.dock", "autohide-time-modifier", "-float", "0"},
-		{"defaults", "write", "NSGlobalDomain", "NSWindowResizeTime", "-float", "0.001"},
-	}
+// animationSettings are the defaults Disable Animations writes
+var animationSettings = []struct {
+	key   defaults.Key
+	value string
+}{
+	{defaults.Key{Domain: "NSGlobalDomain", Name: "NSAutomaticWindowAnimationsEnabled", Type: defaults.Bool}, "false"},
+	{defaults.Key{Domain: "com.apple.dock", Name: "expose-animation-duration", Type: defaults.Float}, "0.1"},
+	{defaults.Key{Domain: "com.apple.dock", Name: "autohide-time-modifier", Type: defaults.Float}, "0"},
+	{defaults.Key{Domain: "NSGlobalDomain", Name: "NSWindowResizeTime", Type: defaults.Float}, "0.001"},
+}
 
-	for _, cmd := range commands {
-		if err := runCommandWithTimeout(shortCommandTimeout, cmd[0], cmd[1:]...); err != nil {
+func (m *Model) disableAnimations() error {
+	// Each write is recorded so it can be reverted from Settings › Changes
+	for _, s := range animationSettings {
+		if err := changes.WriteDefault(m.runner, m.Title(), s.key, s.value, m.restartDock); err != nil {
 			logger.Warn("Failed to set animation preference: %v", err)
 		}
 	}
 
 	// Restart Dock to apply changes
 	logger.Info("Restarting Dock to apply animation changes")
-	return runCommandWithTimeout(shortCommandTimeout, "killall", "Dock")
+	return m.restartDock()
 }
 
-func (m *Model) rebuildLaunchServices() error {
-	// Try the lsregister command with timeout
-	lsregisterPath := "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+// restoreAnimations undoes the last Disable Animations in the history, or
+// puts back macOS's own values when it isn't there
+func (m *Model) restoreAnimations() error {
+	runs, err := loadHistory(m.historyPath())
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if run.Action == "Disable Animations" && len(run.Defaults) > 0 {
+			return m.undoRun(run)
+		}
+	}
+	for _, s := range animationSettings {
+		if err := defaults.Delete(m.runner, s.key); err != nil {
+			return err
+		}
+	}
+	return m.restartDock()
+}
+
+func (m *Model) restartDock() error {
+	return m.restartProcess("Dock")
+}
+
+// lsregister rebuilds the Launch Services database
+var lsregister = []string{
+	"/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister",
+	"-kill", "-r", "-domain", "local", "-domain", "system", "-domain", "user",
+}
 
+func (m *Model) rebuildLaunchServices() error {
 	logger.Info("Rebuilding Launch Services database")
-	err := runCommandWithTimeout(longCommandTimeout, lsregisterPath,
-		"-kill", "-r", "-domain", "local", "-domain", "system", "-domain", "user")
+	err := runCommandWithTimeout(m.timeoutFor("Rebuild Launch Services"), lsregister[0], lsregister[1:]...)
 
 	if err != nil {
 		logger.Error("Failed to rebuild launch services: %v", err)
@@ -698,8 +1123,9 @@ func (m *Model) fixWiFi() error {
 
 func (m *Model) flushDNS() error {
 	// Proper DNS flush requires sudo for full effectiveness
-	err1 := executeSudoCommand("dscacheutil", "-flushcache")
-	err2 := executeSudoCommand("killall", "-HUP", "mDNSResponder")
+	timeout := m.timeoutFor("Flush DNS")
+	err1 := executeSudoCommand(timeout, "dscacheutil", "-flushcache")
+	err2 := executeSudoCommand(timeout, "killall", "-HUP", "mDNSResponder")
 
 	// Both commands should succeed for proper DNS flush
 	if err1 != nil && err2 != nil {
@@ -710,12 +1136,12 @@ func (m *Model) flushDNS() error {
 }
 
 // Helper function for sudo operations with proper error handling
-func executeSudoCommand(command string, args ...string) error {
+func executeSudoCommand(timeout time.Duration, command string, args ...string) error {
 	fullCmd := fmt.Sprintf("%s %v", command, args)
 	logger.Debug("executeSudoCommand: Attempting command: %s", fullCmd)
 
 	// First try without sudo to see if it works (with timeout)
-	output, err := runCommandWithTimeoutOutput(defaultCommandTimeout, command, args...)
+	output, err := runCommandWithTimeoutOutput(timeout, command, args...)
 
 	if err == nil {
 		logger.Info("Command succeeded without sudo: %s", fullCmd)
@@ -726,7 +1152,9 @@ func executeSudoCommand(command string, args ...string) error {
 	logger.Debug("Command failed without sudo: %s, error: %v, output: %s", fullCmd, err, string(output))
 
 	logger.Info("Attempting with sudo: sudo %s", fullCmd)
+	liveOutput.command(append([]string{"sudo", command}, args...)...)
 	sudoOutput, sudoErr := sudohelper.Run(command, args...)
+	liveOutput.text(sudoOutput)
 	if sudoErr != nil {
 		if errors.Is(sudoErr, sudohelper.ErrCancelled) {
 			logger.Warn("Sudo authentication cancelled by user for command: %s", fullCmd)
@@ -747,11 +1175,11 @@ func executeSudoCommand(command string, args ...string) error {
 }
 
 // Helper function to run shell command with sudo
-func executeSudoShell(shellCmd string) error {
+func executeSudoShell(timeout time.Duration, shellCmd string) error {
 	logger.Debug("executeSudoShell: Attempting shell command: %s", shellCmd)
 
 	// Try without sudo first (with timeout)
-	output, err := runShellWithTimeoutOutput(defaultCommandTimeout, shellCmd)
+	output, err := runShellWithTimeoutOutput(timeout, shellCmd)
 
 	if err == nil {
 		logger.Info("Shell command succeeded without sudo: %s", shellCmd)
@@ -762,7 +1190,9 @@ func executeSudoShell(shellCmd string) error {
 	logger.Debug("Shell command failed without sudo: %s, error: %v, output: %s", shellCmd, err, string(output))
 
 	logger.Info("Attempting shell with sudo: sudo %s", shellCmd)
+	liveOutput.command("sudo", "sh", "-c", shellCmd)
 	sudoOutput, sudoErr := sudohelper.RunShell(shellCmd)
+	liveOutput.text(sudoOutput)
 	if sudoErr != nil {
 		if errors.Is(sudoErr, sudohelper.ErrCancelled) {
 			logger.Warn("Sudo authentication cancelled by user for shell command: %s", shellCmd)
@@ -843,7 +1273,7 @@ func (m *Model) resetNetwork() error {
 				success++
 
 				// Try to renew DHCP
-				if err := runCommandWithTimeout(defaultCommandTimeout, "networksetup", "-setdhcp", service); err == nil {
+				if err := runCommandWithTimeout(m.timeoutFor("Reset Network"), "networksetup", "-setdhcp", service); err == nil {
 					logger.Info("Renewed DHCP for: %s", service)
 					success++
 				}
@@ -879,7 +1309,8 @@ func (m *Model) fixBluetooth() error {
 
 	// Method 1: Kill and restart bluetoothd (modern approach)
 	logger.Info("Attempting to restart Bluetooth daemon")
-	err1 := executeSudoCommand("pkill", "-9", "bluetoothd")
+	timeout := m.timeoutFor("Fix Bluetooth")
+	err1 := executeSudoCommand(timeout, "pkill", "-9", "bluetoothd")
 
 	// Give it time to restart automatically
 	if err1 == nil {
@@ -891,7 +1322,7 @@ func (m *Model) fixBluetooth() error {
 	// Method 2: Toggle Bluetooth via blueutil if available
 	logger.Info("Trying alternative Bluetooth toggle method")
 	toggleCmd := "blueutil -p 0 && sleep 2 && blueutil -p 1"
-	if err := runShellWithTimeout(defaultCommandTimeout, toggleCmd); err == nil {
+	if err := runShellWithTimeout(timeout, toggleCmd); err == nil {
 		logger.Info("Bluetooth toggled successfully via blueutil")
 		return nil
 	}
@@ -915,7 +1346,7 @@ func (m *Model) fixAudio() error {
 	logger.Info("Starting audio system reset")
 
 	// Kill Core Audio daemon (requires sudo, auto-restarts)
-	err := executeSudoCommand("killall", "-9", "coreaudiod")
+	err := executeSudoCommand(m.timeoutFor("Fix Audio"), "killall", "-9", "coreaudiod")
 	if err != nil {
 		logger.Error("Failed to restart audio daemon: %v", err)
 		return fmt.Errorf("audio reset requires admin privileges")
@@ -927,30 +1358,22 @@ func (m *Model) fixAudio() error {
 	return nil
 }
 
-func (m *Model) resetSMC() error {
-	// SMC reset is hardware-specific
-	// This would need to detect Mac model and provide instructions
-	return fmt.Errorf("SMC reset requires manual intervention: Shut down, press Shift-Control-Option on left side + power button")
-}
-
-func (m *Model) resetNVRAM() error {
-	// NVRAM reset requires admin privileges - provide instructions instead
-	return fmt.Errorf("NVRAM reset requires manual steps: Shut down Mac, then hold Option+Command+P+R during startup")
-}
-
+// SMC and NVRAM resets happen at startup with steps that depend on the
+// model, so these point to the System module's guides rather than guess
 func (m *Model) fixSpotlight() error {
 	logger.Info("Starting Spotlight reindex")
+	timeout := m.timeoutFor("Fix Spotlight")
 
 	// System-wide Spotlight reindexing requires sudo
 	logger.Info("Disabling Spotlight indexing")
-	err1 := executeSudoCommand("mdutil", "-i", "off", "/")
+	err1 := executeSudoCommand(timeout, "mdutil", "-i", "off", "/")
 	if err1 != nil {
 		logger.Error("Failed to disable Spotlight: %v", err1)
 		return fmt.Errorf("spotlight reset requires admin privileges")
 	}
 
 	logger.Info("Erasing Spotlight index")
-	err2 := executeSudoCommand("mdutil", "-E", "/")
+	err2 := executeSudoCommand(timeout, "mdutil", "-E", "/")
 	if err2 != nil {
 		logger.Warn("Failed to erase index, continuing anyway: %v", err2)
 	}
@@ -959,7 +1382,7 @@ func (m *Model) fixSpotlight() error {
 	time.Sleep(2 * time.Second)
 
 	logger.Info("Re-enabling Spotlight indexing")
-	err3 := executeSudoCommand("mdutil", "-i", "on", "/")
+	err3 := executeSudoCommand(timeout, "mdutil", "-i", "on", "/")
 	if err3 != nil {
 		logger.Error("Failed to re-enable Spotlight: %v", err3)
 		return err3
@@ -1011,57 +1434,8 @@ func (m *Model) fixTimeMachine() error {
 	return nil
 }
 
-func (m *Model) fixPermissions() error {
-	logger.Info("Starting permissions repair")
-	homeDir, _ := os.UserHomeDir()
-	success := 0
-
-	// Fix home directory permissions (non-recursive, safer)
-	logger.Info("Fixing home directory permissions")
-	chmodCmd := fmt.Sprintf("chmod 755 %s", homeDir)
-	if err := runShellWithTimeout(shortCommandTimeout, chmodCmd); err == nil {
-		logger.Info("Home directory permissions fixed")
-		success++
-	} else {
-		logger.Warn("Failed to fix home permissions: %v", err)
-	}
-
-	// Fix common subdirectories
-	commonDirs := []string{
-		filepath.Join(homeDir, "Desktop"),
-		filepath.Join(homeDir, "Documents"),
-		filepath.Join(homeDir, "Downloads"),
-	}
-
-	for _, dir := range commonDirs {
-		if _, err := os.Stat(dir); err == nil {
-			chmodCmd := fmt.Sprintf("chmod 755 %s", dir)
-			if err := runShellWithTimeout(shortCommandTimeout, chmodCmd); err == nil {
-				logger.Debug("Fixed permissions for: %s", dir)
-				success++
-			}
-		}
-	}
-
-	// Verify disk (read-only, safe operation)
-	logger.Info("Verifying disk permissions")
-	if err := runCommandWithTimeout(defaultCommandTimeout, "diskutil", "verifyVolume", "/"); err == nil {
-		logger.Info("Disk verification completed")
-		success++
-	} else {
-		logger.Warn("Disk verification failed: %v", err)
-	}
-
-	if success == 0 {
-		return fmt.Errorf("permission repair failed")
-	}
-
-	logger.Info("Permissions repair completed (%d operations successful)", success)
-	return nil
-}
-
 // emptyTrashInternal performs robust trash clean with multiple fallbacks.
-func emptyTrashInternal() error {
+func emptyTrashInternal(timeout time.Duration) error {
 	logger.Info("=== Starting Empty Trash operation ===")
 
 	homeDir, _ := os.UserHomeDir()
@@ -1070,7 +1444,7 @@ func emptyTrashInternal() error {
 
 	if _, err := os.Stat(trashPath); os.IsNotExist(err) {
 		logger.Error("Trash directory not found: %s", trashPath)
-		return fmt.Errorf("trash directory not found")
+		return clierr.New(clierr.DependencyMissing, "trash directory not found")
 	}
 
 	// Count items before (with timeout)
@@ -1093,7 +1467,7 @@ func emptyTrashInternal() error {
 	runShellWithTimeout(shortCommandTimeout, chflagsCmd)
 
 	rmCmd := fmt.Sprintf("rm -rf %s/* %s/.[!.]* 2>/dev/null || true", trashPath, trashPath)
-	if err := runShellWithTimeout(defaultCommandTimeout, rmCmd); err == nil {
+	if err := runShellWithTimeout(timeout, rmCmd); err == nil {
 		logger.Info("Direct removal completed")
 		success = true
 	} else {
@@ -1105,11 +1479,11 @@ func emptyTrashInternal() error {
 		logger.Info("Attempting find -delete...")
 		// First clear flags
 		findFlagsCmd := fmt.Sprintf("find %s -mindepth 1 -exec chflags nouchg,nouappnd {} + 2>/dev/null || true", trashPath)
-		runShellWithTimeout(defaultCommandTimeout, findFlagsCmd)
+		runShellWithTimeout(timeout, findFlagsCmd)
 
 		// Then delete
 		findDelCmd := fmt.Sprintf("find %s -mindepth 1 -delete 2>/dev/null", trashPath)
-		if err := runShellWithTimeout(defaultCommandTimeout, findDelCmd); err == nil {
+		if err := runShellWithTimeout(timeout, findDelCmd); err == nil {
 			logger.Info("Find deletion completed")
 			success = true
 		} else {
@@ -1121,7 +1495,7 @@ func emptyTrashInternal() error {
 	if !success {
 		logger.Info("Attempting sudo removal for locked files...")
 		sudoCmd := fmt.Sprintf("chflags -R nouchg,nouappnd %s/* 2>/dev/null; rm -rf %s/* %s/.[!.]* 2>/dev/null", trashPath, trashPath, trashPath)
-		if err := executeSudoShell(sudoCmd); err == nil {
+		if err := executeSudoShell(timeout, sudoCmd); err == nil {
 			logger.Info("Sudo removal succeeded")
 			success = true
 		} else {
@@ -1142,77 +1516,78 @@ func emptyTrashInternal() error {
 
 	if itemsAfter < itemsBefore {
 		logger.Info("Partially cleaned: removed %d items, %d remain", itemsBefore-itemsAfter, itemsAfter)
-		return fmt.Errorf("partially cleaned: %d items remain (some may be in use)", itemsAfter)
+		return clierr.New(clierr.PartialFailure, "partially cleaned: %d items remain (some may be in use)", itemsAfter)
 	}
 
 	return fmt.Errorf("failed to empty trash: %d items remain", itemsAfter)
 }
 
 // EmptyTrash exposes the operation for CLI usage.
-func EmptyTrash() error { return emptyTrashInternal() }
+func EmptyTrash() error { return emptyTrashInternal(defaultCommandTimeout) }
 
-func (m *Model) emptyTrash() error { return emptyTrashInternal() }
-
-func (m *Model) cleanDownloads() error {
-	logger.Info("Starting Downloads cleanup")
-	homeDir, _ := os.UserHomeDir()
-	downloadsPath := filepath.Join(homeDir, "Downloads")
+func (m *Model) purgeMemory() error {
+	// Same as clearRAM - requires sudo purge
+	return executeSudoCommand(m.timeoutFor("Purge Memory"), "purge")
+}
 
-	// Verify Downloads directory exists
-	if _, err := os.Stat(downloadsPath); os.IsNotExist(err) {
-		logger.Error("Downloads directory not found: %s", downloadsPath)
-		return fmt.Errorf("downloads directory not found")
-	}
+// Message types
+type actionCompleteMsg struct {
+	message string
+	success bool
+}
 
-	// Count files before cleanup
-	countCmd := fmt.Sprintf("find %s -type f -mtime +30 2>/dev/null | wc -l", downloadsPath)
-	beforeOutput, _ := runShellWithTimeoutOutput(shortCommandTimeout, countCmd)
-	var filesBefore int
-	fmt.Sscanf(strings.TrimSpace(string(beforeOutput)), "%d", &filesBefore)
-	logger.Info("Files older than 30 days in Downloads: %d", filesBefore)
+// showStatusMsg shows a finished action's result again, from its toast
+type showStatusMsg actionCompleteMsg
 
-	if filesBefore == 0 {
-		logger.Info("No old files to clean in Downloads")
-		return nil
-	}
+// runActionMsg runs the named action, e.g. when chosen from the command
+// palette; confirmed skips the action's confirmation dialog
+type runActionMsg struct {
+	name      string
+	confirmed bool
+}
 
-	// Remove files older than 30 days (with timeout to prevent hanging)
-	logger.Info("Removing files older than 30 days from Downloads")
-	deleteCmd := fmt.Sprintf("find %s -type f -mtime +30 -delete 2>/dev/null", downloadsPath)
-	err := runShellWithTimeout(defaultCommandTimeout, deleteCmd)
+type spinnerTickMsg struct{}
 
-	if err != nil {
-		logger.Error("Failed to clean Downloads: %v", err)
-		return fmt.Errorf("failed to clean downloads: %v", err)
+// timeout is how long action may run: its entry in system.timeouts, else
+// the longer of system.command_timeout and the action's own Timeout
+func (m *Model) timeout(action Action) time.Duration {
+	fallback := defaultCommandTimeout
+	if m.config != nil && m.config.System.CommandTimeout > 0 {
+		fallback = time.Duration(m.config.System.CommandTimeout) * time.Second
 	}
-
-	// Count files after cleanup
-	afterOutput, _ := runShellWithTimeoutOutput(shortCommandTimeout, countCmd)
-	var filesAfter int
-	fmt.Sscanf(strings.TrimSpace(string(afterOutput)), "%d", &filesAfter)
-
-	removed := filesBefore - filesAfter
-	logger.Info("Removed %d old files from Downloads", removed)
-
-	if removed == 0 && filesBefore > 0 {
-		return fmt.Errorf("could not remove old files (they may be in use)")
+	fallback = max(fallback, action.Timeout)
+	if m.config == nil {
+		return fallback
 	}
-
-	return nil
+	return m.config.System.Timeout(action.Name, fallback)
 }
 
-func (m *Model) purgeMemory() error {
-	// Same as clearRAM - requires sudo purge
-	return executeSudoCommand("purge")
+// timeoutFor is the timeout of the action named name, for the commands
+// it runs
+func (m *Model) timeoutFor(name string) time.Duration {
+	for _, action := range m.actions {
+		if action.Name == name {
+			return m.timeout(action)
+		}
+	}
+	return m.timeout(Action{Name: name})
 }
 
-// Message types
-type actionCompleteMsg struct {
-	message string
-	success bool
-}
+// errTimedOut is returned by runWithin when the command ran too long
+var errTimedOut = errors.New("timed out")
 
-type spinnerTickMsg struct{}
+// runWithin runs command, giving up on it after timeout. The commands it
+// runs have the same timeout, so it doesn't run on for long.
+func runWithin(timeout time.Duration, command func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- command() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return errTimedOut
+	}
+}
 
 func (m *Model) tickSpinner() tea.Cmd {
 	return tea.Tick(time.Millisecond*100, func(t time.Time) tea.Msg {
@@ -1226,7 +1601,7 @@ func runCommandWithTimeout(timeout time.Duration, name string, args ...string) e
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, name, args...)
-	err := cmd.Run()
+	_, err := liveOutput.stream(cmd)
 
 	if ctx.Err() == context.DeadlineExceeded {
 		logger.Warn("Command timed out after %v: %s %v", timeout, name, args)
@@ -1242,14 +1617,14 @@ func runCommandWithTimeoutOutput(timeout time.Duration, name string, args ...str
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, name, args...)
-	output, err := cmd.CombinedOutput()
+	out, err := liveOutput.stream(cmd)
 
 	if ctx.Err() == context.DeadlineExceeded {
 		logger.Warn("Command timed out after %v: %s %v", timeout, name, args)
 		return nil, fmt.Errorf("command timed out")
 	}
 
-	return output, err
+	return out, err
 }
 
 // Helper function to run shell command with timeout
@@ -1258,7 +1633,7 @@ func runShellWithTimeout(timeout time.Duration, shellCmd string) error {
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "sh", "-c", shellCmd)
-	err := cmd.Run()
+	_, err := liveOutput.stream(cmd)
 
 	if ctx.Err() == context.DeadlineExceeded {
 		logger.Warn("Shell command timed out after %v: %s", timeout, shellCmd)
@@ -1274,12 +1649,12 @@ func runShellWithTimeoutOutput(timeout time.Duration, shellCmd string) ([]byte,
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "sh", "-c", shellCmd)
-	output, err := cmd.CombinedOutput()
+	out, err := liveOutput.stream(cmd)
 
 	if ctx.Err() == context.DeadlineExceeded {
 		logger.Warn("Shell command timed out after %v: %s", timeout, shellCmd)
 		return nil, fmt.Errorf("command timed out")
 	}
 
-	return output, err
+	return out, err
 }
@@ -0,0 +1,700 @@
+package quickactions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := New(nil)
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			golden.RequireEqual(t, m.View())
+		})
+	}
+}
+
+func TestFilterSnapshot(t *testing.T) {
+	m := New(nil)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	for _, key := range "/dns" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+	}
+	golden.RequireEqual(t, m.View())
+
+	if visible := m.visible(); len(visible) == 0 || m.actions[visible[0]].Name != "Flush DNS" {
+		t.Errorf("best match = %v, want Flush DNS first", visible)
+	}
+}
+
+// The cursor in the grouped list must land on the action Enter runs
+func TestGroupedCursorMatchesActions(t *testing.T) {
+	m := New(nil)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 200})
+	for i, index := range m.visible() {
+		m.actionIndex = i
+		name := m.actions[index].Name
+		_, line, _ := strings.Cut(m.View(), "▶ ")
+		if line, _, _ = strings.Cut(line, "\n"); !strings.HasPrefix(strings.TrimLeft(line, " 🔒"), name) {
+			t.Fatalf("cursor %d is on %q, want %s", i, line, name)
+		}
+	}
+}
+
+func TestAppearanceStates(t *testing.T) {
+	fake := runner.NewFake().
+		Set("defaults read -g AppleInterfaceStyle", "Dark\n", nil).
+		Set("nightlight status", "Schedule:\n=> sunset to sunrise\nNight Shift: off\n", nil).
+		Set(currentDesktop, "/Users/caio/Pictures/Wallpapers/dunes.jpg\n", nil)
+	m := New(nil)
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+
+	_, cmd := m.Update(events.Focus{})
+	m.Update(cmd())
+	want := map[string]string{"Toggle Dark Mode": "Dark", "Toggle Night Shift": "Off", "Next Wallpaper": "dunes.jpg"}
+	for name, state := range want {
+		if m.states[name] != state {
+			t.Errorf("%s state = %q, want %q", name, m.states[name], state)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "Toggle Dark Mode        Dark") {
+		t.Errorf("state not shown:\n%s", view)
+	}
+
+	fake.Missing("nightlight")
+	if err := m.toggleNightShift(); err == nil || !strings.Contains(err.Error(), "brew install") {
+		t.Errorf("err = %v, want an install hint", err)
+	}
+}
+
+const currentDesktop = `osascript -e tell application "System Events" to get picture of current desktop`
+
+func TestNextWallpaperRotates(t *testing.T) {
+	t.Cleanup(changes.Clear)
+	dir := t.TempDir()
+	for _, name := range []string{"b.png", "a.jpg", "c.HEIC", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{}
+	cfg.Modules.QuickActions.WallpaperDir = dir
+	set := func(path string) string {
+		return `osascript -e on run argv -e tell application "System Events" to tell every desktop to set picture to item 1 of argv -e end run ` + path
+	}
+
+	tests := []struct{ current, next string }{
+		{"a.jpg", "b.png"},
+		{"c.HEIC", "a.jpg"},        // wraps around
+		{"elsewhere.jpg", "a.jpg"}, // not in the folder
+	}
+	for _, tt := range tests {
+		fake := runner.NewFake().
+			Set(currentDesktop, filepath.Join(dir, tt.current)+"\n", nil).
+			Set(set(filepath.Join(dir, tt.next)), "", nil)
+		m := New(cfg)
+		m.runner = fake
+		if err := m.nextWallpaper(); err != nil {
+			t.Errorf("after %s: %v", tt.current, err)
+		}
+	}
+}
+
+func TestPresetApplyAndRevert(t *testing.T) {
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+	var calm preset
+	for _, p := range presets {
+		if p.Name == "Calm Motion" {
+			calm = p
+		}
+	}
+	exit1 := errors.New("exit status 1")
+	fake := runner.NewFake().
+		Set("defaults read com.apple.universalaccess reduceMotion", "0\n", nil).
+		Set("defaults read NSGlobalDomain NSAutomaticWindowAnimationsEnabled", "does not exist", exit1).
+		Set("defaults read com.apple.dock launchanim", "does not exist", exit1).
+		Set("defaults write com.apple.universalaccess reduceMotion -bool true", "", nil).
+		Set("defaults write NSGlobalDomain NSAutomaticWindowAnimationsEnabled -bool false", "", nil).
+		Set("defaults write com.apple.dock launchanim -bool false", "", nil).
+		Set("killall Dock", "", nil)
+	m := New(nil)
+	m.runner = fake
+
+	if state := m.presetState(calm); state != "Off" {
+		t.Fatalf("state = %q, want Off", state)
+	}
+	if err := m.togglePreset(calm); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Set("defaults read com.apple.universalaccess reduceMotion", "1\n", nil).
+		Set("defaults read NSGlobalDomain NSAutomaticWindowAnimationsEnabled", "0\n", nil).
+		Set("defaults read com.apple.dock launchanim", "0\n", nil)
+	if state := m.presetState(calm); state != "On" {
+		t.Fatalf("state after applying = %q, want On", state)
+	}
+
+	// Running it again puts back what was there: a false and two unset keys
+	fake.Set("defaults write com.apple.universalaccess reduceMotion -bool false", "", nil).
+		Set("defaults delete NSGlobalDomain NSAutomaticWindowAnimationsEnabled", "", nil).
+		Set("defaults delete com.apple.dock launchanim", "", nil)
+	if err := m.togglePreset(calm); err != nil {
+		t.Fatal(err)
+	}
+	calls := fake.Calls()
+	got := strings.Join(calls[len(calls)-4:], "\n")
+	want := "defaults write com.apple.universalaccess reduceMotion -bool false\ndefaults delete NSGlobalDomain NSAutomaticWindowAnimationsEnabled\ndefaults delete com.apple.dock launchanim\nkillall Dock"
+	if got != want {
+		t.Errorf("revert ran:\n%s\nwant:\n%s", got, want)
+	}
+	if list := changes.List(); len(list) != 2 || list[0].After != "Off" || list[1].After != "On" {
+		t.Errorf("journal = %+v", list)
+	}
+}
+
+func TestActionTimeouts(t *testing.T) {
+	if got := New(nil).timeoutFor("Flush DNS"); got != defaultCommandTimeout {
+		t.Errorf("without config = %v, want %v", got, defaultCommandTimeout)
+	}
+
+	cfg := &config.Config{System: config.SystemConfig{
+		CommandTimeout: 45,
+		Timeouts:       map[string]int{"fixspotlight": 300},
+	}}
+	m := New(cfg)
+	want := map[string]time.Duration{
+		"Flush DNS":               45 * time.Second,
+		"Rebuild Launch Services": longCommandTimeout, // needs longer than command_timeout
+		"Fix Spotlight":           5 * time.Minute,
+	}
+	for name, timeout := range want {
+		if got := m.timeoutFor(name); got != timeout {
+			t.Errorf("%s timeout = %v, want %v", name, got, timeout)
+		}
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	if err := runWithin(10*time.Millisecond, func() error { <-release; return nil }); !errors.Is(err, errTimedOut) {
+		t.Errorf("runWithin = %v, want errTimedOut", err)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.running, m.runningAction = true, "Fix Spotlight"
+	m.deadline = time.Now().Add(100 * time.Second)
+	if view := m.View(); !strings.Contains(view, "Executing: Fix Spotlight · 1m40s left") {
+		t.Errorf("time remaining not shown:\n%s", view)
+	}
+}
+
+func TestPlanBeforeDestructiveActions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	downloads := filepath.Join(home, "Downloads")
+	if err := os.MkdirAll(downloads, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old, fresh := filepath.Join(downloads, "old.dmg"), filepath.Join(downloads, "fresh.zip")
+	for _, path := range []string{old, fresh} {
+		if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	longAgo := time.Now().Add(-40 * 24 * time.Hour)
+	if err := os.Chtimes(old, longAgo, longAgo); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(nil)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	var clean Action
+	for _, action := range m.actions {
+		if action.Name == "Clean Downloads" {
+			clean = action
+		}
+	}
+
+	// Choosing the action only works out the plan; nothing is deleted
+	_, cmd := m.Update(m.startAction(clean)())
+	if cmd == nil {
+		t.Fatalf("no confirmation dialog: %s", m.status)
+	}
+	req := cmd().(dialog.Request)
+	if !strings.Contains(req.Detail, "rm ~/Downloads/old.dmg") || strings.Contains(req.Detail, "fresh.zip") {
+		t.Errorf("dialog detail:\n%s", req.Detail)
+	}
+	if _, err := os.Stat(old); err != nil {
+		t.Fatal("deleted before confirming")
+	}
+
+	// Confirming deletes just what was listed
+	_, cmd = m.Update(req.OnConfirm)
+	for _, msg := range cmd().(tea.BatchMsg) {
+		if done, ok := msg().(actionCompleteMsg); ok && !done.success {
+			t.Errorf("clean failed: %s", done.message)
+		}
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("old download not deleted")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Error("recent download deleted")
+	}
+}
+
+func TestKillHeavyProcessesPicker(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // the run goes to the history
+	ps := "  101  95.0 2097152 Xcode\n  202  12.0  4096 cfprefsd\n  303  88.5 524288 node\n"
+	fake := runner.NewFake().
+		Set("ps -Aceo pid=,pcpu=,rss=,comm=", ps, nil).
+		Set("kill -TERM 101", "", nil).
+		Set("kill -KILL 202", "", nil)
+	m := New(nil)
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	// Choosing the action opens the picker with nothing marked
+	var kill Action
+	for _, action := range m.actions {
+		if action.Name == "Kill Heavy Processes" {
+			kill = action
+		}
+	}
+	m.Update(m.startAction(kill)())
+	if m.procs == nil || len(m.procs.procs) != 3 || m.procs.procs[1].Name != "node" {
+		t.Fatalf("picker = %+v", m.procs)
+	}
+	if view := m.View(); !strings.Contains(view, "[ ]     101   95.0%    2.0 GB  Xcode") {
+		t.Errorf("view:\n%s", view)
+	}
+
+	// A marks those above 80% CPU; Space unmarks node
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m.Update(events.NavDown)
+	m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if got := strings.Join(m.CommandLines(), "\n"); got != "kill -TERM 101" {
+		t.Errorf("commands = %q", got)
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	req := cmd().(dialog.Request)
+	if req.Destructive || !strings.Contains(req.Detail, "kill -TERM 101  # Xcode, 95% CPU, 2.0 GB") || strings.Contains(req.Detail, "node") {
+		t.Errorf("dialog = %+v", req)
+	}
+	_, cmd = m.Update(req.OnConfirm)
+	for _, c := range cmd().(tea.BatchMsg) {
+		if done, ok := c().(actionCompleteMsg); ok {
+			m.Update(done)
+		}
+	}
+	if calls := fake.Calls(); calls[len(calls)-1] != "kill -TERM 101" {
+		t.Errorf("ran %q", calls[len(calls)-1])
+	}
+
+	// Shift+X force quits the selected process, unless its PID went to
+	// another program since
+	m.Update(m.startAction(kill)())
+	m.Update(events.NavBottom)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	req = cmd().(dialog.Request)
+	if !req.Destructive || !strings.HasPrefix(req.Detail, "kill -KILL 202  # cfprefsd") {
+		t.Errorf("dialog = %+v", req)
+	}
+	fake.Set("ps -Aceo pid=,pcpu=,rss=,comm=", "  202  1.0 4096 make\n", nil)
+	if err := m.signalProcesses(req.OnConfirm.(signalProcsMsg).procs, "KILL"); err == nil {
+		t.Error("signalled PID 202, which now belongs to another program")
+	}
+}
+
+func TestActionOutputPane(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // the run goes to the history
+	m := New(nil)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	action := Action{Name: "Noisy", Command: func() error {
+		return runShellWithTimeout(time.Second, "echo indexing; echo 'no such volume' >&2; exit 1")
+	}}
+
+	// The action runs first here; in the app its output arrives as it's written
+	cmd := m.executeAction(action)
+	for _, c := range cmd().(tea.BatchMsg)[1:] {
+		for msg := c(); msg != nil; {
+			_, next := m.Update(msg)
+			if next == nil {
+				break
+			}
+			msg = next()
+		}
+	}
+
+	want := []string{"$ sh -c echo indexing; echo 'no such volume' >&2; exit 1", "indexing", "no such volume"}
+	if strings.Join(m.outputLog, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output = %q, want %q", m.outputLog, want)
+	}
+	if view := m.View(); !strings.Contains(view, "Output") || !strings.Contains(view, "no such volume") {
+		t.Errorf("output pane not shown:\n%s", view)
+	}
+	if got := m.Copyable(); !strings.HasPrefix(got, "✗ Noisy failed: exit status 1\n\n$ sh -c") {
+		t.Errorf("Copyable() = %q, want the result and output", got)
+	}
+
+	// Without an action running, the helpers write nowhere
+	if err := runShellWithTimeout(time.Second, "echo later"); err != nil || len(m.outputLog) != 3 {
+		t.Errorf("output outside an action: %v, %q", err, m.outputLog)
+	}
+
+	// The run is in the history with the end of its output
+	runs, err := loadHistory(m.historyPath())
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("history = %+v, %v", runs, err)
+	}
+	if run := runs[0]; run.Action != "Noisy" || run.Success || strings.Join(run.Output, "|") != strings.Join(want, "|") {
+		t.Errorf("recorded run = %+v", run)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	m := New(&config.Config{Storage: config.StorageConfig{DataDir: t.TempDir()}})
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	start := time.Date(2024, 7, 20, 9, 30, 0, 0, time.Local)
+	m.recordRun(Run{Time: start, Action: "Flush DNS", Duration: 1200 * time.Millisecond, Success: true, Result: "✓ Flush DNS completed successfully"})
+	m.recordRun(Run{Time: start.Add(time.Hour), Action: "Fix Spotlight", Duration: 4 * time.Second, Result: "✗ Fix Spotlight failed: spotlight reset requires admin privileges",
+		Output: []string{"$ mdutil -i off /", "Error: unable to change indexing state"}})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m.Update(cmd())
+	if !m.HasOpenModal() {
+		t.Fatal("history not open")
+	}
+	golden.RequireEqual(t, m.View())
+
+	// Newest first; y copies the selected run
+	m.Update(events.NavDown)
+	if got := m.Copyable(); got != "2024-07-20 09:30:00  Flush DNS (1.2s)\n✓ Flush DNS completed successfully" {
+		t.Errorf("Copyable() = %q", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.HasOpenModal() {
+		t.Error("history still open after Esc")
+	}
+}
+
+func TestUndoFromHistory(t *testing.T) {
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+	exit1 := errors.New("exit status 1")
+	fake := runner.NewFake().
+		Set("defaults read NSGlobalDomain NSAutomaticWindowAnimationsEnabled", "does not exist", exit1).
+		Set("defaults read com.apple.dock expose-animation-duration", "0.25\n", nil).
+		Set("defaults read com.apple.dock autohide-time-modifier", "0.5\n", nil).
+		Set("defaults read NSGlobalDomain NSWindowResizeTime", "does not exist", exit1).
+		Set("defaults write NSGlobalDomain NSAutomaticWindowAnimationsEnabled -bool false", "", nil).
+		Set("defaults write com.apple.dock expose-animation-duration -float 0.1", "", nil).
+		Set("defaults write com.apple.dock autohide-time-modifier -float 0", "", nil).
+		Set("defaults write NSGlobalDomain NSWindowResizeTime -float 0.001", "", nil).
+		Set("killall Dock", "", nil)
+	m := New(&config.Config{Storage: config.StorageConfig{DataDir: t.TempDir()}})
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	run := func(cmd tea.Cmd) {
+		for _, c := range cmd().(tea.BatchMsg)[1:] {
+			for msg := c(); msg != nil; {
+				_, next := m.Update(msg)
+				if next == nil {
+					break
+				}
+				msg = next()
+			}
+		}
+	}
+	for _, action := range m.actions {
+		if action.Name == "Disable Animations" {
+			run(m.executeAction(action))
+		}
+	}
+
+	// The run keeps what it replaced, and U in the history puts it back
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m.Update(cmd())
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	req, ok := cmd().(dialog.Request)
+	if !ok || !strings.Contains(req.Detail, "com.apple.dock autohide-time-modifier → 0.5") || !strings.Contains(req.Detail, "NSGlobalDomain NSWindowResizeTime → (not set)") {
+		t.Fatalf("undo confirmation = %+v", req)
+	}
+
+	fake.Set("defaults delete NSGlobalDomain NSAutomaticWindowAnimationsEnabled", "", nil).
+		Set("defaults write com.apple.dock expose-animation-duration -float 0.25", "", nil).
+		Set("defaults write com.apple.dock autohide-time-modifier -float 0.5", "", nil).
+		Set("defaults delete NSGlobalDomain NSWindowResizeTime", "", nil)
+	before := len(fake.Calls())
+	_, cmd = m.Update(req.OnConfirm)
+	run(cmd)
+	want := []string{
+		"defaults delete NSGlobalDomain NSWindowResizeTime",
+		"defaults write com.apple.dock autohide-time-modifier -float 0.5",
+		"defaults write com.apple.dock expose-animation-duration -float 0.25",
+		"defaults delete NSGlobalDomain NSAutomaticWindowAnimationsEnabled",
+		"killall Dock",
+	}
+	if got := fake.Calls()[before:]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("undo ran:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if m.status != "✓ Undo Disable Animations completed successfully" {
+		t.Errorf("status = %q", m.status)
+	}
+
+	// Restore Animations does the same from the last Disable Animations
+	before = len(fake.Calls())
+	if err := m.restoreAnimations(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.Calls()[before:]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Restore Animations ran:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestRestartServices(t *testing.T) {
+	fake := runner.NewFake().
+		Set("killall Finder", "", nil).
+		Set("killall ControlCenter", "No matching processes belonging to you were found\n", errors.New("exit status 1"))
+	m := New(nil)
+	m.runner = fake
+
+	find := func(name string) Action {
+		for _, action := range m.actions {
+			if action.Name == name {
+				return action
+			}
+		}
+		t.Fatalf("no %s action", name)
+		return Action{}
+	}
+	if err := find("Restart Finder").Command(); err != nil {
+		t.Fatal(err)
+	}
+	if err := find("Restart Control Center").Command(); err == nil || !strings.Contains(err.Error(), "No matching processes") {
+		t.Errorf("err = %v, want killall's reason", err)
+	}
+	if calls := fake.Calls(); strings.Join(calls, "\n") != "killall Finder\nkillall ControlCenter" {
+		t.Errorf("ran %q", calls)
+	}
+
+	// WindowServer ends the session, so it warns before running anything
+	ws := find("Restart WindowServer")
+	if ws.Confirm == "" || !ws.RequiresSudo {
+		t.Fatalf("WindowServer = %+v", ws)
+	}
+	if msg, ok := m.startAction(ws)().(dialog.Request); !ok || !msg.Destructive || !strings.Contains(msg.Detail, "logs you out") {
+		t.Errorf("startAction = %#v", msg)
+	}
+	if m.running {
+		t.Error("WindowServer restarted without asking")
+	}
+}
+
+func TestToggleDoNotDisturb(t *testing.T) {
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	fake := runner.NewFake().
+		Set("shortcuts list", "Morning Routine\nToggle Do Not Disturb\n", nil).
+		Set("shortcuts run Toggle Do Not Disturb", "", nil)
+	m := New(nil)
+	m.runner = fake
+
+	// No assertions file: nothing was turned on by hand
+	if state := m.doNotDisturbState(); state != "Off" {
+		t.Errorf("state = %q, want Off", state)
+	}
+	if err := m.toggleDoNotDisturb(); err != nil {
+		t.Fatal(err)
+	}
+	if list := changes.List(); len(list) != 1 || list[0].Before != "Off" || list[0].After != "On" {
+		t.Errorf("journal = %+v", list)
+	}
+
+	path := filepath.Join(home, focusAssertions)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	on := `{"data":[{"storeAssertionRecords":[{"assertionDetails":{"assertionDetailsModeIdentifier":"com.apple.donotdisturb.mode.default"}}]}]}`
+	if err := os.WriteFile(path, []byte(on), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if state := m.doNotDisturbState(); state != "On" {
+		t.Errorf("state = %q, want On", state)
+	}
+
+	// A missing shortcut explains what to create
+	m.config = &config.Config{}
+	m.config.Modules.QuickActions.FocusShortcut = "DND"
+	if err := m.toggleDoNotDisturb(); err == nil || !strings.Contains(err.Error(), `no shortcut named "DND"`) {
+		t.Errorf("err = %v, want a hint to create the shortcut", err)
+	}
+}
+
+func TestSwitchDNS(t *testing.T) {
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+	fake := runner.NewFake().
+		Set("route -n get default", "   route to: default\ndestination: default\n  interface: en7\n", nil).
+		Set("networksetup -listnetworkserviceorder", "An asterisk (*) denotes that a network service is disabled.\n"+
+			"(1) Wi-Fi\n(Hardware Port: Wi-Fi, Device: en0)\n\n"+
+			"(2) USB 10/100/1000 LAN\n(Hardware Port: USB 10/100/1000 LAN, Device: en7)\n\n"+
+			"(*) Thunderbolt Bridge\n(Hardware Port: Thunderbolt Bridge, Device: bridge0)\n", nil).
+		Set("networksetup -getdnsservers USB 10/100/1000 LAN", "10.0.0.53\n", nil).
+		Set("networksetup -setdnsservers USB 10/100/1000 LAN 45.90.28.0 45.90.30.0", "", nil).
+		Set("networksetup -setdnsservers USB 10/100/1000 LAN 10.0.0.53", "", nil)
+	cfg := &config.Config{}
+	cfg.Modules.QuickActions.DNSPresets = map[string][]string{"NextDNS": {"45.90.28.0", "45.90.30.0"}}
+	m := New(cfg)
+	m.runner = fake
+
+	var names []string
+	for _, p := range m.resolvers() {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ", "); got != "Cloudflare, Google, Quad9, NextDNS, DHCP" {
+		t.Errorf("resolvers = %s", got)
+	}
+
+	// Servers set by hand show on DHCP's row
+	_, cmd := m.Update(events.Focus{})
+	m.Update(cmd())
+	if m.states["DNS: DHCP"] != "now 10.0.0.53" || m.states["DNS: Cloudflare"] != "" {
+		t.Errorf("states = %v", m.states)
+	}
+
+	if err := m.switchDNS(m.resolvers()[3]); err != nil {
+		t.Fatal(err)
+	}
+	list := changes.List()
+	if len(list) != 1 || list[0].What != "DNS servers of USB 10/100/1000 LAN" || list[0].Before != "10.0.0.53" || list[0].After != "45.90.28.0, 45.90.30.0" {
+		t.Fatalf("journal = %+v", list)
+	}
+	fake.Set("networksetup -getdnsservers USB 10/100/1000 LAN", "45.90.28.0\n45.90.30.0\n", nil)
+	if state := m.dnsState(m.resolvers()[3]); state != "in use" {
+		t.Errorf("NextDNS state = %q, want in use", state)
+	}
+	if err := list[0].Revert(); err != nil {
+		t.Fatal(err)
+	}
+	if calls := fake.Calls(); calls[len(calls)-1] != "networksetup -setdnsservers USB 10/100/1000 LAN 10.0.0.53" {
+		t.Errorf("revert ran %q", calls[len(calls)-1])
+	}
+}
+
+func TestDeveloperActions(t *testing.T) {
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+	fake := runner.NewFake().
+		Set("defaults read com.apple.finder AppleShowAllFiles", "0\n", nil).
+		Set("defaults write com.apple.finder AppleShowAllFiles -bool true", "", nil).
+		Set("killall Finder", "", nil).
+		Set("xcrun simctl shutdown all", "", nil).
+		Set("xcrun simctl erase all", "", nil)
+	m := New(nil)
+	m.runner = fake
+
+	if err := m.toggleHiddenFiles(); err != nil {
+		t.Fatal(err)
+	}
+	calls := fake.Calls()
+	if got := strings.Join(calls[len(calls)-2:], "\n"); got != "defaults write com.apple.finder AppleShowAllFiles -bool true\nkillall Finder" {
+		t.Errorf("ran:\n%s", got)
+	}
+
+	if err := m.resetSimulators(); err != nil {
+		t.Fatal(err)
+	}
+	fake.Missing("xcrun")
+	if err := m.resetSimulators(); err == nil || !strings.Contains(err.Error(), "install Xcode") {
+		t.Errorf("err = %v, want an install hint", err)
+	}
+
+	// Switch Xcode moves on to the next developer folder, wrapping around
+	root := t.TempDir()
+	for _, dir := range []string{"Xcode.app/Contents/Developer", "Xcode-beta.app/Contents/Developer", "CommandLineTools"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldApps, oldTools := xcodeApps, commandLineTools
+	t.Cleanup(func() { xcodeApps, commandLineTools = oldApps, oldTools })
+	xcodeApps, commandLineTools = filepath.Join(root, "Xcode*.app"), filepath.Join(root, "CommandLineTools")
+
+	for current, next := range map[string]string{
+		filepath.Join(root, "Xcode-beta.app/Contents/Developer"): filepath.Join(root, "Xcode.app/Contents/Developer"),
+		filepath.Join(root, "CommandLineTools"):                  filepath.Join(root, "Xcode-beta.app/Contents/Developer"),
+	} {
+		fake.Set("xcode-select -p", current+"\n", nil)
+		plan, err := m.planSwitchXcode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Commands) != 1 || plan.Commands[0] != "sudo xcode-select -s "+next {
+			t.Errorf("from %s: plan = %+v", current, plan)
+		}
+	}
+	fake.Set("xcode-select -p", filepath.Join(root, "CommandLineTools")+"\n", nil)
+	if state := m.xcodeState(); state != "CommandLineTools" {
+		t.Errorf("state = %q", state)
+	}
+	if name := developerDirName("/Applications/Xcode-beta.app/Contents/Developer"); name != "Xcode-beta.app" {
+		t.Errorf("name = %q", name)
+	}
+}
+
+func TestCommandLines(t *testing.T) {
+	fake := runner.NewFake().
+		Set("defaults read -g AppleInterfaceStyle", "Dark\n", nil).
+		Set("route -n get default", "  interface: en7\n", nil).
+		Set("networksetup -listnetworkserviceorder", "(2) USB 10/100/1000 LAN\n(Hardware Port: USB 10/100/1000 LAN, Device: en7)\n", nil)
+	m := New(nil)
+	m.runner = fake
+
+	// Every action that runs commands can list them
+	for _, action := range m.actions {
+		if action.Command != nil && action.CommandLines == nil && action.Plan == nil && action.Open == nil {
+			t.Errorf("%s doesn't list its commands", action.Name)
+		}
+	}
+
+	lines := func(name string) string {
+		visible := m.visible()
+		for i, index := range visible {
+			if m.actions[index].Name == name {
+				m.actionIndex = i
+			}
+		}
+		return strings.Join(m.CommandLines(), "\n")
+	}
+	for name, want := range map[string]string{
+		"Flush DNS":        "sudo dscacheutil -flushcache\nsudo killall -HUP mDNSResponder",
+		"Toggle Dark Mode": `osascript -e 'tell application "System Events" to tell appearance preferences to set dark mode to false'`,
+		"DNS: Cloudflare":  "networksetup -setdnsservers 'USB 10/100/1000 LAN' 1.1.1.1 1.0.0.1",
+		"Restart Dock":     "killall Dock",
+	} {
+		if got := lines(name); got != want {
+			t.Errorf("%s:\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+	if got := runner.Quote("echo", "it's", ""); got != `echo 'it'\''s' ''` {
+		t.Errorf("Quote = %s", got)
+	}
+}
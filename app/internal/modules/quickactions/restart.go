@@ -0,0 +1,69 @@
+package quickactions
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// uiService is a macOS process that launchd relaunches right after it
+// quits, so quitting it restarts it
+type uiService struct {
+	Process     string
+	Name        string
+	Description string
+	// Confirm, when set, is the warning shown before restarting it
+	Confirm string
+	// Session means quitting it ends the login session; it needs root
+	Session bool
+}
+
+var uiServices = []uiService{
+	{Process: "Dock", Name: "Restart Dock", Description: "Relaunch the Dock, Mission Control and Launchpad"},
+	{Process: "Finder", Name: "Restart Finder", Description: "Relaunch Finder and the desktop icons"},
+	{Process: "SystemUIServer", Name: "Restart SystemUIServer", Description: "Relaunch the menu bar extras"},
+	{Process: "ControlCenter", Name: "Restart Control Center", Description: "Relaunch Control Center and the menu bar clock"},
+	{
+		Process:     "WindowServer",
+		Name:        "Restart WindowServer",
+		Description: "Relaunch the window server; logs you out",
+		Confirm: "This logs you out at once: every app quits without asking to save, and unsaved work is lost.\n" +
+			"Only use it when the screen no longer responds to anything else.",
+		Session: true,
+	},
+}
+
+// restartActions returns an action per UI service that restarts it
+func (m *Model) restartActions() []Action {
+	actions := make([]Action, 0, len(uiServices))
+	for _, s := range uiServices {
+		s := s
+		actions = append(actions, Action{
+			Name:         s.Name,
+			Description:  s.Description,
+			Category:     "Restart",
+			Command:      func() error { return m.restartService(s) },
+			Confirm:      s.Confirm,
+			RequiresSudo: s.Session,
+			CommandLines: func() []string { return restartCommands(s) },
+		})
+	}
+	return actions
+}
+
+// restartService quits s for launchd to relaunch it. WindowServer runs as
+// its own user, so it's quit through sudo.
+func (m *Model) restartService(s uiService) error {
+	if s.Session {
+		return executeSudoCommand(m.timeoutFor(s.Name), "killall", "-HUP", s.Process)
+	}
+	return m.restartProcess(s.Process)
+}
+
+// restartProcess quits an app of the current user for launchd to relaunch
+func (m *Model) restartProcess(process string) error {
+	liveOutput.command("killall", process)
+	if output, err := m.runner.CombinedOutput(exec.Command("killall", process)); err != nil {
+		return fmt.Errorf("restarting %s: %s", process, firstLine(output, err))
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package security
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Signature is what codesign, spctl and stapler say about an app or binary
+type Signature struct {
+	Path        string
+	Signed      bool
+	Adhoc       bool // signed without an identity, as local builds are
+	Identifier  string
+	Team        string
+	Authorities []string // signing certificate chain, leaf first
+	Flags       []string // code directory flags, e.g. runtime
+	Timestamp   string
+	VerifyError string // why the signature doesn't verify; empty when it does
+	Accepted    bool   // Gatekeeper would let it open
+	Source      string // spctl's verdict, e.g. Notarized Developer ID
+	Stapled     bool   // carries its notarization ticket for offline checks
+}
+
+var codeDirectoryFlags = regexp.MustCompile(`flags=0x[0-9a-f]+\(([^)]*)\)`)
+
+// Notarized reports whether Apple notarized it
+func (s Signature) Notarized() bool {
+	return strings.HasPrefix(s.Source, "Notarized")
+}
+
+// HardenedRuntime reports whether it runs with the hardened runtime,
+// which notarization requires
+func (s Signature) HardenedRuntime() bool {
+	for _, flag := range s.Flags {
+		if flag == "runtime" {
+			return true
+		}
+	}
+	return false
+}
+
+// Reason explains in plain words why Gatekeeper refuses it, or is empty
+// when it doesn't
+func (s Signature) Reason() string {
+	switch {
+	case s.Accepted:
+		return ""
+	case !s.Signed:
+		return "It isn't signed at all, so Gatekeeper can't tell who made it or whether it was changed."
+	case s.VerifyError != "":
+		return "The signature doesn't match the contents, usually because a file was changed after signing: " + s.VerifyError
+	case s.Adhoc:
+		return "It's ad-hoc signed, with no developer identity; fine for your own builds, but Gatekeeper won't vouch for it."
+	case s.Source == "Unnotarized Developer ID":
+		return "It's signed with a Developer ID but wasn't notarized by Apple."
+	case !s.HardenedRuntime():
+		return "It's signed without the hardened runtime, which notarization requires."
+	case s.Source != "":
+		return "Gatekeeper rejects it: " + s.Source
+	default:
+		return "Gatekeeper rejects it."
+	}
+}
+
+// readSignature inspects path with codesign, spctl and stapler. All three
+// print to stderr, and exit non-zero for the answers that matter most.
+func readSignature(r runner.Runner, path string) Signature {
+	sig := Signature{Path: path}
+
+	output, err := r.CombinedOutput(exec.Command("codesign", "-dv", "--verbose=4", path))
+	text := string(output)
+	sig.Signed = err == nil && !strings.Contains(text, "not signed at all")
+	for _, line := range strings.Split(text, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		switch key {
+		case "Identifier":
+			sig.Identifier = value
+		case "TeamIdentifier":
+			if value != "not set" {
+				sig.Team = value
+			}
+		case "Authority":
+			sig.Authorities = append(sig.Authorities, value)
+		case "Timestamp":
+			sig.Timestamp = value
+		case "Signature":
+			sig.Adhoc = value == "adhoc"
+		case "CodeDirectory v":
+			if match := codeDirectoryFlags.FindStringSubmatch(line); match != nil && match[1] != "none" {
+				sig.Flags = strings.Split(match[1], ",")
+			}
+		}
+	}
+
+	if sig.Signed {
+		output, err := r.CombinedOutput(exec.Command("codesign", "--verify", "--deep", "--strict", path))
+		if err != nil {
+			sig.VerifyError = verifyError(string(output), path, err)
+		}
+	}
+
+	// "PATH: accepted" or "PATH: rejected", then "source=..."
+	output, err = r.CombinedOutput(exec.Command("spctl", "--assess", "--type", "execute", "-vv", path))
+	sig.Accepted = err == nil && strings.Contains(string(output), ": accepted")
+	for _, line := range strings.Split(string(output), "\n") {
+		if source, ok := strings.CutPrefix(strings.TrimSpace(line), "source="); ok {
+			sig.Source = source
+		}
+	}
+
+	output, err = r.CombinedOutput(exec.Command("stapler", "validate", path))
+	sig.Stapled = err == nil && strings.Contains(string(output), "worked")
+	return sig
+}
+
+// verifyError is codesign --verify's complaint without the path it
+// starts with
+func verifyError(output, path string, err error) string {
+	line := firstLine([]byte(output), err)
+	return strings.TrimSpace(strings.TrimPrefix(line, path+":"))
+}
+
+type signatureMsg struct{ sig Signature }
+
+func (m *Model) checkSignature(path string) tea.Cmd {
+	m.signing = true
+	r := m.runner
+	return func() tea.Msg {
+		return signatureMsg{sig: readSignature(r, path)}
+	}
+}
+
+// handlePathInput edits the path typed after P, checking it on Enter
+func (m *Model) handlePathInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.typingPath = false
+	case tea.KeyEnter:
+		m.typingPath = false
+		if path := strings.TrimSpace(m.pathInput); path != "" {
+			return m.checkSignature(expandHome(path))
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(m.pathInput); len(runes) > 0 {
+			m.pathInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.pathInput = ""
+	case tea.KeySpace:
+		m.pathInput += " "
+	case tea.KeyRunes:
+		m.pathInput += string(msg.Runes)
+	}
+	return nil
+}
+
+// renderSignature shows a checked signature as labelled rows, with the
+// reason Gatekeeper refuses it
+func (m *Model) renderSignature(b *strings.Builder) {
+	header := lipgloss.NewStyle().Bold(true).Foreground(components.ColorWarning)
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	good := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	bad := lipgloss.NewStyle().Foreground(components.ColorError)
+	yesNo := func(ok bool, yes, no string) string {
+		if ok {
+			return good.Render("✓ " + yes)
+		}
+		return bad.Render("✗ " + no)
+	}
+
+	if m.typingPath {
+		b.WriteString("\n" + header.Render("Check signature of: ") + m.pathInput + "█\n")
+		b.WriteString(muted.Render("Enter Check • Esc Cancel") + "\n")
+		return
+	}
+	if m.signing {
+		b.WriteString("\n" + muted.Render("Checking the signature...") + "\n")
+		return
+	}
+	s := m.signature
+	if s == nil {
+		return
+	}
+
+	b.WriteString("\n" + header.Render("Code signature of "+filepath.Base(s.Path)) + "\n")
+	row := func(label, value string) {
+		b.WriteString(fmt.Sprintf("  %-18s %s\n", label, value))
+	}
+	switch {
+	case !s.Signed:
+		row("Signed", bad.Render("✗ Not signed"))
+	case s.Adhoc:
+		row("Signed", lipgloss.NewStyle().Foreground(components.ColorWarning).Render("Ad-hoc (no identity)"))
+	default:
+		identity := "✓ Signed"
+		if len(s.Authorities) > 0 {
+			identity = "✓ " + s.Authorities[0]
+		}
+		row("Signed", good.Render(identity))
+	}
+	if s.Identifier != "" {
+		row("Identifier", s.Identifier)
+	}
+	if s.Team != "" {
+		row("Team ID", s.Team)
+	}
+	if s.Signed {
+		row("Signature intact", yesNo(s.VerifyError == "", "Verifies", s.VerifyError))
+		flags := "none"
+		if len(s.Flags) > 0 {
+			flags = strings.Join(s.Flags, ", ")
+		}
+		row("Hardened runtime", yesNo(s.HardenedRuntime(), "On", "Off")+muted.Render("  flags: "+flags))
+	}
+	if s.Timestamp != "" {
+		row("Signed at", s.Timestamp)
+	}
+	ticket := "  no stapled ticket"
+	if s.Stapled {
+		ticket = "  ticket stapled"
+	}
+	row("Notarized", yesNo(s.Notarized(), "Yes", "No")+muted.Render(ticket))
+	row("Gatekeeper", yesNo(s.Accepted, "Accepted", "Rejected")+muted.Render("  "+s.Source))
+	if reason := s.Reason(); reason != "" {
+		b.WriteString("  " + lipgloss.NewStyle().Width(max(m.width-4, 20)).Render(reason) + "\n")
+	}
+	b.WriteString(muted.Render("Esc Close") + "\n")
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
@@ -0,0 +1,283 @@
+package security
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/workspace"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// templateSuffixes mark the .env files that document the variables a
+// project needs rather than hold their values; these belong in git
+var templateSuffixes = []string{".example", ".sample", ".template", ".dist"}
+
+// EnvProject is a git checkout with .env files at its top
+type EnvProject struct {
+	Dir   string
+	Files []EnvFile
+	// Missing are the variables the templates name that no other .env
+	// file or the environment sets
+	Missing []string
+}
+
+// EnvFile is one of a project's .env files
+type EnvFile struct {
+	Name     string
+	Template bool
+	Tracked  bool // committed to git
+}
+
+// Leaked reports whether the file holds values and git tracks it
+func (f EnvFile) Leaked() bool { return f.Tracked && !f.Template }
+
+// leaks reports whether git tracks any of the project's values
+func (p EnvProject) leaks() bool {
+	for _, f := range p.Files {
+		if f.Leaked() {
+			return true
+		}
+	}
+	return false
+}
+
+// envScan is the env files view
+type envScan struct {
+	projects []EnvProject
+	cursor   int
+	scanning bool
+}
+
+type envScanMsg struct{ projects []EnvProject }
+
+// openEnvScan opens the env files view and scans the workspace roots
+func (m *Model) openEnvScan() tea.Cmd {
+	m.env = &envScan{}
+	return m.rescanEnv()
+}
+
+func (m *Model) rescanEnv() tea.Cmd {
+	m.env.scanning = true
+	r, roots := m.runner, m.config.WorkspaceRoots()
+	return func() tea.Msg {
+		return envScanMsg{projects: scanEnv(r, roots)}
+	}
+}
+
+// updateEnvScan handles the env files view's messages and keys
+func (m *Model) updateEnvScan(msg tea.Msg) tea.Cmd {
+	e := m.env
+	switch msg := msg.(type) {
+	case envScanMsg:
+		e.scanning, e.projects = false, msg.projects
+		e.cursor = min(e.cursor, max(len(e.projects)-1, 0))
+	case events.Nav:
+		e.cursor = msg.Move(e.cursor, len(e.projects))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "e":
+			m.env = nil
+		case "r":
+			if !e.scanning {
+				return m.rescanEnv()
+			}
+		}
+	}
+	return nil
+}
+
+// scanEnv reads the projects in the roots that have .env files
+func scanEnv(r runner.Runner, roots []string) []EnvProject {
+	var projects []EnvProject
+	for _, dir := range workspace.Projects(roots) {
+		if p, ok := readProject(r, dir); ok {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// readProject lists dir's .env files, asks git which it tracks and
+// compares the variables the templates name with those set
+func readProject(r runner.Runner, dir string) (EnvProject, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return EnvProject{}, false
+	}
+	p := EnvProject{Dir: dir}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && (name == ".env" || strings.HasPrefix(name, ".env.")) {
+			names = append(names, name)
+			p.Files = append(p.Files, EnvFile{Name: name, Template: isTemplate(name)})
+		}
+	}
+	if len(names) == 0 {
+		return EnvProject{}, false
+	}
+
+	// ls-files prints the ones among names that are committed; outside a
+	// work tree it fails and nothing is flagged
+	args := append([]string{"-C", dir, "ls-files", "--"}, names...)
+	if out, err := r.Output(exec.Command("git", args...)); err == nil {
+		tracked := map[string]bool{}
+		for _, line := range strings.Split(string(out), "\n") {
+			tracked[strings.TrimSpace(line)] = true
+		}
+		for i := range p.Files {
+			p.Files[i].Tracked = tracked[p.Files[i].Name]
+		}
+	}
+
+	required, set := map[string]bool{}, map[string]bool{}
+	for _, f := range p.Files {
+		for _, key := range envKeys(filepath.Join(dir, f.Name)) {
+			if f.Template {
+				required[key] = true
+			} else {
+				set[key] = true
+			}
+		}
+	}
+	for key := range required {
+		if _, ok := os.LookupEnv(key); !ok && !set[key] {
+			p.Missing = append(p.Missing, key)
+		}
+	}
+	sort.Strings(p.Missing)
+	return p, true
+}
+
+func isTemplate(name string) bool {
+	for _, suffix := range templateSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// envKeys returns the variable names a .env file assigns, in the
+// KEY=value form dotenv loaders read, with or without export
+func envKeys(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var keys []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, _, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if key = strings.TrimSpace(key); ok && key != "" && !strings.ContainsAny(key, " \t") {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// renderEnvScan lists the projects with their .env files, flagging the
+// ones git tracks and the variables nothing sets
+func (m *Model) renderEnvScan(b *strings.Builder) {
+	e := m.env
+	header := lipgloss.NewStyle().Bold(true).Foreground(components.ColorWarning)
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	bad := lipgloss.NewStyle().Foreground(components.ColorError)
+	good := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	warn := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	selected := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+
+	roots := make([]string, len(m.config.WorkspaceRoots()))
+	for i, root := range m.config.WorkspaceRoots() {
+		roots[i] = tildePath(expandHome(root))
+	}
+	leaked, missing := 0, 0
+	for _, p := range e.projects {
+		for _, f := range p.Files {
+			if f.Leaked() {
+				leaked++
+			}
+		}
+		if len(p.Missing) > 0 {
+			missing++
+		}
+	}
+	b.WriteString("\n" + header.Render(fmt.Sprintf(".env files (%d projects)", len(e.projects))) + "\n")
+	switch {
+	case e.scanning:
+		b.WriteString(muted.Render("Looking for projects in "+strings.Join(roots, ", ")+"...") + "\n")
+		return
+	case len(e.projects) == 0:
+		b.WriteString(muted.Render("No project in "+strings.Join(roots, ", ")+" has .env files") + "\n")
+		b.WriteString(muted.Render("R Rescan • Esc Close") + "\n")
+		return
+	}
+	if leaked == 0 && missing == 0 {
+		b.WriteString(good.Render("✓ None tracked by git, nothing missing") + "\n")
+	} else {
+		b.WriteString(bad.Render(fmt.Sprintf("✗ %d %s tracked by git • %d %s missing variables",
+			leaked, plural(leaked, "file", "files"), missing, plural(missing, "project", "projects"))) + "\n")
+	}
+
+	// Each project takes two lines, three when variables are missing
+	rows := max((m.height-18)/3, 2)
+	start := min(max(e.cursor-rows/2, 0), max(len(e.projects)-rows, 0))
+	for i := start; i < min(start+rows, len(e.projects)); i++ {
+		p := e.projects[i]
+		mark := good.Render("✓")
+		switch {
+		case p.leaks():
+			mark = bad.Render("✗")
+		case len(p.Missing) > 0:
+			mark = warn.Render("!")
+		}
+		if i == e.cursor {
+			b.WriteString(selected.Render("▶ ") + mark + " " + selected.Render(tildePath(p.Dir)) + "\n")
+		} else {
+			b.WriteString("  " + mark + " " + tildePath(p.Dir) + "\n")
+		}
+		files := make([]string, len(p.Files))
+		for j, f := range p.Files {
+			if f.Leaked() {
+				files[j] = bad.Render(f.Name + " (tracked by git)")
+			} else {
+				files[j] = muted.Render(f.Name)
+			}
+		}
+		b.WriteString("    " + strings.Join(files, muted.Render(" · ")) + "\n")
+		if len(p.Missing) > 0 {
+			b.WriteString("    " + warn.Render("Missing: "+strings.Join(p.Missing, ", ")) + "\n")
+		}
+	}
+	b.WriteString(muted.Render("R Rescan • Esc Close") + "\n")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// tildePath shortens a path in the home folder to ~/...
+func tildePath(path string) string {
+	home, _ := os.UserHomeDir()
+	if home != "" && (path == home || strings.HasPrefix(path, home+"/")) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
@@ -0,0 +1,256 @@
+package security
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// quarantineAttr is set by browsers and other download agents; Gatekeeper
+// checks what carries it before it first opens
+const quarantineAttr = "com.apple.quarantine"
+
+// quarantineShown caps the list, since xattr runs once per item
+const quarantineShown = 200
+
+// Quarantined is a downloaded file or app still marked as quarantined
+type Quarantined struct {
+	Path  string
+	Value string // the raw attribute, needed to put it back
+	Agent string // the app that downloaded it, e.g. Safari
+	Time  time.Time
+}
+
+type quarantineMsg struct {
+	items []Quarantined
+	err   error
+}
+
+// unquarantineMsg removes the attribute once the warning is confirmed
+type unquarantineMsg struct{ items []Quarantined }
+
+type unquarantinedMsg struct {
+	removed int
+	err     error
+}
+
+// quarantineDirs are where downloaded tools end up: Downloads, the
+// Applications folders and personal bin directories
+func quarantineDirs() []string {
+	home, _ := os.UserHomeDir()
+	candidates := []string{
+		filepath.Join(home, "Downloads"),
+		"/Applications",
+		filepath.Join(home, "Applications"),
+		filepath.Join(home, "bin"),
+		filepath.Join(home, ".local", "bin"),
+	}
+	var dirs []string
+	for _, dir := range candidates {
+		if _, err := os.Stat(dir); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// scanQuarantine lists what directly inside dirs carries the quarantine
+// attribute. Inside an app bundle only the bundle itself matters.
+func scanQuarantine(r runner.Runner, dirs []string) ([]Quarantined, error) {
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+	args := append(append([]string{}, dirs...), "-mindepth", "1", "-maxdepth", "1", "-xattrname", quarantineAttr)
+	// find exits non-zero after a folder it can't read but lists the rest
+	output, err := r.Output(exec.Command("find", args...))
+	if err != nil && len(output) == 0 {
+		return nil, fmt.Errorf("find: %w", err)
+	}
+	var items []Quarantined
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path == "" || len(items) == quarantineShown {
+			continue
+		}
+		value, err := r.Output(exec.Command("xattr", "-p", quarantineAttr, path))
+		if err != nil {
+			continue
+		}
+		items = append(items, parseQuarantine(path, strings.TrimSpace(string(value))))
+	}
+	return items, nil
+}
+
+// parseQuarantine reads an attribute like "0083;65f1a2b3;Safari;<UUID>":
+// flags, the download time in hex Unix seconds and the downloading app
+func parseQuarantine(path, value string) Quarantined {
+	item := Quarantined{Path: path, Value: value}
+	fields := strings.Split(value, ";")
+	if len(fields) > 1 {
+		if secs, err := strconv.ParseInt(fields[1], 16, 64); err == nil {
+			item.Time = time.Unix(secs, 0)
+		}
+	}
+	if len(fields) > 2 {
+		item.Agent = fields[2]
+	}
+	return item
+}
+
+func (m *Model) refreshQuarantine() tea.Cmd {
+	r := m.runner
+	return func() tea.Msg {
+		items, err := scanQuarantine(r, quarantineDirs())
+		return quarantineMsg{items: items, err: err}
+	}
+}
+
+// selectedQuarantine is what U acts on: the marked items, or the one under
+// the cursor
+func (m *Model) selectedQuarantine() []Quarantined {
+	var items []Quarantined
+	for _, item := range m.quarantined {
+		if m.marked[item.Path] {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 && m.cursor < len(m.quarantined) {
+		items = append(items, m.quarantined[m.cursor])
+	}
+	return items
+}
+
+// confirmUnquarantine warns before Gatekeeper's check is skipped
+func (m *Model) confirmUnquarantine() tea.Cmd {
+	items := m.selectedQuarantine()
+	if len(items) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, filepath.Base(item.Path))
+	}
+	return dialog.Confirm(dialog.Request{
+		Title: fmt.Sprintf("Remove quarantine from %d item(s)?", len(items)),
+		Detail: strings.Join(names, ", ") + "\n\nGatekeeper won't check them before they open. " +
+			"Only do this for software you built or trust.",
+		ConfirmLabel: "Remove",
+		Destructive:  true,
+		OnConfirm:    unquarantineMsg{items: items},
+	})
+}
+
+// unquarantine removes the attribute, recursively so nothing inside an app
+// bundle stays blocked, and records how to put it back
+func (m *Model) unquarantine(items []Quarantined) tea.Cmd {
+	r, title := m.runner, m.Title()
+	return func() tea.Msg {
+		removed := 0
+		for _, item := range items {
+			if output, err := r.CombinedOutput(exec.Command("xattr", "-dr", quarantineAttr, item.Path)); err != nil {
+				return unquarantinedMsg{removed: removed, err: fmt.Errorf("%s: %s", filepath.Base(item.Path), firstLine(output, err))}
+			}
+			removed++
+			item := item
+			changes.Record(changes.Change{
+				Source: title,
+				What:   quarantineAttr + " on " + item.Path,
+				Before: item.Value,
+				After:  changes.NotSet,
+				Revert: func() error {
+					_, err := r.CombinedOutput(exec.Command("xattr", "-w", quarantineAttr, item.Value, item.Path))
+					return err
+				},
+			})
+		}
+		return unquarantinedMsg{removed: removed}
+	}
+}
+
+// updateQuarantine handles the quarantine messages
+func (m *Model) updateQuarantine(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case quarantineMsg:
+		m.quarantined, m.quarantineErr = msg.items, msg.err
+		m.quarantineChecked = true
+		m.marked = map[string]bool{}
+		m.cursor = min(m.cursor, max(len(m.quarantined)-1, 0))
+	case unquarantineMsg:
+		return m.unquarantine(msg.items)
+	case unquarantinedMsg:
+		note := fmt.Sprintf("✓ Removed quarantine from %d item(s)", msg.removed)
+		if msg.err != nil {
+			note = "✗ " + msg.err.Error()
+		}
+		m.output = note
+		return tea.Batch(components.StatusToast(note), m.refreshQuarantine())
+	}
+	return nil
+}
+
+// renderQuarantine lists the quarantined items with where they came from
+func (m *Model) renderQuarantine(b *strings.Builder) {
+	header := lipgloss.NewStyle().Bold(true).Foreground(components.ColorWarning)
+	muted := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	selected := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+
+	b.WriteString("\n" + header.Render(fmt.Sprintf("Quarantined downloads (%d)", len(m.quarantined))) + "\n")
+	switch {
+	case !m.quarantineChecked:
+		b.WriteString(muted.Render("Checking Downloads, Applications and ~/bin...") + "\n")
+		return
+	case m.quarantineErr != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorError).Render("✗ "+m.quarantineErr.Error()) + "\n")
+		return
+	case len(m.quarantined) == 0:
+		b.WriteString(muted.Render("Nothing in Downloads, Applications or ~/bin is quarantined • P Check a signature") + "\n")
+		return
+	}
+
+	home, _ := os.UserHomeDir()
+	rows := max(m.height-16, 3)
+	start := min(max(m.cursor-rows/2, 0), max(len(m.quarantined)-rows, 0))
+	for i := start; i < min(start+rows, len(m.quarantined)); i++ {
+		item := m.quarantined[i]
+		mark := "[ ]"
+		if m.marked[item.Path] {
+			mark = "[x]"
+		}
+		path := item.Path
+		if home != "" && strings.HasPrefix(path, home+"/") {
+			path = "~" + strings.TrimPrefix(path, home)
+		}
+		from := item.Agent
+		if !item.Time.IsZero() {
+			from += ", " + item.Time.Format("Jan 2 2006")
+		}
+		line := fmt.Sprintf("%s %-50s", mark, components.TruncateString(path, 50)) + muted.Render(" "+from)
+		if i == m.cursor {
+			b.WriteString(selected.Render("▶ ") + line + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString(muted.Render("Space Mark • A Mark all • U Remove quarantine (asks first) • C Check signature • P Check a path") + "\n")
+}
+
+// firstLine is the first line of a command's output, or err when it printed
+// nothing
+func firstLine(output []byte, err error) string {
+	text := strings.TrimSpace(string(output))
+	if text == "" {
+		return err.Error()
+	}
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -6,6 +6,12 @@ import (
 	"strings"
 
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/export"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -13,6 +19,7 @@ import (
 // Model represents the security module state
 type Model struct {
 	config     *config.Config
+	runner     runner.Runner
 	width      int
 	height     int
 	firewall   string
@@ -20,13 +27,31 @@ type Model struct {
 	sip        string
 	gatekeeper string
 	output     string
+
+	// Downloads still marked for Gatekeeper, selectable to unblock
+	quarantined       []Quarantined
+	quarantineChecked bool
+	quarantineErr     error
+	cursor            int
+	marked            map[string]bool // by path
+
+	// Code signature check of a quarantined item or a typed path
+	signature  *Signature
+	signing    bool
+	typingPath bool
+	pathInput  string
+
+	// .env files of the projects in the workspace roots
+	env *envScan
 }
 
 // New creates a new security module
-func New(cfg *config.Config) *Model { return &Model{config: cfg} }
+func New(cfg *config.Config) *Model {
+	return &Model{config: cfg, runner: runner.Default, marked: map[string]bool{}}
+}
 
 // Init initializes the module
-func (m *Model) Init() tea.Cmd { return m.refresh() }
+func (m *Model) Init() tea.Cmd { return tea.Batch(m.refresh(), m.refreshQuarantine()) }
 
 // Update handles messages
 func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
@@ -34,10 +59,46 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+	case events.Nav:
+		if m.env != nil {
+			return m, m.updateEnvScan(msg)
+		}
+		m.cursor = msg.Move(m.cursor, len(m.quarantined))
 	case tea.KeyMsg:
+		if m.typingPath {
+			return m, m.handlePathInput(msg)
+		}
+		if m.env != nil {
+			return m, m.updateEnvScan(msg)
+		}
 		switch msg.String() {
+		case "esc":
+			m.signature = nil
+		case "c":
+			if m.cursor < len(m.quarantined) {
+				return m, m.checkSignature(m.quarantined[m.cursor].Path)
+			}
+		case "p":
+			m.typingPath, m.pathInput = true, ""
+		case "e":
+			return m, m.openEnvScan()
 		case "r":
-			return m, m.refresh()
+			return m, tea.Batch(m.refresh(), m.refreshQuarantine())
+		case " ":
+			if m.cursor < len(m.quarantined) {
+				path := m.quarantined[m.cursor].Path
+				m.marked[path] = !m.marked[path]
+			}
+		case "a":
+			all := len(m.marked) < len(m.quarantined)
+			m.marked = map[string]bool{}
+			if all {
+				for _, item := range m.quarantined {
+					m.marked[item.Path] = true
+				}
+			}
+		case "u":
+			return m, m.confirmUnquarantine()
 		}
 	case secMsg:
 		m.firewall = msg.firewall
@@ -45,6 +106,15 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.sip = msg.sip
 		m.gatekeeper = msg.gatekeeper
 		m.output = msg.note
+	case quarantineMsg, unquarantineMsg, unquarantinedMsg:
+		return m, m.updateQuarantine(msg)
+	case envScanMsg:
+		if m.env != nil {
+			return m, m.updateEnvScan(msg)
+		}
+	case signatureMsg:
+		m.signing = false
+		m.signature = &msg.sig
 	}
 	return m, nil
 }
@@ -55,12 +125,12 @@ func (m *Model) View() string {
 		return "Loading..."
 	}
 
-	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D9FF")).Render("🔐 SECURITY")
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888")).Render("[r] Refresh")
+	title := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary).Render("🔐 SECURITY")
+	help := lipgloss.NewStyle().Foreground(components.ColorSubtle).Render("[r] Refresh")
 	var b strings.Builder
 	b.WriteString(title + "\n\n")
 	if m.output != "" {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render(m.output) + "\n\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorMuted).Render(m.output) + "\n\n")
 	}
 	b.WriteString(help + "\n\n")
 
@@ -68,6 +138,14 @@ func (m *Model) View() string {
 	b.WriteString(fmt.Sprintf("FileVault:  %s\n", m.filevault))
 	b.WriteString(fmt.Sprintf("SIP:        %s\n", m.sip))
 	b.WriteString(fmt.Sprintf("Gatekeeper: %s\n", m.gatekeeper))
+	switch {
+	case m.env != nil:
+		m.renderEnvScan(&b)
+	case m.typingPath || m.signing || m.signature != nil:
+		m.renderSignature(&b)
+	default:
+		m.renderQuarantine(&b)
+	}
 
 	// Apply viewport to prevent overflow
 	content := b.String()
@@ -82,8 +160,59 @@ func (m *Model) View() string {
 // Title returns the module title
 func (m *Model) Title() string { return "Security" }
 
+// Help describes the security keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "SECURITY",
+		Description: "Firewall, FileVault, SIP and Gatekeeper status, the downloads Gatekeeper still holds in quarantine, code signature checks, and the .env files in your projects.",
+		Sections: []help.Section{{Title: "Keys", Bindings: []help.Binding{
+			{Key: "R", Desc: "Check again"},
+			{Key: "↑/↓", Desc: "Select a quarantined item"},
+			{Key: "Space", Desc: "Mark or unmark it"},
+			{Key: "A", Desc: "Mark all, or none"},
+			{Key: "U", Desc: "Remove the quarantine attribute from the marked items, or the selected one, after a warning"},
+			{Key: "C", Desc: "Check the selected item's code signature, hardened runtime and notarization"},
+			{Key: "P", Desc: "Type the path of an app or binary to check its signature"},
+			{Key: "Esc", Desc: "Close the signature check"},
+		}}, {Title: ".env files", Bindings: []help.Binding{
+			{Key: "E", Desc: "List the .env files of the git projects in the workspace roots, flagging those git tracks and the variables their templates name that nothing sets"},
+			{Key: "R", Desc: "Scan again"},
+			{Key: "Esc", Desc: "Close the list"},
+		}}},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
-func (m *Model) HasOpenModal() bool { return false }
+func (m *Model) HasOpenModal() bool {
+	return m.typingPath || m.signature != nil || m.env != nil
+}
+
+// Export lists the protection status
+func (m *Model) Export() string {
+	if m.firewall == "" && m.filevault == "" && m.sip == "" && m.gatekeeper == "" {
+		return ""
+	}
+	body := export.Table([]string{"Protection", "Status"}, [][]string{
+		{"Firewall", m.firewall},
+		{"FileVault", m.filevault},
+		{"SIP", m.sip},
+		{"Gatekeeper", m.gatekeeper},
+	})
+	if m.output != "" {
+		body += "\n" + m.output + "\n"
+	}
+	return body
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Refresh security status", Hint: "Firewall, FileVault, SIP and Gatekeeper", Msg: palette.Key("r")},
+		{Title: "Check code signature", Hint: "Signing identity, hardened runtime, notarization and Gatekeeper's verdict for an app or binary", Msg: palette.Key("p")},
+		{Title: "Scan .env files", Hint: "Projects' .env files tracked by git and variables their templates need that nothing sets", Msg: palette.Key("e")},
+		{Title: "Remove quarantine", Hint: "Unblock a downloaded tool or app (xattr -d com.apple.quarantine)", Msg: palette.Key("u")},
+	}
+}
 
 type secMsg struct {
 	firewall, filevault, sip, gatekeeper, note string
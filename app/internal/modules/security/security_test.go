@@ -0,0 +1,193 @@
+package security
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := New(nil)
+			m.Update(secMsg{
+				firewall:   "Firewall is enabled. (State = 1)",
+				filevault:  "FileVault is On.",
+				sip:        "System Integrity Protection status: enabled.",
+				gatekeeper: "assessments enabled",
+				note:       "Security status refreshed",
+			})
+			m.Update(quarantineMsg{items: []Quarantined{
+				{Path: "/Users/caio/Downloads/terraform", Agent: "Safari", Time: time.Date(2024, 3, 12, 10, 0, 0, 0, time.Local)},
+				{Path: "/Applications/Postico 2.app", Agent: "Google Chrome", Time: time.Date(2024, 2, 1, 9, 30, 0, 0, time.Local)},
+			}})
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			golden.RequireEqual(t, m.View())
+		})
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+	const value = "0083;65f0288c;Safari;6E8D1A6B-6C1B-4F71-9C2E-2C0B6C1F7E10"
+	fake := runner.NewFake().
+		Set("find /Users/caio/Downloads /Applications -mindepth 1 -maxdepth 1 -xattrname com.apple.quarantine",
+			"/Users/caio/Downloads/terraform\n/Applications/Postico 2.app\n", nil).
+		Set("xattr -p com.apple.quarantine /Users/caio/Downloads/terraform", value+"\n", nil).
+		Set("xattr -p com.apple.quarantine /Applications/Postico 2.app", "0081;65bb5a20;Google Chrome;\n", nil)
+
+	items, err := scanQuarantine(fake, []string{"/Users/caio/Downloads", "/Applications"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Agent != "Safari" || items[0].Time.Unix() != 0x65f0288c || items[1].Agent != "Google Chrome" {
+		t.Fatalf("items = %+v", items)
+	}
+
+	m := New(nil)
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Update(quarantineMsg{items: items})
+
+	// Without marks, U acts on the selected item only
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if got := m.selectedQuarantine(); len(got) != 1 || got[0].Path != "/Users/caio/Downloads/terraform" {
+		t.Fatalf("selected = %+v", got)
+	}
+	if cmd == nil {
+		t.Fatal("U didn't ask first")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if got := m.selectedQuarantine(); len(got) != 2 {
+		t.Fatalf("A marked %d items, want 2", len(got))
+	}
+
+	fake.Set("xattr -dr com.apple.quarantine /Users/caio/Downloads/terraform", "", nil).
+		Set("xattr -dr com.apple.quarantine /Applications/Postico 2.app", "", nil)
+	_, cmd = m.Update(unquarantineMsg{items: m.selectedQuarantine()})
+	msg := cmd()
+	if done := msg.(unquarantinedMsg); done.removed != 2 || done.err != nil {
+		t.Fatalf("unquarantine = %+v", done)
+	}
+
+	// Undoing puts the original attribute back
+	fake.Set("xattr -w com.apple.quarantine "+value+" /Users/caio/Downloads/terraform", "", nil)
+	list := changes.List()
+	if len(list) != 2 || list[1].Before != value {
+		t.Fatalf("journal = %+v", list)
+	}
+	if err := list[1].Revert(); err != nil {
+		t.Fatal(err)
+	}
+	if calls := fake.Calls(); !strings.HasPrefix(calls[len(calls)-1], "xattr -w com.apple.quarantine") {
+		t.Errorf("revert ran %q", calls[len(calls)-1])
+	}
+}
+
+func TestReadSignature(t *testing.T) {
+	const app = "/Applications/Postico 2.app"
+	const tool = "/Users/caio/bin/mytool"
+	fake := runner.NewFake().
+		Set("codesign --verify --deep --strict "+app, "", nil).
+		Set("spctl --assess --type execute -vv "+app, app+": accepted\nsource=Notarized Developer ID\norigin=Developer ID Application: Egger Apps e.U. (XBXJ7A7C6T)\n", nil).
+		Set("stapler validate "+app, "Processing: "+app+"\nThe validate action worked!\n", nil).
+		Set("codesign -dv --verbose=4 "+tool, tool+": code object is not signed at all\n", errors.New("exit status 1")).
+		Set("spctl --assess --type execute -vv "+tool, tool+": rejected\nsource=no usable signature\n", errors.New("exit status 3")).
+		Set("stapler validate "+tool, tool+" does not have a ticket stapled to it.\n", errors.New("exit status 65"))
+	if err := fake.SetFixture("codesign -dv --verbose=4 "+app, filepath.Join("testdata", "codesign_postico.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	sig := readSignature(fake, app)
+	if !sig.Signed || sig.Adhoc || sig.Team != "XBXJ7A7C6T" || sig.Identifier != "at.eggerapps.Postico2" || len(sig.Authorities) != 3 {
+		t.Fatalf("signature = %+v", sig)
+	}
+	if !sig.HardenedRuntime() || !sig.Notarized() || !sig.Stapled || !sig.Accepted || sig.Reason() != "" {
+		t.Errorf("notarized app = %+v, reason %q", sig, sig.Reason())
+	}
+
+	sig = readSignature(fake, tool)
+	if sig.Signed || sig.Accepted || sig.Notarized() || !strings.Contains(sig.Reason(), "isn't signed") {
+		t.Errorf("unsigned tool = %+v, reason %q", sig, sig.Reason())
+	}
+
+	// Checking from the list shows the parsed result until Esc
+	m := New(nil)
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Update(quarantineMsg{items: []Quarantined{{Path: app}}})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m.Update(cmd())
+	view := m.View()
+	for _, want := range []string{"Developer ID Application: Egger Apps e.U. (XBXJ7A7C6T)", "✓ On", "ticket stapled", "✓ Accepted"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view lacks %q:\n%s", want, view)
+		}
+	}
+	if m.Update(tea.KeyMsg{Type: tea.KeyEsc}); m.HasOpenModal() {
+		t.Error("esc didn't close the signature")
+	}
+}
+
+func TestEnvScan(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DEVCOCKPIT_TEST_SET", "1")
+	write := func(path, content string) {
+		path = filepath.Join(home, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("code/api/.git/HEAD", "ref: refs/heads/main\n")
+	write("code/api/.env", "DATABASE_URL=postgres://localhost/api\n")
+	write("code/api/.env.example", "# Copy to .env\nDATABASE_URL=\nexport STRIPE_KEY=\nDEVCOCKPIT_TEST_SET=\n")
+	write("code/web/.git/HEAD", "ref: refs/heads/main\n")
+	write("code/web/.env.local", "API_URL=http://localhost:3000\n")
+	write("code/web/node_modules/dep/.git/HEAD", "")
+	write("code/web/node_modules/dep/.env", "X=1\n")
+	write("code/notes/.env", "TOKEN=abc\n") // not a git checkout
+	write("src/cli/.git/HEAD", "ref: refs/heads/main\n")
+
+	fake := runner.NewFake().
+		Set("git -C "+filepath.Join(home, "code/api")+" ls-files -- .env .env.example", ".env\n.env.example\n", nil).
+		Set("git -C "+filepath.Join(home, "code/web")+" ls-files -- .env.local", "", nil)
+
+	projects := scanEnv(fake, config.DefaultWorkspaceRoots)
+	if len(projects) != 2 {
+		t.Fatalf("projects = %+v", projects)
+	}
+	api := projects[0]
+	if !api.leaks() || api.Files[1].Leaked() || strings.Join(api.Missing, ",") != "STRIPE_KEY" {
+		t.Errorf("api = %+v", api)
+	}
+	if projects[1].leaks() || len(projects[1].Missing) != 0 {
+		t.Errorf("web = %+v", projects[1])
+	}
+
+	m := New(nil)
+	m.runner = fake
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if !m.HasOpenModal() {
+		t.Fatal("E didn't open the scan")
+	}
+	m.Update(cmd())
+	golden.RequireEqual(t, m.View())
+	if m.Update(tea.KeyMsg{Type: tea.KeyEsc}); m.HasOpenModal() {
+		t.Error("esc didn't close the scan")
+	}
+}
@@ -0,0 +1,110 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/backup"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// restoreMsg restores the backup at path once confirmed
+type restoreMsg struct{ path string }
+
+// backupDir is where B saves backups and O looks for them: the home
+// folder, like devcockpit backup run there
+func backupDir() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+// latestBackup is the newest backup in dir, or empty without one
+func latestBackup(dir string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "devcockpit-backup-*.tar.gz"))
+	if len(matches) == 0 {
+		return ""
+	}
+	// The timestamp in the name sorts by date
+	sort.Strings(matches)
+	return matches[len(matches)-1]
+}
+
+func (m *Model) backUp() tea.Cmd {
+	m.busy = true
+	dir := m.config.Dir()
+	return func() tea.Msg {
+		path := filepath.Join(backupDir(), backup.DefaultName(time.Now()))
+		summary, err := backup.Create(dir, path)
+		if err != nil {
+			return storageActionMsg{note: fmt.Sprintf("✗ Backup failed: %v", err)}
+		}
+		logger.Info("Backed up %s to %s", dir, path)
+		return storageActionMsg{note: fmt.Sprintf("✓ Backed up %d files to %s", summary.Files, path)}
+	}
+}
+
+// startRestore asks for the backup to restore, offering the newest one
+func (m *Model) startRestore() {
+	m.typingRestore = true
+	m.restoreInput = latestBackup(backupDir())
+}
+
+// handleRestoreInput edits the backup path, asking to confirm on Enter
+func (m *Model) handleRestoreInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.typingRestore = false
+	case tea.KeyEnter:
+		m.typingRestore = false
+		path := strings.TrimSpace(m.restoreInput)
+		if path == "" {
+			return nil
+		}
+		if home, _ := os.UserHomeDir(); strings.HasPrefix(path, "~/") {
+			path = filepath.Join(home, path[2:])
+		}
+		dir := m.config.Dir()
+		return dialog.Confirm(dialog.Request{
+			Title: "Restore " + filepath.Base(path) + "?",
+			Detail: fmt.Sprintf("%s is replaced by the backup: config, themes, saved state and stored data. "+
+				"The current one is kept as %s.", dir, backup.PreviousDir(dir)),
+			ConfirmLabel: "Restore",
+			Destructive:  true,
+			OnConfirm:    restoreMsg{path: path},
+		})
+	case tea.KeyBackspace:
+		if runes := []rune(m.restoreInput); len(runes) > 0 {
+			m.restoreInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.restoreInput = ""
+	case tea.KeySpace:
+		m.restoreInput += " "
+	case tea.KeyRunes:
+		m.restoreInput += string(msg.Runes)
+	}
+	return nil
+}
+
+// restore replaces the config directory with the backup at path. Dev
+// Cockpit then quits, as the running session would otherwise write its
+// state and data over what was restored.
+func (m *Model) restore(path string) tea.Cmd {
+	m.busy = true
+	dir := m.config.Dir()
+	return func() tea.Msg {
+		summary, err := backup.Restore(path, dir)
+		if err != nil {
+			return storageActionMsg{note: fmt.Sprintf("✗ Restore failed: %v", err)}
+		}
+		logger.Info("Restored %s from %s", dir, path)
+		return events.Restored{Note: fmt.Sprintf("✓ Restored %d files from %s; launch Dev Cockpit again to use them", summary.Files, path)}
+	}
+}
@@ -0,0 +1,86 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Views of the settings module
+const (
+	storageView = iota
+	changesView
+	queryView
+)
+
+// revertedMsg reports a finished revert
+type revertedMsg struct {
+	what string
+	err  error
+}
+
+// revertChange undoes the selected change
+func (m *Model) revertChange() tea.Cmd {
+	list := changes.List()
+	if m.changeCursor >= len(list) {
+		return nil
+	}
+	c := list[m.changeCursor]
+	switch {
+	case c.Reverted:
+		m.message = "Already reverted"
+		return nil
+	case c.Revert == nil:
+		m.message = "⚠ " + changes.ErrNotRevertible.Error()
+		return nil
+	}
+	m.busy = true
+	return func() tea.Msg {
+		return revertedMsg{what: c.What, err: changes.Revert(c.ID)}
+	}
+}
+
+// renderChanges lists what Dev Cockpit changed this session, newest first
+func (m *Model) renderChanges(b *strings.Builder) {
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	beforeStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	afterStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	list := changes.List()
+	if len(list) == 0 {
+		b.WriteString(mutedStyle.Render("Nothing changed yet this session. Settings written by quick actions and"))
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("the System tabs show up here with their previous value."))
+		b.WriteString("\n\n")
+		return
+	}
+	m.changeCursor = min(m.changeCursor, len(list)-1)
+
+	for i, c := range list {
+		line := fmt.Sprintf("%s  %-13s %s", c.Time.Format("15:04:05"), c.Source, c.What)
+		if i == m.changeCursor {
+			b.WriteString(selectedStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(normalStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+
+		// The diff: the old value struck out in red, the new one in green
+		diff := "    " + beforeStyle.Render("- "+c.Before) + "  " + afterStyle.Render("+ "+c.After)
+		switch {
+		case c.Reverted:
+			diff += mutedStyle.Render("  (reverted)")
+		case c.Revert == nil:
+			diff += mutedStyle.Render("  (can't revert)")
+		}
+		b.WriteString(diff)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
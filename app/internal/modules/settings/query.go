@@ -0,0 +1,207 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/query"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxColumnWidth caps a result column, so one long value doesn't push the
+// others off screen
+const maxColumnWidth = 40
+
+// queryLoadedMsg carries the collected data loaded for querying
+type queryLoadedMsg struct {
+	db  *query.DB
+	err error
+}
+
+// queryResultMsg carries what a query returned
+type queryResultMsg struct {
+	result query.Result
+	err    error
+}
+
+// openQuery shows the query view, loading the data the first time
+func (m *Model) openQuery() tea.Cmd {
+	m.view = queryView
+	m.typingQuery = true
+	if m.db != nil || m.loadingQuery {
+		return nil
+	}
+	return m.loadQuery()
+}
+
+// loadQuery copies the collected data into a fresh database, replacing the
+// one loaded before
+func (m *Model) loadQuery() tea.Cmd {
+	m.closeQuery()
+	m.loadingQuery = true
+	m.queryErr = nil
+	cfg, r := m.config, m.runner
+	return func() tea.Msg {
+		db, err := query.Load(context.Background(), cfg, r)
+		return queryLoadedMsg{db: db, err: err}
+	}
+}
+
+// closeQuery removes the loaded database
+func (m *Model) closeQuery() {
+	if m.db == nil {
+		return
+	}
+	if err := m.db.Close(); err != nil {
+		logger.Error("Failed to remove the query database: %v", err)
+	}
+	m.db = nil
+}
+
+// Shutdown removes the loaded database when Dev Cockpit quits
+func (m *Model) Shutdown() { m.closeQuery() }
+
+// runQuery runs what was typed
+func (m *Model) runQuery() tea.Cmd {
+	sql := m.queryInput.Value()
+	if sql == "" || m.db == nil {
+		return nil
+	}
+	m.queryInput.Remember(sql)
+	m.busy = true
+	db := m.db
+	return func() tea.Msg {
+		result, err := db.Query(context.Background(), sql)
+		return queryResultMsg{result: result, err: err}
+	}
+}
+
+// handleQueryInput edits the query; Enter runs it and Esc stops typing,
+// leaving ↑/↓ to scroll the result
+func (m *Model) handleQueryInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		return m.runQuery()
+	case "esc":
+		m.typingQuery = false
+	default:
+		m.queryInput.HandleKey(msg)
+	}
+	return nil
+}
+
+// navQuery recalls earlier queries while typing and scrolls the result
+// otherwise
+func (m *Model) navQuery(nav events.Nav) {
+	if m.typingQuery {
+		m.queryInput.Nav(nav)
+		return
+	}
+	if m.result != nil {
+		m.resultOffset = nav.Move(m.resultOffset, len(m.result.Rows))
+	}
+}
+
+// showResult keeps a query's result, sizing a column to its widest value
+// with a gap after it
+func (m *Model) showResult(msg queryResultMsg) {
+	m.busy = false
+	m.queryErr = msg.err
+	m.result = nil
+	m.resultOffset = 0
+	if msg.err != nil {
+		return
+	}
+	m.result = &msg.result
+	columns := make([]components.Column, len(msg.result.Columns))
+	for i, title := range msg.result.Columns {
+		width := lipgloss.Width(title)
+		for _, row := range msg.result.Rows {
+			if i < len(row) {
+				width = max(width, lipgloss.Width(row[i]))
+			}
+		}
+		columns[i] = components.Column{Title: title, Width: min(width, maxColumnWidth) + 2}
+	}
+	m.resultTable = components.NewTable(columns...)
+}
+
+// renderQuery shows the query being typed and its result, or the tables
+// there are to query before the first one
+func (m *Model) renderQuery(b *strings.Builder) {
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorWarning)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorSubtle)
+	normalStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	switch {
+	case m.loadingQuery:
+		b.WriteString("⏳ Loading the collected data...\n")
+		return
+	case m.db == nil && m.queryErr != nil:
+		b.WriteString(errorStyle.Render("✗ " + m.queryErr.Error()))
+		b.WriteString("\n\n")
+		b.WriteString(controlStyle.Render("R Try Again • 1 Storage • 2 Changes"))
+		return
+	case m.db == nil:
+		return
+	}
+
+	prompt := "SQL: "
+	if m.typingQuery {
+		b.WriteString(sectionStyle.Render(prompt) + m.queryInput.View(max(m.width-12, 20)))
+	} else {
+		b.WriteString(sectionStyle.Render(prompt) + normalStyle.Render(m.queryInput.Value()))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.busy:
+		b.WriteString("⏳ Querying...\n\n")
+	case m.queryErr != nil:
+		b.WriteString(errorStyle.Render("✗ " + m.queryErr.Error()))
+		b.WriteString("\n\n")
+	case m.result != nil && len(m.result.Columns) == 0:
+		b.WriteString(mutedStyle.Render("No rows"))
+		b.WriteString("\n\n")
+	case m.result != nil:
+		b.WriteString(headerStyle.Render(components.TruncateString(m.resultTable.Header(), m.width-4)))
+		b.WriteString("\n")
+		visible := max(m.height-18, 5)
+		end := min(len(m.result.Rows), m.resultOffset+visible)
+		for _, row := range m.result.Rows[m.resultOffset:end] {
+			b.WriteString(normalStyle.Render(components.TruncateString(m.resultTable.Row(row...), m.width-4)))
+			b.WriteString("\n")
+		}
+		more := len(m.result.Rows) - end + m.result.More
+		if more > 0 {
+			b.WriteString(mutedStyle.Render(fmt.Sprintf("… and %d more rows", more)))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	default:
+		b.WriteString(sectionStyle.Render("Tables"))
+		b.WriteString("\n")
+		for _, table := range m.db.Tables {
+			line := fmt.Sprintf("  %-14s %6d rows  %s", table.Name, table.Rows, strings.Join(table.Columns, ", "))
+			b.WriteString(normalStyle.Render(components.TruncateString(line, m.width-4)))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("A copy of the data as of opening this view, read-only; R reloads it."))
+		b.WriteString("\n\n")
+	}
+
+	if m.typingQuery {
+		b.WriteString(controlStyle.Render("Enter Run • ↑/↓ Earlier Queries • Esc Stop Typing"))
+		return
+	}
+	b.WriteString(controlStyle.Render("↑/↓ Scroll • E Edit Query • R Reload Data • 1 Storage • 2 Changes"))
+}
@@ -0,0 +1,467 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/query"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// storeUsage is a store with its measured size
+type storeUsage struct {
+	store storage.Store
+	size  uint64
+}
+
+// Model represents the settings module state
+type Model struct {
+	config       *config.Config
+	runner       runner.Runner
+	width        int
+	height       int
+	footprint    uint64
+	stores       []storeUsage
+	cursor       int
+	view         int
+	changeCursor int // in changes.List(), newest first
+	busy         bool
+	message      string
+
+	typingRestore bool // typing the path of a backup to restore
+	restoreInput  string
+
+	db           *query.DB // the collected data, loaded on opening the query view
+	loadingQuery bool
+	typingQuery  bool
+	queryInput   components.TextInput
+	result       *query.Result
+	resultTable  components.Table
+	resultOffset int
+	queryErr     error
+}
+
+// New creates a new settings module
+func New(cfg *config.Config) *Model {
+	return &Model{config: cfg, runner: runner.Default}
+}
+
+// Init initializes the module
+func (m *Model) Init() tea.Cmd {
+	return m.refresh()
+}
+
+// Update handles messages
+func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case events.Nav:
+		switch {
+		case m.busy:
+		case m.view == queryView:
+			m.navQuery(msg)
+		case m.view == changesView:
+			m.changeCursor = msg.Move(m.changeCursor, len(changes.List()))
+		default:
+			m.cursor = msg.Move(m.cursor, len(m.stores))
+		}
+
+	case tea.KeyMsg:
+		if m.busy {
+			return m, nil
+		}
+		if m.typingRestore {
+			return m, m.handleRestoreInput(msg)
+		}
+		if m.typingQuery {
+			return m, m.handleQueryInput(msg)
+		}
+
+		switch msg.String() {
+		case "1":
+			m.view = storageView
+		case "2":
+			m.view = changesView
+			m.changeCursor = 0
+		case "3":
+			return m, m.openQuery()
+		case "e", "enter":
+			if m.view == queryView && m.db != nil {
+				m.typingQuery = true
+			}
+		case "u":
+			if m.view == changesView {
+				return m, m.revertChange()
+			}
+		case "r":
+			if m.view == queryView {
+				return m, m.loadQuery()
+			}
+			return m, m.refresh()
+		case "p", "b", "o", "x":
+			if m.view == storageView {
+				return m, m.storageAction(msg.String())
+			}
+		}
+
+	case storageCommandMsg:
+		if !m.busy {
+			m.view, m.typingQuery = storageView, false
+			return m, m.storageAction(string(msg))
+		}
+
+	case purgeMsg:
+		if !m.busy {
+			return m, m.purge()
+		}
+
+	case restoreMsg:
+		if !m.busy {
+			return m, m.restore(msg.path)
+		}
+
+	case queryLoadedMsg:
+		m.loadingQuery = false
+		m.db, m.queryErr = msg.db, msg.err
+		if msg.err != nil {
+			m.typingQuery = false
+		}
+
+	case queryResultMsg:
+		m.showResult(msg)
+
+	case purgeCancelledMsg:
+		m.message = "Purge cancelled"
+
+	case usageMsg:
+		m.footprint = msg.footprint
+		m.stores = msg.stores
+		if m.cursor >= len(m.stores) {
+			m.cursor = 0
+		}
+		m.busy = false
+
+	case storageActionMsg:
+		m.busy = false
+		m.message = msg.note
+		return m, m.refresh()
+
+	case revertedMsg:
+		m.busy = false
+		m.message = "✓ Reverted " + msg.what
+		if msg.err != nil {
+			m.message = fmt.Sprintf("✗ Reverting %s: %v", msg.what, msg.err)
+		}
+		return m, components.StatusToast(m.message)
+	}
+
+	return m, nil
+}
+
+// View renders the module
+func (m *Model) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	msgStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("⚙️  SETTINGS"))
+	b.WriteString("\n\n")
+	b.WriteString(m.renderViews())
+	b.WriteString("\n\n")
+
+	switch m.view {
+	case queryView:
+		m.renderQuery(&b)
+	case changesView:
+		m.renderChanges(&b)
+		if m.busy {
+			b.WriteString("⏳ Reverting...\n\n")
+		}
+		b.WriteString(controlStyle.Render("↑/↓ Navigate • U Revert Selected • 1 Storage • 3 Query"))
+	default:
+		m.renderStorage(&b)
+	}
+
+	if m.message != "" {
+		b.WriteString("\n\n")
+		b.WriteString(msgStyle.Render(m.message))
+	}
+
+	// Apply viewport to prevent overflow
+	maxHeight := m.height - 4 // Account for margins
+	if maxHeight < 10 {
+		maxHeight = 10
+	}
+
+	return lipgloss.NewStyle().MaxHeight(maxHeight).Render(b.String())
+}
+
+// renderViews is the view switcher line
+func (m *Model) renderViews() string {
+	activeStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorPrimary)
+	inactiveStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+
+	names := []string{"[1] Storage", fmt.Sprintf("[2] Changes (%d)", len(changes.List())), "[3] Query"}
+	for i, name := range names {
+		if i == m.view {
+			names[i] = activeStyle.Render(name)
+		} else {
+			names[i] = inactiveStyle.Render(name)
+		}
+	}
+	return strings.Join(names, "  ")
+}
+
+// renderStorage shows the data directory and per-store retention
+func (m *Model) renderStorage(b *strings.Builder) {
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorWarning)
+	labelStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle).Width(14)
+	valueStyle := lipgloss.NewStyle().Foreground(components.ColorBright)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	b.WriteString(sectionStyle.Render("Storage"))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Config:"), valueStyle.Render(filepath.Join(m.config.Dir(), "config.yaml"))))
+	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Data:"), valueStyle.Render(storage.DataDir(m.config))))
+	b.WriteString(fmt.Sprintf("%s %s\n\n", labelStyle.Render("On disk:"), valueStyle.Render(formatBytes(m.footprint))))
+
+	b.WriteString(sectionStyle.Render("Retention"))
+	b.WriteString("\n")
+	b.WriteString(controlStyle.Render(fmt.Sprintf("  %-12s %10s %10s %10s", "STORE", "SIZE", "MAX AGE", "MAX SIZE")))
+	b.WriteString("\n")
+	for i, usage := range m.stores {
+		line := fmt.Sprintf("%-12s %10s %10s %10s",
+			usage.store.Name,
+			formatBytes(usage.size),
+			formatLimit(usage.store.Policy.MaxAgeDays, "d"),
+			formatLimit(usage.store.Policy.MaxSizeMB, " MB"))
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(normalStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(controlStyle.Render("Limits are read from storage.retention in config.yaml (0 = unlimited)"))
+	b.WriteString("\n\n")
+
+	if m.busy {
+		b.WriteString("⏳ Working...\n\n")
+	}
+
+	if m.typingRestore {
+		b.WriteString(sectionStyle.Render("Restore backup: ") + m.restoreInput + "█\n")
+		b.WriteString(controlStyle.Render("Enter Restore (asks first) • Esc Cancel"))
+		return
+	}
+	b.WriteString(controlStyle.Render("↑/↓ Navigate • P Apply Retention • X Purge All Data • B Back Up • O Restore • R Refresh • 2 Changes • 3 Query"))
+}
+
+// Title returns the module title
+func (m *Model) Title() string { return "Settings" }
+
+// Help describes the settings keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "SETTINGS",
+		Description: "Data Dev Cockpit keeps on disk, and the system settings it changed this session.",
+		Sections: []help.Section{
+			{Title: "Storage", Bindings: []help.Binding{
+				{Key: "1", Desc: "Storage view"},
+				{Key: "↑/↓", Desc: "Navigate stores"},
+				{Key: "P", Desc: "Apply retention now"},
+				{Key: "X", Desc: "Purge all data (asks first)"},
+				{Key: "B", Desc: "Back up ~/.devcockpit (config, themes, state, data) to a file in your home folder"},
+				{Key: "O", Desc: "Restore a backup, the newest one offered first (asks first), then quit"},
+				{Key: "R", Desc: "Refresh sizes"},
+			}},
+			{Title: "Changes", Bindings: []help.Binding{
+				{Key: "2", Desc: "Changes view: every setting changed this session"},
+				{Key: "↑/↓", Desc: "Navigate changes"},
+				{Key: "U", Desc: "Revert the selected change"},
+			}},
+			{Title: "Query", Bindings: []help.Binding{
+				{Key: "3", Desc: "Query view: SQL over the metrics and the audit logs, read-only"},
+				{Key: "Enter", Desc: "Run the query (E edits it again)"},
+				{Key: "↑/↓", Desc: "Earlier queries while typing, otherwise scroll the result"},
+				{Key: "Esc", Desc: "Stop typing"},
+				{Key: "R", Desc: "Reload the data"},
+			}},
+		},
+	}
+}
+
+// HasOpenModal returns true if the module has an open modal/dialog
+func (m *Model) HasOpenModal() bool { return m.typingRestore || m.typingQuery }
+
+// savedState is what the module keeps between launches
+type savedState struct {
+	Cursor int `json:"cursor"`
+}
+
+// SaveState returns the selected store position
+func (m *Model) SaveState() (json.RawMessage, error) {
+	return json.Marshal(savedState{Cursor: m.cursor})
+}
+
+// RestoreState applies state saved by the previous launch
+func (m *Model) RestoreState(data json.RawMessage) error {
+	var saved savedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	m.cursor = saved.Cursor
+	return nil
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	return []palette.Command{
+		{Title: "Apply data retention", Hint: "Prune stored data that exceeds its retention limits", Msg: storageCommandMsg("p")},
+		{Title: "Purge all stored data", Hint: "Asks for confirmation first", Msg: storageCommandMsg("x")},
+		{Title: "Back up Dev Cockpit", Hint: "Archive config, themes, state and data to your home folder", Msg: storageCommandMsg("b")},
+		{Title: "Restore a Dev Cockpit backup", Hint: "Replace ~/.devcockpit with a backup archive", Msg: storageCommandMsg("o")},
+		{Title: "Show session changes", Hint: "Settings Dev Cockpit changed this session, with revert", Msg: palette.Key("2")},
+		{Title: "Query collected data", Hint: "Read-only SQL over the metrics and the audit logs", Msg: palette.Key("3")},
+	}
+}
+
+// Messages
+type usageMsg struct {
+	footprint uint64
+	stores    []storeUsage
+}
+
+type storageActionMsg struct{ note string }
+
+func (m *Model) refresh() tea.Cmd {
+	cfg := m.config
+	return func() tea.Msg {
+		footprint := storage.DirSize(cfg.Dir())
+
+		// Count the data dir separately when it lives outside ~/.devcockpit
+		dataDir := storage.DataDir(cfg)
+		if rel, err := filepath.Rel(cfg.Dir(), dataDir); err != nil || strings.HasPrefix(rel, "..") {
+			footprint += storage.DirSize(dataDir)
+		}
+
+		var stores []storeUsage
+		for _, store := range storage.Stores(cfg) {
+			stores = append(stores, storeUsage{store: store, size: store.Size()})
+		}
+		return usageMsg{footprint: footprint, stores: stores}
+	}
+}
+
+// storageCommandMsg runs a storage view key from the command palette,
+// whichever view is shown
+type storageCommandMsg string
+
+// storageAction runs what a storage view key does
+func (m *Model) storageAction(key string) tea.Cmd {
+	switch key {
+	case "p":
+		return m.prune()
+	case "b":
+		return m.backUp()
+	case "o":
+		m.startRestore()
+	case "x":
+		return dialog.Confirm(dialog.Request{
+			Title:        "Purge all stored data?",
+			Detail:       fmt.Sprintf("Everything in %s is deleted: metrics, reports, snapshots and the audit log.", storage.DataDir(m.config)),
+			ConfirmLabel: "Purge",
+			Destructive:  true,
+			OnConfirm:    purgeMsg{},
+			OnCancel:     purgeCancelledMsg{},
+		})
+	}
+	return nil
+}
+
+func (m *Model) prune() tea.Cmd {
+	m.busy = true
+	cfg := m.config
+	return func() tea.Msg {
+		removed, trimmed := 0, 0
+		var freed uint64
+		for _, result := range storage.ApplyRetention(cfg) {
+			removed += result.Removed
+			trimmed += result.Trimmed
+			freed += result.Freed
+		}
+		switch {
+		case removed == 0 && trimmed == 0:
+			return storageActionMsg{note: "✓ All stores are within their retention limits"}
+		case trimmed == 0:
+			return storageActionMsg{note: fmt.Sprintf("✓ Removed %d file(s), freed %s", removed, formatBytes(freed))}
+		}
+		return storageActionMsg{note: fmt.Sprintf("✓ Removed %d file(s) and trimmed %d log(s) to their newest entries, freed %s", removed, trimmed, formatBytes(freed))}
+	}
+}
+
+// purgeMsg and purgeCancelledMsg answer the purge confirmation
+type (
+	purgeMsg          struct{}
+	purgeCancelledMsg struct{}
+)
+
+func (m *Model) purge() tea.Cmd {
+	m.busy = true
+	cfg := m.config
+	return func() tea.Msg {
+		logger.Info("User requested purge of all stored data")
+		freed, err := storage.PurgeAll(cfg)
+		if err != nil {
+			return storageActionMsg{note: fmt.Sprintf("✗ Purge failed: %v", err)}
+		}
+		return storageActionMsg{note: fmt.Sprintf("✓ Purged all stored data, freed %s", formatBytes(freed))}
+	}
+}
+
+func formatLimit(value int, unit string) string {
+	if value <= 0 {
+		return "∞"
+	}
+	return fmt.Sprintf("%d%s", value, unit)
+}
+
+// formatBytes formats bytes into human-readable format
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,212 @@
+package settings
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snapshotModel returns the module with fixed store sizes instead of a scan
+func snapshotModel(t *testing.T) *Model {
+	t.Helper()
+	t.Setenv("HOME", "/Users/dev")
+
+	cfg := &config.Config{}
+	cfg.Storage.Retention = config.RetentionConfig{
+		Metrics:   config.RetentionPolicy{MaxAgeDays: 30, MaxSizeMB: 100},
+		Reports:   config.RetentionPolicy{MaxAgeDays: 90, MaxSizeMB: 50},
+		Snapshots: config.RetentionPolicy{MaxAgeDays: 30},
+		Audit:     config.RetentionPolicy{MaxAgeDays: 180, MaxSizeMB: 20},
+	}
+
+	m := New(cfg)
+	var stores []storeUsage
+	for i, store := range storage.Stores(cfg) {
+		stores = append(stores, storeUsage{store: store, size: uint64(i+1) * 3 * 1024 * 1024})
+	}
+	m.Update(usageMsg{footprint: 31 * 1024 * 1024, stores: stores})
+	return m
+}
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := snapshotModel(t)
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			golden.RequireEqual(t, m.View())
+		})
+	}
+}
+
+func TestPurgeAsksFirst(t *testing.T) {
+	m := snapshotModel(t)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if cmd == nil {
+		t.Fatal("X should ask before purging")
+	}
+	req, ok := cmd().(dialog.Request)
+	if !ok || !req.Destructive || req.OnConfirm != (purgeMsg{}) {
+		t.Fatalf("X returned %#v, want a destructive confirmation", req)
+	}
+
+	m.Update(req.OnCancel)
+	if m.busy || m.message != "Purge cancelled" {
+		t.Errorf("cancel: busy = %v message = %q", m.busy, m.message)
+	}
+}
+
+func TestStorageKeysOnlyInStorageView(t *testing.T) {
+	m := snapshotModel(t)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	for _, key := range []string{"p", "b", "o", "x"} {
+		if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}); cmd != nil || m.typingRestore || m.busy {
+			t.Errorf("%s acted in the changes view", key)
+		}
+	}
+
+	// The palette's storage commands still work from there
+	_, cmd := m.Update(storageCommandMsg("x"))
+	if req, ok := cmd().(dialog.Request); !ok || req.OnConfirm != (purgeMsg{}) || m.view != storageView {
+		t.Errorf("the purge command returned %#v in view %v", req, m.view)
+	}
+}
+
+func TestRevertChange(t *testing.T) {
+	t.Cleanup(changes.Clear)
+	reverted := false
+	changes.Record(changes.Change{
+		Source: "Quick Actions", What: "com.apple.dock autohide", Before: "false", After: "true",
+		Revert: func() error { reverted = true; return nil },
+	})
+
+	m := snapshotModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	if view := m.View(); !strings.Contains(view, "[2] Changes (1)") || !strings.Contains(view, "- false") || !strings.Contains(view, "+ true") {
+		t.Fatalf("changes view:\n%s", view)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if cmd == nil {
+		t.Fatal("U should revert the selected change")
+	}
+	m.Update(cmd())
+	if !reverted || m.message != "✓ Reverted com.apple.dock autohide" || !strings.Contains(m.View(), "(reverted)") {
+		t.Errorf("reverted = %v message = %q", reverted, m.message)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")}); cmd != nil || m.message != "Already reverted" {
+		t.Errorf("reverting twice: message = %q", m.message)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".devcockpit")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("theme: dark\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(&config.Config{})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m.Update(cmd())
+	if !strings.HasPrefix(m.message, "✓ Backed up 1 files") {
+		t.Fatalf("backup: %q", m.message)
+	}
+
+	// O offers the backup just made, and asks before restoring it
+	os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("theme: light\n"), 0644)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if !m.HasOpenModal() || !strings.Contains(m.restoreInput, "devcockpit-backup-") {
+		t.Fatalf("restore offered %q", m.restoreInput)
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	req, ok := cmd().(dialog.Request)
+	if !ok || !req.Destructive {
+		t.Fatalf("Enter returned %#v, want a destructive confirmation", req)
+	}
+	_, cmd = m.Update(req.OnConfirm)
+	if restored, ok := cmd().(events.Restored); !ok || !strings.HasPrefix(restored.Note, "✓ Restored") {
+		t.Errorf("restore returned %#v, want the app told to quit", restored)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "config.yaml")); string(data) != "theme: dark\n" {
+		t.Errorf("config after restore = %q (%s)", data, m.message)
+	}
+}
+
+// sqliteFake loads nothing and answers each query with sqlite3's output
+// for it, as the database sqlite3 is given lives in a temporary folder
+type sqliteFake struct {
+	*runner.Fake
+	answers map[string]string
+}
+
+func (f *sqliteFake) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	sql := cmd.Args[len(cmd.Args)-1]
+	if strings.HasPrefix(sql, ".read ") {
+		return nil, nil
+	}
+	if answer, ok := f.answers[sql]; ok {
+		return []byte(answer), nil
+	}
+	return []byte("Error: in prepare, no such table: " + sql), errors.New("exit status 1")
+}
+
+func TestQuery(t *testing.T) {
+	m := snapshotModel(t)
+	m.config.Storage.DataDir = t.TempDir()
+	m.runner = &sqliteFake{Fake: runner.NewFake(), answers: map[string]string{
+		"SELECT action, count(*) AS runs FROM quick_actions GROUP BY action": "action,runs\nFlush DNS,2\nFix Audio,1\n",
+	}}
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	if cmd == nil {
+		t.Fatal("3 should load the data")
+	}
+	m.Update(cmd())
+	if m.db == nil || !m.HasOpenModal() || !strings.Contains(m.View(), "metrics") {
+		t.Fatalf("query view after loading (err %v):\n%s", m.queryErr, m.View())
+	}
+	path := m.db.Path
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("SELECT action, count(*) AS runs FROM quick_actions GROUP BY action")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	view := m.View()
+	if !strings.Contains(view, "Flush DNS   2") || !strings.Contains(view, "Fix Audio   1") {
+		t.Errorf("result not shown:\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" x")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if view := m.View(); !strings.Contains(view, "✗ in prepare, no such table") {
+		t.Errorf("error not shown:\n%s", view)
+	}
+
+	// Esc stops typing, so the app's keys work again; quitting removes the copy
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.HasOpenModal() {
+		t.Error("esc should stop typing")
+	}
+	m.Shutdown()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Shutdown left the query database behind")
+	}
+}
@@ -1,11 +1,15 @@
 package support
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"runtime"
 
+	"github.com/caioricciuti/dev-cockpit/internal/ui/clipboard"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
 	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -39,17 +43,11 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.status = ""
 	case events.Blur:
 		m.status = ""
+	case events.Nav:
+		m.selectedItem = msg.Move(m.selectedItem, 2)
 
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "up", "k":
-			if m.selectedItem > 0 {
-				m.selectedItem--
-			}
-		case "down", "j":
-			if m.selectedItem < 1 {
-				m.selectedItem++
-			}
 		case "1":
 			return m, m.openURL("GitHub Sponsors", sponsorsURL)
 		case "2":
@@ -84,36 +82,36 @@ func (m *Model) View() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00D9FF"))
+		Foreground(components.ColorPrimary)
 
 	paragraphStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#DDD"))
+		Foreground(components.ColorText)
 
 	selectedCardStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00D9FF")).
+		BorderForeground(components.ColorPrimary).
 		Padding(1, 2).
 		Width(m.width - 10)
 
 	unselectedCardStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#444")).
+		BorderForeground(components.ColorFaint).
 		Padding(1, 2).
 		Width(m.width - 10)
 
 	optionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	urlStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666"))
+		Foreground(components.ColorMuted)
 
 	statusStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#0FD976")).
+		Foreground(components.ColorSuccess).
 		Bold(true)
 
 	controlsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666"))
+		Foreground(components.ColorMuted)
 
 	// Build content simply
 	content := []string{
@@ -173,6 +171,20 @@ func (m *Model) Title() string {
 	return "Support"
 }
 
+// Help describes the support keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "SUPPORT",
+		Description: "Ways to support Dev Cockpit's development.",
+		Sections: []help.Section{{Title: "Keys", Bindings: []help.Binding{
+			{Key: "↑/↓", Desc: "Navigate links"},
+			{Key: "Enter / Space", Desc: "Open the selected link"},
+			{Key: "1 / 2", Desc: "Open GitHub Sponsors / Buy Me a Coffee"},
+			{Key: "C", Desc: "Copy the selected link"},
+		}}},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
 func (m *Model) HasOpenModal() bool {
 	return false
@@ -194,29 +206,13 @@ func (m *Model) openURL(label, url string) tea.Cmd {
 
 func (m *Model) copyToClipboard(url string) tea.Cmd {
 	return func() tea.Msg {
-		// Use pbcopy on macOS to copy to clipboard
-		if runtime.GOOS == "darwin" {
-			cmd := exec.Command("pbcopy")
-			stdin, err := cmd.StdinPipe()
-			if err != nil {
-				return supportMsg{note: fmt.Sprintf("❌ Failed to access clipboard: %v", err)}
-			}
-
-			if err := cmd.Start(); err != nil {
-				return supportMsg{note: fmt.Sprintf("❌ Failed to copy: %v", err)}
-			}
-
-			if _, err := stdin.Write([]byte(url)); err != nil {
-				return supportMsg{note: fmt.Sprintf("❌ Failed to write to clipboard: %v", err)}
+		if err := clipboard.Copy(m.Title(), url); err != nil {
+			if errors.Is(err, clipboard.ErrUnavailable) {
+				return supportMsg{note: fmt.Sprintf("📋 URL: %s", url)}
 			}
-
-			stdin.Close()
-			cmd.Wait()
-
-			return supportMsg{note: fmt.Sprintf("✓ Copied to clipboard: %s", url)}
+			return supportMsg{note: fmt.Sprintf("❌ %v", err)}
 		}
-
-		return supportMsg{note: fmt.Sprintf("📋 URL: %s", url)}
+		return supportMsg{note: fmt.Sprintf("✓ Copied to clipboard: %s", url)}
 	}
 }
 
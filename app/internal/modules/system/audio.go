@@ -0,0 +1,364 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/coreaudio"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// audioTab is the index of the Audio tab
+const audioTab = 5
+
+// inputLevelFeature samples the microphone for the level meter
+var inputLevelFeature = tools.Feature{
+	Name:  "Input Level",
+	Tools: []tools.Tool{{Name: "sox", Paths: []string{"/opt/homebrew/bin/sox", "/usr/local/bin/sox"}}},
+	Hint:  "brew install sox",
+}
+
+// Core Audio calls, replaced in tests
+var (
+	listAudioDevices = coreaudio.Devices
+	setSampleRate    = coreaudio.SetSampleRate
+)
+
+// defaultMicVolume is restored on unmute when the level before muting
+// isn't known
+const defaultMicVolume = 75
+
+// meterFloor is the quietest level the meter shows, in dBFS
+const meterFloor = -60.0
+
+// soxRMSPattern finds the RMS amplitude in `sox ... stat` output
+var soxRMSPattern = regexp.MustCompile(`RMS\s+amplitude:\s+([0-9.]+)`)
+
+type audioMsg struct {
+	devices     []coreaudio.Device
+	devicesErr  error
+	inputVolume int
+	volumeErr   error
+}
+
+type inputLevelMsg struct {
+	db  float64
+	err error
+}
+
+type audioActionMsg struct {
+	note string
+	err  error
+}
+
+// scanAudio reads the output devices and the microphone volume
+func (m *Model) scanAudio() tea.Cmd {
+	m.audioChecked = true
+	return func() tea.Msg {
+		var msg audioMsg
+		devices, err := listAudioDevices()
+		for _, d := range devices {
+			if d.Output {
+				msg.devices = append(msg.devices, d)
+			}
+		}
+		msg.devicesErr = err
+		msg.inputVolume, msg.volumeErr = m.readInputVolume()
+		return msg
+	}
+}
+
+func (m *Model) readInputVolume() (int, error) {
+	output, err := m.runner.Output(exec.Command("osascript", "-e", "input volume of (get volume settings)"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the input volume: %w", err)
+	}
+	volume, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected input volume %q", strings.TrimSpace(string(output)))
+	}
+	return volume, nil
+}
+
+// setMicVolume mutes the microphone for every app at 0 and unmutes it at
+// any other level
+func (m *Model) setMicVolume(volume int, note string) tea.Cmd {
+	m.audioBusy = true
+	return func() tea.Msg {
+		script := fmt.Sprintf("set volume input volume %d", volume)
+		if output, err := m.runner.CombinedOutput(exec.Command("osascript", "-e", script)); err != nil {
+			return audioActionMsg{err: fmt.Errorf("osascript: %s", firstLine(output, err))}
+		}
+		return audioActionMsg{note: note}
+	}
+}
+
+// toggleMute mutes the microphone, remembering its level, or restores it
+func (m *Model) toggleMute() tea.Cmd {
+	if m.micVolume > 0 {
+		m.micRestore = m.micVolume
+		return m.setMicVolume(0, "✓ Microphone muted")
+	}
+	volume := m.micRestore
+	if volume == 0 {
+		volume = defaultMicVolume
+	}
+	return m.setMicVolume(volume, fmt.Sprintf("✓ Microphone unmuted (%d%%)", volume))
+}
+
+// readInputLevel records a moment of the default input and measures it
+func (m *Model) readInputLevel() tea.Cmd {
+	path := m.meterCapability.Path
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// stat writes to stderr
+		output, err := m.runner.CombinedOutput(exec.CommandContext(ctx, path, "-q", "-d", "-n", "trim", "0", "0.3", "stat"))
+		if err != nil {
+			return inputLevelMsg{err: fmt.Errorf("sox: %s", firstLine(output, err))}
+		}
+		db, err := parseInputLevel(string(output))
+		return inputLevelMsg{db: db, err: err}
+	}
+}
+
+// parseInputLevel converts the RMS amplitude from `sox stat` to dBFS
+func parseInputLevel(output string) (float64, error) {
+	match := soxRMSPattern.FindStringSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("no RMS amplitude in sox output")
+	}
+	rms, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	if rms <= 0 {
+		return meterFloor, nil
+	}
+	return math.Max(meterFloor, 20*math.Log10(rms)), nil
+}
+
+// meterTask samples the microphone while the meter is on and the Audio
+// tab is shown
+func (m *Model) meterTask() tea.Cmd {
+	if !m.meterOn || m.activeTab != audioTab {
+		return nil
+	}
+	return m.readInputLevel()
+}
+
+// toggleMeter starts or stops the level meter. Recording needs the
+// terminal to have microphone access.
+func (m *Model) toggleMeter() {
+	if m.meterOn {
+		m.meterOn = false
+		m.meterErr = nil
+		return
+	}
+	m.meterCapability = tools.Resolve(m.runner, inputLevelFeature)
+	if !m.meterCapability.Available() {
+		m.audioMessage = "✗ The level meter needs sox: " + m.meterCapability.Reason
+		return
+	}
+	m.meterOn = true
+	m.meterLevel = meterFloor
+	m.audioMessage = ""
+}
+
+// applySampleRate switches the selected output device to rate
+func (m *Model) applySampleRate(device coreaudio.Device, rate float64) tea.Cmd {
+	m.audioBusy = true
+	return func() tea.Msg {
+		if err := setSampleRate(device.ID, rate); err != nil {
+			return audioActionMsg{err: err}
+		}
+		return audioActionMsg{note: fmt.Sprintf("✓ %s set to %s", device.Name, coreaudio.FormatRate(rate))}
+	}
+}
+
+// updateAudio applies audio scans, meter readings and action results
+func (m *Model) updateAudio(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case audioMsg:
+		m.audioDevices = msg.devices
+		m.audioErr = msg.devicesErr
+		m.micVolume = msg.inputVolume
+		m.micErr = msg.volumeErr
+		if m.audioCursor >= len(m.audioDevices) {
+			m.audioCursor = 0
+		}
+	case inputLevelMsg:
+		if !m.meterOn {
+			return nil
+		}
+		m.meterErr = msg.err
+		if msg.err == nil {
+			m.meterLevel = msg.db
+		}
+	case audioActionMsg:
+		m.audioBusy = false
+		m.audioMessage = msg.note
+		if msg.err != nil {
+			m.audioMessage = "✗ " + msg.err.Error()
+		}
+		return tea.Batch(components.StatusToast(m.audioMessage), m.scanAudio())
+	}
+	return nil
+}
+
+// handleAudioKey handles keys on the Audio tab and reports whether the
+// key was used
+func (m *Model) handleAudioKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	if m.ratePicking {
+		return m.handleRateKey(key), true
+	}
+	if m.audioBusy {
+		return nil, key == "r" || key == "m" || key == "enter"
+	}
+
+	switch key {
+	case "r":
+		m.audioMessage = ""
+		return m.scanAudio(), true
+	case "m":
+		if m.micErr != nil {
+			m.audioMessage = "✗ " + m.micErr.Error()
+			break
+		}
+		return m.toggleMute(), true
+	case "i":
+		m.toggleMeter()
+	case "enter":
+		if m.audioCursor >= len(m.audioDevices) {
+			break
+		}
+		device := m.audioDevices[m.audioCursor]
+		if len(device.Rates) < 2 {
+			m.audioMessage = fmt.Sprintf("%s only supports %s", device.Name, coreaudio.FormatRate(device.SampleRate))
+			break
+		}
+		m.ratePicking = true
+		m.rateCursor = 0
+		for i, rate := range device.Rates {
+			if rate == device.SampleRate {
+				m.rateCursor = i
+			}
+		}
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// handleRateKey moves through and applies the selected device's rates
+func (m *Model) handleRateKey(key string) tea.Cmd {
+	device := m.audioDevices[m.audioCursor]
+	switch key {
+	case "esc", "q":
+		m.ratePicking = false
+	case "enter":
+		m.ratePicking = false
+		rate := device.Rates[m.rateCursor]
+		if rate == device.SampleRate {
+			return nil
+		}
+		m.audioMessage = "Switching to " + coreaudio.FormatRate(rate) + "..."
+		return m.applySampleRate(device, rate)
+	}
+	return nil
+}
+
+// renderAudio is the Audio tab
+func (m *Model) renderAudio() string {
+	style := lipgloss.NewStyle().Padding(1)
+	highlightStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	actionStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorBright).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(highlightStyle.Render("Microphone") + "\n")
+	switch {
+	case !m.audioChecked:
+		b.WriteString("  ⏳ Reading audio settings...\n")
+	case m.micErr != nil:
+		b.WriteString("  " + errorStyle.Render("✗ "+m.micErr.Error()) + "\n")
+	case m.micVolume == 0:
+		restore := ""
+		if m.micRestore > 0 {
+			restore = fmt.Sprintf(" (was %d%%)", m.micRestore)
+		}
+		b.WriteString("  🔇 " + errorStyle.Render("Muted") + restore + "   " + actionStyle.Render("[M]") + " Unmute\n")
+	default:
+		b.WriteString(fmt.Sprintf("  🎙  Input volume %d%%   %s Mute\n", m.micVolume, actionStyle.Render("[M]")))
+	}
+
+	switch {
+	case !m.meterOn:
+		b.WriteString("  " + actionStyle.Render("[I]") + " Show input level " + mutedStyle.Render("(records the default input; needs sox)") + "\n")
+	case m.meterErr != nil:
+		b.WriteString("  " + errorStyle.Render("✗ "+m.meterErr.Error()) + "  " + actionStyle.Render("[I]") + " Stop\n")
+	default:
+		// Map meterFloor..0 dBFS onto the bar
+		fraction := (m.meterLevel - meterFloor) / -meterFloor
+		b.WriteString(fmt.Sprintf("  Level %s %4.0f dB  %s Stop\n", m.renderProgressBar(24, fraction), m.meterLevel, actionStyle.Render("[I]")))
+	}
+
+	b.WriteString("\n" + highlightStyle.Render("Output Devices") + "\n")
+	switch {
+	case m.audioErr != nil:
+		b.WriteString("  " + errorStyle.Render("✗ "+m.audioErr.Error()) + "\n")
+	case m.audioChecked && len(m.audioDevices) == 0:
+		b.WriteString(mutedStyle.Render("  No output devices found") + "\n")
+	}
+
+	if m.ratePicking {
+		device := m.audioDevices[m.audioCursor]
+		b.WriteString("  Sample rate for " + device.Name + "\n")
+		for i, rate := range device.Rates {
+			line := coreaudio.FormatRate(rate)
+			if rate == device.SampleRate {
+				line += " (current)"
+			}
+			if i == m.rateCursor {
+				b.WriteString("  ▶ " + selectedStyle.Render(line) + "\n")
+			} else {
+				b.WriteString("    " + line + "\n")
+			}
+		}
+		b.WriteString(mutedStyle.Render("  ↑/↓ select • Enter apply • Esc back") + "\n")
+		return style.Render(b.String())
+	}
+
+	for i, device := range m.audioDevices {
+		line := fmt.Sprintf("%-32s %-10s", device.Name, coreaudio.FormatRate(device.SampleRate))
+		if device.Default {
+			line += " default"
+		}
+		if i == m.audioCursor {
+			b.WriteString("▶ " + selectedStyle.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	b.WriteString("\n" + actionStyle.Render("[Enter]") + " Change sample rate  " +
+		actionStyle.Render("[M]") + " Mute/unmute mic  " +
+		actionStyle.Render("[I]") + " Level meter  " +
+		actionStyle.Render("[R]") + " Rescan\n")
+	if m.audioMessage != "" {
+		b.WriteString(m.audioMessage + "\n")
+	}
+	return style.Render(b.String())
+}
@@ -0,0 +1,131 @@
+package system
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/coreaudio"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stubAudioDevices replaces Core Audio with two outputs and a microphone
+// for the duration of the test
+func stubAudioDevices(t *testing.T) *[]string {
+	t.Helper()
+	var applied []string
+	devices := []coreaudio.Device{
+		{ID: 41, Name: "MacBook Pro Microphone", Input: true, SampleRate: 48000, Rates: []float64{48000}},
+		{ID: 48, Name: "MacBook Pro Speakers", Output: true, Default: true, SampleRate: 48000, Rates: []float64{44100, 48000, 96000}},
+		{ID: 62, Name: "Studio Display Speakers", Output: true, SampleRate: 48000, Rates: []float64{48000}},
+	}
+	list, set := listAudioDevices, setSampleRate
+	listAudioDevices = func() ([]coreaudio.Device, error) { return devices, nil }
+	setSampleRate = func(id uint32, rate float64) error {
+		applied = append(applied, coreaudio.FormatRate(rate))
+		for i := range devices {
+			if devices[i].ID == id {
+				devices[i].SampleRate = rate
+			}
+		}
+		return nil
+	}
+	t.Cleanup(func() { listAudioDevices, setSampleRate = list, set })
+	return &applied
+}
+
+func TestParseInputLevel(t *testing.T) {
+	output := "Samples read:             28800\nLength (seconds):      0.300000\nMaximum amplitude:     0.250000\nRMS     amplitude:     0.010000\n"
+	if db, err := parseInputLevel(output); err != nil || db != -40 {
+		t.Errorf("parseInputLevel = %v, %v; want -40", db, err)
+	}
+	if db, _ := parseInputLevel("RMS     amplitude:     0.000000\n"); db != meterFloor {
+		t.Errorf("silence = %v, want %v", db, meterFloor)
+	}
+	if _, err := parseInputLevel("sox FAIL formats: can't open input"); err == nil {
+		t.Error("output without a level should fail")
+	}
+}
+
+func TestMuteRestoresVolume(t *testing.T) {
+	stubAudioDevices(t)
+	fake := runner.NewFake().
+		Set("osascript -e input volume of (get volume settings)", "68\n", nil).
+		Set("osascript -e set volume input volume 0", "", nil).
+		Set("osascript -e set volume input volume 68", "", nil)
+	m := New(nil)
+	m.runner = fake
+	m.activeTab = audioTab
+	m.Update(m.scanAudio()())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m.Update(cmd())
+	fake.Set("osascript -e input volume of (get volume settings)", "0\n", nil)
+	m.Update(m.scanAudio()())
+	if m.micVolume != 0 || !strings.Contains(m.renderAudio(), "(was 68%)") {
+		t.Fatalf("not muted: volume = %d", m.micVolume)
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if msg := cmd(); msg.(audioActionMsg).err != nil {
+		t.Fatal(msg.(audioActionMsg).err)
+	}
+	if calls := fake.Calls(); calls[len(calls)-1] != "osascript -e set volume input volume 68" {
+		t.Errorf("unmute ran %q", calls[len(calls)-1])
+	}
+}
+
+func TestSampleRatePicker(t *testing.T) {
+	applied := stubAudioDevices(t)
+	m := New(nil)
+	m.runner = runner.NewFake().Set("osascript -e input volume of (get volume settings)", "68\n", nil)
+	m.activeTab = audioTab
+	m.Update(m.scanAudio()())
+	if len(m.audioDevices) != 2 {
+		t.Fatalf("output devices = %+v", m.audioDevices)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.HasOpenModal() || m.rateCursor != 1 {
+		t.Fatalf("picker open = %v on rate %d, want the current 48 kHz", m.ratePicking, m.rateCursor)
+	}
+	m.Update(events.NavDown)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if len(*applied) != 1 || (*applied)[0] != "96 kHz" {
+		t.Errorf("applied %v, want 96 kHz", *applied)
+	}
+	if m.audioMessage != "✓ MacBook Pro Speakers set to 96 kHz" {
+		t.Errorf("message = %q", m.audioMessage)
+	}
+
+	// A device with one rate has nothing to pick
+	m.Update(events.NavDown)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.ratePicking {
+		t.Error("picker opened for a single-rate device")
+	}
+}
+
+func TestInputMeterNeedsSox(t *testing.T) {
+	m := New(nil)
+	m.runner = runner.NewFake().Missing("sox")
+	m.activeTab = audioTab
+	m.toggleMeter()
+	if m.meterOn || !strings.Contains(m.audioMessage, "brew install sox") {
+		t.Errorf("meter on = %v, message = %q", m.meterOn, m.audioMessage)
+	}
+
+	m.runner = runner.NewFake().Set("/usr/bin/sox -q -d -n trim 0 0.3 stat", "", errors.New("exit status 1"))
+	m.toggleMeter()
+	m.Update(m.meterTask()())
+	if m.meterErr == nil {
+		t.Error("a failed recording should be shown")
+	}
+	m.activeTab = 0
+	if m.meterTask() != nil {
+		t.Error("the meter should pause on other tabs")
+	}
+}
@@ -0,0 +1,238 @@
+package system
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chargeLimitFeature caps the battery charge. bclm writes the SMC key
+// directly (as root); batt asks its own daemon to stop charging.
+var chargeLimitFeature = tools.Feature{
+	Name: "Charge Limit",
+	Tools: []tools.Tool{
+		{Name: "bclm", Paths: []string{"/opt/homebrew/bin/bclm", "/usr/local/bin/bclm"}},
+		{Name: "batt", Paths: []string{"/opt/homebrew/bin/batt", "/usr/local/bin/batt"}},
+	},
+	Hint: "brew install bclm or batt",
+}
+
+// runPrivileged is replaced in tests
+var runPrivileged = sudo.Run
+
+// battLimitPattern finds the limit in `batt status` output
+var battLimitPattern = regexp.MustCompile(`(?i)limit[^0-9\n]*(\d+)\s*%`)
+
+type chargeLimitMsg struct {
+	capability tools.Capability
+	percent    int
+	err        error
+}
+
+type chargeLimitSetMsg struct {
+	percent int
+	err     error
+}
+
+// readChargeLimit asks the installed tool for the current limit
+func (m *Model) readChargeLimit() tea.Cmd {
+	m.chargeChecked = true
+	c := tools.Resolve(m.runner, chargeLimitFeature)
+	return func() tea.Msg {
+		if !c.Available() {
+			return chargeLimitMsg{capability: c}
+		}
+		args := []string{"read"}
+		if c.Tool == "batt" {
+			args = []string{"status"}
+		}
+		output, err := m.runner.CombinedOutput(exec.Command(c.Path, args...))
+		if err != nil {
+			return chargeLimitMsg{capability: c, err: fmt.Errorf("%s %s: %s", c.Tool, args[0], firstLine(output, err))}
+		}
+		percent, err := parseChargeLimit(c.Tool, string(output))
+		return chargeLimitMsg{capability: c, percent: percent, err: err}
+	}
+}
+
+// parseChargeLimit reads the limit from `bclm read` or `batt status`
+func parseChargeLimit(tool, output string) (int, error) {
+	text := strings.TrimSpace(output)
+	if tool == "batt" {
+		match := battLimitPattern.FindStringSubmatch(text)
+		if match == nil {
+			return 0, fmt.Errorf("no charge limit in batt status")
+		}
+		text = match[1]
+	}
+	percent, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected %s output %q", tool, text)
+	}
+	return percent, nil
+}
+
+// validateChargeLimit checks percent against what the tool supports. bclm
+// on Apple Silicon can only stop at 80% or not at all.
+func validateChargeLimit(tool, arch string, percent int) error {
+	switch {
+	case tool == "bclm" && arch == "arm64":
+		if percent != 80 && percent != 100 {
+			return fmt.Errorf("bclm supports only 80 or 100 on Apple Silicon")
+		}
+	case tool == "bclm":
+		if percent < 20 || percent > 100 {
+			return fmt.Errorf("limit must be between 20 and 100")
+		}
+	default:
+		if percent < 10 || percent > 100 {
+			return fmt.Errorf("limit must be between 10 and 100")
+		}
+	}
+	return nil
+}
+
+// setChargeLimit applies percent. bclm needs root and a launch daemon
+// (persist) to keep the limit after a reboot; 100 removes the daemon.
+func (m *Model) setChargeLimit(percent int) tea.Cmd {
+	c := m.chargeCapability
+	return func() tea.Msg {
+		if c.Tool == "batt" {
+			output, err := m.runner.CombinedOutput(exec.Command(c.Path, "limit", strconv.Itoa(percent)))
+			if err != nil {
+				return chargeLimitSetMsg{percent: percent, err: fmt.Errorf("%s", firstLine(output, err))}
+			}
+			return chargeLimitSetMsg{percent: percent}
+		}
+
+		if _, err := runPrivileged(c.Path, "write", strconv.Itoa(percent)); err != nil {
+			return chargeLimitSetMsg{percent: percent, err: err}
+		}
+		persist := "persist"
+		if percent == 100 {
+			persist = "unpersist"
+		}
+		if _, err := runPrivileged(c.Path, persist); err != nil {
+			return chargeLimitSetMsg{percent: percent, err: fmt.Errorf("limit set until reboot; %s failed: %v", persist, err)}
+		}
+		return chargeLimitSetMsg{percent: percent}
+	}
+}
+
+// firstLine is the first line of a command's output, or err when it
+// printed nothing
+func firstLine(output []byte, err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if line == "" {
+		return err.Error()
+	}
+	return line
+}
+
+// updateChargeLimit handles the results of reading and setting the limit
+func (m *Model) updateChargeLimit(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case chargeLimitMsg:
+		m.chargeCapability = msg.capability
+		m.chargeLimit = msg.percent
+		m.chargeErr = msg.err
+	case chargeLimitSetMsg:
+		m.chargeBusy = false
+		if msg.err != nil {
+			m.chargeMessage = "✗ " + msg.err.Error()
+			return tea.Batch(components.StatusToast(m.chargeMessage), m.readChargeLimit())
+		}
+		m.chargeLimit = msg.percent
+		m.chargeErr = nil
+		m.chargeMessage = fmt.Sprintf("✓ Charge limit set to %d%%", msg.percent)
+		if msg.percent == 100 {
+			m.chargeMessage = "✓ Charge limit removed"
+		}
+		return components.StatusToast(m.chargeMessage)
+	}
+	return nil
+}
+
+// handleChargeKey opens the limit input, or edits it while open
+func (m *Model) handleChargeKey(msg tea.KeyMsg) tea.Cmd {
+	if !m.chargeEditing {
+		if m.chargeBusy || !m.chargeCapability.Available() {
+			return nil
+		}
+		m.chargeEditing = true
+		m.chargeInput = ""
+		m.chargeMessage = ""
+		return nil
+	}
+
+	switch key := msg.String(); key {
+	case "esc":
+		m.chargeEditing = false
+	case "backspace":
+		if m.chargeInput != "" {
+			m.chargeInput = m.chargeInput[:len(m.chargeInput)-1]
+		}
+	case "enter":
+		percent, err := strconv.Atoi(m.chargeInput)
+		if err == nil {
+			err = validateChargeLimit(m.chargeCapability.Tool, m.info.Architecture, percent)
+		}
+		if err != nil {
+			m.chargeMessage = "✗ " + err.Error()
+			return nil
+		}
+		m.chargeEditing = false
+		m.chargeBusy = true
+		m.chargeMessage = fmt.Sprintf("Setting charge limit to %d%%...", percent)
+		return m.setChargeLimit(percent)
+	default:
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && len(m.chargeInput) < 3 {
+			m.chargeInput += key
+		}
+	}
+	return nil
+}
+
+// renderChargeLimit is the charge limit entry of the Maintenance quick
+// actions, with the input and the last result below it
+func (m *Model) renderChargeLimit() string {
+	actionStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	c := m.chargeCapability
+	line := actionStyle.Render("[C]") + " Battery Charge Limit"
+	switch {
+	case !m.chargeChecked || c.Feature == "":
+		line += mutedStyle.Render(" (checking...)")
+	case !c.Available():
+		line += mutedStyle.Render(" unavailable: " + c.Reason)
+	case m.chargeErr != nil:
+		line += lipgloss.NewStyle().Foreground(components.ColorError).Render(": " + m.chargeErr.Error())
+	case m.chargeLimit >= 100:
+		line += fmt.Sprintf(": none (%s)", c.Tool)
+	default:
+		line += fmt.Sprintf(": %d%% (%s)", m.chargeLimit, c.Tool)
+	}
+
+	lines := []string{line}
+	if m.chargeEditing {
+		hint := "Enter to apply, Esc to cancel"
+		if c.Tool == "bclm" && m.info.Architecture == "arm64" {
+			hint = "80 or 100 on Apple Silicon • " + hint
+		}
+		input := lipgloss.NewStyle().Foreground(components.ColorBright).Render(m.chargeInput + "█")
+		lines = append(lines, "    New limit: "+input+"%  "+mutedStyle.Render(hint))
+	}
+	if m.chargeMessage != "" {
+		lines = append(lines, "    "+m.chargeMessage)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,98 @@
+package system
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseChargeLimit(t *testing.T) {
+	tests := []struct {
+		tool, output string
+		want         int
+	}{
+		{"bclm", "80\n", 80},
+		{"batt", "Charging status:\n  Allow charging: false\n  Upper limit: 75%\n", 75},
+	}
+	for _, tt := range tests {
+		if got, err := parseChargeLimit(tt.tool, tt.output); err != nil || got != tt.want {
+			t.Errorf("parseChargeLimit(%s) = %d, %v; want %d", tt.tool, got, err, tt.want)
+		}
+	}
+	if _, err := parseChargeLimit("batt", "daemon not running"); err == nil {
+		t.Error("batt output without a limit should fail")
+	}
+}
+
+func TestValidateChargeLimit(t *testing.T) {
+	if err := validateChargeLimit("bclm", "arm64", 60); err == nil {
+		t.Error("bclm on Apple Silicon accepted 60")
+	}
+	if err := validateChargeLimit("bclm", "arm64", 80); err != nil {
+		t.Error(err)
+	}
+	if err := validateChargeLimit("bclm", "amd64", 60); err != nil {
+		t.Error(err)
+	}
+	if err := validateChargeLimit("batt", "arm64", 101); err == nil {
+		t.Error("batt accepted 101")
+	}
+}
+
+func TestSetChargeLimitWithBclm(t *testing.T) {
+	var calls []string
+	runPrivileged = func(command string, args ...string) (string, error) {
+		calls = append(calls, command+" "+strings.Join(args, " "))
+		return "", nil
+	}
+	t.Cleanup(func() { runPrivileged = sudo.Run })
+
+	m := New(nil)
+	m.runner = runner.NewFake().Set("/usr/bin/bclm read", "100", nil)
+	m.info.Architecture = "arm64"
+	m.activeTab = 3
+	m.Update(m.readChargeLimit()())
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if !m.HasOpenModal() {
+		t.Fatal("C should open the limit input")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("7")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !strings.Contains(m.chargeMessage, "only 80 or 100") {
+		t.Fatalf("70 should be rejected on Apple Silicon, message %q", m.chargeMessage)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("8")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+
+	want := []string{"/usr/bin/bclm write 80", "/usr/bin/bclm persist"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("privileged calls = %q, want %q", calls, want)
+	}
+	if m.chargeLimit != 80 || m.HasOpenModal() {
+		t.Errorf("limit = %d, input open = %v", m.chargeLimit, m.HasOpenModal())
+	}
+}
+
+func TestChargeLimitUnavailable(t *testing.T) {
+	m := New(nil)
+	m.runner = runner.NewFake().Missing("bclm", "batt")
+	m.activeTab = 3
+	m.Update(m.readChargeLimit()())
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.HasOpenModal() {
+		t.Error("the limit input opened without bclm or batt")
+	}
+	if line := m.renderChargeLimit(); !strings.Contains(line, "bclm or batt not found") {
+		t.Errorf("line doesn't explain the missing tool: %q", line)
+	}
+}
@@ -0,0 +1,406 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// captureTab is the index of the Capture tab
+const captureTab = 6
+
+// screencaptureDomain holds the screenshot preferences
+const screencaptureDomain = "com.apple.screencapture"
+
+// captureSetting is a screenshot preference shown on the Capture tab
+type captureSetting struct {
+	Label string
+	Key   defaults.Key
+	// Fallback is what macOS uses while the key isn't set
+	Fallback string
+	// Choices are the values Enter cycles through; without any the value
+	// is typed, like the save location
+	Choices []string
+	// Inverted shows a disable-* key the other way round, so "on" always
+	// means the feature is on
+	Inverted bool
+	Note     string
+}
+
+var onOff = []string{"true", "false"}
+
+var captureSettings = []captureSetting{
+	{Label: "Save location", Key: defaults.Key{Domain: screencaptureDomain, Name: "location", Type: defaults.String}, Fallback: "~/Desktop"},
+	{Label: "Format", Key: defaults.Key{Domain: screencaptureDomain, Name: "type", Type: defaults.String}, Fallback: "png",
+		Choices: []string{"png", "jpg", "heic", "pdf", "tiff", "gif"}},
+	{Label: "Window shadow", Key: defaults.Key{Domain: screencaptureDomain, Name: "disable-shadow", Type: defaults.Bool}, Fallback: "false",
+		Choices: onOff, Inverted: true},
+	{Label: "Floating thumbnail", Key: defaults.Key{Domain: screencaptureDomain, Name: "show-thumbnail", Type: defaults.Bool}, Fallback: "true",
+		Choices: onOff, Note: "off saves screenshots without the 5 second delay"},
+}
+
+// recordLengths are the timed recording lengths Enter cycles through
+var recordLengths = []time.Duration{10 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute, 5 * time.Minute}
+
+type captureMsg struct {
+	values []defaults.Value
+	err    error
+}
+
+type captureAppliedMsg struct {
+	note string
+	err  error
+}
+
+type recordingDoneMsg struct {
+	path string
+	err  error
+}
+
+// scanCapture reads the screenshot preferences
+func (m *Model) scanCapture() tea.Cmd {
+	m.captureChecked = true
+	return func() tea.Msg {
+		values := make([]defaults.Value, len(captureSettings))
+		for i, s := range captureSettings {
+			value, err := defaults.Read(m.runner, s.Key)
+			if err != nil {
+				return captureMsg{err: err}
+			}
+			values[i] = value
+		}
+		return captureMsg{values: values}
+	}
+}
+
+// captureValue is the value shown for setting i: the pending edit, else
+// the current value, else what macOS uses by default
+func (m *Model) captureValue(i int) string {
+	if value, ok := m.capturePending[i]; ok {
+		return value
+	}
+	if i < len(m.captureValues) && m.captureValues[i].Set {
+		return m.captureValues[i].Text
+	}
+	return captureSettings[i].Fallback
+}
+
+// showCaptureValue formats a value for the tab
+func showCaptureValue(s captureSetting, value string) string {
+	if s.Key.Type != defaults.Bool {
+		return value
+	}
+	if (value == "true") != s.Inverted {
+		return "on"
+	}
+	return "off"
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// applyCapture writes the pending edits and restarts SystemUIServer, which
+// reads the preferences only when it starts
+func (m *Model) applyCapture() tea.Cmd {
+	pending := m.capturePending
+	m.captureBusy = true
+	return func() tea.Msg {
+		for i, s := range captureSettings {
+			value, ok := pending[i]
+			if !ok {
+				continue
+			}
+			if s.Key.Name == "location" {
+				value = expandHome(value)
+				if info, err := os.Stat(value); err != nil || !info.IsDir() {
+					return captureAppliedMsg{err: fmt.Errorf("%s is not a folder", value)}
+				}
+			}
+			if err := changes.WriteDefault(m.runner, m.Title(), s.Key, value, m.reloadSystemUI); err != nil {
+				return captureAppliedMsg{err: err}
+			}
+		}
+		if err := m.reloadSystemUI(); err != nil {
+			return captureAppliedMsg{err: err}
+		}
+		return captureAppliedMsg{note: fmt.Sprintf("✓ Applied %d screenshot setting(s)", len(pending))}
+	}
+}
+
+// revertCapture restores the preferences read when the tab first opened
+func (m *Model) revertCapture() tea.Cmd {
+	saved, current := m.captureSaved, m.captureValues
+	m.captureBusy = true
+	return func() tea.Msg {
+		for i, s := range captureSettings {
+			if saved[i] == current[i] {
+				continue
+			}
+			if err := defaults.Restore(m.runner, s.Key, saved[i]); err != nil {
+				return captureAppliedMsg{err: err}
+			}
+		}
+		if err := m.reloadSystemUI(); err != nil {
+			return captureAppliedMsg{err: err}
+		}
+		return captureAppliedMsg{note: "✓ Restored the screenshot settings"}
+	}
+}
+
+func (m *Model) reloadSystemUI() error {
+	output, err := m.runner.CombinedOutput(exec.Command("killall", "SystemUIServer"))
+	if err != nil {
+		return fmt.Errorf("restarting SystemUIServer: %s", firstLine(output, err))
+	}
+	return nil
+}
+
+// startRecording records the whole screen for the selected length into
+// the save location
+func (m *Model) startRecording() tea.Cmd {
+	length := recordLengths[m.recordLength]
+	now := time.Now()
+	name := "Screen Recording " + now.Format("2006-01-02 at 15.04.05") + ".mov"
+	path := filepath.Join(expandHome(m.captureValue(0)), name)
+	m.recordingUntil = now.Add(length)
+	m.captureMessage = ""
+	return func() tea.Msg {
+		seconds := fmt.Sprint(int(length.Seconds()))
+		output, err := m.runner.CombinedOutput(exec.Command("screencapture", "-v", "-V", seconds, path))
+		if err != nil {
+			return recordingDoneMsg{err: fmt.Errorf("screencapture: %s", firstLine(output, err))}
+		}
+		return recordingDoneMsg{path: path}
+	}
+}
+
+// recording reports whether a timed recording is running
+func (m *Model) recording() bool {
+	return !m.recordingUntil.IsZero()
+}
+
+// updateCapture applies reads, writes and finished recordings
+func (m *Model) updateCapture(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case captureMsg:
+		m.captureErr = msg.err
+		if msg.err != nil {
+			return nil
+		}
+		m.captureValues = msg.values
+		if m.captureSaved == nil {
+			m.captureSaved = msg.values
+		}
+	case captureAppliedMsg:
+		m.captureBusy = false
+		if msg.err != nil {
+			m.captureMessage = "✗ " + msg.err.Error()
+		} else {
+			m.captureMessage = msg.note
+			m.capturePending = nil
+		}
+		return tea.Batch(components.StatusToast(m.captureMessage), m.scanCapture())
+	case recordingDoneMsg:
+		m.recordingUntil = time.Time{}
+		m.captureMessage = "✓ Saved " + msg.path
+		if msg.err != nil {
+			m.captureMessage = "✗ " + msg.err.Error()
+		}
+		return components.ResultToast(m.captureMessage, palette.Key("7"))
+	}
+	return nil
+}
+
+// handleCaptureKey handles keys on the Capture tab and reports whether
+// the key was used
+func (m *Model) handleCaptureKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	if m.captureEditing {
+		return m.handleLocationKey(msg), true
+	}
+	if m.captureBusy || m.captureValues == nil {
+		return nil, key == "r" || key == "enter" || key == "a" || key == "u" || key == "v"
+	}
+
+	switch key {
+	case "r":
+		m.captureMessage = ""
+		return m.scanCapture(), true
+	case "enter", " ":
+		if m.captureCursor == len(captureSettings) {
+			m.recordLength = (m.recordLength + 1) % len(recordLengths)
+			break
+		}
+		s := captureSettings[m.captureCursor]
+		if len(s.Choices) == 0 {
+			m.captureEditing = true
+			m.captureInput = m.captureValue(m.captureCursor)
+			break
+		}
+		m.setPending(m.captureCursor, nextChoice(s.Choices, m.captureValue(m.captureCursor)))
+	case "a":
+		if len(m.capturePending) == 0 {
+			m.captureMessage = "No changes to apply"
+			break
+		}
+		m.captureMessage = "Applying..."
+		return m.applyCapture(), true
+	case "u":
+		if len(m.capturePending) > 0 {
+			m.capturePending = nil
+			m.captureMessage = "Discarded the changes not applied yet"
+			break
+		}
+		for i := range captureSettings {
+			if m.captureSaved[i] != m.captureValues[i] {
+				m.captureMessage = "Reverting..."
+				return m.revertCapture(), true
+			}
+		}
+		m.captureMessage = "Nothing to revert"
+	case "v":
+		if m.recording() {
+			break
+		}
+		return m.startRecording(), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// setPending stages value for setting i, dropping edits back to the
+// current value
+func (m *Model) setPending(i int, value string) {
+	delete(m.capturePending, i)
+	if value == m.captureValue(i) {
+		return
+	}
+	if m.capturePending == nil {
+		m.capturePending = make(map[int]string)
+	}
+	m.capturePending[i] = value
+}
+
+// nextChoice is the choice after current, wrapping around
+func nextChoice(choices []string, current string) string {
+	for i, choice := range choices {
+		if choice == current {
+			return choices[(i+1)%len(choices)]
+		}
+	}
+	return choices[0]
+}
+
+// handleLocationKey edits the save location
+func (m *Model) handleLocationKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.captureEditing = false
+	case tea.KeyEnter:
+		m.captureEditing = false
+		if path := strings.TrimSpace(m.captureInput); path != "" {
+			m.setPending(m.captureCursor, path)
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(m.captureInput); len(runes) > 0 {
+			m.captureInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.captureInput = ""
+	case tea.KeySpace:
+		m.captureInput += " "
+	case tea.KeyRunes:
+		m.captureInput += string(msg.Runes)
+	}
+	return nil
+}
+
+// renderCapture is the Capture tab
+func (m *Model) renderCapture() string {
+	style := lipgloss.NewStyle().Padding(1)
+	highlightStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	actionStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorBright).Bold(true)
+	pendingStyle := lipgloss.NewStyle().Foreground(components.ColorWarning).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(highlightStyle.Render("Screenshots") + "\n")
+	switch {
+	case m.captureErr != nil:
+		b.WriteString("  " + errorStyle.Render("✗ "+m.captureErr.Error()) + "\n")
+		return style.Render(b.String())
+	case m.captureValues == nil:
+		b.WriteString("  ⏳ Reading screenshot settings...\n")
+		return style.Render(b.String())
+	}
+
+	row := func(i int, label, value, note string) {
+		line := fmt.Sprintf("%-20s %s", label, value)
+		if i == m.captureCursor {
+			b.WriteString("▶ " + selectedStyle.Render(line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		if note != "" {
+			b.WriteString("  " + note)
+		}
+		b.WriteString("\n")
+	}
+	for i, s := range captureSettings {
+		value := showCaptureValue(s, m.captureValue(i))
+		if i == m.captureCursor && m.captureEditing {
+			value = lipgloss.NewStyle().Foreground(components.ColorBright).Render(m.captureInput + "█")
+		}
+		note := mutedStyle.Render(s.Note)
+		if _, ok := m.capturePending[i]; ok {
+			note = pendingStyle.Render("* not applied")
+		}
+		row(i, s.Label, value, note)
+	}
+
+	b.WriteString("\n" + highlightStyle.Render("Screen Recording") + "\n")
+	length := recordLengths[m.recordLength]
+	note := mutedStyle.Render("saved to the screenshot location")
+	if m.recording() {
+		left := max(time.Until(m.recordingUntil).Round(time.Second), 0)
+		note = errorStyle.Render(fmt.Sprintf("● Recording, %s left", left))
+	}
+	row(len(captureSettings), "Timed recording", length.String(), note)
+
+	b.WriteString("\n")
+	if m.captureEditing {
+		b.WriteString(mutedStyle.Render("Type a folder • Enter keep • Ctrl+U clear • Esc cancel") + "\n")
+	} else {
+		b.WriteString(actionStyle.Render("[Enter]") + " Change  " +
+			actionStyle.Render("[A]") + " Apply  " +
+			actionStyle.Render("[U]") + " Undo/revert  " +
+			actionStyle.Render("[V]") + " Record  " +
+			actionStyle.Render("[R]") + " Reread\n")
+	}
+	if m.captureMessage != "" {
+		b.WriteString(m.captureMessage + "\n")
+	}
+	return style.Render(b.String())
+}
@@ -0,0 +1,140 @@
+package system
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const notSet = "The domain/default pair of (com.apple.screencapture, type) does not exist"
+
+// captureDefaults answers the screenshot preference reads: a custom
+// location, no shadow and the other keys unset
+func captureDefaults(fake *runner.Fake) {
+	fake.Set("defaults read com.apple.screencapture location", "/Users/caio/Screenshots\n", nil).
+		Set("defaults read com.apple.screencapture type", notSet, errors.New("exit status 1")).
+		Set("defaults read com.apple.screencapture disable-shadow", "1\n", nil).
+		Set("defaults read com.apple.screencapture show-thumbnail", notSet, errors.New("exit status 1"))
+}
+
+func captureModel(t *testing.T) (*Model, *runner.Fake) {
+	t.Helper()
+	fake := runner.NewFake()
+	captureDefaults(fake)
+	m := New(nil)
+	m.runner = fake
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("7")})
+	if cmd == nil {
+		t.Fatal("opening the Capture tab should read the settings")
+	}
+	m.Update(cmd())
+	return m, fake
+}
+
+func key(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestCaptureApplyAndRevert(t *testing.T) {
+	m, fake := captureModel(t)
+	if got := m.renderCapture(); !strings.Contains(got, "Window shadow        off") || !strings.Contains(got, "Format               png") {
+		t.Fatalf("settings not shown as read:\n%s", got)
+	}
+
+	// Format png -> jpg, and the shadow back on
+	m.Update(events.NavDown)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(events.NavDown)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.capturePending) != 2 || !strings.Contains(m.renderCapture(), "* not applied") {
+		t.Fatalf("pending = %v", m.capturePending)
+	}
+
+	fake.Set("defaults write com.apple.screencapture type -string jpg", "", nil).
+		Set("defaults write com.apple.screencapture disable-shadow -bool false", "", nil).
+		Set("killall SystemUIServer", "", nil)
+	_, cmd := m.Update(key("a"))
+	m.Update(cmd())
+	if m.captureMessage != "✓ Applied 2 screenshot setting(s)" || len(m.capturePending) != 0 {
+		t.Fatalf("message = %q pending = %v", m.captureMessage, m.capturePending)
+	}
+
+	fake.Set("defaults read com.apple.screencapture type", "jpg\n", nil).
+		Set("defaults read com.apple.screencapture disable-shadow", "0\n", nil)
+	m.Update(m.scanCapture()())
+
+	// Revert writes the shadow back and deletes the type key, which wasn't set
+	fake.Set("defaults delete com.apple.screencapture type", "", nil).
+		Set("defaults write com.apple.screencapture disable-shadow -bool true", "", nil)
+	_, cmd = m.Update(key("u"))
+	if cmd == nil {
+		t.Fatal("u should revert the applied changes")
+	}
+	m.Update(cmd())
+	calls := fake.Calls()
+	reverted := strings.Join(calls[len(calls)-3:], "\n")
+	want := "defaults delete com.apple.screencapture type\ndefaults write com.apple.screencapture disable-shadow -bool true\nkillall SystemUIServer"
+	if reverted != want {
+		t.Errorf("revert ran:\n%s\nwant:\n%s", reverted, want)
+	}
+}
+
+func TestCaptureLocation(t *testing.T) {
+	m, _ := captureModel(t)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.HasOpenModal() || m.captureInput != "/Users/caio/Screenshots" {
+		t.Fatalf("editing = %v input = %q", m.captureEditing, m.captureInput)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	missing := filepath.Join(t.TempDir(), "missing")
+	m.Update(key(missing))
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.capturePending[0] != missing {
+		t.Fatalf("pending = %v", m.capturePending)
+	}
+
+	_, cmd := m.Update(key("a"))
+	m.Update(cmd())
+	if m.captureMessage != "✗ "+missing+" is not a folder" || len(m.capturePending) != 1 {
+		t.Errorf("message = %q, want the missing folder rejected and the edit kept", m.captureMessage)
+	}
+
+	m.Update(key("u"))
+	if len(m.capturePending) != 0 || m.captureMessage != "Discarded the changes not applied yet" {
+		t.Errorf("u should discard pending edits first: %q", m.captureMessage)
+	}
+}
+
+func TestTimedRecording(t *testing.T) {
+	m, fake := captureModel(t)
+	for range captureSettings {
+		m.Update(events.NavDown)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := recordLengths[m.recordLength].String(); got != "1m0s" {
+		t.Fatalf("length = %s, want Enter to step from 30s to 1m", got)
+	}
+
+	_, cmd := m.Update(key("v"))
+	if !m.recording() || !strings.Contains(m.renderCapture(), "● Recording") {
+		t.Fatal("the tab should show the recording countdown")
+	}
+	if _, again := m.Update(key("v")); again != nil {
+		t.Error("a second recording started while one was running")
+	}
+
+	msg := cmd()
+	call := fake.Calls()[len(fake.Calls())-1]
+	if !strings.HasPrefix(call, "screencapture -v -V 60 /Users/caio/Screenshots/Screen Recording ") || !strings.HasSuffix(call, ".mov") {
+		t.Errorf("ran %q", call)
+	}
+	m.Update(msg)
+	if m.recording() || !strings.HasPrefix(m.captureMessage, "✗ screencapture") {
+		t.Errorf("message = %q, want the failure reported", m.captureMessage)
+	}
+}
@@ -0,0 +1,137 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// timeServer is the server the clock is compared with and synced to
+const timeServer = "time.apple.com"
+
+// Clock offsets past these break things: a second already upsets some
+// signed requests, and TOTP codes and token expiry fail by 30 seconds
+const (
+	clockWarnOffset  = time.Second
+	clockErrorOffset = 30 * time.Second
+)
+
+// sntpOffsetPattern finds the offset in `sntp` output, e.g.
+// "+0.012345 +/- 0.023456 time.apple.com 17.253.34.253"
+var sntpOffsetPattern = regexp.MustCompile(`([+-]\d+(?:\.\d+)?) \+/- `)
+
+type clockMsg struct {
+	networkTime string // "On", "Off", or "" when systemsetup needs admin
+	offset      time.Duration
+	err         error // sntp failed, e.g. offline
+}
+
+type clockSyncedMsg struct {
+	err error
+}
+
+// checkClock compares the clock with timeServer and reads whether macOS
+// sets the time automatically
+func (m *Model) checkClock() tea.Cmd {
+	m.clockChecked = true
+	m.clockBusy = true
+	r := m.runner
+	return func() tea.Msg {
+		var msg clockMsg
+		out, err := r.CombinedOutput(exec.Command("systemsetup", "-getusingnetworktime"))
+		if text := strings.TrimSpace(string(out)); err == nil && strings.HasPrefix(text, "Network Time:") {
+			msg.networkTime = strings.TrimSpace(strings.TrimPrefix(text, "Network Time:"))
+		}
+
+		out, err = r.CombinedOutput(exec.Command("sntp", timeServer))
+		if err != nil {
+			msg.err = fmt.Errorf("sntp %s: %s", timeServer, firstLine(out, err))
+			return msg
+		}
+		match := sntpOffsetPattern.FindStringSubmatch(string(out))
+		if match == nil {
+			msg.err = errors.New("no offset in sntp output")
+			return msg
+		}
+		seconds, _ := strconv.ParseFloat(match[1], 64)
+		msg.offset = time.Duration(seconds * float64(time.Second))
+		return msg
+	}
+}
+
+// syncClock turns network time on if it's off and steps the clock to
+// timeServer; both need the administrator password
+func (m *Model) syncClock() tea.Cmd {
+	if m.clockBusy {
+		return nil
+	}
+	m.clockBusy = true
+	m.clockMessage = ""
+	enable := m.clockNetworkTime == "Off"
+	return func() tea.Msg {
+		if enable {
+			if _, err := runPrivileged("systemsetup", "-setusingnetworktime", "on"); err != nil {
+				return clockSyncedMsg{err: fmt.Errorf("turning on network time: %w", err)}
+			}
+		}
+		if _, err := runPrivileged("sntp", "-sS", timeServer); err != nil {
+			return clockSyncedMsg{err: err}
+		}
+		return clockSyncedMsg{}
+	}
+}
+
+func (m *Model) updateClock(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case clockMsg:
+		m.clockBusy = false
+		m.clockNetworkTime = msg.networkTime
+		m.clockOffset = msg.offset
+		m.clockErr = msg.err
+	case clockSyncedMsg:
+		m.clockBusy = false
+		if msg.err != nil {
+			m.clockMessage = "✗ " + msg.err.Error()
+			return components.StatusToast(m.clockMessage)
+		}
+		m.clockMessage = "✓ Clock synced with " + timeServer
+		return tea.Batch(m.checkClock(), components.StatusToast(m.clockMessage))
+	}
+	return nil
+}
+
+// clockStatus describes the clock for the Recommended Maintenance list
+func (m *Model) clockStatus() (string, lipgloss.AdaptiveColor) {
+	switch {
+	case !m.clockChecked, m.clockBusy && m.clockOffset == 0 && m.clockErr == nil:
+		return "Checking against " + timeServer + "...", components.ColorSubtle
+	case m.clockErr != nil:
+		return m.clockErr.Error(), components.ColorSubtle
+	}
+
+	drift := time.Duration(math.Abs(float64(m.clockOffset)))
+	status := fmt.Sprintf("%+.3fs from %s", m.clockOffset.Seconds(), timeServer)
+	color := components.ColorSuccess
+	switch {
+	case drift >= clockErrorOffset:
+		status += " • press [T] to resync"
+		color = components.ColorError
+	case drift >= clockWarnOffset:
+		status += " • press [T] to resync"
+		color = components.ColorWarning
+	}
+	if m.clockNetworkTime == "Off" {
+		status += " • automatic time is off"
+		color = components.ColorWarning
+	}
+	return status, color
+}
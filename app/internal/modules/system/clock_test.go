@@ -0,0 +1,60 @@
+package system
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestClockDriftAndResync(t *testing.T) {
+	var calls []string
+	runPrivileged = func(command string, args ...string) (string, error) {
+		calls = append(calls, command+" "+strings.Join(args, " "))
+		return "", nil
+	}
+	t.Cleanup(func() { runPrivileged = sudo.Run })
+
+	m := New(nil)
+	m.runner = runner.NewFake().
+		Set("systemsetup -getusingnetworktime", "Network Time: Off\n", nil).
+		Set("sntp time.apple.com", "-42.118012 +/- 0.021973 time.apple.com 17.253.34.125\n", nil)
+	m.activeTab = 3
+	m.Update(m.checkClock()())
+
+	if m.clockOffset != -42118012*time.Microsecond {
+		t.Fatalf("offset = %v", m.clockOffset)
+	}
+	status, color := m.clockStatus()
+	if !strings.Contains(status, "-42.118s") || !strings.Contains(status, "automatic time is off") {
+		t.Errorf("status = %q", status)
+	}
+	if color == components.ColorSuccess {
+		t.Error("a 42 second drift shows as healthy")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m.Update(cmd())
+	want := []string{"systemsetup -setusingnetworktime on", "sntp -sS time.apple.com"}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran %q, want %q", calls, want)
+	}
+	if !strings.HasPrefix(m.clockMessage, "✓") {
+		t.Errorf("message = %q", m.clockMessage)
+	}
+}
+
+func TestClockCheckOffline(t *testing.T) {
+	m := New(nil)
+	m.runner = runner.NewFake().
+		Set("systemsetup -getusingnetworktime", "You need administrator access to run this tool... exiting!\n", nil)
+	m.Update(m.checkClock()())
+
+	if m.clockErr == nil || m.clockNetworkTime != "" {
+		t.Errorf("err = %v, network time = %q", m.clockErr, m.clockNetworkTime)
+	}
+}
@@ -0,0 +1,809 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// displaysTab is the index of the Displays tab
+const displaysTab = 4
+
+// displayplacer arranges displays from the command line. It isn't in
+// homebrew-core, so the guided install taps its author's repository.
+var (
+	displayplacerFeature = tools.Feature{
+		Name:  "Display Arrangement",
+		Tools: []tools.Tool{{Name: "displayplacer", Paths: []string{"/opt/homebrew/bin/displayplacer", "/usr/local/bin/displayplacer"}}},
+		Hint:  "press I to install it with Homebrew",
+	}
+	brewFeature = tools.Feature{
+		Name:  "Homebrew",
+		Tools: []tools.Tool{{Name: "brew", Paths: []string{"/opt/homebrew/bin/brew", "/usr/local/bin/brew"}}},
+		Hint:  "install it from brew.sh",
+	}
+)
+
+// displayplacerFormula is what the guided install asks Homebrew for
+const displayplacerFormula = "jakehilborn/jakehilborn/displayplacer"
+
+// displayLayoutsFile holds the saved layouts in the config directory
+const displayLayoutsFile = "display_layouts.json"
+
+// displayLayoutNameMax limits the length of layout names
+const displayLayoutNameMax = 24
+
+// Display is a screen as `displayplacer list` reports it
+type Display struct {
+	ID         string // persistent screen id
+	Type       string // e.g. "MacBook built in screen" or "27 inch external screen"
+	Resolution string
+	Hertz      string
+	Scaling    string
+	Origin     string
+	Rotation   string
+	Main       bool
+	Enabled    bool
+	Modes      []DisplayMode
+}
+
+// DisplayMode is one resolution a display supports
+type DisplayMode struct {
+	Number     int
+	Resolution string
+	Hertz      string
+	ColorDepth string
+	Scaling    string
+	Current    bool
+}
+
+// String describes the mode as it's listed in the picker
+func (d DisplayMode) String() string {
+	s := d.Resolution
+	if d.Hertz != "" {
+		s += " @ " + d.Hertz + "Hz"
+	}
+	if d.Scaling == "on" {
+		s += " (HiDPI)"
+	}
+	return s
+}
+
+// DisplayLayout is a saved arrangement, e.g. "docked" or "undocked"
+type DisplayLayout struct {
+	Name  string    `json:"name"`
+	Args  []string  `json:"args"` // displayplacer arguments, one per screen
+	Saved time.Time `json:"saved"`
+}
+
+type displaysMsg struct {
+	capability  tools.Capability
+	displays    []Display
+	arrangement []string
+	layouts     []DisplayLayout
+	err         error
+}
+
+type displayActionMsg struct {
+	note    string
+	err     error
+	rescan  bool
+	saved   bool            // layouts were written
+	layouts []DisplayLayout // the saved layouts, when saved
+}
+
+var (
+	displayModePattern = regexp.MustCompile(`^mode (\d+): (.*)$`)
+	quotedArgPattern   = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// scanDisplays lists the displays, the current arrangement and the saved
+// layouts
+func (m *Model) scanDisplays() tea.Cmd {
+	m.displaysChecked = true
+	m.displaysLoading = true
+	c := tools.Resolve(m.runner, displayplacerFeature)
+	layoutsPath := m.layoutsPath
+
+	return func() tea.Msg {
+		layouts, err := loadDisplayLayouts(layoutsPath)
+		if err != nil {
+			return displaysMsg{capability: c, err: fmt.Errorf("failed to read saved layouts: %w", err)}
+		}
+		if !c.Available() {
+			return displaysMsg{capability: c, layouts: layouts}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		output, err := m.runner.Output(exec.CommandContext(ctx, c.Path, "list"))
+		if err != nil {
+			return displaysMsg{capability: c, layouts: layouts, err: fmt.Errorf("displayplacer list: %w", err)}
+		}
+		displays, arrangement := parseDisplayplacerList(string(output))
+		return displaysMsg{capability: c, displays: displays, arrangement: arrangement, layouts: layouts}
+	}
+}
+
+// parseDisplayplacerList reads the screens and the command that restores
+// the current arrangement from `displayplacer list`
+func parseDisplayplacerList(output string) ([]Display, []string) {
+	var displays []Display
+	var arrangement []string
+	var current *Display
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "displayplacer \"") {
+			arrangement = nil
+			for _, match := range quotedArgPattern.FindAllStringSubmatch(line, -1) {
+				arrangement = append(arrangement, match[1])
+			}
+			continue
+		}
+		if match := displayModePattern.FindStringSubmatch(line); match != nil && current != nil {
+			mode := parseDisplayMode(match[2])
+			mode.Number, _ = strconv.Atoi(match[1])
+			current.Modes = append(current.Modes, mode)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if key == "Persistent screen id" {
+			displays = append(displays, Display{ID: value})
+			current = &displays[len(displays)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		switch key {
+		case "Type":
+			current.Type = value
+		case "Resolution":
+			current.Resolution = value
+		case "Hertz":
+			current.Hertz = value
+		case "Scaling":
+			current.Scaling = value
+		case "Origin":
+			origin, note, _ := strings.Cut(value, " - ")
+			current.Origin = origin
+			current.Main = strings.Contains(note, "main display")
+		case "Rotation":
+			current.Rotation, _, _ = strings.Cut(value, " ")
+		case "Enabled":
+			current.Enabled = value == "true"
+		}
+	}
+	return displays, arrangement
+}
+
+// parseDisplayMode reads "res:2560x1440 hz:60 color_depth:8 scaling:on
+// <-- current mode"
+func parseDisplayMode(text string) DisplayMode {
+	var mode DisplayMode
+	text, current := strings.CutSuffix(text, "<-- current mode")
+	mode.Current = current
+	for _, field := range strings.Fields(text) {
+		key, value, _ := strings.Cut(field, ":")
+		switch key {
+		case "res":
+			mode.Resolution = value
+		case "hz":
+			mode.Hertz = value
+		case "color_depth":
+			mode.ColorDepth = value
+		case "scaling":
+			mode.Scaling = value
+		}
+	}
+	return mode
+}
+
+// argID returns the screen ids of a displayplacer argument; mirrored
+// screens share one argument as "id:A+B"
+func argID(arg string) []string {
+	for _, field := range strings.Fields(arg) {
+		if ids, ok := strings.CutPrefix(field, "id:"); ok {
+			return strings.Split(ids, "+")
+		}
+	}
+	return nil
+}
+
+// setArgFields replaces or adds key:value fields of a displayplacer
+// argument; an empty value removes the field
+func setArgFields(arg string, values map[string]string) string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(arg) {
+		key, _, _ := strings.Cut(field, ":")
+		value, ok := values[key]
+		switch {
+		case !ok:
+			fields = append(fields, field)
+		case value != "":
+			fields = append(fields, key+":"+value)
+		}
+		seen[key] = true
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !seen[key] && values[key] != "" {
+			fields = append(fields, key+":"+values[key])
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// mirrored reports whether any screens in the arrangement mirror each other
+func mirrored(arrangement []string) bool {
+	for _, arg := range arrangement {
+		if len(argID(arg)) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// modeArgs switches the screen id to mode, keeping its place in the
+// arrangement
+func modeArgs(arrangement []string, id string, mode DisplayMode) ([]string, error) {
+	args := append([]string(nil), arrangement...)
+	for i, arg := range args {
+		for _, argID := range argID(arg) {
+			if argID != id {
+				continue
+			}
+			args[i] = setArgFields(arg, map[string]string{
+				"res":         mode.Resolution,
+				"hz":          mode.Hertz,
+				"color_depth": mode.ColorDepth,
+				"scaling":     mode.Scaling,
+				"mode":        "",
+			})
+			return args, nil
+		}
+	}
+	return nil, fmt.Errorf("display %s is not in the current arrangement", id)
+}
+
+// mirrorArgs toggles mirroring. Mirroring puts every screen on the main
+// one; unmirroring lays the screens out left to right starting with it.
+func mirrorArgs(displays []Display, arrangement []string) ([]string, error) {
+	if len(displays) < 2 {
+		return nil, errors.New("mirroring needs a second display")
+	}
+	if len(arrangement) == 0 {
+		return nil, errors.New("displayplacer reported no arrangement")
+	}
+
+	if !mirrored(arrangement) {
+		main := arrangement[0]
+		for _, arg := range arrangement {
+			if strings.Contains(arg, "origin:(0,0)") {
+				main = arg
+			}
+		}
+		var ids []string
+		for _, arg := range arrangement {
+			ids = append(ids, argID(arg)...)
+		}
+		mainIDs := argID(main)
+		for _, id := range ids {
+			if id != mainIDs[0] {
+				mainIDs = append(mainIDs, id)
+			}
+		}
+		return []string{setArgFields(main, map[string]string{"id": strings.Join(mainIDs, "+")})}, nil
+	}
+
+	var args []string
+	x := 0
+	for _, arg := range arrangement {
+		for i, id := range argID(arg) {
+			next := setArgFields(arg, map[string]string{"id": id, "origin": fmt.Sprintf("(%d,0)", x)})
+			if i > 0 {
+				// The mirror follows the main screen; give it its own mode back
+				d := findDisplay(displays, id)
+				if d == nil || d.Resolution == "" {
+					return nil, fmt.Errorf("no resolution known for display %s", id)
+				}
+				next = fmt.Sprintf("id:%s res:%s enabled:true origin:(%d,0) degree:0", id, d.Resolution, x)
+			}
+			args = append(args, next)
+
+			width := resolutionWidth(next)
+			if width == 0 {
+				return nil, fmt.Errorf("no resolution known for display %s", id)
+			}
+			x += width
+		}
+	}
+	return args, nil
+}
+
+func findDisplay(displays []Display, id string) *Display {
+	for i := range displays {
+		if displays[i].ID == id {
+			return &displays[i]
+		}
+	}
+	return nil
+}
+
+// resolutionWidth is the width in the res: field of a displayplacer argument
+func resolutionWidth(arg string) int {
+	for _, field := range strings.Fields(arg) {
+		if res, ok := strings.CutPrefix(field, "res:"); ok {
+			width, _, _ := strings.Cut(res, "x")
+			n, _ := strconv.Atoi(width)
+			return n
+		}
+	}
+	return 0
+}
+
+// runDisplayplacer applies args and rescans
+func (m *Model) runDisplayplacer(args []string, note string) tea.Cmd {
+	path := m.displayCapability.Path
+	m.displaysBusy = true
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		output, err := m.runner.CombinedOutput(exec.CommandContext(ctx, path, args...))
+		if err != nil {
+			return displayActionMsg{err: fmt.Errorf("displayplacer: %s", firstLine(output, err)), rescan: true}
+		}
+		return displayActionMsg{note: note, rescan: true}
+	}
+}
+
+// detectDisplays declares user activity, which wakes sleeping displays and
+// makes macOS probe its ports again, then rescans. It works without
+// displayplacer.
+func (m *Model) detectDisplays() tea.Cmd {
+	m.displaysBusy = true
+	return func() tea.Msg {
+		if output, err := m.runner.CombinedOutput(exec.Command("caffeinate", "-u", "-t", "2")); err != nil {
+			return displayActionMsg{err: fmt.Errorf("caffeinate: %s", firstLine(output, err)), rescan: true}
+		}
+		return displayActionMsg{note: "✓ Asked macOS to detect displays", rescan: true}
+	}
+}
+
+// installDisplayplacer installs displayplacer with Homebrew
+func (m *Model) installDisplayplacer() tea.Cmd {
+	brew := tools.Resolve(m.runner, brewFeature)
+	if !brew.Available() {
+		m.displaysMessage = "✗ Installing displayplacer needs Homebrew: " + brew.Reason
+		return nil
+	}
+	m.displaysBusy = true
+	m.displaysMessage = "Installing displayplacer with Homebrew..."
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		output, err := m.runner.CombinedOutput(exec.CommandContext(ctx, brew.Path, "install", displayplacerFormula))
+		if err != nil {
+			return displayActionMsg{err: fmt.Errorf("brew install displayplacer: %s", firstLine(output, err))}
+		}
+		return displayActionMsg{note: "✓ Installed displayplacer", rescan: true}
+	}
+}
+
+// saveDisplayLayout saves the current arrangement as name, replacing a
+// layout of the same name
+func (m *Model) saveDisplayLayout(name string) tea.Cmd {
+	layout := DisplayLayout{Name: name, Args: m.arrangement, Saved: time.Now()}
+	layouts := append([]DisplayLayout(nil), m.displayLayouts...)
+	replaced := false
+	for i := range layouts {
+		if layouts[i].Name == name {
+			layouts[i] = layout
+			replaced = true
+		}
+	}
+	if !replaced {
+		layouts = append(layouts, layout)
+	}
+	return m.writeDisplayLayouts(layouts, fmt.Sprintf("✓ Saved layout %q", name))
+}
+
+// deleteDisplayLayout removes the layout at index i
+func (m *Model) deleteDisplayLayout(i int) tea.Cmd {
+	name := m.displayLayouts[i].Name
+	layouts := append([]DisplayLayout(nil), m.displayLayouts[:i]...)
+	layouts = append(layouts, m.displayLayouts[i+1:]...)
+	return m.writeDisplayLayouts(layouts, fmt.Sprintf("✓ Deleted layout %q", name))
+}
+
+func (m *Model) writeDisplayLayouts(layouts []DisplayLayout, note string) tea.Cmd {
+	path := m.layoutsPath
+	return func() tea.Msg {
+		if err := saveDisplayLayouts(path, layouts); err != nil {
+			return displayActionMsg{err: fmt.Errorf("failed to save layouts: %w", err)}
+		}
+		return displayActionMsg{note: note, saved: true, layouts: layouts}
+	}
+}
+
+// loadDisplayLayouts reads the saved layouts. A missing file, or no path
+// at all, means none are saved.
+func loadDisplayLayouts(path string) ([]DisplayLayout, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var layouts []DisplayLayout
+	if err := json.Unmarshal(data, &layouts); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return layouts, nil
+}
+
+func saveDisplayLayouts(path string, layouts []DisplayLayout) error {
+	if path == "" {
+		return errors.New("no config directory")
+	}
+	data, err := json.MarshalIndent(layouts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// currentLayout returns the saved layout matching the arrangement, if any
+func (m *Model) currentLayout() string {
+	for _, layout := range m.displayLayouts {
+		if strings.Join(layout.Args, "\n") == strings.Join(m.arrangement, "\n") {
+			return layout.Name
+		}
+	}
+	return ""
+}
+
+// updateDisplays applies scan and action results
+func (m *Model) updateDisplays(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case displaysMsg:
+		m.displaysLoading = false
+		m.displayCapability = msg.capability
+		m.displays = msg.displays
+		m.arrangement = msg.arrangement
+		m.displayLayouts = msg.layouts
+		m.displaysErr = msg.err
+		if m.displayCursor >= len(m.displays)+len(m.displayLayouts) {
+			m.displayCursor = 0
+		}
+	case displayActionMsg:
+		m.displaysBusy = false
+		if msg.saved {
+			m.displayLayouts = msg.layouts
+			m.displayCursor = min(m.displayCursor, max(0, len(m.displays)+len(m.displayLayouts)-1))
+		}
+		m.displaysMessage = msg.note
+		if msg.err != nil {
+			m.displaysMessage = "✗ " + msg.err.Error()
+		}
+		cmds := []tea.Cmd{components.StatusToast(m.displaysMessage)}
+		if msg.rescan {
+			cmds = append(cmds, m.scanDisplays())
+		}
+		return tea.Batch(cmds...)
+	}
+	return nil
+}
+
+// handleDisplayKey handles keys on the Displays tab and reports whether
+// the key was used
+func (m *Model) handleDisplayKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	switch {
+	case m.layoutNaming:
+		return m.handleLayoutNameKey(msg), true
+	case m.modePicking:
+		return m.handleModeKey(key), true
+	case m.displaysBusy || m.displaysLoading:
+		return nil, key == "r"
+	}
+
+	available := m.displayCapability.Available()
+	rows := len(m.displays) + len(m.displayLayouts)
+	switch key {
+	case "r":
+		m.displaysMessage = ""
+		return m.scanDisplays(), true
+	case "d":
+		m.displaysMessage = "Detecting displays..."
+		return m.detectDisplays(), true
+	case "i":
+		if !available {
+			return m.installDisplayplacer(), true
+		}
+	case "enter":
+		if !available || rows == 0 {
+			break
+		}
+		if m.displayCursor < len(m.displays) {
+			display := m.displays[m.displayCursor]
+			if len(display.Modes) == 0 {
+				m.displaysMessage = "✗ displayplacer listed no modes for this display"
+				break
+			}
+			m.modePicking = true
+			m.modeCursor = 0
+			for i, mode := range display.Modes {
+				if mode.Current {
+					m.modeCursor = i
+				}
+			}
+			break
+		}
+		layout := m.displayLayouts[m.displayCursor-len(m.displays)]
+		m.displaysMessage = fmt.Sprintf("Applying layout %q...", layout.Name)
+		return m.runDisplayplacer(layout.Args, fmt.Sprintf("✓ Applied layout %q", layout.Name)), true
+	case "m":
+		if !available {
+			break
+		}
+		args, err := mirrorArgs(m.displays, m.arrangement)
+		if err != nil {
+			m.displaysMessage = "✗ " + err.Error()
+			break
+		}
+		note := "✓ Mirroring on"
+		if mirrored(m.arrangement) {
+			note = "✓ Mirroring off"
+		}
+		return m.runDisplayplacer(args, note), true
+	case "s":
+		if !available || len(m.arrangement) == 0 {
+			break
+		}
+		m.layoutNaming = true
+		m.layoutName = m.currentLayout()
+		if m.layoutName == "" {
+			m.layoutName = "docked"
+			if len(m.displays) == 1 {
+				m.layoutName = "undocked"
+			}
+		}
+	case "x":
+		if i := m.displayCursor - len(m.displays); i >= 0 && i < len(m.displayLayouts) {
+			return m.deleteDisplayLayout(i), true
+		}
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// handleModeKey moves through and applies the selected display's modes
+func (m *Model) handleModeKey(key string) tea.Cmd {
+	display := m.displays[m.displayCursor]
+	switch key {
+	case "esc", "q":
+		m.modePicking = false
+	case "enter":
+		m.modePicking = false
+		mode := display.Modes[m.modeCursor]
+		args, err := modeArgs(m.arrangement, display.ID, mode)
+		if err != nil {
+			m.displaysMessage = "✗ " + err.Error()
+			return nil
+		}
+		m.displaysMessage = "Switching to " + mode.String() + "..."
+		return m.runDisplayplacer(args, "✓ "+displayName(display)+" set to "+mode.String())
+	}
+	return nil
+}
+
+// handleLayoutNameKey edits the name of the layout being saved
+func (m *Model) handleLayoutNameKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.layoutNaming = false
+	case tea.KeyBackspace:
+		if runes := []rune(m.layoutName); len(runes) > 0 {
+			m.layoutName = string(runes[:len(runes)-1])
+		}
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.layoutName)
+		if name == "" {
+			m.displaysMessage = "✗ Name the layout first"
+			return nil
+		}
+		m.layoutNaming = false
+		return m.saveDisplayLayout(name)
+	case tea.KeyRunes, tea.KeySpace:
+		if len([]rune(m.layoutName))+len(msg.Runes) <= displayLayoutNameMax {
+			m.layoutName += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// displayName is how the display is listed
+func displayName(d Display) string {
+	name := d.Type
+	if name == "" {
+		name = d.ID
+	}
+	if strings.HasPrefix(name, "MacBook built in") {
+		name = "Built-in display"
+	}
+	return name
+}
+
+// renderDisplays is the Displays tab
+func (m *Model) renderDisplays() string {
+	style := lipgloss.NewStyle().Padding(1)
+	highlightStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	actionStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorBright).Bold(true)
+	okStyle := lipgloss.NewStyle().Foreground(components.ColorSuccess)
+
+	var b strings.Builder
+	b.WriteString(highlightStyle.Render("Displays") + "\n")
+
+	c := m.displayCapability
+	switch {
+	case !m.displaysChecked || m.displaysLoading && c.Feature == "":
+		b.WriteString("⏳ Looking for displays...\n")
+		return style.Render(b.String())
+	case !c.Available():
+		b.WriteString(mutedStyle.Render("displayplacer is needed to change resolutions, mirroring and layouts") + "\n")
+		b.WriteString(mutedStyle.Render(c.Reason) + "\n\n")
+		b.WriteString(actionStyle.Render("[I]") + " Install displayplacer (brew install " + displayplacerFormula + ")\n")
+		b.WriteString(actionStyle.Render("[D]") + " Detect displays\n")
+		if m.displaysMessage != "" {
+			b.WriteString("\n" + m.displaysMessage + "\n")
+		}
+		return style.Render(b.String())
+	case m.displaysErr != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(components.ColorError).Render("✗ "+m.displaysErr.Error()) + "\n")
+	}
+
+	if m.modePicking {
+		b.WriteString(m.renderModePicker())
+		return style.Render(b.String())
+	}
+
+	row := 0
+	cursor := func() string {
+		defer func() { row++ }()
+		if row == m.displayCursor {
+			return "▶ "
+		}
+		return "  "
+	}
+	for _, d := range m.displays {
+		prefix := cursor()
+		name := displayName(d)
+		if d.Main {
+			name += " (main)"
+		}
+		mode := d.Resolution
+		if d.Hertz != "" && d.Hertz != "N/A" {
+			mode += " @ " + d.Hertz + "Hz"
+		}
+		line := fmt.Sprintf("%-30s %-20s", name, mode)
+		if d.Scaling == "on" {
+			line += " HiDPI"
+		}
+		if !d.Enabled {
+			line += mutedStyle.Render(" disabled")
+		}
+		if prefix == "▶ " {
+			line = selectedStyle.Render(line)
+		}
+		b.WriteString(prefix + line + "\n")
+	}
+	mirroring := "off"
+	if mirrored(m.arrangement) {
+		mirroring = "on"
+	}
+	b.WriteString(mutedStyle.Render("  Mirroring: "+mirroring) + "\n\n")
+
+	b.WriteString(highlightStyle.Render("Saved Layouts") + "\n")
+	if len(m.displayLayouts) == 0 {
+		b.WriteString(mutedStyle.Render("  None yet. Arrange your screens, then press S to save (e.g. \"docked\").") + "\n")
+	}
+	current := m.currentLayout()
+	for _, layout := range m.displayLayouts {
+		prefix := cursor()
+		count := fmt.Sprintf("%d screens", len(layout.Args))
+		if len(layout.Args) == 1 {
+			count = "1 screen"
+		}
+		line := fmt.Sprintf("%-24s %-10s", layout.Name, count)
+		if prefix == "▶ " {
+			line = selectedStyle.Render(line)
+		}
+		if layout.Name == current {
+			line += okStyle.Render(" ● current")
+		}
+		b.WriteString(prefix + line + "\n")
+	}
+
+	if m.layoutNaming {
+		input := lipgloss.NewStyle().Foreground(components.ColorBright).Render(m.layoutName + "█")
+		b.WriteString("\n  Layout name: " + input + "  " + mutedStyle.Render("Enter to save, Esc to cancel") + "\n")
+	}
+
+	b.WriteString("\n" + actionStyle.Render("[Enter]") + " Change mode / apply layout  " +
+		actionStyle.Render("[M]") + " Toggle mirroring  " +
+		actionStyle.Render("[D]") + " Detect displays\n")
+	b.WriteString(actionStyle.Render("[S]") + " Save layout  " +
+		actionStyle.Render("[X]") + " Delete layout  " +
+		actionStyle.Render("[R]") + " Rescan\n")
+	switch {
+	case m.displaysBusy:
+		b.WriteString("⏳ " + m.displaysMessage + "\n")
+	case m.displaysMessage != "":
+		b.WriteString(m.displaysMessage + "\n")
+	}
+	return style.Render(b.String())
+}
+
+// renderModePicker lists the selected display's modes around the cursor
+func (m *Model) renderModePicker() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorBright).Bold(true)
+
+	display := m.displays[m.displayCursor]
+	var b strings.Builder
+	b.WriteString("Modes for " + displayName(display) + "\n")
+
+	const shown = 8
+	start := max(0, min(m.modeCursor-shown/2, len(display.Modes)-shown))
+	end := min(len(display.Modes), start+shown)
+	for i := start; i < end; i++ {
+		mode := display.Modes[i]
+		line := mode.String()
+		if mode.Current {
+			line += " (current)"
+		}
+		if i == m.modeCursor {
+			b.WriteString("▶ " + selectedStyle.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("%d of %d • ↑/↓ select • Enter apply • Esc back", m.modeCursor+1, len(display.Modes))) + "\n")
+	return b.String()
+}
@@ -0,0 +1,181 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	builtinID  = "37D8832A-2D66-02CA-B9F7-8F30A301B230"
+	externalID = "5E3B0C3A-1A7F-4E0B-9C2D-2E8D6F1A4B77"
+)
+
+func readDisplayplacerList(t *testing.T) ([]Display, []string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "displayplacer_list.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parseDisplayplacerList(string(data))
+}
+
+func TestParseDisplayplacerList(t *testing.T) {
+	displays, arrangement := readDisplayplacerList(t)
+	if len(displays) != 2 || len(arrangement) != 2 {
+		t.Fatalf("got %d displays and %d arguments, want 2 and 2", len(displays), len(arrangement))
+	}
+	builtin, external := displays[0], displays[1]
+	if !builtin.Main || builtin.Origin != "(0,0)" || external.Main {
+		t.Errorf("main display not detected: %+v / %+v", builtin, external)
+	}
+	if len(external.Modes) != 4 || !external.Modes[1].Current || external.Modes[2].String() != "1920x1080 @ 60Hz (HiDPI)" {
+		t.Errorf("external modes = %+v", external.Modes)
+	}
+	if !strings.HasPrefix(arrangement[1], "id:"+externalID+" res:2560x1440") {
+		t.Errorf("arrangement = %q", arrangement)
+	}
+}
+
+func TestModeArgs(t *testing.T) {
+	displays, arrangement := readDisplayplacerList(t)
+	args, err := modeArgs(arrangement, externalID, displays[1].Modes[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "id:" + externalID + " res:1920x1080 hz:30 color_depth:8 enabled:true scaling:on origin:(1512,-300) degree:0"
+	if args[1] != want || args[0] != arrangement[0] {
+		t.Errorf("args = %q, want %q", args[1], want)
+	}
+	if _, err := modeArgs(arrangement, "unknown", displays[1].Modes[0]); err == nil {
+		t.Error("an unknown display should fail")
+	}
+}
+
+func TestMirrorArgs(t *testing.T) {
+	displays, arrangement := readDisplayplacerList(t)
+
+	on, err := mirrorArgs(displays, arrangement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(on) != 1 || !strings.HasPrefix(on[0], "id:"+builtinID+"+"+externalID+" res:1512x982") || !mirrored(on) {
+		t.Fatalf("mirror on = %q", on)
+	}
+
+	off, err := mirrorArgs(displays, on)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"id:" + builtinID + " res:1512x982 hz:120 color_depth:8 enabled:true scaling:on origin:(0,0) degree:0",
+		"id:" + externalID + " res:2560x1440 enabled:true origin:(1512,0) degree:0",
+	}
+	if !reflect.DeepEqual(off, want) {
+		t.Errorf("mirror off = %q, want %q", off, want)
+	}
+
+	if _, err := mirrorArgs(displays[:1], arrangement[:1]); err == nil {
+		t.Error("mirroring a single display should fail")
+	}
+}
+
+func TestDisplayModeAndLayoutKeys(t *testing.T) {
+	m := New(nil)
+	m.layoutsPath = filepath.Join(t.TempDir(), displayLayoutsFile)
+	fake := runner.NewFake()
+	if err := fake.SetFixture("/usr/bin/displayplacer list", filepath.Join("testdata", "displayplacer_list.txt")); err != nil {
+		t.Fatal(err)
+	}
+	m.runner = fake
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	if cmd == nil {
+		t.Fatal("opening the Displays tab should list displays")
+	}
+	m.Update(cmd())
+
+	// Pick the external display's 1920x1080 HiDPI mode
+	m.Update(events.NavDown)
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.HasOpenModal() || m.modeCursor != 1 {
+		t.Fatalf("mode picker not open on the current mode: picking=%v cursor=%d", m.modePicking, m.modeCursor)
+	}
+	m.Update(events.NavDown)
+	apply := "/usr/bin/displayplacer id:" + builtinID + " res:1512x982 hz:120 color_depth:8 enabled:true scaling:on origin:(0,0) degree:0 " +
+		"id:" + externalID + " res:1920x1080 hz:60 color_depth:8 enabled:true scaling:on origin:(1512,-300) degree:0"
+	fake.Set(apply, "", nil)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmd()
+	if action, ok := msg.(displayActionMsg); !ok || action.err != nil || !action.rescan {
+		t.Fatalf("apply = %#v", msg)
+	}
+	if calls := fake.Calls(); calls[len(calls)-1] != apply {
+		t.Errorf("ran %q", calls[len(calls)-1])
+	}
+	m.Update(displayActionMsg{})
+
+	// Save the arrangement as "desk", then select and delete it
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if m.layoutName != "docked" {
+		t.Errorf("suggested name = %q, want docked", m.layoutName)
+	}
+	for range "docked" {
+		m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("desk")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if m.currentLayout() != "desk" {
+		t.Fatalf("layouts = %+v", m.displayLayouts)
+	}
+	if saved, err := loadDisplayLayouts(m.layoutsPath); err != nil || len(saved) != 1 || saved[0].Name != "desk" {
+		t.Errorf("saved = %+v, %v", saved, err)
+	}
+
+	m.Update(events.NavDown)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m.Update(cmd())
+	if len(m.displayLayouts) != 0 || m.displayCursor != 1 {
+		t.Errorf("after delete: layouts = %+v, cursor = %d", m.displayLayouts, m.displayCursor)
+	}
+}
+
+func TestDisplayLayoutsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), displayLayoutsFile)
+	if layouts, err := loadDisplayLayouts(path); err != nil || layouts != nil {
+		t.Fatalf("missing file = %v, %v", layouts, err)
+	}
+	want := []DisplayLayout{{Name: "docked", Args: []string{"id:A res:1512x982", "id:B res:2560x1440"}}}
+	if err := saveDisplayLayouts(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadDisplayLayouts(path)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("layouts = %+v, %v", got, err)
+	}
+}
+
+func TestDisplayplacerMissing(t *testing.T) {
+	m := New(nil)
+	m.runner = runner.NewFake().Missing("displayplacer", "brew")
+	m.activeTab = displaysTab
+	m.Update(m.scanDisplays()())
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if cmd, _ := m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")}); cmd != nil {
+		t.Error("install without Homebrew should not run anything")
+	}
+	if !strings.Contains(m.displaysMessage, "needs Homebrew") {
+		t.Errorf("message = %q", m.displaysMessage)
+	}
+	if view := m.renderDisplays(); !strings.Contains(view, "Install displayplacer") {
+		t.Errorf("view lacks the install action:\n%s", view)
+	}
+}
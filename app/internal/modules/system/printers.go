@@ -0,0 +1,347 @@
+package system
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// printersTab is the index of the Printers tab
+const printersTab = 8
+
+// stuckAfter is how long a job may wait before it counts as stuck
+const stuckAfter = 10 * time.Minute
+
+// cupsWebURL is where CUPS serves its web interface when it's on
+const cupsWebURL = "http://localhost:631"
+
+// Printer is a CUPS print queue
+type Printer struct {
+	Name     string
+	State    string // idle, printing or disabled
+	Reason   string // why a disabled queue stopped, e.g. "Unable to locate printer"
+	Default  bool
+	JobCount int
+}
+
+// PrintJob is a job waiting in a queue
+type PrintJob struct {
+	ID      string // e.g. HP_LaserJet-12
+	Printer string
+	User    string
+	Size    int64
+	Queued  time.Time // zero when lpstat's date didn't parse
+}
+
+var (
+	printerLine = regexp.MustCompile(`^printer (\S+) (?:is (idle)|(disabled)|now (printing))`)
+	defaultLine = regexp.MustCompile(`^system default destination: (\S+)`)
+	jobLine     = regexp.MustCompile(`^(\S+)-(\d+)\s+(\S+)\s+(\d+)\s+(.+)$`)
+)
+
+type printersMsg struct {
+	printers []Printer
+	jobs     []PrintJob
+	web      bool
+	err      error
+}
+
+type printerActionMsg struct {
+	note string
+	err  error
+}
+
+// cancelJobsMsg asks to cancel every job on a printer once confirmed
+type cancelJobsMsg struct{ printer string }
+
+// scanPrinters reads the queues, their jobs and whether the CUPS web
+// interface is on
+func (m *Model) scanPrinters() tea.Cmd {
+	m.printersChecked = true
+	r := m.runner
+	return func() tea.Msg {
+		printers, jobs, err := readPrinters(r)
+		if err != nil {
+			return printersMsg{err: err}
+		}
+		output, _ := r.CombinedOutput(exec.Command("cupsctl"))
+		web := strings.Contains(string(output), "WebInterface=yes")
+		return printersMsg{printers: printers, jobs: jobs, web: web}
+	}
+}
+
+// readPrinters parses `lpstat -d -p` and `lpstat -o`
+func readPrinters(r runner.Runner) ([]Printer, []PrintJob, error) {
+	// lpstat exits non-zero when there are no printers, after saying so
+	output, err := r.CombinedOutput(exec.Command("lpstat", "-d", "-p"))
+	text := string(output)
+	if err != nil && !strings.Contains(text, "No destinations added") && !strings.Contains(text, "no system default destination") {
+		return nil, nil, fmt.Errorf("lpstat: %s", firstLine(output, err))
+	}
+
+	var printers []Printer
+	defaultName := ""
+	for _, line := range strings.Split(text, "\n") {
+		if match := defaultLine.FindStringSubmatch(line); match != nil {
+			defaultName = match[1]
+			continue
+		}
+		if match := printerLine.FindStringSubmatch(line); match != nil {
+			printers = append(printers, Printer{Name: match[1], State: match[2] + match[3] + match[4]})
+			continue
+		}
+		// A disabled queue's reason is on the next, indented line
+		if n := len(printers); n > 0 && strings.HasPrefix(line, "\t") && printers[n-1].State == "disabled" {
+			printers[n-1].Reason = strings.TrimSpace(line)
+		}
+	}
+
+	output, err = r.CombinedOutput(exec.Command("lpstat", "-o"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("lpstat -o: %s", firstLine(output, err))
+	}
+	var jobs []PrintJob
+	for _, line := range strings.Split(string(output), "\n") {
+		match := jobLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(match[4], 10, 64)
+		queued, _ := time.ParseInLocation("Mon Jan _2 15:04:05 2006", match[5], time.Local)
+		jobs = append(jobs, PrintJob{ID: match[1] + "-" + match[2], Printer: match[1], User: match[3], Size: size, Queued: queued})
+	}
+
+	for i := range printers {
+		printers[i].Default = printers[i].Name == defaultName
+		for _, job := range jobs {
+			if job.Printer == printers[i].Name {
+				printers[i].JobCount++
+			}
+		}
+	}
+	return printers, jobs, nil
+}
+
+// stuck reports whether a job is going nowhere: its queue is disabled, or
+// it has waited longer than stuckAfter
+func (m *Model) stuck(job PrintJob, now time.Time) bool {
+	for _, p := range m.printers {
+		if p.Name == job.Printer && p.State == "disabled" {
+			return true
+		}
+	}
+	return !job.Queued.IsZero() && now.Sub(job.Queued) > stuckAfter
+}
+
+// confirmCancelJobs asks before cancelling the selected printer's jobs
+func (m *Model) confirmCancelJobs() tea.Cmd {
+	if m.printerCursor >= len(m.printers) {
+		return nil
+	}
+	p := m.printers[m.printerCursor]
+	if p.JobCount == 0 {
+		m.printersMessage = "No jobs on " + p.Name
+		return nil
+	}
+	return dialog.Confirm(dialog.Request{
+		Title:        fmt.Sprintf("Cancel %d job(s) on %s?", p.JobCount, p.Name),
+		Detail:       "The documents are removed from the queue and won't print.",
+		ConfirmLabel: "Cancel Jobs",
+		Destructive:  true,
+		OnConfirm:    cancelJobsMsg{printer: p.Name},
+	})
+}
+
+// cancelJobs runs `cancel -a` for a confirmed cancelJobsMsg
+func (m *Model) cancelJobs(printer string) tea.Cmd {
+	m.printersBusy = true
+	r := m.runner
+	return func() tea.Msg {
+		if output, err := r.CombinedOutput(exec.Command("cancel", "-a", printer)); err != nil {
+			return printerActionMsg{err: fmt.Errorf("cancel: %s", firstLine(output, err))}
+		}
+		return printerActionMsg{note: "✓ Cancelled the jobs on " + printer}
+	}
+}
+
+// resumePrinter re-enables a queue CUPS paused after an error
+func (m *Model) resumePrinter() tea.Cmd {
+	if m.printerCursor >= len(m.printers) {
+		return nil
+	}
+	p := m.printers[m.printerCursor]
+	if p.State != "disabled" {
+		m.printersMessage = p.Name + " isn't paused"
+		return nil
+	}
+	m.printersBusy = true
+	r := m.runner
+	return func() tea.Msg {
+		if output, err := r.CombinedOutput(exec.Command("cupsenable", p.Name)); err != nil {
+			return printerActionMsg{err: fmt.Errorf("cupsenable: %s", firstLine(output, err))}
+		}
+		return printerActionMsg{note: "✓ Resumed " + p.Name}
+	}
+}
+
+// toggleCUPSWeb turns the CUPS web interface on or off; cupsctl needs
+// the administrator password
+func (m *Model) toggleCUPSWeb() tea.Cmd {
+	m.printersBusy = true
+	on := !m.cupsWeb
+	return func() tea.Msg {
+		value := "WebInterface=no"
+		if on {
+			value = "WebInterface=yes"
+		}
+		if _, err := runPrivileged("cupsctl", value); err != nil {
+			return printerActionMsg{err: err}
+		}
+		if on {
+			return printerActionMsg{note: "✓ CUPS web interface on at " + cupsWebURL}
+		}
+		return printerActionMsg{note: "✓ CUPS web interface off"}
+	}
+}
+
+func (m *Model) updatePrinters(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case printersMsg:
+		m.printersLoaded = true
+		m.printersErr = msg.err
+		if msg.err == nil {
+			m.printers, m.printJobs, m.cupsWeb = msg.printers, msg.jobs, msg.web
+			m.printerCursor = min(m.printerCursor, max(len(m.printers)-1, 0))
+		}
+	case cancelJobsMsg:
+		return m.cancelJobs(msg.printer)
+	case printerActionMsg:
+		m.printersBusy = false
+		m.printersMessage = msg.note
+		if msg.err != nil {
+			m.printersMessage = "✗ " + msg.err.Error()
+		}
+		return tea.Batch(components.StatusToast(m.printersMessage), m.scanPrinters())
+	}
+	return nil
+}
+
+// handlePrinterKey handles keys on the Printers tab and reports whether
+// the key was used
+func (m *Model) handlePrinterKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	if m.printersBusy || !m.printersLoaded {
+		return nil, key == "r" || key == "x" || key == "e" || key == "w"
+	}
+	m.printersMessage = ""
+	switch key {
+	case "r":
+		return m.scanPrinters(), true
+	case "x":
+		return m.confirmCancelJobs(), true
+	case "e":
+		return m.resumePrinter(), true
+	case "w":
+		return m.toggleCUPSWeb(), true
+	}
+	return nil, false
+}
+
+// renderPrinters is the Printers tab
+func (m *Model) renderPrinters() string {
+	style := lipgloss.NewStyle().Padding(1)
+	highlightStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	actionStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorBright).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(highlightStyle.Render("Print Queues") + "\n")
+	switch {
+	case m.printersErr != nil:
+		b.WriteString("  " + errorStyle.Render("✗ "+m.printersErr.Error()) + "\n")
+		return style.Render(b.String())
+	case !m.printersLoaded:
+		b.WriteString("  ⏳ Reading print queues...\n")
+		return style.Render(b.String())
+	case len(m.printers) == 0:
+		b.WriteString("  " + mutedStyle.Render("No printers. Add one in System Settings > Printers & Scanners.") + "\n")
+	}
+
+	for i, p := range m.printers {
+		name := p.Name
+		if p.Default {
+			name += " (default)"
+		}
+		state := lipgloss.NewStyle().Foreground(components.ColorSuccess).Render(fmt.Sprintf("● %-8s", p.State))
+		if p.State == "disabled" {
+			state = errorStyle.Render(fmt.Sprintf("● %-8s", "paused"))
+		}
+		line := fmt.Sprintf("%-32s", components.TruncateString(name, 32))
+		if i == m.printerCursor {
+			b.WriteString("▶ " + selectedStyle.Render(line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString(" " + state + mutedStyle.Render(fmt.Sprintf("  %d job(s)", p.JobCount)))
+		if p.Reason != "" {
+			b.WriteString("  " + mutedStyle.Render(p.Reason))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + highlightStyle.Render("Jobs") + "\n")
+	if len(m.printJobs) == 0 {
+		b.WriteString("  " + mutedStyle.Render("Nothing waiting to print") + "\n")
+	}
+	now := time.Now()
+	for _, job := range m.printJobs {
+		queued := ""
+		if !job.Queued.IsZero() {
+			queued = job.Queued.Format("Jan 2 15:04")
+		}
+		line := fmt.Sprintf("  %-28s %-10s %8s  %s", components.TruncateString(job.ID, 28), job.User, formatBytes(job.Size), queued)
+		if m.stuck(job, now) {
+			line += "  " + errorStyle.Render("stuck")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	web := "off"
+	if m.cupsWeb {
+		web = "on at " + cupsWebURL
+	}
+	b.WriteString("\n" + mutedStyle.Render("CUPS web interface: "+web) + "\n")
+	b.WriteString(mutedStyle.Render("Scanners have no queue to manage; use Image Capture for them.") + "\n\n")
+
+	b.WriteString(actionStyle.Render("[X]") + " Cancel all jobs  " +
+		actionStyle.Render("[E]") + " Resume paused printer  " +
+		actionStyle.Render("[W]") + " Toggle web interface  " +
+		actionStyle.Render("[R]") + " Reread\n")
+	if m.printersMessage != "" {
+		b.WriteString(m.printersMessage + "\n")
+	}
+	return style.Render(b.String())
+}
+
+// formatBytes is e.g. "121 KB" or "2.4 MB"
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/1024/1024)
+	case size >= 1024:
+		return fmt.Sprintf("%d KB", size/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
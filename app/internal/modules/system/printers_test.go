@@ -0,0 +1,97 @@
+package system
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/dialog"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// printerFixtures fakes a paused Brother with two jobs and an idle HP
+func printerFixtures(t *testing.T, fake *runner.Fake) {
+	t.Helper()
+	if err := fake.SetFixture("lpstat -d -p", filepath.Join("testdata", "lpstat_printers.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fake.SetFixture("lpstat -o", filepath.Join("testdata", "lpstat_jobs.txt")); err != nil {
+		t.Fatal(err)
+	}
+	fake.Set("cupsctl", "_debug_logging=0\n_remote_admin=0\nWebInterface=no\n", nil)
+}
+
+func TestReadPrinters(t *testing.T) {
+	fake := runner.NewFake()
+	printerFixtures(t, fake)
+	printers, jobs, err := readPrinters(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Printer{
+		{Name: "Brother_HL_L2350DW", State: "disabled", Reason: `Unable to locate printer "BRW0080927AFBCE.local".`, JobCount: 2},
+		{Name: "HP_LaserJet_Pro", State: "idle", Default: true},
+	}
+	if len(printers) != len(want) || printers[0] != want[0] || printers[1] != want[1] {
+		t.Errorf("printers = %+v, want %+v", printers, want)
+	}
+	if len(jobs) != 2 || jobs[0].ID != "Brother_HL_L2350DW-41" || jobs[0].Size != 123904 ||
+		!jobs[0].Queued.Equal(time.Date(2024, 7, 1, 10, 2, 11, 0, time.Local)) {
+		t.Errorf("jobs = %+v", jobs)
+	}
+
+	// lpstat fails on a Mac without printers
+	fake.Set("lpstat -d -p", "no system default destination\nlpstat: No destinations added.\n", errors.New("exit status 1")).
+		Set("lpstat -o", "", nil)
+	if printers, _, err := readPrinters(fake); err != nil || len(printers) != 0 {
+		t.Errorf("without printers: %+v, %v", printers, err)
+	}
+}
+
+func TestPrinterActions(t *testing.T) {
+	var calls []string
+	runPrivileged = func(command string, args ...string) (string, error) {
+		calls = append(calls, command+" "+strings.Join(args, " "))
+		return "", nil
+	}
+	t.Cleanup(func() { runPrivileged = sudo.Run })
+
+	fake := runner.NewFake()
+	printerFixtures(t, fake)
+	fake.Set("cancel -a Brother_HL_L2350DW", "", nil).
+		Set("cupsenable Brother_HL_L2350DW", "", nil)
+	m := New(nil)
+	m.runner = fake
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("9")})
+	m.Update(cmd())
+	if !m.stuck(m.printJobs[0], time.Now()) {
+		t.Error("a job on a paused printer should be stuck")
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	req, ok := cmd().(dialog.Request)
+	if !ok || !req.Destructive || !strings.Contains(req.Title, "2 job(s) on Brother_HL_L2350DW") {
+		t.Fatalf("x should ask first, got %+v", req)
+	}
+	_, cmd = m.Update(req.OnConfirm)
+	m.Update(cmd())
+	if !strings.HasPrefix(m.printersMessage, "✓ Cancelled") {
+		t.Errorf("message = %q", m.printersMessage)
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m.Update(cmd())
+	if m.printersMessage != "✓ Resumed Brother_HL_L2350DW" {
+		t.Errorf("message = %q", m.printersMessage)
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	m.Update(cmd())
+	if len(calls) != 1 || calls[0] != "cupsctl WebInterface=yes" {
+		t.Errorf("ran %q", calls)
+	}
+}
@@ -0,0 +1,420 @@
+package system
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// regionTab is the index of the Region tab
+const regionTab = 7
+
+// The Region tab's rows, in order
+const (
+	regionZone = iota
+	regionAutoZone
+	regionLocale
+	regionWeekday
+	regionRows
+)
+
+var (
+	localeKey    = defaults.Key{Domain: "NSGlobalDomain", Name: "AppleLocale", Type: defaults.String}
+	languagesKey = defaults.Key{Domain: "NSGlobalDomain", Name: "AppleLanguages"}
+	weekdayKey   = defaults.Key{Domain: "NSGlobalDomain", Name: "AppleFirstWeekday"}
+	// autoZoneKey is the system-wide "Set time zone automatically using
+	// your current location" switch; macOS turns it on when it isn't set
+	autoZoneKey = defaults.Key{Domain: "/Library/Preferences/com.apple.timezone.auto", Name: "Active", Type: defaults.Bool}
+)
+
+// weekdays are named by AppleFirstWeekday's numbering, Sunday being 1
+var weekdays = []string{"", "Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+var (
+	weekdayPattern  = regexp.MustCompile(`gregorian = (\d)`)
+	languagePattern = regexp.MustCompile(`"([^"]+)"`)
+	localePattern   = regexp.MustCompile(`^[a-z]{2,3}(_[A-Z]{2})?(@[\w=;-]+)?$`)
+)
+
+// regionInfo is the clock and language settings the Region tab shows
+type regionInfo struct {
+	Timezone  string // IANA name, e.g. Europe/Lisbon
+	AutoZone  bool
+	Locale    string
+	Languages []string
+	Weekday   int // 1-7 from Sunday; 0 follows the locale
+}
+
+type regionMsg struct {
+	info regionInfo
+	err  error
+}
+
+type regionAppliedMsg struct {
+	note string
+	err  error
+}
+
+// scanRegion reads the time zone, locale and calendar settings
+func (m *Model) scanRegion() tea.Cmd {
+	m.regionChecked = true
+	r := m.runner
+	return func() tea.Msg {
+		var info regionInfo
+		// /etc/localtime links into the zoneinfo database, e.g.
+		// /var/db/timezone/zoneinfo/Europe/Lisbon
+		output, err := r.CombinedOutput(exec.Command("readlink", "/etc/localtime"))
+		if err != nil {
+			return regionMsg{err: fmt.Errorf("reading the time zone: %s", firstLine(output, err))}
+		}
+		link := strings.TrimSpace(string(output))
+		if _, zone, ok := strings.Cut(link, "zoneinfo/"); ok {
+			link = zone
+		}
+		info.Timezone = link
+
+		auto, err := defaults.Read(r, autoZoneKey)
+		if err != nil {
+			return regionMsg{err: err}
+		}
+		info.AutoZone = !auto.Set || auto.Text == "true"
+
+		locale, err := defaults.Read(r, localeKey)
+		if err != nil {
+			return regionMsg{err: err}
+		}
+		info.Locale = locale.Text
+
+		languages, err := defaults.Read(r, languagesKey)
+		if err != nil {
+			return regionMsg{err: err}
+		}
+		for _, match := range languagePattern.FindAllStringSubmatch(languages.Text, -1) {
+			info.Languages = append(info.Languages, match[1])
+		}
+
+		weekday, err := defaults.Read(r, weekdayKey)
+		if err != nil {
+			return regionMsg{err: err}
+		}
+		if match := weekdayPattern.FindStringSubmatch(weekday.Text); match != nil {
+			info.Weekday, _ = strconv.Atoi(match[1])
+		}
+		return regionMsg{info: info}
+	}
+}
+
+// setTimezone changes the system time zone, which needs the administrator
+// password
+func (m *Model) setTimezone(zone string) tea.Cmd {
+	if _, err := time.LoadLocation(zone); err != nil || zone == "" || zone == "Local" {
+		m.regionMessage = "✗ Unknown time zone " + zone + " (use a name like Europe/Lisbon)"
+		return nil
+	}
+	before, auto := m.region.Timezone, m.region.AutoZone
+	m.regionBusy = true
+	return func() tea.Msg {
+		if _, err := runPrivileged("systemsetup", "-settimezone", zone); err != nil {
+			return regionAppliedMsg{err: err}
+		}
+		changes.Record(changes.Change{
+			Source: m.Title(),
+			What:   "Time zone",
+			Before: before,
+			After:  zone,
+			Revert: func() error {
+				_, err := runPrivileged("systemsetup", "-settimezone", before)
+				return err
+			},
+		})
+		note := "✓ Time zone set to " + zone
+		if auto {
+			note += " (automatic time zone may change it back)"
+		}
+		return regionAppliedMsg{note: note}
+	}
+}
+
+// setAutoZone turns the automatic time zone on or off
+func (m *Model) setAutoZone(on bool) tea.Cmd {
+	m.regionBusy = true
+	return func() tea.Msg {
+		if _, err := runPrivileged("defaults", "write", autoZoneKey.Domain, autoZoneKey.Name, "-bool", strconv.FormatBool(on)); err != nil {
+			return regionAppliedMsg{err: err}
+		}
+		changes.Record(changes.Change{
+			Source: m.Title(),
+			What:   "Automatic time zone",
+			Before: onOffLabel(!on),
+			After:  onOffLabel(on),
+			Revert: func() error {
+				_, err := runPrivileged("defaults", "write", autoZoneKey.Domain, autoZoneKey.Name, "-bool", strconv.FormatBool(!on))
+				return err
+			},
+		})
+		return regionAppliedMsg{note: "✓ Automatic time zone " + onOffLabel(on)}
+	}
+}
+
+// setLocale writes the locale apps format dates, numbers and currency with
+func (m *Model) setLocale(locale string) tea.Cmd {
+	if !localePattern.MatchString(locale) {
+		m.regionMessage = "✗ " + locale + " isn't a locale (use a name like en_GB)"
+		return nil
+	}
+	m.regionBusy = true
+	return func() tea.Msg {
+		if err := changes.WriteDefault(m.runner, m.Title(), localeKey, locale, nil); err != nil {
+			return regionAppliedMsg{err: err}
+		}
+		return regionAppliedMsg{note: "✓ Locale set to " + locale + "; apps use it once they restart"}
+	}
+}
+
+// setWeekday makes day the first day of the week, or follows the locale
+// again for 0. The setting is a dictionary, which defaults.Write can't
+// write.
+func (m *Model) setWeekday(day int) tea.Cmd {
+	before := m.region.Weekday
+	m.regionBusy = true
+	return func() tea.Msg {
+		if err := m.writeWeekday(day); err != nil {
+			return regionAppliedMsg{err: err}
+		}
+		changes.Record(changes.Change{
+			Source: m.Title(),
+			What:   weekdayKey.String(),
+			Before: weekdayName(before),
+			After:  weekdayName(day),
+			Revert: func() error { return m.writeWeekday(before) },
+		})
+		return regionAppliedMsg{note: "✓ Weeks start on " + weekdayName(day) + "; apps use it once they restart"}
+	}
+}
+
+func (m *Model) writeWeekday(day int) error {
+	if day == 0 {
+		return defaults.Delete(m.runner, weekdayKey)
+	}
+	cmd := exec.Command("defaults", "write", weekdayKey.Domain, weekdayKey.Name, "-dict", "gregorian", "-int", strconv.Itoa(day))
+	if output, err := m.runner.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("defaults write %s: %s", weekdayKey, firstLine(output, err))
+	}
+	return nil
+}
+
+// weekdayName names a first weekday setting
+func weekdayName(day int) string {
+	if day < 1 || day >= len(weekdays) {
+		return "locale default"
+	}
+	return weekdays[day]
+}
+
+func onOffLabel(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+func (m *Model) updateRegion(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case regionMsg:
+		m.regionErr = msg.err
+		if msg.err == nil {
+			m.region = &msg.info
+		}
+	case regionAppliedMsg:
+		m.regionBusy = false
+		m.regionMessage = msg.note
+		if msg.err != nil {
+			m.regionMessage = "✗ " + msg.err.Error()
+		}
+		return tea.Batch(components.StatusToast(m.regionMessage), m.scanRegion())
+	}
+	return nil
+}
+
+// handleRegionKey handles keys on the Region tab and reports whether the
+// key was used
+func (m *Model) handleRegionKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	if m.regionEditing {
+		return m.handleRegionInput(msg), true
+	}
+	if m.regionBusy || m.region == nil {
+		return nil, key == "r" || key == "enter"
+	}
+
+	switch key {
+	case "r":
+		m.regionMessage = ""
+		return m.scanRegion(), true
+	case "enter", " ":
+		m.regionMessage = ""
+		switch m.regionCursor {
+		case regionZone:
+			m.regionEditing, m.regionInput = true, m.region.Timezone
+		case regionLocale:
+			m.regionEditing, m.regionInput = true, m.region.Locale
+		case regionAutoZone:
+			return m.setAutoZone(!m.region.AutoZone), true
+		case regionWeekday:
+			return m.setWeekday((m.region.Weekday + 1) % len(weekdays)), true
+		}
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// handleRegionInput edits the time zone or locale
+func (m *Model) handleRegionInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.regionEditing = false
+	case tea.KeyEnter:
+		m.regionEditing = false
+		value := strings.TrimSpace(m.regionInput)
+		if m.regionCursor == regionZone && value != m.region.Timezone {
+			return m.setTimezone(value)
+		}
+		if m.regionCursor == regionLocale && value != m.region.Locale {
+			return m.setLocale(value)
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(m.regionInput); len(runes) > 0 {
+			m.regionInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.regionInput = ""
+	case tea.KeyRunes:
+		m.regionInput += string(msg.Runes)
+	}
+	return nil
+}
+
+// renderRegion is the Region tab
+func (m *Model) renderRegion() string {
+	style := lipgloss.NewStyle().Padding(1)
+	highlightStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	actionStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	errorStyle := lipgloss.NewStyle().Foreground(components.ColorError)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorBright).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(highlightStyle.Render("Time Zone & Region") + "\n")
+	switch {
+	case m.regionErr != nil:
+		b.WriteString("  " + errorStyle.Render("✗ "+m.regionErr.Error()) + "\n")
+		return style.Render(b.String())
+	case m.region == nil:
+		b.WriteString("  ⏳ Reading region settings...\n")
+		return style.Render(b.String())
+	}
+
+	info := m.region
+	zone := info.Timezone
+	if loc, err := time.LoadLocation(zone); err == nil {
+		zone += mutedStyle.Render(" " + time.Now().In(loc).Format("MST, UTC-07:00"))
+	}
+	languages := mutedStyle.Render("languages: " + strings.Join(info.Languages, ", "))
+	if len(info.Languages) == 0 {
+		languages = ""
+	}
+	rows := []struct{ label, value, note string }{
+		{"Time zone", zone, ""},
+		{"Automatic time zone", onOffLabel(info.AutoZone), mutedStyle.Render("uses Location Services")},
+		{"Locale", info.Locale, languages},
+		{"First day of week", weekdayName(info.Weekday), ""},
+	}
+	for i, row := range rows {
+		value := row.value
+		if i == m.regionCursor && m.regionEditing {
+			value = lipgloss.NewStyle().Foreground(components.ColorBright).Render(m.regionInput + "█")
+		}
+		line := fmt.Sprintf("%-20s %s", row.label, value)
+		if i == m.regionCursor {
+			b.WriteString("▶ " + selectedStyle.Render(line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		if row.note != "" {
+			b.WriteString("  " + row.note)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + highlightStyle.Render("World Clock") + "\n")
+	b.WriteString(m.renderWorldClock(time.Now()))
+
+	b.WriteString("\n")
+	if m.regionEditing {
+		b.WriteString(mutedStyle.Render("Type a new value • Enter apply • Ctrl+U clear • Esc cancel") + "\n")
+	} else {
+		b.WriteString(actionStyle.Render("[Enter]") + " Change  " + actionStyle.Render("[R]") + " Reread\n")
+	}
+	if m.regionMessage != "" {
+		b.WriteString(m.regionMessage + "\n")
+	}
+	return style.Render(b.String())
+}
+
+// renderWorldClock shows the time of each teammate under
+// system.world_clock and how far ahead or behind it is, a few per line
+func (m *Model) renderWorldClock(now time.Time) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	var clocks []config.WorldClock
+	if m.config != nil {
+		clocks = m.config.System.WorldClock
+	}
+	if len(clocks) == 0 {
+		return "  " + mutedStyle.Render("Add teammates' time zones under system.world_clock in config.yaml") + "\n"
+	}
+
+	_, localOffset := now.Zone()
+	var entries []string
+	for _, c := range clocks {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			entries = append(entries, c.Name+" "+mutedStyle.Render("unknown time zone"))
+			continue
+		}
+		there := now.In(loc)
+		_, offset := there.Zone()
+		diff := time.Duration(offset-localOffset) * time.Second
+		relative := "same time"
+		if diff != 0 {
+			relative = fmt.Sprintf("%+gh", diff.Hours())
+		}
+		entries = append(entries, fmt.Sprintf("%s %s", c.Name, there.Format("15:04 Mon"))+" "+mutedStyle.Render(relative))
+	}
+
+	var b strings.Builder
+	line := ""
+	width := max(m.width-8, 30)
+	for _, entry := range entries {
+		if line != "" && lipgloss.Width(line)+3+lipgloss.Width(entry) > width {
+			b.WriteString("  " + line + "\n")
+			line = ""
+		}
+		if line != "" {
+			line += "  ·  "
+		}
+		line += entry
+	}
+	b.WriteString("  " + line + "\n")
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package system
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/changes"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// regionDefaults fakes a Mac in Tokyo with a British locale and weeks
+// starting on Monday
+func regionDefaults(fake *runner.Fake) {
+	fake.Set("readlink /etc/localtime", "/var/db/timezone/zoneinfo/Asia/Tokyo\n", nil).
+		Set("defaults read /Library/Preferences/com.apple.timezone.auto Active", "0\n", nil).
+		Set("defaults read NSGlobalDomain AppleLocale", "en_GB\n", nil).
+		Set("defaults read NSGlobalDomain AppleLanguages", "(\n    \"en-GB\",\n    \"ja-JP\"\n)\n", nil).
+		Set("defaults read NSGlobalDomain AppleFirstWeekday", "{\n    gregorian = 2;\n}\n", nil)
+}
+
+func TestRegionTab(t *testing.T) {
+	var calls []string
+	runPrivileged = func(command string, args ...string) (string, error) {
+		calls = append(calls, command+" "+strings.Join(args, " "))
+		return "", nil
+	}
+	t.Cleanup(func() { runPrivileged = sudo.Run })
+	changes.Clear()
+	t.Cleanup(changes.Clear)
+
+	fake := runner.NewFake()
+	regionDefaults(fake)
+	m := New(nil)
+	m.runner = fake
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("8")})
+	m.Update(cmd())
+
+	want := regionInfo{Timezone: "Asia/Tokyo", Locale: "en_GB", Languages: []string{"en-GB", "ja-JP"}, Weekday: 2}
+	if m.region == nil || m.region.Timezone != want.Timezone || m.region.AutoZone || m.region.Locale != want.Locale ||
+		strings.Join(m.region.Languages, ",") != "en-GB,ja-JP" || m.region.Weekday != 2 {
+		t.Fatalf("region = %+v, want %+v", m.region, want)
+	}
+
+	// An unknown zone is refused before asking for the password
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Mars/Olympus")})
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !strings.Contains(m.regionMessage, "Unknown time zone") {
+		t.Fatalf("message = %q", m.regionMessage)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Europe/Lisbon")})
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if len(calls) != 1 || calls[0] != "systemsetup -settimezone Europe/Lisbon" {
+		t.Errorf("ran %q", calls)
+	}
+
+	// Enter on the first day of the week moves it to Tuesday
+	fake.Set("defaults write NSGlobalDomain AppleFirstWeekday -dict gregorian -int 3", "", nil)
+	m.regionCursor = regionWeekday
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if !strings.HasPrefix(m.regionMessage, "✓ Weeks start on Tuesday") {
+		t.Errorf("message = %q", m.regionMessage)
+	}
+	if list := changes.List(); len(list) != 2 || list[0].Before != "Monday" || list[0].After != "Tuesday" {
+		t.Errorf("changes = %+v", list)
+	}
+}
+
+func TestRegionWeekdayFollowsLocale(t *testing.T) {
+	fake := runner.NewFake()
+	regionDefaults(fake)
+	fake.Set("defaults read NSGlobalDomain AppleFirstWeekday", "The domain/default pair of (NSGlobalDomain, AppleFirstWeekday) does not exist\n", errors.New("exit status 1"))
+	m := New(nil)
+	m.runner = fake
+	m.Update(m.scanRegion()())
+	if m.region == nil || m.region.Weekday != 0 || weekdayName(m.region.Weekday) != "locale default" {
+		t.Errorf("region = %+v", m.region)
+	}
+}
+
+func TestWorldClock(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.System.WorldClock = []config.WorldClock{
+		{Name: "Ana", Timezone: "America/Sao_Paulo"},
+		{Name: "Kenji", Timezone: "Asia/Tokyo"},
+		{Name: "Bo", Timezone: "Nowhere/Atlantis"},
+	}
+	m := New(cfg)
+	m.width = 120
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	got := m.renderWorldClock(now)
+	for _, want := range []string{"Ana 09:00 Mon", "-3h", "Kenji 21:00 Mon", "+9h", "Bo", "unknown time zone"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("world clock is missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package system
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// macKind is the hardware family that decides how SMC and NVRAM resets work
+type macKind int
+
+const (
+	appleSilicon macKind = iota
+	intelT2Laptop
+	intelT2Desktop
+	intelLaptop
+	intelDesktop
+)
+
+func (k macKind) String() string {
+	switch k {
+	case appleSilicon:
+		return "Apple silicon"
+	case intelT2Laptop:
+		return "Intel notebook with T2 chip"
+	case intelT2Desktop:
+		return "Intel desktop with T2 chip"
+	case intelLaptop:
+		return "Intel notebook"
+	default:
+		return "Intel desktop"
+	}
+}
+
+// macModel identifies the Mac a reset guide is for
+type macModel struct {
+	Identifier string // e.g. MacBookPro16,1
+	Kind       macKind
+}
+
+// detectMac tells Apple silicon from Intel, and Intel Macs with a T2
+// security chip from older ones
+func detectMac(r runner.Runner) (macModel, error) {
+	output, err := r.Output(exec.Command("sysctl", "-n", "hw.model"))
+	if err != nil {
+		return macModel{}, fmt.Errorf("sysctl hw.model: %w", err)
+	}
+	mac := macModel{Identifier: strings.TrimSpace(string(output))}
+	laptop := strings.HasPrefix(mac.Identifier, "MacBook")
+
+	// hw.optional.arm64 doesn't exist on Intel, so an error means Intel.
+	// It reads 1 under Rosetta too, unlike the architecture we were built for.
+	if output, err := r.Output(exec.Command("sysctl", "-n", "hw.optional.arm64")); err == nil && strings.TrimSpace(string(output)) == "1" {
+		mac.Kind = appleSilicon
+		return mac, nil
+	}
+
+	t2 := false
+	if output, err := r.Output(exec.Command("system_profiler", "SPiBridgeDataType")); err == nil {
+		t2 = strings.Contains(string(output), "T2")
+	}
+	switch {
+	case t2 && laptop:
+		mac.Kind = intelT2Laptop
+	case t2:
+		mac.Kind = intelT2Desktop
+	case laptop:
+		mac.Kind = intelLaptop
+	default:
+		mac.Kind = intelDesktop
+	}
+	return mac, nil
+}
+
+// resetGuide is the step-by-step instructions shown over the Maintenance tab
+type resetGuide struct {
+	title string
+	intro string
+	steps []string
+	more  string // Apple's support article
+}
+
+// guideMsg carries the detected Mac for the guide that was asked for
+type guideMsg struct {
+	nvram bool
+	mac   macModel
+	err   error
+}
+
+// openResetGuide detects the Mac, once, and shows the SMC or NVRAM guide for it
+func (m *Model) openResetGuide(nvram bool) tea.Cmd {
+	if m.mac != nil {
+		m.guide = buildGuide(nvram, *m.mac)
+		return nil
+	}
+	m.guideLoading = true
+	return func() tea.Msg {
+		mac, err := detectMac(m.runner)
+		return guideMsg{nvram: nvram, mac: mac, err: err}
+	}
+}
+
+func (m *Model) updateGuide(msg guideMsg) {
+	m.guideLoading = false
+	if msg.err != nil {
+		m.guide = &resetGuide{
+			title: "Couldn't detect this Mac",
+			intro: msg.err.Error() + ". Apple's guide covers every model.",
+			more:  guideArticle(msg.nvram),
+		}
+		return
+	}
+	m.mac = &msg.mac
+	m.guide = buildGuide(msg.nvram, msg.mac)
+}
+
+// handleGuideKey closes the guide
+func (m *Model) handleGuideKey(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc", "enter", "q", "s", "n":
+		m.guide = nil
+	}
+}
+
+func guideArticle(nvram bool) string {
+	if nvram {
+		return "support.apple.com/102603"
+	}
+	return "support.apple.com/102605"
+}
+
+// buildGuide returns the reset steps for mac
+func buildGuide(nvram bool, mac macModel) *resetGuide {
+	g := &resetGuide{more: guideArticle(nvram)}
+	if nvram {
+		g.title = "Reset NVRAM"
+	} else {
+		g.title = "Reset SMC"
+	}
+	g.title += " · " + mac.Identifier + " (" + mac.Kind.String() + ")"
+
+	switch {
+	case nvram && mac.Kind == appleSilicon:
+		g.intro = "Apple silicon Macs have no key combination for this: NVRAM is checked at every " +
+			"startup and reset automatically if something is wrong with it."
+		g.steps = []string{
+			"Shut down the Mac (Apple menu › Shut Down, not Restart)",
+			"Wait about 30 seconds",
+			"Turn it back on",
+			"To clear a single variable instead: sudo nvram -d <name> (list them with nvram -p)",
+		}
+	case nvram:
+		g.intro = "Turn off any firmware password first, or the key combination is ignored."
+		g.steps = []string{
+			"Shut down the Mac",
+			"Turn it on and immediately press and hold Option-Command-P-R",
+			"Keep holding for about 20 seconds (on Macs that play a startup sound, until the second chime)",
+			"Release the keys and let the Mac finish starting up",
+			"Check Startup Disk, display, sound and time zone settings afterwards",
+		}
+	case mac.Kind == appleSilicon:
+		g.intro = "Apple silicon Macs have no SMC to reset: the chip handles power, battery and " +
+			"fans itself, and a full shutdown does what an SMC reset did."
+		g.steps = []string{
+			"Shut down the Mac (Apple menu › Shut Down)",
+			"Wait about 30 seconds",
+			"Turn it back on; on a notebook, plug in the power adapter first",
+		}
+	case mac.Kind == intelT2Laptop:
+		g.intro = "First try shutting down and holding the power button for 10 seconds. If that doesn't help:"
+		g.steps = []string{
+			"Shut down the Mac",
+			"Press and hold Control and Option on the left and Shift on the right for 7 seconds",
+			"Keep holding them, then also press and hold the power button for another 7 seconds",
+			"Release all keys, wait a few seconds, then turn the Mac on",
+		}
+	case mac.Kind == intelLaptop:
+		g.intro = "For notebooks with a built-in battery, i.e. every MacBook since 2009."
+		g.steps = []string{
+			"Shut down the Mac and connect the power adapter",
+			"Press and hold Shift-Control-Option on the left side and the power button together",
+			"Hold them for 10 seconds, then release all keys",
+			"Turn the Mac on",
+		}
+	default:
+		g.intro = "Desktop Macs reset the SMC by losing power."
+		g.steps = []string{
+			"Shut down the Mac and unplug the power cord",
+			"Wait 15 seconds, then plug the cord back in",
+			"Wait another 5 seconds, then turn the Mac on",
+		}
+	}
+	return g
+}
+
+// renderGuide shows the open guide in place of the Maintenance tab
+func (m *Model) renderGuide() string {
+	titleStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	stepStyle := lipgloss.NewStyle().Foreground(components.ColorWarning)
+	mutedStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	boxStyle := lipgloss.NewStyle().
+		Width(min(m.width-4, 90)).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(components.ColorPrimary).
+		Padding(0, 2)
+
+	g := m.guide
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(g.title) + "\n\n")
+	if g.intro != "" {
+		b.WriteString(textStyle.Render(g.intro) + "\n\n")
+	}
+	for i, step := range g.steps {
+		b.WriteString(stepStyle.Render(fmt.Sprintf("%d.", i+1)) + " " + step + "\n")
+	}
+	if len(g.steps) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(mutedStyle.Render("More: "+g.more) + "\n")
+	b.WriteString(mutedStyle.Render("Esc/Enter close"))
+	return boxStyle.Render(b.String())
+}
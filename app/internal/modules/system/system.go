@@ -4,14 +4,27 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/caioricciuti/dev-cockpit/internal/battery"
 	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/coreaudio"
+	"github.com/caioricciuti/dev-cockpit/internal/cpusample"
+	"github.com/caioricciuti/dev-cockpit/internal/defaults"
+	"github.com/caioricciuti/dev-cockpit/internal/export"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/scheduler"
+	"github.com/caioricciuti/dev-cockpit/internal/thermal"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/help"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/palette"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/mem"
@@ -61,29 +74,135 @@ type Model struct {
 	activeTab  int
 	tabs       []string
 	lastUpdate time.Time
+	refresh    time.Duration // between system info refreshes; + and - change it
+	runner     runner.Runner
+
+	// Battery charge limit (Maintenance tab)
+	chargeChecked    bool
+	chargeCapability tools.Capability
+	chargeLimit      int
+	chargeErr        error
+	chargeEditing    bool
+	chargeInput      string
+	chargeBusy       bool
+	chargeMessage    string
+
+	// SMC and NVRAM reset guides (Maintenance tab)
+	mac          *macModel // detected when a guide first opens
+	guide        *resetGuide
+	guideLoading bool
+
+	// Clock drift and network time (Maintenance tab)
+	clockChecked     bool
+	clockBusy        bool
+	clockNetworkTime string
+	clockOffset      time.Duration
+	clockErr         error
+	clockMessage     string
+
+	// Displays tab
+	displaysChecked   bool
+	displaysLoading   bool
+	displaysBusy      bool
+	displayCapability tools.Capability
+	displays          []Display
+	arrangement       []string // displayplacer arguments for the current arrangement
+	displayLayouts    []DisplayLayout
+	displaysErr       error
+	displaysMessage   string
+	displayCursor     int // over the displays, then the saved layouts
+	modePicking       bool
+	modeCursor        int
+	layoutNaming      bool
+	layoutName        string
+	layoutsPath       string // saved layouts, "" without a config
+
+	// Audio tab
+	audioChecked    bool
+	audioBusy       bool
+	audioDevices    []coreaudio.Device // output devices
+	audioErr        error
+	audioCursor     int
+	audioMessage    string
+	ratePicking     bool
+	rateCursor      int
+	micVolume       int
+	micRestore      int // input volume before muting
+	micErr          error
+	meterOn         bool
+	meterCapability tools.Capability
+	meterLevel      float64 // dBFS
+	meterErr        error
+
+	// Capture tab
+	captureChecked bool
+	captureBusy    bool
+	captureValues  []defaults.Value
+	captureSaved   []defaults.Value // as read when the tab first opened, for revert
+	capturePending map[int]string   // edits not applied yet, by setting
+	captureErr     error
+	captureCursor  int // over the settings, then the timed recording
+	captureEditing bool
+	captureInput   string
+	captureMessage string
+	recordLength   int // index into recordLengths
+	recordingUntil time.Time
+
+	// Region tab
+	regionChecked bool
+	regionBusy    bool
+	region        *regionInfo
+	regionErr     error
+	regionCursor  int
+	regionEditing bool
+	regionInput   string
+	regionMessage string
+
+	// Printers tab
+	printersChecked bool
+	printersLoaded  bool
+	printersBusy    bool
+	printers        []Printer
+	printJobs       []PrintJob
+	cupsWeb         bool
+	printersErr     error
+	printerCursor   int
+	printersMessage string
 }
 
 // New creates a new system module
 func New(cfg *config.Config) *Model {
-	return &Model{
-		config:  cfg,
-		tabs:    []string{"Overview", "Hardware", "Performance", "Maintenance"},
-		loading: true,
+	m := &Model{
+		config:       cfg,
+		tabs:         []string{"Overview", "Hardware", "Performance", "Maintenance", "Displays", "Audio", "Capture", "Region", "Printers"},
+		loading:      true,
+		runner:       runner.Default,
+		recordLength: 1,
+		refresh:      5 * time.Second,
 	}
+	if cfg != nil {
+		m.layoutsPath = filepath.Join(cfg.Dir(), displayLayoutsFile)
+	}
+	return m
 }
 
-// Init initializes the module
+// Init initializes the module. System info is refreshed by the scheduler.
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(
-		m.fetchSystemInfo(),
-		m.tickCmd(),
-	)
+	if !m.chargeChecked {
+		return m.readChargeLimit()
+	}
+	return nil
 }
 
-func (m *Model) tickCmd() tea.Cmd {
-	return tea.Tick(time.Second*5, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+// Schedule refreshes system info every few seconds, and samples the CPU
+// every second, while the tab is shown, and samples the microphone while
+// the level meter is on
+func (m *Model) Schedule() []scheduler.Task {
+	return []scheduler.Task{
+		cpusample.Default.Task(),
+		{Name: "info", Every: m.refresh, Jitter: time.Second, Run: m.fetchSystemInfo},
+		{Name: "input-level", Every: 500 * time.Millisecond, Run: m.meterTask},
+	}
 }
 
 // Update handles messages
@@ -93,7 +212,42 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case events.Nav:
+		m.navigate(msg)
+
 	case tea.KeyMsg:
+		if m.chargeEditing {
+			return m, m.handleChargeKey(msg)
+		}
+		if m.guide != nil {
+			m.handleGuideKey(msg)
+			return m, nil
+		}
+		if m.activeTab == displaysTab {
+			if cmd, ok := m.handleDisplayKey(msg); ok {
+				return m, cmd
+			}
+		}
+		if m.activeTab == audioTab {
+			if cmd, ok := m.handleAudioKey(msg); ok {
+				return m, cmd
+			}
+		}
+		if m.activeTab == captureTab {
+			if cmd, ok := m.handleCaptureKey(msg); ok {
+				return m, cmd
+			}
+		}
+		if m.activeTab == regionTab {
+			if cmd, ok := m.handleRegionKey(msg); ok {
+				return m, cmd
+			}
+		}
+		if m.activeTab == printersTab {
+			if cmd, ok := m.handlePrinterKey(msg); ok {
+				return m, cmd
+			}
+		}
 		switch msg.String() {
 		case "tab", "l":
 			m.activeTab = (m.activeTab + 1) % len(m.tabs)
@@ -103,7 +257,14 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 				m.activeTab = len(m.tabs) - 1
 			}
 		case "r":
+			if m.activeTab == 3 && !m.clockBusy { // Maintenance tab
+				return m, tea.Batch(m.fetchSystemInfo(), m.checkClock())
+			}
 			return m, m.fetchSystemInfo()
+		case "+", "=":
+			return m, m.setRefresh(scheduler.Step(refreshSteps, m.refresh, true))
+		case "-":
+			return m, m.setRefresh(scheduler.Step(refreshSteps, m.refresh, false))
 		case "1":
 			m.activeTab = 0
 		case "2":
@@ -112,6 +273,16 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			m.activeTab = 2
 		case "4":
 			m.activeTab = 3
+		case "5":
+			m.activeTab = displaysTab
+		case "6":
+			m.activeTab = audioTab
+		case "7":
+			m.activeTab = captureTab
+		case "8":
+			m.activeTab = regionTab
+		case "9":
+			m.activeTab = printersTab
 
 		// Quick actions based on tab
 		case "d":
@@ -120,11 +291,19 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 			}
 		case "s":
 			if m.activeTab == 3 { // Maintenance tab
-				return m, m.showSMCResetInstructions()
+				return m, m.openResetGuide(false)
 			}
 		case "n":
 			if m.activeTab == 3 { // Maintenance tab
-				return m, m.showNVRAMResetInstructions()
+				return m, m.openResetGuide(true)
+			}
+		case "c":
+			if m.activeTab == 3 { // Maintenance tab
+				return m, m.handleChargeKey(msg)
+			}
+		case "t":
+			if m.activeTab == 3 { // Maintenance tab
+				return m, m.syncClock()
 			}
 		}
 
@@ -133,13 +312,87 @@ func (m *Model) Update(msg tea.Msg) (interface{}, tea.Cmd) {
 		m.loading = false
 		m.lastUpdate = time.Now()
 
-	case tickMsg:
-		return m, m.fetchSystemInfo()
+	case guideMsg:
+		m.updateGuide(msg)
+
+	case clockMsg, clockSyncedMsg:
+		return m, m.updateClock(msg)
+
+	case chargeLimitMsg, chargeLimitSetMsg:
+		return m, m.updateChargeLimit(msg)
+
+	case displaysMsg, displayActionMsg:
+		return m, m.updateDisplays(msg)
+
+	case audioMsg, inputLevelMsg, audioActionMsg:
+		return m, m.updateAudio(msg)
+
+	case captureMsg, captureAppliedMsg, recordingDoneMsg:
+		return m, m.updateCapture(msg)
+
+	case regionMsg, regionAppliedMsg:
+		return m, m.updateRegion(msg)
+
+	case printersMsg, cancelJobsMsg, printerActionMsg:
+		return m, m.updatePrinters(msg)
 	}
 
+	// The clock, displays, audio devices, screenshot and region settings
+	// and print queues are read the first time their tab opens
+	if m.activeTab == 3 && !m.clockChecked {
+		return m, m.checkClock()
+	}
+	if m.activeTab == displaysTab && !m.displaysChecked {
+		return m, m.scanDisplays()
+	}
+	if m.activeTab == audioTab && !m.audioChecked {
+		return m, m.scanAudio()
+	}
+	if m.activeTab == captureTab && !m.captureChecked {
+		return m, m.scanCapture()
+	}
+	if m.activeTab == regionTab && !m.regionChecked {
+		return m, m.scanRegion()
+	}
+	if m.activeTab == printersTab && !m.printersChecked {
+		return m, m.scanPrinters()
+	}
 	return m, nil
 }
 
+// navigate moves through the lists of the Displays, Audio, Capture,
+// Region and Printers tabs, or the picker open over them
+func (m *Model) navigate(nav events.Nav) {
+	switch m.activeTab {
+	case displaysTab:
+		switch {
+		case m.modePicking:
+			m.modeCursor = nav.Move(m.modeCursor, len(m.displays[m.displayCursor].Modes))
+		case !m.layoutNaming && !m.displaysBusy && !m.displaysLoading:
+			m.displayCursor = nav.Move(m.displayCursor, len(m.displays)+len(m.displayLayouts))
+		}
+	case audioTab:
+		switch {
+		case m.ratePicking:
+			m.rateCursor = nav.Move(m.rateCursor, len(m.audioDevices[m.audioCursor].Rates))
+		case !m.audioBusy:
+			m.audioCursor = nav.Move(m.audioCursor, len(m.audioDevices))
+		}
+	case captureTab:
+		if !m.captureEditing && !m.captureBusy {
+			m.captureCursor = nav.Move(m.captureCursor, len(captureSettings)+1)
+		}
+	case regionTab:
+		if !m.regionEditing && !m.regionBusy {
+			m.regionCursor = nav.Move(m.regionCursor, regionRows)
+		}
+	case printersTab:
+		if !m.printersBusy {
+			m.printerCursor = nav.Move(m.printerCursor, len(m.printers))
+		}
+	}
+}
+
 // View renders the module
 func (m *Model) View() string {
 	if m.width == 0 || m.height == 0 {
@@ -173,6 +426,19 @@ func (m *Model) View() string {
 		content = m.renderPerformance()
 	case 3:
 		content = m.renderMaintenance()
+		if m.guide != nil {
+			content = m.renderGuide()
+		}
+	case displaysTab:
+		content = m.renderDisplays()
+	case audioTab:
+		content = m.renderAudio()
+	case captureTab:
+		content = m.renderCapture()
+	case regionTab:
+		content = m.renderRegion()
+	case printersTab:
+		content = m.renderPrinters()
 	}
 
 	// Apply viewport to prevent overflow
@@ -194,11 +460,11 @@ func (m *Model) renderHeader() string {
 
 	activeTabStyle := tabStyle.Copy().
 		Bold(true).
-		Foreground(lipgloss.Color("#000")).
-		Background(lipgloss.Color("#00D9FF"))
+		Foreground(components.ColorOverlay).
+		Background(components.ColorPrimary)
 
 	inactiveTabStyle := tabStyle.Copy().
-		Foreground(lipgloss.Color("#888"))
+		Foreground(components.ColorSubtle)
 
 	var tabs []string
 	for i, tab := range m.tabs {
@@ -209,11 +475,9 @@ func (m *Model) renderHeader() string {
 		}
 	}
 
-	tabBar := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
-
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		MarginBottom(1)
 
 	// Use safe width for separator (accounting for margins)
@@ -222,6 +486,18 @@ func (m *Model) renderHeader() string {
 		separatorWidth = 40
 	}
 
+	// Narrow terminals show the tabs around the active one, with arrows
+	// where more are hidden
+	start, end := fitTabs(tabs, m.activeTab, separatorWidth)
+	shown := tabs[start:end]
+	if start > 0 {
+		shown = append([]string{inactiveTabStyle.Render("‹")}, shown...)
+	}
+	if end < len(tabs) {
+		shown = append(shown, inactiveTabStyle.Render("›"))
+	}
+	tabBar := lipgloss.JoinHorizontal(lipgloss.Top, shown...)
+
 	return lipgloss.JoinVertical(
 		lipgloss.Top,
 		titleStyle.Render("🖥️  SYSTEM INFORMATION"),
@@ -230,22 +506,69 @@ func (m *Model) renderHeader() string {
 	)
 }
 
+// fitTabs returns the range of rendered tabs around active that fits in
+// width, leaving room for the arrows when some don't fit
+func fitTabs(tabs []string, active, width int) (start, end int) {
+	total := 0
+	for _, tab := range tabs {
+		total += lipgloss.Width(tab)
+	}
+	if total <= width {
+		return 0, len(tabs)
+	}
+
+	arrow := lipgloss.Width("‹") + 4 // with the tab padding
+	width -= 2 * arrow
+	start, end = active, active+1
+	used := lipgloss.Width(tabs[active])
+	for {
+		grew := false
+		if end < len(tabs) && used+lipgloss.Width(tabs[end]) <= width {
+			used += lipgloss.Width(tabs[end])
+			end++
+			grew = true
+		}
+		if start > 0 && used+lipgloss.Width(tabs[start-1]) <= width {
+			start--
+			used += lipgloss.Width(tabs[start])
+			grew = true
+		}
+		if !grew {
+			return start, end
+		}
+	}
+}
+
+// refreshSteps are the delays between refreshes + and - step through
+var refreshSteps = []time.Duration{2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second, time.Minute}
+
+// setRefresh refreshes system info every every from now on
+func (m *Model) setRefresh(every time.Duration) tea.Cmd {
+	if every == m.refresh {
+		return nil
+	}
+	m.refresh = every
+	return scheduler.Reschedule
+}
+
 func (m *Model) renderFooter() string {
 	if m.width == 0 {
 		return ""
 	}
 
 	help := []string{
-		"1-4: Switch Views",
+		"1-9: Switch Views",
 		"Tab/Shift+Tab: Cycle Views",
-		"R: Refresh Snapshot",
+		fmt.Sprintf("R: Refresh (every %v, +/-)", m.refresh),
 		"D: Disk First Aid",
 		"S: SMC Guide",
 		"N: NVRAM Guide",
+		"C: Charge Limit",
+		"T: Sync Clock",
 	}
 
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666")).
+		Foreground(components.ColorMuted).
 		MarginTop(1).
 		Render(strings.Join(help, "  |  "))
 }
@@ -254,14 +577,14 @@ func (m *Model) renderOverview() string {
 	style := lipgloss.NewStyle().Padding(1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888")).
+		Foreground(components.ColorSubtle).
 		Width(20)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFF"))
+		Foreground(components.ColorBright)
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	content := strings.Builder{}
@@ -303,7 +626,7 @@ func (m *Model) renderOverview() string {
 
 	// Last update
 	content.WriteString(fmt.Sprintf("\n%s %s",
-		lipgloss.NewStyle().Foreground(lipgloss.Color("#666")).Render("Last updated:"),
+		lipgloss.NewStyle().Foreground(components.ColorMuted).Render("Last updated:"),
 		m.lastUpdate.Format("15:04:05")))
 
 	return style.Render(content.String())
@@ -313,14 +636,14 @@ func (m *Model) renderHardware() string {
 	style := lipgloss.NewStyle().Padding(1)
 
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#888")).
+		Foreground(components.ColorSubtle).
 		Width(20)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFF"))
+		Foreground(components.ColorBright)
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	content := strings.Builder{}
@@ -362,7 +685,7 @@ func (m *Model) renderPerformance() string {
 	content := strings.Builder{}
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	// CPU Usage bar
@@ -415,11 +738,11 @@ func (m *Model) renderMaintenance() string {
 	style := lipgloss.NewStyle().Padding(1)
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00D9FF")).
+		Foreground(components.ColorPrimary).
 		Bold(true)
 
 	actionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFA500"))
+		Foreground(components.ColorWarning)
 
 	content := strings.Builder{}
 
@@ -428,26 +751,36 @@ func (m *Model) renderMaintenance() string {
 	// Quick Actions
 	content.WriteString(highlightStyle.Render("Quick Actions") + "\n")
 	content.WriteString(actionStyle.Render("[D]") + " Run Disk Utility First Aid\n")
-	content.WriteString(actionStyle.Render("[S]") + " SMC Reset Instructions\n")
-	content.WriteString(actionStyle.Render("[N]") + " NVRAM Reset Instructions\n")
+	content.WriteString(actionStyle.Render("[S]") + " SMC Reset Guide for this Mac\n")
+	content.WriteString(actionStyle.Render("[N]") + " NVRAM Reset Guide for this Mac\n")
+	if m.guideLoading {
+		content.WriteString("    Detecting this Mac...\n")
+	}
+	content.WriteString(m.renderChargeLimit())
+	content.WriteString(actionStyle.Render("[T]") + " Sync Clock with " + timeServer + "\n")
+	if m.clockMessage != "" {
+		content.WriteString("    " + m.clockMessage + "\n")
+	}
 	content.WriteString(actionStyle.Render("[R]") + " Refresh System Info\n\n")
 
 	// Maintenance Tasks
 	content.WriteString(highlightStyle.Render("Recommended Maintenance") + "\n")
 
+	clock, clockColor := m.clockStatus()
 	tasks := []struct {
 		task   string
 		status string
-		color  string
+		color  lipgloss.AdaptiveColor
 	}{
-		{"macOS Updates", m.checkMacOSUpdates(), "#FFA500"},
-		{"Disk Verification", "Press [D] to run", "#888"},
+		{"macOS Updates", m.checkMacOSUpdates(), components.ColorWarning},
+		{"Disk Verification", "Press [D] to run", components.ColorSubtle},
 		{"Storage Optimization", m.getStorageStatus(), m.getStorageStatusColor()},
-		{"Battery Health", m.info.BatteryHealth, "#0F0"},
+		{"Battery Health", m.info.BatteryHealth, components.ColorSuccess},
+		{"Clock", clock, clockColor},
 	}
 
 	for _, task := range tasks {
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(task.color))
+		statusStyle := lipgloss.NewStyle().Foreground(task.color)
 		content.WriteString(fmt.Sprintf("  • %-25s %s\n", task.task, statusStyle.Render(task.status)))
 	}
 
@@ -486,19 +819,19 @@ func (m *Model) renderProgressBar(width int, percent float64) string {
 	empty := width - filled
 
 	// Color based on percentage
-	var color string
+	var color lipgloss.AdaptiveColor
 	if percent < 0.5 {
-		color = "#0F0" // Green
+		color = components.ColorSuccess
 	} else if percent < 0.8 {
-		color = "#FFA500" // Orange
+		color = components.ColorWarning
 	} else {
-		color = "#F00" // Red
+		color = components.ColorError
 	}
 
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", empty)
 
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color(color)).
+		Foreground(color).
 		Render(fmt.Sprintf("[%s]", bar))
 }
 
@@ -507,13 +840,117 @@ func (m *Model) Title() string {
 	return "System"
 }
 
+// Help describes the system keys
+func (m *Model) Help() help.Help {
+	return help.Help{
+		Title:       "SYSTEM",
+		Description: "Hardware, OS and storage details, maintenance guides, displays, audio, screenshots, time zone and region settings, and print queues.",
+		Sections: []help.Section{
+			{Title: "Views", Bindings: []help.Binding{
+				{Key: "1-9", Desc: "Switch views"},
+				{Key: "Tab / Shift+Tab", Desc: "Cycle views"},
+				{Key: "R", Desc: "Refresh snapshot"},
+				{Key: "+ / -", Desc: "Refresh more or less often (2s to 1m)"},
+			}},
+			{Title: "Maintenance view", Bindings: []help.Binding{
+				{Key: "D", Desc: "Run Disk Utility First Aid"},
+				{Key: "S", Desc: "SMC reset steps for this Mac's model"},
+				{Key: "N", Desc: "NVRAM reset steps for this Mac's model"},
+				{Key: "C", Desc: "Set the battery charge limit (bclm or batt)"},
+				{Key: "T", Desc: "Sync the clock with time.apple.com, turning automatic time on if it's off"},
+			}},
+			{Title: "Displays view", Bindings: []help.Binding{
+				{Key: "↑/↓", Desc: "Select a display or saved layout"},
+				{Key: "Enter", Desc: "Change the display's resolution and refresh rate, or apply the layout"},
+				{Key: "M", Desc: "Toggle mirroring"},
+				{Key: "D", Desc: "Detect displays"},
+				{Key: "S", Desc: "Save the arrangement as a layout (e.g. docked)"},
+				{Key: "X", Desc: "Delete the selected layout"},
+				{Key: "I", Desc: "Install displayplacer with Homebrew"},
+			}},
+			{Title: "Audio view", Bindings: []help.Binding{
+				{Key: "M", Desc: "Mute or unmute the microphone for every app"},
+				{Key: "I", Desc: "Show the input level meter (needs sox)"},
+				{Key: "↑/↓", Desc: "Select an output device"},
+				{Key: "Enter", Desc: "Change its sample rate"},
+			}},
+			{Title: "Capture view", Bindings: []help.Binding{
+				{Key: "Enter", Desc: "Change the selected screenshot setting or recording length"},
+				{Key: "A", Desc: "Apply the changes"},
+				{Key: "U", Desc: "Discard changes not applied, or revert to the settings you started with"},
+				{Key: "V", Desc: "Start a timed screen recording"},
+			}},
+			{Title: "Region view", Bindings: []help.Binding{
+				{Key: "Enter", Desc: "Change the time zone or locale, toggle the automatic time zone, or cycle the first day of the week"},
+			}},
+			{Title: "Printers view", Bindings: []help.Binding{
+				{Key: "↑/↓", Desc: "Select a printer"},
+				{Key: "X", Desc: "Cancel all of its jobs"},
+				{Key: "E", Desc: "Resume it after CUPS paused it"},
+				{Key: "W", Desc: "Turn the CUPS web interface (localhost:631) on or off"},
+			}},
+		},
+	}
+}
+
 // HasOpenModal returns true if the module has an open modal/dialog
 func (m *Model) HasOpenModal() bool {
-	return false
+	return m.chargeEditing || m.guide != nil || m.modePicking || m.layoutNaming || m.ratePicking || m.captureEditing || m.regionEditing
+}
+
+// Export lists the system snapshot
+func (m *Model) Export() string {
+	if m.loading {
+		return ""
+	}
+	info := m.info
+	power := "Battery"
+	if info.PowerAdapter {
+		power = "AC adapter"
+	}
+	rows := [][]string{
+		{"Model", info.Model},
+		{"Chip", info.Chip},
+		{"Architecture", info.Architecture},
+		{"CPU cores", fmt.Sprint(info.CPUCores)},
+		{"Memory", fmt.Sprintf("%d GB (%.1f%% used)", info.MemoryGB, info.MemoryUsage)},
+		{"macOS", fmt.Sprintf("%s (%s)", info.OSVersion, info.BuildNumber)},
+		{"Hostname", info.Hostname},
+		{"Boot time", info.BootTime.Format("2006-01-02 15:04")},
+		{"Uptime", formatDuration(info.Uptime)},
+		{"CPU usage", fmt.Sprintf("%.1f%%", info.CPUUsage)},
+		{"Disk", fmt.Sprintf("%.1f GB free of %.1f GB (%.1f%% used)", float64(info.DiskFree)/1024/1024/1024, float64(info.DiskTotal)/1024/1024/1024, info.DiskUsagePercent)},
+		{"Battery", fmt.Sprintf("%d%%, %d cycles, %s", info.BatteryLevel, info.BatteryCycles, info.BatteryHealth)},
+		{"Power source", power},
+	}
+	if m.chargeCapability.Available() && m.chargeErr == nil {
+		rows = append(rows, []string{"Charge limit", fmt.Sprintf("%d%% (%s)", m.chargeLimit, m.chargeCapability.Tool)})
+	}
+	if m.clockChecked && !m.clockBusy && m.clockErr == nil {
+		rows = append(rows, []string{"Clock offset", fmt.Sprintf("%+.3fs from %s", m.clockOffset.Seconds(), timeServer)})
+		if m.clockNetworkTime != "" {
+			rows = append(rows, []string{"Network time", m.clockNetworkTime})
+		}
+	}
+	return export.Table([]string{"Item", "Value"}, rows)
+}
+
+// Commands returns the actions this module offers in the command palette
+func (m *Model) Commands() []palette.Command {
+	commands := make([]palette.Command, 0, len(m.tabs))
+	for i, tab := range m.tabs {
+		commands = append(commands, palette.Command{
+			Title: "System " + tab,
+			Hint:  "Open the " + tab + " tab",
+			Msg:   palette.Key(fmt.Sprint(i + 1)),
+		})
+	}
+	return commands
 }
 
 // Helper functions
 func (m *Model) fetchSystemInfo() tea.Cmd {
+	r := m.runner
 	return func() tea.Msg {
 		info := SystemInfo{}
 
@@ -562,9 +999,14 @@ func (m *Model) fetchSystemInfo() tea.Cmd {
 			info.BootTime = time.Unix(int64(hostInfo.BootTime), 0)
 		}
 
-		// Get CPU usage
-		if cpuPercent, err := cpu.Percent(time.Second, false); err == nil && len(cpuPercent) > 0 {
-			info.CPUUsage = cpuPercent[0]
+		// Get CPU usage, as of the sampler's last reading
+		info.CPUUsage, _ = cpusample.Default.Total()
+
+		// Get temperature and fan speed, when smctemp or a sudo session
+		// can read them
+		if reading, err := thermal.Read(r); err == nil {
+			info.CPUTemperature = reading.CPU
+			info.FanSpeed = reading.FanRPM
 		}
 
 		// Get disk usage
@@ -574,52 +1016,12 @@ func (m *Model) fetchSystemInfo() tea.Cmd {
 			info.DiskTotal = diskStat.Total
 		}
 
-		// Get battery info (using pmset)
-		if output, err := exec.Command("pmset", "-g", "batt").Output(); err == nil {
-			batteryStr := string(output)
-			// Parse battery level
-			if strings.Contains(batteryStr, "%") {
-				parts := strings.Split(batteryStr, "%")
-				if len(parts) > 0 {
-					// Extract percentage from string like "100%"
-					for i := len(parts[0]) - 1; i >= 0; i-- {
-						if parts[0][i] < '0' || parts[0][i] > '9' {
-							if i < len(parts[0])-1 {
-								fmt.Sscanf(parts[0][i+1:], "%d", &info.BatteryLevel)
-							}
-							break
-						}
-					}
-				}
-			}
-
-			// Check if AC Power
-			info.PowerAdapter = strings.Contains(batteryStr, "AC Power")
-
-			// Simple health status
-			if strings.Contains(batteryStr, "Normal") {
-				info.BatteryHealth = "Normal"
-			} else if strings.Contains(batteryStr, "Service") {
-				info.BatteryHealth = "Service Recommended"
-			} else {
-				info.BatteryHealth = "Good"
-			}
-		}
-
-		// Get battery cycle count
-		if output, err := exec.Command("system_profiler", "SPPowerDataType").Output(); err == nil {
-			powerStr := string(output)
-			if strings.Contains(powerStr, "Cycle Count:") {
-				lines := strings.Split(powerStr, "\n")
-				for _, line := range lines {
-					if strings.Contains(line, "Cycle Count:") {
-						parts := strings.Split(line, ":")
-						if len(parts) > 1 {
-							fmt.Sscanf(strings.TrimSpace(parts[1]), "%d", &info.BatteryCycles)
-						}
-					}
-				}
-			}
+		// Get battery info from the I/O Registry; desktops have none
+		if status, err := battery.Read(r); err == nil {
+			info.BatteryLevel = status.Percent
+			info.BatteryCycles = status.Cycles
+			info.BatteryHealth = fmt.Sprintf("%s (%.0f%% of design capacity)", status.Condition(), status.Health())
+			info.PowerAdapter = status.External
 		}
 
 		return systemInfoMsg{info: info}
@@ -640,13 +1042,13 @@ func (m *Model) getStorageStatus() string {
 	return "Good"
 }
 
-func (m *Model) getStorageStatusColor() string {
+func (m *Model) getStorageStatusColor() lipgloss.AdaptiveColor {
 	if m.info.DiskUsagePercent > 90 {
-		return "#F00"
+		return components.ColorError
 	} else if m.info.DiskUsagePercent > 80 {
-		return "#FFA500"
+		return components.ColorWarning
 	}
-	return "#0F0"
+	return components.ColorSuccess
 }
 
 func (m *Model) runDiskUtility() tea.Cmd {
@@ -656,20 +1058,6 @@ func (m *Model) runDiskUtility() tea.Cmd {
 	}
 }
 
-func (m *Model) showSMCResetInstructions() tea.Cmd {
-	return func() tea.Msg {
-		// This would show SMC reset instructions
-		return nil
-	}
-}
-
-func (m *Model) showNVRAMResetInstructions() tea.Cmd {
-	return func() tea.Msg {
-		// This would show NVRAM reset instructions
-		return nil
-	}
-}
-
 func formatDuration(d time.Duration) string {
 	days := int(d.Hours()) / 24
 	hours := int(d.Hours()) % 24
@@ -685,8 +1073,6 @@ func formatDuration(d time.Duration) string {
 }
 
 // Message types
-type tickMsg time.Time
-
 type systemInfoMsg struct {
 	info SystemInfo
 }
@@ -0,0 +1,140 @@
+package system
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/golden"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snapshotModel returns the module with fixed system info instead of host data
+func snapshotModel(t *testing.T) *Model {
+	t.Helper()
+	m := New(nil)
+	m.Update(systemInfoMsg{info: SystemInfo{
+		Model:            "MacBook Pro",
+		Chip:             "Apple M2 Pro",
+		CPUCores:         10,
+		MemoryGB:         32,
+		Architecture:     "arm64",
+		OSVersion:        "14.5",
+		BuildNumber:      "23F79",
+		Hostname:         "devbox.local",
+		Uptime:           49*time.Hour + 15*time.Minute,
+		BootTime:         time.Date(2024, 6, 29, 8, 15, 0, 0, time.UTC),
+		DiskUsagePercent: 71.2,
+		DiskFree:         143 * 1024 * 1024 * 1024,
+		DiskTotal:        494 * 1024 * 1024 * 1024,
+		CPUUsage:         34,
+		MemoryUsage:      62.5,
+		BatteryLevel:     87,
+		BatteryCycles:    212,
+		BatteryHealth:    "Normal",
+		PowerAdapter:     true,
+	}})
+	m.lastUpdate = time.Date(2024, 7, 1, 9, 30, 0, 0, time.UTC)
+	fake := runner.NewFake().Set("/usr/bin/bclm read", "80\n", nil)
+	if err := fake.SetFixture("/usr/bin/displayplacer list", filepath.Join("testdata", "displayplacer_list.txt")); err != nil {
+		t.Fatal(err)
+	}
+	fake.Set("osascript -e input volume of (get volume settings)", "68\n", nil)
+	stubAudioDevices(t)
+	captureDefaults(fake)
+	regionDefaults(fake)
+	printerFixtures(t, fake)
+	m.runner = fake
+	m.Update(m.readChargeLimit()())
+	m.Update(m.scanDisplays()())
+	m.Update(m.scanAudio()())
+	m.Update(m.scanCapture()())
+	m.Update(m.scanRegion()())
+	m.Update(m.scanPrinters()())
+	return m
+}
+
+func TestViewSnapshots(t *testing.T) {
+	for _, size := range golden.Sizes {
+		for tab, name := range New(nil).tabs {
+			t.Run(size.String()+"/"+name, func(t *testing.T) {
+				m := snapshotModel(t)
+				m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+				m.activeTab = tab
+				golden.RequireEqual(t, m.View())
+			})
+		}
+	}
+}
+
+func TestResetGuides(t *testing.T) {
+	exit1 := errors.New("exit status 1")
+	tests := []struct {
+		name  string
+		fake  *runner.Fake
+		nvram bool
+		want  []string
+	}{
+		{
+			name: "Apple silicon NVRAM",
+			fake: runner.NewFake().
+				Set("sysctl -n hw.model", "Mac14,9\n", nil).
+				Set("sysctl -n hw.optional.arm64", "1\n", nil),
+			nvram: true,
+			want:  []string{"Mac14,9 (Apple silicon)", "no key combination", "sudo nvram -d"},
+		},
+		{
+			name: "T2 notebook SMC",
+			fake: runner.NewFake().
+				Set("sysctl -n hw.model", "MacBookPro16,1\n", nil).
+				Set("sysctl -n hw.optional.arm64", "", exit1).
+				Set("system_profiler SPiBridgeDataType", "Controller Information:\n\n      Model Name: Apple T2 Security Chip\n", nil),
+			want: []string{"Intel notebook with T2 chip", "Shift on the right"},
+		},
+		{
+			name: "older iMac SMC",
+			fake: runner.NewFake().
+				Set("sysctl -n hw.model", "iMac18,3\n", nil).
+				Set("sysctl -n hw.optional.arm64", "", exit1).
+				Set("system_profiler SPiBridgeDataType", "", nil),
+			want: []string{"Intel desktop)", "unplug the power cord"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := snapshotModel(t)
+			m.runner = tt.fake
+			m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			m.activeTab = 3
+			key := "s"
+			if tt.nvram {
+				key = "n"
+			}
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+			if cmd == nil {
+				t.Fatal("the guide should detect the Mac first")
+			}
+			m.Update(cmd())
+			view := m.View()
+			for _, want := range tt.want {
+				if !strings.Contains(view, want) {
+					t.Errorf("guide lacks %q:\n%s", want, view)
+				}
+			}
+			if !m.HasOpenModal() {
+				t.Error("the guide should hold the keys while open")
+			}
+
+			m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			if m.guide != nil {
+				t.Fatal("Esc should close the guide")
+			}
+			if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}); cmd != nil || m.guide == nil {
+				t.Error("the Mac should be detected only once")
+			}
+		})
+	}
+}
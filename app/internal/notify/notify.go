@@ -0,0 +1,50 @@
+// Package notify posts native macOS notifications, through
+// terminal-notifier when it's installed and osascript otherwise.
+package notify
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+)
+
+// AppName is the notification title; the sender goes in the subtitle
+const AppName = "Dev Cockpit"
+
+var feature = tools.Feature{
+	Name: "Notifications",
+	Tools: []tools.Tool{
+		{Name: "terminal-notifier", Paths: []string{"/opt/homebrew/bin/terminal-notifier", "/usr/local/bin/terminal-notifier"}},
+		{Name: "osascript", Paths: []string{"/usr/bin/osascript"}},
+	},
+	Hint: "brew install terminal-notifier",
+}
+
+// Send posts a notification from subtitle (usually a module title) with
+// message as its body
+func Send(r runner.Runner, subtitle, message string) error {
+	c := tools.Resolve(r, feature)
+	if !c.Available() {
+		return errors.New(c.Reason)
+	}
+
+	var cmd *exec.Cmd
+	if c.Tool == "terminal-notifier" {
+		cmd = exec.Command(c.Path, "-title", AppName, "-subtitle", subtitle, "-message", message)
+	} else {
+		script := "display notification " + quote(message) + " with title " + quote(AppName) + " subtitle " + quote(subtitle)
+		cmd = exec.Command(c.Path, "-e", script)
+	}
+	_, err := r.CombinedOutput(cmd)
+	return err
+}
+
+// quote makes s an AppleScript string literal
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+func TestSendPrefersTerminalNotifier(t *testing.T) {
+	fake := runner.NewFake().Set("/usr/bin/terminal-notifier -title Dev Cockpit -subtitle Cleanup -message Cleaned 3 items", "", nil)
+	if err := Send(fake, "Cleanup", "Cleaned 3 items"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendFallsBackToOsascript(t *testing.T) {
+	fake := runner.NewFake().Missing("terminal-notifier").
+		Set(`/usr/bin/osascript -e display notification "Toggled \"web\"" with title "Dev Cockpit" subtitle "Docker"`, "", nil)
+	if err := Send(fake, "Docker", `Toggled "web"`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendWithoutNotifier(t *testing.T) {
+	fake := runner.NewFake().Missing("terminal-notifier", "osascript")
+	if err := Send(fake, "Docker", "Toggled web"); err == nil {
+		t.Error("want an error when no notifier is installed")
+	}
+}
@@ -0,0 +1,122 @@
+// Package opener opens paths outside the TUI: in Finder, a terminal or an
+// editor, using the commands configured under `open` in config.yaml.
+package opener
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// Target is where a path is opened
+type Target int
+
+const (
+	Finder Target = iota
+	Terminal
+	Editor
+)
+
+// Commands used when config.yaml leaves one empty
+const (
+	DefaultFileManager = "open {path}"
+	DefaultTerminal    = "open -a Terminal {path}"
+	DefaultEditor      = "code {path}"
+)
+
+// String returns the target name for status messages
+func (t Target) String() string {
+	switch t {
+	case Terminal:
+		return "Terminal"
+	case Editor:
+		return "editor"
+	default:
+		return "Finder"
+	}
+}
+
+// Command builds the command that opens path in target. The template is
+// split on spaces and "{path}" is replaced by path as a single argument;
+// a template without the placeholder gets path appended.
+func Command(cfg *config.Config, target Target, path string) *exec.Cmd {
+	fields := strings.Fields(template(cfg, target))
+
+	args := make([]string, 0, len(fields)+1)
+	replaced := false
+	for _, field := range fields {
+		if strings.Contains(field, "{path}") {
+			field = strings.ReplaceAll(field, "{path}", path)
+			replaced = true
+		}
+		args = append(args, field)
+	}
+	if !replaced {
+		args = append(args, path)
+	}
+
+	return exec.Command(args[0], args[1:]...)
+}
+
+// Open opens path in target. The configured command should launch an app
+// and return; it must not take over the terminal Dev Cockpit runs in.
+func Open(r runner.Runner, cfg *config.Config, target Target, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s does not exist", path)
+	}
+
+	cmd := Command(cfg, target, path)
+	if _, err := r.LookPath(cmd.Args[0]); err != nil {
+		return fmt.Errorf("%s not found (set open.%s in config.yaml)", cmd.Args[0], configKey(target))
+	}
+
+	output, err := r.CombinedOutput(cmd)
+	if err != nil {
+		logger.Warn("Failed to open %s in %s: %v (%s)", path, target, err, strings.TrimSpace(string(output)))
+		return fmt.Errorf("failed to open in %s: %v", target, err)
+	}
+	logger.Info("Opened %s in %s", path, target)
+	return nil
+}
+
+func template(cfg *config.Config, target Target) string {
+	var configured string
+	if cfg != nil {
+		switch target {
+		case Terminal:
+			configured = cfg.Open.Terminal
+		case Editor:
+			configured = cfg.Open.Editor
+		default:
+			configured = cfg.Open.FileManager
+		}
+	}
+	if strings.TrimSpace(configured) != "" {
+		return configured
+	}
+
+	switch target {
+	case Terminal:
+		return DefaultTerminal
+	case Editor:
+		return DefaultEditor
+	default:
+		return DefaultFileManager
+	}
+}
+
+func configKey(target Target) string {
+	switch target {
+	case Terminal:
+		return "terminal"
+	case Editor:
+		return "editor"
+	default:
+		return "file_manager"
+	}
+}
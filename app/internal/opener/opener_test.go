@@ -0,0 +1,57 @@
+package opener
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+func TestCommand(t *testing.T) {
+	cfg := &config.Config{Open: config.OpenConfig{Terminal: "open -a iTerm {path}", Editor: "zed"}}
+	path := "/Users/caio/My Projects/shop"
+
+	tests := []struct {
+		target Target
+		want   []string
+	}{
+		{Finder, []string{"open", path}},
+		{Terminal, []string{"open", "-a", "iTerm", path}},
+		{Editor, []string{"zed", path}},
+	}
+	for _, tt := range tests {
+		got := Command(cfg, tt.target, path).Args
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s args = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	fake := runner.NewFake().Set("code "+dir, "", nil)
+
+	if err := Open(fake, nil, Editor, dir); err != nil {
+		t.Fatal(err)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0] != "code "+dir {
+		t.Errorf("calls = %q", calls)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Open(runner.NewFake(), nil, Finder, dir+"/missing"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("missing path err = %v", err)
+	}
+	if err := Open(runner.NewFake().Missing("code"), nil, Editor, dir); err == nil || !strings.Contains(err.Error(), "open.editor") {
+		t.Errorf("missing editor err = %v, want a hint about open.editor", err)
+	}
+	fake := runner.NewFake().Set("open "+dir, "", errors.New("exit status 1"))
+	if err := Open(fake, nil, Finder, dir); err == nil {
+		t.Error("a failing launcher should return an error")
+	}
+}
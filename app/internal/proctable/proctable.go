@@ -0,0 +1,54 @@
+// Package proctable reads the process table with ps, for the lists of the
+// busiest processes in the Dashboard and Quick Actions.
+package proctable
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// Process is one row of the process table
+type Process struct {
+	PID  int
+	CPU  float64 // percent of one core
+	RSS  uint64  // resident memory in bytes
+	Name string
+}
+
+// Read lists every process with its CPU and memory use. -c prints the
+// executable name instead of the full command line.
+func Read(ctx context.Context, r runner.Runner) ([]Process, error) {
+	output, err := r.Output(exec.CommandContext(ctx, "ps", "-Aceo", "pid=,pcpu=,rss=,comm="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the process table: %v", err)
+	}
+	var procs []Process
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(fields[1], 64)
+		rss, _ := strconv.ParseUint(fields[2], 10, 64)
+		procs = append(procs, Process{PID: pid, CPU: cpu, RSS: rss * 1024, Name: strings.Join(fields[3:], " ")})
+	}
+	return procs, nil
+}
+
+// FormatMemory is e.g. "1.2 GB" or "340 MB"
+func FormatMemory(bytes uint64) string {
+	const mb = 1024 * 1024
+	if bytes >= 1024*mb {
+		return fmt.Sprintf("%.1f GB", float64(bytes)/(1024*mb))
+	}
+	return fmt.Sprintf("%d MB", bytes/mb)
+}
@@ -0,0 +1,33 @@
+package proctable
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+func TestRead(t *testing.T) {
+	fake := runner.NewFake().Set("ps -Aceo pid=,pcpu=,rss=,comm=", "    1   0.1  30720 launchd\n 5120  92.4 1572864 node\n  871  12.0 2097152 Google Chrome Helper\n", nil)
+	procs, err := Read(context.Background(), fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 3 || procs[2].Name != "Google Chrome Helper" || procs[1].RSS != 1536<<20 || procs[1].CPU != 92.4 {
+		t.Fatalf("procs = %+v", procs)
+	}
+
+	fake.Set("ps -Aceo pid=,pcpu=,rss=,comm=", "", errors.New("exit status 1"))
+	if _, err := Read(context.Background(), fake); err == nil {
+		t.Error("a failed ps should be an error")
+	}
+}
+
+func TestFormatMemory(t *testing.T) {
+	for bytes, want := range map[uint64]string{340 << 20: "340 MB", 1536 << 20: "1.5 GB"} {
+		if got := FormatMemory(bytes); got != want {
+			t.Errorf("FormatMemory(%d) = %s, want %s", bytes, got, want)
+		}
+	}
+}
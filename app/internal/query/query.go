@@ -0,0 +1,289 @@
+// Package query runs read-only SQL against the data Dev Cockpit collects.
+// The stores keep their own formats; Load copies them into a throwaway
+// SQLite database with the sqlite3 tool that ships with macOS, so any
+// question about them is one query away instead of one more view. The
+// metrics store becomes the metrics table, and each JSON Lines log in the
+// audit store a table named after its file: quick_actions, wifi_joins,
+// maintenance and whatever is added later.
+package query
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+)
+
+// MaxRows is how many rows of a result are kept; the rest are counted
+const MaxRows = 1000
+
+// Table is a table of the loaded database
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    int
+}
+
+// DB is the collected data loaded into SQLite. Close removes it.
+type DB struct {
+	Path   string
+	Tables []Table
+	dir    string
+	r      runner.Runner
+}
+
+// Result is what a query returned, each value as sqlite3 prints it
+type Result struct {
+	Columns []string
+	Rows    [][]string
+	More    int // rows left out past MaxRows
+}
+
+// Load copies the metrics store and the audit logs into a new database
+func Load(ctx context.Context, cfg *config.Config, r runner.Runner) (*DB, error) {
+	if _, err := r.LookPath("sqlite3"); err != nil {
+		return nil, clierr.New(clierr.DependencyMissing, "sqlite3 not found: it ships with macOS in /usr/bin")
+	}
+	var script strings.Builder
+	script.WriteString("BEGIN;\n")
+	tables, err := writeMetrics(&script, cfg)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := writeAuditLogs(&script, cfg)
+	if err != nil {
+		return nil, err
+	}
+	tables = append(tables, logs...)
+	script.WriteString("COMMIT;\n")
+
+	dir, err := os.MkdirTemp("", "devcockpit-query-")
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{Path: filepath.Join(dir, "data.db"), Tables: tables, dir: dir, r: r}
+	load := filepath.Join(dir, "load.sql")
+	if err := os.WriteFile(load, []byte(script.String()), 0600); err != nil {
+		db.Close()
+		return nil, err
+	}
+	output, err := r.CombinedOutput(exec.CommandContext(ctx, "sqlite3", "-bail", db.Path, ".read "+load))
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("loading the data: %s", firstLine(output, err))
+	}
+	return db, nil
+}
+
+// Close removes the database
+func (db *DB) Close() error {
+	return os.RemoveAll(db.dir)
+}
+
+// Query runs sql read-only. sqlite3's dot-commands aren't SQL and are
+// refused; -safe also turns off ATTACH and the functions that touch files.
+func (db *DB) Query(ctx context.Context, sql string) (Result, error) {
+	sql = strings.TrimSpace(sql)
+	switch {
+	case sql == "":
+		return Result{}, clierr.New(clierr.Usage, "no query")
+	case strings.HasPrefix(sql, "."):
+		return Result{}, clierr.New(clierr.Usage, "only SQL runs here, not sqlite3 dot-commands like %s", strings.Fields(sql)[0])
+	}
+	cmd := exec.CommandContext(ctx, "sqlite3", "-readonly", "-safe", "-bail", "-csv", "-header", db.Path, sql)
+	output, err := db.r.CombinedOutput(cmd)
+	if err != nil {
+		return Result{}, errors.New(firstLine(output, err))
+	}
+	return parseCSV(output)
+}
+
+// parseCSV reads sqlite3's -csv -header output: the column names, then a
+// record per row. A query without rows prints nothing, not even them.
+func parseCSV(output []byte) (Result, error) {
+	reader := csv.NewReader(strings.NewReader(string(output)))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Result{}, fmt.Errorf("reading the result: %v", err)
+	}
+	var result Result
+	if len(records) == 0 {
+		return result, nil
+	}
+	result.Columns, records = records[0], records[1:]
+	result.Rows = records[:min(len(records), MaxRows)]
+	result.More = len(records) - len(result.Rows)
+	return result, nil
+}
+
+// writeMetrics adds the metrics table, one row per stored sample
+func writeMetrics(script *strings.Builder, cfg *config.Config) ([]Table, error) {
+	table := Table{Name: "metrics", Columns: []string{"time", "cpu", "memory", "disk", "gpu"}}
+	createTable(script, table)
+	from, ok := storage.OldestMetrics(cfg)
+	if !ok {
+		return []Table{table}, nil
+	}
+	samples, err := storage.ReadMetrics(cfg, from, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("reading the metrics: %v", err)
+	}
+	for _, s := range samples {
+		fmt.Fprintf(script, "INSERT INTO \"metrics\" VALUES (%s, %s, %s, %s, %s);\n", quote(s.Time.Format(time.RFC3339)),
+			percent(s.CPU), percent(s.Memory), percent(s.Disk), percent(s.GPU))
+	}
+	table.Rows = len(samples)
+	return []Table{table}, nil
+}
+
+// writeAuditLogs adds a table per JSON Lines log in the audit store, with
+// a column per field found in it: time first, then by name. Nested values
+// are kept as JSON, which SQLite's json functions read.
+func writeAuditLogs(script *strings.Builder, cfg *config.Config) ([]Table, error) {
+	paths, _ := filepath.Glob(filepath.Join(storage.DataDir(cfg), storage.StoreAudit, "*.jsonl"))
+	sort.Strings(paths)
+	var tables []Table
+	for _, path := range paths {
+		records, err := readJSONLines(path)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		var columns []string
+		for _, record := range records {
+			for name := range record {
+				if !seen[name] {
+					seen[name] = true
+					columns = append(columns, name)
+				}
+			}
+		}
+		sort.Slice(columns, func(i, j int) bool {
+			if (columns[i] == "time") != (columns[j] == "time") {
+				return columns[i] == "time"
+			}
+			return columns[i] < columns[j]
+		})
+
+		if len(columns) == 0 {
+			continue
+		}
+		table := Table{Name: tableName(path), Columns: columns, Rows: len(records)}
+		createTable(script, table)
+		for _, record := range records {
+			values := make([]string, len(columns))
+			for i, name := range columns {
+				values[i] = literal(record[name])
+			}
+			fmt.Fprintf(script, "INSERT INTO %s VALUES (%s);\n", identifier(table.Name), strings.Join(values, ", "))
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// readJSONLines reads the records of a log, skipping lines cut short by a
+// crash
+func readJSONLines(path string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// tableName is a log's file name as an identifier, e.g. quick_actions
+func tableName(path string) string {
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func createTable(script *strings.Builder, table Table) {
+	columns := make([]string, len(table.Columns))
+	for i, name := range table.Columns {
+		columns[i] = identifier(name)
+	}
+	fmt.Fprintf(script, "CREATE TABLE %s (%s);\n", identifier(table.Name), strings.Join(columns, ", "))
+}
+
+// literal is a JSON value as SQL: numbers and text as they are, booleans
+// as 1 or 0, and arrays and objects as JSON text
+func literal(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case float64:
+		return number(v)
+	case string:
+		return quote(v)
+	}
+	data, _ := json.Marshal(value)
+	return quote(string(data))
+}
+
+// number writes whole numbers, like durations in nanoseconds, without an
+// exponent so they stay integers
+func number(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// percent is a stored percentage with the precision it was stored with
+func percent(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 32)
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func identifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// firstLine is the first line sqlite3 printed, without its "Error: "
+// prefix, or err without output
+func firstLine(output []byte, err error) string {
+	if line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n"); line != "" {
+		return strings.TrimPrefix(line, "Error: ")
+	}
+	return err.Error()
+}
@@ -0,0 +1,148 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/storage"
+)
+
+// collected returns a config whose data directory holds two metric
+// samples and a quick action history
+func collected(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Storage.DataDir = t.TempDir()
+	start := time.Date(2026, 10, 14, 9, 30, 0, 0, time.Local)
+	for i, cpu := range []float64{12.5, 80} {
+		sample := storage.MetricSample{Time: start.Add(time.Duration(i) * time.Minute), CPU: cpu, Memory: 50, Disk: 70.25}
+		if err := storage.AppendMetrics(cfg, sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir, err := storage.StoreDir(cfg, storage.StoreAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := `{"time":"2026-10-14T09:31:00Z","action":"Flush DNS","duration":1500000000,"success":true,"result":"it's done"}
+{"time":"2026-10-14T09:32:00Z","action":"Fix Audio","duration":200000000,"success":false,"output":["no coreaudiod"]}
+{"time":"2026-10-14T09:33
+`
+	if err := os.WriteFile(filepath.Join(dir, "quick_actions.jsonl"), []byte(history), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoad(t *testing.T) {
+	cfg := collected(t)
+	fake := &loadFake{Fake: runner.NewFake()}
+	db, err := Load(context.Background(), cfg, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := fake.script
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(db.Path); !os.IsNotExist(err) {
+		t.Error("Close left the database behind")
+	}
+
+	want := []Table{
+		{Name: "metrics", Columns: []string{"time", "cpu", "memory", "disk", "gpu"}, Rows: 2},
+		{Name: "quick_actions", Columns: []string{"time", "action", "duration", "output", "result", "success"}, Rows: 2},
+	}
+	if !reflect.DeepEqual(db.Tables, want) {
+		t.Errorf("tables = %+v, want %+v", db.Tables, want)
+	}
+	stamp := time.Date(2026, 10, 14, 9, 31, 0, 0, time.Local).Format(time.RFC3339)
+	for _, line := range []string{
+		`CREATE TABLE "metrics" ("time", "cpu", "memory", "disk", "gpu");`,
+		`INSERT INTO "metrics" VALUES ('` + stamp + `', 80, 50, 70.25, 0);`,
+		`INSERT INTO "quick_actions" VALUES ('2026-10-14T09:31:00Z', 'Flush DNS', 1500000000, NULL, 'it''s done', 1);`,
+		`INSERT INTO "quick_actions" VALUES ('2026-10-14T09:32:00Z', 'Fix Audio', 200000000, '["no coreaudiod"]', NULL, 0);`,
+	} {
+		if !strings.Contains(script, line+"\n") {
+			t.Errorf("script lacks %s\n%s", line, script)
+		}
+	}
+}
+
+// loadFake keeps the script sqlite3 is asked to read, from a temporary
+// folder a test can't know in advance
+type loadFake struct {
+	*runner.Fake
+	script string
+}
+
+func (f *loadFake) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	if path, ok := strings.CutPrefix(cmd.Args[len(cmd.Args)-1], ".read "); ok {
+		data, err := os.ReadFile(path)
+		f.script = string(data)
+		return nil, err
+	}
+	return f.Fake.CombinedOutput(cmd)
+}
+
+func TestLoadWithoutSQLite(t *testing.T) {
+	fake := runner.NewFake().Missing("sqlite3")
+	if _, err := Load(context.Background(), collected(t), fake); clierr.CodeOf(err) != clierr.DependencyMissing {
+		t.Errorf("err = %v, want sqlite3 reported missing", err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	fake := runner.NewFake()
+	db := &DB{Path: "/tmp/data.db", r: fake}
+	cmdline := "sqlite3 -readonly -safe -bail -csv -header /tmp/data.db "
+
+	fake.Set(cmdline+"SELECT action, count(*) AS runs FROM quick_actions GROUP BY action",
+		"action,runs\n\"Flush DNS, twice\",2\nFix Audio,1\n", nil)
+	result, err := db.Query(context.Background(), " SELECT action, count(*) AS runs FROM quick_actions GROUP BY action\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Result{Columns: []string{"action", "runs"}, Rows: [][]string{{"Flush DNS, twice", "2"}, {"Fix Audio", "1"}}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+
+	fake.Set(cmdline+"SELECT 1 WHERE 0", "", nil)
+	if result, err := db.Query(context.Background(), "SELECT 1 WHERE 0"); err != nil || len(result.Columns) != 0 {
+		t.Errorf("empty result = %+v, %v", result, err)
+	}
+
+	fake.Set(cmdline+"DELETE FROM metrics", "Runtime error near line 1: attempt to write a readonly database (8)\n", errors.New("exit status 1"))
+	if _, err := db.Query(context.Background(), "DELETE FROM metrics"); err == nil || !strings.Contains(err.Error(), "readonly") {
+		t.Errorf("err = %v, want sqlite3's refusal", err)
+	}
+
+	if _, err := db.Query(context.Background(), ".shell rm -rf ~"); clierr.CodeOf(err) != clierr.Usage {
+		t.Errorf("err = %v, want dot-commands refused", err)
+	}
+	if len(fake.Calls()) != 3 {
+		t.Errorf("calls = %q, want the dot-command never run", fake.Calls())
+	}
+}
+
+func TestResultKeepsMaxRows(t *testing.T) {
+	output := "n\n" + strings.Repeat("1\n", MaxRows+5)
+	result, err := parseCSV([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Rows) != MaxRows || result.More != 5 {
+		t.Errorf("rows = %d more = %d", len(result.Rows), result.More)
+	}
+}
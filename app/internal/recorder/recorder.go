@@ -0,0 +1,157 @@
+// Package recorder captures a TUI session as an asciinema v2 cast file:
+// every rendered frame becomes an output event and every keystroke an
+// input event, so a bug report can be replayed with `asciinema play`.
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clearScreen homes the cursor and clears the screen before each frame so
+// the cast replays full frames rather than the renderer's partial diffs
+const clearScreen = "\x1b[H\x1b[2J"
+
+// header is the first line of an asciinema v2 cast
+type header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder writes cast events. The header needs the terminal size, so
+// nothing is written until the first tea.WindowSizeMsg arrives.
+type Recorder struct {
+	w       io.Writer
+	closer  io.Closer
+	title   string
+	now     func() time.Time
+	start   time.Time
+	started bool
+	last    string
+	err     error
+}
+
+// New records to w; title is stored in the cast header
+func New(w io.Writer, title string) *Recorder {
+	return &Recorder{w: w, title: title, now: time.Now}
+}
+
+// Create records to a new cast file at path, replacing any existing one
+func Create(path, title string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+	r := New(f, title)
+	r.closer = f
+	return r, nil
+}
+
+// Wrap returns a model that records model's frames and keystrokes
+func (r *Recorder) Wrap(model tea.Model) tea.Model {
+	return recorded{inner: model, rec: r}
+}
+
+// Close finishes the recording and returns the first write error, if any
+func (r *Recorder) Close() error {
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+	}
+	return r.err
+}
+
+// resize writes the header on the first size and a resize event after
+func (r *Recorder) resize(width, height int) {
+	if !r.started {
+		r.started = true
+		r.start = r.now()
+		r.writeLine(header{
+			Version:   2,
+			Width:     width,
+			Height:    height,
+			Timestamp: r.start.Unix(),
+			Title:     r.title,
+			Env:       map[string]string{"SHELL": os.Getenv("SHELL"), "TERM": os.Getenv("TERM")},
+		})
+		return
+	}
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// frame records a rendered view, skipping repaints of the same frame
+func (r *Recorder) frame(view string) {
+	if !r.started || view == r.last {
+		return
+	}
+	r.last = view
+	r.event("o", clearScreen+strings.ReplaceAll(view, "\n", "\r\n"))
+}
+
+// key records a keystroke: typed text as-is, other keys by name
+func (r *Recorder) key(msg tea.KeyMsg) {
+	if !r.started {
+		return
+	}
+	data := msg.String()
+	if msg.Type == tea.KeyRunes && !msg.Alt {
+		data = string(msg.Runes)
+	}
+	r.event("i", data)
+}
+
+func (r *Recorder) event(kind, data string) {
+	elapsed := r.now().Sub(r.start).Seconds()
+	r.writeLine([]interface{}{elapsed, kind, data})
+}
+
+func (r *Recorder) writeLine(v interface{}) {
+	if r.err != nil {
+		return
+	}
+	line, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return
+	}
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		r.err = fmt.Errorf("failed to write recording: %w", err)
+	}
+}
+
+// recorded is the model returned by Wrap. Bubble Tea calls Update and View
+// from its event loop, so the recorder needs no locking.
+type recorded struct {
+	inner tea.Model
+	rec   *Recorder
+}
+
+func (m recorded) Init() tea.Cmd { return m.inner.Init() }
+
+func (m recorded) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.rec.resize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		m.rec.key(msg)
+	}
+	next, cmd := m.inner.Update(msg)
+	return recorded{inner: next, rec: m.rec}, cmd
+}
+
+func (m recorded) View() string {
+	view := m.inner.View()
+	m.rec.frame(view)
+	return view
+}
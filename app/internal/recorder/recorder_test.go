@@ -0,0 +1,78 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// echoModel renders the last key it received
+type echoModel struct{ last string }
+
+func (m echoModel) Init() tea.Cmd { return nil }
+
+func (m echoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		m.last = key.String()
+	}
+	return m, nil
+}
+
+func (m echoModel) View() string { return "last:\n" + m.last }
+
+func TestRecordsCast(t *testing.T) {
+	var buf bytes.Buffer
+	rec := New(&buf, "Dev Cockpit")
+	start := time.Unix(1700000000, 0)
+	clock := start
+	rec.now = func() time.Time { return clock }
+
+	var model tea.Model = rec.Wrap(echoModel{})
+	model.View() // before the size arrives: not recorded
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	model.View()
+	clock = start.Add(1500 * time.Millisecond)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model.View()
+	model.View() // unchanged frame: skipped
+	clock = start.Add(2 * time.Second)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, want 6:\n%s", len(lines), buf.String())
+	}
+
+	var h header
+	if err := json.Unmarshal([]byte(lines[0]), &h); err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	if h.Version != 2 || h.Width != 80 || h.Height != 24 || h.Timestamp != start.Unix() || h.Title != "Dev Cockpit" {
+		t.Errorf("header = %+v", h)
+	}
+
+	want := [][]interface{}{
+		{0.0, "o", clearScreen + "last:\r\n"},
+		{1.5, "i", "j"},
+		{1.5, "o", clearScreen + "last:\r\nj"},
+		{2.0, "i", "esc"},
+		{2.0, "r", "120x40"},
+	}
+	for i, w := range want {
+		var got []interface{}
+		if err := json.Unmarshal([]byte(lines[i+1]), &got); err != nil {
+			t.Fatalf("event %d: %v", i, err)
+		}
+		if len(got) != 3 || got[0] != w[0] || got[1] != w[1] || got[2] != w[2] {
+			t.Errorf("event %d = %q, want %q", i, got, w)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Response is the canned result of a faked command
+type Response struct {
+	Output []byte
+	Err    error
+}
+
+// Fake is a Runner that answers commands from canned responses and records
+// every command line it was asked to run. Commands are keyed by their
+// arguments joined with spaces, e.g. "docker ps -a".
+type Fake struct {
+	mu        sync.Mutex
+	responses map[string]Response
+	missing   map[string]bool
+	calls     []string
+}
+
+// NewFake creates an empty Fake; unknown commands fail
+func NewFake() *Fake {
+	return &Fake{
+		responses: make(map[string]Response),
+		missing:   make(map[string]bool),
+	}
+}
+
+// Set registers the output and error returned for a command line
+func (f *Fake) Set(cmdline, output string, err error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[cmdline] = Response{Output: []byte(output), Err: err}
+	return f
+}
+
+// SetFixture registers the contents of a recorded fixture file as the
+// output of a command line
+func (f *Fake) SetFixture(cmdline, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture %s: %w", filepath.Base(path), err)
+	}
+	f.Set(cmdline, string(data), nil)
+	return nil
+}
+
+// Missing makes LookPath fail for the given binaries
+func (f *Fake) Missing(names ...string) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, name := range names {
+		f.missing[name] = true
+	}
+	return f
+}
+
+// Calls returns the command lines run so far, in order
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// Output returns the canned response for cmd
+func (f *Fake) Output(cmd *exec.Cmd) ([]byte, error) {
+	return f.respond(cmd)
+}
+
+// CombinedOutput returns the canned response for cmd
+func (f *Fake) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	return f.respond(cmd)
+}
+
+// LookPath succeeds unless the binary was marked missing. Absolute paths
+// are checked by their base name, so a missing tool is missing everywhere.
+func (f *Fake) LookPath(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.missing[filepath.Base(name)] {
+		return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	if filepath.IsAbs(name) {
+		return name, nil
+	}
+	return filepath.Join("/usr/bin", name), nil
+}
+
+func (f *Fake) respond(cmd *exec.Cmd) ([]byte, error) {
+	cmdline := strings.Join(cmd.Args, " ")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, cmdline)
+
+	resp, ok := f.responses[cmdline]
+	if !ok {
+		return nil, fmt.Errorf("runner: unexpected command %q", cmdline)
+	}
+	return resp.Output, resp.Err
+}
@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Previewer is implemented by modules that can list the command lines
+// their selected action runs, so they can be copied to read over or to run
+// by hand
+type Previewer interface {
+	// CommandLines returns them in the order they run, or none when
+	// nothing selected runs a command
+	CommandLines() []string
+}
+
+// Line is the command line cmd runs, quoted for a shell
+func Line(cmd *exec.Cmd) string {
+	return Quote(cmd.Args...)
+}
+
+// Quote joins args into a command line a POSIX shell splits back into the
+// same args, quoting only those that need it
+func Quote(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func quoteArg(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if strings.IndexFunc(arg, unsafe) < 0 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// unsafe reports whether a shell would treat r as anything but part of a
+// word
+func unsafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("@%+=:,./_-", r)
+}
@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os/exec"
+)
+
+// Runner executes external commands on behalf of a module. Modules build
+// the *exec.Cmd as usual (context, env, args) and hand it to their Runner,
+// so tests can substitute recorded output for the real tools.
+type Runner interface {
+	// Output runs cmd and returns its standard output
+	Output(cmd *exec.Cmd) ([]byte, error)
+	// CombinedOutput runs cmd and returns stdout and stderr combined
+	CombinedOutput(cmd *exec.Cmd) ([]byte, error)
+	// LookPath searches for an executable in the PATH
+	LookPath(name string) (string, error)
+}
+
+// Streamer is implemented by runners that can hand over a command's
+// output as it's written rather than once it exits
+type Streamer interface {
+	// Stream runs cmd and calls line for each line of its stdout and
+	// stderr combined, returning once it exits
+	Stream(cmd *exec.Cmd, line func(string)) error
+}
+
+// Stream runs cmd through r and calls line for each line of its combined
+// output. Runners that can't stream, like Fake, run cmd to completion and
+// replay its output.
+func Stream(r Runner, cmd *exec.Cmd, line func(string)) error {
+	if s, ok := r.(Streamer); ok {
+		return s.Stream(cmd, line)
+	}
+	out, err := r.CombinedOutput(cmd)
+	scanLines(bytes.NewReader(out), line)
+	return err
+}
+
+// System runs commands on the host
+type System struct{}
+
+// Default is the Runner modules use outside of tests
+var Default Runner = System{}
+
+// Output runs cmd and returns its standard output
+func (System) Output(cmd *exec.Cmd) ([]byte, error) {
+	return cmd.Output()
+}
+
+// CombinedOutput runs cmd and returns stdout and stderr combined
+func (System) CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	return cmd.CombinedOutput()
+}
+
+// LookPath searches for an executable in the PATH
+func (System) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// Stream runs cmd and calls line for each line of its output as it's
+// written
+func (System) Stream(cmd *exec.Cmd, line func(string)) error {
+	pr, pw := io.Pipe()
+	cmd.Stdout, cmd.Stderr = pw, pw
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+		pw.Close()
+	}()
+	scanLines(pr, line)
+	return <-done
+}
+
+// scanLines calls line for each line read from r. A carriage return also
+// ends a line, so progress bars that redraw in place arrive as updates.
+func scanLines(r io.Reader, line func(string)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	for scanner.Scan() {
+		if text := scanner.Text(); text != "" {
+			line(text)
+		}
+	}
+	// Keep draining so the command isn't blocked writing
+	_, _ = io.Copy(io.Discard, r)
+}
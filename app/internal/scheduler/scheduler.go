@@ -0,0 +1,163 @@
+// Package scheduler runs the periodic refreshes of every module from the
+// shell's single tick loop. Modules describe their tasks instead of
+// chaining tea.Tick commands, which used to stack a new loop each time a
+// tab was re-initialized. A task never overlaps itself, pauses while its
+// module is hidden, and runs as soon as the module is shown again if it
+// fell due in the meantime.
+package scheduler
+
+import (
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Task is a periodic refresh owned by a module
+type Task struct {
+	Name   string        // unique within the module; re-adding replaces it
+	Every  time.Duration // delay between the end of one run and the next
+	Jitter time.Duration // up to this much is added to each delay at random
+
+	// Background tasks keep running while their module is hidden
+	Background bool
+
+	// Run returns the refresh command. Its message is delivered to the
+	// module; a nil command skips the run.
+	Run func() tea.Cmd
+}
+
+// Scheduled is implemented by modules with periodic tasks
+type Scheduled interface {
+	Schedule() []Task
+}
+
+// RescheduleMsg asks the shell to read the module's Schedule again, after
+// the module changed how often its tasks run
+type RescheduleMsg struct{}
+
+// Reschedule is the command a module returns after changing its tasks
+func Reschedule() tea.Msg {
+	return RescheduleMsg{}
+}
+
+// DoneMsg carries the result of a task run back to the shell, which
+// passes Msg on to the module
+type DoneMsg struct {
+	Module string
+	Task   string
+	Msg    tea.Msg
+}
+
+// entry is a registered task and its state
+type entry struct {
+	module  string
+	task    Task
+	next    time.Time // zero: due right away
+	running bool
+}
+
+// Scheduler holds the tasks of all modules
+type Scheduler struct {
+	entries []*entry
+	jitter  func(max time.Duration) time.Duration
+}
+
+// New returns an empty scheduler
+func New() *Scheduler {
+	return &Scheduler{jitter: func(max time.Duration) time.Duration {
+		return time.Duration(rand.Int63n(int64(max) + 1))
+	}}
+}
+
+// Add registers tasks for module, replacing tasks of the same name. A
+// task whose delay changed is due right away, so the new pace shows at
+// once.
+func (s *Scheduler) Add(module string, tasks ...Task) {
+	for _, task := range tasks {
+		if e := s.find(module, task.Name); e != nil {
+			if e.task.Every != task.Every {
+				e.next = time.Time{}
+			}
+			e.task = task
+			continue
+		}
+		s.entries = append(s.entries, &entry{module: module, task: task})
+	}
+}
+
+// Len returns the number of registered tasks
+func (s *Scheduler) Len() int {
+	return len(s.entries)
+}
+
+// Due starts every task that is due at now and not already running.
+// Tasks of modules that aren't visible wait unless they run in the
+// background.
+func (s *Scheduler) Due(now time.Time, visible func(module string) bool) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, e := range s.entries {
+		if e.running || now.Before(e.next) {
+			continue
+		}
+		if !e.task.Background && !visible(e.module) {
+			continue
+		}
+		cmd := e.task.Run()
+		if cmd == nil {
+			e.next = s.after(now, e.task)
+			continue
+		}
+		e.running = true
+		module, name := e.module, e.task.Name
+		cmds = append(cmds, func() tea.Msg {
+			return DoneMsg{Module: module, Task: name, Msg: cmd()}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// Done records that a run finished at now and schedules the next one
+func (s *Scheduler) Done(msg DoneMsg, now time.Time) {
+	if e := s.find(msg.Module, msg.Task); e != nil {
+		e.running = false
+		e.next = s.after(now, e.task)
+	}
+}
+
+// after returns when task is next due if a run ends at now
+func (s *Scheduler) after(now time.Time, task Task) time.Time {
+	next := now.Add(task.Every)
+	if task.Jitter > 0 {
+		next = next.Add(s.jitter(task.Jitter))
+	}
+	return next
+}
+
+func (s *Scheduler) find(module, name string) *entry {
+	for _, e := range s.entries {
+		if e.module == module && e.task.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// Step returns the delay after every in steps, ascending: the next shorter
+// one when faster, else the next longer one. At either end every stays.
+func Step(steps []time.Duration, every time.Duration, faster bool) time.Duration {
+	if faster {
+		for i := len(steps) - 1; i >= 0; i-- {
+			if steps[i] < every {
+				return steps[i]
+			}
+		}
+		return every
+	}
+	for _, step := range steps {
+		if step > every {
+			return step
+		}
+	}
+	return every
+}
@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type refreshed struct{}
+
+// counter returns a task that counts how often it was started
+func counter(name string, every time.Duration, runs *int) Task {
+	return Task{Name: name, Every: every, Run: func() tea.Cmd {
+		*runs++
+		return func() tea.Msg { return refreshed{} }
+	}}
+}
+
+// run executes the commands Due returned and reports them done at now
+func run(s *Scheduler, cmd tea.Cmd, now time.Time) []DoneMsg {
+	if cmd == nil {
+		return nil
+	}
+	cmds := []tea.Cmd{cmd}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		cmds = batch
+	}
+	var done []DoneMsg
+	for _, c := range cmds {
+		msg := c().(DoneMsg)
+		s.Done(msg, now)
+		done = append(done, msg)
+	}
+	return done
+}
+
+func always(string) bool { return true }
+
+func TestTaskNeverOverlapsItself(t *testing.T) {
+	s := New()
+	runs := 0
+	s.Add("Dashboard", counter("metrics", time.Second, &runs))
+	now := time.Now()
+
+	pending := s.Due(now, always)
+	if runs != 1 || pending == nil {
+		t.Fatalf("a new task should run right away, runs = %d", runs)
+	}
+	s.Due(now.Add(5*time.Second), always)
+	if runs != 1 {
+		t.Errorf("task started again while running, runs = %d", runs)
+	}
+
+	done := run(s, pending, now.Add(5*time.Second))
+	if len(done) != 1 || done[0].Module != "Dashboard" || done[0].Msg != (refreshed{}) {
+		t.Fatalf("done = %+v", done)
+	}
+	if s.Due(now.Add(5500*time.Millisecond), always); runs != 1 {
+		t.Error("task ran before its delay after the last run")
+	}
+	if s.Due(now.Add(6*time.Second), always); runs != 2 {
+		t.Errorf("task should run once due, runs = %d", runs)
+	}
+}
+
+func TestHiddenModulesPause(t *testing.T) {
+	s := New()
+	var shown, background int
+	s.Add("System", counter("info", time.Second, &shown))
+	bg := counter("sync", time.Second, &background)
+	bg.Background = true
+	s.Add("Docker", bg)
+
+	s.Due(time.Now(), func(string) bool { return false })
+	if shown != 0 || background != 1 {
+		t.Errorf("shown = %d, background = %d; want only the background task", shown, background)
+	}
+	s.Due(time.Now(), func(module string) bool { return module == "System" })
+	if shown != 1 {
+		t.Error("an overdue task should run once its module is shown")
+	}
+}
+
+func TestAddReplacesTask(t *testing.T) {
+	s := New()
+	var first, second int
+	s.Add("Dashboard", counter("metrics", time.Second, &first))
+	s.Add("Dashboard", counter("metrics", time.Second, &second))
+
+	s.Due(time.Now(), always)
+	if s.Len() != 1 || first != 0 || second != 1 {
+		t.Errorf("len = %d, first = %d, second = %d", s.Len(), first, second)
+	}
+}
+
+func TestNewDelayRunsAtOnce(t *testing.T) {
+	s := New()
+	runs := 0
+	s.Add("Dashboard", counter("metrics", 10*time.Second, &runs))
+	now := time.Now()
+	run(s, s.Due(now, always), now)
+
+	s.Add("Dashboard", counter("metrics", 10*time.Second, &runs))
+	if s.Due(now.Add(time.Second), always); runs != 1 {
+		t.Error("re-adding the same task should keep its delay")
+	}
+	s.Add("Dashboard", counter("metrics", time.Second, &runs))
+	if s.Due(now.Add(time.Second), always); runs != 2 {
+		t.Errorf("runs = %d, want the task to run as soon as its delay changed", runs)
+	}
+}
+
+func TestJitterDelaysNextRun(t *testing.T) {
+	s := New()
+	s.jitter = func(max time.Duration) time.Duration { return max }
+	runs := 0
+	task := counter("info", 5*time.Second, &runs)
+	task.Jitter = time.Second
+	s.Add("System", task)
+
+	now := time.Now()
+	run(s, s.Due(now, always), now)
+	if s.Due(now.Add(5*time.Second), always); runs != 1 {
+		t.Error("jitter should push the next run past the interval")
+	}
+	if s.Due(now.Add(6*time.Second), always); runs != 2 {
+		t.Errorf("runs = %d, want 2 after interval plus jitter", runs)
+	}
+}
+
+func TestStep(t *testing.T) {
+	steps := []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+	tests := []struct {
+		every  time.Duration
+		faster bool
+		want   time.Duration
+	}{
+		{5 * time.Second, true, time.Second},
+		{5 * time.Second, false, 30 * time.Second},
+		{3 * time.Second, false, 5 * time.Second}, // from a configured delay between steps
+		{time.Second, true, time.Second},
+		{30 * time.Second, false, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := Step(steps, tt.every, tt.faster); got != tt.want {
+			t.Errorf("Step(%v, faster=%v) = %v, want %v", tt.every, tt.faster, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package startup times the launch for --profile-startup. Phases (module
+// construction, config loading) record how long they took, milestones
+// (first render, first metrics) record when they happened after launch.
+// The breakdown goes to the log once every expected milestone is in, or
+// when the program exits. Everything is a no-op unless Begin was called.
+package startup
+
+import (
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+)
+
+// Milestones the shell waits for before writing the breakdown
+const (
+	FirstRender  = "first render"
+	FirstMetrics = "first metrics"
+)
+
+// entry is one line of the breakdown
+type entry struct {
+	name      string
+	took      time.Duration
+	milestone bool // took is the time since launch
+}
+
+// profile is the state of an active --profile-startup run
+type profile struct {
+	mu      sync.Mutex
+	start   time.Time
+	entries []entry
+	seen    map[string]bool
+	expect  []string
+	cpu     *os.File
+	done    bool
+}
+
+var active *profile
+
+// Begin starts profiling. With a non-empty cpuProfile path a pprof CPU
+// profile is written there as well. The breakdown is logged as soon as all
+// expected milestones have been marked.
+func Begin(cpuProfile string, expect ...string) error {
+	p := &profile{start: time.Now(), seen: make(map[string]bool), expect: expect}
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return err
+		}
+		p.cpu = f
+	}
+	active = p
+	return nil
+}
+
+// Enabled reports whether a profile is being recorded
+func Enabled() bool {
+	return active != nil
+}
+
+// Measure records a phase that began at start and ends now
+func Measure(name string, start time.Time) {
+	p := active
+	if p == nil {
+		return
+	}
+	took := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.done {
+		p.entries = append(p.entries, entry{name: name, took: took})
+	}
+}
+
+// Mark records a milestone the first time it is reached. Later calls are
+// cheap, so it can sit in View or Update.
+func Mark(name string) {
+	p := active
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if p.done || p.seen[name] {
+		p.mu.Unlock()
+		return
+	}
+	p.seen[name] = true
+	p.entries = append(p.entries, entry{name: name, took: time.Since(p.start), milestone: true})
+	complete := true
+	for _, want := range p.expect {
+		complete = complete && p.seen[want]
+	}
+	p.mu.Unlock()
+
+	if complete {
+		Finish()
+	}
+}
+
+// Finish logs the breakdown and stops the CPU profile. Only the first call
+// does anything; milestones never reached are reported as missing.
+func Finish() {
+	p := active
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if p.done {
+		p.mu.Unlock()
+		return
+	}
+	p.done = true
+	lines := p.report()
+	p.mu.Unlock()
+
+	for _, line := range lines {
+		logger.Info("%s", line)
+	}
+	if p.cpu != nil {
+		pprof.StopCPUProfile()
+		p.cpu.Close()
+		logger.Info("Startup CPU profile written to %s (inspect with: go tool pprof %s)", p.cpu.Name(), p.cpu.Name())
+	}
+}
+
+// report formats the breakdown; the caller holds p.mu
+func (p *profile) report() []string {
+	width := 0
+	for _, e := range p.entries {
+		width = max(width, len(e.name))
+	}
+	for _, name := range p.expect {
+		width = max(width, len(name))
+	}
+
+	var phases time.Duration
+	lines := []string{"Startup profile:"}
+	for _, e := range p.entries {
+		if e.milestone {
+			lines = append(lines, fmt.Sprintf("  %-*s  at %s", width, e.name, round(e.took)))
+			continue
+		}
+		phases += e.took
+		lines = append(lines, fmt.Sprintf("  %-*s  %s", width, e.name, round(e.took)))
+	}
+	for _, name := range p.expect {
+		if !p.seen[name] {
+			lines = append(lines, fmt.Sprintf("  %-*s  not reached", width, name))
+		}
+	}
+	lines = append(lines, fmt.Sprintf("  %s in measured phases", round(phases)))
+	return lines
+}
+
+// round keeps durations readable: whole microseconds below a millisecond,
+// tenths of a millisecond above
+func round(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
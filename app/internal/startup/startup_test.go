@@ -0,0 +1,60 @@
+package startup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfileFinishesWhenMilestonesAreIn(t *testing.T) {
+	if err := Begin("", FirstRender, FirstMetrics); err != nil {
+		t.Fatal(err)
+	}
+	p := active
+	defer func() { active = nil }()
+
+	Measure("new Dashboard", time.Now().Add(-3*time.Millisecond))
+	Mark(FirstRender)
+	Mark(FirstRender)
+	if p.done {
+		t.Fatal("finished before the first metrics")
+	}
+	Mark(FirstMetrics)
+	if !p.done {
+		t.Fatal("not finished after every milestone")
+	}
+	Measure("late", time.Now())
+
+	report := strings.Join(p.report(), "\n")
+	for _, want := range []string{"new Dashboard  3", "first render   at ", "first metrics  at "} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report lacks %q:\n%s", want, report)
+		}
+	}
+	if strings.Count(report, FirstRender) != 1 || strings.Contains(report, "late") {
+		t.Errorf("report should hold each milestone once and nothing after finishing:\n%s", report)
+	}
+}
+
+func TestFinishReportsMissingMilestones(t *testing.T) {
+	if err := Begin("", FirstRender, FirstMetrics); err != nil {
+		t.Fatal(err)
+	}
+	p := active
+	defer func() { active = nil }()
+
+	Mark(FirstRender)
+	Finish()
+	if report := strings.Join(p.report(), "\n"); !strings.Contains(report, "first metrics  not reached") {
+		t.Errorf("report = \n%s", report)
+	}
+}
+
+func TestDisabledIsNoop(t *testing.T) {
+	Measure("new Dashboard", time.Now())
+	Mark(FirstRender)
+	Finish()
+	if Enabled() {
+		t.Error("profiling enabled without Begin")
+	}
+}
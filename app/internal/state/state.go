@@ -0,0 +1,74 @@
+// Package state remembers where the user left off: the active module and
+// whatever each module chooses to persist, saved to ~/.devcockpit/state.json
+// on quit and restored on the next launch.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+)
+
+// FileName is the state file inside the config directory
+const FileName = "state.json"
+
+// Persister is implemented by modules that keep state between launches,
+// such as cursor positions or selections. Each module owns the format of
+// its own data.
+type Persister interface {
+	SaveState() (json.RawMessage, error)
+	RestoreState(data json.RawMessage) error
+}
+
+// State is the contents of state.json
+type State struct {
+	ActiveModule string                     `json:"active_module,omitempty"`
+	Modules      map[string]json.RawMessage `json:"modules,omitempty"`
+}
+
+// Path returns the state file location for cfg
+func Path(cfg *config.Config) string {
+	return filepath.Join(cfg.Dir(), FileName)
+}
+
+// Load reads the state file. A missing file is an empty state, not an
+// error, so first launches start from the defaults.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the state file, replacing it atomically so a crash while
+// quitting can't leave a truncated file behind
+func Save(path string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
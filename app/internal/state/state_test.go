@@ -0,0 +1,54 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.ActiveModule != "" || len(s.Modules) != 0 {
+		t.Errorf("state = %+v, want empty", s)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+	saved := &State{
+		ActiveModule: "Cleanup",
+		Modules:      map[string]json.RawMessage{"Cleanup": json.RawMessage(`{"cursor":2}`)},
+	}
+	if err := Save(path, saved); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var module struct{ Cursor int }
+	if err := json.Unmarshal(loaded.Modules["Cleanup"], &module); err != nil {
+		t.Fatalf("module state: %v", err)
+	}
+	if loaded.ActiveModule != "Cleanup" || module.Cursor != 2 {
+		t.Errorf("loaded = %+v, cursor %d", loaded, module.Cursor)
+	}
+}
+
+func TestLoadCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load should fail on a corrupt file")
+	}
+}
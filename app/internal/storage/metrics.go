@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+)
+
+// MetricSample is one sample of system metrics in the metrics store.
+// Percentages are stored as float32, which is plenty for a graph.
+type MetricSample struct {
+	Time   time.Time
+	CPU    float64
+	Memory float64
+	Disk   float64
+	GPU    float64
+}
+
+// metricRecordSize is a sample on disk: Unix seconds, then the four
+// percentages, little endian
+const metricRecordSize = 8 + 4*4
+
+// metricsFile is the day file a sample taken at t goes into. A file per
+// local day lets retention drop whole days.
+func metricsFile(dir string, t time.Time) string {
+	return filepath.Join(dir, t.Format("2006-01-02")+".bin")
+}
+
+// AppendMetrics adds a sample to the metrics store. Starting a new day
+// file also prunes the store, so it stays within its retention limits
+// without anything else having to run.
+func AppendMetrics(cfg *config.Config, sample MetricSample) error {
+	dir, err := StoreDir(cfg, StoreMetrics)
+	if err != nil {
+		return err
+	}
+	path := metricsFile(dir, sample.Time)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		for _, store := range Stores(cfg) {
+			if store.Name == StoreMetrics {
+				_, _ = store.Prune(sample.Time)
+			}
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open metrics file: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(encodeMetric(sample))
+	return err
+}
+
+// ReadMetrics returns the stored samples taken from from up to to, oldest
+// first. Days without a file are skipped.
+func ReadMetrics(cfg *config.Config, from, to time.Time) ([]MetricSample, error) {
+	dir := filepath.Join(DataDir(cfg), StoreMetrics)
+	var samples []MetricSample
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		data, err := os.ReadFile(metricsFile(dir, day))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return samples, err
+		}
+		// A record cut short by a crash is left out
+		for len(data) >= metricRecordSize {
+			sample := decodeMetric(data[:metricRecordSize])
+			data = data[metricRecordSize:]
+			if !sample.Time.Before(from) && !sample.Time.After(to) {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	return samples, nil
+}
+
+// OldestMetrics returns the start of the oldest day in the metrics store,
+// or false when it holds none
+func OldestMetrics(cfg *config.Config) (time.Time, bool) {
+	matches, _ := filepath.Glob(filepath.Join(DataDir(cfg), StoreMetrics, "*.bin"))
+	var oldest time.Time
+	for _, path := range matches {
+		day, err := time.ParseInLocation("2006-01-02", strings.TrimSuffix(filepath.Base(path), ".bin"), time.Local)
+		if err == nil && (oldest.IsZero() || day.Before(oldest)) {
+			oldest = day
+		}
+	}
+	return oldest, !oldest.IsZero()
+}
+
+// CompactResult summarizes what CompactMetrics thinned out
+type CompactResult struct {
+	Days   int // day files rewritten
+	Merged int // samples merged into others
+	Freed  uint64
+}
+
+// CompactMetrics thins the day files from before the day of before to a
+// sample per bucket, the average of the samples taken in it, so a long
+// history stays small and keeps its shape. Files already that sparse are
+// left alone.
+func CompactMetrics(cfg *config.Config, before time.Time, bucket time.Duration) (CompactResult, error) {
+	var result CompactResult
+	dir := filepath.Join(DataDir(cfg), StoreMetrics)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	cutoff := time.Date(before.Year(), before.Month(), before.Day(), 0, 0, 0, 0, before.Location())
+	for _, entry := range entries {
+		day, err := time.ParseInLocation("2006-01-02.bin", entry.Name(), before.Location())
+		if err != nil || !day.Before(cutoff) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return result, err
+		}
+		var samples []MetricSample
+		for ; len(data) >= metricRecordSize; data = data[metricRecordSize:] {
+			samples = append(samples, decodeMetric(data[:metricRecordSize]))
+		}
+		compacted := averageMetrics(samples, bucket)
+		if len(compacted) == len(samples) {
+			continue
+		}
+
+		var out []byte
+		for _, sample := range compacted {
+			out = append(out, encodeMetric(sample)...)
+		}
+		// Written aside and renamed, so a crash leaves the old file whole
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, out, 0644); err != nil {
+			return result, fmt.Errorf("failed to compact %s: %w", entry.Name(), err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(tmp)
+			return result, fmt.Errorf("failed to compact %s: %w", entry.Name(), err)
+		}
+		result.Days++
+		result.Merged += len(samples) - len(compacted)
+		result.Freed += uint64((len(samples) - len(compacted)) * metricRecordSize)
+	}
+	return result, nil
+}
+
+// averageMetrics merges the samples taken within each bucket into one,
+// timed at the start of the bucket
+func averageMetrics(samples []MetricSample, bucket time.Duration) []MetricSample {
+	var merged []MetricSample
+	for i := 0; i < len(samples); {
+		start := samples[i].Time.Truncate(bucket)
+		sum := MetricSample{Time: start}
+		n := 0
+		for ; i < len(samples) && samples[i].Time.Truncate(bucket).Equal(start); i++ {
+			sum.CPU += samples[i].CPU
+			sum.Memory += samples[i].Memory
+			sum.Disk += samples[i].Disk
+			sum.GPU += samples[i].GPU
+			n++
+		}
+		sum.CPU /= float64(n)
+		sum.Memory /= float64(n)
+		sum.Disk /= float64(n)
+		sum.GPU /= float64(n)
+		merged = append(merged, sum)
+	}
+	return merged
+}
+
+func encodeMetric(sample MetricSample) []byte {
+	record := make([]byte, metricRecordSize)
+	binary.LittleEndian.PutUint64(record, uint64(sample.Time.Unix()))
+	for i, v := range []float64{sample.CPU, sample.Memory, sample.Disk, sample.GPU} {
+		binary.LittleEndian.PutUint32(record[8+4*i:], math.Float32bits(float32(v)))
+	}
+	return record
+}
+
+func decodeMetric(record []byte) MetricSample {
+	value := func(i int) float64 {
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(record[8+4*i:])))
+	}
+	return MetricSample{
+		Time:   time.Unix(int64(binary.LittleEndian.Uint64(record)), 0),
+		CPU:    value(0),
+		Memory: value(1),
+		Disk:   value(2),
+		GPU:    value(3),
+	}
+}
@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+)
+
+// Store names for the data Dev Cockpit writes under the data directory
+const (
+	StoreMetrics   = "metrics"
+	StoreReports   = "reports"
+	StoreSnapshots = "snapshots"
+	StoreAudit     = "audit"
+)
+
+// Store is a directory of files governed by a retention policy
+type Store struct {
+	Name   string
+	Dir    string
+	Policy config.RetentionPolicy
+	// Logs marks a store of append-only JSON Lines logs, which size
+	// retention trims to their newest lines instead of removing
+	Logs bool
+}
+
+// PruneResult summarizes what retention removed from a store
+type PruneResult struct {
+	Store   string
+	Removed int
+	Trimmed int // logs cut down to their newest lines
+	Freed   uint64
+}
+
+// DataDir returns the configured data directory
+func DataDir(cfg *config.Config) string {
+	if cfg != nil && cfg.Storage.DataDir != "" {
+		return cfg.Storage.DataDir
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".devcockpit", "data")
+}
+
+// Stores returns every store with its configured retention policy
+func Stores(cfg *config.Config) []Store {
+	dataDir := DataDir(cfg)
+
+	var retention config.RetentionConfig
+	if cfg != nil {
+		retention = cfg.Storage.Retention
+		// max_history_days predates per-store retention and still limits
+		// the metrics history when retention doesn't
+		if retention.Metrics.MaxAgeDays == 0 {
+			retention.Metrics.MaxAgeDays = cfg.Storage.MaxHistoryDays
+		}
+	}
+
+	return []Store{
+		{Name: StoreMetrics, Dir: filepath.Join(dataDir, StoreMetrics), Policy: retention.Metrics},
+		{Name: StoreReports, Dir: filepath.Join(dataDir, StoreReports), Policy: retention.Reports},
+		{Name: StoreSnapshots, Dir: filepath.Join(dataDir, StoreSnapshots), Policy: retention.Snapshots},
+		{Name: StoreAudit, Dir: filepath.Join(dataDir, StoreAudit), Policy: retention.Audit, Logs: true},
+	}
+}
+
+// StoreDir returns the directory for the named store, creating it if needed
+func StoreDir(cfg *config.Config, name string) (string, error) {
+	dir := filepath.Join(DataDir(cfg), name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s store: %w", name, err)
+	}
+	return dir, nil
+}
+
+// Size returns the bytes currently used by the store
+func (s Store) Size() uint64 {
+	return DirSize(s.Dir)
+}
+
+// Prune removes files older than MaxAgeDays, then the oldest remaining
+// files until the store fits within MaxSizeMB. In a store of logs, the
+// JSON Lines files lose their oldest lines instead.
+func (s Store) Prune(now time.Time) (PruneResult, error) {
+	result := PruneResult{Store: s.Name}
+
+	files, err := listFiles(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return result, err
+	}
+
+	// Oldest first so size trimming drops the stalest data
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	var kept []fileEntry
+	var total uint64
+	for _, f := range files {
+		if s.Policy.MaxAgeDays > 0 && now.Sub(f.modTime) > time.Duration(s.Policy.MaxAgeDays)*24*time.Hour {
+			if err := os.Remove(f.path); err != nil {
+				logger.Warn("Retention: failed to remove %s: %v", f.path, err)
+				continue
+			}
+			result.Removed++
+			result.Freed += f.size
+			continue
+		}
+		kept = append(kept, f)
+		total += f.size
+	}
+
+	if s.Policy.MaxSizeMB > 0 {
+		limit := uint64(s.Policy.MaxSizeMB) * 1024 * 1024
+		for _, f := range kept {
+			if total <= limit {
+				break
+			}
+			if s.Logs && filepath.Ext(f.path) == ".jsonl" {
+				freed, err := trimOldestLines(f.path, total-limit)
+				if err != nil {
+					logger.Warn("Retention: failed to trim %s: %v", f.path, err)
+					continue
+				}
+				result.Trimmed++
+				result.Freed += freed
+				total -= freed
+				continue
+			}
+			if err := os.Remove(f.path); err != nil {
+				logger.Warn("Retention: failed to remove %s: %v", f.path, err)
+				continue
+			}
+			result.Removed++
+			result.Freed += f.size
+			total -= f.size
+		}
+	}
+
+	return result, nil
+}
+
+// trimOldestLines drops whole lines from the start of the JSON Lines log
+// at path until at least excess bytes are gone, and returns how many were
+func trimOldestLines(path string, excess uint64) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	cut := 0
+	for uint64(cut) < excess && cut < len(data) {
+		end := bytes.IndexByte(data[cut:], '\n')
+		if end < 0 {
+			cut = len(data)
+			break
+		}
+		cut += end + 1
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data[cut:], 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	return uint64(cut), nil
+}
+
+// ApplyRetention prunes every store according to its policy
+func ApplyRetention(cfg *config.Config) []PruneResult {
+	now := time.Now()
+	var results []PruneResult
+	for _, store := range Stores(cfg) {
+		result, err := store.Prune(now)
+		if err != nil {
+			logger.Warn("Retention: failed to prune %s store: %v", store.Name, err)
+			continue
+		}
+		if result.Removed > 0 || result.Trimmed > 0 {
+			logger.Info("Retention: removed %d file(s) and trimmed %d log(s) in %s store (%d bytes)", result.Removed, result.Trimmed, store.Name, result.Freed)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// PurgeAll deletes everything Dev Cockpit has stored in its data directory.
+// The configuration file is left untouched.
+func PurgeAll(cfg *config.Config) (uint64, error) {
+	dataDir := DataDir(cfg)
+	freed := DirSize(dataDir)
+
+	if err := os.RemoveAll(dataDir); err != nil {
+		return 0, fmt.Errorf("failed to purge %s: %w", dataDir, err)
+	}
+
+	logger.Info("Purged data directory %s (%d bytes)", dataDir, freed)
+	return freed, nil
+}
+
+// DirSize returns the total size of regular files below path
+func DirSize(path string) uint64 {
+	var total uint64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += uint64(info.Size())
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+type fileEntry struct {
+	path    string
+	size    uint64
+	modTime time.Time
+}
+
+func listFiles(dir string) ([]fileEntry, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	var files []fileEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, fileEntry{path: path, size: uint64(info.Size()), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/config"
+)
+
+func TestPruneTrimsLogs(t *testing.T) {
+	dir := t.TempDir()
+	line := `{"action":"flush-dns","ok":true,"pad":"` + strings.Repeat("x", 1000) + `"}` + "\n"
+	log := filepath.Join(dir, "quick_actions.jsonl")
+	var lines []string
+	for i := 0; i < 1536; i++ {
+		lines = append(lines, line)
+	}
+	if err := os.WriteFile(log, []byte(strings.Join(lines, "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "report.md")
+	if err := os.WriteFile(report, make([]byte, 512*1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(report, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	store := Store{Name: StoreAudit, Dir: dir, Policy: config.RetentionPolicy{MaxSizeMB: 1}, Logs: true}
+	result, err := store.Prune(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Removed != 1 || result.Trimmed != 1 {
+		t.Errorf("result = %+v, want the report removed and the log trimmed", result)
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal("the log should be kept:", err)
+	}
+	if len(data) > 1024*1024 || len(data)%len(line) != 0 || !strings.HasPrefix(string(data), line) {
+		t.Errorf("the log kept %d bytes, want whole lines within 1 MB", len(data))
+	}
+	if size := store.Size(); size > 1024*1024 {
+		t.Errorf("the store is %d bytes after pruning", size)
+	}
+}
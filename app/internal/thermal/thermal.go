@@ -0,0 +1,136 @@
+// Package thermal reads CPU/GPU die temperatures and fan speed. smctemp
+// reads the SMC without a password when it's installed; otherwise
+// powermetrics is run through sudo, which needs an authorized session.
+package thermal
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+	"github.com/caioricciuti/dev-cockpit/internal/tools"
+)
+
+// Reading is one sample; zero fields weren't reported
+type Reading struct {
+	CPU      float64 // °C
+	GPU      float64 // °C
+	FanRPM   int
+	Pressure string // thermal pressure level, e.g. "Nominal"
+}
+
+// Empty reports whether nothing was read
+func (r Reading) Empty() bool {
+	return r.CPU == 0 && r.GPU == 0 && r.FanRPM == 0 && r.Pressure == ""
+}
+
+// ErrNeedsSudo is returned by Read until Authorize has been called (or
+// sudo was used recently in this terminal)
+var ErrNeedsSudo = errors.New("powermetrics needs administrator access")
+
+var smctemp = tools.Feature{
+	Name:  "SMC temperatures",
+	Tools: []tools.Tool{{Name: "smctemp", Paths: []string{"/opt/homebrew/bin/smctemp", "/usr/local/bin/smctemp"}}},
+	Hint:  "brew install narugit/tap/smctemp",
+}
+
+// samplers are tried in turn: smc only exists on Intel Macs, and Apple
+// silicon reports just the thermal pressure
+var samplers = []string{"smc,thermal", "thermal"}
+
+// runPrivileged is replaced in tests
+var runPrivileged = sudo.Run
+
+// Read samples the sensors with smctemp when it's installed, or with
+// powermetrics through an existing sudo session
+func Read(r runner.Runner) (Reading, error) {
+	if c := tools.Resolve(r, smctemp); c.Available() {
+		return readSMCTemp(r, c.Path)
+	}
+	return powermetrics(func(args []string) (string, error) {
+		out, err := r.CombinedOutput(exec.Command("sudo", append([]string{"-n"}, args...)...))
+		if err != nil && strings.Contains(strings.ToLower(string(out)), "password is required") {
+			return "", ErrNeedsSudo
+		}
+		if err != nil {
+			return string(out), errors.New(firstLine(out, err))
+		}
+		return string(out), nil
+	})
+}
+
+// Authorize runs powermetrics through sudo, asking for the password, so
+// Read can use the session afterwards
+func Authorize() (Reading, error) {
+	return powermetrics(func(args []string) (string, error) {
+		return runPrivileged(args[0], args[1:]...)
+	})
+}
+
+func powermetrics(run func(args []string) (string, error)) (Reading, error) {
+	var err error
+	for _, sampler := range samplers {
+		var out string
+		out, err = run([]string{"powermetrics", "--samplers", sampler, "-i", "200", "-n", "1"})
+		if err == nil {
+			return Parse(out), nil
+		}
+		if !strings.Contains(strings.ToLower(out+err.Error()), "unrecognized sampler") {
+			break
+		}
+	}
+	return Reading{}, err
+}
+
+func readSMCTemp(r runner.Runner, path string) (Reading, error) {
+	var reading Reading
+	out, err := r.Output(exec.Command(path, "-c"))
+	if err != nil {
+		return reading, errors.New(firstLine(out, err))
+	}
+	reading.CPU, _ = strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if out, err := r.Output(exec.Command(path, "-g")); err == nil {
+		reading.GPU, _ = strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	}
+	return reading, nil
+}
+
+var (
+	cpuPattern      = regexp.MustCompile(`(?m)^CPU die temperature:\s*([\d.]+)`)
+	gpuPattern      = regexp.MustCompile(`(?m)^GPU die temperature:\s*([\d.]+)`)
+	fanPattern      = regexp.MustCompile(`(?m)^Fan:\s*([\d.]+)\s*rpm`)
+	pressurePattern = regexp.MustCompile(`(?m)^Current pressure level:\s*(\S+)`)
+)
+
+// Parse reads the smc and thermal sections of powermetrics output
+func Parse(out string) Reading {
+	var r Reading
+	if m := cpuPattern.FindStringSubmatch(out); m != nil {
+		r.CPU, _ = strconv.ParseFloat(m[1], 64)
+	}
+	if m := gpuPattern.FindStringSubmatch(out); m != nil {
+		r.GPU, _ = strconv.ParseFloat(m[1], 64)
+	}
+	if m := fanPattern.FindStringSubmatch(out); m != nil {
+		rpm, _ := strconv.ParseFloat(m[1], 64)
+		r.FanRPM = int(rpm + 0.5)
+	}
+	if m := pressurePattern.FindStringSubmatch(out); m != nil {
+		r.Pressure = m[1]
+	}
+	return r
+}
+
+// firstLine is the first line of a command's output, or err when it
+// printed nothing
+func firstLine(out []byte, err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if line == "" {
+		return err.Error()
+	}
+	return line
+}
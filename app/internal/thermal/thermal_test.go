@@ -0,0 +1,53 @@
+package thermal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+	"github.com/caioricciuti/dev-cockpit/internal/sudo"
+)
+
+func TestReadPowermetrics(t *testing.T) {
+	fake := runner.NewFake().Missing("smctemp").
+		Set("sudo -n powermetrics --samplers smc,thermal -i 200 -n 1", "sudo: a password is required\n", errors.New("exit status 1"))
+	if _, err := Read(fake); err != ErrNeedsSudo {
+		t.Fatalf("err = %v, want ErrNeedsSudo", err)
+	}
+
+	if err := fake.SetFixture("sudo -n powermetrics --samplers smc,thermal -i 200 -n 1", "testdata/powermetrics_intel.txt"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(fake)
+	want := Reading{CPU: 71.94, GPU: 58, FanRPM: 2159, Pressure: "Moderate"}
+	if err != nil || got != want {
+		t.Errorf("got %+v, %v; want %+v", got, err, want)
+	}
+}
+
+func TestAuthorizeFallsBackToThermal(t *testing.T) {
+	var ran [][]string
+	runPrivileged = func(command string, args ...string) (string, error) {
+		ran = append(ran, append([]string{command}, args...))
+		if args[1] == "smc,thermal" {
+			return "", errors.New("powermetrics: unrecognized sampler: smc")
+		}
+		return "**** Thermal pressure ****\n\nCurrent pressure level: Nominal\n", nil
+	}
+	t.Cleanup(func() { runPrivileged = sudo.Run })
+
+	got, err := Authorize()
+	if err != nil || got != (Reading{Pressure: "Nominal"}) || len(ran) != 2 {
+		t.Errorf("got %+v, %v after %d runs", got, err, len(ran))
+	}
+}
+
+func TestReadSMCTemp(t *testing.T) {
+	fake := runner.NewFake().
+		Set("/usr/bin/smctemp -c", "48.2\n", nil).
+		Set("/usr/bin/smctemp -g", "41.0\n", nil)
+	got, err := Read(fake)
+	if err != nil || got != (Reading{CPU: 48.2, GPU: 41}) {
+		t.Errorf("got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,70 @@
+// Package tools finds the external binaries features depend on. A feature
+// lists the tools that can provide it in order of preference; each tool is
+// looked up in PATH and then in its standard locations, since PATH is
+// often trimmed when launched from a GUI, cron or a minimal shell. When
+// nothing is found the feature is disabled with a hint instead of failing
+// at runtime.
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// Tool is an external binary and where it's usually installed
+type Tool struct {
+	Name  string
+	Paths []string
+}
+
+// Feature is something the UI offers, provided by the first of its Tools
+// that is installed
+type Feature struct {
+	Name  string
+	Tools []Tool
+	// Hint tells the user how to get the feature when no tool is found
+	Hint string
+}
+
+// Capability is a resolved Feature
+type Capability struct {
+	Feature string
+	Tool    string // the tool providing the feature, "" when unavailable
+	Path    string
+	Reason  string // why the feature is unavailable
+}
+
+// Available reports whether a tool was found for the feature
+func (c Capability) Available() bool { return c.Path != "" }
+
+// Resolve finds the first installed tool for f
+func Resolve(r runner.Runner, f Feature) Capability {
+	names := make([]string, 0, len(f.Tools))
+	for _, tool := range f.Tools {
+		if path, ok := find(r, tool); ok {
+			return Capability{Feature: f.Name, Tool: tool.Name, Path: path}
+		}
+		names = append(names, tool.Name)
+	}
+
+	reason := fmt.Sprintf("%s not found", strings.Join(names, " or "))
+	if f.Hint != "" {
+		reason += " • " + f.Hint
+	}
+	return Capability{Feature: f.Name, Reason: reason}
+}
+
+// find looks tool up in PATH, then at each of its standard paths
+func find(r runner.Runner, tool Tool) (string, bool) {
+	if path, err := r.LookPath(tool.Name); err == nil {
+		return path, true
+	}
+	for _, candidate := range tool.Paths {
+		if path, err := r.LookPath(candidate); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
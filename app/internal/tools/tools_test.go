@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+var dns = Feature{
+	Name: "DNS Lookup",
+	Tools: []Tool{
+		{Name: "dig", Paths: []string{"/usr/bin/dig"}},
+		{Name: "nslookup", Paths: []string{"/usr/bin/nslookup"}},
+	},
+	Hint: "brew install bind",
+}
+
+func TestResolvePrefersFirstTool(t *testing.T) {
+	c := Resolve(runner.NewFake(), dns)
+	if !c.Available() || c.Tool != "dig" || c.Path != "/usr/bin/dig" {
+		t.Errorf("got %+v, want dig", c)
+	}
+}
+
+func TestResolveFallsBackToAlternative(t *testing.T) {
+	c := Resolve(runner.NewFake().Missing("dig"), dns)
+	if c.Tool != "nslookup" {
+		t.Errorf("got %+v, want nslookup", c)
+	}
+}
+
+func TestResolveMissingExplains(t *testing.T) {
+	c := Resolve(runner.NewFake().Missing("dig", "nslookup"), dns)
+	if c.Available() {
+		t.Fatalf("got %+v, want unavailable", c)
+	}
+	if want := "dig or nslookup not found • brew install bind"; c.Reason != want {
+		t.Errorf("Reason = %q, want %q", c.Reason, want)
+	}
+}
+
+// notInPath fails PATH lookups but finds absolute paths, like a GUI launch
+// with a trimmed PATH
+type notInPath struct{ runner.System }
+
+func (notInPath) LookPath(name string) (string, error) {
+	if name == "/usr/bin/dig" {
+		return name, nil
+	}
+	return runner.NewFake().Missing(name).LookPath(name)
+}
+
+func TestResolveChecksStandardPaths(t *testing.T) {
+	c := Resolve(notInPath{}, dns)
+	if c.Path != "/usr/bin/dig" {
+		t.Errorf("got %+v, want dig from its standard path", c)
+	}
+}
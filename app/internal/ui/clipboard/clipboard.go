@@ -0,0 +1,102 @@
+// Package clipboard copies text to the system clipboard and keeps a
+// session history of everything copied through it.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/logger"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+// maxItems caps the session history
+const maxItems = 50
+
+// ErrUnavailable is returned when there is no pbcopy to write to
+var ErrUnavailable = errors.New("clipboard is not available (pbcopy not found)")
+
+// Runner runs pbcopy; tests replace it with a runner.Fake
+var Runner runner.Runner = runner.Default
+
+// Copyable is implemented by modules that can copy their selected item or
+// last output with y. Copyable returns "" when there is nothing to copy or
+// the module is taking text input, so y reaches it as a typed key.
+type Copyable interface {
+	Copyable() string
+}
+
+// Item is a piece of text copied during the session
+type Item struct {
+	Text   string
+	Source string
+	Copied time.Time
+}
+
+var (
+	mu      sync.Mutex
+	history []Item
+)
+
+// Copy writes text to the system clipboard and records it in the history,
+// attributed to source (usually the module title). The item is recorded
+// even if the write fails so it can be copied again later.
+func Copy(source, text string) error {
+	record(Item{Text: text, Source: source, Copied: time.Now()})
+	return write(text)
+}
+
+// Recopy puts a history item back on the clipboard and moves it to the top
+// of the history
+func Recopy(item Item) error {
+	return Copy(item.Source, item.Text)
+}
+
+// History returns the copied items, most recent first
+func History() []Item {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Item(nil), history...)
+}
+
+// Clear empties the history
+func Clear() {
+	mu.Lock()
+	defer mu.Unlock()
+	history = nil
+}
+
+func record(item Item) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Copying the same text again only moves it to the top
+	kept := []Item{item}
+	for _, existing := range history {
+		if existing.Text != item.Text {
+			kept = append(kept, existing)
+		}
+	}
+	if len(kept) > maxItems {
+		kept = kept[:maxItems]
+	}
+	history = kept
+}
+
+func write(text string) error {
+	if _, err := Runner.LookPath("pbcopy"); err != nil {
+		return ErrUnavailable
+	}
+
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := Runner.CombinedOutput(cmd); err != nil {
+		logger.Warn("pbcopy failed: %v (%s)", err, strings.TrimSpace(string(output)))
+		return fmt.Errorf("failed to copy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
+)
+
+func useFake(t *testing.T, fake *runner.Fake) {
+	t.Helper()
+	Clear()
+	Runner = fake
+	t.Cleanup(func() {
+		Runner = runner.Default
+		Clear()
+	})
+}
+
+func TestCopyRecordsHistory(t *testing.T) {
+	fake := runner.NewFake().Set("pbcopy", "", nil)
+	useFake(t, fake)
+
+	for _, text := range []string{"192.168.1.42", "openssl@3", "192.168.1.42"} {
+		if err := Copy("Network", text); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history := History()
+	if len(history) != 2 || history[0].Text != "192.168.1.42" || history[1].Text != "openssl@3" {
+		t.Fatalf("history = %+v, want the repeated copy moved to the top", history)
+	}
+	if calls := fake.Calls(); len(calls) != 3 {
+		t.Errorf("calls = %q, want pbcopy three times", calls)
+	}
+}
+
+func TestCopyWithoutPbcopy(t *testing.T) {
+	useFake(t, runner.NewFake().Missing("pbcopy"))
+
+	if err := Copy("Support", "https://example.com"); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if len(History()) != 1 {
+		t.Error("failed copies should still be recorded")
+	}
+}
+
+func TestHistoryIsCapped(t *testing.T) {
+	useFake(t, runner.NewFake().Set("pbcopy", "", nil))
+
+	for i := 0; i < maxItems+10; i++ {
+		_ = Copy("Logs", fmt.Sprintf("line %d", i))
+	}
+
+	history := History()
+	if len(history) != maxItems {
+		t.Fatalf("len(history) = %d, want %d", len(history), maxItems)
+	}
+	if want := fmt.Sprintf("line %d", maxItems+9); history[0].Text != want {
+		t.Errorf("newest = %q, want %q", history[0].Text, want)
+	}
+}
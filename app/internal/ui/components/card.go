@@ -25,7 +25,7 @@ func (m *MetricCard) Render() string {
 	styles := NewBaseStyles()
 
 	// Determine border color based on status level
-	var borderColor lipgloss.Color
+	var borderColor lipgloss.AdaptiveColor
 	switch m.StatusLevel {
 	case "error":
 		borderColor = styles.Theme.Error
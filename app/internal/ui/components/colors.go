@@ -0,0 +1,190 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Palette is a full set of view colors. Each color has a Dark variant and
+// a Light one readable on light terminal backgrounds; lipgloss picks one
+// from the detected background.
+type Palette struct {
+	Primary   lipgloss.AdaptiveColor // cyan
+	Success   lipgloss.AdaptiveColor // green
+	Warning   lipgloss.AdaptiveColor // orange
+	Error     lipgloss.AdaptiveColor // red
+	Accent    lipgloss.AdaptiveColor // pink
+	Highlight lipgloss.AdaptiveColor // gold
+
+	Text   lipgloss.AdaptiveColor // body text
+	Bright lipgloss.AdaptiveColor // emphasized values
+	Subtle lipgloss.AdaptiveColor // hints and help lines
+	Muted  lipgloss.AdaptiveColor // secondary text
+	Border lipgloss.AdaptiveColor // borders and rules
+	Faint  lipgloss.AdaptiveColor // empty bars, disabled items
+
+	// Backgrounds of the screen and of headers, toasts and overlays
+	Background lipgloss.AdaptiveColor
+	Panel      lipgloss.AdaptiveColor
+	Overlay    lipgloss.AdaptiveColor
+	TabBar     lipgloss.AdaptiveColor
+}
+
+// Cyberpunk is the default palette
+var Cyberpunk = Palette{
+	Primary:   lipgloss.AdaptiveColor{Light: "#00739E", Dark: "#00D9FF"},
+	Success:   lipgloss.AdaptiveColor{Light: "#0A7F45", Dark: "#0FD976"},
+	Warning:   lipgloss.AdaptiveColor{Light: "#A85800", Dark: "#FFA500"},
+	Error:     lipgloss.AdaptiveColor{Light: "#C62828", Dark: "#FF6B6B"},
+	Accent:    lipgloss.AdaptiveColor{Light: "#B0186E", Dark: "#FF6AC1"},
+	Highlight: lipgloss.AdaptiveColor{Light: "#8A6D00", Dark: "#FFD700"},
+
+	Text:   lipgloss.AdaptiveColor{Light: "#1F1F1F", Dark: "#DDDDDD"},
+	Bright: lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+	Subtle: lipgloss.AdaptiveColor{Light: "#4D4D4D", Dark: "#888888"},
+	Muted:  lipgloss.AdaptiveColor{Light: "#6B6B6B", Dark: "#666666"},
+	Border: lipgloss.AdaptiveColor{Light: "#B5B5B5", Dark: "#333333"},
+	Faint:  lipgloss.AdaptiveColor{Light: "#A0A0A0", Dark: "#444444"},
+
+	Background: lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#0A0A0F"},
+	Panel:      lipgloss.AdaptiveColor{Light: "#E6ECF2", Dark: "#1A1A2E"},
+	Overlay:    lipgloss.AdaptiveColor{Light: "#F4F6F8", Dark: "#0F1419"},
+	TabBar:     lipgloss.AdaptiveColor{Light: "#E0E0E0", Dark: "#1A1A1A"},
+}
+
+// HighContrast keeps text at full black or white and uses saturated status
+// colors, for low vision or washed-out displays
+var HighContrast = Palette{
+	Primary:   lipgloss.AdaptiveColor{Light: "#0000CC", Dark: "#00FFFF"},
+	Success:   lipgloss.AdaptiveColor{Light: "#006400", Dark: "#00FF00"},
+	Warning:   lipgloss.AdaptiveColor{Light: "#8B4000", Dark: "#FFFF00"},
+	Error:     lipgloss.AdaptiveColor{Light: "#B00000", Dark: "#FF5050"},
+	Accent:    lipgloss.AdaptiveColor{Light: "#800080", Dark: "#FF80FF"},
+	Highlight: lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFF00"},
+
+	Text:   lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+	Bright: lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+	Subtle: lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#E6E6E6"},
+	Muted:  lipgloss.AdaptiveColor{Light: "#333333", Dark: "#CCCCCC"},
+	Border: lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+	Faint:  lipgloss.AdaptiveColor{Light: "#595959", Dark: "#A6A6A6"},
+
+	Background: lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"},
+	Panel:      lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"},
+	Overlay:    lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"},
+	TabBar:     lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"},
+}
+
+// ANSI uses only the 16 base terminal colors, so it follows the user's
+// terminal theme instead of approximating hex colors that collapse (dark
+// gray borders become black on black) on 16-color terminals
+var ANSI = Palette{
+	Primary:   lipgloss.AdaptiveColor{Light: "4", Dark: "14"},
+	Success:   lipgloss.AdaptiveColor{Light: "2", Dark: "10"},
+	Warning:   lipgloss.AdaptiveColor{Light: "3", Dark: "11"},
+	Error:     lipgloss.AdaptiveColor{Light: "1", Dark: "9"},
+	Accent:    lipgloss.AdaptiveColor{Light: "5", Dark: "13"},
+	Highlight: lipgloss.AdaptiveColor{Light: "3", Dark: "11"},
+
+	Text:   lipgloss.AdaptiveColor{Light: "0", Dark: "7"},
+	Bright: lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+	Subtle: lipgloss.AdaptiveColor{Light: "8", Dark: "7"},
+	Muted:  lipgloss.AdaptiveColor{Light: "8", Dark: "8"},
+	Border: lipgloss.AdaptiveColor{Light: "8", Dark: "8"},
+	Faint:  lipgloss.AdaptiveColor{Light: "7", Dark: "8"},
+
+	Background: lipgloss.AdaptiveColor{Light: "15", Dark: "0"},
+	Panel:      lipgloss.AdaptiveColor{Light: "7", Dark: "0"},
+	Overlay:    lipgloss.AdaptiveColor{Light: "15", Dark: "0"},
+	TabBar:     lipgloss.AdaptiveColor{Light: "7", Dark: "0"},
+}
+
+// schemes maps ui.color_scheme values to palettes
+var schemes = map[string]Palette{
+	"cyberpunk":     Cyberpunk,
+	"high-contrast": HighContrast,
+	"ansi":          ANSI,
+}
+
+// The colors every view draws with, from the active palette. Views read
+// them at render time, so UsePalette restyles the whole UI.
+var (
+	ColorPrimary   lipgloss.AdaptiveColor
+	ColorSuccess   lipgloss.AdaptiveColor
+	ColorWarning   lipgloss.AdaptiveColor
+	ColorError     lipgloss.AdaptiveColor
+	ColorAccent    lipgloss.AdaptiveColor
+	ColorHighlight lipgloss.AdaptiveColor
+
+	ColorText   lipgloss.AdaptiveColor
+	ColorBright lipgloss.AdaptiveColor
+	ColorSubtle lipgloss.AdaptiveColor
+	ColorMuted  lipgloss.AdaptiveColor
+	ColorBorder lipgloss.AdaptiveColor
+	ColorFaint  lipgloss.AdaptiveColor
+
+	ColorBackground lipgloss.AdaptiveColor
+	ColorPanel      lipgloss.AdaptiveColor
+	ColorOverlay    lipgloss.AdaptiveColor
+	ColorTabBar     lipgloss.AdaptiveColor
+)
+
+func init() {
+	UsePalette(Cyberpunk)
+}
+
+// UsePalette makes p the active palette. Call it before building views;
+// styles created earlier keep their colors.
+func UsePalette(p Palette) {
+	ColorPrimary, ColorSuccess, ColorWarning = p.Primary, p.Success, p.Warning
+	ColorError, ColorAccent, ColorHighlight = p.Error, p.Accent, p.Highlight
+	ColorText, ColorBright, ColorSubtle = p.Text, p.Bright, p.Subtle
+	ColorMuted, ColorBorder, ColorFaint = p.Muted, p.Border, p.Faint
+	ColorBackground, ColorPanel = p.Background, p.Panel
+	ColorOverlay, ColorTabBar = p.Overlay, p.TabBar
+}
+
+// SetColorScheme activates the palette named by ui.color_scheme. The
+// default scheme falls back to ANSI on 16-color terminals. Unknown names
+// keep the default palette and return an error.
+func SetColorScheme(scheme string) error {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	if scheme == "" || scheme == "cyberpunk" {
+		if lipgloss.ColorProfile() == termenv.ANSI {
+			UsePalette(ANSI)
+		} else {
+			UsePalette(Cyberpunk)
+		}
+		return nil
+	}
+	palette, ok := schemes[scheme]
+	if !ok {
+		UsePalette(Cyberpunk)
+		return fmt.Errorf("unknown color scheme %q (use cyberpunk, high-contrast or ansi)", scheme)
+	}
+	UsePalette(palette)
+	return nil
+}
+
+// DisableColor renders the UI without colors, for NO_COLOR and
+// --no-color. Bold, underline and reverse still mark selections.
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// SetBackground picks the palette variant: "light" or "dark" forces one,
+// anything else ("auto") detects the terminal background. Call it before
+// the program starts, since detection queries the terminal.
+func SetBackground(theme string) {
+	switch strings.ToLower(theme) {
+	case "light":
+		lipgloss.SetHasDarkBackground(false)
+	case "dark":
+		lipgloss.SetHasDarkBackground(true)
+	default:
+		lipgloss.HasDarkBackground()
+	}
+}
@@ -0,0 +1,38 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSetBackground(t *testing.T) {
+	defer lipgloss.SetHasDarkBackground(lipgloss.HasDarkBackground())
+
+	SetBackground("light")
+	if lipgloss.HasDarkBackground() {
+		t.Error(`SetBackground("light") left a dark background`)
+	}
+	SetBackground("Dark")
+	if !lipgloss.HasDarkBackground() {
+		t.Error(`SetBackground("Dark") left a light background`)
+	}
+}
+
+func TestSetColorScheme(t *testing.T) {
+	defer UsePalette(Cyberpunk)
+
+	if err := SetColorScheme("High-Contrast"); err != nil {
+		t.Fatal(err)
+	}
+	if ColorText != HighContrast.Text || DefaultTheme().Border != HighContrast.Border {
+		t.Error("high-contrast palette not applied to the colors and the theme")
+	}
+
+	if err := SetColorScheme("solarized"); err == nil {
+		t.Error("unknown scheme accepted")
+	}
+	if ColorPrimary != Cyberpunk.Primary {
+		t.Error("unknown scheme should fall back to cyberpunk")
+	}
+}
@@ -0,0 +1,98 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/fuzzy"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ListFilter is the inline "/" filter shared by long lists. The list keeps
+// its own items and cursor; Matches maps the query to the items to show.
+type ListFilter struct {
+	query  string
+	typing bool
+}
+
+// HandleKey updates the filter and reports whether it consumed the key.
+// "/" starts typing; while typing, Enter keeps the query and Esc clears
+// it. Esc also clears a kept query. Arrow keys are left to the list.
+func (f *ListFilter) HandleKey(msg tea.KeyMsg) bool {
+	if !f.typing {
+		switch msg.String() {
+		case "/":
+			f.typing = true
+			return true
+		case "esc":
+			if f.query != "" {
+				f.query = ""
+				return true
+			}
+		}
+		return false
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		f.Reset()
+	case tea.KeyEnter:
+		f.typing = false
+	case tea.KeyBackspace:
+		if runes := []rune(f.query); len(runes) > 0 {
+			f.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		f.query = ""
+	case tea.KeySpace:
+		f.query += " "
+	case tea.KeyRunes:
+		f.query += string(msg.Runes)
+	default:
+		return false
+	}
+	return true
+}
+
+// Start begins typing, for lists that open straight into the filter
+func (f *ListFilter) Start() { f.typing = true }
+
+// Reset clears the query and stops typing
+func (f *ListFilter) Reset() {
+	f.query = ""
+	f.typing = false
+}
+
+// SetQuery replaces the query without typing
+func (f *ListFilter) SetQuery(query string) { f.query = query }
+
+// Query returns the current query
+func (f *ListFilter) Query() string { return f.query }
+
+// Typing reports whether keys go to the filter
+func (f *ListFilter) Typing() bool { return f.typing }
+
+// Active reports whether the filter is typing or has a query. Esc then
+// belongs to the filter, so modules count it as an open modal.
+func (f *ListFilter) Active() bool { return f.typing || f.query != "" }
+
+// Matches returns the indexes of texts matching the query, best match
+// first, or every index in order when there is no query
+func (f *ListFilter) Matches(texts []string) []int {
+	return fuzzy.Filter(f.query, texts)
+}
+
+// View renders the filter bar with the match count, or nothing when the
+// filter is inactive
+func (f *ListFilter) View(matches, total int) string {
+	if !f.Active() {
+		return ""
+	}
+	promptStyle := lipgloss.NewStyle().Foreground(ColorSuccess).Bold(true)
+	countStyle := lipgloss.NewStyle().Foreground(ColorMuted)
+
+	if f.typing {
+		return promptStyle.Render("/ "+f.query+"▏") + countStyle.Render(fmt.Sprintf(" (%d/%d) • Enter Keep • Esc Clear", matches, total))
+	}
+	return promptStyle.Render("🔍 Filter: "+f.query) + countStyle.Render(fmt.Sprintf(" (%d/%d) • / Edit • Esc Clear", matches, total))
+}
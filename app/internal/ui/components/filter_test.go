@@ -0,0 +1,58 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestListFilterTyping(t *testing.T) {
+	var f ListFilter
+	if f.HandleKey(runes("r")) {
+		t.Fatal("keys other than / should pass through while inactive")
+	}
+
+	for _, key := range []tea.KeyMsg{runes("/"), runes("r"), runes("d"), {Type: tea.KeyBackspace}, runes("e")} {
+		if !f.HandleKey(key) {
+			t.Fatalf("key %q should be consumed while typing", key)
+		}
+	}
+	if f.Query() != "re" || !f.Typing() {
+		t.Fatalf("query = %q typing = %v", f.Query(), f.Typing())
+	}
+	if f.HandleKey(tea.KeyMsg{Type: tea.KeyDown}) {
+		t.Error("arrow keys should be left to the list")
+	}
+
+	f.HandleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if f.Typing() || !f.Active() {
+		t.Fatal("Enter should keep the query and stop typing")
+	}
+	if f.HandleKey(runes("s")) {
+		t.Error("list shortcuts should work again after Enter")
+	}
+
+	if !f.HandleKey(tea.KeyMsg{Type: tea.KeyEsc}) || f.Active() {
+		t.Error("Esc should clear a kept query")
+	}
+	if f.HandleKey(tea.KeyMsg{Type: tea.KeyEsc}) {
+		t.Error("Esc with no query should pass through")
+	}
+}
+
+func TestListFilterMatches(t *testing.T) {
+	var f ListFilter
+	texts := []string{"postgres-dev postgres:16", "redis-cache redis:7", "web nginx:1.27"}
+
+	if got := f.Matches(texts); len(got) != 3 || got[0] != 0 || got[2] != 2 {
+		t.Errorf("no query = %v, want every index in order", got)
+	}
+	f.SetQuery("redis")
+	if got := f.Matches(texts); len(got) != 1 || got[0] != 1 {
+		t.Errorf("redis = %v, want [1]", got)
+	}
+}
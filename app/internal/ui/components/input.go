@@ -0,0 +1,206 @@
+package components
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxInputHistory is how many entries a TextInput remembers
+const maxInputHistory = 50
+
+// TextInput is a single-line text field with cursor editing, paste and a
+// history recalled with ↑/↓. The app sends arrows and Home/End as
+// events.Nav, so those go to Nav and the other keys to HandleKey.
+type TextInput struct {
+	value   []rune
+	pos     int      // cursor position in value
+	history []string // oldest first
+	recall  int      // history entry shown, or len(history) while editing
+	draft   string   // what was typed before recalling history
+}
+
+// HandleKey edits the text and reports whether it used the key. Enter and
+// Esc are left to the caller. A paste arrives as one key with many runes;
+// line breaks in it become spaces.
+func (t *TextInput) HandleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "left", "ctrl+b":
+		t.pos = max(t.pos-1, 0)
+	case "right", "ctrl+f":
+		t.pos = min(t.pos+1, len(t.value))
+	case "alt+left", "alt+b":
+		t.pos = t.wordStart()
+	case "alt+right", "alt+f":
+		t.pos = t.wordEnd()
+	case "ctrl+a":
+		t.pos = 0
+	case "ctrl+e":
+		t.pos = len(t.value)
+	case "backspace", "ctrl+h":
+		if t.pos > 0 {
+			t.value = append(t.value[:t.pos-1], t.value[t.pos:]...)
+			t.pos--
+		}
+	case "delete", "ctrl+d":
+		if t.pos < len(t.value) {
+			t.value = append(t.value[:t.pos], t.value[t.pos+1:]...)
+		}
+	case "ctrl+w", "alt+backspace":
+		start := t.wordStart()
+		t.value = append(t.value[:start], t.value[t.pos:]...)
+		t.pos = start
+	case "ctrl+u":
+		t.value = t.value[t.pos:]
+		t.pos = 0
+	case "ctrl+k":
+		t.value = t.value[:t.pos]
+	case " ":
+		t.insert([]rune{' '})
+	default:
+		if msg.Type != tea.KeyRunes || msg.Alt {
+			return false
+		}
+		t.insert(msg.Runes)
+	}
+	return true
+}
+
+// Nav moves through the history (↑/↓) or to either end of the text
+// (Home/End)
+func (t *TextInput) Nav(nav events.Nav) {
+	switch nav {
+	case events.NavUp:
+		if t.recall == len(t.history) {
+			t.draft = string(t.value)
+		}
+		if t.recall > 0 {
+			t.recall--
+			t.show(t.history[t.recall])
+		}
+	case events.NavDown:
+		if t.recall < len(t.history)-1 {
+			t.recall++
+			t.show(t.history[t.recall])
+		} else if t.recall == len(t.history)-1 {
+			t.recall++
+			t.show(t.draft)
+		}
+	case events.NavTop:
+		t.pos = 0
+	case events.NavBottom:
+		t.pos = len(t.value)
+	}
+}
+
+// insert adds runes at the cursor, dropping control characters
+func (t *TextInput) insert(runes []rune) {
+	clean := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			clean = append(clean, ' ')
+		case unicode.IsPrint(r):
+			clean = append(clean, r)
+		}
+	}
+	t.value = append(t.value[:t.pos], append(clean, t.value[t.pos:]...)...)
+	t.pos += len(clean)
+}
+
+func (t *TextInput) show(s string) {
+	t.value = []rune(s)
+	t.pos = len(t.value)
+}
+
+// wordStart is where the word before the cursor starts
+func (t *TextInput) wordStart() int {
+	i := t.pos
+	for i > 0 && unicode.IsSpace(t.value[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(t.value[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd is where the word after the cursor ends
+func (t *TextInput) wordEnd() int {
+	i := t.pos
+	for i < len(t.value) && unicode.IsSpace(t.value[i]) {
+		i++
+	}
+	for i < len(t.value) && !unicode.IsSpace(t.value[i]) {
+		i++
+	}
+	return i
+}
+
+// Value returns the text, trimmed of surrounding spaces
+func (t *TextInput) Value() string {
+	return strings.TrimSpace(string(t.value))
+}
+
+// SetValue replaces the text and puts the cursor at its end
+func (t *TextInput) SetValue(s string) {
+	t.show(s)
+	t.recall = len(t.history)
+}
+
+// Reset clears the text; the history is kept
+func (t *TextInput) Reset() {
+	t.SetValue("")
+	t.draft = ""
+}
+
+// Remember adds s to the history, moving it to the end if it's already
+// there
+func (t *TextInput) Remember(s string) {
+	if s == "" {
+		return
+	}
+	kept := t.history[:0]
+	for _, entry := range t.history {
+		if entry != s {
+			kept = append(kept, entry)
+		}
+	}
+	t.history = append(kept, s)
+	if len(t.history) > maxInputHistory {
+		t.history = t.history[len(t.history)-maxInputHistory:]
+	}
+	t.recall = len(t.history)
+}
+
+// History returns the remembered entries, oldest first
+func (t *TextInput) History() []string { return t.history }
+
+// SetHistory replaces the history, e.g. with one saved by a previous launch
+func (t *TextInput) SetHistory(history []string) {
+	t.history = nil
+	for _, entry := range history {
+		t.Remember(entry)
+	}
+}
+
+// View renders the text within width columns with the cursor shown,
+// scrolling sideways to keep the cursor in view
+func (t *TextInput) View(width int) string {
+	width = max(width, 2)
+	start := 0
+	if t.pos >= width {
+		start = t.pos - width + 1
+	}
+	end := min(len(t.value), start+width)
+
+	before := string(t.value[start:t.pos])
+	if t.pos >= len(t.value) {
+		return before + "▊"
+	}
+	cursor := lipgloss.NewStyle().Reverse(true).Render(string(t.value[t.pos]))
+	return before + cursor + string(t.value[t.pos+1:end])
+}
@@ -0,0 +1,71 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTextInputEditing(t *testing.T) {
+	var in TextInput
+	in.HandleKey(runes("exmple.com\n")) // pasted in one go
+	in.Nav(events.NavTop)
+	for range "ex" {
+		in.HandleKey(tea.KeyMsg{Type: tea.KeyRight})
+	}
+	in.HandleKey(runes("a"))
+	if got := in.Value(); got != "example.com" {
+		t.Fatalf("value = %q, want the paste kept and the typo fixed mid-text", got)
+	}
+
+	in.Nav(events.NavBottom)
+	in.HandleKey(tea.KeyMsg{Type: tea.KeySpace})
+	in.HandleKey(runes("extra"))
+	in.HandleKey(tea.KeyMsg{Type: tea.KeyCtrlW})
+	in.HandleKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got := in.Value(); got != "example.com" {
+		t.Errorf("after Ctrl+W and Backspace value = %q", got)
+	}
+	if in.HandleKey(tea.KeyMsg{Type: tea.KeyEnter}) {
+		t.Error("Enter belongs to the caller")
+	}
+}
+
+func TestTextInputHistory(t *testing.T) {
+	var in TextInput
+	for _, target := range []string{"a.com", "b.com", "a.com"} {
+		in.Remember(target)
+	}
+	if h := in.History(); len(h) != 2 || h[0] != "b.com" || h[1] != "a.com" {
+		t.Fatalf("history = %q, want a repeat moved to the end", h)
+	}
+
+	in.HandleKey(runes("draft"))
+	in.Nav(events.NavUp)
+	if in.Value() != "a.com" {
+		t.Fatalf("↑ = %q, want the newest entry", in.Value())
+	}
+	in.Nav(events.NavUp)
+	in.Nav(events.NavUp) // stays on the oldest
+	if in.Value() != "b.com" {
+		t.Fatalf("↑↑↑ = %q", in.Value())
+	}
+	in.Nav(events.NavDown)
+	in.Nav(events.NavDown)
+	if in.Value() != "draft" {
+		t.Errorf("↓ past the newest = %q, want the draft back", in.Value())
+	}
+}
+
+func TestTextInputViewScrolls(t *testing.T) {
+	var in TextInput
+	in.SetValue("abcdefghij")
+	if got := in.View(5); got != "ghij▊" {
+		t.Errorf("View(5) at the end = %q", got)
+	}
+	in.Nav(events.NavTop)
+	if got := in.View(5); got != "abcde" {
+		t.Errorf("View(5) at the start = %q", got)
+	}
+}
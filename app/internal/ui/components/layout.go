@@ -77,6 +77,22 @@ func (l *Layout) SplitHorizontal(columns int, spacing int) []int {
 	return widths
 }
 
+// MinPaneWidth is the narrowest pane SplitPanes will produce
+const MinPaneWidth = 36
+
+// SplitPanes divides the content width into a left pane taking ratio of the
+// space and a right pane taking the rest, separated by spacing columns.
+// ok is false when the terminal is too narrow for two usable panes.
+func (l *Layout) SplitPanes(ratio float64, spacing int) (left, right int, ok bool) {
+	available := l.ContentWidth - spacing
+	left = int(float64(available) * ratio)
+	right = available - left
+	if left < MinPaneWidth || right < MinPaneWidth {
+		return 0, 0, false
+	}
+	return left, right, true
+}
+
 // BoxDimensions calculates inner dimensions accounting for border and padding
 type BoxDimensions struct {
 	OuterWidth  int
@@ -0,0 +1,80 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Progress tracks a long operation made of known amounts of work, e.g.
+// targets to clean weighted by their size, and estimates the time left
+// from the rate so far
+type Progress struct {
+	total   int64
+	done    int64
+	started time.Time
+}
+
+// NewProgress starts tracking total units of work
+func NewProgress(total int64) Progress {
+	return Progress{total: max(total, 1), started: time.Now()}
+}
+
+// Advance records n more units as done
+func (p *Progress) Advance(n int64) {
+	p.done = min(p.done+n, p.total)
+}
+
+// Set records how many units are done in all
+func (p *Progress) Set(done int64) {
+	p.done = min(max(done, 0), p.total)
+}
+
+// Fraction is the share of the work done, 0 to 1
+func (p Progress) Fraction() float64 {
+	if p.total == 0 {
+		return 0
+	}
+	return float64(p.done) / float64(p.total)
+}
+
+// Estimate returns how long the rest should take at the rate so far; ok is
+// false until some work is done
+func (p Progress) Estimate(elapsed time.Duration) (left time.Duration, ok bool) {
+	if p.done == 0 || p.done >= p.total {
+		return 0, p.done >= p.total && p.total > 0
+	}
+	return time.Duration(float64(elapsed) * float64(p.total-p.done) / float64(p.done)), true
+}
+
+// View renders a bar width columns wide with the percentage, time taken
+// and time left as of now
+func (p Progress) View(width int, now time.Time) string {
+	elapsed := now.Sub(p.started)
+	if p.started.IsZero() {
+		elapsed = 0
+	}
+	status := fmt.Sprintf(" %3.0f%% · %s", p.Fraction()*100, formatETA(elapsed))
+	if left, ok := p.Estimate(elapsed); ok && p.done < p.total {
+		status += " · ~" + formatETA(left) + " left"
+	} else if !ok {
+		status += " · estimating"
+	}
+
+	barWidth := max(width-lipgloss.Width(status)-2, 10)
+	filled := int(p.Fraction() * float64(barWidth))
+	bar := lipgloss.NewStyle().Foreground(ColorPrimary).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(ColorBorder).Render(strings.Repeat("░", barWidth-filled))
+	return "[" + bar + "]" + lipgloss.NewStyle().Foreground(ColorMuted).Render(status)
+}
+
+// formatETA shows a duration in whole seconds, or minutes and seconds
+func formatETA(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
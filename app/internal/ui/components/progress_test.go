@@ -0,0 +1,43 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestProgressEstimate(t *testing.T) {
+	p := NewProgress(100)
+	if _, ok := p.Estimate(5 * time.Second); ok {
+		t.Error("no estimate before any work is done")
+	}
+
+	p.Advance(25)
+	if left, ok := p.Estimate(10 * time.Second); !ok || left != 30*time.Second {
+		t.Errorf("a quarter done in 10s: %v left, want 30s", left)
+	}
+
+	p.Set(150)
+	if p.Fraction() != 1 {
+		t.Errorf("fraction = %v, want done clamped to the total", p.Fraction())
+	}
+	if left, ok := p.Estimate(time.Minute); !ok || left != 0 {
+		t.Errorf("finished: %v, %v", left, ok)
+	}
+}
+
+func TestProgressView(t *testing.T) {
+	p := NewProgress(4)
+	p.Advance(1)
+	view := p.View(60, p.started.Add(75*time.Second))
+	for _, want := range []string{" 25%", "1m15s", "~3m45s left"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view %q lacks %q", view, want)
+		}
+	}
+	if w := lipgloss.Width(view); w != 60 {
+		t.Errorf("view is %d columns, want 60", w)
+	}
+}
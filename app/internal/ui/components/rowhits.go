@@ -0,0 +1,55 @@
+package components
+
+import "strings"
+
+// RowHits maps the lines of a rendered list back to its rows, so a mouse
+// click can select the row under it. A view Resets it, Adds each row at the
+// line it starts on and Ends the list after the last row; rows may span
+// several lines.
+type RowHits struct {
+	starts []int
+	rows   []int
+	end    int
+}
+
+// Reset forgets the rows of the previous render
+func (h *RowHits) Reset() {
+	h.starts = h.starts[:0]
+	h.rows = h.rows[:0]
+	h.end = 0
+}
+
+// Add records that row starts on line
+func (h *RowHits) Add(line, row int) {
+	h.starts = append(h.starts, line)
+	h.rows = append(h.rows, row)
+}
+
+// End records the line after the last row
+func (h *RowHits) End(line int) { h.end = line }
+
+// Shift moves every row down n lines, for lists rendered below a header
+// their view adds later
+func (h *RowHits) Shift(n int) {
+	for i := range h.starts {
+		h.starts[i] += n
+	}
+	h.end += n
+}
+
+// Row returns the row drawn on line
+func (h *RowHits) Row(line int) (int, bool) {
+	if len(h.starts) == 0 || line < h.starts[0] || line >= h.end {
+		return 0, false
+	}
+	i := len(h.starts) - 1
+	for h.starts[i] > line {
+		i--
+	}
+	return h.rows[i], true
+}
+
+// NextLine returns the line the next write to s starts on
+func NextLine(s string) int {
+	return strings.Count(s, "\n")
+}
@@ -0,0 +1,40 @@
+package components
+
+import "testing"
+
+func TestRowHits(t *testing.T) {
+	var h RowHits
+	// Row 0 is two lines tall (it shows a description), rows 1 and 2 one
+	h.Add(4, 0)
+	h.Add(6, 1)
+	h.Add(7, 2)
+	h.End(8)
+
+	tests := []struct {
+		line int
+		row  int
+		ok   bool
+	}{
+		{3, 0, false},
+		{4, 0, true},
+		{5, 0, true},
+		{6, 1, true},
+		{7, 2, true},
+		{8, 0, false},
+	}
+	for _, tt := range tests {
+		if row, ok := h.Row(tt.line); row != tt.row || ok != tt.ok {
+			t.Errorf("Row(%d) = %d, %v; want %d, %v", tt.line, row, ok, tt.row, tt.ok)
+		}
+	}
+
+	h.Shift(2)
+	if row, ok := h.Row(9); !ok || row != 2 {
+		t.Errorf("after Shift(2), Row(9) = %d, %v; want 2, true", row, ok)
+	}
+
+	h.Reset()
+	if _, ok := h.Row(6); ok {
+		t.Error("Reset should forget every row")
+	}
+}
@@ -0,0 +1,146 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ScrollView shows Height lines of longer content with a scrollbar on the
+// right. Unlike Viewport it keeps its offset, so the rest of the content
+// stays reachable with the arrows, PgUp/PgDn, Home/End and the wheel.
+type ScrollView struct {
+	// Height is how many lines are shown
+	Height int
+	// Follow keeps the view on the last line while content grows, as long
+	// as it was scrolled to the bottom, like tail -f
+	Follow bool
+
+	lines  []string
+	offset int
+}
+
+// SetContent replaces the content, keeping the offset where possible
+func (v *ScrollView) SetContent(content string) {
+	v.SetLines(strings.Split(strings.TrimRight(content, "\n"), "\n"))
+}
+
+// SetLines replaces the content with lines
+func (v *ScrollView) SetLines(lines []string) {
+	follow := v.Follow && v.AtBottom()
+	v.lines = lines
+	if follow {
+		v.GotoBottom()
+	} else {
+		v.ScrollBy(0)
+	}
+}
+
+// ScrollBy moves the view down (positive) or up by lines, stopping at the
+// first and last lines
+func (v *ScrollView) ScrollBy(lines int) {
+	v.offset = max(0, min(v.offset+lines, v.maxOffset()))
+}
+
+// GotoTop shows the first lines
+func (v *ScrollView) GotoTop() { v.offset = 0 }
+
+// GotoBottom shows the last lines
+func (v *ScrollView) GotoBottom() { v.offset = v.maxOffset() }
+
+// Nav scrolls a line or to either end
+func (v *ScrollView) Nav(nav events.Nav) {
+	switch nav {
+	case events.NavUp:
+		v.ScrollBy(-1)
+	case events.NavDown:
+		v.ScrollBy(1)
+	case events.NavTop:
+		v.GotoTop()
+	case events.NavBottom:
+		v.GotoBottom()
+	}
+}
+
+// HandleKey pages with PgUp/PgDn and jumps with Home/End, and reports
+// whether it consumed the key
+func (v *ScrollView) HandleKey(key string) bool {
+	switch key {
+	case "pgup":
+		v.ScrollBy(-v.PageSize())
+	case "pgdown":
+		v.ScrollBy(v.PageSize())
+	case "home":
+		v.GotoTop()
+	case "end":
+		v.GotoBottom()
+	default:
+		return false
+	}
+	return true
+}
+
+// PageSize is how far PgUp/PgDn move: a screen, keeping one line of the
+// last for context
+func (v *ScrollView) PageSize() int {
+	return max(v.Height-1, 1)
+}
+
+// EnsureVisible scrolls the least needed to show line, for lists whose
+// cursor moves through the content
+func (v *ScrollView) EnsureVisible(line int) {
+	if line < v.offset {
+		v.offset = line
+	} else if v.Height > 0 && line >= v.offset+v.Height {
+		v.offset = line - v.Height + 1
+	}
+	v.ScrollBy(0)
+}
+
+// Offset is the index of the first line shown
+func (v *ScrollView) Offset() int { return v.offset }
+
+// AtBottom reports whether the last line is shown
+func (v *ScrollView) AtBottom() bool { return v.offset >= v.maxOffset() }
+
+// Scrollable reports whether the content is taller than the view
+func (v *ScrollView) Scrollable() bool { return v.maxOffset() > 0 }
+
+func (v *ScrollView) maxOffset() int {
+	return max(len(v.lines)-max(v.Height, 1), 0)
+}
+
+// View renders the visible lines. Content taller than the view gets a
+// scrollbar whose thumb shows the position and share of what's visible.
+func (v *ScrollView) View() string {
+	v.ScrollBy(0)
+	end := min(v.offset+max(v.Height, 1), len(v.lines))
+	visible := v.lines[v.offset:end]
+	if !v.Scrollable() {
+		return strings.Join(visible, "\n")
+	}
+
+	width := 0
+	for _, line := range visible {
+		width = max(width, lipgloss.Width(line))
+	}
+
+	// The thumb is as tall as the visible share of the content and moves
+	// from the top to the bottom of the track as the view scrolls
+	height := len(visible)
+	thumb := max(height*height/len(v.lines), 1)
+	thumbTop := v.offset * (height - thumb) / v.maxOffset()
+
+	track := lipgloss.NewStyle().Foreground(ColorFaint).Render("│")
+	bar := lipgloss.NewStyle().Foreground(ColorPrimary).Render("┃")
+	rows := make([]string, len(visible))
+	for i, line := range visible {
+		marker := track
+		if i >= thumbTop && i < thumbTop+thumb {
+			marker = bar
+		}
+		rows[i] = line + strings.Repeat(" ", width-lipgloss.Width(line)+1) + marker
+	}
+	return strings.Join(rows, "\n")
+}
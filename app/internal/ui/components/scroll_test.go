@@ -0,0 +1,122 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/events"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	return lines
+}
+
+func TestScrollViewKeys(t *testing.T) {
+	v := ScrollView{Height: 5}
+	v.SetLines(numberedLines(20))
+
+	if v.HandleKey("x") {
+		t.Error("other keys should pass through")
+	}
+	for _, step := range []struct {
+		key  string
+		want int
+	}{
+		{"pgdown", 4},
+		{"pgdown", 8},
+		{"end", 15},
+		{"pgdown", 15},
+		{"pgup", 11},
+		{"home", 0},
+		{"pgup", 0},
+	} {
+		if !v.HandleKey(step.key) || v.Offset() != step.want {
+			t.Fatalf("%s: offset = %d, want %d", step.key, v.Offset(), step.want)
+		}
+	}
+
+	v.Nav(events.NavDown)
+	v.Nav(events.NavDown)
+	v.Nav(events.NavUp)
+	if v.Offset() != 1 {
+		t.Errorf("offset after nav = %d, want 1", v.Offset())
+	}
+	v.Nav(events.NavBottom)
+	if !v.AtBottom() {
+		t.Error("NavBottom should show the last line")
+	}
+}
+
+func TestScrollViewEnsureVisible(t *testing.T) {
+	v := ScrollView{Height: 5}
+	v.SetLines(numberedLines(20))
+
+	v.EnsureVisible(3)
+	if v.Offset() != 0 {
+		t.Errorf("a visible line should not scroll, offset = %d", v.Offset())
+	}
+	v.EnsureVisible(12)
+	if v.Offset() != 8 {
+		t.Errorf("offset = %d, want 8 with line 12 at the bottom", v.Offset())
+	}
+	v.EnsureVisible(6)
+	if v.Offset() != 6 {
+		t.Errorf("offset = %d, want 6 with line 6 at the top", v.Offset())
+	}
+}
+
+func TestScrollViewFollow(t *testing.T) {
+	v := ScrollView{Height: 5, Follow: true}
+	v.SetLines(numberedLines(20))
+	if v.Offset() != 15 {
+		t.Fatalf("a following view should open at the bottom, offset = %d", v.Offset())
+	}
+	v.SetLines(numberedLines(30))
+	if v.Offset() != 25 {
+		t.Errorf("new lines should keep the bottom in view, offset = %d", v.Offset())
+	}
+
+	v.ScrollBy(-10)
+	v.SetLines(numberedLines(40))
+	if v.Offset() != 15 {
+		t.Errorf("a view scrolled up should stay put, offset = %d", v.Offset())
+	}
+
+	v.SetLines(numberedLines(3))
+	if v.Offset() != 0 || v.Scrollable() {
+		t.Errorf("shorter content should clamp the offset, offset = %d", v.Offset())
+	}
+}
+
+func TestScrollViewView(t *testing.T) {
+	v := ScrollView{Height: 4}
+	v.SetContent("short\ncontent\n")
+	if got := v.View(); got != "short\ncontent" {
+		t.Errorf("content that fits should render as is, got %q", got)
+	}
+
+	v.SetLines(numberedLines(8))
+	v.HandleKey("end")
+	rows := strings.Split(v.View(), "\n")
+	if len(rows) != 4 || !strings.HasPrefix(rows[0], "line 4") || !strings.HasPrefix(rows[3], "line 7") {
+		t.Fatalf("rows = %q", rows)
+	}
+	for _, row := range rows {
+		if lipgloss.Width(row) != len("line 4")+2 {
+			t.Errorf("row %q should be padded to line up the scrollbar", row)
+		}
+	}
+	// Half the content is visible, so the thumb fills the bottom half
+	for i, row := range rows {
+		thumb := strings.HasSuffix(row, "┃")
+		if thumb != (i >= 2) {
+			t.Errorf("row %d thumb = %v in %q", i, thumb, rows)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package components
+
+import "strings"
+
+// sparkBlocks are the bar heights of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the last width values as a one-line bar graph, scaled so
+// that top fills a cell. A top of 0 or less scales to the largest value,
+// for series without a fixed range such as network rates. Values of 0 or
+// less are left blank, so a history that hasn't filled up yet grows in
+// from the right.
+func Sparkline(values []float64, width int, top float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if top <= 0 {
+		for _, v := range values {
+			top = max(top, v)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		if v <= 0 || top <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int(v / top * float64(len(sparkBlocks)))
+		b.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
@@ -0,0 +1,24 @@
+package components
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		top    float64
+		want   string
+	}{
+		{"fixed range", []float64{0, 10, 50, 100, 120}, 5, 100, " ▁▅██"},
+		{"pads on the left", []float64{100, 50}, 4, 100, "  █▅"},
+		{"keeps the latest", []float64{100, 100, 0, 50}, 2, 100, " ▅"},
+		{"autoscale", []float64{1, 2, 4}, 3, 0, "▃▅█"},
+		{"all zero", []float64{0, 0}, 2, 0, "  "},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values, tt.width, tt.top); got != tt.want {
+			t.Errorf("%s: Sparkline(%v, %d, %v) = %q, want %q", tt.name, tt.values, tt.width, tt.top, got, tt.want)
+		}
+	}
+}
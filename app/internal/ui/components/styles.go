@@ -5,44 +5,44 @@ import "github.com/charmbracelet/lipgloss"
 // Theme defines the color palette
 type Theme struct {
 	// Primary colors
-	Primary   lipgloss.Color
-	Secondary lipgloss.Color
-	Accent    lipgloss.Color
+	Primary   lipgloss.AdaptiveColor
+	Secondary lipgloss.AdaptiveColor
+	Accent    lipgloss.AdaptiveColor
 
 	// Status colors
-	Success lipgloss.Color
-	Warning lipgloss.Color
-	Error   lipgloss.Color
-	Info    lipgloss.Color
+	Success lipgloss.AdaptiveColor
+	Warning lipgloss.AdaptiveColor
+	Error   lipgloss.AdaptiveColor
+	Info    lipgloss.AdaptiveColor
 
 	// UI colors
-	Background lipgloss.Color
-	Foreground lipgloss.Color
-	Muted      lipgloss.Color
-	Border     lipgloss.Color
+	Background lipgloss.AdaptiveColor
+	Foreground lipgloss.AdaptiveColor
+	Muted      lipgloss.AdaptiveColor
+	Border     lipgloss.AdaptiveColor
 
 	// Special
-	Highlight lipgloss.Color
+	Highlight lipgloss.AdaptiveColor
 }
 
-// DefaultTheme returns the cyberpunk-inspired theme
+// DefaultTheme returns the theme of the active palette (see UsePalette)
 func DefaultTheme() Theme {
 	return Theme{
-		Primary:    lipgloss.Color("#00D9FF"), // Cyan
-		Secondary:  lipgloss.Color("#FFA500"), // Orange
-		Accent:     lipgloss.Color("#FF6AC1"), // Pink
+		Primary:   ColorPrimary,
+		Secondary: ColorWarning,
+		Accent:    ColorAccent,
 
-		Success:    lipgloss.Color("#0FD976"), // Green
-		Warning:    lipgloss.Color("#FFA500"), // Orange
-		Error:      lipgloss.Color("#FF6B6B"), // Red
-		Info:       lipgloss.Color("#00D9FF"), // Cyan
+		Success: ColorSuccess,
+		Warning: ColorWarning,
+		Error:   ColorError,
+		Info:    ColorPrimary,
 
-		Background: lipgloss.Color("#0A0A0F"), // Very dark blue
-		Foreground: lipgloss.Color("#FFFFFF"), // White
-		Muted:      lipgloss.Color("#666666"), // Gray
-		Border:     lipgloss.Color("#333333"), // Dark gray
+		Background: ColorBackground,
+		Foreground: ColorBright,
+		Muted:      ColorMuted,
+		Border:     ColorBorder,
 
-		Highlight:  lipgloss.Color("#FFD700"), // Gold
+		Highlight: ColorHighlight,
 	}
 }
 
@@ -121,7 +121,7 @@ func (s *BaseStyles) Info() lipgloss.Style {
 }
 
 // Box creates a bordered box style with proper dimensions
-func (s *BaseStyles) Box(width, height int, borderColor lipgloss.Color) lipgloss.Style {
+func (s *BaseStyles) Box(width, height int, borderColor lipgloss.AdaptiveColor) lipgloss.Style {
 	style := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(borderColor).
@@ -150,7 +150,7 @@ func (s *BaseStyles) ActiveCard(width, height int) lipgloss.Style {
 
 // StatusIndicator returns a styled status indicator
 func (s *BaseStyles) StatusIndicator(status string, level string) string {
-	var color lipgloss.Color
+	var color lipgloss.AdaptiveColor
 	switch level {
 	case "success", "good", "healthy":
 		color = s.Theme.Success
@@ -188,7 +188,7 @@ func (s *BaseStyles) ProgressBar(percent float64, width int) string {
 	}
 
 	// Determine color based on percentage
-	var barColor lipgloss.Color
+	var barColor lipgloss.AdaptiveColor
 	if percent >= 90 {
 		barColor = s.Theme.Error
 	} else if percent >= 75 {
@@ -218,7 +218,7 @@ func (s *BaseStyles) Spinner(frame int) string {
 }
 
 // Badge renders a small badge
-func (s *BaseStyles) Badge(text string, color lipgloss.Color) string {
+func (s *BaseStyles) Badge(text string, color lipgloss.AdaptiveColor) string {
 	return lipgloss.NewStyle().
 		Foreground(color).
 		Background(s.Theme.Background).
@@ -0,0 +1,167 @@
+package components
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Column describes one column of a Table
+type Column struct {
+	Title string
+	// Width pads or truncates cells; 0 leaves the cell as is, for the last
+	// column
+	Width int
+	// Align is lipgloss.Left or lipgloss.Right
+	Align lipgloss.Position
+	// Key sorts the table by this column when pressed; "" isn't sortable
+	Key string
+	// Numeric sorts by the number the cell starts with instead of its text
+	Numeric bool
+}
+
+// Table lays out rows in columns and sorts them by the column whose key
+// was pressed; pressing it again reverses the order. Like ListFilter it
+// keeps no rows: the list sorts its own indexes and renders each row.
+type Table struct {
+	Columns []Column
+	sortBy  int // column index + 1, 0 when unsorted
+	desc    bool
+}
+
+// NewTable returns an unsorted table with columns
+func NewTable(columns ...Column) Table {
+	return Table{Columns: columns}
+}
+
+// HandleKey sorts by the column whose key is key and reports whether it
+// consumed the key
+func (t *Table) HandleKey(key string) bool {
+	for i, col := range t.Columns {
+		if col.Key == "" || col.Key != key {
+			continue
+		}
+		if t.sortBy == i+1 {
+			t.desc = !t.desc
+		} else {
+			t.sortBy = i + 1
+			t.desc = false
+		}
+		return true
+	}
+	return false
+}
+
+// SortedBy returns the title of the column the table is sorted by and
+// whether the order is descending; "" when unsorted
+func (t *Table) SortedBy() (string, bool) {
+	if t.sortBy == 0 {
+		return "", false
+	}
+	return t.Columns[t.sortBy-1].Title, t.desc
+}
+
+// SortByTitle sorts by the column titled title, e.g. to restore a saved
+// order. Unknown titles leave the table unsorted.
+func (t *Table) SortByTitle(title string, desc bool) {
+	t.sortBy, t.desc = 0, false
+	for i, col := range t.Columns {
+		if col.Title == title && col.Key != "" {
+			t.sortBy, t.desc = i+1, desc
+		}
+	}
+}
+
+// Sort orders indexes by the sorted column, keeping their order among
+// equal cells. cell returns the text of a row's column.
+func (t *Table) Sort(indexes []int, cell func(index, column int) string) {
+	if t.sortBy == 0 {
+		return
+	}
+	column := t.sortBy - 1
+	numeric := t.Columns[column].Numeric
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := cell(indexes[i], column), cell(indexes[j], column)
+		if t.desc {
+			a, b = b, a
+		}
+		if numeric {
+			x, xok := leadingNumber(a)
+			y, yok := leadingNumber(b)
+			if xok && yok && x != y {
+				return x < y
+			}
+			if xok != yok {
+				return xok // numbers before text
+			}
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+}
+
+// leadingNumber parses the number a cell starts with, e.g. 12.5 in "12.5 MB"
+func leadingNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || end == 0 && s[end] == '-') {
+		end++
+	}
+	n, err := strconv.ParseFloat(s[:end], 64)
+	return n, err == nil
+}
+
+// Header renders the column titles, marking the sorted column with ▲ or ▼
+func (t *Table) Header() string {
+	titles := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		titles[i] = col.Title
+		if t.sortBy == i+1 {
+			arrow := " ▲"
+			if t.desc {
+				arrow = " ▼"
+			}
+			titles[i] += arrow
+		}
+	}
+	return t.Row(titles...)
+}
+
+// Row lays out cells in the columns, padding, aligning and truncating
+// each to its width
+func (t *Table) Row(cells ...string) string {
+	parts := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		if col.Width > 0 {
+			cell = TruncateString(cell, col.Width)
+			pad := strings.Repeat(" ", col.Width-lipgloss.Width(cell))
+			if col.Align == lipgloss.Right {
+				cell = pad + cell
+			} else {
+				cell += pad
+			}
+		}
+		parts[i] = cell
+	}
+	return strings.TrimRight(strings.Join(parts, " "), " ")
+}
+
+// SortHint describes the sort keys for a view's key legend, e.g.
+// "[c/p/u] Sort"
+func (t *Table) SortHint() string {
+	var keys []string
+	for _, col := range t.Columns {
+		if col.Key != "" {
+			keys = append(keys, col.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(keys, "/") + "] Sort"
+}
@@ -0,0 +1,74 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func testTable() Table {
+	return NewTable(
+		Column{Title: "NAME", Width: 8, Key: "n"},
+		Column{Title: "PID", Width: 5, Align: lipgloss.Right, Key: "p", Numeric: true},
+		Column{Title: "NOTE"},
+	)
+}
+
+func TestTableSort(t *testing.T) {
+	rows := [][]string{
+		{"node", "912", ""},
+		{"Caddy", "80", ""},
+		{"beam", "4001", ""},
+	}
+	cell := func(index, column int) string { return rows[index][column] }
+	table := testTable()
+
+	indexes := []int{0, 1, 2}
+	table.Sort(indexes, cell)
+	if !reflect.DeepEqual(indexes, []int{0, 1, 2}) {
+		t.Errorf("unsorted table reordered rows: %v", indexes)
+	}
+
+	if table.HandleKey("x") || table.HandleKey("") {
+		t.Error("keys without a column should pass through")
+	}
+	table.HandleKey("n")
+	table.Sort(indexes, cell)
+	if want := []int{2, 1, 0}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("by name = %v, want %v (case-insensitive)", indexes, want)
+	}
+
+	table.HandleKey("p")
+	table.Sort(indexes, cell)
+	if want := []int{1, 0, 2}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("by pid = %v, want %v (numeric)", indexes, want)
+	}
+
+	table.HandleKey("p")
+	table.Sort(indexes, cell)
+	if want := []int{2, 0, 1}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("by pid again = %v, want %v (reversed)", indexes, want)
+	}
+	if title, desc := table.SortedBy(); title != "PID" || !desc {
+		t.Errorf("SortedBy = %q, %v", title, desc)
+	}
+}
+
+func TestTableRow(t *testing.T) {
+	table := testTable()
+	if got, want := table.Row("postgres-dev", "42", "listening"), "postg...    42 listening"; got != want {
+		t.Errorf("Row = %q, want %q", got, want)
+	}
+	if got, want := table.Row("redis", "7"), "redis        7"; got != want {
+		t.Errorf("Row with a missing cell = %q, want %q", got, want)
+	}
+
+	table.SortByTitle("PID", true)
+	if got, want := table.Header(), "NAME     PID ▼ NOTE"; got != want {
+		t.Errorf("Header = %q, want %q", got, want)
+	}
+	if got := table.SortHint(); got != "[n/p] Sort" {
+		t.Errorf("SortHint = %q", got)
+	}
+}
@@ -0,0 +1,180 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ToastKind sets a toast's color, icon and how long it stays up
+type ToastKind int
+
+const (
+	ToastInfo ToastKind = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// maxToasts is how many toasts are shown at once; older ones are dropped
+const maxToasts = 3
+
+// duration returns how long a toast of this kind stays up. Problems stay
+// longer so they aren't missed.
+func (k ToastKind) duration() time.Duration {
+	switch k {
+	case ToastWarning:
+		return 6 * time.Second
+	case ToastError:
+		return 8 * time.Second
+	default:
+		return 4 * time.Second
+	}
+}
+
+func (k ToastKind) icon() string {
+	switch k {
+	case ToastSuccess:
+		return "✓"
+	case ToastWarning:
+		return "⚠"
+	case ToastError:
+		return "✗"
+	default:
+		return "ℹ"
+	}
+}
+
+func (k ToastKind) color() lipgloss.AdaptiveColor {
+	switch k {
+	case ToastSuccess:
+		return ColorSuccess
+	case ToastWarning:
+		return ColorWarning
+	case ToastError:
+		return ColorError
+	default:
+		return ColorPrimary
+	}
+}
+
+// ToastMsg asks the shell to show a toast. Modules return it from a
+// command (see Toast); the shell fills in Source with the module title.
+type ToastMsg struct {
+	Kind   ToastKind
+	Text   string
+	Source string
+	// Result, when set, opens the finished task's result in the source
+	// module. The shell offers Ctrl+O to jump there and deliver it.
+	Result tea.Msg
+}
+
+// Toast returns a command that shows a toast
+func Toast(kind ToastKind, text string) tea.Cmd {
+	return func() tea.Msg { return ToastMsg{Kind: kind, Text: text} }
+}
+
+// StatusToast returns a command that toasts a module's status line,
+// taking the kind from its leading ✓, ⚠ or ✗ so existing messages can be
+// reused as they are
+func StatusToast(status string) tea.Cmd {
+	return ResultToast(status, nil)
+}
+
+// ResultToast is StatusToast for a task whose result the module can show
+// again: result is delivered back to the module when the user asks to view
+// it from another tab
+func ResultToast(status string, result tea.Msg) tea.Cmd {
+	kind := ToastInfo
+	for _, k := range []ToastKind{ToastSuccess, ToastWarning, ToastError} {
+		if rest, ok := strings.CutPrefix(status, k.icon()); ok {
+			kind, status = k, strings.TrimSpace(rest)
+			break
+		}
+	}
+	return func() tea.Msg { return ToastMsg{Kind: kind, Text: status, Result: result} }
+}
+
+type toast struct {
+	ToastMsg
+	expires time.Time
+}
+
+// Toasts is the stack of toasts the shell shows above its footer
+type Toasts struct {
+	items []toast
+}
+
+// Push shows msg until its kind's duration has passed
+func (t *Toasts) Push(msg ToastMsg, now time.Time) {
+	t.items = append(t.items, toast{ToastMsg: msg, expires: now.Add(msg.Kind.duration())})
+	if len(t.items) > maxToasts {
+		t.items = t.items[len(t.items)-maxToasts:]
+	}
+}
+
+// Expire drops toasts whose time is up
+func (t *Toasts) Expire(now time.Time) {
+	kept := t.items[:0]
+	for _, item := range t.items {
+		if now.Before(item.expires) {
+			kept = append(kept, item)
+		}
+	}
+	t.items = kept
+}
+
+// Clear dismisses every toast
+func (t *Toasts) Clear() { t.items = nil }
+
+// Len returns how many toasts are showing
+func (t *Toasts) Len() int { return len(t.items) }
+
+// TakeResult removes the newest toast that has a Result and returns it
+func (t *Toasts) TakeResult() (ToastMsg, bool) {
+	for i := len(t.items) - 1; i >= 0; i-- {
+		if t.items[i].Result != nil {
+			msg := t.items[i].ToastMsg
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			return msg, true
+		}
+	}
+	return ToastMsg{}, false
+}
+
+// Latest returns the newest toast's text, or "" when there is none
+func (t *Toasts) Latest() string {
+	if len(t.items) == 0 {
+		return ""
+	}
+	return t.items[len(t.items)-1].Text
+}
+
+// View renders the toasts right-aligned within width, newest last, or ""
+// when there are none
+func (t *Toasts) View(width int) string {
+	if len(t.items) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(t.items))
+	for _, item := range t.items {
+		text := item.Text
+		if item.Source != "" {
+			text = item.Source + " • " + text
+		}
+		style := lipgloss.NewStyle().
+			Foreground(item.Kind.color()).
+			Background(ColorPanel).
+			Bold(true).
+			Padding(0, 1)
+		hint := ""
+		if item.Result != nil {
+			hint = " • ^O view"
+		}
+		line := style.Render(TruncateString(item.Kind.icon()+" "+strings.Join(strings.Fields(text), " "), width-2-lipgloss.Width(hint)) + hint)
+		lines = append(lines, lipgloss.NewStyle().Width(width).Align(lipgloss.Right).Render(line))
+	}
+	return strings.Join(lines, "\n")
+}
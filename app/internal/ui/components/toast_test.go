@@ -0,0 +1,79 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToastsExpireByKind(t *testing.T) {
+	now := time.Now()
+	var toasts Toasts
+	toasts.Push(ToastMsg{Kind: ToastSuccess, Text: "done"}, now)
+	toasts.Push(ToastMsg{Kind: ToastError, Text: "failed"}, now)
+
+	toasts.Expire(now.Add(5 * time.Second))
+	if toasts.Len() != 1 || toasts.Latest() != "failed" {
+		t.Fatalf("after 5s got %d toasts, latest %q; want only the error", toasts.Len(), toasts.Latest())
+	}
+	toasts.Expire(now.Add(9 * time.Second))
+	if toasts.Len() != 0 {
+		t.Errorf("after 9s got %d toasts, want none", toasts.Len())
+	}
+}
+
+func TestToastsKeepNewest(t *testing.T) {
+	now := time.Now()
+	var toasts Toasts
+	for _, text := range []string{"one", "two", "three", "four"} {
+		toasts.Push(ToastMsg{Text: text}, now)
+	}
+	view := toasts.View(60)
+	if toasts.Len() != maxToasts || strings.Contains(view, "one") || !strings.Contains(view, "four") {
+		t.Errorf("want the %d newest toasts, got:\n%s", maxToasts, view)
+	}
+}
+
+func TestToastViewShowsSource(t *testing.T) {
+	var toasts Toasts
+	toasts.Push(ToastMsg{Kind: ToastWarning, Text: "Fixed 3 issues", Source: "Quick Actions"}, time.Now())
+	if view := toasts.View(60); !strings.Contains(view, "⚠ Quick Actions • Fixed 3 issues") {
+		t.Errorf("view = %q, want icon, source and text", view)
+	}
+}
+
+func TestStatusToastKind(t *testing.T) {
+	tests := []struct {
+		status string
+		want   ToastMsg
+	}{
+		{"✓ Cache cleaned", ToastMsg{Kind: ToastSuccess, Text: "Cache cleaned"}},
+		{"⚠ Fixed 2 issues (1 failed)", ToastMsg{Kind: ToastWarning, Text: "Fixed 2 issues (1 failed)"}},
+		{"✗ Listing timed out", ToastMsg{Kind: ToastError, Text: "Listing timed out"}},
+		{"Loaded 12 packages", ToastMsg{Kind: ToastInfo, Text: "Loaded 12 packages"}},
+	}
+	for _, tt := range tests {
+		if got := StatusToast(tt.status)(); got != tt.want {
+			t.Errorf("StatusToast(%q) = %+v, want %+v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestTakeResult(t *testing.T) {
+	type showResults struct{}
+	var toasts Toasts
+	now := time.Now()
+	toasts.Push(ToastMsg{Text: "Cleaned 3 items", Source: "Cleanup", Result: showResults{}}, now)
+	toasts.Push(ToastMsg{Text: "Copied"}, now)
+	if view := toasts.View(60); !strings.Contains(view, "Cleanup • Cleaned 3 items • ^O view") {
+		t.Errorf("view = %q, want the view hint", view)
+	}
+
+	msg, ok := toasts.TakeResult()
+	if !ok || msg.Source != "Cleanup" || msg.Result != (showResults{}) {
+		t.Fatalf("TakeResult = %+v, %v", msg, ok)
+	}
+	if _, ok := toasts.TakeResult(); ok || toasts.Latest() != "Copied" {
+		t.Errorf("the result toast should be gone and the other kept")
+	}
+}
@@ -0,0 +1,118 @@
+// Package dialog is the confirmation dialog shared by modules. A module
+// opens one by returning Confirm's command; the app shows it over the
+// screen and delivers OnConfirm back to that module only if the user says
+// yes, so destructive actions need no dialog state of their own.
+package dialog
+
+import (
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Request describes a confirmation dialog
+type Request struct {
+	Title string
+	// Detail explains what will happen, e.g. the files to be deleted
+	Detail string
+	// ConfirmLabel names the confirm button; "Confirm" when empty
+	ConfirmLabel string
+	// Destructive shows the confirm button in the error color
+	Destructive bool
+	// OnConfirm is delivered to the module that opened the dialog when
+	// the user confirms
+	OnConfirm tea.Msg
+	// OnCancel, when set, is delivered when the user cancels
+	OnCancel tea.Msg
+}
+
+// Confirm returns a command that opens a dialog for req
+func Confirm(req Request) tea.Cmd {
+	return func() tea.Msg { return req }
+}
+
+// Model is an open dialog
+type Model struct {
+	req     Request
+	module  string
+	confirm bool // focus is on the confirm button
+}
+
+// New opens req on behalf of module. Focus starts on Cancel, so an Enter
+// pressed out of habit doesn't confirm a destructive action.
+func New(module string, req Request) *Model {
+	if req.ConfirmLabel == "" {
+		req.ConfirmLabel = "Confirm"
+	}
+	return &Model{req: req, module: module}
+}
+
+// Module returns the ID of the module that opened the dialog
+func (d *Model) Module() string { return d.module }
+
+// Update handles a key press. done is true once the dialog should close;
+// result is then OnConfirm or OnCancel, or nil when there's nothing to
+// deliver.
+func (d *Model) Update(msg tea.KeyMsg) (result tea.Msg, done bool) {
+	switch strings.ToLower(msg.String()) {
+	case "y":
+		return d.req.OnConfirm, true
+	case "n", "esc", "q":
+		return d.req.OnCancel, true
+	case "enter":
+		if d.confirm {
+			return d.req.OnConfirm, true
+		}
+		return d.req.OnCancel, true
+	case "left", "right", "tab", "shift+tab", "h", "l":
+		d.confirm = !d.confirm
+	}
+	return nil, false
+}
+
+// View renders the dialog centered in a width x height screen
+func (d *Model) View(width, height int) string {
+	boxWidth := min(56, max(width-4, 30))
+
+	border := components.ColorPrimary
+	confirmColor := components.ColorPrimary
+	if d.req.Destructive {
+		border = components.ColorError
+		confirmColor = components.ColorError
+	}
+	boxStyle := lipgloss.NewStyle().
+		Width(boxWidth).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(1, 2)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(components.ColorBright)
+	detailStyle := lipgloss.NewStyle().Foreground(components.ColorText).Width(boxWidth - 6)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	button := lipgloss.NewStyle().Padding(0, 2)
+	focused := button.Copy().Bold(true).Foreground(components.ColorOverlay)
+
+	cancel := button.Render("Cancel")
+	confirm := button.Copy().Foreground(confirmColor).Render(d.req.ConfirmLabel)
+	if d.confirm {
+		confirm = focused.Copy().Background(confirmColor).Render(d.req.ConfirmLabel)
+	} else {
+		cancel = focused.Copy().Background(components.ColorSubtle).Render("Cancel")
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(d.req.Title))
+	b.WriteString("\n")
+	if d.req.Detail != "" {
+		b.WriteString("\n")
+		b.WriteString(detailStyle.Render(d.req.Detail))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cancel, "  ", confirm))
+	b.WriteString("\n\n")
+	b.WriteString(controlStyle.Render("Y confirm • N/Esc cancel • ←/→ choose • Enter select"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
@@ -0,0 +1,43 @@
+package dialog
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type stopMsg struct{}
+type keepMsg struct{}
+
+func TestKeys(t *testing.T) {
+	req := Request{Title: "Stop web?", OnConfirm: stopMsg{}, OnCancel: keepMsg{}}
+	tests := []struct {
+		keys []tea.KeyMsg
+		want tea.Msg
+	}{
+		{[]tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("Y")}}, stopMsg{}},
+		{[]tea.KeyMsg{{Type: tea.KeyEsc}}, keepMsg{}},
+		{[]tea.KeyMsg{{Type: tea.KeyEnter}}, keepMsg{}},
+		{[]tea.KeyMsg{{Type: tea.KeyRight}, {Type: tea.KeyEnter}}, stopMsg{}},
+		{[]tea.KeyMsg{{Type: tea.KeyTab}, {Type: tea.KeyTab}, {Type: tea.KeyEnter}}, keepMsg{}},
+	}
+	for _, tt := range tests {
+		d := New("Docker", req)
+		var got tea.Msg
+		done := false
+		for _, key := range tt.keys {
+			if done {
+				t.Fatalf("%v: closed before the last key", tt.keys)
+			}
+			got, done = d.Update(key)
+		}
+		if !done || got != tt.want {
+			t.Errorf("%v: got %#v done = %v, want %#v", tt.keys, got, done, tt.want)
+		}
+	}
+
+	d := New("Docker", req)
+	if _, done := d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}); done {
+		t.Error("other keys should leave the dialog open")
+	}
+}
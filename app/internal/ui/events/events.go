@@ -1,7 +1,93 @@
 package events
 
+import tea "github.com/charmbracelet/bubbletea"
+
 // Focus is sent when the user enters a module to interact with it.
 type Focus struct{}
 
 // Blur is sent when the user leaves a module interaction mode.
 type Blur struct{}
+
+// ModuleMsg tags a message produced by a module's command with the ID of
+// that module, so the app delivers it to its owner even when another module
+// is active.
+type ModuleMsg struct {
+	Module string
+	Msg    tea.Msg
+}
+
+// Wrap returns a command whose result is tagged with the given module ID.
+// Batched commands are wrapped individually so every result stays tagged.
+// Commands built with tea.Sequence are not supported.
+func Wrap(module string, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if msg == nil {
+			return nil
+		}
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			wrapped := make(tea.BatchMsg, 0, len(batch))
+			for _, c := range batch {
+				if c != nil {
+					wrapped = append(wrapped, Wrap(module, c))
+				}
+			}
+			return wrapped
+		}
+		return ModuleMsg{Module: module, Msg: msg}
+	}
+}
+
+// Nav is a normalized navigation request. The app translates arrow keys,
+// j/k, the vim-mode motions and the mouse wheel into Nav, so modules move
+// their cursor the same way whichever input the user prefers.
+type Nav int
+
+const (
+	NavUp Nav = iota
+	NavDown
+	NavTop
+	NavBottom
+)
+
+// Move returns cursor moved by nav within a list of count items
+func (n Nav) Move(cursor, count int) int {
+	if count <= 0 {
+		return 0
+	}
+	switch n {
+	case NavUp:
+		cursor--
+	case NavDown:
+		cursor++
+	case NavTop:
+		cursor = 0
+	case NavBottom:
+		cursor = count - 1
+	}
+	return max(0, min(cursor, count-1))
+}
+
+// Click is a left click inside the active module, in cells relative to the
+// top-left corner of its View. The mouse wheel arrives as NavUp/NavDown.
+type Click struct {
+	X, Y int
+}
+
+// Alerting reports the alert rules firing after a check, for the shell
+// to show in the terminal title, and the alerts that just fired, which
+// it can post as terminal notifications
+type Alerting struct {
+	Firing []string // short, e.g. "Disk 92%"
+	Fired  []string // one line each
+}
+
+// Restored is sent by a module that replaced ~/.devcockpit with a backup.
+// The shell quits without saving its state, so nothing from this session
+// writes over the restored files, and shows Note on the way out.
+type Restored struct {
+	Note string
+}
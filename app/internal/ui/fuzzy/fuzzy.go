@@ -0,0 +1,85 @@
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Score reports whether text matches query and how well. Every
+// space-separated term of the query must appear in text as a subsequence
+// (case-insensitive). Consecutive characters and matches at word starts
+// score higher, so "flush dns" ranks "Flush DNS" above "Fix Launch Services".
+func Score(query, text string) (int, bool) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return 0, true
+	}
+
+	lower := []rune(strings.ToLower(text))
+	total := 0
+	for _, term := range terms {
+		score, ok := scoreTerm([]rune(term), lower)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+func scoreTerm(term, text []rune) (int, bool) {
+	score := 0
+	ti := 0
+	prev := -2
+	for i, r := range text {
+		if ti == len(term) {
+			break
+		}
+		if r != term[ti] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 3 // consecutive run
+		}
+		if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+			score += 5 // start of a word
+		}
+		prev = i
+		ti++
+	}
+	if ti < len(term) {
+		return 0, false
+	}
+	// Prefer shorter texts when everything else is equal
+	return score*100 - len(text), true
+}
+
+// Filter returns the indexes of texts matching query, best match first.
+// Ties keep their original order.
+func Filter(query string, texts []string) []int {
+	type match struct {
+		index int
+		score int
+	}
+
+	var matches []match
+	for i, text := range texts {
+		if score, ok := Score(query, text); ok {
+			matches = append(matches, match{index: i, score: score})
+		}
+	}
+
+	if strings.TrimSpace(query) != "" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+	}
+
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
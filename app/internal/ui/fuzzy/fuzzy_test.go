@@ -0,0 +1,39 @@
+package fuzzy
+
+import "testing"
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		query, text string
+		match       bool
+	}{
+		{"", "anything", true},
+		{"flush dns", "Flush DNS", true},
+		{"fdns", "Flush DNS", true},
+		{"dns flush", "Flush DNS", true},
+		{"prune", "Flush DNS", false},
+		{"dokcer", "Docker", false},
+	}
+	for _, tt := range tests {
+		if _, ok := Score(tt.query, tt.text); ok != tt.match {
+			t.Errorf("Score(%q, %q) match = %v, want %v", tt.query, tt.text, ok, tt.match)
+		}
+	}
+}
+
+func TestFilterRanksBestMatchFirst(t *testing.T) {
+	texts := []string{"Fix Launch Services", "Reset Network", "Flush DNS"}
+	got := Filter("flush dns", texts)
+	if len(got) == 0 || texts[got[0]] != "Flush DNS" {
+		t.Fatalf("Filter ranked %v, want Flush DNS first", got)
+	}
+}
+
+func TestFilterEmptyQueryKeepsOrder(t *testing.T) {
+	got := Filter("", []string{"b", "a", "c"})
+	for i, index := range got {
+		if index != i {
+			t.Fatalf("Filter(\"\") = %v, want original order", got)
+		}
+	}
+}
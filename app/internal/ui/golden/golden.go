@@ -0,0 +1,87 @@
+// Package golden snapshots rendered views into golden files with teatest's
+// RequireEqualOutput, after stripping the trailing padding that would churn
+// them. Set UPDATE_GOLDEN to rewrite the files under testdata/ after an
+// intentional layout change; it sets teatest's -update flag, which works
+// too for a single package:
+//
+//	UPDATE_GOLDEN=1 go test ./...
+//	go test ./internal/app -update
+//
+// Programs are driven with teatest itself: see TestProgram in internal/app.
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+)
+
+func init() {
+	// Snapshots compare layout, not colors
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		flag.Set("update", "true")
+	}
+}
+
+// Size is a terminal size a view is rendered at
+type Size struct {
+	Width  int
+	Height int
+}
+
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// Sizes are the standard terminal sizes every view is snapshotted at
+var Sizes = []Size{
+	{Width: 80, Height: 24},
+	{Width: 120, Height: 40},
+}
+
+// RequireEqual compares out with testdata/<test name>.golden, failing the
+// test with a unified diff when they differ
+func RequireEqual(t testing.TB, out string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", filepath.FromSlash(t.Name())+".golden")
+	if f := flag.Lookup("update"); f == nil || f.Value.String() != "true" {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("missing golden file %s (run with UPDATE_GOLDEN=1 to create it): %v", path, err)
+		}
+	}
+	teatest.RequireEqualOutput(t, []byte(normalize(out)))
+}
+
+// RequireFits fails the test when out is wider or taller than size
+func RequireFits(t testing.TB, out string, size Size) {
+	t.Helper()
+
+	lines := strings.Split(out, "\n")
+	if len(lines) > size.Height {
+		t.Errorf("view is %d lines tall, want at most %d", len(lines), size.Height)
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w > size.Width {
+			t.Errorf("line %d is %d columns wide, want at most %d: %q", i+1, w, size.Width, line)
+		}
+	}
+}
+
+// normalize strips trailing whitespace so padding changes don't churn files
+func normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
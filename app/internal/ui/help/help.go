@@ -0,0 +1,93 @@
+// Package help renders keybinding tables for the ? overlay. The shell
+// builds the global help with it, and a focused module that implements
+// Provider gets its own help instead.
+package help
+
+import (
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Binding is one row of a keybinding table
+type Binding struct {
+	Key  string
+	Desc string
+}
+
+// Section is a titled group of bindings, optionally followed by a note
+type Section struct {
+	Title    string
+	Bindings []Binding
+	Note     string
+}
+
+// Help is everything the overlay shows for a module or the shell
+type Help struct {
+	Title       string
+	Description string
+	Sections    []Section
+}
+
+// Provider is implemented by modules with their own help
+type Provider interface {
+	Help() Help
+}
+
+// View renders h in a box centered in width x height, with footer below
+// the sections
+func View(h Help, width, height int, footer string) string {
+	boxStyle := lipgloss.NewStyle().
+		Width(max(width-10, 20)).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(components.ColorPrimary).
+		Background(components.ColorOverlay).
+		Padding(1, 4)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(components.ColorPrimary).
+		Background(components.ColorPanel).
+		Padding(0, 2)
+
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(components.ColorWarning).
+		Bold(true)
+
+	keyStyle := lipgloss.NewStyle().
+		Foreground(components.ColorPrimary).
+		Bold(true)
+
+	descStyle := lipgloss.NewStyle().
+		Foreground(components.ColorText)
+
+	// One key column for the whole help keeps every table aligned
+	keyWidth := 0
+	for _, section := range h.Sections {
+		for _, binding := range section.Bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(binding.Key))
+		}
+	}
+
+	lines := []string{headerStyle.Render("⌘ " + h.Title)}
+	if h.Description != "" {
+		lines = append(lines, "", descStyle.Render(h.Description))
+	}
+	for _, section := range h.Sections {
+		lines = append(lines, "", sectionStyle.Render(strings.ToUpper(section.Title)+":"))
+		for _, binding := range section.Bindings {
+			key := keyStyle.Render(binding.Key) + strings.Repeat(" ", keyWidth-lipgloss.Width(binding.Key))
+			lines = append(lines, "  "+key+"  "+binding.Desc)
+		}
+		if section.Note != "" {
+			lines = append(lines, descStyle.Render("  "+section.Note))
+		}
+	}
+	if footer != "" {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(components.ColorMuted).Render(footer))
+	}
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
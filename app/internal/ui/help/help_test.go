@@ -0,0 +1,31 @@
+package help
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestViewAlignsKeys(t *testing.T) {
+	h := Help{
+		Title:       "DOCKER",
+		Description: "Containers from the local Docker daemon.",
+		Sections: []Section{
+			{Title: "Keys", Bindings: []Binding{{Key: "S", Desc: "Start"}, {Key: "O / T / E", Desc: "Open mount"}}},
+			{Title: "More", Note: "Follow the hints"},
+		},
+	}
+	view := View(h, 80, 24, "Press Esc to close")
+
+	for _, want := range []string{"⌘ DOCKER", "Containers from the local Docker daemon.", "KEYS:", "MORE:", "Follow the hints", "Press Esc to close"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+	start := strings.Index(view, "Start")
+	open := strings.Index(view, "Open mount")
+	startCol := start - strings.LastIndex(view[:start], "\n")
+	openCol := open - strings.LastIndex(view[:open], "\n")
+	if startCol != openCol {
+		t.Errorf("descriptions start at columns %d and %d, want them aligned", startCol, openCol)
+	}
+}
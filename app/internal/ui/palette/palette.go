@@ -0,0 +1,250 @@
+package palette
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caioricciuti/dev-cockpit/internal/ui/components"
+	"github.com/caioricciuti/dev-cockpit/internal/ui/fuzzy"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Command is an action a module offers in the command palette
+type Command struct {
+	Title string
+	Hint  string
+	// Msg is delivered to the owning module when the command runs.
+	// A nil Msg only switches to the module.
+	Msg tea.Msg
+}
+
+// Provider is implemented by modules that expose commands to the palette
+type Provider interface {
+	Commands() []Command
+}
+
+// Searchable is implemented by modules whose content shows up in global
+// search. Each item's Msg selects that item in the module.
+type Searchable interface {
+	SearchItems() []Command
+}
+
+// Entry is a command bound to the module that owns it
+type Entry struct {
+	Module string
+	Command
+}
+
+// Key returns a key press for s, for commands that replay a module shortcut
+func Key(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// Model is the state of the palette overlay
+type Model struct {
+	entries     []Entry
+	matches     []int
+	query       string
+	cursor      int
+	prompt      string
+	placeholder string
+	empty       string
+	action      string
+	// matchHint matches on title and hint instead of module and title
+	matchHint bool
+}
+
+// New creates a command palette listing entries
+func New(entries []Entry) *Model {
+	p := &Model{
+		entries:     entries,
+		prompt:      "⌘ ",
+		placeholder: "Type a command…",
+		empty:       "No matching commands",
+		action:      "Run",
+	}
+	p.filter()
+	return p
+}
+
+// NewSearch creates a global search over module content. Items match on
+// their title and hint rather than on the module name.
+func NewSearch(entries []Entry) *Model {
+	p := &Model{
+		entries:     entries,
+		prompt:      "/ ",
+		placeholder: "Search packages, containers, ports, cleanup targets…",
+		empty:       "No matches (modules are indexed once they have loaded)",
+		action:      "Open",
+		matchHint:   true,
+	}
+	p.filter()
+	return p
+}
+
+// NewPicker creates a list to choose one entry from, such as clipboard
+// history. Items match on their title and hint.
+func NewPicker(placeholder, empty, action string, entries []Entry) *Model {
+	p := &Model{
+		entries:     entries,
+		prompt:      "› ",
+		placeholder: placeholder,
+		empty:       empty,
+		action:      action,
+		matchHint:   true,
+	}
+	p.filter()
+	return p
+}
+
+// Update handles a key press. It returns the chosen entry once the user
+// presses Enter, and done is true when the palette should close.
+func (p *Model) Update(msg tea.KeyMsg) (chosen *Entry, done bool) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlK:
+		return nil, true
+	case tea.KeyEnter:
+		if p.cursor < len(p.matches) {
+			entry := p.entries[p.matches[p.cursor]]
+			return &entry, true
+		}
+		return nil, true
+	case tea.KeyUp, tea.KeyCtrlP:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case tea.KeyBackspace:
+		if len(p.query) > 0 {
+			runes := []rune(p.query)
+			p.query = string(runes[:len(runes)-1])
+			p.filter()
+		}
+	case tea.KeyCtrlU:
+		p.query = ""
+		p.filter()
+	case tea.KeySpace:
+		p.query += " "
+		p.filter()
+	case tea.KeyRunes:
+		p.query += string(msg.Runes)
+		p.filter()
+	}
+	return nil, false
+}
+
+func (p *Model) filter() {
+	texts := make([]string, len(p.entries))
+	for i, e := range p.entries {
+		if p.matchHint {
+			texts[i] = e.Title + " " + e.Hint
+		} else {
+			texts[i] = e.Module + " " + e.Title
+		}
+	}
+	p.matches = fuzzy.Filter(p.query, texts)
+	p.cursor = 0
+}
+
+// View renders the palette centered in a width x height screen
+func (p *Model) View(width, height int) string {
+	boxWidth := 64
+	if width-4 < boxWidth {
+		boxWidth = width - 4
+	}
+	if boxWidth < 30 {
+		boxWidth = 30
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Width(boxWidth).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(components.ColorPrimary).
+		Padding(0, 1)
+	promptStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	queryStyle := lipgloss.NewStyle().Foreground(components.ColorBright)
+	placeholderStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+	selectedStyle := lipgloss.NewStyle().Foreground(components.ColorPrimary).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(components.ColorText)
+	moduleStyle := lipgloss.NewStyle().Foreground(components.ColorSubtle)
+	controlStyle := lipgloss.NewStyle().Foreground(components.ColorMuted)
+
+	var b strings.Builder
+	b.WriteString(promptStyle.Render(p.prompt))
+	if p.query == "" {
+		b.WriteString(placeholderStyle.Render(truncate(p.placeholder, boxWidth-4)))
+	} else {
+		b.WriteString(queryStyle.Render(p.query))
+	}
+	b.WriteString("\n")
+	b.WriteString(controlStyle.Render(strings.Repeat("─", boxWidth-2)))
+	b.WriteString("\n")
+
+	// Leave room for the prompt, separator, footer and border
+	maxVisible := height - 10
+	if maxVisible > 12 {
+		maxVisible = 12
+	}
+	if maxVisible < 3 {
+		maxVisible = 3
+	}
+
+	start := 0
+	if p.cursor >= maxVisible {
+		start = p.cursor - maxVisible + 1
+	}
+	end := start + maxVisible
+	if end > len(p.matches) {
+		end = len(p.matches)
+	}
+
+	if len(p.matches) == 0 {
+		b.WriteString(placeholderStyle.Render(truncate(p.empty, boxWidth-2)))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		entry := p.entries[p.matches[i]]
+		module := moduleStyle.Render(entry.Module)
+		titleWidth := boxWidth - 4 - lipgloss.Width(entry.Module) - 1
+		title := truncate(entry.Title, titleWidth)
+		gap := boxWidth - 4 - lipgloss.Width(title) - lipgloss.Width(entry.Module)
+		if gap < 1 {
+			gap = 1
+		}
+		if i == p.cursor {
+			b.WriteString(selectedStyle.Render("▶ " + title))
+		} else {
+			b.WriteString(normalStyle.Render("  " + title))
+		}
+		b.WriteString(strings.Repeat(" ", gap))
+		b.WriteString(module)
+		b.WriteString("\n")
+	}
+
+	if p.cursor < len(p.matches) {
+		if hint := p.entries[p.matches[p.cursor]].Hint; hint != "" {
+			b.WriteString(moduleStyle.Render(truncate(hint, boxWidth-2)))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(controlStyle.Render(fmt.Sprintf("%d/%d • ↑/↓ Navigate • Enter %s • Esc Close", len(p.matches), len(p.entries), p.action)))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(runes[:n])
+	}
+	return string(runes[:n-1]) + "…"
+}
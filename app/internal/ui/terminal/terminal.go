@@ -0,0 +1,46 @@
+// Package terminal builds the escape sequences that set the window title
+// and post notifications through the terminal itself, so they show in
+// the tab bar and the terminal's own notification style.
+package terminal
+
+import "strings"
+
+// Title is the sequence that sets the window and tab title (OSC 2)
+func Title(title string) string {
+	return "\x1b]2;" + clean(title) + "\a"
+}
+
+// Notification is the sequence that posts a desktop notification
+// (OSC 9), as iTerm2 introduced it
+func Notification(message string) string {
+	return "\x1b]9;" + clean(message) + "\a"
+}
+
+// notifiers are the TERM_PROGRAM values of terminals that show OSC 9
+// notifications; others ignore the sequence or, like Terminal.app, have
+// no such notifications
+var notifiers = []string{"iTerm.app", "WezTerm", "ghostty"}
+
+// Notifies reports whether the terminal getenv describes shows OSC 9
+// notifications. Inside tmux TERM_PROGRAM is tmux, which doesn't pass
+// them on.
+func Notifies(getenv func(string) string) bool {
+	program := getenv("TERM_PROGRAM")
+	for _, name := range notifiers {
+		if program == name {
+			return true
+		}
+	}
+	return getenv("TERM") == "xterm-kitty"
+}
+
+// clean drops control characters, which would end the sequence early or
+// start another
+func clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,32 @@
+package terminal
+
+import "testing"
+
+func TestSequences(t *testing.T) {
+	if got := Title("DevCockpit — Disk 92%!"); got != "\x1b]2;DevCockpit — Disk 92%!\a" {
+		t.Errorf("Title = %q", got)
+	}
+	if got := Notification("disk-full:\x07 disk\x1b]2;at 96%\n"); got != "\x1b]9;disk-full: disk]2;at 96%\a" {
+		t.Errorf("Notification = %q", got)
+	}
+}
+
+func TestNotifies(t *testing.T) {
+	for env, want := range map[[2]string]bool{
+		{"iTerm.app", "xterm-256color"}:      true,
+		{"ghostty", "xterm-ghostty"}:         true,
+		{"", "xterm-kitty"}:                  true,
+		{"Apple_Terminal", "xterm-256color"}: false,
+		{"tmux", "tmux-256color"}:            false,
+	} {
+		getenv := func(key string) string {
+			if key == "TERM_PROGRAM" {
+				return env[0]
+			}
+			return env[1]
+		}
+		if got := Notifies(getenv); got != want {
+			t.Errorf("Notifies(%v) = %v, want %v", env, got, want)
+		}
+	}
+}
@@ -2,12 +2,17 @@ package uninstaller
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
+	"github.com/caioricciuti/dev-cockpit/internal/cliio"
+	"github.com/caioricciuti/dev-cockpit/internal/maintain"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
 	"github.com/caioricciuti/dev-cockpit/internal/sudo"
 )
 
@@ -18,25 +23,15 @@ const (
 	fallbackConfigDir  = "./.devcockpit"
 )
 
-// Colors for terminal output
-const (
-	colorRed    = "\033[0;31m"
-	colorGreen  = "\033[0;32m"
-	colorYellow = "\033[1;33m"
-	colorBlue   = "\033[0;34m"
-	colorNC     = "\033[0m" // No Color
-)
-
 // Uninstall performs the complete uninstallation process
 func Uninstall(force bool) error {
-	printBanner()
+	cliio.Banner(cliio.Blue, "Dev Cockpit Uninstaller")
 
 	if !force {
 		if !confirmUninstall() {
-			printInfo("Uninstallation cancelled")
-			return nil
+			return clierr.New(clierr.Cancelled, "uninstallation cancelled")
 		}
-		fmt.Println()
+		cliio.Println()
 	}
 
 	if err := checkRunning(); err != nil {
@@ -47,6 +42,8 @@ func Uninstall(force bool) error {
 		return err
 	}
 
+	removeMaintenanceAgent()
+
 	if err := removeConfig(); err != nil {
 		return err
 	}
@@ -58,18 +55,10 @@ func Uninstall(force bool) error {
 	return nil
 }
 
-func printBanner() {
-	fmt.Println()
-	fmt.Printf("%s╔════════════════════════════════════════════╗%s\n", colorBlue, colorNC)
-	fmt.Printf("%s║      Dev Cockpit Uninstaller v1.0.0       ║%s\n", colorBlue, colorNC)
-	fmt.Printf("%s╚════════════════════════════════════════════╝%s\n", colorBlue, colorNC)
-	fmt.Println()
-}
-
 func confirmUninstall() bool {
-	printWarning("This will remove Dev Cockpit from your system")
+	cliio.Warning("This will remove Dev Cockpit from your system")
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Are you sure you want to continue? (y/N): ")
+	cliio.Printf("Are you sure you want to continue? (y/N): ")
 	response, err := reader.ReadString('\n')
 	if err != nil {
 		return false
@@ -83,9 +72,9 @@ func checkRunning() error {
 	cmd := exec.Command("pgrep", "-x", binaryName)
 	if err := cmd.Run(); err == nil {
 		// Process is running
-		printWarning("Dev Cockpit is currently running")
+		cliio.Warning("Dev Cockpit is currently running")
 		reader := bufio.NewReader(os.Stdin)
-		fmt.Print("Do you want to stop it? (y/N): ")
+		cliio.Printf("Do you want to stop it? (y/N): ")
 		response, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read response: %w", err)
@@ -93,7 +82,7 @@ func checkRunning() error {
 		response = strings.TrimSpace(strings.ToLower(response))
 
 		if response == "y" || response == "yes" {
-			printInfo("Stopping Dev Cockpit...")
+			cliio.Info("Stopping Dev Cockpit...")
 
 			// Try graceful termination first
 			exec.Command("pkill", "-TERM", binaryName).Run()
@@ -106,9 +95,9 @@ func checkRunning() error {
 				exec.Command("pkill", "-KILL", binaryName).Run()
 			}
 
-			printSuccess("Dev Cockpit stopped")
+			cliio.Success("Dev Cockpit stopped")
 		} else {
-			return fmt.Errorf("please stop Dev Cockpit before uninstalling")
+			return clierr.New(clierr.Cancelled, "please stop Dev Cockpit before uninstalling")
 		}
 	}
 	return nil
@@ -119,22 +108,25 @@ func removeBinary() error {
 
 	// Check if binary exists
 	if _, err := os.Stat(binaryPath); os.IsNotExist(err) {
-		printInfo(fmt.Sprintf("Binary not found at %s (already removed or never installed)", binaryPath))
+		cliio.Info(fmt.Sprintf("Binary not found at %s (already removed or never installed)", binaryPath))
 		return nil
 	}
 
-	printInfo(fmt.Sprintf("Removing binary from %s...", binaryPath))
+	cliio.Info(fmt.Sprintf("Removing binary from %s...", binaryPath))
 
 	// Try to remove without sudo first
 	if err := os.Remove(binaryPath); err != nil {
 		// Need sudo
-		printWarning("Requesting administrator privileges to remove binary")
+		cliio.Warning("Requesting administrator privileges to remove binary")
 		if _, err := sudo.Run("rm", "-f", binaryPath); err != nil {
+			if errors.Is(err, sudo.ErrCancelled) {
+				return clierr.New(clierr.Cancelled, "uninstallation cancelled")
+			}
 			return fmt.Errorf("failed to remove binary: %w", err)
 		}
 	}
 
-	printSuccess("Binary removed")
+	cliio.Success("Binary removed")
 	return nil
 }
 
@@ -148,37 +140,39 @@ func removeConfig() error {
 
 	// Check if config directory exists
 	if _, err := os.Stat(configDir); os.IsNotExist(err) {
-		printInfo(fmt.Sprintf("No configuration directory found at %s", configDir))
+		cliio.Info(fmt.Sprintf("No configuration directory found at %s", configDir))
 		return nil
 	}
 
-	printInfo(fmt.Sprintf("Found configuration directory: %s", configDir))
+	cliio.Info("Found configuration directory")
 
 	// Show what will be deleted
-	configFile := filepath.Join(configDir, "config.yaml")
-	debugLog := filepath.Join(configDir, "debug.log")
-	dataDir := filepath.Join(configDir, "data")
-
-	if _, err := os.Stat(configFile); err == nil {
-		printInfo("  - config.yaml")
-	}
-	if _, err := os.Stat(debugLog); err == nil {
-		printInfo("  - debug.log")
+	var contents []string
+	for _, entry := range []struct{ name, label string }{
+		{"config.yaml", "config.yaml"},
+		{"debug.log", "debug.log"},
+		{"state.json", "state.json"},
+		{"data", "data directory"},
+	} {
+		if _, err := os.Stat(filepath.Join(configDir, entry.name)); err == nil {
+			contents = append(contents, entry.label)
+		}
 	}
-	if _, err := os.Stat(dataDir); err == nil {
-		printInfo("  - data directory")
+	rows := [][2]string{{"Path", configDir}}
+	if len(contents) > 0 {
+		rows = append(rows, [2]string{"Contents", strings.Join(contents, ", ")})
 	}
 
 	// Show directory size
 	cmd := exec.Command("du", "-sh", configDir)
 	if output, err := cmd.Output(); err == nil {
-		size := strings.Fields(string(output))[0]
-		printInfo(fmt.Sprintf("  Total size: %s", size))
+		rows = append(rows, [2]string{"Total size", strings.Fields(string(output))[0]})
 	}
+	cliio.Fields(rows)
 
-	fmt.Println()
+	cliio.Println()
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Remove configuration and data? (y/N): ")
+	cliio.Printf("Remove configuration and data? (y/N): ")
 	response, err := reader.ReadString('\n')
 	if err != nil {
 		return fmt.Errorf("failed to read response: %w", err)
@@ -189,23 +183,36 @@ func removeConfig() error {
 		if err := os.RemoveAll(configDir); err != nil {
 			return fmt.Errorf("failed to remove config directory: %w", err)
 		}
-		printSuccess("Configuration directory removed")
+		cliio.Success("Configuration directory removed")
 	} else {
-		printInfo(fmt.Sprintf("Configuration directory kept at %s", configDir))
+		cliio.Info(fmt.Sprintf("Configuration directory kept at %s", configDir))
 	}
 
 	return nil
 }
 
+// removeMaintenanceAgent stops the background maintenance, which would
+// otherwise keep launching the removed binary
+func removeMaintenanceAgent() {
+	if !maintain.Installed() {
+		return
+	}
+	if err := maintain.Uninstall(runner.Default); err != nil {
+		cliio.Warning(fmt.Sprintf("Failed to remove the maintenance agent at %s: %v", maintain.PlistPath(), err))
+		return
+	}
+	cliio.Success("Background maintenance removed")
+}
+
 func removeFallbackConfig() {
 	// Check if fallback config exists
 	if _, err := os.Stat(fallbackConfigDir); os.IsNotExist(err) {
 		return
 	}
 
-	printWarning(fmt.Sprintf("Found fallback config directory: %s", fallbackConfigDir))
+	cliio.Warning(fmt.Sprintf("Found fallback config directory: %s", fallbackConfigDir))
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Remove it? (y/N): ")
+	cliio.Printf("Remove it? (y/N): ")
 	response, err := reader.ReadString('\n')
 	if err != nil {
 		return
@@ -214,13 +221,13 @@ func removeFallbackConfig() {
 
 	if response == "y" || response == "yes" {
 		if err := os.RemoveAll(fallbackConfigDir); err == nil {
-			printSuccess("Fallback config directory removed")
+			cliio.Success("Fallback config directory removed")
 		}
 	}
 }
 
 func removeTempFiles() {
-	printInfo("Checking for temporary files...")
+	cliio.Info("Checking for temporary files...")
 
 	foundTemp := false
 	tempCount := 0
@@ -237,40 +244,20 @@ func removeTempFiles() {
 	}
 
 	if foundTemp {
-		printSuccess(fmt.Sprintf("Removed %d temporary file(s)", tempCount))
+		cliio.Success(fmt.Sprintf("Removed %d temporary file(s)", tempCount))
 	} else {
-		printInfo("No temporary files found")
+		cliio.Info("No temporary files found")
 	}
 }
 
 func printCompletion() {
-	fmt.Println()
-	fmt.Printf("%s╔════════════════════════════════════════════╗%s\n", colorGreen, colorNC)
-	fmt.Printf("%s║  Dev Cockpit uninstalled successfully! ✓   ║%s\n", colorGreen, colorNC)
-	fmt.Printf("%s╚════════════════════════════════════════════╝%s\n", colorGreen, colorNC)
-	fmt.Println()
-	fmt.Printf("%sThank you for using Dev Cockpit!%s\n", colorBlue, colorNC)
-	fmt.Println()
-	fmt.Println("If you encountered any issues, please report them at:")
-	fmt.Println("  https://github.com/caioricciuti/dev-cockpit/issues")
-	fmt.Println()
-	fmt.Println("To reinstall in the future:")
-	fmt.Println("  curl -fsSL https://raw.githubusercontent.com/caioricciuti/dev-cockpit/main/install.sh | bash")
-	fmt.Println()
-}
-
-func printInfo(msg string) {
-	fmt.Printf("%sℹ%s %s\n", colorBlue, colorNC, msg)
-}
-
-func printSuccess(msg string) {
-	fmt.Printf("%s✓%s %s\n", colorGreen, colorNC, msg)
-}
-
-func printWarning(msg string) {
-	fmt.Printf("%s⚠%s %s\n", colorYellow, colorNC, msg)
-}
-
-func printError(msg string) {
-	fmt.Printf("%s✗%s %s\n", colorRed, colorNC, msg)
+	cliio.Banner(cliio.Green, "Dev Cockpit uninstalled successfully! ✓")
+	cliio.Println(cliio.Paint(cliio.Blue, "Thank you for using Dev Cockpit!"))
+	cliio.Println()
+	cliio.Println("If you encountered any issues, please report them at:")
+	cliio.Println("  https://github.com/caioricciuti/dev-cockpit/issues")
+	cliio.Println()
+	cliio.Println("To reinstall in the future:")
+	cliio.Println("  curl -fsSL https://raw.githubusercontent.com/caioricciuti/dev-cockpit/main/install.sh | bash")
+	cliio.Println()
 }
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
 )
 
 // DownloadAndVerify downloads the binary and checksum, then verifies integrity
@@ -34,13 +36,13 @@ func DownloadAndVerify(release *Release) (string, error) {
 	// Download checksum first (it's small)
 	if err := downloadFile(checksumAsset.BrowserDownloadURL, checksumPath); err != nil {
 		os.RemoveAll(tempDir)
-		return "", fmt.Errorf("failed to download checksum: %w", err)
+		return "", clierr.Wrap(clierr.Network, fmt.Errorf("failed to download checksum: %w", err))
 	}
 
 	// Download binary
 	if err := downloadFile(binaryAsset.BrowserDownloadURL, binaryPath); err != nil {
 		os.RemoveAll(tempDir)
-		return "", fmt.Errorf("failed to download binary: %w", err)
+		return "", clierr.Wrap(clierr.Network, fmt.Errorf("failed to download binary: %w", err))
 	}
 
 	// Verify checksum
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"golang.org/x/mod/semver"
+
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
 )
 
 const (
@@ -23,12 +25,12 @@ func FetchLatestRelease() (*Release, error) {
 
 	resp, err := client.Get(githubAPIURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to GitHub: %w", err)
+		return nil, clierr.Wrap(clierr.Network, fmt.Errorf("failed to connect to GitHub: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("GitHub API error: HTTP %d", resp.StatusCode)
+		return nil, clierr.New(clierr.Network, "GitHub API error: HTTP %d", resp.StatusCode)
 	}
 
 	var release Release
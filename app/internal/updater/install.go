@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
 	"github.com/caioricciuti/dev-cockpit/internal/sudo"
 )
 
@@ -24,7 +25,7 @@ func InstallUpdate(newBinaryPath, targetPath string) error {
 
 	// Check if target exists
 	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		return fmt.Errorf("current binary not found at %s", targetPath)
+		return clierr.New(clierr.DependencyMissing, "current binary not found at %s", targetPath)
 	}
 
 	// Step 1: Backup current binary
@@ -37,7 +38,7 @@ func InstallUpdate(newBinaryPath, targetPath string) error {
 	if _, err := sudo.Run("cp", newBinaryPath, targetPath); err != nil {
 		// No need to rollback - original is still in place
 		if err == sudo.ErrCancelled {
-			return fmt.Errorf("update cancelled")
+			return clierr.New(clierr.Cancelled, "update cancelled")
 		}
 		return fmt.Errorf("failed to replace binary: %w", err)
 	}
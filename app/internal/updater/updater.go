@@ -5,33 +5,30 @@ import (
 	"fmt"
 	"os"
 	"strings"
-)
 
-// Colors for terminal output
-const (
-	colorRed    = "\033[0;31m"
-	colorGreen  = "\033[0;32m"
-	colorYellow = "\033[1;33m"
-	colorBlue   = "\033[0;34m"
-	colorNC     = "\033[0m" // No Color
+	"github.com/caioricciuti/dev-cockpit/internal/awake"
+	"github.com/caioricciuti/dev-cockpit/internal/clierr"
+	"github.com/caioricciuti/dev-cockpit/internal/cliio"
+	"github.com/caioricciuti/dev-cockpit/internal/runner"
 )
 
 // Update performs the complete update process
 func Update(opts UpdateOptions) error {
-	printBanner()
-
-	// Step 1: Show current version
-	printInfo(fmt.Sprintf("Current version: v%s", opts.CurrentVer))
+	cliio.Banner(cliio.Blue, "Dev Cockpit Updater")
 
-	// Step 2: Check for updates
-	printInfo("Checking for updates...")
+	// Step 1: Check for updates
+	cliio.Info("Checking for updates...")
 
 	release, err := FetchLatestRelease()
 	if err != nil {
 		return fmt.Errorf("failed to check for updates: %w", err)
 	}
 
-	printInfo(fmt.Sprintf("Latest version: %s", release.TagName))
+	// Step 2: Show both versions
+	cliio.Fields([][2]string{
+		{"Current version", "v" + opts.CurrentVer},
+		{"Latest version", release.TagName},
+	})
 
 	// Step 3: Compare versions
 	updateAvailable, err := HasUpdate(opts.CurrentVer, release.TagName)
@@ -40,60 +37,64 @@ func Update(opts UpdateOptions) error {
 	}
 
 	if !updateAvailable {
-		printSuccess("Already up to date! You're running the latest version.")
+		cliio.Success("Already up to date! You're running the latest version.")
 		return nil
 	}
 
-	printSuccess("Update available!")
-	fmt.Println()
+	cliio.Success("Update available!")
+	cliio.Println()
 
 	// If check-only mode, stop here
 	if opts.CheckOnly {
-		fmt.Printf("Update available: v%s → %s\n", opts.CurrentVer, release.TagName)
-		fmt.Println("\nRun 'devcockpit update' to install")
+		cliio.Printf("Update available: v%s → %s\n", opts.CurrentVer, release.TagName)
+		cliio.Println("\nRun 'devcockpit update' to install")
 		return nil
 	}
 
 	// Show release notes if available
 	if release.Body != "" {
-		fmt.Printf("Update v%s → %s:\n", opts.CurrentVer, release.TagName)
+		cliio.Printf("Update v%s → %s:\n", opts.CurrentVer, release.TagName)
 		// Show first few lines of release notes
 		lines := strings.Split(release.Body, "\n")
 		for i, line := range lines {
 			if i >= 5 { // Limit to first 5 lines
-				fmt.Println("  ...")
+				cliio.Println("  ...")
 				break
 			}
-			fmt.Printf("  %s\n", line)
+			cliio.Printf("  %s\n", cliio.Stdout.Truncate(strings.TrimRight(line, "\r"), 2))
 		}
-		fmt.Println()
+		cliio.Println()
 	}
 
 	// Step 4: Confirm with user (unless --force)
 	if !opts.Force {
 		if !confirmUpdate() {
-			printInfo("Update cancelled. No changes were made.")
-			return nil
+			return clierr.New(clierr.Cancelled, "update cancelled, no changes were made")
 		}
-		fmt.Println()
+		cliio.Println()
 	}
 
-	// Step 5: Download and verify
-	printInfo("Downloading binary...")
+	// Step 5: Download and verify, kept awake until installed
+	hold := awake.Start(runner.Default)
+	defer hold.Release()
+	if warning := awake.Warning(runner.Default, "the update"); warning != "" {
+		cliio.Warning(warning)
+	}
+	cliio.Info("Downloading binary...")
 	binaryPath, err := DownloadAndVerify(release)
 	if err != nil {
 		return err
 	}
 
-	printSuccess("Downloaded successfully")
-	printInfo("Verifying checksum...")
-	printSuccess("Checksum verified")
-	fmt.Println()
+	cliio.Success("Downloaded successfully")
+	cliio.Info("Verifying checksum...")
+	cliio.Success("Checksum verified")
+	cliio.Println()
 
 	// Step 6: Install update
-	printInfo("Installing update...")
-	printWarning("Requesting administrator privileges to install")
-	fmt.Println()
+	cliio.Info("Installing update...")
+	cliio.Warning("Requesting administrator privileges to install")
+	cliio.Println()
 
 	if err := InstallUpdate(binaryPath, defaultInstallPath); err != nil {
 		return err
@@ -105,29 +106,15 @@ func Update(opts UpdateOptions) error {
 	return nil
 }
 
-// Helper functions for output
-
-func printBanner() {
-	fmt.Println()
-	fmt.Printf("%s╔════════════════════════════════════════════╗%s\n", colorBlue, colorNC)
-	fmt.Printf("%s║      Dev Cockpit Updater v1.0.0           ║%s\n", colorBlue, colorNC)
-	fmt.Printf("%s╚════════════════════════════════════════════╝%s\n", colorBlue, colorNC)
-	fmt.Println()
-}
-
 func printCompletion(version string) {
-	fmt.Println()
-	fmt.Printf("%s╔════════════════════════════════════════════╗%s\n", colorGreen, colorNC)
-	fmt.Printf("%s║  Dev Cockpit updated to %s! 🚀         ║%s\n", colorGreen, version, colorNC)
-	fmt.Printf("%s╚════════════════════════════════════════════╝%s\n", colorGreen, colorNC)
-	fmt.Println()
-	fmt.Println("Run 'devcockpit --version' to verify")
-	fmt.Println()
+	cliio.Banner(cliio.Green, fmt.Sprintf("Dev Cockpit updated to %s! 🚀", version))
+	cliio.Println("Run 'devcockpit --version' to verify")
+	cliio.Println()
 }
 
 func confirmUpdate() bool {
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Continue with update? (y/N): ")
+	cliio.Printf("Continue with update? (y/N): ")
 	response, err := reader.ReadString('\n')
 	if err != nil {
 		return false
@@ -135,19 +122,3 @@ func confirmUpdate() bool {
 	response = strings.TrimSpace(strings.ToLower(response))
 	return response == "y" || response == "yes"
 }
-
-func printInfo(msg string) {
-	fmt.Printf("%sℹ%s %s\n", colorBlue, colorNC, msg)
-}
-
-func printSuccess(msg string) {
-	fmt.Printf("%s✓%s %s\n", colorGreen, colorNC, msg)
-}
-
-func printWarning(msg string) {
-	fmt.Printf("%s⚠%s %s\n", colorYellow, colorNC, msg)
-}
-
-func printError(msg string) {
-	fmt.Printf("%s✗%s %s\n", colorRed, colorNC, msg)
-}
@@ -0,0 +1,56 @@
+// Package workspace finds the projects kept in the workspace roots: the
+// git checkouts a few folders below them.
+package workspace
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Depth is how many folders below a root projects are looked for
+const Depth = 3
+
+// skippedDirs hold dependencies, not projects of their own
+var skippedDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// Projects returns the git checkouts within Depth of the roots, sorted,
+// without looking inside them or in hidden and dependency folders. A
+// leading ~ in a root is the home folder.
+func Projects(roots []string) []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, root := range roots {
+		root = filepath.Clean(ExpandHome(root))
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			depth := strings.Count(strings.TrimPrefix(path, root), string(filepath.Separator))
+			if path != root && (strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()] || depth > Depth) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+				return nil
+			}
+			if !seen[path] {
+				seen[path] = true
+				dirs = append(dirs, path)
+			}
+			return filepath.SkipDir
+		})
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// ExpandHome replaces a leading ~ with the home folder
+func ExpandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}